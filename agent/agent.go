@@ -563,6 +563,12 @@ func (a *Agent) setupTask(agentCtx, setupCtx context.Context, initialTC *taskCon
 			InternalRedactions: tc.taskConfig.InternalRedactions,
 		},
 		S3Usage: tc.taskConfig.S3Usage,
+		IngestTestResults: func(ctx context.Context, format taskoutput.TestResultsFormat, files []string) error {
+			return command.IngestTestResultFiles(ctx, a.comm, tc.logger, tc.taskConfig, format, files)
+		},
+		UploadArtifacts: func(ctx context.Context, spec taskoutput.ArtifactsSpec, files []taskoutput.ArtifactFile) error {
+			return command.UploadArtifactFiles(ctx, a.comm, tc.logger, tc.taskConfig, spec, files)
+		},
 	}
 	tc.taskConfig.TaskOutputDir = taskoutput.NewDirectory(opts)
 	if err := tc.taskConfig.TaskOutputDir.Setup(); err != nil {
@@ -1243,7 +1249,6 @@ func (a *Agent) finishTask(ctx context.Context, tc *taskContext, detail *apimode
 
 		detail.PostErrored = tc.getPostErrored()
 		detail.OtherFailingCommands = tc.getOtherFailingCommands()
-		updateEndTaskFailureDetailsForTestResults(ctx, tc, detail)
 
 	case evergreen.TaskFailed:
 		a.handleTimeoutAndOOM(ctx, tc, detail)
@@ -1286,12 +1291,15 @@ func (a *Agent) finishTask(ctx context.Context, tc *taskContext, detail *apimode
 	}
 
 	// Attempt automatic task output ingestion if the task output directory
-	// was setup, regardless of the task status.
+	// was setup, regardless of the task status. This must happen before the
+	// test results are checked so that results ingested from the task output
+	// directory can determine the final task status.
 	if tc.taskConfig != nil && tc.taskConfig.TaskOutputDir != nil {
 		toCtx, span := a.tracer.Start(ctx, "task-output-ingestion")
 		tc.logger.Execution().Error(ctx, errors.Wrap(tc.taskConfig.TaskOutputDir.Run(toCtx), "ingesting task output"))
 		span.End()
 	}
+	updateEndTaskFailureDetailsForTestResults(ctx, tc, detail)
 
 	_ = a.killProcs(ctx, tc, false, "task is ending")
 
@@ -3139,6 +3139,39 @@ tasks:
 	s.Equal(expectedLines, actualLines)
 }
 
+func (s *AgentSuite) TestTaskOutputDirectoryFailingTestResultFailsTask() {
+	projYml := `
+tasks:
+  - name: this_is_a_task_name
+    must_have_test_results: true
+    commands:
+      - command: shell.exec
+        params:
+          script: |
+            cat >> build/TestResults/results.xml <<EOF
+            <testsuite name="suite" tests="1" failures="1">
+              <testcase name="test" classname="suite" time="1">
+                <failure message="failed">assertion failed</failure>
+              </testcase>
+            </testsuite>
+            EOF
+`
+	s.setupRunTask(projYml)
+	nextTask := &apimodels.NextTaskResponse{
+		TaskId:     s.tc.task.ID,
+		TaskSecret: s.tc.task.Secret,
+	}
+	s.tc.taskConfig.Task.MustHaveResults = true
+	s.tc.taskConfig.DisplayTaskInfo = &apimodels.DisplayTaskInfo{}
+	_, _, err := s.a.runTask(s.ctx, s.tc, nextTask, false, s.testTmpDirName)
+	s.Require().NoError(err)
+
+	s.True(s.tc.taskConfig.HasTestResults)
+	s.Equal(evergreen.CommandTypeTest, s.mockCommunicator.EndTaskResult.Detail.Type)
+	s.Equal(evergreen.TaskFailed, s.mockCommunicator.EndTaskResult.Detail.Status)
+	s.Equal(evergreen.TaskDescriptionResultsFailed, s.mockCommunicator.EndTaskResult.Detail.Description)
+}
+
 func (s *AgentSuite) TestClearGlobalFiles() {
 	s.setupRunTask(defaultProjYml)
 	// create a fake git config file
@@ -0,0 +1,768 @@
+package command
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/agent/internal"
+	"github.com/evergreen-ci/evergreen/model/cachestore"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// cacheChunkMinSize and cacheChunkMaxSize bound the size of the
+	// content-defined chunks of a chunked cache. With cacheChunkMask, chunks
+	// average about 1 MiB.
+	cacheChunkMinSize = 256 * 1024
+	cacheChunkMaxSize = 4 * 1024 * 1024
+	cacheChunkMask    = 1<<20 - 1
+
+	// cacheBlobConcurrency is the maximum number of blobs transferred at
+	// once by a chunked cache.save or cache.restore.
+	cacheBlobConcurrency = 16
+)
+
+// cacheGearTable maps each byte to a pseudo-random value for the rolling hash
+// that finds chunk boundaries. It must never change, since doing so would move
+// every chunk boundary and defeat deduplication against existing blobs.
+var cacheGearTable = func() [256]uint64 {
+	var table [256]uint64
+	// splitmix64 with a fixed seed.
+	state := uint64(0x6576657267726565)
+	for i := range table {
+		state += 0x9e3779b97f4a7c15
+		z := state
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		table[i] = z ^ (z >> 31)
+	}
+	return table
+}()
+
+// cacheChunkBoundary returns the length of the chunk at the start of data. The
+// boundary is placed where a rolling hash over the preceding bytes matches
+// cacheChunkMask, so boundaries depend only on nearby content: inserting or
+// removing bytes only changes the chunks around the edit, and the remaining
+// chunks are identical to (and deduplicated against) the previous save.
+func cacheChunkBoundary(data []byte) int {
+	if len(data) <= cacheChunkMinSize {
+		return len(data)
+	}
+	end := min(len(data), cacheChunkMaxSize)
+	var h uint64
+	for i := cacheChunkMinSize; i < end; i++ {
+		h = (h << 1) + cacheGearTable[data[i]]
+		if h&cacheChunkMask == 0 {
+			return i + 1
+		}
+	}
+	return end
+}
+
+// cacheChunker splits a stream into content-defined chunks.
+type cacheChunker struct {
+	r        io.Reader
+	buf      []byte
+	buffered int
+	consumed int
+	eof      bool
+}
+
+func newCacheChunker(r io.Reader) *cacheChunker {
+	return &cacheChunker{r: r, buf: make([]byte, cacheChunkMaxSize)}
+}
+
+// next returns the next chunk of the stream, or io.EOF once the stream is
+// exhausted. The returned slice is only valid until the following call.
+func (c *cacheChunker) next() ([]byte, error) {
+	if c.consumed > 0 {
+		c.buffered = copy(c.buf, c.buf[c.consumed:c.buffered])
+		c.consumed = 0
+	}
+	if !c.eof {
+		n, err := io.ReadFull(c.r, c.buf[c.buffered:])
+		c.buffered += n
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			c.eof = true
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	if c.buffered == 0 {
+		return nil, io.EOF
+	}
+	c.consumed = cacheChunkBoundary(c.buf[:c.buffered])
+	return c.buf[:c.consumed], nil
+}
+
+// cacheContentStream reads the concatenated contents of the regular files in a
+// chunked cache.
+type cacheContentStream struct {
+	sources []cacheContentSource
+	idx     int
+	cur     *os.File
+	r       io.Reader
+	read    int64
+}
+
+// cacheContentSource is a local file whose contents are part of a chunked
+// cache.
+type cacheContentSource struct {
+	path string
+	size int64
+}
+
+func (s *cacheContentStream) Read(p []byte) (int, error) {
+	for {
+		if s.cur == nil {
+			if s.idx >= len(s.sources) {
+				return 0, io.EOF
+			}
+			f, err := os.Open(s.sources[s.idx].path)
+			if err != nil {
+				return 0, errors.Wrapf(err, "opening file '%s'", s.sources[s.idx].path)
+			}
+			s.cur = f
+			s.r = io.LimitReader(f, s.sources[s.idx].size)
+			s.read = 0
+		}
+
+		n, err := s.r.Read(p)
+		s.read += int64(n)
+		if err == io.EOF {
+			source := s.sources[s.idx]
+			closeErr := s.cur.Close()
+			s.cur = nil
+			s.idx++
+			if s.read != source.size {
+				return n, errors.Errorf("file '%s' changed size while saving the cache", source.path)
+			}
+			if closeErr != nil {
+				return n, errors.Wrapf(closeErr, "closing file '%s'", source.path)
+			}
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+func (s *cacheContentStream) Close() error {
+	if s.cur == nil {
+		return nil
+	}
+	return s.cur.Close()
+}
+
+// cacheEntryName returns the path of a file in a chunked cache. It matches the
+// tarball layout: paths under rootPath are relative to it, and any other
+// absolute path loses its leading slash.
+func cacheEntryName(rootPath, filePath string) string {
+	if rel, err := filepath.Rel(rootPath, filePath); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(rel)
+	}
+	return strings.TrimLeft(path.Clean(filepath.ToSlash(filePath)), "/")
+}
+
+// buildCacheManifest resolves paths into the entries of a chunked cache and
+// splits the contents of its regular files into chunks. It returns the
+// manifest along with the local files that make up its content stream. When
+// preserveSymlinks is false, symlinks are dereferenced like they are for
+// tarball caches.
+func buildCacheManifest(ctx context.Context, workDir string, paths []string, logger grip.Journaler, preserveSymlinks bool) (*cachestore.Manifest, []cacheContentSource, error) {
+	contents, _, err := gatherCacheContents(workDir, paths)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	type entryWithSource struct {
+		entry  cachestore.Entry
+		source string
+	}
+	var entries []entryWithSource
+	seen := map[string]bool{}
+	for _, file := range contents {
+		name := cacheEntryName(workDir, file.path)
+		if name == "" || name == "." || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		info := file.info
+		source := file.path
+		if info.Mode()&os.ModeSymlink != 0 {
+			if preserveSymlinks {
+				target, err := os.Readlink(file.path)
+				if err != nil {
+					return nil, nil, errors.Wrapf(err, "reading symlink '%s'", file.path)
+				}
+				entries = append(entries, entryWithSource{entry: cachestore.Entry{
+					Path:       name,
+					Type:       cachestore.EntryTypeSymlink,
+					Mode:       uint32(info.Mode().Perm()),
+					LinkTarget: target,
+				}})
+				continue
+			}
+			target, err := filepath.EvalSymlinks(file.path)
+			if err != nil {
+				logger.Warningf(ctx, "Could not follow symlink '%s', ignoring.", file.path)
+				continue
+			}
+			if info, err = os.Stat(target); err != nil {
+				logger.Warningf(ctx, "Failed to get underlying file for symlink '%s', ignoring.", file.path)
+				continue
+			}
+			source = target
+		}
+
+		switch {
+		case info.IsDir():
+			entries = append(entries, entryWithSource{entry: cachestore.Entry{
+				Path: name,
+				Type: cachestore.EntryTypeDir,
+				Mode: uint32(info.Mode().Perm()),
+			}})
+		case info.Mode().IsRegular():
+			entries = append(entries, entryWithSource{
+				entry: cachestore.Entry{
+					Path: name,
+					Type: cachestore.EntryTypeFile,
+					Mode: uint32(info.Mode().Perm()),
+					Size: info.Size(),
+				},
+				source: source,
+			})
+		default:
+			logger.Warningf(ctx, "Skipping '%s' because it is not a regular file, directory, or symlink.", file.path)
+		}
+	}
+
+	// Sorting makes the content stream, and therefore the chunks, independent
+	// of the order the paths were given in. It also puts every directory ahead
+	// of its contents.
+	sort.Slice(entries, func(i, j int) bool { return entries[i].entry.Path < entries[j].entry.Path })
+
+	manifest := &cachestore.Manifest{Version: cachestore.ManifestVersion}
+	var sources []cacheContentSource
+	for _, e := range entries {
+		manifest.Entries = append(manifest.Entries, e.entry)
+		if e.entry.Type == cachestore.EntryTypeFile {
+			sources = append(sources, cacheContentSource{path: e.source, size: e.entry.Size})
+		}
+	}
+
+	err = forEachCacheChunk(ctx, sources, func(_ int, chunk []byte) error {
+		manifest.Chunks = append(manifest.Chunks, cachestore.Chunk{Hash: hashCacheChunk(chunk), Size: int64(len(chunk))})
+		return nil
+	})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "chunking cache contents")
+	}
+
+	return manifest, sources, nil
+}
+
+// forEachCacheChunk splits the concatenated contents of sources into chunks and
+// calls handle with each chunk's index and contents. The contents are only
+// valid for the duration of the call.
+func forEachCacheChunk(ctx context.Context, sources []cacheContentSource, handle func(int, []byte) error) error {
+	stream := &cacheContentStream{sources: sources}
+	defer stream.Close()
+
+	chunker := newCacheChunker(stream)
+	for i := 0; ; i++ {
+		if err := ctx.Err(); err != nil {
+			return errors.Wrap(err, "chunking was canceled")
+		}
+		chunk, err := chunker.next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := handle(i, chunk); err != nil {
+			return err
+		}
+	}
+}
+
+func hashCacheChunk(chunk []byte) string {
+	sum := sha256.Sum256(chunk)
+	return hex.EncodeToString(sum[:])
+}
+
+// retryCacheBlobOp retries op with the same backoff as retryS3Op, but without
+// logging every attempt, since a chunked cache can transfer thousands of
+// blobs.
+func retryCacheBlobOp(ctx context.Context, op utility.RetryableFunc) error {
+	return utility.Retry(ctx, op, utility.RetryOptions{
+		MaxAttempts: maxS3OpAttempts,
+		MinDelay:    s3OpSleep,
+		MaxDelay:    s3OpRetryMaxSleep,
+	})
+}
+
+// manifestKey returns the key of the manifest for a chunked cache with the
+// given content key.
+func (c *cacheCommon) manifestKey(key string) string {
+	return cachestore.ManifestKey(c.StorePath, c.RemotePath, key, c.CacheName)
+}
+
+// saveChunked uploads a chunked cache. Only the blobs that aren't already in
+// the store are uploaded, followed by the manifest.
+//
+// Before any blob is uploaded, the manifest is also written as a pending
+// manifest, which keeps garbage collection from removing blobs that this save
+// is about to reference.
+func (c *cacheSave) saveChunked(ctx context.Context, logger grip.Journaler, conf *internal.TaskConfig, key string) error {
+	manifestKey := c.manifestKey(key)
+	if exists, err := c.bucket.Exists(ctx, manifestKey); err == nil && exists {
+		logger.Infof(ctx, "cache.save: not uploading because '%s/%s' already exists.", c.Bucket, manifestKey)
+		return nil
+	}
+
+	logger.Infof(ctx, "cache.save: chunking paths %s.", c.Paths)
+	manifest, sources, err := buildCacheManifest(ctx, conf.WorkDir, c.Paths, logger, c.PreserveSymlinks)
+	if err != nil {
+		return errors.Wrap(err, "building cache manifest")
+	}
+	manifest.CreatedAt = time.Now()
+	manifestData, err := cachestore.MarshalManifest(manifest)
+	if err != nil {
+		return err
+	}
+
+	pendingKey := cachestore.PendingKey(c.StorePath, utility.RandomString())
+	err = retryS3Op(ctx, logger, fmt.Sprintf("upload pending cache manifest '%s'", pendingKey), func() (bool, error) {
+		putErr := c.bucket.Put(ctx, pendingKey, bytes.NewReader(manifestData))
+		return putErr != nil && !isS3ClientError(putErr), putErr
+	})
+	if err != nil {
+		return errors.Wrapf(err, "uploading pending cache manifest '%s'", pendingKey)
+	}
+	defer func() {
+		logger.Error(ctx, errors.Wrapf(c.bucket.Remove(ctx, pendingKey), "removing pending cache manifest '%s'", pendingKey))
+	}()
+
+	missing, err := c.findMissingBlobs(ctx, manifest.Blobs())
+	if err != nil {
+		return errors.Wrap(err, "checking for existing blobs")
+	}
+	logger.Infof(ctx, "cache.save: %d of %d distinct chunks are not yet stored.", len(missing), len(manifest.Blobs()))
+
+	uploaded, err := c.uploadBlobs(ctx, manifest, sources, missing)
+	if err != nil {
+		return errors.Wrap(err, "uploading blobs")
+	}
+
+	alreadyExists := false
+	err = retryS3Op(ctx, logger, fmt.Sprintf("upload cache manifest '%s'", manifestKey), func() (bool, error) {
+		putErr := c.bucket.Put(ctx, manifestKey, bytes.NewReader(manifestData))
+		if putErr == nil {
+			return false, nil
+		}
+		if isS3PreconditionFailed(putErr) {
+			alreadyExists = true
+			return false, nil
+		}
+		return !isS3ClientError(putErr), putErr
+	})
+	if err != nil {
+		return errors.Wrapf(err, "uploading cache manifest '%s'", manifestKey)
+	}
+	if alreadyExists {
+		logger.Infof(ctx, "cache.save: not uploading manifest because '%s/%s' already exists.", c.Bucket, manifestKey)
+		return nil
+	}
+
+	logger.Infof(ctx, "cache.save: uploaded %d bytes of new chunks and the manifest to '%s/%s'.", uploaded, c.Bucket, manifestKey)
+	return nil
+}
+
+// findMissingBlobs returns the set of hashes whose blobs aren't in the store.
+func (c *cacheCommon) findMissingBlobs(ctx context.Context, hashes []string) (map[string]bool, error) {
+	var mu sync.Mutex
+	missing := map[string]bool{}
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(cacheBlobConcurrency)
+	for _, hash := range hashes {
+		g.Go(func() error {
+			blobKey := cachestore.BlobKey(c.StorePath, hash)
+			var exists bool
+			err := retryCacheBlobOp(gctx, func() (bool, error) {
+				var existsErr error
+				exists, existsErr = c.bucket.Exists(gctx, blobKey)
+				if existsErr == nil {
+					return false, nil
+				}
+				switch classifyCacheDownloadErr(existsErr) {
+				case cacheDownloadMiss, cacheDownloadMaybeMiss:
+					// Without s3:ListBucket, S3 reports a missing object as
+					// access denied, so upload it to be safe.
+					exists = false
+					return false, nil
+				case cacheDownloadFatal:
+					return false, existsErr
+				default:
+					return true, existsErr
+				}
+			})
+			if err != nil {
+				return errors.Wrapf(err, "checking for blob '%s'", blobKey)
+			}
+			if !exists {
+				mu.Lock()
+				missing[hash] = true
+				mu.Unlock()
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return missing, nil
+}
+
+// uploadBlobs re-reads the content stream and uploads the chunks whose hashes
+// are missing from the store. It returns the number of uncompressed bytes
+// uploaded.
+func (c *cacheSave) uploadBlobs(ctx context.Context, manifest *cachestore.Manifest, sources []cacheContentSource, missing map[string]bool) (int64, error) {
+	var uploaded atomic.Int64
+	queued := map[string]bool{}
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(cacheBlobConcurrency)
+	err := forEachCacheChunk(gctx, sources, func(i int, chunk []byte) error {
+		if i >= len(manifest.Chunks) || hashCacheChunk(chunk) != manifest.Chunks[i].Hash {
+			return errors.New("cache contents changed while saving")
+		}
+		hash := manifest.Chunks[i].Hash
+		if !missing[hash] || queued[hash] {
+			return nil
+		}
+		queued[hash] = true
+
+		data, err := compressCacheBlob(chunk)
+		if err != nil {
+			return errors.Wrapf(err, "compressing chunk '%s'", hash)
+		}
+		size := int64(len(chunk))
+		g.Go(func() error {
+			blobKey := cachestore.BlobKey(c.StorePath, hash)
+			err := retryCacheBlobOp(gctx, func() (bool, error) {
+				putErr := c.bucket.Put(gctx, blobKey, bytes.NewReader(data))
+				if putErr == nil || isS3PreconditionFailed(putErr) {
+					// Another save uploaded the same blob first.
+					return false, nil
+				}
+				return !isS3ClientError(putErr), putErr
+			})
+			if err != nil {
+				return errors.Wrapf(err, "uploading blob '%s'", blobKey)
+			}
+			uploaded.Add(size)
+			return nil
+		})
+		return nil
+	})
+	if waitErr := g.Wait(); waitErr != nil {
+		return 0, waitErr
+	}
+	if err != nil {
+		return 0, err
+	}
+	return uploaded.Load(), nil
+}
+
+func compressCacheBlob(chunk []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(chunk); err != nil {
+		return nil, errors.Wrap(err, "writing gzip data")
+	}
+	if err := gz.Close(); err != nil {
+		return nil, errors.Wrap(err, "closing gzip writer")
+	}
+	return buf.Bytes(), nil
+}
+
+// errCacheBlobMissing indicates that a manifest refers to a blob that isn't in
+// the store, for example because it was garbage collected while a save was
+// reusing it.
+var errCacheBlobMissing = errors.New("cache blob is missing")
+
+// downloadCacheBlob downloads, decompresses, and verifies a blob.
+func (c *cacheCommon) downloadCacheBlob(ctx context.Context, chunk cachestore.Chunk) ([]byte, error) {
+	blobKey := cachestore.BlobKey(c.StorePath, chunk.Hash)
+	var data []byte
+	err := retryCacheBlobOp(ctx, func() (bool, error) {
+		var getErr error
+		data, getErr = c.getCacheBlob(ctx, blobKey, chunk)
+		if getErr == nil {
+			return false, nil
+		}
+		switch classifyCacheDownloadErr(getErr) {
+		case cacheDownloadMiss, cacheDownloadMaybeMiss:
+			return false, errors.Wrapf(errCacheBlobMissing, "getting blob '%s'", blobKey)
+		case cacheDownloadFatal:
+			return false, getErr
+		default:
+			return true, getErr
+		}
+	})
+	return data, errors.Wrapf(err, "downloading blob '%s'", blobKey)
+}
+
+func (c *cacheCommon) getCacheBlob(ctx context.Context, blobKey string, chunk cachestore.Chunk) ([]byte, error) {
+	r, err := c.bucket.Get(ctx, blobKey)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading gzip header")
+	}
+	data, err := io.ReadAll(io.LimitReader(gz, chunk.Size+1))
+	if err != nil {
+		return nil, errors.Wrap(err, "decompressing blob")
+	}
+	if int64(len(data)) != chunk.Size || hashCacheChunk(data) != chunk.Hash {
+		return nil, errors.New("blob contents do not match their hash")
+	}
+	return data, nil
+}
+
+// restoreChunked downloads the blobs of a chunked cache and writes its entries
+// into rootPath. Blobs are downloaded in parallel but written in order. It
+// returns errCacheBlobMissing if any blob is not in the store.
+func (c *cacheRestore) restoreChunked(ctx context.Context, manifest *cachestore.Manifest, rootPath string) error {
+	for _, e := range manifest.Entries {
+		if err := validateRelativePath(e.Path, rootPath); err != nil {
+			return errors.Wrapf(err, "cache path '%s' should be relative to the root path", e.Path)
+		}
+		if e.Type == cachestore.EntryTypeSymlink {
+			if err := validateSymlinkTarget(e.LinkTarget, filepath.Join(rootPath, filepath.FromSlash(e.Path)), rootPath); err != nil {
+				return errors.Wrapf(err, "validating symlink target '%s' for '%s'", e.LinkTarget, e.Path)
+			}
+		}
+	}
+
+	for _, e := range manifest.Entries {
+		if e.Type != cachestore.EntryTypeDir {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Join(rootPath, filepath.FromSlash(e.Path)), 0755); err != nil {
+			return errors.Wrapf(err, "creating directory '%s'", e.Path)
+		}
+	}
+
+	if err := c.writeChunkedFiles(ctx, manifest, rootPath); err != nil {
+		return err
+	}
+
+	for _, e := range manifest.Entries {
+		if e.Type != cachestore.EntryTypeSymlink {
+			continue
+		}
+		linkPath := filepath.Join(rootPath, filepath.FromSlash(e.Path))
+		if err := os.MkdirAll(filepath.Dir(linkPath), 0755); err != nil {
+			return errors.Wrapf(err, "creating directory '%s'", filepath.Dir(linkPath))
+		}
+		// Replace any existing entry, like tarball extraction does.
+		if err := os.Remove(linkPath); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "removing existing entry '%s'", linkPath)
+		}
+		if err := os.Symlink(e.LinkTarget, linkPath); err != nil {
+			return errors.Wrapf(err, "creating symlink '%s'", linkPath)
+		}
+	}
+
+	return nil
+}
+
+// writeChunkedFiles downloads the chunks of the content stream, up to
+// cacheBlobConcurrency at a time, and writes the stream into the regular files
+// of the manifest.
+func (c *cacheRestore) writeChunkedFiles(ctx context.Context, manifest *cachestore.Manifest, rootPath string) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type blobResult struct {
+		data []byte
+		err  error
+	}
+	results := make([]chan blobResult, len(manifest.Chunks))
+	for i := range results {
+		results[i] = make(chan blobResult, 1)
+	}
+	sem := make(chan struct{}, cacheBlobConcurrency)
+	go func() {
+		for i, chunk := range manifest.Chunks {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			go func() {
+				data, err := c.downloadCacheBlob(ctx, chunk)
+				results[i] <- blobResult{data: data, err: err}
+			}()
+		}
+	}()
+
+	w := &cacheFileWriter{rootPath: rootPath, entries: manifest.Entries}
+	defer w.Close()
+	for i := range manifest.Chunks {
+		var res blobResult
+		select {
+		case res = <-results[i]:
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), "restoring cache was canceled")
+		}
+		<-sem
+		if res.err != nil {
+			return res.err
+		}
+		if _, err := w.Write(res.data); err != nil {
+			return errors.Wrap(err, "writing cache contents")
+		}
+	}
+	return errors.Wrap(w.finish(), "writing cache contents")
+}
+
+// cacheFileWriter writes a content stream into the regular files of a
+// manifest, in order.
+type cacheFileWriter struct {
+	rootPath  string
+	entries   []cachestore.Entry
+	idx       int
+	cur       *os.File
+	curEntry  cachestore.Entry
+	remaining int64
+}
+
+func (w *cacheFileWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		if w.cur == nil || w.remaining == 0 {
+			if err := w.openNext(); err != nil {
+				return written, err
+			}
+			continue
+		}
+		n := int(min(int64(len(p)), w.remaining))
+		n, err := w.cur.Write(p[:n])
+		written += n
+		w.remaining -= int64(n)
+		p = p[n:]
+		if err != nil {
+			return written, errors.Wrapf(err, "writing file '%s'", w.curEntry.Path)
+		}
+	}
+	return written, nil
+}
+
+// openNext closes the current file and opens the next one to write.
+func (w *cacheFileWriter) openNext() error {
+	if err := w.closeCurrent(); err != nil {
+		return err
+	}
+	for ; w.idx < len(w.entries); w.idx++ {
+		if w.entries[w.idx].Type == cachestore.EntryTypeFile {
+			break
+		}
+	}
+	if w.idx >= len(w.entries) {
+		return errors.New("cache contents are larger than the files in the manifest")
+	}
+	w.curEntry = w.entries[w.idx]
+	w.idx++
+
+	filePath := filepath.Join(w.rootPath, filepath.FromSlash(w.curEntry.Path))
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return errors.Wrapf(err, "creating directory '%s'", filepath.Dir(filePath))
+	}
+	f, err := os.Create(filePath)
+	if err != nil {
+		return errors.Wrapf(err, "creating file '%s'", filePath)
+	}
+	w.cur = f
+	w.remaining = w.curEntry.Size
+	return nil
+}
+
+func (w *cacheFileWriter) closeCurrent() error {
+	if w.cur == nil {
+		return nil
+	}
+	f := w.cur
+	w.cur = nil
+	if w.remaining != 0 {
+		_ = f.Close()
+		return errors.Errorf("file '%s' is missing %d bytes", w.curEntry.Path, w.remaining)
+	}
+	if err := f.Close(); err != nil {
+		return errors.Wrapf(err, "closing file '%s'", f.Name())
+	}
+	return errors.Wrapf(os.Chmod(f.Name(), os.FileMode(w.curEntry.Mode)), "changing file '%s' mode to %d", f.Name(), w.curEntry.Mode)
+}
+
+// finish writes any remaining (necessarily empty) files once the whole content
+// stream has been written.
+func (w *cacheFileWriter) finish() error {
+	for {
+		if err := w.closeCurrent(); err != nil {
+			return err
+		}
+		hasMore := false
+		for _, e := range w.entries[w.idx:] {
+			if e.Type == cachestore.EntryTypeFile {
+				hasMore = true
+				break
+			}
+		}
+		if !hasMore {
+			return nil
+		}
+		if err := w.openNext(); err != nil {
+			return err
+		}
+	}
+}
+
+func (w *cacheFileWriter) Close() error {
+	if w.cur == nil {
+		return nil
+	}
+	return w.cur.Close()
+}
+
+// isCacheBlobMissing returns whether err indicates that a chunked cache refers
+// to a blob that's no longer stored.
+func isCacheBlobMissing(err error) bool {
+	return errors.Is(err, errCacheBlobMissing)
+}
@@ -0,0 +1,232 @@
+package command
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/agent/internal"
+	"github.com/evergreen-ci/evergreen/agent/internal/client"
+	"github.com/evergreen-ci/evergreen/model/cachestore"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/pail"
+	"github.com/mongodb/grip/logging"
+	"github.com/mongodb/grip/send"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func chunkAll(t *testing.T, data []byte) [][]byte {
+	var chunks [][]byte
+	chunker := newCacheChunker(bytes.NewReader(data))
+	for {
+		chunk, err := chunker.next()
+		if err == io.EOF {
+			return chunks
+		}
+		require.NoError(t, err)
+		chunks = append(chunks, append([]byte(nil), chunk...))
+	}
+}
+
+func TestCacheChunker(t *testing.T) {
+	data := make([]byte, 12*1024*1024)
+	rand.New(rand.NewSource(1)).Read(data)
+
+	t.Run("ChunksReassembleIntoInput", func(t *testing.T) {
+		chunks := chunkAll(t, data)
+		assert.Greater(t, len(chunks), 1)
+		for i, chunk := range chunks {
+			assert.LessOrEqual(t, len(chunk), cacheChunkMaxSize)
+			if i < len(chunks)-1 {
+				assert.Greater(t, len(chunk), cacheChunkMinSize)
+			}
+		}
+		assert.Equal(t, data, bytes.Join(chunks, nil))
+	})
+	t.Run("EmptyInputHasNoChunks", func(t *testing.T) {
+		assert.Empty(t, chunkAll(t, nil))
+	})
+	t.Run("InsertionOnlyChangesNearbyChunks", func(t *testing.T) {
+		original := map[string]bool{}
+		for _, chunk := range chunkAll(t, data) {
+			original[hashCacheChunk(chunk)] = true
+		}
+
+		edited := append(append(append([]byte(nil), data[:5*1024*1024]...), []byte("inserted bytes")...), data[5*1024*1024:]...)
+		editedChunks := chunkAll(t, edited)
+		changed := 0
+		for _, chunk := range editedChunks {
+			if !original[hashCacheChunk(chunk)] {
+				changed++
+			}
+		}
+		assert.LessOrEqual(t, changed, 2, "only the chunks around the insertion should differ")
+	})
+}
+
+func TestCacheEntryName(t *testing.T) {
+	root := filepath.Join(string(filepath.Separator), "data", "mci")
+	assert.Equal(t, "deps/lib.txt", cacheEntryName(root, filepath.Join(root, "deps", "lib.txt")))
+	assert.Equal(t, "home/user/.m2", cacheEntryName(root, filepath.Join(string(filepath.Separator), "home", "user", ".m2")))
+}
+
+func TestChunkedCacheRoundTrip(t *testing.T) {
+	logger := logging.MakeGrip(send.MakeInternalLogger())
+
+	newCommon := func(bucket pail.Bucket) cacheCommon {
+		return cacheCommon{
+			CacheName:  "deps",
+			RemotePath: "project/caches",
+			Chunked:    true,
+			StorePath:  cachestore.DefaultPrefix,
+			bucket:     bucket,
+		}
+	}
+	setup := func(t *testing.T) (pail.Bucket, string) {
+		bucket, err := pail.NewLocalBucket(pail.LocalOptions{Path: t.TempDir()})
+		require.NoError(t, err)
+
+		srcDir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(srcDir, "top.txt"), []byte("top"), 0644))
+		require.NoError(t, os.MkdirAll(filepath.Join(srcDir, "deps", "empty-dir"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(srcDir, "deps", "empty.txt"), nil, 0600))
+		large := make([]byte, 3*1024*1024)
+		rand.New(rand.NewSource(2)).Read(large)
+		require.NoError(t, os.WriteFile(filepath.Join(srcDir, "deps", "lib.bin"), large, 0755))
+		return bucket, srcDir
+	}
+	restore := func(t *testing.T, bucket pail.Bucket, key string) (string, error) {
+		ctx := t.Context()
+		r := &cacheRestore{cacheCommon: newCommon(bucket)}
+		data, err := bucket.Get(ctx, r.manifestKey(key))
+		require.NoError(t, err)
+		defer data.Close()
+		b, err := io.ReadAll(data)
+		require.NoError(t, err)
+		manifest, err := cachestore.UnmarshalManifest(b)
+		require.NoError(t, err)
+
+		destDir := t.TempDir()
+		return destDir, r.restoreChunked(ctx, manifest, destDir)
+	}
+
+	t.Run("RestoresSavedContents", func(t *testing.T) {
+		bucket, srcDir := setup(t)
+		s := &cacheSave{cacheCommon: newCommon(bucket), Paths: []string{"top.txt", "deps"}}
+		require.NoError(t, s.saveChunked(t.Context(), logger, &internal.TaskConfig{WorkDir: srcDir}, "key"))
+
+		destDir, err := restore(t, bucket, "key")
+		require.NoError(t, err)
+		for _, name := range []string{"top.txt", filepath.Join("deps", "empty.txt"), filepath.Join("deps", "lib.bin")} {
+			expected, err := os.ReadFile(filepath.Join(srcDir, name))
+			require.NoError(t, err)
+			actual, err := os.ReadFile(filepath.Join(destDir, name))
+			require.NoError(t, err, name)
+			assert.Equal(t, expected, actual, name)
+		}
+		info, err := os.Stat(filepath.Join(destDir, "deps", "empty-dir"))
+		require.NoError(t, err)
+		assert.True(t, info.IsDir())
+		if runtime.GOOS != "windows" {
+			info, err = os.Stat(filepath.Join(destDir, "deps", "lib.bin"))
+			require.NoError(t, err)
+			assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+		}
+
+		pending, err := bucket.List(t.Context(), cachestore.DefaultPrefix+"/pending/")
+		require.NoError(t, err)
+		assert.False(t, pending.Next(t.Context()), "pending manifest should be removed after saving")
+	})
+	t.Run("SavingChangedContentsOnlyUploadsNewChunks", func(t *testing.T) {
+		bucket, srcDir := setup(t)
+		s := &cacheSave{cacheCommon: newCommon(bucket), Paths: []string{"top.txt", "deps"}}
+		require.NoError(t, s.saveChunked(t.Context(), logger, &internal.TaskConfig{WorkDir: srcDir}, "key1"))
+		countBlobs := func() int {
+			iter, err := bucket.List(t.Context(), cachestore.DefaultPrefix+"/blobs/")
+			require.NoError(t, err)
+			n := 0
+			for iter.Next(t.Context()) {
+				n++
+			}
+			return n
+		}
+		before := countBlobs()
+
+		require.NoError(t, os.WriteFile(filepath.Join(srcDir, "top.txt"), []byte("changed"), 0644))
+		s = &cacheSave{cacheCommon: newCommon(bucket), Paths: []string{"top.txt", "deps"}}
+		require.NoError(t, s.saveChunked(t.Context(), logger, &internal.TaskConfig{WorkDir: srcDir}, "key2"))
+		assert.LessOrEqual(t, countBlobs()-before, 1, "unchanged chunks should be shared between keys")
+
+		destDir, err := restore(t, bucket, "key2")
+		require.NoError(t, err)
+		top, err := os.ReadFile(filepath.Join(destDir, "top.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, "changed", string(top))
+	})
+	t.Run("RestoringWithMissingBlobIsAMiss", func(t *testing.T) {
+		bucket, srcDir := setup(t)
+		s := &cacheSave{cacheCommon: newCommon(bucket), Paths: []string{"top.txt", "deps"}}
+		require.NoError(t, s.saveChunked(t.Context(), logger, &internal.TaskConfig{WorkDir: srcDir}, "key"))
+		require.NoError(t, bucket.RemovePrefix(t.Context(), cachestore.DefaultPrefix+"/blobs/"))
+
+		_, err := restore(t, bucket, "key")
+		require.Error(t, err)
+		assert.True(t, isCacheBlobMissing(err))
+	})
+	t.Run("MissingBlobRemovesManifestSoNextSaveUploadsAgain", func(t *testing.T) {
+		bucket, srcDir := setup(t)
+		s := &cacheSave{cacheCommon: newCommon(bucket), Paths: []string{"top.txt", "deps"}}
+		require.NoError(t, s.saveChunked(t.Context(), logger, &internal.TaskConfig{WorkDir: srcDir}, "key"))
+		require.NoError(t, bucket.RemovePrefix(t.Context(), cachestore.DefaultPrefix+"/blobs/"))
+
+		r := &cacheRestore{cacheCommon: newCommon(bucket)}
+		manifestKey := r.manifestKey("key")
+		conf := &internal.TaskConfig{WorkDir: t.TempDir(), Expansions: *util.NewExpansions(nil)}
+		taskLogger := client.NewSingleChannelLogHarness("test", send.MakeInternalLogger())
+		require.NoError(t, r.executeChunked(t.Context(), taskLogger, conf, "key", manifestKey))
+		assert.False(t, cacheWasHit(conf, "deps"))
+		exists, err := bucket.Exists(t.Context(), manifestKey)
+		require.NoError(t, err)
+		assert.False(t, exists, "manifest referring to a missing blob should be removed")
+
+		s = &cacheSave{cacheCommon: newCommon(bucket), Paths: []string{"top.txt", "deps"}}
+		require.NoError(t, s.saveChunked(t.Context(), logger, &internal.TaskConfig{WorkDir: srcDir}, "key"))
+		destDir, err := restore(t, bucket, "key")
+		require.NoError(t, err)
+		top, err := os.ReadFile(filepath.Join(destDir, "top.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, "top", string(top))
+	})
+	t.Run("PreservesSymlinks", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("symlink preservation is not supported on Windows")
+		}
+		bucket, srcDir := setup(t)
+		require.NoError(t, os.Symlink("top.txt", filepath.Join(srcDir, "link.txt")))
+		s := &cacheSave{cacheCommon: newCommon(bucket), Paths: []string{"top.txt", "link.txt"}}
+		s.PreserveSymlinks = true
+		require.NoError(t, s.saveChunked(t.Context(), logger, &internal.TaskConfig{WorkDir: srcDir}, "key"))
+
+		destDir, err := restore(t, bucket, "key")
+		require.NoError(t, err)
+		target, err := os.Readlink(filepath.Join(destDir, "link.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, "top.txt", target)
+	})
+	t.Run("RejectsManifestEscapingRoot", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("symlink preservation is not supported on Windows")
+		}
+		r := &cacheRestore{cacheCommon: newCommon(nil)}
+		manifest := &cachestore.Manifest{
+			Version: cachestore.ManifestVersion,
+			Entries: []cachestore.Entry{{Path: "link", Type: cachestore.EntryTypeSymlink, LinkTarget: "../../etc/passwd"}},
+		}
+		assert.Error(t, r.restoreChunked(t.Context(), manifest, t.TempDir()))
+	})
+}
@@ -3,12 +3,14 @@ package command
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/aws/smithy-go"
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/agent/internal"
 	"github.com/evergreen-ci/evergreen/agent/internal/client"
+	"github.com/evergreen-ci/evergreen/model/cachestore"
 	"github.com/evergreen-ci/pail"
 	"github.com/evergreen-ci/utility"
 	"github.com/mongodb/grip"
@@ -16,8 +18,8 @@ import (
 )
 
 // cacheRestore is a command that downloads and extracts a previously saved
-// cache artifact from S3, setting a cache-hit expansion the rest of the task
-// can branch on.
+// cache artifact (or, in chunked mode, the chunks listed in its manifest) from
+// S3, setting a cache-hit expansion the rest of the task can branch on.
 type cacheRestore struct {
 	cacheCommon `mapstructure:",squash" plugin:"expand"`
 	base
@@ -56,6 +58,9 @@ func (c *cacheRestore) Execute(ctx context.Context, comm client.Communicator, lo
 	}
 
 	remoteKey := c.remoteKey(key)
+	if c.Chunked {
+		remoteKey = c.manifestKey(key)
+	}
 
 	logger.Task().Infof(ctx, "cache.restore: computed cache key '%s'.", key)
 	logger.Task().Infof(ctx, "cache.restore: looking up cache at '%s/%s'.", c.Bucket, remoteKey)
@@ -70,6 +75,10 @@ func (c *cacheRestore) Execute(ctx context.Context, comm client.Communicator, lo
 		return errors.Wrap(err, "checking bucket")
 	}
 
+	if c.Chunked {
+		return errors.Wrap(c.executeChunked(ctx, logger, conf, key, remoteKey), "restoring chunked cache")
+	}
+
 	localPath, err := createTempCacheArchive(conf.WorkDir)
 	if err != nil {
 		return errors.Wrap(err, "creating local cache file")
@@ -169,6 +178,70 @@ func classifyCacheDownloadErr(err error) cacheDownloadOutcome {
 	return cacheDownloadRetry
 }
 
+// executeChunked downloads the manifest of a chunked cache and restores the
+// entries it lists. A missing manifest is a cache miss, as is a missing blob,
+// which can happen if it was garbage collected while a save was reusing it. In
+// that case the manifest is removed so that the next cache.save of the key
+// uploads the cache again rather than skipping it because the manifest exists.
+func (c *cacheRestore) executeChunked(ctx context.Context, logger client.LoggerProducer, conf *internal.TaskConfig, key, manifestKey string) error {
+	var manifestData []byte
+	miss := false
+	downloadDesc := fmt.Sprintf("download cache manifest '%s'", manifestKey)
+	err := retryS3Op(ctx, logger.Task(), downloadDesc, func() (bool, error) {
+		r, getErr := c.bucket.Get(ctx, manifestKey)
+		if getErr == nil {
+			defer r.Close()
+			manifestData, getErr = io.ReadAll(r)
+			if getErr == nil {
+				return false, nil
+			}
+		}
+		switch classifyCacheDownloadErr(getErr) {
+		case cacheDownloadMaybeMiss:
+			logger.Task().Warningf(ctx, "cache.restore: got access-denied downloading '%s/%s', treating as a cache miss; if a cache was expected here, verify the credentials grant s3:GetObject on this path.", c.Bucket, manifestKey)
+			miss = true
+			return false, nil
+		case cacheDownloadMiss:
+			miss = true
+			return false, nil
+		case cacheDownloadFatal:
+			return false, getErr
+		default:
+			return true, getErr
+		}
+	})
+	if err != nil {
+		return errors.Wrapf(err, "downloading cache manifest '%s'", manifestKey)
+	}
+	if miss {
+		logger.Task().Infof(ctx, "cache.restore: cache miss for key '%s'.", key)
+		setCacheHit(conf, c.CacheName, false)
+		return nil
+	}
+
+	manifest, err := cachestore.UnmarshalManifest(manifestData)
+	if err != nil {
+		return errors.Wrapf(err, "reading cache manifest '%s'", manifestKey)
+	}
+
+	logger.Task().Infof(ctx, "cache.restore: downloading %d chunks for %d entries.", len(manifest.Chunks), len(manifest.Entries))
+	if err := c.restoreChunked(ctx, manifest, conf.WorkDir); err != nil {
+		if isCacheBlobMissing(err) {
+			logger.Task().Warningf(ctx, "cache.restore: treating cache as a miss because it refers to a chunk that is no longer stored: %s", err)
+			if removeErr := c.bucket.Remove(ctx, manifestKey); removeErr != nil {
+				logger.Task().Warningf(ctx, "cache.restore: could not remove cache manifest '%s/%s' that refers to a missing chunk, so it will not be saved again until it expires: %s", c.Bucket, manifestKey, removeErr)
+			}
+			setCacheHit(conf, c.CacheName, false)
+			return nil
+		}
+		return err
+	}
+
+	logger.Task().Infof(ctx, "cache.restore: cache hit for key '%s', restored into '%s'.", key, conf.WorkDir)
+	setCacheHit(conf, c.CacheName, true)
+	return nil
+}
+
 func (c *cacheRestore) extract(ctx context.Context, archivePath, dest string) error {
 	f, err := os.Open(archivePath)
 	if err != nil {
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/aws/smithy-go"
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/agent/internal"
 	"github.com/evergreen-ci/evergreen/agent/internal/client"
@@ -15,8 +14,10 @@ import (
 )
 
 // cacheSave is a command that bundles paths into a tarball and uploads it to S3
-// so a later task can restore them via cache.restore. It no-ops when the
-// corresponding cache.restore already reported a hit.
+// so a later task can restore them via cache.restore. In chunked mode, it
+// instead uploads the chunks of the paths' contents that aren't already stored,
+// plus a manifest. It no-ops when the corresponding cache.restore already
+// reported a hit.
 type cacheSave struct {
 	cacheCommon `mapstructure:",squash" plugin:"expand"`
 
@@ -69,6 +70,20 @@ func (c *cacheSave) Execute(ctx context.Context, comm client.Communicator, logge
 		return errors.Wrap(err, "computing cache key")
 	}
 
+	if c.Chunked {
+		logger.Task().Infof(ctx, "cache.save: computed cache key '%s'.", key)
+		httpClient := utility.GetHTTPClient()
+		httpClient.Timeout = s3HTTPClientTimeout
+		defer utility.PutHTTPClient(httpClient)
+		if err := c.createPailBucket(ctx, comm, httpClient, true); err != nil {
+			return errors.Wrap(err, "connecting to S3")
+		}
+		if err := c.bucket.Check(ctx); err != nil {
+			return errors.Wrap(err, "checking bucket")
+		}
+		return errors.Wrap(c.saveChunked(ctx, logger.Task(), conf, key), "saving chunked cache")
+	}
+
 	remoteKey := c.remoteKey(key)
 
 	localPath, err := createTempCacheArchive(conf.WorkDir)
@@ -106,8 +121,7 @@ func (c *cacheSave) Execute(ctx context.Context, comm client.Communicator, logge
 		// Skip-existing semantics: S3 reports PreconditionFailed when the
 		// object already exists and IfNotExists is set on the request. That is
 		// not an error for caching, it just means another task saved first.
-		var apiErr smithy.APIError
-		if errors.As(uploadErr, &apiErr) && apiErr.ErrorCode() == "PreconditionFailed" {
+		if isS3PreconditionFailed(uploadErr) {
 			alreadyExists = true
 			return false, nil
 		}
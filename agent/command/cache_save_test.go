@@ -69,6 +69,20 @@ func TestCacheSaveParseParams(t *testing.T) {
 		require.NoError(t, c.ParseParams(params))
 		assert.True(t, c.PreserveSymlinks)
 	})
+
+	t.Run("ChunkedDecodedAndDefaultsToFalse", func(t *testing.T) {
+		c := &cacheSave{}
+		require.NoError(t, c.ParseParams(validParams()))
+		assert.False(t, c.Chunked)
+
+		params := validParams()
+		params["chunked"] = true
+		params["store_path"] = "shared-store"
+		c = &cacheSave{}
+		require.NoError(t, c.ParseParams(params))
+		assert.True(t, c.Chunked)
+		assert.Equal(t, "shared-store", c.StorePath)
+	})
 }
 
 // TestCacheSaveRecomputedKeyMatchesRestore verifies cache.save derives the same
@@ -19,6 +19,7 @@ import (
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/agent/internal"
 	"github.com/evergreen-ci/evergreen/agent/internal/client"
+	"github.com/evergreen-ci/evergreen/model/cachestore"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/pail"
 	"github.com/evergreen-ci/utility"
@@ -69,6 +70,18 @@ type cacheCommon struct {
 	// cache.restore.
 	PreserveSymlinks bool `mapstructure:"preserve_symlinks"`
 
+	// Chunked, when true, stores the cache as content-addressed chunks in a
+	// store shared with other caches instead of as a single tarball, so that
+	// saving only uploads the chunks that aren't already stored. It must
+	// match between cache.save and cache.restore.
+	Chunked bool `mapstructure:"chunked"`
+
+	// StorePath is the S3 key prefix of the store that holds the chunks of a
+	// chunked cache. Caches in the same bucket and store share chunks,
+	// regardless of their remote_path. It defaults to
+	// "evergreen-cache-store".
+	StorePath string `mapstructure:"store_path" plugin:"expand"`
+
 	// AwsKey, AwsSecret, and AwsSessionToken are the user's credentials for
 	// authenticating interactions with S3.
 	AwsKey          string `mapstructure:"aws_key" plugin:"expand"`
@@ -124,6 +137,9 @@ func (c *cacheCommon) expandParams(command any, conf *internal.TaskConfig) error
 	if c.Region == "" {
 		c.Region = evergreen.DefaultEC2Region
 	}
+	if c.Chunked && c.StorePath == "" {
+		c.StorePath = cachestore.DefaultPrefix
+	}
 	return nil
 }
 
@@ -229,6 +245,13 @@ func retryS3Op(ctx context.Context, logger grip.Journaler, description string, o
 	})
 }
 
+// isS3PreconditionFailed reports whether err is the response S3 gives when an
+// object already exists and IfNotExists is set on the request.
+func isS3PreconditionFailed(err error) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == "PreconditionFailed"
+}
+
 // isS3ClientError reports whether err is an S3 API error caused by a client
 // (4xx) fault. Such errors won't succeed on retry, so callers should treat them
 // as terminal rather than burning the retry budget.
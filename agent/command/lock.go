@@ -0,0 +1,150 @@
+package command
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/agent/internal"
+	"github.com/evergreen-ci/evergreen/agent/internal/client"
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/model/lease"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+)
+
+// lockPollInterval is how often lock.acquire tries again to acquire a lock
+// held by other tasks.
+var lockPollInterval = 15 * time.Second
+
+type lockAcquire struct {
+	// LockName is the name of the project lock to acquire.
+	LockName string `mapstructure:"name" plugin:"expand"`
+	// Slots is the number of tasks that can hold the lock at once. Defaults
+	// to 1.
+	Slots int `mapstructure:"slots"`
+	// TimeoutSecs is how long to wait for the lock before failing. By default,
+	// it waits until the task times out.
+	TimeoutSecs int `mapstructure:"timeout_secs"`
+	base
+}
+
+func lockAcquireFactory() Command { return &lockAcquire{} }
+func (*lockAcquire) Name() string { return "lock.acquire" }
+
+// ParseParams validates the input to lock.acquire.
+func (c *lockAcquire) ParseParams(params map[string]any) error {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		WeaklyTypedInput: true,
+		Result:           c,
+	})
+	if err != nil {
+		return errors.Wrap(err, "constructing mapstructure decoder")
+	}
+	if err := decoder.Decode(params); err != nil {
+		return errors.Wrap(err, "decoding mapstructure params")
+	}
+
+	if c.LockName == "" {
+		return errors.New("lock name must be set")
+	}
+	if c.Slots == 0 {
+		c.Slots = 1
+	}
+	if c.Slots < 0 || c.Slots > lease.MaxSlots {
+		return errors.Errorf("slots must be between 1 and %d", lease.MaxSlots)
+	}
+	if c.TimeoutSecs < 0 {
+		return errors.New("cannot have negative timeout")
+	}
+
+	return nil
+}
+
+// Execute waits until the task acquires the lock. The lock is held until
+// lock.release releases it or the task finishes.
+func (c *lockAcquire) Execute(ctx context.Context, comm client.Communicator, logger client.LoggerProducer, conf *internal.TaskConfig) error {
+	if err := util.ExpandValues(c, &conf.Expansions); err != nil {
+		return errors.Wrap(err, "applying expansions")
+	}
+	if c.LockName == "" {
+		return errors.New("lock name cannot be empty after expansion")
+	}
+
+	if c.TimeoutSecs > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(c.TimeoutSecs)*time.Second)
+		defer cancel()
+	}
+
+	td := client.TaskData{ID: conf.Task.Id, Secret: conf.Task.Secret}
+	req := apimodels.LockRequest{Name: c.LockName, Slots: c.Slots}
+	start := time.Now()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if c.TimeoutSecs > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return errors.Errorf("reached timeout (%d seconds) waiting for lock '%s'", c.TimeoutSecs, c.LockName)
+			}
+			return errors.Wrapf(ctx.Err(), "waiting for lock '%s'", c.LockName)
+		case <-timer.C:
+			resp, err := comm.AcquireLock(ctx, td, req)
+			if err != nil {
+				return errors.Wrapf(err, "acquiring lock '%s'", c.LockName)
+			}
+			if resp.Acquired {
+				logger.Task().Infof(ctx, "Acquired lock '%s' after waiting %s.", c.LockName, time.Since(start).Round(time.Second))
+				return nil
+			}
+
+			holders := make([]string, 0, len(resp.Holders))
+			for _, holder := range resp.Holders {
+				holders = append(holders, holder.TaskID)
+			}
+			logger.Task().Infof(ctx, "Waiting for lock '%s', which is held by: %s.", c.LockName, strings.Join(holders, ", "))
+			timer.Reset(lockPollInterval)
+		}
+	}
+}
+
+type lockRelease struct {
+	// LockName is the name of the project lock to release.
+	LockName string `mapstructure:"name" plugin:"expand"`
+	base
+}
+
+func lockReleaseFactory() Command { return &lockRelease{} }
+func (*lockRelease) Name() string { return "lock.release" }
+
+// ParseParams validates the input to lock.release.
+func (c *lockRelease) ParseParams(params map[string]any) error {
+	if err := mapstructure.Decode(params, c); err != nil {
+		return errors.Wrap(err, "decoding mapstructure params")
+	}
+	if c.LockName == "" {
+		return errors.New("lock name must be set")
+	}
+	return nil
+}
+
+// Execute releases the task's hold on the lock. It succeeds even if the task
+// does not hold the lock.
+func (c *lockRelease) Execute(ctx context.Context, comm client.Communicator, logger client.LoggerProducer, conf *internal.TaskConfig) error {
+	if err := util.ExpandValues(c, &conf.Expansions); err != nil {
+		return errors.Wrap(err, "applying expansions")
+	}
+	if c.LockName == "" {
+		return errors.New("lock name cannot be empty after expansion")
+	}
+
+	td := client.TaskData{ID: conf.Task.Id, Secret: conf.Task.Secret}
+	if err := comm.ReleaseLock(ctx, td, apimodels.LockRequest{Name: c.LockName}); err != nil {
+		return errors.Wrapf(err, "releasing lock '%s'", c.LockName)
+	}
+	logger.Task().Infof(ctx, "Released lock '%s'.", c.LockName)
+	return nil
+}
@@ -0,0 +1,112 @@
+package command
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/agent/internal"
+	"github.com/evergreen-ci/evergreen/agent/internal/client"
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockAcquireParseParams(t *testing.T) {
+	t.Run("DefaultsToOneSlot", func(t *testing.T) {
+		cmd := lockAcquireFactory().(*lockAcquire)
+		require.NoError(t, cmd.ParseParams(map[string]any{"name": "staging"}))
+		assert.Equal(t, "staging", cmd.LockName)
+		assert.Equal(t, 1, cmd.Slots)
+	})
+	t.Run("AcceptsStringSlots", func(t *testing.T) {
+		cmd := lockAcquireFactory().(*lockAcquire)
+		require.NoError(t, cmd.ParseParams(map[string]any{"name": "license", "slots": "3", "timeout_secs": "60"}))
+		assert.Equal(t, 3, cmd.Slots)
+		assert.Equal(t, 60, cmd.TimeoutSecs)
+	})
+	t.Run("RequiresName", func(t *testing.T) {
+		cmd := lockAcquireFactory().(*lockAcquire)
+		assert.Error(t, cmd.ParseParams(map[string]any{}))
+	})
+	t.Run("RejectsInvalidSlots", func(t *testing.T) {
+		cmd := lockAcquireFactory().(*lockAcquire)
+		assert.Error(t, cmd.ParseParams(map[string]any{"name": "staging", "slots": -1}))
+		cmd = lockAcquireFactory().(*lockAcquire)
+		assert.Error(t, cmd.ParseParams(map[string]any{"name": "staging", "slots": 1000}))
+	})
+	t.Run("RejectsNegativeTimeout", func(t *testing.T) {
+		cmd := lockAcquireFactory().(*lockAcquire)
+		assert.Error(t, cmd.ParseParams(map[string]any{"name": "staging", "timeout_secs": -1}))
+	})
+}
+
+func TestLockAcquireExecute(t *testing.T) {
+	originalInterval := lockPollInterval
+	lockPollInterval = 10 * time.Millisecond
+	defer func() {
+		lockPollInterval = originalInterval
+	}()
+
+	setup := func(t *testing.T) (*client.Mock, client.LoggerProducer, *internal.TaskConfig) {
+		comm := client.NewMock("http://localhost.com")
+		conf := &internal.TaskConfig{
+			Task:       task.Task{Id: "task_id", Secret: "task_secret"},
+			Expansions: util.Expansions{"env": "staging"},
+		}
+		logger, err := comm.GetLoggerProducer(t.Context(), &conf.Task, nil)
+		require.NoError(t, err)
+		return comm, logger, conf
+	}
+	held := apimodels.LockAcquireResponse{Holders: []apimodels.LockHolder{{TaskID: "other_task"}}}
+
+	t.Run("AcquiresFreeLock", func(t *testing.T) {
+		comm, logger, conf := setup(t)
+		cmd := &lockAcquire{LockName: "${env}", Slots: 1}
+		require.NoError(t, cmd.Execute(t.Context(), comm, logger, conf))
+		assert.Equal(t, "staging", cmd.LockName)
+		assert.Equal(t, 1, comm.AcquireLockCount)
+	})
+	t.Run("WaitsForHeldLock", func(t *testing.T) {
+		comm, logger, conf := setup(t)
+		comm.AcquireLockResponses = []apimodels.LockAcquireResponse{held, held, {Acquired: true}}
+		cmd := &lockAcquire{LockName: "staging", Slots: 1}
+		require.NoError(t, cmd.Execute(t.Context(), comm, logger, conf))
+		assert.Equal(t, 3, comm.AcquireLockCount)
+	})
+	t.Run("TimesOutWaitingForLock", func(t *testing.T) {
+		comm, logger, conf := setup(t)
+		comm.AcquireLockResponses = []apimodels.LockAcquireResponse{held}
+		cmd := &lockAcquire{LockName: "staging", Slots: 1, TimeoutSecs: 1}
+		err := cmd.Execute(t.Context(), comm, logger, conf)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "reached timeout")
+		assert.Greater(t, comm.AcquireLockCount, 1)
+	})
+	t.Run("FailsOnRequestError", func(t *testing.T) {
+		comm, logger, conf := setup(t)
+		comm.AcquireLockShouldFail = true
+		cmd := &lockAcquire{LockName: "staging", Slots: 1}
+		assert.Error(t, cmd.Execute(t.Context(), comm, logger, conf))
+	})
+}
+
+func TestLockRelease(t *testing.T) {
+	comm := client.NewMock("http://localhost.com")
+	conf := &internal.TaskConfig{
+		Task:       task.Task{Id: "task_id", Secret: "task_secret"},
+		Expansions: util.Expansions{"env": "staging"},
+	}
+	logger, err := comm.GetLoggerProducer(t.Context(), &conf.Task, nil)
+	require.NoError(t, err)
+
+	cmd := lockReleaseFactory().(*lockRelease)
+	assert.Error(t, cmd.ParseParams(map[string]any{}))
+	require.NoError(t, cmd.ParseParams(map[string]any{"name": "${env}"}))
+	require.NoError(t, cmd.Execute(t.Context(), comm, logger, conf))
+	assert.Equal(t, []string{"staging"}, comm.ReleasedLocks)
+
+	comm.ReleaseLockShouldFail = true
+	assert.Error(t, cmd.Execute(t.Context(), comm, logger, conf))
+}
@@ -67,10 +67,30 @@ func (c *perfSend) Execute(ctx context.Context, comm client.Communicator, logger
 	}
 	c.addEvgData(report, conf)
 
+	// The bucket credentials are only needed to upload artifacts, so they're
+	// left out of the results stored in Evergreen.
+	evgReport := *report
+	evgReport.BucketConf = poplar.BucketConfiguration{}
+	// Storing the report in Evergreen is best-effort so that it can't prevent
+	// the report from reaching the external performance service.
+	td := client.TaskData{ID: conf.Task.Id, Secret: conf.Task.Secret}
+	evgErr := errors.Wrap(comm.SendPerfReport(ctx, td, &evgReport), "sending perf report to Evergreen")
+	if evgErr != nil {
+		logger.Task().Warning(ctx, evgErr)
+	}
+
 	perfURL, err := comm.GetPerfMonitoringURL(ctx)
 	if err != nil {
 		return errors.Wrap(err, "getting performance URL")
 	}
+	if perfURL == "" {
+		if evgErr != nil {
+			// There's nowhere else to send the report, so it would be lost.
+			return evgErr
+		}
+		logger.Task().Info(ctx, "No external performance service is configured, so the perf report was only stored in Evergreen.")
+		return nil
+	}
 	opts := rpc.UploadReportOptions{
 		Report: report,
 		SPSURL: perfURL,
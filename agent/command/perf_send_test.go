@@ -1,13 +1,18 @@
 package command
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/agent/internal"
+	"github.com/evergreen-ci/evergreen/agent/internal/client"
 	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/poplar"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPerfSendParseParams(t *testing.T) {
@@ -80,3 +85,46 @@ func TestPerfSendAddEvgData(t *testing.T) {
 	cmd.addEvgData(report, conf)
 	assert.Equal(t, expectedReport, report)
 }
+
+func TestPerfSendExecute(t *testing.T) {
+	setup := func(t *testing.T) (*client.Mock, client.LoggerProducer, *internal.TaskConfig) {
+		comm := client.NewMock("http://localhost.com")
+		comm.NoPerfMonitoringURL = true
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "perf.json"), []byte(`[{"info": {"test_name": "insert"}, "metrics": [{"name": "ops_per_sec", "value": 100.5}]}]`), 0644))
+		conf := &internal.TaskConfig{
+			Task:       task.Task{Id: "task_id", Secret: "task_secret", Project: "project", BuildVariant: "bv", DisplayName: "task"},
+			WorkDir:    dir,
+			Expansions: util.Expansions{},
+		}
+		logger, err := comm.GetLoggerProducer(t.Context(), &conf.Task, nil)
+		require.NoError(t, err)
+		return comm, logger, conf
+	}
+
+	t.Run("SendsReportWithoutCredentials", func(t *testing.T) {
+		comm, logger, conf := setup(t)
+		cmd := &perfSend{File: "perf.json", AWSKey: "key", AWSSecret: "secret"}
+		require.NoError(t, cmd.Execute(t.Context(), comm, logger, conf))
+
+		require.Len(t, comm.PerfReports, 1)
+		report := comm.PerfReports[0]
+		assert.Equal(t, "task_id", report.TaskID)
+		assert.Equal(t, "bv", report.Variant)
+		assert.Zero(t, report.BucketConf)
+		require.Len(t, report.Tests, 1)
+		assert.Equal(t, "insert", report.Tests[0].Info.TestName)
+	})
+	t.Run("FailsWhenReportIsNotStoredAnywhere", func(t *testing.T) {
+		comm, logger, conf := setup(t)
+		comm.SendPerfReportShouldFail = true
+		cmd := &perfSend{File: "perf.json"}
+		assert.Error(t, cmd.Execute(t.Context(), comm, logger, conf))
+	})
+	t.Run("FailsWithMissingFile", func(t *testing.T) {
+		comm, logger, conf := setup(t)
+		cmd := &perfSend{File: "missing.json"}
+		assert.Error(t, cmd.Execute(t.Context(), comm, logger, conf))
+		assert.Empty(t, comm.PerfReports)
+	})
+}
@@ -30,6 +30,7 @@ func init() {
 		evergreen.AttachArtifactsCommandName:    attachArtifactsFactory,
 		evergreen.CacheRestoreCommandName:       cacheRestoreFactory,
 		evergreen.CacheSaveCommandName:          cacheSaveFactory,
+		"ctrf.parse_files":                      ctrfResultsFactory,
 		evergreen.HostCreateCommandName:         createHostFactory,
 		"ec2.assume_role":                       ec2AssumeRoleFactory,
 		"host.list":                             listHostFactory,
@@ -41,9 +42,12 @@ func init() {
 		"github.generate_token":                 githubGenerateTokenFactory,
 		"gotest.parse_files":                    goTestFactory,
 		"keyval.inc":                            keyValIncFactory,
+		"lock.acquire":                          lockAcquireFactory,
+		"lock.release":                          lockReleaseFactory,
 		"manifest.load":                         manifestLoadFactory,
 		"papertrail.trace":                      papertrailTraceFactory,
 		"perf.send":                             perfSendFactory,
+		"pytest.parse_files":                    pytestResultsFactory,
 		"downstream_expansions.set":             setExpansionsFactory,
 		"s3.get":                                s3GetFactory,
 		"s3.put":                                s3PutFactory,
@@ -51,6 +55,7 @@ func init() {
 		evergreen.ShellExecCommandName:          shellExecFactory,
 		"subprocess.exec":                       subprocessExecFactory,
 		"setup.initial":                         initialSetupFactory,
+		"tap.parse_files":                       tapResultsFactory,
 		"test_selection.get":                    testSelectionGetFactory,
 		"timeout.update":                        timeoutUpdateFactory,
 	}
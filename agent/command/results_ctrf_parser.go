@@ -0,0 +1,135 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/pkg/errors"
+)
+
+// ctrfReport is a Common Test Report Format (CTRF) JSON report.
+type ctrfReport struct {
+	ReportFormat string `json:"reportFormat"`
+	Results      struct {
+		Tests []ctrfTest `json:"tests"`
+	} `json:"results"`
+}
+
+// ctrfTest is a single test of a CTRF report.
+type ctrfTest struct {
+	Name string `json:"name"`
+	// Status is one of "passed", "failed", "skipped", "pending" or
+	// "other".
+	Status string `json:"status"`
+	// Duration is the duration of the test in milliseconds.
+	Duration float64 `json:"duration"`
+	// Start and Stop are the Unix timestamps, in milliseconds, of when the
+	// test started and stopped.
+	Start    int64           `json:"start"`
+	Stop     int64           `json:"stop"`
+	Suite    json.RawMessage `json:"suite"`
+	Message  string          `json:"message"`
+	Trace    string          `json:"trace"`
+	FilePath string          `json:"filePath"`
+	Line     int             `json:"line"`
+	Retries  int             `json:"retries"`
+	Flaky    bool            `json:"flaky"`
+	Stdout   []string        `json:"stdout"`
+	Stderr   []string        `json:"stderr"`
+}
+
+// parseCTRFReport parses a CTRF JSON report. Each test is named after its
+// suite, if any, and its log contains its message, which is the skip reason
+// for skipped tests, its stack trace, and its captured output.
+func parseCTRFReport(r io.Reader) (*testReport, error) {
+	var ctrf ctrfReport
+	if err := json.NewDecoder(r).Decode(&ctrf); err != nil {
+		return nil, errors.Wrap(err, "decoding CTRF report")
+	}
+	if ctrf.ReportFormat != "" && ctrf.ReportFormat != "CTRF" {
+		return nil, errors.Errorf("unrecognized report format '%s'", ctrf.ReportFormat)
+	}
+
+	report := &testReport{}
+	for _, test := range ctrf.Results.Tests {
+		tc := testReportCase{
+			name:     test.Name,
+			status:   ctrfStatus(test.Status),
+			duration: time.Duration(test.Duration * float64(time.Millisecond)),
+		}
+		if suite := ctrfSuiteName(test.Suite); suite != "" {
+			tc.name = suite + "/" + test.Name
+		}
+		if test.Start > 0 {
+			tc.start = time.UnixMilli(test.Start)
+			if test.Stop >= test.Start {
+				tc.end = time.UnixMilli(test.Stop)
+			}
+		}
+
+		var lines []string
+		if test.FilePath != "" {
+			location := test.FilePath
+			if test.Line > 0 {
+				location += ":" + strconv.Itoa(test.Line)
+			}
+			lines = append(lines, "location: "+location)
+		}
+		if test.Retries > 0 || test.Flaky {
+			lines = append(lines, fmt.Sprintf("retries: %d, flaky: %t", test.Retries, test.Flaky))
+		}
+		lines = append(lines, splitReportLines(test.Message)...)
+		lines = append(lines, splitReportLines(test.Trace)...)
+		if len(test.Stdout) > 0 {
+			lines = append(lines, systemOut)
+			lines = append(lines, test.Stdout...)
+		}
+		if len(test.Stderr) > 0 {
+			lines = append(lines, systemErr)
+			lines = append(lines, test.Stderr...)
+		}
+
+		report.addCase(tc, lines...)
+	}
+
+	return report, nil
+}
+
+// ctrfStatus converts a CTRF test status to a test result status.
+func ctrfStatus(status string) string {
+	switch strings.ToLower(status) {
+	case "passed":
+		return evergreen.TestSucceededStatus
+	case "failed":
+		return evergreen.TestFailedStatus
+	case "skipped", "pending":
+		return evergreen.TestSkippedStatus
+	default:
+		return evergreen.TestSilentlyFailedStatus
+	}
+}
+
+// ctrfSuiteName returns the name of a test's suite, which may be either a
+// string or, in newer versions of the specification, a list of nested suite
+// names.
+func ctrfSuiteName(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+
+	var suite string
+	if err := json.Unmarshal(raw, &suite); err == nil {
+		return suite
+	}
+	var suites []string
+	if err := json.Unmarshal(raw, &suites); err == nil {
+		return strings.Join(suites, "/")
+	}
+
+	return ""
+}
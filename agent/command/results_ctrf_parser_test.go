@@ -0,0 +1,64 @@
+package command
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCTRFReport(t *testing.T) {
+	t.Run("Tests", func(t *testing.T) {
+		report, err := parseCTRFReport(strings.NewReader(`{
+  "reportFormat": "CTRF",
+  "results": {
+    "tool": {"name": "jest"},
+    "tests": [
+      {"name": "passes", "status": "passed", "duration": 100, "start": 1700000000000, "stop": 1700000000100, "suite": "math"},
+      {"name": "fails", "status": "failed", "duration": 20, "message": "expected 1\nreceived 2", "trace": "at add.js:3", "filePath": "add.test.js", "line": 3, "stdout": ["out"]},
+      {"name": "skipped", "status": "skipped", "message": "not supported", "suite": ["outer", "inner"]},
+      {"name": "pending", "status": "pending"},
+      {"name": "other", "status": "other"}
+    ]
+  }
+}`))
+		require.NoError(t, err)
+		require.Len(t, report.cases, 5)
+
+		assert.Equal(t, "math/passes", report.cases[0].name)
+		assert.Equal(t, evergreen.TestSucceededStatus, report.cases[0].status)
+		assert.Equal(t, 100*time.Millisecond, report.cases[0].duration)
+		assert.Equal(t, time.UnixMilli(1700000000000), report.cases[0].start)
+		assert.Equal(t, time.UnixMilli(1700000000100), report.cases[0].end)
+
+		assert.Equal(t, "fails", report.cases[1].name)
+		assert.Equal(t, evergreen.TestFailedStatus, report.cases[1].status)
+		assert.Equal(t, []string{
+			"=== fails (fail)",
+			"location: add.test.js:3",
+			"expected 1",
+			"received 2",
+			"at add.js:3",
+			systemOut,
+			"out",
+		}, report.logLines[report.cases[1].logLine:report.cases[2].logLine])
+
+		assert.Equal(t, "outer/inner/skipped", report.cases[2].name)
+		assert.Equal(t, evergreen.TestSkippedStatus, report.cases[2].status)
+		assert.Equal(t, "not supported", report.logLines[report.cases[2].logLine+1])
+
+		assert.Equal(t, evergreen.TestSkippedStatus, report.cases[3].status)
+		assert.Equal(t, evergreen.TestSilentlyFailedStatus, report.cases[4].status)
+	})
+	t.Run("UnrecognizedFormat", func(t *testing.T) {
+		_, err := parseCTRFReport(strings.NewReader(`{"reportFormat": "JUnit"}`))
+		assert.Error(t, err)
+	})
+	t.Run("InvalidJSON", func(t *testing.T) {
+		_, err := parseCTRFReport(strings.NewReader(`{`))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,229 @@
+package command
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/pkg/errors"
+)
+
+// pytestReport is a single line of a pytest report log, as written by
+// pytest's --report-log option.
+type pytestReport struct {
+	ReportType string `json:"$report_type"`
+	NodeID     string `json:"nodeid"`
+	// Outcome is one of "passed", "failed" or "skipped".
+	Outcome string `json:"outcome"`
+	// When is the test phase of the report, which is one of "setup",
+	// "call" or "teardown".
+	When     string          `json:"when"`
+	Longrepr json.RawMessage `json:"longrepr"`
+	// Duration is the duration of the phase in seconds.
+	Duration float64 `json:"duration"`
+	// Start and Stop are the Unix timestamps, in seconds, of when the phase
+	// started and stopped.
+	Start    float64    `json:"start"`
+	Stop     float64    `json:"stop"`
+	Sections [][]string `json:"sections"`
+	// WasXfail is the reason the test was expected to fail, if it was.
+	WasXfail *string `json:"wasxfail"`
+}
+
+// pytestLongrepr is the serialized representation of a failure.
+type pytestLongrepr struct {
+	ReprCrash *struct {
+		Path    string `json:"path"`
+		Lineno  int    `json:"lineno"`
+		Message string `json:"message"`
+	} `json:"reprcrash"`
+	ReprTraceback *struct {
+		ReprEntries []struct {
+			Data struct {
+				Lines []string `json:"lines"`
+			} `json:"data"`
+		} `json:"reprentries"`
+	} `json:"reprtraceback"`
+}
+
+// pytestTest accumulates the reports of the phases of a single test.
+type pytestTest struct {
+	nodeID      string
+	failed      bool
+	skipped     bool
+	duration    float64
+	start, stop float64
+	lines       []string
+	sections    [][]string
+}
+
+// parsePytestReportLog parses a pytest report log. The reports of the setup,
+// call and teardown phases of each test are combined into a single test
+// result, which fails if any phase failed. Collection errors are reported as
+// failed tests. Each test's log contains its skip reason or failure
+// representation and its captured output.
+func parsePytestReportLog(r io.Reader) (*testReport, error) {
+	var (
+		order   []string
+		tests   = map[string]*pytestTest{}
+		scanner = bufio.NewScanner(r)
+	)
+	getTest := func(nodeID string) *pytestTest {
+		test, ok := tests[nodeID]
+		if !ok {
+			test = &pytestTest{nodeID: nodeID}
+			tests[nodeID] = test
+			order = append(order, nodeID)
+		}
+		return test
+	}
+
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var report pytestReport
+		if err := json.Unmarshal([]byte(line), &report); err != nil {
+			return nil, errors.Wrapf(err, "decoding pytest report on line %d", lineNum)
+		}
+
+		switch report.ReportType {
+		case "TestReport":
+			getTest(report.NodeID).addPhase(report)
+		case "CollectReport":
+			if report.Outcome != "failed" {
+				continue
+			}
+			nodeID := report.NodeID
+			if nodeID == "" {
+				nodeID = "collection"
+			}
+			test := getTest(nodeID)
+			test.failed = true
+			test.lines = append(test.lines, "collection failed:")
+			test.lines = append(test.lines, pytestLongreprLines(report.Longrepr)...)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "reading pytest report log")
+	}
+
+	report := &testReport{}
+	for _, nodeID := range order {
+		test := tests[nodeID]
+		status := evergreen.TestSucceededStatus
+		switch {
+		case test.failed:
+			status = evergreen.TestFailedStatus
+		case test.skipped:
+			status = evergreen.TestSkippedStatus
+		}
+
+		tc := testReportCase{
+			name:     nodeID,
+			status:   status,
+			duration: time.Duration(test.duration * float64(time.Second)),
+		}
+		if test.start > 0 {
+			tc.start = pytestTime(test.start)
+			if test.stop >= test.start {
+				tc.end = pytestTime(test.stop)
+			}
+		}
+
+		lines := test.lines
+		for _, section := range test.sections {
+			if len(section) != 2 {
+				continue
+			}
+			lines = append(lines, "----- "+section[0]+" -----")
+			lines = append(lines, splitReportLines(section[1])...)
+		}
+		report.addCase(tc, lines...)
+	}
+
+	return report, nil
+}
+
+func (t *pytestTest) addPhase(report pytestReport) {
+	t.duration += report.Duration
+	if report.Start > 0 && (t.start == 0 || report.Start < t.start) {
+		t.start = report.Start
+	}
+	t.stop = math.Max(t.stop, report.Stop)
+	// Captured output accumulates across phases, so only the sections of
+	// the latest phase are kept.
+	if len(report.Sections) > 0 {
+		t.sections = report.Sections
+	}
+
+	switch report.Outcome {
+	case "failed":
+		t.failed = true
+		t.lines = append(t.lines, report.When+" failed:")
+		t.lines = append(t.lines, pytestLongreprLines(report.Longrepr)...)
+	case "skipped":
+		t.skipped = true
+		if report.WasXfail != nil {
+			t.lines = append(t.lines, "xfail: "+*report.WasXfail)
+		} else {
+			t.lines = append(t.lines, pytestLongreprLines(report.Longrepr)...)
+		}
+	case "passed":
+		if report.WasXfail != nil {
+			t.lines = append(t.lines, "xpass: "+*report.WasXfail)
+		}
+	}
+}
+
+// pytestLongreprLines returns the log lines of a serialized failure
+// representation, which is either null, a string, a skip location and
+// reason, or a failure with a traceback.
+func pytestLongreprLines(raw json.RawMessage) []string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return splitReportLines(text)
+	}
+
+	var skip []any
+	if err := json.Unmarshal(raw, &skip); err == nil {
+		if len(skip) == 3 {
+			return []string{fmt.Sprintf("%v:%v: %v", skip[0], skip[1], skip[2])}
+		}
+		return []string{fmt.Sprint(skip...)}
+	}
+
+	var longrepr pytestLongrepr
+	if err := json.Unmarshal(raw, &longrepr); err != nil {
+		return []string{string(raw)}
+	}
+	var lines []string
+	if longrepr.ReprTraceback != nil {
+		for _, entry := range longrepr.ReprTraceback.ReprEntries {
+			lines = append(lines, entry.Data.Lines...)
+		}
+	}
+	if crash := longrepr.ReprCrash; crash != nil {
+		lines = append(lines, fmt.Sprintf("%s:%d: %s", crash.Path, crash.Lineno, crash.Message))
+	}
+
+	return lines
+}
+
+// pytestTime converts a Unix timestamp in seconds to a time.
+func pytestTime(ts float64) time.Time {
+	sec, frac := math.Modf(ts)
+	return time.Unix(int64(sec), int64(frac*float64(time.Second)))
+}
@@ -0,0 +1,70 @@
+package command
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePytestReportLog(t *testing.T) {
+	t.Run("Tests", func(t *testing.T) {
+		report, err := parsePytestReportLog(strings.NewReader(`{"pytest_version": "8.3.0", "$report_type": "SessionStart"}
+{"nodeid": "", "outcome": "passed", "longrepr": null, "result": null, "sections": [], "$report_type": "CollectReport"}
+{"nodeid": "test_a.py::test_pass", "when": "setup", "outcome": "passed", "longrepr": null, "duration": 0.25, "start": 1700000000.0, "stop": 1700000000.25, "sections": [], "$report_type": "TestReport"}
+{"nodeid": "test_a.py::test_pass", "when": "call", "outcome": "passed", "longrepr": null, "duration": 0.5, "start": 1700000000.25, "stop": 1700000000.75, "sections": [["Captured stdout call", "hello\n"]], "$report_type": "TestReport"}
+{"nodeid": "test_a.py::test_pass", "when": "teardown", "outcome": "passed", "longrepr": null, "duration": 0.25, "start": 1700000000.75, "stop": 1700000001.0, "sections": [["Captured stdout call", "hello\n"]], "$report_type": "TestReport"}
+{"nodeid": "test_a.py::test_fail", "when": "call", "outcome": "failed", "longrepr": {"reprcrash": {"path": "test_a.py", "lineno": 7, "message": "AssertionError: assert 1 == 2"}, "reprtraceback": {"reprentries": [{"type": "ReprEntry", "data": {"lines": ["    def test_fail():", ">       assert 1 == 2"]}}]}}, "duration": 0.1, "sections": [], "$report_type": "TestReport"}
+{"nodeid": "test_a.py::test_skip", "when": "setup", "outcome": "skipped", "longrepr": ["test_a.py", 10, "Skipped: needs network"], "duration": 0.0, "sections": [], "$report_type": "TestReport"}
+{"nodeid": "test_a.py::test_xfail", "when": "call", "outcome": "skipped", "longrepr": null, "wasxfail": "known bug", "duration": 0.0, "sections": [], "$report_type": "TestReport"}
+{"nodeid": "test_b.py", "outcome": "failed", "longrepr": "ImportError: no module named b", "result": [], "sections": [], "$report_type": "CollectReport"}
+{"exitstatus": 1, "$report_type": "SessionFinish"}
+`))
+		require.NoError(t, err)
+		require.Len(t, report.cases, 5)
+
+		pass := report.cases[0]
+		assert.Equal(t, "test_a.py::test_pass", pass.name)
+		assert.Equal(t, evergreen.TestSucceededStatus, pass.status)
+		assert.Equal(t, time.Second, pass.duration)
+		assert.Equal(t, int64(1700000000), pass.start.Unix())
+		assert.Equal(t, int64(1700000001), pass.end.Unix())
+		assert.Equal(t, []string{
+			"=== test_a.py::test_pass (pass)",
+			"----- Captured stdout call -----",
+			"hello",
+		}, report.logLines[pass.logLine:report.cases[1].logLine])
+
+		fail := report.cases[1]
+		assert.Equal(t, evergreen.TestFailedStatus, fail.status)
+		assert.Equal(t, 100*time.Millisecond, fail.duration)
+		assert.True(t, fail.start.IsZero())
+		assert.Equal(t, []string{
+			"=== test_a.py::test_fail (fail)",
+			"call failed:",
+			"    def test_fail():",
+			">       assert 1 == 2",
+			"test_a.py:7: AssertionError: assert 1 == 2",
+		}, report.logLines[fail.logLine:report.cases[2].logLine])
+
+		skip := report.cases[2]
+		assert.Equal(t, evergreen.TestSkippedStatus, skip.status)
+		assert.Equal(t, "test_a.py:10: Skipped: needs network", report.logLines[skip.logLine+1])
+
+		xfail := report.cases[3]
+		assert.Equal(t, evergreen.TestSkippedStatus, xfail.status)
+		assert.Equal(t, "xfail: known bug", report.logLines[xfail.logLine+1])
+
+		collect := report.cases[4]
+		assert.Equal(t, "test_b.py", collect.name)
+		assert.Equal(t, evergreen.TestFailedStatus, collect.status)
+		assert.Equal(t, "ImportError: no module named b", report.logLines[collect.logLine+2])
+	})
+	t.Run("InvalidLine", func(t *testing.T) {
+		_, err := parsePytestReportLog(strings.NewReader("{\"$report_type\": \"TestReport\"}\nnot json\n"))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,240 @@
+package command
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/agent/internal"
+	"github.com/evergreen-ci/evergreen/agent/internal/client"
+	"github.com/evergreen-ci/evergreen/model/testlog"
+	"github.com/evergreen-ci/evergreen/model/testresult"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+)
+
+// testReportFormat describes a test report file format that can be parsed
+// into test results.
+type testReportFormat struct {
+	// commandName is the name of the command that parses the format.
+	commandName string
+	// parse parses a single report file.
+	parse func(io.Reader) (*testReport, error)
+}
+
+var (
+	tapReportFormat = testReportFormat{
+		commandName: "tap.parse_files",
+		parse:       parseTAPReport,
+	}
+	ctrfReportFormat = testReportFormat{
+		commandName: "ctrf.parse_files",
+		parse:       parseCTRFReport,
+	}
+	pytestReportFormat = testReportFormat{
+		commandName: "pytest.parse_files",
+		parse:       parsePytestReportLog,
+	}
+)
+
+// testReportResults parses files in a test report format, such as TAP, and
+// sends the test results and logs found in them to the server.
+type testReportResults struct {
+	// Files is a list of file patterns to parse, relative to the task's
+	// working directory.
+	Files []string `mapstructure:"files" plugin:"expand"`
+
+	// OptionalOutput, when set to true, causes this command to be skipped
+	// over without an error when no files are found to be parsed.
+	OptionalOutput   string `mapstructure:"optional_output" plugin:"expand"`
+	outputIsOptional bool
+
+	format testReportFormat
+	base
+}
+
+func tapResultsFactory() Command    { return &testReportResults{format: tapReportFormat} }
+func ctrfResultsFactory() Command   { return &testReportResults{format: ctrfReportFormat} }
+func pytestResultsFactory() Command { return &testReportResults{format: pytestReportFormat} }
+
+func (c *testReportResults) Name() string { return c.format.commandName }
+
+// ParseParams reads the specified map of parameters into the command and
+// validates that at least one file pattern is specified.
+func (c *testReportResults) ParseParams(params map[string]any) error {
+	var err error
+	if err = mapstructure.Decode(params, c); err != nil {
+		return errors.Wrap(err, "decoding mapstructure params")
+	}
+
+	if c.OptionalOutput != "" {
+		c.outputIsOptional, err = strconv.ParseBool(c.OptionalOutput)
+		if err != nil {
+			return errors.Wrap(err, "parsing optional output parameter as a boolean")
+		}
+	}
+
+	if len(c.Files) == 0 {
+		return errors.New("must specify at least one file pattern to parse")
+	}
+	return nil
+}
+
+// Execute parses the report files and sends the test results and logs found
+// in them to the server.
+func (c *testReportResults) Execute(ctx context.Context, comm client.Communicator, logger client.LoggerProducer, conf *internal.TaskConfig) error {
+	if err := util.ExpandValues(c, &conf.Expansions); err != nil {
+		return errors.Wrap(err, "applying expansions")
+	}
+
+	for i, file := range c.Files {
+		c.Files[i] = GetWorkingDirectory(conf, file)
+	}
+
+	reportFiles, err := globFiles(c.Files...)
+	if err != nil {
+		return errors.Wrap(err, "obtaining names of report files")
+	}
+	if len(reportFiles) == 0 {
+		if c.outputIsOptional {
+			return nil
+		}
+
+		return errors.New("no files found to be parsed")
+	}
+
+	var (
+		logs    []testlog.TestLog
+		results []testresult.TestResult
+	)
+	for _, reportFile := range reportFiles {
+		if err := ctx.Err(); err != nil {
+			return errors.Wrap(err, "canceled while processing report files")
+		}
+
+		report, err := c.parseFile(reportFile)
+		if err != nil {
+			logger.Task().Error(ctx, errors.Wrapf(err, "parsing file '%s'", reportFile))
+			continue
+		}
+
+		log, fileResults := report.toModel(conf, testReportLogName(reportFile))
+		logs = append(logs, log)
+		results = append(results, fileResults...)
+	}
+
+	if !conf.Task.MustHaveResults && len(results) == 0 {
+		return nil
+	}
+
+	return errors.Wrap(sendTestLogsAndResults(ctx, comm, logger, conf, logs, results), "sending test logs and test results")
+}
+
+func (c *testReportResults) parseFile(path string) (*testReport, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "opening file")
+	}
+	defer f.Close()
+
+	report, err := c.format.parse(f)
+	if err != nil {
+		return nil, err
+	}
+	if len(report.cases) == 0 {
+		return nil, errors.New("no results found")
+	}
+
+	return report, nil
+}
+
+// testReportLogName returns the name of the test log for a report file, which
+// is the file's name without its extension.
+func testReportLogName(path string) string {
+	name := filepath.Base(path)
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// testReport is a format-agnostic test report parsed from a single file.
+type testReport struct {
+	cases []testReportCase
+	// logLines are the lines of the report's test log. Each test case
+	// points to the range of lines belonging to it.
+	logLines []string
+}
+
+// testReportCase is a single test case of a test report.
+type testReportCase struct {
+	name   string
+	status string
+	// start and end are the times the test case ran, if reported. If
+	// start is not reported, the test cases are laid out back to back
+	// using their durations.
+	start, end time.Time
+	duration   time.Duration
+	// logLine is the 0-based index of the first line of the test case in
+	// the report's test log.
+	logLine int
+}
+
+// addCase adds a test case to the report along with its log lines, which are
+// appended to the report's test log under a header naming the test case.
+func (r *testReport) addCase(tc testReportCase, lines ...string) {
+	tc.logLine = len(r.logLines)
+	r.logLines = append(r.logLines, "=== "+tc.name+" ("+tc.status+")")
+	r.logLines = append(r.logLines, lines...)
+	r.cases = append(r.cases, tc)
+}
+
+// toModel returns the test log and test results of the report.
+func (r *testReport) toModel(conf *internal.TaskConfig, logName string) (testlog.TestLog, []testresult.TestResult) {
+	log := testlog.TestLog{
+		Name:          logName,
+		Task:          conf.Task.Id,
+		TaskExecution: conf.Task.Execution,
+		Lines:         r.logLines,
+	}
+
+	cursor := time.Now()
+	results := make([]testresult.TestResult, 0, len(r.cases))
+	for _, tc := range r.cases {
+		start, end := tc.start, tc.end
+		if start.IsZero() {
+			start = cursor
+			end = time.Time{}
+		}
+		if end.IsZero() {
+			end = start.Add(tc.duration)
+		}
+		cursor = end
+
+		results = append(results, testresult.TestResult{
+			TestName:      tc.name,
+			Status:        tc.status,
+			TestStartTime: start,
+			TestEndTime:   end,
+			LogInfo: &testresult.TestLogInfo{
+				LogName: logName,
+				LineNum: int32(tc.logLine),
+			},
+		})
+	}
+
+	return log, results
+}
+
+// splitReportLines splits multi-line text from a report into log lines,
+// dropping trailing empty lines.
+func splitReportLines(text string) []string {
+	text = strings.TrimRight(text, "\r\n")
+	if text == "" {
+		return nil
+	}
+
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
@@ -0,0 +1,67 @@
+package command
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/agent/internal"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestReportResultsParseParams(t *testing.T) {
+	t.Run("RequiresFiles", func(t *testing.T) {
+		cmd := tapResultsFactory()
+		assert.Error(t, cmd.ParseParams(map[string]any{}))
+	})
+	t.Run("ParsesOptionalOutput", func(t *testing.T) {
+		cmd := pytestResultsFactory().(*testReportResults)
+		require.NoError(t, cmd.ParseParams(map[string]any{
+			"files":           []string{"report.jsonl"},
+			"optional_output": "true",
+		}))
+		assert.True(t, cmd.outputIsOptional)
+		assert.Equal(t, "pytest.parse_files", cmd.Name())
+	})
+	t.Run("FailsWithInvalidOptionalOutput", func(t *testing.T) {
+		cmd := ctrfResultsFactory()
+		assert.Error(t, cmd.ParseParams(map[string]any{
+			"files":           []string{"report.json"},
+			"optional_output": "maybe",
+		}))
+	})
+}
+
+func TestTestReportToModel(t *testing.T) {
+	conf := &internal.TaskConfig{Task: task.Task{Id: "task", Execution: 1}}
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	report := &testReport{}
+	report.addCase(testReportCase{name: "first", status: evergreen.TestSucceededStatus, start: start, duration: time.Second})
+	report.addCase(testReportCase{name: "second", status: evergreen.TestSkippedStatus, duration: time.Minute}, "skip reason")
+	report.addCase(testReportCase{name: "third", status: evergreen.TestFailedStatus, duration: time.Second})
+
+	log, results := report.toModel(conf, "report")
+	assert.Equal(t, "report", log.Name)
+	assert.Equal(t, "task", log.Task)
+	assert.Equal(t, 1, log.TaskExecution)
+	assert.Equal(t, []string{"=== first (pass)", "=== second (skip)", "skip reason", "=== third (fail)"}, log.Lines)
+
+	require.Len(t, results, 3)
+	assert.Equal(t, start, results[0].TestStartTime)
+	assert.Equal(t, start.Add(time.Second), results[0].TestEndTime)
+	assert.Equal(t, time.Minute, results[1].TestEndTime.Sub(results[1].TestStartTime))
+	assert.Equal(t, results[1].TestEndTime, results[2].TestStartTime)
+	for i, lineNum := range []int32{0, 1, 3} {
+		require.NotNil(t, results[i].LogInfo)
+		assert.Equal(t, "report", results[i].LogInfo.LogName)
+		assert.Equal(t, lineNum, results[i].LogInfo.LineNum)
+	}
+}
+
+func TestTestReportLogName(t *testing.T) {
+	assert.Equal(t, "results", testReportLogName("/path/to/results.tap"))
+	assert.Equal(t, "report.log", testReportLogName("report.log.jsonl"))
+}
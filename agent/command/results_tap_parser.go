@@ -0,0 +1,187 @@
+package command
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/pkg/errors"
+)
+
+var (
+	// tapTestPointRegex matches a TAP test point, capturing whether it
+	// failed, its number, and the rest of the line.
+	tapTestPointRegex = regexp.MustCompile(`^(not )?ok\b(?:\s+(\d+))?\s*(.*)$`)
+	// tapPlanRegex matches a TAP plan.
+	tapPlanRegex = regexp.MustCompile(`^\d+\.\.\d+`)
+	// tapDurationRegex matches the durations reported in YAML diagnostics.
+	tapDurationRegex = regexp.MustCompile(`^duration_ms:\s*([0-9.]+)\s*$`)
+	// tapTimeDirectiveRegex matches the time reported in the directive of a
+	// test point by some producers, such as node-tap.
+	tapTimeDirectiveRegex = regexp.MustCompile(`(?i)\btime=([0-9.]+)(ms|s)?\b`)
+)
+
+const tapBailOut = "Bail out!"
+
+// parseTAPReport parses a Test Anything Protocol version 13 or 14 report. The
+// test log of the report is the report itself, and each test case points to
+// its test point. Subtests are named after their parent subtests, separated by
+// a slash. Durations are read from the YAML diagnostics of each test point.
+func parseTAPReport(r io.Reader) (*testReport, error) {
+	report := &testReport{}
+
+	var (
+		subtests        = map[int]string{}
+		pendingSubtests []string
+		last            = -1
+		lastIndent      = 0
+		inYAML          bool
+		bailedOut       bool
+		scanner         = bufio.NewScanner(r)
+		indentWidth     = 4
+	)
+	// Subtest comments precede the lines of the subtests they name, which
+	// are indented one level deeper than their parents. Depending on the
+	// producer, the comments are indented either at the level of the parent
+	// or of the subtest itself, so the pending names are assigned innermost
+	// first to the depth of the first line following them.
+	nameSubtests := func(depth int) {
+		for i := len(pendingSubtests) - 1; i >= 0 && depth > 0; i-- {
+			subtests[depth] = pendingSubtests[i]
+			depth--
+		}
+		pendingSubtests = nil
+	}
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		raw := scanner.Text()
+		lineNum := len(report.logLines)
+		report.logLines = append(report.logLines, raw)
+		if bailedOut {
+			continue
+		}
+
+		trimmed := strings.TrimLeft(raw, " \t")
+		indent := len(raw) - len(trimmed)
+		trimmed = strings.TrimRight(trimmed, " \t\r")
+
+		if inYAML {
+			switch {
+			case trimmed == "..." && indent <= lastIndent+2:
+				inYAML = false
+			case last >= 0:
+				if match := tapDurationRegex.FindStringSubmatch(trimmed); match != nil {
+					if ms, err := strconv.ParseFloat(match[1], 64); err == nil {
+						report.cases[last].duration = time.Duration(ms * float64(time.Millisecond))
+					}
+				}
+			}
+			continue
+		}
+
+		switch {
+		case trimmed == "---" && last >= 0 && indent > lastIndent:
+			inYAML = true
+		case strings.HasPrefix(trimmed, "# Subtest"):
+			pendingSubtests = append(pendingSubtests, strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(trimmed, "# Subtest"), ":")))
+		case strings.HasPrefix(trimmed, tapBailOut):
+			// The remaining tests did not run, so fail the report.
+			bailedOut = true
+			report.cases = append(report.cases, testReportCase{
+				name:    tapBailOut,
+				status:  evergreen.TestFailedStatus,
+				logLine: lineNum,
+			})
+		case tapPlanRegex.MatchString(trimmed):
+			nameSubtests(indent / indentWidth)
+		default:
+			match := tapTestPointRegex.FindStringSubmatch(trimmed)
+			if match == nil {
+				continue
+			}
+
+			depth := indent / indentWidth
+			nameSubtests(depth)
+			tc := parseTAPTestPoint(match[1] != "", match[2], match[3])
+			var prefix []string
+			for d := 1; d <= depth; d++ {
+				if name, ok := subtests[d]; ok && name != "" {
+					prefix = append(prefix, name)
+				}
+			}
+			if len(prefix) > 0 {
+				tc.name = strings.Join(append(prefix, tc.name), "/")
+			}
+			for d := range subtests {
+				if d > depth {
+					delete(subtests, d)
+				}
+			}
+			tc.logLine = lineNum
+			report.cases = append(report.cases, tc)
+			last = len(report.cases) - 1
+			lastIndent = indent
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "reading TAP report")
+	}
+
+	return report, nil
+}
+
+// parseTAPTestPoint parses the parts of a test point following its number.
+func parseTAPTestPoint(failed bool, number, rest string) testReportCase {
+	description, directive := splitTAPDirective(rest)
+	description = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(description), "-"))
+	description = strings.ReplaceAll(description, `\#`, "#")
+	if description == "" {
+		description = "test " + number
+	}
+
+	status := evergreen.TestSucceededStatus
+	if failed {
+		status = evergreen.TestFailedStatus
+	}
+	lowerDirective := strings.ToLower(directive)
+	switch {
+	case strings.HasPrefix(lowerDirective, "skip"):
+		status = evergreen.TestSkippedStatus
+	case strings.HasPrefix(lowerDirective, "todo") && failed:
+		// Failing TODO tests are expected to fail, so they do not
+		// count as failures.
+		status = evergreen.TestSkippedStatus
+	}
+
+	tc := testReportCase{name: description, status: status}
+	if match := tapTimeDirectiveRegex.FindStringSubmatch(directive); match != nil {
+		if value, err := strconv.ParseFloat(match[1], 64); err == nil {
+			unit := time.Millisecond
+			if strings.EqualFold(match[2], "s") {
+				unit = time.Second
+			}
+			tc.duration = time.Duration(value * float64(unit))
+		}
+	}
+
+	return tc
+}
+
+// splitTAPDirective splits a test point's description from its directive,
+// which follows the first unescaped '#'.
+func splitTAPDirective(s string) (string, string) {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '#':
+			return s[:i], strings.TrimSpace(s[i+1:])
+		}
+	}
+
+	return s, ""
+}
@@ -0,0 +1,107 @@
+package command
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTAPReport(t *testing.T) {
+	t.Run("TestPoints", func(t *testing.T) {
+		report, err := parseTAPReport(strings.NewReader(`TAP version 13
+1..5
+ok 1 - passes
+not ok 2 - fails
+  ---
+  duration_ms: 12.5
+  message: 'expected 1 to equal 2'
+  ...
+ok 3 - is skipped # SKIP not supported on this platform
+not ok 4 - is not done # TODO implement
+ok 5 # time=250ms
+`))
+		require.NoError(t, err)
+		require.Len(t, report.cases, 5)
+
+		assert.Equal(t, "passes", report.cases[0].name)
+		assert.Equal(t, evergreen.TestSucceededStatus, report.cases[0].status)
+		assert.Equal(t, 2, report.cases[0].logLine)
+
+		assert.Equal(t, "fails", report.cases[1].name)
+		assert.Equal(t, evergreen.TestFailedStatus, report.cases[1].status)
+		assert.Equal(t, 12500*time.Microsecond, report.cases[1].duration)
+		assert.Equal(t, 3, report.cases[1].logLine)
+
+		assert.Equal(t, "is skipped", report.cases[2].name)
+		assert.Equal(t, evergreen.TestSkippedStatus, report.cases[2].status)
+		assert.Equal(t, 8, report.cases[2].logLine)
+		assert.Equal(t, "ok 3 - is skipped # SKIP not supported on this platform", report.logLines[report.cases[2].logLine])
+
+		assert.Equal(t, "is not done", report.cases[3].name)
+		assert.Equal(t, evergreen.TestSkippedStatus, report.cases[3].status)
+
+		assert.Equal(t, "test 5", report.cases[4].name)
+		assert.Equal(t, 250*time.Millisecond, report.cases[4].duration)
+	})
+	t.Run("Subtests", func(t *testing.T) {
+		report, err := parseTAPReport(strings.NewReader(`TAP version 14
+# Subtest: parent
+    # Subtest: child
+        ok 1 - leaf
+        1..1
+    ok 1 - child
+    not ok 2 - other
+    1..2
+not ok 1 - parent
+1..1
+`))
+		require.NoError(t, err)
+		require.Len(t, report.cases, 4)
+		assert.Equal(t, "parent/child/leaf", report.cases[0].name)
+		assert.Equal(t, "parent/child", report.cases[1].name)
+		assert.Equal(t, "parent/other", report.cases[2].name)
+		assert.Equal(t, evergreen.TestFailedStatus, report.cases[2].status)
+		assert.Equal(t, "parent", report.cases[3].name)
+	})
+	t.Run("SubtestsIndentedWithChildren", func(t *testing.T) {
+		report, err := parseTAPReport(strings.NewReader(`TAP version 13
+    # Subtest: parent
+        # Subtest: child
+        ok 1 - leaf
+        1..1
+    ok 1 - child
+    1..1
+ok 1 - parent
+1..1
+`))
+		require.NoError(t, err)
+		require.Len(t, report.cases, 3)
+		assert.Equal(t, "parent/child/leaf", report.cases[0].name)
+		assert.Equal(t, "parent/child", report.cases[1].name)
+		assert.Equal(t, "parent", report.cases[2].name)
+	})
+	t.Run("BailOut", func(t *testing.T) {
+		report, err := parseTAPReport(strings.NewReader(`1..3
+ok 1 - first
+Bail out! database is down
+ok 2 - ignored
+`))
+		require.NoError(t, err)
+		require.Len(t, report.cases, 2)
+		assert.Equal(t, tapBailOut, report.cases[1].name)
+		assert.Equal(t, evergreen.TestFailedStatus, report.cases[1].status)
+		assert.Equal(t, 2, report.cases[1].logLine)
+		assert.Len(t, report.logLines, 4)
+	})
+	t.Run("EscapedHash", func(t *testing.T) {
+		report, err := parseTAPReport(strings.NewReader(`ok 1 - handles \# in names # SKIP reason`))
+		require.NoError(t, err)
+		require.Len(t, report.cases, 1)
+		assert.Equal(t, "handles # in names", report.cases[0].name)
+		assert.Equal(t, evergreen.TestSkippedStatus, report.cases[0].status)
+	})
+}
@@ -29,6 +29,15 @@ func sendTestResults(ctx context.Context, comm client.Communicator, logger clien
 	}
 
 	logger.Task().Info(ctx, "Attaching test results...")
+
+	if conf.LocalStorage != nil {
+		if err := attachLocalTestResults(conf, results); err != nil {
+			return errors.Wrap(err, "attaching test results locally")
+		}
+		logger.Task().Info(ctx, "Successfully attached results locally.")
+		return nil
+	}
+
 	td := client.TaskData{ID: conf.Task.Id, Secret: conf.Task.Secret}
 
 	if err := attachTestResults(ctx, conf, td, comm, results); err != nil {
@@ -47,6 +56,10 @@ func sendTestLogsAndResults(ctx context.Context, comm client.Communicator, logge
 	if len(logs) == 0 {
 		return sendTestResults(ctx, comm, logger, conf, results)
 	}
+	if conf.LocalStorage != nil {
+		logger.Task().Infof(ctx, "Skipping upload of %d test logs because test logs are not supported in local execution.", len(logs))
+		return sendTestResults(ctx, comm, logger, conf, results)
+	}
 
 	logger.Task().Info(ctx, "Posting test logs...")
 
@@ -94,6 +107,22 @@ func attachTestResults(ctx context.Context, conf *internal.TaskConfig, td client
 	}
 }
 
+// attachLocalTestResults records the test results in the task's local storage
+// instead of sending them to the backend results service.
+func attachLocalTestResults(conf *internal.TaskConfig, results []testresult.TestResult) error {
+	if err := conf.LocalStorage.AttachTestResults(results); err != nil {
+		return err
+	}
+	conf.HasTestResults = true
+	for _, result := range results {
+		if result.Status == evergreen.TestFailedStatus {
+			conf.HasFailingTestResult = true
+			break
+		}
+	}
+	return nil
+}
+
 const (
 	maxTestResultsInterval   = 24 * time.Hour
 	failedTestsSampleSize    = 10
@@ -237,6 +237,16 @@ func (c *xunitResults) parseAndUploadResults(ctx context.Context, conf *internal
 		return errors.New("all given file paths do not exist or are directories")
 	}
 
+	if conf.LocalStorage != nil {
+		if len(cumulative.logs) > 0 {
+			logger.Task().Infof(ctx, "Skipping upload of %d test logs because test logs are not supported in local execution.", len(cumulative.logs))
+		}
+		if len(cumulative.tests) > 0 {
+			return sendTestResults(ctx, comm, logger, conf, cumulative.tests)
+		}
+		return nil
+	}
+
 	// Upload test logs in parallel using a worker pool.
 	type indexedLog struct {
 		idx int
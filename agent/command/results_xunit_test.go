@@ -3,6 +3,7 @@ package command
 import (
 	"context"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -241,3 +242,37 @@ func TestXUnitParseAndUpload(t *testing.T) {
 		})
 	}
 }
+
+func TestXUnitParseAndUploadLocal(t *testing.T) {
+	storage := &taskOutputTestStorage{dir: t.TempDir()}
+	conf := &internal.TaskConfig{
+		Task:            task.Task{Id: "id", Secret: "secret"},
+		DisplayTaskInfo: &apimodels.DisplayTaskInfo{},
+		WorkDir:         filepath.Join(testutil.GetDirectoryOfFile(), "testdata", "xunit"),
+		NewExpansions:   &util.DynamicExpansions{},
+		LocalStorage:    storage,
+	}
+	comm := client.NewMock("url")
+	logger, err := comm.GetLoggerProducer(t.Context(), &conf.Task, nil)
+	require.NoError(t, err)
+
+	xr := xunitResults{
+		Files: []string{filepath.Join(conf.WorkDir, "junit*.xml")},
+	}
+	require.NoError(t, xr.parseAndUploadResults(t.Context(), conf, logger, comm))
+	assert.NoError(t, logger.Close())
+
+	assert.Len(t, storage.results, 683)
+	assert.True(t, conf.HasTestResults)
+	assert.True(t, conf.HasFailingTestResult)
+	assert.Empty(t, comm.FailedTestSample)
+
+	var skippedLogs bool
+	for _, line := range comm.GetTaskLogs(conf.Task.Id) {
+		assert.NotContains(t, line.Data, "panic")
+		if strings.Contains(line.Data, "Skipping upload") {
+			skippedLogs = true
+		}
+	}
+	assert.True(t, skippedLogs, "local execution should skip uploading test logs")
+}
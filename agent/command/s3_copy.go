@@ -230,6 +230,13 @@ func (c *s3copy) copyWithRetry(ctx context.Context,
 			return errors.Wrap(err, "command was cancelled")
 		}
 
+		if conf.LocalStorage != nil {
+			if err := c.copyLocal(ctx, comm, logger, conf, td, s3CopyFile); err != nil {
+				return err
+			}
+			continue
+		}
+
 		logger.Execution().Infof(ctx, "Making API push copy call to "+
 			"transfer %v/%v => %v/%v", s3CopyFile.Source.Bucket,
 			s3CopyFile.Source.Path, s3CopyFile.Destination.Bucket,
@@ -330,7 +337,7 @@ func (c *s3copy) copyWithRetry(ctx context.Context,
 					if err := comm.UpdatePushStatus(ctx, td, newPushLog); err != nil {
 						return errors.Wrap(err, "updating push log status to success for task")
 					}
-					if err = c.attachFiles(ctx, comm, logger, td, s3CopyReq); err != nil {
+					if err = c.attachFiles(ctx, comm, logger, conf, td, s3CopyReq); err != nil {
 						return errors.Wrap(err, "attaching files")
 					}
 					break retryLoop
@@ -344,9 +351,55 @@ func (c *s3copy) copyWithRetry(ctx context.Context,
 	return nil
 }
 
+// copyLocal copies the file between buckets in the task's local storage. Push
+// logs are not recorded since they only exist in the backend.
+func (c *s3copy) copyLocal(ctx context.Context, comm client.Communicator,
+	logger client.LoggerProducer, conf *internal.TaskConfig, td client.TaskData, s3CopyFile *s3CopyFile) error {
+
+	logger.Execution().Infof(ctx, "Copying %v/%v => %v/%v in local storage.", s3CopyFile.Source.Bucket,
+		s3CopyFile.Source.Path, s3CopyFile.Destination.Bucket, s3CopyFile.Destination.Path)
+
+	srcBucket, err := conf.LocalStorage.Bucket(s3CopyFile.Source.Bucket)
+	if err != nil {
+		return errors.Wrap(err, "getting local source bucket")
+	}
+	destBucket, err := conf.LocalStorage.Bucket(s3CopyFile.Destination.Bucket)
+	if err != nil {
+		return errors.Wrap(err, "getting local destination bucket")
+	}
+
+	copyOpts := pail.CopyOptions{
+		SourceKey:         s3CopyFile.Source.Path,
+		DestinationKey:    s3CopyFile.Destination.Path,
+		DestinationBucket: destBucket,
+	}
+	if err = srcBucket.Copy(ctx, copyOpts); err != nil {
+		if s3CopyFile.Optional {
+			logger.Execution().Errorf(ctx, "S3copy.copy failed to copy '%s' to '%s' in local storage and file is optional, continuing: %s",
+				s3CopyFile.Source.Path, s3CopyFile.Destination.Bucket, err)
+			return nil
+		}
+		return errors.Wrapf(err, "S3copy.copy failed to copy '%s' to '%s' in local storage", s3CopyFile.Source.Path, s3CopyFile.Destination.Bucket)
+	}
+
+	s3CopyReq := apimodels.S3CopyRequest{
+		S3SourceBucket:      s3CopyFile.Source.Bucket,
+		S3SourcePath:        s3CopyFile.Source.Path,
+		S3DestinationBucket: s3CopyFile.Destination.Bucket,
+		S3DestinationPath:   s3CopyFile.Destination.Path,
+		S3DisplayName:       s3CopyFile.DisplayName,
+	}
+	if err = c.attachFiles(ctx, comm, logger, conf, td, s3CopyReq); err != nil {
+		return errors.Wrap(err, "attaching files")
+	}
+
+	logger.Task().Infof(ctx, "Successfully copied source file '%s' to destination path '%s' in local storage.", s3CopyFile.Source.Path, s3CopyFile.Destination.Path)
+	return nil
+}
+
 // attachFiles is responsible for sending the specified file to the API Server.
 func (c *s3copy) attachFiles(ctx context.Context, comm client.Communicator,
-	logger client.LoggerProducer, td client.TaskData, request apimodels.S3CopyRequest) error {
+	logger client.LoggerProducer, conf *internal.TaskConfig, td client.TaskData, request apimodels.S3CopyRequest) error {
 
 	remotePath := filepath.ToSlash(request.S3DestinationPath)
 	fileLink := agentutil.S3DefaultURL(request.S3DestinationBucket, remotePath)
@@ -362,7 +415,7 @@ func (c *s3copy) attachFiles(ctx context.Context, comm client.Communicator,
 		FileKey: remotePath,
 	}
 	files := []*artifact.File{&file}
-	if err := comm.AttachFiles(ctx, td, files); err != nil {
+	if err := attachTaskFiles(ctx, comm, conf, td, files); err != nil {
 		return errors.Wrapf(err, "attaching file '%s'", displayName)
 	}
 	logger.Execution().Infof(ctx, "Successfully attached file '%s'.", displayName)
@@ -217,12 +217,20 @@ func (c *s3get) Execute(ctx context.Context, comm client.Communicator, logger cl
 		attribute.String(s3GetAssumeRoleARN, c.assumedRoleARN),
 	)
 
+	if conf.LocalStorage != nil {
+		if err := c.useLocalBucketIfExists(ctx, conf, logger); err != nil {
+			return errors.Wrap(err, "checking local storage")
+		}
+	}
+
 	// create pail bucket
 	httpClient := utility.GetHTTPClient()
 	httpClient.Timeout = s3HTTPClientTimeout
 	defer utility.PutHTTPClient(httpClient)
-	if err := c.createPailBucket(ctx, comm, httpClient); err != nil {
-		return errors.Wrap(err, "creating S3 bucket")
+	if c.bucket == nil {
+		if err := c.createPailBucket(ctx, comm, httpClient); err != nil {
+			return errors.Wrap(err, "creating S3 bucket")
+		}
 	}
 
 	if err := c.bucket.Check(ctx); err != nil {
@@ -379,6 +387,27 @@ func (c *s3get) fetchAndExtractTarball(ctx context.Context, f *os.File) error {
 	return nil
 }
 
+// useLocalBucketIfExists fetches the remote file from local storage if it was
+// previously uploaded there. Otherwise, the file is fetched from S3 as usual.
+func (c *s3get) useLocalBucketIfExists(ctx context.Context, conf *internal.TaskConfig, logger client.LoggerProducer) error {
+	bucket, err := conf.LocalStorage.Bucket(c.Bucket)
+	if err != nil {
+		return errors.Wrapf(err, "getting local bucket '%s'", c.Bucket)
+	}
+	exists, err := bucket.Exists(ctx, c.RemoteFile)
+	if err != nil {
+		return errors.Wrapf(err, "checking if remote file '%s' exists in local bucket '%s'", c.RemoteFile, c.Bucket)
+	}
+	if !exists {
+		logger.Task().Infof(ctx, "Remote file '%s' was not found in local storage, fetching it from S3 bucket '%s'.", c.RemoteFile, c.Bucket)
+		return nil
+	}
+
+	logger.Task().Infof(ctx, "Fetching remote file '%s' from local storage.", c.RemoteFile)
+	c.bucket = bucket
+	return nil
+}
+
 func (c *s3get) createPailBucket(ctx context.Context, comm client.Communicator, httpClient *http.Client) error {
 	opts := pail.S3Options{
 		Region:                 c.Region,
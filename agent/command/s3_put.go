@@ -398,6 +398,14 @@ func (s3pc *s3put) Execute(ctx context.Context, comm client.Communicator, logger
 		attribute.String(s3PutAssumeRoleARN, s3pc.assumedRoleARN),
 	)
 
+	if conf.LocalStorage != nil {
+		bucket, err := conf.LocalStorage.Bucket(s3pc.Bucket)
+		if err != nil {
+			return errors.Wrapf(err, "getting local bucket '%s'", s3pc.Bucket)
+		}
+		s3pc.bucket = bucket
+	}
+
 	// create pail bucket
 	httpClient := utility.GetHTTPClient()
 	httpClient.Timeout = s3HTTPClientTimeout
@@ -610,7 +618,7 @@ retryLoop:
 		DevprodOwnedAWSAccountIDs: conf.DevprodOwnedAWSAccountIDs,
 	})
 
-	err = errors.WithStack(s3pc.attachFiles(ctx, comm, uploadedFiles))
+	err = errors.WithStack(s3pc.attachFiles(ctx, comm, conf, uploadedFiles))
 	if err != nil {
 		return err
 	}
@@ -629,7 +637,7 @@ retryLoop:
 
 // attachTaskFiles is responsible for sending the
 // specified file to the API Server. Does not support multiple file putting.
-func (s3pc *s3put) attachFiles(ctx context.Context, comm client.Communicator, uploadedFiles []s3usage.FileMetrics) error {
+func (s3pc *s3put) attachFiles(ctx context.Context, comm client.Communicator, conf *internal.TaskConfig, uploadedFiles []s3usage.FileMetrics) error {
 	files := []*artifact.File{}
 
 	for _, uploadInfo := range uploadedFiles {
@@ -674,7 +682,7 @@ func (s3pc *s3put) attachFiles(ctx context.Context, comm client.Communicator, up
 		})
 	}
 
-	err := comm.AttachFiles(ctx, s3pc.taskData, files)
+	err := attachTaskFiles(ctx, comm, conf, s3pc.taskData, files)
 	if err != nil {
 		return errors.Wrap(err, "attaching files")
 	}
@@ -432,7 +432,7 @@ func TestSignedUrlVisibility(t *testing.T) {
 			},
 		}
 
-		require.NoError(t, s.attachFiles(ctx, comm, uploadedFiles))
+		require.NoError(t, s.attachFiles(ctx, comm, &internal.TaskConfig{}, uploadedFiles))
 
 		attachedFiles := comm.AttachedFiles
 		if v, found := attachedFiles[""]; found {
@@ -502,7 +502,7 @@ func TestContentTypeSaved(t *testing.T) {
 		},
 	}
 
-	require.NoError(t, s.attachFiles(ctx, comm, uploadedFiles))
+	require.NoError(t, s.attachFiles(ctx, comm, &internal.TaskConfig{}, uploadedFiles))
 
 	attachedFiles := comm.AttachedFiles
 	files, ok := attachedFiles[conf.Task.Id]
@@ -975,7 +975,7 @@ func TestS3PutWithAssociatedLinks(t *testing.T) {
 		},
 	}
 
-	require.NoError(t, s.attachFiles(ctx, comm, uploadedFiles))
+	require.NoError(t, s.attachFiles(ctx, comm, &internal.TaskConfig{}, uploadedFiles))
 
 	attachedFiles := comm.AttachedFiles
 	files, ok := attachedFiles[conf.Task.Id]
@@ -1123,7 +1123,7 @@ func TestAttachFilesRecordsCredentialVarNames(t *testing.T) {
 		require.NoError(t, cmd.expandParams(conf))
 		comm := client.NewMock("http://localhost.com")
 		cmd.taskData = client.TaskData{ID: "task", Secret: "secret"}
-		require.NoError(t, cmd.attachFiles(t.Context(), comm, []s3usage.FileMetrics{{
+		require.NoError(t, cmd.attachFiles(t.Context(), comm, conf, []s3usage.FileMetrics{{
 			LocalPath:  "local_file",
 			RemotePath: "remote/file",
 		}}))
@@ -0,0 +1,96 @@
+package command
+
+import (
+	"context"
+	"mime"
+	"path"
+	"path/filepath"
+
+	"github.com/evergreen-ci/evergreen/agent/internal"
+	"github.com/evergreen-ci/evergreen/agent/internal/client"
+	"github.com/evergreen-ci/evergreen/agent/internal/taskoutput"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+)
+
+const defaultArtifactContentType = "application/octet-stream"
+
+// IngestTestResultFiles parses and attaches the test result files written to
+// the task output directory using the same logic as the command that
+// attaches that format of results.
+func IngestTestResultFiles(ctx context.Context, comm client.Communicator, logger client.LoggerProducer, conf *internal.TaskConfig, format taskoutput.TestResultsFormat, files []string) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	switch format {
+	case taskoutput.TestResultsFormatXUnit:
+		// attach.xunit_results only accepts paths within the working
+		// directory.
+		relFiles := make([]string, 0, len(files))
+		for _, file := range files {
+			relFile, err := filepath.Rel(conf.WorkDir, file)
+			if err != nil {
+				return errors.Wrapf(err, "getting path of xunit results file '%s' relative to the working directory", file)
+			}
+			relFiles = append(relFiles, relFile)
+		}
+		cmd := &xunitResults{Files: relFiles}
+		return errors.Wrap(cmd.Execute(ctx, comm, logger, conf), "attaching xunit results")
+	case taskoutput.TestResultsFormatGoTest:
+		cmd := &goTestResults{Files: files}
+		return errors.Wrap(cmd.Execute(ctx, comm, logger, conf), "parsing go test results")
+	case taskoutput.TestResultsFormatEvergreen:
+		catcher := grip.NewBasicCatcher()
+		for _, file := range files {
+			cmd := &attachResults{FileLoc: file}
+			catcher.Wrapf(cmd.Execute(ctx, comm, logger, conf), "attaching results file '%s'", file)
+		}
+		return catcher.Resolve()
+	default:
+		return errors.Errorf("unrecognized test results format '%s'", format)
+	}
+}
+
+// UploadArtifactFiles uploads each file written to the task output directory
+// to S3 as described by the spec and attaches it to the task, using the same
+// logic as s3.put. The files keep their paths relative to the directory, both
+// in the bucket and as their display names.
+func UploadArtifactFiles(ctx context.Context, comm client.Communicator, logger client.LoggerProducer, conf *internal.TaskConfig, spec taskoutput.ArtifactsSpec, files []taskoutput.ArtifactFile) error {
+	catcher := grip.NewBasicCatcher()
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			catcher.Wrapf(err, "uploading artifact '%s'", file.Name)
+			break
+		}
+
+		contentType := spec.ContentType
+		if contentType == "" {
+			contentType = mime.TypeByExtension(filepath.Ext(file.Path))
+		}
+		if contentType == "" {
+			contentType = defaultArtifactContentType
+		}
+
+		cmd := &s3put{
+			AwsKey:              spec.AWSKey,
+			AwsSecret:           spec.AWSSecret,
+			AwsSessionToken:     spec.AWSSessionToken,
+			RoleARN:             spec.RoleARN,
+			LocalFile:           file.Path,
+			RemoteFile:          path.Join(spec.RemotePrefix, file.Name),
+			Region:              spec.Region,
+			Bucket:              spec.Bucket,
+			Permissions:         spec.Permissions,
+			ContentType:         contentType,
+			ResourceDisplayName: file.Name,
+			Visibility:          spec.Visibility,
+		}
+		if err := cmd.validate(); err != nil {
+			return errors.Wrap(err, "invalid artifacts spec")
+		}
+		catcher.Wrapf(cmd.Execute(ctx, comm, logger, conf), "uploading artifact '%s'", file.Name)
+	}
+
+	return catcher.Resolve()
+}
@@ -0,0 +1,146 @@
+package command
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/agent/internal"
+	"github.com/evergreen-ci/evergreen/agent/internal/client"
+	"github.com/evergreen-ci/evergreen/agent/internal/taskoutput"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/s3usage"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/testresult"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/pail"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type taskOutputTestStorage struct {
+	dir     string
+	results []testresult.TestResult
+	files   []*artifact.File
+}
+
+func (s *taskOutputTestStorage) Bucket(name string) (pail.FastGetS3Bucket, error) {
+	path := filepath.Join(s.dir, name)
+	if err := os.MkdirAll(path, 0777); err != nil {
+		return nil, err
+	}
+	b, err := pail.NewLocalBucket(pail.LocalOptions{Path: path, UseSlash: true})
+	if err != nil {
+		return nil, err
+	}
+	return &taskOutputTestBucket{Bucket: b}, nil
+}
+
+func (s *taskOutputTestStorage) AttachTestResults(results []testresult.TestResult) error {
+	s.results = append(s.results, results...)
+	return nil
+}
+
+func (s *taskOutputTestStorage) AttachFiles(files []*artifact.File) error {
+	s.files = append(s.files, files...)
+	return nil
+}
+
+type taskOutputTestBucket struct {
+	pail.Bucket
+}
+
+func (b *taskOutputTestBucket) GetToWriter(context.Context, string, io.WriterAt) error {
+	return errors.New("not implemented")
+}
+
+func TestIngestTestResultFiles(t *testing.T) {
+	testDataDir := filepath.Join(testutil.GetDirectoryOfFile(), "testdata")
+	setup := func(t *testing.T) (*internal.TaskConfig, *taskOutputTestStorage, client.Communicator, client.LoggerProducer) {
+		storage := &taskOutputTestStorage{dir: t.TempDir()}
+		conf := &internal.TaskConfig{
+			Task:         task.Task{Id: "task", Secret: "secret"},
+			WorkDir:      t.TempDir(),
+			Expansions:   util.Expansions{},
+			LocalStorage: storage,
+		}
+		comm := client.NewMock("http://localhost.com")
+		logger, err := comm.GetLoggerProducer(t.Context(), &conf.Task, nil)
+		require.NoError(t, err)
+		return conf, storage, comm, logger
+	}
+
+	t.Run("XUnit", func(t *testing.T) {
+		conf, storage, comm, logger := setup(t)
+		require.NoError(t, IngestTestResultFiles(t.Context(), comm, logger, conf, taskoutput.TestResultsFormatXUnit, []string{filepath.Join(testDataDir, "xunit", "results.xml")}))
+		assert.NotEmpty(t, storage.results)
+		assert.True(t, conf.HasTestResults)
+	})
+	t.Run("GoTest", func(t *testing.T) {
+		conf, storage, comm, logger := setup(t)
+		require.NoError(t, IngestTestResultFiles(t.Context(), comm, logger, conf, taskoutput.TestResultsFormatGoTest, []string{filepath.Join(testDataDir, "gotest", "1_simple.log")}))
+		assert.NotEmpty(t, storage.results)
+	})
+	t.Run("Evergreen", func(t *testing.T) {
+		conf, storage, comm, logger := setup(t)
+		require.NoError(t, IngestTestResultFiles(t.Context(), comm, logger, conf, taskoutput.TestResultsFormatEvergreen, []string{filepath.Join(testDataDir, "attach", "plugin_attach_results.json")}))
+		assert.NotEmpty(t, storage.results)
+	})
+	t.Run("InvalidFormat", func(t *testing.T) {
+		conf, _, comm, logger := setup(t)
+		assert.Error(t, IngestTestResultFiles(t.Context(), comm, logger, conf, "junit", []string{"results.xml"}))
+	})
+}
+
+func TestUploadArtifactFiles(t *testing.T) {
+	storage := &taskOutputTestStorage{dir: t.TempDir()}
+	conf := &internal.TaskConfig{
+		Task:         task.Task{Id: "task", Secret: "secret", Execution: 1},
+		WorkDir:      t.TempDir(),
+		S3Usage:      &s3usage.S3Usage{},
+		Expansions:   util.Expansions{"task_id": "task", "execution": "1", "aws_key": "key", "aws_secret": "secret"},
+		LocalStorage: storage,
+	}
+	comm := client.NewMock("http://localhost.com")
+	logger, err := comm.GetLoggerProducer(t.Context(), &conf.Task, nil)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	report := filepath.Join(dir, "report.html")
+	require.NoError(t, os.WriteFile(report, []byte("<html/>"), 0777))
+	binary := filepath.Join(dir, "bin", "server")
+	require.NoError(t, os.MkdirAll(filepath.Dir(binary), 0777))
+	require.NoError(t, os.WriteFile(binary, []byte("binary"), 0777))
+
+	spec := taskoutput.ArtifactsSpec{
+		Bucket:       "artifacts-bucket",
+		AWSKey:       "${aws_key}",
+		AWSSecret:    "${aws_secret}",
+		RemotePrefix: "${task_id}/${execution}",
+		Permissions:  "private",
+		Visibility:   "signed",
+	}
+	require.NoError(t, UploadArtifactFiles(t.Context(), comm, logger, conf, spec, []taskoutput.ArtifactFile{
+		{Path: binary, Name: "bin/server"},
+		{Path: report, Name: "report.html"},
+	}))
+
+	require.Len(t, storage.files, 2)
+	assert.Equal(t, "bin/server", storage.files[0].Name)
+	assert.Equal(t, "task/1/bin/server", storage.files[0].FileKey)
+	assert.Equal(t, "report.html", storage.files[1].Name)
+	assert.Equal(t, "task/1/report.html", storage.files[1].FileKey)
+	assert.Equal(t, "signed", storage.files[1].Visibility)
+	assert.Equal(t, "text/html; charset=utf-8", storage.files[1].ContentType)
+	assert.Equal(t, defaultArtifactContentType, storage.files[0].ContentType)
+
+	_, err = os.Stat(filepath.Join(storage.dir, "artifacts-bucket", "task", "1", "report.html"))
+	assert.NoError(t, err)
+
+	spec.AWSKey = ""
+	assert.Error(t, UploadArtifactFiles(t.Context(), comm, logger, conf, spec, []taskoutput.ArtifactFile{{Path: report, Name: "report.html"}}))
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/evergreen-ci/evergreen/agent/internal"
 	"github.com/evergreen-ci/evergreen/agent/internal/client"
+	"github.com/evergreen-ci/evergreen/model/artifact"
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
@@ -75,6 +76,16 @@ func GetWorkingDirectory(conf *internal.TaskConfig, path string) string {
 	return filepath.Join(conf.WorkDir, path)
 }
 
+// attachTaskFiles attaches the files to the task. If the task is running with
+// local storage, the files are recorded there instead of being sent to the
+// backend.
+func attachTaskFiles(ctx context.Context, comm client.Communicator, conf *internal.TaskConfig, td client.TaskData, files []*artifact.File) error {
+	if conf.LocalStorage != nil {
+		return errors.Wrap(conf.LocalStorage.AttachFiles(files), "attaching files locally")
+	}
+	return comm.AttachFiles(ctx, td, files)
+}
+
 // getWorkingDirectoryLegacy is a legacy function to get the working directory
 // for a path, enforce that the path is always prefixed with the task working
 // directory, and check that the directory exists. This is a legacy function, so
@@ -22,6 +22,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/testresult"
 	restmodel "github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/poplar"
 	"github.com/evergreen-ci/utility"
 	"github.com/google/go-github/v70/github"
 	"github.com/mongodb/grip"
@@ -754,6 +755,60 @@ func (c *baseCommunicator) KeyValInc(ctx context.Context, taskData TaskData, kv
 	return nil
 }
 
+// AcquireLock tries once to acquire the project lock for the task.
+func (c *baseCommunicator) AcquireLock(ctx context.Context, taskData TaskData, req apimodels.LockRequest) (*apimodels.LockAcquireResponse, error) {
+	info := requestInfo{
+		method:   http.MethodPost,
+		taskData: &taskData,
+	}
+	info.setTaskPathSuffix("lock/acquire")
+	resp, err := c.retryRequest(ctx, info, req)
+	if err != nil {
+		return nil, util.RespError(resp, errors.Wrapf(err, "acquiring lock '%s'", req.Name).Error())
+	}
+	defer resp.Body.Close()
+
+	lockResp := apimodels.LockAcquireResponse{}
+	if err = utility.ReadJSON(resp.Body, &lockResp); err != nil {
+		return nil, errors.Wrap(err, "reading lock acquire response")
+	}
+
+	return &lockResp, nil
+}
+
+// ReleaseLock releases the task's hold on the project lock, if any.
+func (c *baseCommunicator) ReleaseLock(ctx context.Context, taskData TaskData, req apimodels.LockRequest) error {
+	info := requestInfo{
+		method:   http.MethodPost,
+		taskData: &taskData,
+	}
+	info.setTaskPathSuffix("lock/release")
+	resp, err := c.retryRequest(ctx, info, req)
+	if err != nil {
+		return util.RespError(resp, errors.Wrapf(err, "releasing lock '%s'", req.Name).Error())
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+// SendPerfReport sends the results of a poplar performance report to be stored
+// for the task.
+func (c *baseCommunicator) SendPerfReport(ctx context.Context, taskData TaskData, report *poplar.Report) error {
+	info := requestInfo{
+		method:   http.MethodPost,
+		taskData: &taskData,
+	}
+	info.setTaskPathSuffix("perf/report")
+	resp, err := c.retryRequest(ctx, info, report)
+	if err != nil {
+		return util.RespError(resp, errors.Wrap(err, "sending perf report").Error())
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
 // GenerateTasks posts new tasks for the `generate.tasks` command.
 func (c *baseCommunicator) GenerateTasks(ctx context.Context, td TaskData, jsonBytes []json.RawMessage) error {
 	info := requestInfo{
@@ -17,6 +17,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/testlog"
 	"github.com/evergreen-ci/evergreen/model/testresult"
 	restmodel "github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/poplar"
 	"github.com/google/go-github/v70/github"
 	"github.com/mongodb/grip"
 )
@@ -102,6 +103,15 @@ type SharedCommunicator interface {
 	GetManifest(context.Context, TaskData) (*manifest.Manifest, error)
 	KeyValInc(context.Context, TaskData, *model.KeyVal) error
 
+	// AcquireLock tries once to acquire the project lock for the task.
+	AcquireLock(context.Context, TaskData, apimodels.LockRequest) (*apimodels.LockAcquireResponse, error)
+	// ReleaseLock releases the task's hold on the project lock, if any.
+	ReleaseLock(context.Context, TaskData, apimodels.LockRequest) error
+
+	// SendPerfReport sends the results of a poplar performance report to be
+	// stored for the task.
+	SendPerfReport(context.Context, TaskData, *poplar.Report) error
+
 	// GenerateTasks posts new tasks for the `generate.tasks` command.
 	GenerateTasks(context.Context, TaskData, []json.RawMessage) error
 
@@ -26,6 +26,7 @@ import (
 	"github.com/evergreen-ci/evergreen/rest/model"
 	restmodel "github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/poplar"
 	"github.com/evergreen-ci/utility"
 	"github.com/google/go-github/v70/github"
 	"github.com/mongodb/grip"
@@ -100,6 +101,21 @@ type Mock struct {
 	LastMessageSent  time.Time
 	DownstreamParams []patchModel.Parameter
 
+	// AcquireLockResponses are returned by successive calls to AcquireLock.
+	// The last response is repeated, and the lock is acquired if there are
+	// none.
+	AcquireLockResponses  []apimodels.LockAcquireResponse
+	AcquireLockCount      int
+	AcquireLockShouldFail bool
+	ReleasedLocks         []string
+	ReleaseLockShouldFail bool
+
+	PerfReports              []poplar.Report
+	SendPerfReportShouldFail bool
+	// NoPerfMonitoringURL makes GetPerfMonitoringURL return an empty URL,
+	// as if no external performance service were configured.
+	NoPerfMonitoringURL bool
+
 	// SelectTests mock fields
 	SelectTestsCalled   bool
 	SelectTestsRequest  restmodel.SelectTestsRequest
@@ -338,6 +354,9 @@ func (c *Mock) GetNextTask(ctx context.Context, details *apimodels.GetNextTaskDe
 
 // GetPerfMonitoringURL returns a mock performance URL.
 func (c *Mock) GetPerfMonitoringURL(ctx context.Context) (string, error) {
+	if c.NoPerfMonitoringURL {
+		return "", nil
+	}
 	return "http://myurl.mongodb.com", nil
 }
 
@@ -526,6 +545,45 @@ func (c *Mock) KeyValInc(ctx context.Context, td TaskData, kv *serviceModel.KeyV
 	return nil
 }
 
+func (c *Mock) AcquireLock(ctx context.Context, td TaskData, req apimodels.LockRequest) (*apimodels.LockAcquireResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.AcquireLockShouldFail {
+		return nil, errors.New("acquire lock should fail")
+	}
+	c.AcquireLockCount++
+	if len(c.AcquireLockResponses) == 0 {
+		return &apimodels.LockAcquireResponse{Acquired: true}, nil
+	}
+	idx := min(c.AcquireLockCount, len(c.AcquireLockResponses)) - 1
+	resp := c.AcquireLockResponses[idx]
+	return &resp, nil
+}
+
+func (c *Mock) ReleaseLock(ctx context.Context, td TaskData, req apimodels.LockRequest) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ReleaseLockShouldFail {
+		return errors.New("release lock should fail")
+	}
+	c.ReleasedLocks = append(c.ReleasedLocks, req.Name)
+	return nil
+}
+
+// SendPerfReport stores the perf report in the mock.
+func (c *Mock) SendPerfReport(ctx context.Context, td TaskData, report *poplar.Report) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.SendPerfReportShouldFail {
+		return errors.New("send perf report should fail")
+	}
+	c.PerfReports = append(c.PerfReports, *report)
+	return nil
+}
+
 // GenerateTasks posts new tasks for the `generate.tasks` command.
 func (c *Mock) GenerateTasks(ctx context.Context, td TaskData, jsonBytes []json.RawMessage) error {
 	if td.ID != "mock_id" {
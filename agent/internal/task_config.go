@@ -13,11 +13,14 @@ import (
 	agentutil "github.com/evergreen-ci/evergreen/agent/util"
 	"github.com/evergreen-ci/evergreen/apimodels"
 	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/artifact"
 	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/evergreen/model/s3usage"
 	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/testresult"
 	"github.com/evergreen-ci/evergreen/thirdparty"
 	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/pail"
 	"github.com/evergreen-ci/utility"
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/otel/attribute"
@@ -111,9 +114,26 @@ type TaskConfig struct {
 	// message of a version to be used in the otel attributes.
 	PatchOrVersionDescription string
 
+	// LocalStorage, if set, replaces S3 and the backend results and artifact
+	// services with local storage. It is only set when a task runs outside
+	// of a real agent, such as in an `evergreen debug` session.
+	LocalStorage LocalStorage
+
 	mu sync.RWMutex
 }
 
+// LocalStorage is a local stand-in for the remote storage that commands
+// upload to and attach from during normal task execution.
+type LocalStorage interface {
+	// Bucket returns the local bucket that stands in for the S3 bucket with
+	// the given name.
+	Bucket(name string) (pail.FastGetS3Bucket, error)
+	// AttachTestResults records test results parsed by a command.
+	AttachTestResults(results []testresult.TestResult) error
+	// AttachFiles records artifacts attached by a command.
+	AttachFiles(files []*artifact.File) error
+}
+
 func (tc *TaskConfig) TaskData() client.TaskData {
 	return client.TaskData{
 		ID:     tc.Task.Id,
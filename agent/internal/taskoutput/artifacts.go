@@ -0,0 +1,169 @@
+package taskoutput
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/evergreen-ci/evergreen/agent/internal/client"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"gopkg.in/yaml.v2"
+)
+
+// ArtifactsSpec represents the artifacts specification file written at the
+// top level of the reserved artifacts directory. It describes where the
+// artifacts are uploaded and how they're attached to the task. Values may
+// contain expansions, such as ${aws_key}, which are expanded by the agent
+// rather than written to the file.
+type ArtifactsSpec struct {
+	SchemaVersion string `yaml:"schema_version"`
+	// Bucket is the S3 bucket to upload the artifacts to.
+	Bucket string `yaml:"bucket"`
+	// RemotePrefix is the path in the bucket that the artifacts are uploaded
+	// under, preserving their paths relative to the artifacts directory.
+	// Defaults to "${task_id}/${execution}".
+	RemotePrefix string `yaml:"remote_prefix"`
+	Region       string `yaml:"region"`
+	// RoleARN is the role assumed to upload the artifacts. Otherwise,
+	// AWSKey and AWSSecret must be set.
+	RoleARN         string `yaml:"role_arn"`
+	AWSKey          string `yaml:"aws_key"`
+	AWSSecret       string `yaml:"aws_secret"`
+	AWSSessionToken string `yaml:"aws_session_token"`
+	// Permissions is the S3 ACL applied to the artifacts. Defaults to
+	// "private".
+	Permissions string `yaml:"permissions"`
+	// Visibility determines who can see the artifact links. Defaults to
+	// "signed".
+	Visibility string `yaml:"visibility"`
+	// ContentType is the MIME type of every artifact. If it is not set, the
+	// content type of each artifact is detected from its extension.
+	ContentType string `yaml:"content_type"`
+}
+
+const (
+	artifactsSpecFilename      = "artifacts_spec.yaml"
+	defaultArtifactsPrefix     = "${task_id}/${execution}"
+	defaultArtifactPermissions = "private"
+	defaultArtifactVisibility  = "signed"
+)
+
+func (s *ArtifactsSpec) validate() error {
+	if s.Bucket == "" {
+		return errors.New("bucket must be set")
+	}
+	if s.RoleARN == "" && (s.AWSKey == "" || s.AWSSecret == "") {
+		return errors.New("must set either a role ARN or both an AWS key and secret")
+	}
+	if s.RemotePrefix == "" {
+		s.RemotePrefix = defaultArtifactsPrefix
+	}
+	if s.Permissions == "" {
+		s.Permissions = defaultArtifactPermissions
+	}
+	if s.Visibility == "" {
+		s.Visibility = defaultArtifactVisibility
+	}
+	return nil
+}
+
+// ArtifactFile is a file written to the artifacts directory.
+type ArtifactFile struct {
+	// Path is the absolute path to the file.
+	Path string
+	// Name is the slash-separated path of the file relative to the artifacts
+	// directory.
+	Name string
+}
+
+// ArtifactUploader uploads the given files as described by the spec and
+// attaches them to the task.
+type ArtifactUploader func(ctx context.Context, spec ArtifactsSpec, files []ArtifactFile) error
+
+// artifactsDirectoryHandler implements automatic task output handling for the
+// reserved artifacts directory.
+type artifactsDirectoryHandler struct {
+	dir    string
+	logger client.LoggerProducer
+	upload ArtifactUploader
+}
+
+// newArtifactsDirectoryHandler returns a new artifacts directory handler for
+// the specified task.
+func newArtifactsDirectoryHandler(dir string, logger client.LoggerProducer, handlerOpts directoryHandlerOpts) directoryHandler {
+	return &artifactsDirectoryHandler{
+		dir:    dir,
+		logger: logger,
+		upload: handlerOpts.uploadArtifacts,
+	}
+}
+
+func (h *artifactsDirectoryHandler) run(ctx context.Context) error {
+	ctx, span := tracer.Start(ctx, "artifacts-upload")
+	defer span.End()
+
+	ignore := filepath.Join(h.dir, artifactsSpecFilename)
+	var files []ArtifactFile
+	err := filepath.WalkDir(h.dir, func(path string, info fs.DirEntry, err error) error {
+		if err != nil {
+			h.logger.Execution().Warning(ctx, errors.Wrap(err, "walking artifacts directory"))
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if info.IsDir() || path == ignore {
+			return nil
+		}
+
+		name, err := filepath.Rel(h.dir, path)
+		if err != nil {
+			h.logger.Task().Warning(ctx, errors.Wrapf(err, "getting relative path for artifact '%s'", path))
+			return nil
+		}
+		files = append(files, ArtifactFile{Path: path, Name: filepath.ToSlash(name)})
+
+		return nil
+	})
+	span.SetAttributes(attribute.Int("artifact_file_count", len(files)))
+	if err != nil {
+		return errors.Wrap(err, "walking artifacts directory")
+	}
+	if len(files) == 0 {
+		return nil
+	}
+
+	spec, err := h.getSpecFile()
+	if err != nil {
+		return errors.Wrapf(err, "cannot upload %d artifact(s) without a valid '%s'", len(files), artifactsSpecFilename)
+	}
+	if h.upload == nil {
+		return errors.New("artifacts cannot be uploaded without an uploader")
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+	h.logger.Task().Infof(ctx, "Uploading %d artifact(s) from the artifacts directory to bucket '%s'.", len(files), spec.Bucket)
+
+	return errors.Wrap(h.upload(ctx, *spec, files), "uploading artifacts")
+}
+
+// getSpecFile reads the artifacts specification file in the top level of the
+// reserved artifacts directory. Unlike other task output directories, there
+// is no default spec, since the artifacts need somewhere to be uploaded.
+func (h *artifactsDirectoryHandler) getSpecFile() (*ArtifactsSpec, error) {
+	data, err := os.ReadFile(filepath.Join(h.dir, artifactsSpecFilename))
+	if err != nil {
+		return nil, errors.Wrap(err, "reading artifacts spec")
+	}
+	spec := &ArtifactsSpec{}
+	if err = yaml.Unmarshal(data, spec); err != nil {
+		return nil, errors.Wrap(err, "unmarshalling artifacts spec")
+	}
+	if err = spec.validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid artifacts spec")
+	}
+	return spec, nil
+}
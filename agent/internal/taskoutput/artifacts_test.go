@@ -0,0 +1,91 @@
+package taskoutput
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/agent/internal/client"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArtifactsDirectoryHandlerRun(t *testing.T) {
+	type upload struct {
+		spec  ArtifactsSpec
+		files []ArtifactFile
+	}
+	setup := func(t *testing.T) (*artifactsDirectoryHandler, *[]upload) {
+		comm := client.NewMock("url")
+		logger, err := comm.GetLoggerProducer(t.Context(), &task.Task{Id: "task"}, nil)
+		require.NoError(t, err)
+
+		uploads := []upload{}
+		h := newArtifactsDirectoryHandler(t.TempDir(), logger, directoryHandlerOpts{
+			uploadArtifacts: func(_ context.Context, spec ArtifactsSpec, files []ArtifactFile) error {
+				uploads = append(uploads, upload{spec: spec, files: files})
+				return nil
+			},
+		}).(*artifactsDirectoryHandler)
+		return h, &uploads
+	}
+	writeFile := func(t *testing.T, dir, name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0777))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0777))
+		return path
+	}
+
+	t.Run("UploadsAllFiles", func(t *testing.T) {
+		h, uploads := setup(t)
+		writeFile(t, h.dir, artifactsSpecFilename, "bucket: my-bucket\nrole_arn: arn:aws:iam::123456789012:role/uploader\n")
+		report := writeFile(t, h.dir, "report.html", "<html/>")
+		binary := writeFile(t, h.dir, "bin/server", "binary")
+
+		require.NoError(t, h.run(t.Context()))
+		require.Len(t, *uploads, 1)
+		u := (*uploads)[0]
+		assert.Equal(t, "my-bucket", u.spec.Bucket)
+		assert.Equal(t, defaultArtifactsPrefix, u.spec.RemotePrefix)
+		assert.Equal(t, defaultArtifactPermissions, u.spec.Permissions)
+		assert.Equal(t, defaultArtifactVisibility, u.spec.Visibility)
+		assert.Equal(t, []ArtifactFile{
+			{Path: binary, Name: "bin/server"},
+			{Path: report, Name: "report.html"},
+		}, u.files)
+	})
+	t.Run("KeepsSpecValues", func(t *testing.T) {
+		h, uploads := setup(t)
+		writeFile(t, h.dir, artifactsSpecFilename, "bucket: my-bucket\naws_key: ${aws_key}\naws_secret: ${aws_secret}\nremote_prefix: builds/${revision}\nvisibility: public\npermissions: public-read\n")
+		writeFile(t, h.dir, "report.html", "<html/>")
+
+		require.NoError(t, h.run(t.Context()))
+		require.Len(t, *uploads, 1)
+		spec := (*uploads)[0].spec
+		assert.Equal(t, "${aws_key}", spec.AWSKey, "expansions should be left for the uploader")
+		assert.Equal(t, "builds/${revision}", spec.RemotePrefix)
+		assert.Equal(t, "public", spec.Visibility)
+		assert.Equal(t, "public-read", spec.Permissions)
+	})
+	t.Run("NoopsWithoutFiles", func(t *testing.T) {
+		h, uploads := setup(t)
+		writeFile(t, h.dir, artifactsSpecFilename, "bucket: my-bucket\n")
+		require.NoError(t, h.run(t.Context()))
+		assert.Empty(t, *uploads)
+	})
+	t.Run("FailsWithoutSpec", func(t *testing.T) {
+		h, uploads := setup(t)
+		writeFile(t, h.dir, "report.html", "<html/>")
+		assert.ErrorContains(t, h.run(t.Context()), artifactsSpecFilename)
+		assert.Empty(t, *uploads)
+	})
+	t.Run("FailsWithInvalidSpec", func(t *testing.T) {
+		h, uploads := setup(t)
+		writeFile(t, h.dir, artifactsSpecFilename, "bucket: my-bucket\n")
+		writeFile(t, h.dir, "report.html", "<html/>")
+		assert.ErrorContains(t, h.run(t.Context()), "role ARN")
+		assert.Empty(t, *uploads)
+	})
+}
@@ -32,8 +32,10 @@ import (
 )
 
 var directoryHandlerFactories = map[string]directoryHandlerFactory{
-	"TestLogs":   newTestLogDirectoryHandler,
-	"OTelTraces": newOtelTraceDirectoryHandler,
+	"TestLogs":    newTestLogDirectoryHandler,
+	"OTelTraces":  newOtelTraceDirectoryHandler,
+	"TestResults": newTestResultsDirectoryHandler,
+	"Artifacts":   newArtifactsDirectoryHandler,
 }
 
 // Directory is the application representation of a task's reserved output
@@ -52,17 +54,25 @@ type DirectoryOpts struct {
 	Logger       client.LoggerProducer
 	TraceClient  otlptrace.Client
 	S3Usage      *s3usage.S3Usage
+	// IngestTestResults attaches the test result files written to the test
+	// results directory.
+	IngestTestResults TestResultsIngester
+	// UploadArtifacts uploads and attaches the files written to the
+	// artifacts directory.
+	UploadArtifacts ArtifactUploader
 }
 
 // NewDirectory returns a new task output directory with the specified root for
 // the given task.
 func NewDirectory(opts DirectoryOpts) *Directory {
 	handlerOpts := directoryHandlerOpts{
-		tsk:          opts.Tsk,
-		redactorOpts: opts.RedactorOpts,
-		output:       opts.Tsk.TaskOutputInfo,
-		traceClient:  opts.TraceClient,
-		s3Usage:      opts.S3Usage,
+		tsk:               opts.Tsk,
+		redactorOpts:      opts.RedactorOpts,
+		output:            opts.Tsk.TaskOutputInfo,
+		traceClient:       opts.TraceClient,
+		s3Usage:           opts.S3Usage,
+		ingestTestResults: opts.IngestTestResults,
+		uploadArtifacts:   opts.UploadArtifacts,
 	}
 	root := filepath.Join(opts.Root, "build")
 	handlers := map[string]directoryHandler{}
@@ -120,11 +130,13 @@ type directoryHandler interface {
 
 // directoryHandlerOpts contains options to be passed into each directory handler implementation initialization.
 type directoryHandlerOpts struct {
-	output       *task.TaskOutput
-	tsk          *task.Task
-	redactorOpts redactor.RedactionOptions
-	traceClient  otlptrace.Client
-	s3Usage      *s3usage.S3Usage
+	output            *task.TaskOutput
+	tsk               *task.Task
+	redactorOpts      redactor.RedactionOptions
+	traceClient       otlptrace.Client
+	s3Usage           *s3usage.S3Usage
+	ingestTestResults TestResultsIngester
+	uploadArtifacts   ArtifactUploader
 }
 
 // directoryHandlerFactory abstracts the creation of a directory handler.
@@ -0,0 +1,180 @@
+package taskoutput
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen/agent/internal/client"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"gopkg.in/yaml.v2"
+)
+
+// TestResultsFormat is the format of the test result files written to the
+// test results directory.
+type TestResultsFormat string
+
+const (
+	// TestResultsFormatXUnit is an xunit XML report, as attached by
+	// attach.xunit_results.
+	TestResultsFormatXUnit TestResultsFormat = "xunit"
+	// TestResultsFormatEvergreen is Evergreen's native JSON format, as
+	// attached by attach.results.
+	TestResultsFormatEvergreen TestResultsFormat = "evergreen"
+	// TestResultsFormatGoTest is the output of go test, as parsed by
+	// gotest.parse_files.
+	TestResultsFormatGoTest TestResultsFormat = "gotest"
+)
+
+func (f TestResultsFormat) validate() error {
+	switch f {
+	case TestResultsFormatXUnit, TestResultsFormatEvergreen, TestResultsFormatGoTest:
+		return nil
+	default:
+		return errors.Errorf("unrecognized test results format '%s'", f)
+	}
+}
+
+// TestResultsIngester parses the given test result files of the given format
+// and attaches the results to the task.
+type TestResultsIngester func(ctx context.Context, format TestResultsFormat, files []string) error
+
+// testResultsSpec represents the test results specification file written at
+// the top level of the reserved test results directory.
+type testResultsSpec struct {
+	SchemaVersion string `yaml:"schema_version"`
+	// Format is the format of every file in the directory. If it is not set,
+	// the format of each file is detected from its extension.
+	Format TestResultsFormat `yaml:"format"`
+}
+
+const testResultsSpecFilename = "results_spec.yaml"
+
+// testResultsDirectoryHandler implements automatic task output handling for
+// the reserved test results directory.
+type testResultsDirectoryHandler struct {
+	dir    string
+	logger client.LoggerProducer
+	spec   testResultsSpec
+	ingest TestResultsIngester
+}
+
+// newTestResultsDirectoryHandler returns a new test results directory handler
+// for the specified task.
+func newTestResultsDirectoryHandler(dir string, logger client.LoggerProducer, handlerOpts directoryHandlerOpts) directoryHandler {
+	return &testResultsDirectoryHandler{
+		dir:    dir,
+		logger: logger,
+		ingest: handlerOpts.ingestTestResults,
+	}
+}
+
+func (h *testResultsDirectoryHandler) run(ctx context.Context) error {
+	ctx, span := tracer.Start(ctx, "test-results-ingestion")
+	defer span.End()
+
+	h.getSpecFile(ctx)
+
+	ignore := filepath.Join(h.dir, testResultsSpecFilename)
+	filesByFormat := map[TestResultsFormat][]string{}
+	var numFiles int
+	err := filepath.WalkDir(h.dir, func(path string, info fs.DirEntry, err error) error {
+		if err != nil {
+			h.logger.Execution().Warning(ctx, errors.Wrap(err, "walking test results directory"))
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if info.IsDir() || path == ignore {
+			return nil
+		}
+
+		format := h.spec.Format
+		if format == "" {
+			format = detectTestResultsFormat(path)
+		}
+		if format == "" {
+			h.logger.Task().Warningf(ctx, "skipping test results file '%s' because its format cannot be detected from its extension; set the format in '%s' to ingest it", path, testResultsSpecFilename)
+			return nil
+		}
+		filesByFormat[format] = append(filesByFormat[format], path)
+		numFiles++
+
+		return nil
+	})
+	span.SetAttributes(attribute.Int("test_results_file_count", numFiles))
+	if err != nil {
+		return errors.Wrap(err, "walking test results directory")
+	}
+	if numFiles == 0 {
+		return nil
+	}
+	if h.ingest == nil {
+		return errors.New("test results cannot be ingested without an ingester")
+	}
+
+	formats := make([]string, 0, len(filesByFormat))
+	for format := range filesByFormat {
+		formats = append(formats, string(format))
+	}
+	sort.Strings(formats)
+
+	catcher := grip.NewBasicCatcher()
+	for _, format := range formats {
+		files := filesByFormat[TestResultsFormat(format)]
+		sort.Strings(files)
+		h.logger.Task().Infof(ctx, "Ingesting %d %s test results file(s) from the test results directory.", len(files), format)
+		catcher.Wrapf(h.ingest(ctx, TestResultsFormat(format), files), "ingesting %s test results", format)
+	}
+
+	return catcher.Resolve()
+}
+
+// getSpecFile looks for the test results specification file in the top level
+// of the reserved test results directory. If the spec file cannot be read for
+// any reason, the handler detects each file's format from its extension.
+//
+// Called once per task run before sweeping the directory for test results
+// files.
+func (h *testResultsDirectoryHandler) getSpecFile(ctx context.Context) {
+	data, err := os.ReadFile(filepath.Join(h.dir, testResultsSpecFilename))
+	if os.IsNotExist(err) {
+		return
+	}
+	if err != nil {
+		h.logger.Task().Warning(ctx, errors.Wrap(err, "reading test results spec; falling back to detecting formats by file extension"))
+		return
+	}
+	if err = yaml.Unmarshal(data, &h.spec); err != nil {
+		h.logger.Task().Warning(ctx, errors.Wrap(err, "unmarshalling test results spec; falling back to detecting formats by file extension"))
+		return
+	}
+
+	if h.spec.Format != "" {
+		if err = h.spec.Format.validate(); err != nil {
+			h.logger.Task().Warning(ctx, errors.Wrap(err, "invalid test results format specified; falling back to detecting formats by file extension"))
+			h.spec.Format = ""
+		}
+	}
+}
+
+// detectTestResultsFormat returns the format of the test results file based
+// on its extension, or an empty format if it's not recognized.
+func detectTestResultsFormat(path string) TestResultsFormat {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".xml":
+		return TestResultsFormatXUnit
+	case ".json":
+		return TestResultsFormatEvergreen
+	case ".log", ".out", ".txt":
+		return TestResultsFormatGoTest
+	default:
+		return ""
+	}
+}
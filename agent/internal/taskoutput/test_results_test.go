@@ -0,0 +1,98 @@
+package taskoutput
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/agent/internal/client"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestTestResultsDirectoryHandlerRun(t *testing.T) {
+	setup := func(t *testing.T) (*testResultsDirectoryHandler, map[TestResultsFormat][]string) {
+		comm := client.NewMock("url")
+		logger, err := comm.GetLoggerProducer(t.Context(), &task.Task{Id: "task"}, nil)
+		require.NoError(t, err)
+
+		ingested := map[TestResultsFormat][]string{}
+		h := newTestResultsDirectoryHandler(t.TempDir(), logger, directoryHandlerOpts{
+			ingestTestResults: func(_ context.Context, format TestResultsFormat, files []string) error {
+				ingested[format] = append(ingested[format], files...)
+				return nil
+			},
+		}).(*testResultsDirectoryHandler)
+		return h, ingested
+	}
+	writeFile := func(t *testing.T, dir, name string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0777))
+		require.NoError(t, os.WriteFile(path, []byte("results"), 0777))
+		return path
+	}
+
+	t.Run("DetectsFormatsByExtension", func(t *testing.T) {
+		h, ingested := setup(t)
+		xunit := writeFile(t, h.dir, "junit.xml")
+		nested := writeFile(t, h.dir, "nested/report.XML")
+		native := writeFile(t, h.dir, "results.json")
+		goTest := writeFile(t, h.dir, "unit.log")
+		writeFile(t, h.dir, "screenshot.png")
+
+		require.NoError(t, h.run(t.Context()))
+		assert.ElementsMatch(t, []string{xunit, nested}, ingested[TestResultsFormatXUnit])
+		assert.Equal(t, []string{native}, ingested[TestResultsFormatEvergreen])
+		assert.Equal(t, []string{goTest}, ingested[TestResultsFormatGoTest])
+	})
+	t.Run("UsesSpecFormat", func(t *testing.T) {
+		h, ingested := setup(t)
+		data, err := yaml.Marshal(testResultsSpec{Format: TestResultsFormatGoTest})
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(h.dir, testResultsSpecFilename), data, 0777))
+		first := writeFile(t, h.dir, "a.xml")
+		second := writeFile(t, h.dir, "b")
+
+		require.NoError(t, h.run(t.Context()))
+		assert.Equal(t, TestResultsFormatGoTest, h.spec.Format)
+		require.Len(t, ingested, 1)
+		assert.Equal(t, []string{first, second}, ingested[TestResultsFormatGoTest])
+	})
+	t.Run("FallsBackToExtensionsForInvalidSpec", func(t *testing.T) {
+		h, ingested := setup(t)
+		require.NoError(t, os.WriteFile(filepath.Join(h.dir, testResultsSpecFilename), []byte("format: junit"), 0777))
+		xunit := writeFile(t, h.dir, "junit.xml")
+
+		require.NoError(t, h.run(t.Context()))
+		assert.Empty(t, h.spec.Format)
+		assert.Equal(t, []string{xunit}, ingested[TestResultsFormatXUnit])
+	})
+	t.Run("NoopsWithoutFiles", func(t *testing.T) {
+		h, ingested := setup(t)
+		h.ingest = nil
+		require.NoError(t, h.run(t.Context()))
+		assert.Empty(t, ingested)
+	})
+	t.Run("ReturnsIngestionErrors", func(t *testing.T) {
+		h, _ := setup(t)
+		h.ingest = func(context.Context, TestResultsFormat, []string) error {
+			return errors.New("ingestion failed")
+		}
+		writeFile(t, h.dir, "junit.xml")
+
+		assert.ErrorContains(t, h.run(t.Context()), "ingestion failed")
+	})
+}
+
+func TestDetectTestResultsFormat(t *testing.T) {
+	assert.Equal(t, TestResultsFormatXUnit, detectTestResultsFormat("dir/junit.xml"))
+	assert.Equal(t, TestResultsFormatEvergreen, detectTestResultsFormat("results.json"))
+	assert.Equal(t, TestResultsFormatGoTest, detectTestResultsFormat("go_test.out"))
+	assert.Equal(t, TestResultsFormatGoTest, detectTestResultsFormat("go_test.txt"))
+	assert.Empty(t, detectTestResultsFormat("results"))
+	assert.Empty(t, detectTestResultsFormat("image.png"))
+}
@@ -0,0 +1,259 @@
+package taskexec
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// BreakpointType is the kind of condition a breakpoint stops on.
+type BreakpointType string
+
+const (
+	// BreakpointStep stops before the step with the given step number (e.g.
+	// "5", "5.3" or "pre:1.2").
+	BreakpointStep BreakpointType = "step"
+	// BreakpointCommand stops before every step running the given command
+	// (e.g. "s3.put").
+	BreakpointCommand BreakpointType = "command"
+	// BreakpointFunction stops before the first step of every invocation of
+	// the given function.
+	BreakpointFunction BreakpointType = "function"
+	// BreakpointFailure stops after the first step that fails, including
+	// steps whose failure does not fail the task.
+	BreakpointFailure BreakpointType = "failure"
+)
+
+// Breakpoint is a named condition that stops a debug session when continuing.
+type Breakpoint struct {
+	Name  string         `json:"name"`
+	Type  BreakpointType `json:"type"`
+	Value string         `json:"value,omitempty"`
+}
+
+// Validate checks that the breakpoint is well-formed.
+func (b Breakpoint) Validate() error {
+	switch b.Type {
+	case BreakpointStep, BreakpointCommand, BreakpointFunction:
+		if b.Value == "" {
+			return errors.Errorf("%s breakpoint must specify a value", b.Type)
+		}
+	case BreakpointFailure:
+	default:
+		return errors.Errorf("invalid breakpoint type '%s'", b.Type)
+	}
+	return nil
+}
+
+// String returns a human-readable description of the breakpoint.
+func (b Breakpoint) String() string {
+	if b.Type == BreakpointFailure {
+		return fmt.Sprintf("%s (on first failure)", b.Name)
+	}
+	return fmt.Sprintf("%s (%s %s)", b.Name, b.Type, b.Value)
+}
+
+// matchesBefore returns whether the breakpoint stops before running the given
+// command.
+func (b Breakpoint) matchesBefore(ci CommandInfo) bool {
+	switch b.Type {
+	case BreakpointStep:
+		return ci.FullStepNumber() == b.Value
+	case BreakpointCommand:
+		return ci.CommandName == b.Value
+	case BreakpointFunction:
+		// Only stop at the start of the function rather than at each of the
+		// commands it expands to.
+		return ci.IsFunction && ci.FunctionName == b.Value && ci.FuncSubCmdNum <= 1
+	default:
+		return false
+	}
+}
+
+// ExpansionSnapshot is a copy of the task's expansions taken when the debug
+// session stopped at a step.
+type ExpansionSnapshot struct {
+	StepIndex  int               `json:"step_index"`
+	StepNumber string            `json:"step_number"`
+	Reason     string            `json:"reason"`
+	CreatedAt  time.Time         `json:"created_at"`
+	Expansions map[string]string `json:"expansions"`
+}
+
+// ExpansionChange is an expansion whose value differs between two snapshots.
+type ExpansionChange struct {
+	Key string `json:"key"`
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// ExpansionDiff is the difference between the expansions of two snapshots.
+type ExpansionDiff struct {
+	From    string            `json:"from"`
+	To      string            `json:"to"`
+	Added   map[string]string `json:"added,omitempty"`
+	Removed map[string]string `json:"removed,omitempty"`
+	Changed []ExpansionChange `json:"changed,omitempty"`
+}
+
+// IsEmpty returns whether the snapshots have identical expansions.
+func (d ExpansionDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// diffExpansions returns the changes needed to go from the expansions in one
+// snapshot to the expansions in another.
+func diffExpansions(from, to ExpansionSnapshot) ExpansionDiff {
+	diff := ExpansionDiff{
+		From:    from.StepNumber,
+		To:      to.StepNumber,
+		Added:   map[string]string{},
+		Removed: map[string]string{},
+	}
+	for k, v := range to.Expansions {
+		oldVal, ok := from.Expansions[k]
+		switch {
+		case !ok:
+			diff.Added[k] = v
+		case oldVal != v:
+			diff.Changed = append(diff.Changed, ExpansionChange{Key: k, Old: oldVal, New: v})
+		}
+	}
+	for k, v := range from.Expansions {
+		if _, ok := to.Expansions[k]; !ok {
+			diff.Removed[k] = v
+		}
+	}
+	sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i].Key < diff.Changed[j].Key })
+
+	return diff
+}
+
+// AddBreakpoint adds a breakpoint to the debug session. If the breakpoint has
+// no name, one is generated.
+func (ds *DebugState) AddBreakpoint(bp Breakpoint) (Breakpoint, error) {
+	if err := bp.Validate(); err != nil {
+		return bp, err
+	}
+	if bp.Type == BreakpointStep {
+		if _, err := ds.ResolveStepNumber(bp.Value); err != nil {
+			return bp, errors.Wrap(err, "resolving breakpoint step")
+		}
+	}
+	if bp.Name == "" {
+		bp.Name = ds.nextBreakpointName()
+	}
+	if ds.findBreakpoint(bp.Name) >= 0 {
+		return bp, errors.Errorf("breakpoint '%s' already exists", bp.Name)
+	}
+
+	ds.Breakpoints = append(ds.Breakpoints, bp)
+	return bp, nil
+}
+
+// RemoveBreakpoint removes the breakpoint with the given name.
+func (ds *DebugState) RemoveBreakpoint(name string) error {
+	i := ds.findBreakpoint(name)
+	if i < 0 {
+		return errors.Errorf("breakpoint '%s' not found", name)
+	}
+	ds.Breakpoints = append(ds.Breakpoints[:i], ds.Breakpoints[i+1:]...)
+	return nil
+}
+
+// ClearBreakpoints removes all breakpoints.
+func (ds *DebugState) ClearBreakpoints() {
+	ds.Breakpoints = []Breakpoint{}
+}
+
+func (ds *DebugState) findBreakpoint(name string) int {
+	for i, bp := range ds.Breakpoints {
+		if bp.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (ds *DebugState) nextBreakpointName() string {
+	for n := len(ds.Breakpoints) + 1; ; n++ {
+		name := fmt.Sprintf("bp%d", n)
+		if ds.findBreakpoint(name) < 0 {
+			return name
+		}
+	}
+}
+
+// breakpointBefore returns the first breakpoint that stops before the step at
+// the given index, or nil if there is none.
+func (ds *DebugState) breakpointBefore(index int) *Breakpoint {
+	if index < 0 || index >= len(ds.CommandList) {
+		return nil
+	}
+	for i := range ds.Breakpoints {
+		if ds.Breakpoints[i].matchesBefore(ds.CommandList[index]) {
+			return &ds.Breakpoints[i]
+		}
+	}
+	return nil
+}
+
+// failureBreakpoint returns the failure breakpoint, or nil if there is none.
+func (ds *DebugState) failureBreakpoint() *Breakpoint {
+	for i := range ds.Breakpoints {
+		if ds.Breakpoints[i].Type == BreakpointFailure {
+			return &ds.Breakpoints[i]
+		}
+	}
+	return nil
+}
+
+// addSnapshot records a snapshot of the expansions at the current step.
+func (ds *DebugState) addSnapshot(expansions map[string]string, reason string) ExpansionSnapshot {
+	snapshot := ExpansionSnapshot{
+		StepIndex:  ds.CurrentStepIndex,
+		StepNumber: ds.currentStepNumber(),
+		Reason:     reason,
+		CreatedAt:  time.Now(),
+		Expansions: expansions,
+	}
+	ds.Snapshots = append(ds.Snapshots, snapshot)
+	return snapshot
+}
+
+// currentStepNumber returns the step number of the current step, or "end" if
+// all steps have run.
+func (ds *DebugState) currentStepNumber() string {
+	if !ds.HasMoreSteps() {
+		return "end"
+	}
+	return ds.CommandList[ds.CurrentStepIndex].FullStepNumber()
+}
+
+// findSnapshot returns the most recent snapshot taken at the given step
+// number.
+func (ds *DebugState) findSnapshot(stepNum string) (ExpansionSnapshot, error) {
+	for i := len(ds.Snapshots) - 1; i >= 0; i-- {
+		if ds.Snapshots[i].StepNumber == stepNum {
+			return ds.Snapshots[i], nil
+		}
+	}
+	return ExpansionSnapshot{}, errors.Errorf("no expansion snapshot for step '%s'", stepNum)
+}
+
+// DiffSnapshots returns the difference between the most recent expansion
+// snapshots taken at the two given step numbers.
+func (ds *DebugState) DiffSnapshots(fromStep, toStep string) (ExpansionDiff, error) {
+	from, err := ds.findSnapshot(strings.TrimSpace(fromStep))
+	if err != nil {
+		return ExpansionDiff{}, err
+	}
+	to, err := ds.findSnapshot(strings.TrimSpace(toStep))
+	if err != nil {
+		return ExpansionDiff{}, err
+	}
+	return diffExpansions(from, to), nil
+}
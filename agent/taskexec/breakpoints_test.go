@@ -0,0 +1,209 @@
+package taskexec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/agent/command"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBreakpointValidate(t *testing.T) {
+	for tName, tCase := range map[string]struct {
+		bp    Breakpoint
+		valid bool
+	}{
+		"Step":         {bp: Breakpoint{Type: BreakpointStep, Value: "3"}, valid: true},
+		"Command":      {bp: Breakpoint{Type: BreakpointCommand, Value: "s3.put"}, valid: true},
+		"Function":     {bp: Breakpoint{Type: BreakpointFunction, Value: "setup"}, valid: true},
+		"Failure":      {bp: Breakpoint{Type: BreakpointFailure}, valid: true},
+		"MissingValue": {bp: Breakpoint{Type: BreakpointCommand}},
+		"InvalidType":  {bp: Breakpoint{Type: "nonexistent", Value: "3"}},
+		"Empty":        {},
+	} {
+		t.Run(tName, func(t *testing.T) {
+			err := tCase.bp.Validate()
+			if tCase.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestBreakpointMatchesBefore(t *testing.T) {
+	main := CommandInfo{CommandName: "shell.exec", BlockType: command.MainTaskBlock, BlockCmdNum: 2}
+	funcFirst := CommandInfo{CommandName: "s3.put", IsFunction: true, FunctionName: "upload", BlockType: command.MainTaskBlock, BlockCmdNum: 3, FuncSubCmdNum: 1, FuncTotalSubCmds: 2}
+	funcSecond := CommandInfo{CommandName: "shell.exec", IsFunction: true, FunctionName: "upload", BlockType: command.MainTaskBlock, BlockCmdNum: 3, FuncSubCmdNum: 2, FuncTotalSubCmds: 2}
+
+	step := Breakpoint{Type: BreakpointStep, Value: "2"}
+	assert.True(t, step.matchesBefore(main))
+	assert.False(t, step.matchesBefore(funcFirst))
+
+	cmd := Breakpoint{Type: BreakpointCommand, Value: "shell.exec"}
+	assert.True(t, cmd.matchesBefore(main))
+	assert.True(t, cmd.matchesBefore(funcSecond))
+	assert.False(t, cmd.matchesBefore(funcFirst))
+
+	fn := Breakpoint{Type: BreakpointFunction, Value: "upload"}
+	assert.True(t, fn.matchesBefore(funcFirst))
+	assert.False(t, fn.matchesBefore(funcSecond), "function breakpoints should only stop at the start of the function")
+	assert.False(t, fn.matchesBefore(main))
+
+	failure := Breakpoint{Type: BreakpointFailure}
+	assert.False(t, failure.matchesBefore(main), "failure breakpoints should only stop after a step runs")
+}
+
+func TestDebugStateBreakpoints(t *testing.T) {
+	ds := NewDebugState()
+	ds.CommandList = []CommandInfo{
+		{CommandName: "shell.exec", BlockType: command.MainTaskBlock, BlockCmdNum: 1},
+		{CommandName: "s3.put", BlockType: command.MainTaskBlock, BlockCmdNum: 2},
+	}
+
+	t.Run("GeneratesNames", func(t *testing.T) {
+		bp, err := ds.AddBreakpoint(Breakpoint{Type: BreakpointCommand, Value: "s3.put"})
+		require.NoError(t, err)
+		assert.Equal(t, "bp1", bp.Name)
+		defer ds.ClearBreakpoints()
+
+		bp, err = ds.AddBreakpoint(Breakpoint{Type: BreakpointFailure})
+		require.NoError(t, err)
+		assert.Equal(t, "bp2", bp.Name)
+		assert.Len(t, ds.Breakpoints, 2)
+	})
+	t.Run("RejectsDuplicateNames", func(t *testing.T) {
+		defer ds.ClearBreakpoints()
+		_, err := ds.AddBreakpoint(Breakpoint{Name: "upload", Type: BreakpointCommand, Value: "s3.put"})
+		require.NoError(t, err)
+		_, err = ds.AddBreakpoint(Breakpoint{Name: "upload", Type: BreakpointStep, Value: "1"})
+		assert.Error(t, err)
+	})
+	t.Run("RejectsUnknownStep", func(t *testing.T) {
+		_, err := ds.AddBreakpoint(Breakpoint{Type: BreakpointStep, Value: "10"})
+		assert.Error(t, err)
+		assert.Empty(t, ds.Breakpoints)
+	})
+	t.Run("Remove", func(t *testing.T) {
+		_, err := ds.AddBreakpoint(Breakpoint{Name: "upload", Type: BreakpointCommand, Value: "s3.put"})
+		require.NoError(t, err)
+		require.NotNil(t, ds.breakpointBefore(1))
+
+		require.NoError(t, ds.RemoveBreakpoint("upload"))
+		assert.Empty(t, ds.Breakpoints)
+		assert.Nil(t, ds.breakpointBefore(1))
+		assert.Error(t, ds.RemoveBreakpoint("upload"))
+	})
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	ds := NewDebugState()
+	ds.Snapshots = []ExpansionSnapshot{
+		{StepNumber: "1", Expansions: map[string]string{"a": "1", "b": "2", "c": "3"}},
+		{StepNumber: "3", Expansions: map[string]string{"a": "1", "b": "20", "d": "4"}},
+	}
+
+	diff, err := ds.DiffSnapshots("1", "3")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"d": "4"}, diff.Added)
+	assert.Equal(t, map[string]string{"c": "3"}, diff.Removed)
+	assert.Equal(t, []ExpansionChange{{Key: "b", Old: "2", New: "20"}}, diff.Changed)
+
+	diff, err = ds.DiffSnapshots("3", "3")
+	require.NoError(t, err)
+	assert.True(t, diff.IsEmpty())
+
+	_, err = ds.DiffSnapshots("1", "2")
+	assert.Error(t, err)
+}
+
+func TestContinue(t *testing.T) {
+	setup := func(t *testing.T) *LocalExecutor {
+		tmpDir := t.TempDir()
+		yamlFile := filepath.Join(tmpDir, "test.yml")
+		require.NoError(t, os.WriteFile(yamlFile, []byte(`
+functions:
+  set_stage:
+    - command: expansions.update
+      params:
+        updates:
+          - key: stage
+            value: ${stage_name}
+tasks:
+  - name: test-task
+    commands:
+      - command: expansions.update
+        params:
+          updates:
+            - key: first
+              value: "1"
+      - func: set_stage
+        vars:
+          stage_name: build
+      - command: expansions.update
+        params:
+          updates:
+            - key: second
+              value: "2"
+      - func: set_stage
+        vars:
+          stage_name: test
+`), 0644))
+
+		executor, err := NewLocalExecutor(t.Context(), LocalExecutorOptions{WorkingDir: tmpDir})
+		require.NoError(t, err)
+		_, err = executor.LoadProject(yamlFile)
+		require.NoError(t, err)
+		require.NoError(t, executor.PrepareTask(t.Context(), "test-task", ""))
+		require.Len(t, executor.debugState.CommandList, 4)
+		return executor
+	}
+
+	t.Run("StopsAtEachBreakpoint", func(t *testing.T) {
+		executor := setup(t)
+		_, err := executor.debugState.AddBreakpoint(Breakpoint{Name: "stage", Type: BreakpointFunction, Value: "set_stage"})
+		require.NoError(t, err)
+
+		bp, err := executor.Continue(t.Context())
+		require.NoError(t, err)
+		require.NotNil(t, bp)
+		assert.Equal(t, "stage", bp.Name)
+		assert.Equal(t, 1, executor.debugState.CurrentStepIndex)
+
+		bp, err = executor.Continue(t.Context())
+		require.NoError(t, err)
+		require.NotNil(t, bp)
+		assert.Equal(t, 3, executor.debugState.CurrentStepIndex)
+
+		bp, err = executor.Continue(t.Context())
+		require.NoError(t, err)
+		assert.Nil(t, bp)
+		assert.False(t, executor.debugState.HasMoreSteps())
+	})
+	t.Run("SnapshotsExpansionsAtEachStop", func(t *testing.T) {
+		executor := setup(t)
+		_, err := executor.debugState.AddBreakpoint(Breakpoint{Type: BreakpointStep, Value: "4"})
+		require.NoError(t, err)
+
+		require.NoError(t, executor.StepNext(t.Context()))
+		_, err = executor.Continue(t.Context())
+		require.NoError(t, err)
+		require.Len(t, executor.debugState.Snapshots, 2)
+
+		diff, err := executor.debugState.DiffSnapshots("2", "4")
+		require.NoError(t, err)
+		assert.Equal(t, "build", diff.Added["stage"])
+		assert.Equal(t, "2", diff.Added["second"])
+		assert.NotContains(t, diff.Added, "first")
+		assert.Empty(t, diff.Removed)
+	})
+	t.Run("ReturnsErrorWithNoMoreSteps", func(t *testing.T) {
+		executor := setup(t)
+		require.NoError(t, executor.RunAll(t.Context()))
+		_, err := executor.Continue(t.Context())
+		assert.Error(t, err)
+	})
+}
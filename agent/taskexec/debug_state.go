@@ -20,6 +20,10 @@ type DebugState struct {
 	LastError        error
 	ExecutionHistory []executionRecord
 	ConfigPath       string
+	// Breakpoints are the conditions that stop the session when continuing.
+	Breakpoints []Breakpoint
+	// Snapshots are the expansions recorded each time the session stopped.
+	Snapshots []ExpansionSnapshot
 }
 
 // executionRecord tracks the execution of a single command
@@ -30,6 +34,12 @@ type executionRecord struct {
 	errMsg     string
 }
 
+// failed returns whether the command errored, even if the error did not fail
+// the task.
+func (r executionRecord) failed() bool {
+	return !r.success || r.errMsg != ""
+}
+
 // GetStepExecution returns whether a step has been executed and if it succeeded.
 func (ds *DebugState) GetStepExecution(index int) (executed, success bool) {
 	for _, record := range ds.ExecutionHistory {
@@ -94,6 +104,8 @@ func NewDebugState() *DebugState {
 		CustomVars:       make(map[string]string),
 		CommandList:      []CommandInfo{},
 		ExecutionHistory: []executionRecord{},
+		Breakpoints:      []Breakpoint{},
+		Snapshots:        []ExpansionSnapshot{},
 	}
 }
 
@@ -17,6 +17,7 @@ import (
 	"github.com/evergreen-ci/evergreen/agent/internal/redactor"
 	agentutil "github.com/evergreen-ci/evergreen/agent/util"
 	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/s3usage"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/logging"
@@ -25,19 +26,15 @@ import (
 )
 
 var noOpCommands = map[string]string{
-	evergreen.HostCreateCommandName:         "dynamic host creation is not supported in local execution",
-	"host.list":                             "host listing is not supported in local execution",
-	"generate.tasks":                        "dynamic task generation is not supported in local execution",
-	"downstream_expansions.set":             "downstream expansions are not available in local execution",
-	evergreen.AttachXUnitResultsCommandName: "test result attachment is not supported in local execution",
-	evergreen.AttachResultsCommandName:      "result attachment is not supported in local execution",
-	"gotest.parse_files":                    "result attachment is not supported in local execution",
-	evergreen.AttachArtifactsCommandName:    "artifact attachment is not supported in local execution",
-	"papertrail.trace":                      "papertrail tracing is not available in local execution",
-	"keyval.inc":                            "key-value increment operations are not supported in local execution",
-	"perf.send":                             "performance metrics submission is not supported in local execution",
-	"s3.put":                                "S3 upload operations are not supported in local execution",
-	"s3Copy.copy":                           "S3 copy operations are not supported in local execution",
+	evergreen.HostCreateCommandName: "dynamic host creation is not supported in local execution",
+	"host.list":                     "host listing is not supported in local execution",
+	"generate.tasks":                "dynamic task generation is not supported in local execution",
+	"downstream_expansions.set":     "downstream expansions are not available in local execution",
+	"papertrail.trace":              "papertrail tracing is not available in local execution",
+	"keyval.inc":                    "key-value increment operations are not supported in local execution",
+	"lock.acquire":                  "project locks are not supported in local execution",
+	"lock.release":                  "project locks are not supported in local execution",
+	"perf.send":                     "performance metrics submission is not supported in local execution",
 }
 
 // mockSecret is required to make agent request formation validation pass but it's not used in
@@ -123,8 +120,15 @@ func NewLocalExecutor(ctx context.Context, opts LocalExecutorOptions) (*LocalExe
 		NewExpansions:         agentutil.NewDynamicExpansions(expansions),
 		WorkDir:               opts.WorkingDir,
 		AssumeRoleInformation: map[string]internal.AssumeRoleInformation{},
+		S3Usage:               &s3usage.S3Usage{},
 	}
 
+	storageDir, err := getSessionStorageDir()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting session storage directory")
+	}
+	taskConfig.LocalStorage = newLocalStorage(storageDir)
+
 	jasperManager, err := jasper.NewSynchronizedManager(false)
 	if err != nil {
 		return nil, errors.Wrap(err, "creating jasper manager")
@@ -277,8 +281,9 @@ func (e *LocalExecutor) RunUntil(ctx context.Context, untilIndex int) error {
 		untilIndex = maxIndex
 	}
 
+	defer e.snapshot("run-until")
 	for e.debugState.CurrentStepIndex < untilIndex {
-		if err := e.StepNext(ctx); err != nil {
+		if err := e.stepNext(ctx); err != nil {
 			e.logger.Errorf(ctx, "Step %s failed: %v", e.debugState.CommandList[e.debugState.CurrentStepIndex].FullStepNumber(), err)
 			return err
 		}
@@ -315,6 +320,7 @@ func (e *LocalExecutor) StepNext(ctx context.Context) error {
 	if !e.debugState.HasMoreSteps() {
 		return errors.New("no more steps to execute")
 	}
+	defer e.snapshot("next")
 	return e.stepNext(ctx)
 }
 
@@ -402,6 +408,9 @@ func (e *LocalExecutor) stepNext(ctx context.Context) error {
 
 	// Flag to track if we've executed our target command
 	executed := false
+	// Errors from commands that cannot fail the task are recorded so that
+	// they can still stop the session at a failure breakpoint.
+	var nonFatalErr error
 
 	// Override the RunCommandOrFunc callback to intercept and execute
 	// only the specific command we're targeting
@@ -444,6 +453,7 @@ func (e *LocalExecutor) stepNext(ctx context.Context) error {
 				}
 				blockName := executor.BlockToLegacyName(blockType)
 				e.logger.Warningf(ctx, "Continuing after non-fatal error in %s block: %v", blockName, err)
+				nonFatalErr = err
 			} else {
 				e.logger.Infof(ctx, "Step %s completed successfully", targetCmd.FullStepNumber())
 			}
@@ -477,6 +487,9 @@ func (e *LocalExecutor) stepNext(ctx context.Context) error {
 	}
 	durationMs := time.Since(startTime).Milliseconds()
 	record.durationMs = durationMs
+	if nonFatalErr != nil {
+		record.errMsg = nonFatalErr.Error()
+	}
 	e.debugState.addExecutionRecord(record)
 
 	if e.streamWriter != nil {
@@ -498,8 +511,9 @@ func (e *LocalExecutor) getNoOpMessage(cmdName string) string {
 
 // RunAll executes all steps in a task
 func (e *LocalExecutor) RunAll(ctx context.Context) error {
+	defer e.snapshot("run-all")
 	for e.debugState.HasMoreSteps() {
-		if err := e.StepNext(ctx); err != nil {
+		if err := e.stepNext(ctx); err != nil {
 			e.logger.Warningf(ctx, "Step failed, continuing")
 			return err
 		}
@@ -507,6 +521,77 @@ func (e *LocalExecutor) RunAll(ctx context.Context) error {
 	return nil
 }
 
+// Continue executes steps until a breakpoint is reached, a step fails, or there
+// are no more steps. Breakpoints on the current step are skipped so that
+// continuing from a breakpoint makes progress. It returns the breakpoint that
+// stopped the session, if any.
+func (e *LocalExecutor) Continue(ctx context.Context) (*Breakpoint, error) {
+	if !e.debugState.HasMoreSteps() {
+		return nil, errors.New("no more steps to execute")
+	}
+
+	for started := false; e.debugState.HasMoreSteps(); started = true {
+		if started {
+			if bp := e.debugState.breakpointBefore(e.debugState.CurrentStepIndex); bp != nil {
+				e.stopAtBreakpoint(ctx, *bp)
+				return bp, nil
+			}
+		}
+
+		err := e.stepNext(ctx)
+		if bp := e.debugState.failureBreakpoint(); bp != nil && e.lastStepFailed() {
+			e.stopAtBreakpoint(ctx, *bp)
+			return bp, err
+		}
+		if err != nil {
+			e.snapshot("continue")
+			return nil, err
+		}
+	}
+
+	e.snapshot("continue")
+	return nil, nil
+}
+
+// lastStepFailed returns whether the most recently executed step errored.
+func (e *LocalExecutor) lastStepFailed() bool {
+	history := e.debugState.ExecutionHistory
+	return len(history) > 0 && history[len(history)-1].failed()
+}
+
+// stopAtBreakpoint reports that the session stopped at the breakpoint and
+// snapshots the expansions.
+func (e *LocalExecutor) stopAtBreakpoint(ctx context.Context, bp Breakpoint) {
+	msg := fmt.Sprintf("Stopped at breakpoint %s before step %s.", bp.String(), e.debugState.currentStepNumber())
+	if e.streamWriter != nil {
+		e.streamWriter.WriteChannelMessage(ExecChannel, msg)
+	}
+	e.logger.Info(ctx, msg)
+	e.snapshot("breakpoint " + bp.Name)
+}
+
+// snapshot records the current expansions, with private values redacted, in
+// the debug state.
+func (e *LocalExecutor) snapshot(reason string) {
+	redacted := map[string]bool{}
+	for _, key := range e.taskConfig.Redacted {
+		redacted[key] = true
+	}
+	for _, info := range e.taskConfig.NewExpansions.GetRedacted() {
+		redacted[info.Key] = true
+	}
+
+	expansions := map[string]string{}
+	e.taskConfig.NewExpansions.Range(func(key, value string) bool {
+		if redacted[key] {
+			value = fmt.Sprintf("<REDACTED:%s>", key)
+		}
+		expansions[key] = value
+		return true
+	})
+	e.debugState.addSnapshot(expansions, reason)
+}
+
 // GetDebugState returns the current debug state
 func (e *LocalExecutor) GetDebugState() *DebugState {
 	return e.debugState
@@ -983,18 +983,15 @@ tasks:
 		yamlFile := filepath.Join(tmpDir, "test.yml")
 		yamlContent := `
 functions:
-  upload_func:
-    - command: s3.put
+  increment_func:
+    - command: keyval.inc
       params:
-        aws_key: key
-        aws_secret: secret
-        local_file: file.txt
-        remote_file: remote.txt
-        bucket: bucket
+        key: counter
+        destination: counter_value
 tasks:
   - name: test-task
     commands:
-      - func: upload_func
+      - func: increment_func
 `
 		require.NoError(t, os.WriteFile(yamlFile, []byte(yamlContent), 0644))
 
@@ -1007,8 +1004,8 @@ tasks:
 
 		require.Len(t, executor.debugState.CommandList, 1)
 		cmdInfo := executor.debugState.CommandList[0]
-		assert.Equal(t, "s3.put", cmdInfo.CommandName)
+		assert.Equal(t, "keyval.inc", cmdInfo.CommandName)
 		_, isNoOp := noOpCommands[cmdInfo.CommandName]
-		assert.True(t, isNoOp, "s3.put inside a function should be detected as a noOp command")
+		assert.True(t, isNoOp, "keyval.inc inside a function should be detected as a noOp command")
 	})
 }
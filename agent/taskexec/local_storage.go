@@ -0,0 +1,192 @@
+package taskexec
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/testresult"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/pail"
+	"github.com/pkg/errors"
+)
+
+const (
+	storageSubDir   = "storage"
+	bucketsSubDir   = "buckets"
+	testResultsFile = "test_results.json"
+	artifactsFile   = "artifacts.json"
+)
+
+// localStorage is a filesystem-backed stand-in for S3 and for the results and
+// artifact services. Uploaded files are stored in a per-bucket directory in
+// the session directory so that later downloads in the same session can read
+// them back, and attached test results and artifacts are recorded as JSON.
+type localStorage struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// newLocalStorage creates local storage rooted at the given directory. The
+// directory is created lazily once something is stored in it.
+func newLocalStorage(dir string) *localStorage {
+	return &localStorage{dir: dir}
+}
+
+// getSessionStorageDir returns the directory that holds the local storage
+// for the current debug session.
+func getSessionStorageDir() (string, error) {
+	homeDir, err := util.GetUserHome()
+	if err != nil {
+		return "", errors.Wrap(err, "getting user home directory")
+	}
+	return filepath.Join(homeDir, logBaseDir, storageSubDir, sessionSubDir), nil
+}
+
+// Bucket returns a bucket backed by a directory in local storage.
+func (s *localStorage) Bucket(name string) (pail.FastGetS3Bucket, error) {
+	if name == "" {
+		return nil, errors.New("bucket name cannot be empty")
+	}
+	// The bucket name must be a single path element so that the bucket can't
+	// be stored outside of the buckets directory.
+	if name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return nil, errors.Errorf("invalid bucket name '%s'", name)
+	}
+	path := filepath.Join(s.dir, bucketsSubDir, name)
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return nil, errors.Wrapf(err, "creating local bucket directory '%s'", path)
+	}
+	b, err := pail.NewLocalBucket(pail.LocalOptions{
+		Path:     path,
+		UseSlash: true,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "creating local bucket '%s'", name)
+	}
+	return &localBucket{Bucket: b}, nil
+}
+
+// AttachTestResults appends the test results to the session's results file.
+func (s *localStorage) AttachTestResults(results []testresult.TestResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := filepath.Join(s.dir, testResultsFile)
+	var existing []testresult.TestResult
+	if err := readJSONFile(path, &existing); err != nil {
+		return errors.Wrap(err, "reading existing test results")
+	}
+	return errors.Wrap(writeJSONFile(path, append(existing, results...)), "writing test results")
+}
+
+// AttachFiles appends the artifacts to the session's artifacts file.
+func (s *localStorage) AttachFiles(files []*artifact.File) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := filepath.Join(s.dir, artifactsFile)
+	var existing []artifact.File
+	if err := readJSONFile(path, &existing); err != nil {
+		return errors.Wrap(err, "reading existing artifacts")
+	}
+	for _, f := range files {
+		if f == nil {
+			continue
+		}
+		// Credentials for signed artifacts shouldn't be persisted to disk.
+		file := *f
+		file.AWSKey = ""
+		file.AWSSecret = ""
+		existing = append(existing, file)
+	}
+	return errors.Wrap(writeJSONFile(path, existing), "writing artifacts")
+}
+
+// localBucket wraps a local pail bucket so it can be used by commands that
+// expect to download from S3.
+type localBucket struct {
+	pail.Bucket
+}
+
+// GetToWriter downloads the key to the writer.
+func (b *localBucket) GetToWriter(ctx context.Context, key string, w io.WriterAt) error {
+	r, err := b.Get(ctx, key)
+	if err != nil {
+		return errors.Wrapf(err, "getting key '%s'", key)
+	}
+	defer r.Close()
+
+	_, err = io.Copy(io.NewOffsetWriter(w, 0), r)
+	return errors.Wrapf(err, "copying key '%s'", key)
+}
+
+// ReadTestResults returns the test results attached during the current debug
+// session.
+func ReadTestResults() ([]testresult.TestResult, error) {
+	dir, err := getSessionStorageDir()
+	if err != nil {
+		return nil, err
+	}
+	var results []testresult.TestResult
+	if err := readJSONFile(filepath.Join(dir, testResultsFile), &results); err != nil {
+		return nil, errors.Wrap(err, "reading test results")
+	}
+	return results, nil
+}
+
+// ReadArtifacts returns the artifacts attached during the current debug
+// session.
+func ReadArtifacts() ([]artifact.File, error) {
+	dir, err := getSessionStorageDir()
+	if err != nil {
+		return nil, err
+	}
+	var files []artifact.File
+	if err := readJSONFile(filepath.Join(dir, artifactsFile), &files); err != nil {
+		return nil, errors.Wrap(err, "reading artifacts")
+	}
+	return files, nil
+}
+
+// ClearSessionStorage removes the uploaded files, test results and artifacts
+// from the current debug session (called when selecting a new task).
+func ClearSessionStorage() error {
+	dir, err := getSessionStorageDir()
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "clearing session storage")
+	}
+	return nil
+}
+
+// readJSONFile decodes the JSON file at the given path into out. A missing
+// file is not an error and leaves out unchanged.
+func readJSONFile(path string, out any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "reading file '%s'", path)
+	}
+	return errors.Wrapf(json.Unmarshal(data, out), "unmarshalling file '%s'", path)
+}
+
+func writeJSONFile(path string, in any) error {
+	data, err := json.MarshalIndent(in, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "marshalling JSON")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Wrapf(err, "creating directory for file '%s'", path)
+	}
+	return errors.Wrapf(os.WriteFile(path, data, 0644), "writing file '%s'", path)
+}
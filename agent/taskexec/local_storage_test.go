@@ -0,0 +1,156 @@
+package taskexec
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/testresult"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStorage(t *testing.T) {
+	t.Run("BucketRoundTrip", func(t *testing.T) {
+		storage := newLocalStorage(t.TempDir())
+		bucket, err := storage.Bucket("my-bucket")
+		require.NoError(t, err)
+
+		require.NoError(t, bucket.Put(t.Context(), "path/to/file.txt", strings.NewReader("hello")))
+		exists, err := bucket.Exists(t.Context(), "path/to/file.txt")
+		require.NoError(t, err)
+		assert.True(t, exists)
+
+		out, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+		require.NoError(t, err)
+		defer out.Close()
+		require.NoError(t, bucket.GetToWriter(t.Context(), "path/to/file.txt", out))
+		data, err := os.ReadFile(out.Name())
+		require.NoError(t, err)
+		assert.Equal(t, "hello", string(data))
+	})
+	t.Run("BucketsAreIsolated", func(t *testing.T) {
+		storage := newLocalStorage(t.TempDir())
+		bucket1, err := storage.Bucket("bucket1")
+		require.NoError(t, err)
+		bucket2, err := storage.Bucket("bucket2")
+		require.NoError(t, err)
+
+		require.NoError(t, bucket1.Put(t.Context(), "file.txt", strings.NewReader("hello")))
+		exists, err := bucket2.Exists(t.Context(), "file.txt")
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+	t.Run("EmptyBucketNameErrors", func(t *testing.T) {
+		storage := newLocalStorage(t.TempDir())
+		_, err := storage.Bucket("")
+		assert.Error(t, err)
+	})
+	t.Run("BucketNameOutsideStorageErrors", func(t *testing.T) {
+		dir := t.TempDir()
+		storage := newLocalStorage(filepath.Join(dir, "storage"))
+		for _, name := range []string{"..", ".", "../escaped", "nested/bucket", `..\escaped`} {
+			_, err := storage.Bucket(name)
+			assert.Error(t, err, name)
+		}
+		_, err := os.Stat(filepath.Join(dir, "escaped"))
+		assert.True(t, os.IsNotExist(err), "bucket should not be created outside of local storage")
+	})
+	t.Run("AttachTestResultsAppends", func(t *testing.T) {
+		dir := t.TempDir()
+		storage := newLocalStorage(dir)
+		require.NoError(t, storage.AttachTestResults([]testresult.TestResult{{TestName: "test1", Status: evergreen.TestSucceededStatus}}))
+		require.NoError(t, storage.AttachTestResults([]testresult.TestResult{{TestName: "test2", Status: evergreen.TestFailedStatus}}))
+
+		var results []testresult.TestResult
+		require.NoError(t, readJSONFile(filepath.Join(dir, testResultsFile), &results))
+		require.Len(t, results, 2)
+		assert.Equal(t, "test1", results[0].TestName)
+		assert.Equal(t, "test2", results[1].TestName)
+		assert.Equal(t, evergreen.TestFailedStatus, results[1].Status)
+	})
+	t.Run("AttachFilesOmitsCredentials", func(t *testing.T) {
+		dir := t.TempDir()
+		storage := newLocalStorage(dir)
+		require.NoError(t, storage.AttachFiles([]*artifact.File{
+			{Name: "file", Link: "link", AWSKey: "key", AWSSecret: "secret"},
+			nil,
+		}))
+
+		data, err := os.ReadFile(filepath.Join(dir, artifactsFile))
+		require.NoError(t, err)
+		assert.False(t, bytes.Contains(data, []byte("secret")))
+
+		var files []artifact.File
+		require.NoError(t, json.Unmarshal(data, &files))
+		require.Len(t, files, 1)
+		assert.Equal(t, "file", files[0].Name)
+		assert.Empty(t, files[0].AWSKey)
+	})
+}
+
+func TestLocalExecutorUsesLocalStorage(t *testing.T) {
+	tmpDir := t.TempDir()
+	storageDir := t.TempDir()
+	yamlFile := filepath.Join(tmpDir, "test.yml")
+	yamlContent := `
+tasks:
+  - name: test-task
+    commands:
+      - command: s3.put
+        params:
+          aws_key: key
+          aws_secret: secret
+          local_file: upload.txt
+          remote_file: uploads/upload.txt
+          bucket: my-bucket
+          content_type: text/plain
+          permissions: private
+      - command: s3.get
+        params:
+          aws_key: key
+          aws_secret: secret
+          local_file: download.txt
+          remote_file: uploads/upload.txt
+          bucket: my-bucket
+      - command: attach.results
+        params:
+          file_location: results.json
+`
+	require.NoError(t, os.WriteFile(yamlFile, []byte(yamlContent), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "upload.txt"), []byte("artifact contents"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "results.json"), []byte(`{"results": [{"test_file": "test1", "status": "fail"}]}`), 0644))
+
+	executor, err := NewLocalExecutor(t.Context(), LocalExecutorOptions{WorkingDir: tmpDir})
+	require.NoError(t, err)
+	storage := newLocalStorage(storageDir)
+	executor.taskConfig.LocalStorage = storage
+
+	_, err = executor.LoadProject(yamlFile)
+	require.NoError(t, err)
+	require.NoError(t, executor.SetupWorkingDirectory(tmpDir))
+	require.NoError(t, executor.PrepareTask(t.Context(), "test-task", ""))
+	require.NoError(t, executor.RunAll(t.Context()))
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, "download.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "artifact contents", string(data))
+
+	var results []testresult.TestResult
+	require.NoError(t, readJSONFile(filepath.Join(storageDir, testResultsFile), &results))
+	require.Len(t, results, 1)
+	assert.Equal(t, "test1", results[0].TestName)
+	assert.Equal(t, evergreen.TestFailedStatus, results[0].Status)
+	assert.True(t, executor.taskConfig.HasFailingTestResult)
+
+	var files []artifact.File
+	require.NoError(t, readJSONFile(filepath.Join(storageDir, artifactsFile), &files))
+	require.Len(t, files, 1)
+	assert.Equal(t, "my-bucket", files[0].Bucket)
+	assert.Equal(t, "uploads/upload.txt", files[0].FileKey)
+}
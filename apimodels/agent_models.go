@@ -425,3 +425,27 @@ type CheckRunAnnotation struct {
 type HighExecTimeoutReport struct {
 	ExecTimeoutSecs int `json:"exec_timeout_secs"`
 }
+
+// LockRequest is sent by the agent to acquire or release a project lock for
+// the lock.acquire and lock.release commands.
+type LockRequest struct {
+	Name string `json:"name"`
+	// Slots is the number of tasks that can hold the lock at once. It is only
+	// used when acquiring the lock.
+	Slots int `json:"slots,omitempty"`
+}
+
+// LockAcquireResponse is sent by the API server in response to a request to
+// acquire a lock.
+type LockAcquireResponse struct {
+	Acquired bool `json:"acquired"`
+	// Holders are the tasks currently holding the lock.
+	Holders []LockHolder `json:"holders,omitempty"`
+}
+
+// LockHolder is a task holding a lock.
+type LockHolder struct {
+	TaskID    string    `json:"task_id"`
+	Execution int       `json:"execution"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -34,6 +34,14 @@ func TestLoadUserManager(t *testing.T) {
 		Issuer:     "www.example.com",
 		KeysetURL:  "www.google.com",
 	}
+	oidcConf := evergreen.OIDCConfig{
+		Issuer:   "https://keycloak.example.com/realms/evergreen",
+		ClientID: "client_id",
+	}
+	multiOIDC := evergreen.MultiAuthConfig{
+		ReadWrite: []string{evergreen.AuthOIDCKey},
+		ReadOnly:  []string{evergreen.AuthNaiveKey},
+	}
 
 	a := evergreen.AuthConfig{}
 	um, info, err := LoadUserManager(&evergreen.Settings{AuthConfig: a})
@@ -125,6 +133,22 @@ func TestLoadUserManager(t *testing.T) {
 	_, ok = um.(*NaiveUserManager)
 	assert.True(t, ok)
 
+	a = evergreen.AuthConfig{PreferredType: evergreen.AuthOIDCKey, OIDC: &oidcConf, Naive: &naive}
+	um, info, err = LoadUserManager(&evergreen.Settings{AuthConfig: a})
+	assert.NoError(t, err)
+	assert.True(t, info.CanClearTokens)
+	assert.True(t, info.CanReauthorize)
+	assert.NotNil(t, um)
+	_, ok = um.(*oidcUserManager)
+	assert.True(t, ok)
+
+	a = evergreen.AuthConfig{PreferredType: evergreen.AuthMultiKey, Multi: &multiOIDC, OIDC: &oidcConf, Naive: &naive}
+	um, info, err = LoadUserManager(&evergreen.Settings{AuthConfig: a})
+	assert.NoError(t, err)
+	assert.True(t, info.CanClearTokens, "should be able to clear tokens if the underlying manager is able")
+	assert.True(t, info.CanReauthorize, "should be able to reauthorize if the underlying manager is able")
+	assert.NotNil(t, um)
+
 	a = evergreen.AuthConfig{PreferredType: evergreen.AuthKanopyKey, Kanopy: &kanopy}
 	um, info, err = LoadUserManager(&evergreen.Settings{AuthConfig: a})
 	assert.NoError(t, err)
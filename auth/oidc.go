@@ -0,0 +1,766 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc"
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/gimlet/usercache"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+)
+
+const (
+	oidcNonceCookieName      = "oidc-nonce"
+	oidcStateCookieName      = "oidc-state"
+	oidcVerifierCookieName   = "oidc-code-verifier"
+	oidcRequestURICookieName = "oidc-original-request-uri"
+
+	oidcTemporaryCookieTTL = time.Hour
+	// oidcKeyRefreshInterval is the minimum time between forced refreshes of
+	// the identity provider's signing keys when an ID token fails signature
+	// verification.
+	oidcKeyRefreshInterval = time.Minute
+
+	defaultOIDCGroupsClaim = "groups"
+
+	// oidcInvalidGrantMessage is included in reauthorization errors when the
+	// identity provider rejects the user's refresh token, so that the user can
+	// be logged out rather than retried.
+	oidcInvalidGrantMessage = "refresh token is invalid or expired"
+)
+
+var defaultOIDCScopes = []string{"profile", "email", oidc.ScopeOfflineAccess}
+
+// oidcUserManager authenticates users through a generic OpenID Connect
+// identity provider using the authorization code flow with PKCE.
+type oidcUserManager struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	redirectURI  string
+	scopes       []string
+
+	usernameClaim string
+	groupsClaim   string
+	userGroup     string
+	roleMappings  []evergreen.OIDCRoleMapping
+
+	cookiePath      string
+	cookieDomain    string
+	loginCookieName string
+	loginCookieTTL  time.Duration
+
+	cache usercache.Cache
+	// updateRoles grants and revokes the roles managed by the role mappings
+	// for a user that has already been persisted.
+	updateRoles func(ctx context.Context, u gimlet.User, grant, revoke []string) error
+
+	getHTTPClient      func() *http.Client
+	putHTTPClient      func(*http.Client)
+	keyRefreshInterval time.Duration
+	reconciliateID     func(string) string
+
+	// The provider is discovered on first use so that the identity provider
+	// being unavailable does not prevent the app server from starting.
+	providerMu sync.Mutex
+	provider   *oidcProvider
+}
+
+// oidcProvider contains the endpoints and signing keys discovered from the
+// identity provider.
+type oidcProvider struct {
+	endpoint oauth2.Endpoint
+	verifier *oidc.IDTokenVerifier
+}
+
+// oidcClaims are the standard claims read from the ID token. Other claims,
+// such as the username and groups claims, are configurable and read from the
+// raw claims.
+type oidcClaims struct {
+	Subject string
+	Email   string
+	Name    string
+	Raw     map[string]any
+}
+
+// NewOIDCUserManager returns a user manager that logs users in through a
+// generic OpenID Connect identity provider.
+func NewOIDCUserManager(conf *evergreen.OIDCConfig, evgURL, loginDomain string) (gimlet.UserManager, error) {
+	expireAfter := time.Duration(conf.ExpireAfterMinutes) * time.Minute
+	cache, err := usercache.NewExternal(usercache.ExternalOptions{
+		PutUserGetToken: user.PutLoginCache,
+		GetUserByToken: func(ctx context.Context, token string) (gimlet.User, bool, error) {
+			return user.GetLoginCache(ctx, token, expireAfter)
+		},
+		ClearUserToken: func(ctx context.Context, u gimlet.User, all bool) error {
+			if all {
+				return user.ClearAllLoginCaches(ctx)
+			}
+			return user.ClearLoginCache(ctx, u)
+		},
+		GetUserByID: func(ctx context.Context, id string) (gimlet.User, bool, error) {
+			return getUserByIdWithExpiration(ctx, id, expireAfter)
+		},
+		GetOrCreateUser: getOrCreateUser,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "creating OIDC user cache")
+	}
+	return newOIDCUserManager(conf, strings.TrimRight(evgURL, "/")+"/login/redirect/callback", loginDomain, cache, updateOIDCUserRoles)
+}
+
+func newOIDCUserManager(conf *evergreen.OIDCConfig, redirectURI, loginDomain string, cache usercache.Cache, updateRoles func(context.Context, gimlet.User, []string, []string) error) (*oidcUserManager, error) {
+	catcher := grip.NewBasicCatcher()
+	catcher.NewWhen(conf.Issuer == "", "must specify issuer")
+	catcher.NewWhen(conf.ClientID == "", "must specify client ID")
+	catcher.NewWhen(redirectURI == "", "must specify redirect URI")
+	catcher.NewWhen(cache == nil, "must specify user cache")
+	if catcher.HasErrors() {
+		return nil, errors.Wrap(catcher.Resolve(), "invalid OIDC manager options")
+	}
+
+	scopes := []string{oidc.ScopeOpenID}
+	requested := conf.Scopes
+	if len(requested) == 0 {
+		requested = defaultOIDCScopes
+	}
+	for _, scope := range requested {
+		if !utility.StringSliceContains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	groupsClaim := conf.GroupsClaim
+	if groupsClaim == "" {
+		groupsClaim = defaultOIDCGroupsClaim
+	}
+
+	return &oidcUserManager{
+		issuer:             conf.Issuer,
+		clientID:           conf.ClientID,
+		clientSecret:       conf.ClientSecret,
+		redirectURI:        redirectURI,
+		scopes:             scopes,
+		usernameClaim:      conf.UsernameClaim,
+		groupsClaim:        groupsClaim,
+		userGroup:          conf.UserGroup,
+		roleMappings:       conf.RoleMappings,
+		cookiePath:         "/",
+		cookieDomain:       loginDomain,
+		loginCookieName:    evergreen.AuthTokenCookie,
+		loginCookieTTL:     evergreen.LoginCookieTTL,
+		cache:              cache,
+		updateRoles:        updateRoles,
+		getHTTPClient:      utility.GetHTTPClient,
+		putHTTPClient:      utility.PutHTTPClient,
+		keyRefreshInterval: oidcKeyRefreshInterval,
+		reconciliateID:     makeReconciliateID(conf.ExpectedEmailDomains),
+	}, nil
+}
+
+// getProvider returns the identity provider, discovering its configuration
+// if it has not already been discovered.
+func (m *oidcUserManager) getProvider(ctx context.Context) (*oidcProvider, error) {
+	m.providerMu.Lock()
+	defer m.providerMu.Unlock()
+
+	if m.provider != nil {
+		return m.provider, nil
+	}
+
+	discoveryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	client := m.getHTTPClient()
+	defer m.putHTTPClient(client)
+	provider, err := oidc.NewProvider(oidc.ClientContext(discoveryCtx, client), m.issuer)
+	if err != nil {
+		return nil, errors.Wrapf(err, "discovering OIDC provider '%s'", m.issuer)
+	}
+	var discovery struct {
+		JWKSURL string   `json:"jwks_uri"`
+		Algs    []string `json:"id_token_signing_alg_values_supported"`
+	}
+	if err = provider.Claims(&discovery); err != nil {
+		return nil, errors.Wrap(err, "reading OIDC provider discovery document")
+	}
+	if discovery.JWKSURL == "" {
+		return nil, errors.New("OIDC provider discovery document is missing the JWKS URI")
+	}
+
+	// The key set outlives the request that triggers discovery, so it keeps
+	// its own HTTP client for fetching the signing keys.
+	keysCtx := oidc.ClientContext(context.Background(), m.getHTTPClient())
+	keys := newOIDCKeySet(keysCtx, discovery.JWKSURL, m.keyRefreshInterval)
+	m.provider = &oidcProvider{
+		endpoint: provider.Endpoint(),
+		verifier: oidc.NewVerifier(m.issuer, keys, &oidc.Config{
+			ClientID:             m.clientID,
+			SupportedSigningAlgs: discovery.Algs,
+		}),
+	}
+	return m.provider, nil
+}
+
+func (m *oidcUserManager) oauthConfig(p *oidcProvider) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     m.clientID,
+		ClientSecret: m.clientSecret,
+		Endpoint:     p.endpoint,
+		RedirectURL:  m.redirectURI,
+		Scopes:       m.scopes,
+	}
+}
+
+func (m *oidcUserManager) GetUserByToken(ctx context.Context, token string) (gimlet.User, error) {
+	u, valid, err := m.cache.Get(ctx, token)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting cached user")
+	}
+	if u == nil {
+		return nil, errors.New("user not found in cache")
+	}
+	if !valid {
+		if err := m.ReauthorizeUser(ctx, u); err != nil {
+			return u, gimlet.ErrNeedsReauthentication
+		}
+	}
+	return u, nil
+}
+
+func (m *oidcUserManager) GetUserByID(ctx context.Context, id string) (gimlet.User, error) {
+	u, valid, err := m.cache.Find(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting user by ID")
+	}
+	if u == nil {
+		return nil, errors.New("user not found in cache")
+	}
+	if !valid {
+		if err := m.ReauthorizeUser(ctx, u); err != nil {
+			return u, gimlet.ErrNeedsReauthentication
+		}
+	}
+	return u, nil
+}
+
+func (m *oidcUserManager) CreateUserToken(context.Context, string, string) (string, error) {
+	return "", errors.New("creating user tokens is not supported for OIDC")
+}
+
+func (m *oidcUserManager) GetLoginHandler(string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		provider, err := m.getProvider(r.Context())
+		if err != nil {
+			grip.Error(r.Context(), message.WrapError(err, message.Fields{
+				"message": "could not start OIDC login",
+				"request": gimlet.GetRequestID(r.Context()),
+			}))
+			writeOIDCError(r.Context(), w, err)
+			return
+		}
+
+		redirectURI := getOIDCRedirectPath(r.URL.Query().Get("redirect"))
+		nonce := utility.RandomString()
+		state := utility.RandomString()
+		verifier := oauth2.GenerateVerifier()
+
+		m.setTemporaryCookie(w, oidcNonceCookieName, nonce)
+		m.setTemporaryCookie(w, oidcStateCookieName, state)
+		m.setTemporaryCookie(w, oidcVerifierCookieName, verifier)
+		m.setTemporaryCookie(w, oidcRequestURICookieName, redirectURI)
+
+		opts := []oauth2.AuthCodeOption{oidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier)}
+		// Users who still have a login cookie for an existing user can
+		// silently reauthenticate if their session with the identity provider
+		// is still active.
+		if !m.canSilentReauth(r) {
+			opts = append(opts, oauth2.SetAuthURLParam("prompt", "login"))
+		}
+
+		w.Header().Set("Cache-Control", "no-cache,no-store")
+		http.Redirect(w, r, m.oauthConfig(provider).AuthCodeURL(state, opts...), http.StatusFound)
+	}
+}
+
+// canSilentReauth returns whether the request has a login cookie belonging to
+// an existing user.
+func (m *oidcUserManager) canSilentReauth(r *http.Request) bool {
+	cookie, err := r.Cookie(m.loginCookieName)
+	if err != nil {
+		return false
+	}
+	token, err := url.QueryUnescape(cookie.Value)
+	if err != nil {
+		return false
+	}
+	u, _, err := m.cache.Get(r.Context(), token)
+	return err == nil && u != nil
+}
+
+func (m *oidcUserManager) GetLoginCallbackHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		q := r.URL.Query()
+		if errCode := q.Get("error"); errCode != "" {
+			err := errors.Errorf("callback handler received error from OIDC provider: %s: %s", errCode, q.Get("error_description"))
+			grip.Error(ctx, err)
+			writeOIDCError(ctx, w, err)
+			return
+		}
+
+		nonce, state, verifier, requestURI, err := getOIDCCookies(r)
+		if err != nil {
+			err = errors.Wrap(err, "getting OIDC login state from cookies")
+			grip.Error(ctx, err)
+			writeOIDCError(ctx, w, err)
+			return
+		}
+		if q.Get("state") != state {
+			err = errors.New("state value received from OIDC provider did not match expected state")
+			grip.Error(ctx, message.WrapError(err, message.Fields{
+				"expected_state": state,
+				"actual_state":   q.Get("state"),
+			}))
+			writeOIDCError(ctx, w, err)
+			return
+		}
+
+		u, err := m.login(ctx, q.Get("code"), verifier, nonce)
+		if err != nil {
+			grip.Error(ctx, err)
+			writeOIDCError(ctx, w, err)
+			return
+		}
+		loginToken, err := m.cache.Put(ctx, u)
+		if err != nil {
+			err = errors.Wrapf(err, "caching user '%s'", u.Username())
+			grip.Error(ctx, err)
+			writeOIDCError(ctx, w, err)
+			return
+		}
+
+		m.unsetTemporaryCookie(w, oidcNonceCookieName)
+		m.unsetTemporaryCookie(w, oidcStateCookieName)
+		m.unsetTemporaryCookie(w, oidcVerifierCookieName)
+		m.unsetTemporaryCookie(w, oidcRequestURICookieName)
+		http.SetCookie(w, &http.Cookie{
+			Name:     m.loginCookieName,
+			Path:     m.cookiePath,
+			Value:    loginToken,
+			HttpOnly: true,
+			Expires:  time.Now().Add(m.loginCookieTTL),
+			Domain:   m.cookieDomain,
+			SameSite: http.SameSiteLaxMode,
+		})
+
+		http.Redirect(w, r, getOIDCRedirectPath(requestURI), http.StatusFound)
+	}
+}
+
+// getOIDCRedirectPath returns the path to redirect the user to once they are
+// logged in. Only paths on this host are allowed so that the login flow
+// cannot be used to redirect to other sites; anything else redirects to the
+// home page.
+func getOIDCRedirectPath(redirect string) string {
+	normalized := strings.ReplaceAll(redirect, "\\", "/")
+	u, err := url.Parse(normalized)
+	if err != nil || u.Scheme != "" || u.Host != "" || !strings.HasPrefix(u.Path, "/") {
+		return "/"
+	}
+	return redirect
+}
+
+// login redeems the authorization code for tokens, validates the ID token and
+// returns the persisted user with their mapped roles.
+func (m *oidcUserManager) login(ctx context.Context, code, verifier, nonce string) (gimlet.User, error) {
+	provider, err := m.getProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+	client := m.getHTTPClient()
+	defer m.putHTTPClient(client)
+
+	tokens, err := m.oauthConfig(provider).Exchange(oidc.ClientContext(ctx, client), code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, errors.Wrap(err, "redeeming authorization code for tokens")
+	}
+	claims, err := m.verifyIDToken(ctx, provider, tokens)
+	if err != nil {
+		return nil, err
+	}
+	if claims.nonce != nonce {
+		return nil, errors.New("nonce in ID token did not match expected nonce")
+	}
+
+	u, err := m.makeUser(claims.oidcClaims, tokens)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating user from ID token")
+	}
+	dbUser, err := m.cache.GetOrCreate(ctx, u)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting existing user or creating new user")
+	}
+	if err := m.syncRoles(ctx, dbUser, claims.oidcClaims); err != nil {
+		return nil, errors.Wrapf(err, "updating roles for user '%s'", dbUser.Username())
+	}
+	return dbUser, nil
+}
+
+type verifiedOIDCClaims struct {
+	oidcClaims
+	nonce string
+}
+
+// verifyIDToken validates the signature, issuer, audience and expiration of
+// the ID token returned with the tokens, checks that the user is in the
+// required group and returns its claims.
+func (m *oidcUserManager) verifyIDToken(ctx context.Context, provider *oidcProvider, tokens *oauth2.Token) (*verifiedOIDCClaims, error) {
+	rawIDToken, ok := tokens.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, errors.New("token response is missing ID token")
+	}
+	idToken, err := provider.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid ID token from OIDC provider")
+	}
+	claims := &verifiedOIDCClaims{
+		oidcClaims: oidcClaims{Subject: idToken.Subject},
+		nonce:      idToken.Nonce,
+	}
+	if err = idToken.Claims(&claims.Raw); err != nil {
+		return nil, errors.Wrap(err, "reading ID token claims")
+	}
+	claims.Email, _ = claims.Raw["email"].(string)
+	claims.Name, _ = claims.Raw["name"].(string)
+
+	if m.userGroup != "" && !utility.StringSliceContains(m.groups(claims.oidcClaims), m.userGroup) {
+		grip.Info(ctx, message.Fields{
+			"message":        "user is not in the required OIDC group",
+			"subject":        claims.Subject,
+			"expected_group": m.userGroup,
+		})
+		return nil, errors.Errorf("user is not in the required group '%s'", m.userGroup)
+	}
+	return claims, nil
+}
+
+// username returns the username for the user identified by the claims.
+func (m *oidcUserManager) username(claims oidcClaims) (string, error) {
+	if m.usernameClaim == "" {
+		if claims.Email == "" {
+			return "", errors.New("ID token is missing email claim")
+		}
+		return m.reconciliateID(claims.Email), nil
+	}
+	username, _ := claims.Raw[m.usernameClaim].(string)
+	if username == "" {
+		return "", errors.Errorf("ID token is missing username claim '%s'", m.usernameClaim)
+	}
+	return username, nil
+}
+
+// groups returns the groups in the configured groups claim, which may be
+// nested in other claims.
+func (m *oidcUserManager) groups(claims oidcClaims) []string {
+	var val any = claims.Raw
+	for _, key := range strings.Split(m.groupsClaim, ".") {
+		obj, ok := val.(map[string]any)
+		if !ok {
+			return nil
+		}
+		val = obj[key]
+	}
+
+	switch v := val.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		groups := make([]string, 0, len(v))
+		for _, group := range v {
+			if s, ok := group.(string); ok {
+				groups = append(groups, s)
+			}
+		}
+		return groups
+	default:
+		return nil
+	}
+}
+
+// mappedRoles returns the roles that the user's groups grant and the roles
+// managed by the role mappings that the user's groups do not grant.
+func (m *oidcUserManager) mappedRoles(claims oidcClaims) (grant, revoke []string) {
+	groups := m.groups(claims)
+	for _, mapping := range m.roleMappings {
+		if !utility.StringSliceContains(groups, mapping.Group) {
+			continue
+		}
+		for _, role := range mapping.Roles {
+			if !utility.StringSliceContains(grant, role) {
+				grant = append(grant, role)
+			}
+		}
+	}
+	for _, mapping := range m.roleMappings {
+		for _, role := range mapping.Roles {
+			if !utility.StringSliceContains(grant, role) && !utility.StringSliceContains(revoke, role) {
+				revoke = append(revoke, role)
+			}
+		}
+	}
+	return grant, revoke
+}
+
+func (m *oidcUserManager) makeUser(claims oidcClaims, tokens *oauth2.Token) (gimlet.User, error) {
+	username, err := m.username(claims)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := gimlet.NewBasicUserOptions(username)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating user")
+	}
+	grant, _ := m.mappedRoles(claims)
+	return gimlet.NewBasicUser(opts.
+		Name(claims.Name).
+		Email(claims.Email).
+		AccessToken(tokens.AccessToken).
+		RefreshToken(tokens.RefreshToken).
+		Roles(grant...)), nil
+}
+
+func (m *oidcUserManager) syncRoles(ctx context.Context, u gimlet.User, claims oidcClaims) error {
+	if len(m.roleMappings) == 0 {
+		return nil
+	}
+	grant, revoke := m.mappedRoles(claims)
+	return m.updateRoles(ctx, u, grant, revoke)
+}
+
+// ReauthorizeUser refreshes the user's tokens and checks that the refreshed
+// ID token still belongs to the same user. If the identity provider doesn't
+// return a new ID token, which it isn't required to do (OpenID Connect Core
+// 1.0 section 12.2), the claims verified when the user logged in still apply
+// and only the tokens are updated. If the identity provider rejects the
+// refresh token, the returned error says that the refresh token is invalid or
+// expired so the user can be logged out.
+func (m *oidcUserManager) ReauthorizeUser(ctx context.Context, u gimlet.User) error {
+	refreshToken := u.GetRefreshToken()
+	if refreshToken == "" {
+		return errors.Errorf("user '%s' cannot refresh tokens because refresh token is missing", u.Username())
+	}
+	provider, err := m.getProvider(ctx)
+	if err != nil {
+		return err
+	}
+	client := m.getHTTPClient()
+	defer m.putHTTPClient(client)
+
+	tokens, err := m.oauthConfig(provider).TokenSource(oidc.ClientContext(ctx, client), &oauth2.Token{RefreshToken: refreshToken}).Token()
+	if err != nil {
+		var retrieveErr *oauth2.RetrieveError
+		if errors.As(err, &retrieveErr) && retrieveErr.ErrorCode == "invalid_grant" {
+			return errors.Wrapf(err, "%s for user '%s'", oidcInvalidGrantMessage, u.Username())
+		}
+		return errors.Wrap(err, "refreshing authorization tokens")
+	}
+	if rawIDToken, _ := tokens.Extra("id_token").(string); rawIDToken == "" {
+		opts, err := gimlet.NewBasicUserOptions(u.Username())
+		if err != nil {
+			return errors.Wrap(err, "creating user")
+		}
+		refreshed := gimlet.NewBasicUser(opts.
+			Name(u.DisplayName()).
+			Email(u.Email()).
+			AccessToken(tokens.AccessToken).
+			RefreshToken(tokens.RefreshToken).
+			Roles(u.Roles()...))
+		_, err = m.cache.Put(ctx, refreshed)
+		return errors.Wrap(err, "updating reauthorized user in cache")
+	}
+
+	claims, err := m.verifyIDToken(ctx, provider, tokens)
+	if err != nil {
+		return errors.Wrap(err, "reauthorizing user ID token after refreshing tokens")
+	}
+	username, err := m.username(claims.oidcClaims)
+	if err != nil {
+		return err
+	}
+	if username != u.Username() {
+		return errors.Errorf("user name '%s' from ID token did not match user name '%s' to reauthorize", username, u.Username())
+	}
+
+	refreshed, err := m.makeUser(claims.oidcClaims, tokens)
+	if err != nil {
+		return errors.Wrap(err, "creating user from refreshed ID token")
+	}
+	if _, err = m.cache.Put(ctx, refreshed); err != nil {
+		return errors.Wrap(err, "updating reauthorized user in cache")
+	}
+	return errors.Wrapf(m.syncRoles(ctx, u, claims.oidcClaims), "updating roles for user '%s'", u.Username())
+}
+
+func (m *oidcUserManager) IsRedirect() bool { return true }
+
+func (m *oidcUserManager) GetOrCreateUser(ctx context.Context, u gimlet.User) (gimlet.User, error) {
+	return m.cache.GetOrCreate(ctx, u)
+}
+
+func (m *oidcUserManager) ClearUser(ctx context.Context, u gimlet.User, all bool) error {
+	return m.cache.Clear(ctx, u, all)
+}
+
+func (m *oidcUserManager) GetGroupsForUser(string) ([]string, error) {
+	return nil, errors.New("not implemented")
+}
+
+// setTemporaryCookie sets a short-lived cookie that is required for the login
+// callback to succeed.
+func (m *oidcUserManager) setTemporaryCookie(w http.ResponseWriter, name, value string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Path:     m.cookiePath,
+		Value:    value,
+		HttpOnly: true,
+		Expires:  time.Now().Add(oidcTemporaryCookieTTL),
+		Domain:   m.cookieDomain,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func (m *oidcUserManager) unsetTemporaryCookie(w http.ResponseWriter, name string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:   name,
+		Path:   m.cookiePath,
+		Domain: m.cookieDomain,
+		Value:  "",
+		MaxAge: -1,
+	})
+}
+
+// getOIDCCookies gets the login state set by the login handler from the
+// cookies.
+func getOIDCCookies(r *http.Request) (nonce, state, verifier, requestURI string, err error) {
+	catcher := grip.NewBasicCatcher()
+	get := func(name string) string {
+		cookie, err := r.Cookie(name)
+		if err != nil {
+			return ""
+		}
+		val, err := url.QueryUnescape(cookie.Value)
+		catcher.Wrapf(err, "decoding cookie '%s'", name)
+		return val
+	}
+	nonce = get(oidcNonceCookieName)
+	state = get(oidcStateCookieName)
+	verifier = get(oidcVerifierCookieName)
+	requestURI = get(oidcRequestURICookieName)
+	catcher.NewWhen(nonce == "", "nonce could not be retrieved from cookies")
+	catcher.NewWhen(state == "", "state could not be retrieved from cookies")
+	catcher.NewWhen(verifier == "", "code verifier could not be retrieved from cookies")
+	if requestURI == "" {
+		requestURI = "/"
+	}
+	return nonce, state, verifier, requestURI, catcher.Resolve()
+}
+
+func writeOIDCError(ctx context.Context, w http.ResponseWriter, err error) {
+	gimlet.WriteResponse(ctx, w, gimlet.MakeTextErrorResponder(gimlet.ErrorResponse{
+		StatusCode: http.StatusInternalServerError,
+		Message:    err.Error(),
+	}))
+}
+
+// updateOIDCUserRoles grants and revokes roles for a persisted user.
+func updateOIDCUserRoles(ctx context.Context, u gimlet.User, grant, revoke []string) error {
+	dbUser, err := user.FindOneById(ctx, u.Username())
+	if err != nil {
+		return errors.Wrapf(err, "finding user '%s'", u.Username())
+	}
+	if dbUser == nil {
+		return errors.Errorf("user '%s' not found", u.Username())
+	}
+	catcher := grip.NewBasicCatcher()
+	for _, role := range grant {
+		catcher.Wrapf(dbUser.AddRole(ctx, role), "adding role '%s'", role)
+	}
+	for _, role := range revoke {
+		if utility.StringSliceContains(dbUser.Roles(), role) {
+			catcher.Wrapf(dbUser.RemoveRole(ctx, role), "removing role '%s'", role)
+		}
+	}
+	return catcher.Resolve()
+}
+
+// oidcKeySet verifies ID token signatures using the identity provider's JWKS.
+// The provider may rotate its signing keys before the cached keys expire, so
+// if a signature cannot be verified, the keys are refetched (at most once per
+// refresh interval) and verification is retried.
+type oidcKeySet struct {
+	ctx             context.Context
+	jwksURL         string
+	refreshInterval time.Duration
+
+	mu          sync.Mutex
+	keys        oidc.KeySet
+	lastRefresh time.Time
+}
+
+func newOIDCKeySet(ctx context.Context, jwksURL string, refreshInterval time.Duration) *oidcKeySet {
+	return &oidcKeySet{
+		ctx:             ctx,
+		jwksURL:         jwksURL,
+		refreshInterval: refreshInterval,
+		keys:            oidc.NewRemoteKeySet(ctx, jwksURL),
+		lastRefresh:     time.Now(),
+	}
+}
+
+// VerifySignature implements oidc.KeySet.
+func (ks *oidcKeySet) VerifySignature(ctx context.Context, jwt string) ([]byte, error) {
+	keys := ks.current()
+	payload, err := keys.VerifySignature(ctx, jwt)
+	if err == nil {
+		return payload, nil
+	}
+	refreshed := ks.refresh(keys)
+	if refreshed == nil {
+		return nil, err
+	}
+	payload, err = refreshed.VerifySignature(ctx, jwt)
+	return payload, errors.Wrap(err, "verifying signature after refreshing signing keys")
+}
+
+func (ks *oidcKeySet) current() oidc.KeySet {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	return ks.keys
+}
+
+// refresh discards the cached keys so they are refetched on next use. It
+// returns the new key set, or nil if the keys were refreshed too recently.
+func (ks *oidcKeySet) refresh(stale oidc.KeySet) oidc.KeySet {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if ks.keys != stale {
+		// Another request already refreshed the keys.
+		return ks.keys
+	}
+	if time.Since(ks.lastRefresh) < ks.refreshInterval {
+		return nil
+	}
+	ks.keys = oidc.NewRemoteKeySet(ks.ctx, ks.jwksURL)
+	ks.lastRefresh = time.Now()
+	return ks.keys
+}
@@ -0,0 +1,610 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/gimlet/usercache"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	mockOIDCClientID     = "evergreen"
+	mockOIDCClientSecret = "secret"
+)
+
+// mockOIDCProvider is a minimal OpenID Connect identity provider that
+// supports discovery, the authorization code flow with PKCE, refresh tokens
+// and signing key rotation.
+type mockOIDCProvider struct {
+	t      *testing.T
+	server *httptest.Server
+
+	mu            sync.Mutex
+	keys          map[string]*rsa.PrivateKey
+	signingKeyID  string
+	rotations     int
+	codes         map[string]mockOIDCCode
+	refreshTokens map[string]jwt.MapClaims
+	jwksFetches   int
+	// omitRefreshIDToken makes refresh token responses leave out the ID
+	// token, which OIDC providers are allowed to do.
+	omitRefreshIDToken bool
+}
+
+type mockOIDCCode struct {
+	challenge string
+	nonce     string
+	claims    jwt.MapClaims
+}
+
+func newMockOIDCProvider(t *testing.T) *mockOIDCProvider {
+	p := &mockOIDCProvider{
+		t:             t,
+		keys:          map[string]*rsa.PrivateKey{},
+		codes:         map[string]mockOIDCCode{},
+		refreshTokens: map[string]jwt.MapClaims{},
+	}
+	p.rotateKey()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", p.discovery)
+	mux.HandleFunc("/keys", p.jwks)
+	mux.HandleFunc("/token", p.token)
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+
+	return p
+}
+
+func (p *mockOIDCProvider) issuer() string { return p.server.URL }
+
+// rotateKey replaces the signing key with a new one. The old keys are no
+// longer published.
+func (p *mockOIDCProvider) rotateKey() {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(p.t, err)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rotations++
+	p.signingKeyID = fmt.Sprintf("key%d", p.rotations)
+	p.keys = map[string]*rsa.PrivateKey{p.signingKeyID: key}
+}
+
+// authorize simulates a user authenticating with the identity provider and
+// returns the authorization code that would be sent to the callback.
+func (p *mockOIDCProvider) authorize(authURL string, claims jwt.MapClaims) (code, state string) {
+	parsed, err := url.Parse(authURL)
+	require.NoError(p.t, err)
+	q := parsed.Query()
+	require.Equal(p.t, "S256", q.Get("code_challenge_method"))
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	code = fmt.Sprintf("code%d", len(p.codes)+1)
+	p.codes[code] = mockOIDCCode{
+		challenge: q.Get("code_challenge"),
+		nonce:     q.Get("nonce"),
+		claims:    claims,
+	}
+	return code, q.Get("state")
+}
+
+func (p *mockOIDCProvider) discovery(w http.ResponseWriter, r *http.Request) {
+	writeMockOIDCJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                p.issuer(),
+		"authorization_endpoint":                p.issuer() + "/auth",
+		"token_endpoint":                        p.issuer() + "/token",
+		"jwks_uri":                              p.issuer() + "/keys",
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (p *mockOIDCProvider) jwks(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.jwksFetches++
+
+	var keys []map[string]string
+	for kid, key := range p.keys {
+		keys = append(keys, map[string]string{
+			"kty": "RSA",
+			"use": "sig",
+			"alg": "RS256",
+			"kid": kid,
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+	// Allow the keys to be cached so that key rotation can only be handled
+	// by forcing the keys to be refetched.
+	w.Header().Set("Cache-Control", "max-age=3600")
+	writeMockOIDCJSON(w, http.StatusOK, map[string]any{"keys": keys})
+}
+
+func (p *mockOIDCProvider) token(w http.ResponseWriter, r *http.Request) {
+	require.NoError(p.t, r.ParseForm())
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != mockOIDCClientID || clientSecret != mockOIDCClientSecret {
+		writeMockOIDCJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var claims jwt.MapClaims
+	switch r.PostForm.Get("grant_type") {
+	case "authorization_code":
+		code, ok := p.codes[r.PostForm.Get("code")]
+		delete(p.codes, r.PostForm.Get("code"))
+		sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != code.challenge {
+			writeMockOIDCJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "Code not valid"})
+			return
+		}
+		claims = jwt.MapClaims{"nonce": code.nonce}
+		for k, v := range code.claims {
+			claims[k] = v
+		}
+	case "refresh_token":
+		refreshClaims, ok := p.refreshTokens[r.PostForm.Get("refresh_token")]
+		if !ok {
+			writeMockOIDCJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "Token is not active"})
+			return
+		}
+		claims = jwt.MapClaims{}
+		for k, v := range refreshClaims {
+			claims[k] = v
+		}
+	default:
+		writeMockOIDCJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+
+	now := time.Now()
+	defaults := jwt.MapClaims{
+		"iss": p.issuer(),
+		"aud": mockOIDCClientID,
+		"iat": now.Unix(),
+		"exp": now.Add(time.Hour).Unix(),
+	}
+	for k, v := range defaults {
+		if _, ok := claims[k]; !ok {
+			claims[k] = v
+		}
+	}
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	idToken.Header["kid"] = p.signingKeyID
+	signed, err := idToken.SignedString(p.keys[p.signingKeyID])
+	require.NoError(p.t, err)
+
+	refreshToken := fmt.Sprintf("refresh%d", len(p.refreshTokens)+1)
+	refreshClaims := jwt.MapClaims{}
+	for k, v := range claims {
+		if k != "nonce" && k != "iat" && k != "exp" {
+			refreshClaims[k] = v
+		}
+	}
+	p.refreshTokens[refreshToken] = refreshClaims
+
+	resp := map[string]any{
+		"access_token":  "access-" + refreshToken,
+		"token_type":    "Bearer",
+		"expires_in":    300,
+		"refresh_token": refreshToken,
+		"id_token":      signed,
+	}
+	if r.PostForm.Get("grant_type") == "refresh_token" && p.omitRefreshIDToken {
+		delete(resp, "id_token")
+	}
+	writeMockOIDCJSON(w, http.StatusOK, resp)
+}
+
+// setRefreshClaims replaces the claims returned when refreshing the given
+// refresh token.
+func (p *mockOIDCProvider) setRefreshClaims(refreshToken string, claims jwt.MapClaims) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.refreshTokens[refreshToken] = claims
+}
+
+func (p *mockOIDCProvider) setOmitRefreshIDToken(omit bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.omitRefreshIDToken = omit
+}
+
+func (p *mockOIDCProvider) revokeRefreshToken(refreshToken string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.refreshTokens, refreshToken)
+}
+
+func (p *mockOIDCProvider) getJWKSFetches() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.jwksFetches
+}
+
+func writeMockOIDCJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+type mockRoleUpdate struct {
+	user   string
+	grant  []string
+	revoke []string
+}
+
+func TestOIDCUserManager(t *testing.T) {
+	setup := func(t *testing.T, conf evergreen.OIDCConfig) (*oidcUserManager, *mockOIDCProvider, *[]mockRoleUpdate) {
+		provider := newMockOIDCProvider(t)
+		conf.Issuer = provider.issuer()
+		conf.ClientID = mockOIDCClientID
+		conf.ClientSecret = mockOIDCClientSecret
+
+		var roleUpdates []mockRoleUpdate
+		updateRoles := func(_ context.Context, u gimlet.User, grant, revoke []string) error {
+			roleUpdates = append(roleUpdates, mockRoleUpdate{user: u.Username(), grant: grant, revoke: revoke})
+			return nil
+		}
+		m, err := newOIDCUserManager(&conf, "https://evergreen.example.com/login/redirect/callback", "example.com", usercache.NewInMemory(t.Context(), time.Hour), updateRoles)
+		require.NoError(t, err)
+		m.getHTTPClient = func() *http.Client { return provider.server.Client() }
+		m.putHTTPClient = func(*http.Client) {}
+		m.keyRefreshInterval = 0
+		return m, provider, &roleUpdates
+	}
+
+	startLogin := func(t *testing.T, m *oidcUserManager) *httptest.ResponseRecorder {
+		rw := httptest.NewRecorder()
+		m.GetLoginHandler("")(rw, httptest.NewRequest(http.MethodGet, "/login/redirect?redirect=%2Fwaterfall%2Fevergreen", nil))
+		require.Equal(t, http.StatusFound, rw.Code)
+		return rw
+	}
+
+	callback := func(t *testing.T, m *oidcUserManager, cookies []*http.Cookie, code, state string) *httptest.ResponseRecorder {
+		q := url.Values{}
+		q.Set("code", code)
+		q.Set("state", state)
+		r := httptest.NewRequest(http.MethodGet, "/login/redirect/callback?"+q.Encode(), nil)
+		for _, c := range cookies {
+			r.AddCookie(c)
+		}
+		rw := httptest.NewRecorder()
+		m.GetLoginCallbackHandler()(rw, r)
+		return rw
+	}
+
+	login := func(t *testing.T, m *oidcUserManager, p *mockOIDCProvider, claims jwt.MapClaims) *httptest.ResponseRecorder {
+		rw := startLogin(t, m)
+		code, state := p.authorize(rw.Header().Get("Location"), claims)
+		return callback(t, m, rw.Result().Cookies(), code, state)
+	}
+
+	loginToken := func(t *testing.T, rw *httptest.ResponseRecorder) string {
+		for _, c := range rw.Result().Cookies() {
+			if c.Name == evergreen.AuthTokenCookie {
+				return c.Value
+			}
+		}
+		require.FailNow(t, "login cookie was not set")
+		return ""
+	}
+
+	alice := jwt.MapClaims{
+		"sub":                "1234",
+		"email":              "alice@example.com",
+		"name":               "Alice",
+		"preferred_username": "alice",
+		"groups":             []string{"evergreen-users", "evergreen-admins"},
+	}
+
+	t.Run("LoginRedirectUsesPKCE", func(t *testing.T) {
+		m, p, _ := setup(t, evergreen.OIDCConfig{})
+		rw := startLogin(t, m)
+
+		location, err := url.Parse(rw.Header().Get("Location"))
+		require.NoError(t, err)
+		assert.Equal(t, p.issuer()+"/auth", location.Scheme+"://"+location.Host+location.Path)
+		q := location.Query()
+		assert.Equal(t, mockOIDCClientID, q.Get("client_id"))
+		assert.Equal(t, "code", q.Get("response_type"))
+		assert.Equal(t, "https://evergreen.example.com/login/redirect/callback", q.Get("redirect_uri"))
+		assert.ElementsMatch(t, []string{"openid", "profile", "email", "offline_access"}, strings.Fields(q.Get("scope")))
+		assert.Equal(t, "S256", q.Get("code_challenge_method"))
+		assert.NotEmpty(t, q.Get("code_challenge"))
+		assert.NotEmpty(t, q.Get("nonce"))
+		assert.NotEmpty(t, q.Get("state"))
+		assert.Equal(t, "login", q.Get("prompt"))
+
+		cookies := map[string]string{}
+		for _, c := range rw.Result().Cookies() {
+			cookies[c.Name] = c.Value
+		}
+		assert.Equal(t, q.Get("state"), cookies[oidcStateCookieName])
+		assert.Equal(t, q.Get("nonce"), cookies[oidcNonceCookieName])
+		assert.Equal(t, "/waterfall/evergreen", cookies[oidcRequestURICookieName])
+		assert.NotEqual(t, q.Get("code_challenge"), cookies[oidcVerifierCookieName], "verifier should not be sent to the provider")
+	})
+	t.Run("LoginRedirectsOnlyWithinSite", func(t *testing.T) {
+		m, p, _ := setup(t, evergreen.OIDCConfig{})
+		rw := httptest.NewRecorder()
+		m.GetLoginHandler("")(rw, httptest.NewRequest(http.MethodGet, "/login/redirect?redirect="+url.QueryEscape("https://evil.example.com"), nil))
+		require.Equal(t, http.StatusFound, rw.Code)
+		cookies := rw.Result().Cookies()
+		for _, c := range cookies {
+			if c.Name == oidcRequestURICookieName {
+				assert.Equal(t, "/", c.Value)
+			}
+		}
+
+		code, state := p.authorize(rw.Header().Get("Location"), alice)
+		for _, c := range cookies {
+			if c.Name == oidcRequestURICookieName {
+				c.Value = "//evil.example.com"
+			}
+		}
+		rw = callback(t, m, cookies, code, state)
+		require.Equal(t, http.StatusFound, rw.Code, rw.Body.String())
+		assert.Equal(t, "/", rw.Header().Get("Location"))
+	})
+	t.Run("LoginCreatesUserWithMappedRoles", func(t *testing.T) {
+		m, p, roleUpdates := setup(t, evergreen.OIDCConfig{
+			UsernameClaim: "preferred_username",
+			RoleMappings: []evergreen.OIDCRoleMapping{
+				{Group: "evergreen-admins", Roles: []string{"superuser"}},
+				{Group: "evergreen-users", Roles: []string{"basic_project_access"}},
+				{Group: "release-managers", Roles: []string{"release_admin"}},
+			},
+		})
+		rw := login(t, m, p, alice)
+		require.Equal(t, http.StatusFound, rw.Code, rw.Body.String())
+		assert.Equal(t, "/waterfall/evergreen", rw.Header().Get("Location"))
+
+		u, err := m.GetUserByToken(t.Context(), loginToken(t, rw))
+		require.NoError(t, err)
+		assert.Equal(t, "alice", u.Username())
+		assert.Equal(t, "alice@example.com", u.Email())
+		assert.Equal(t, "Alice", u.DisplayName())
+		assert.NotEmpty(t, u.GetRefreshToken())
+		assert.ElementsMatch(t, []string{"superuser", "basic_project_access"}, u.Roles())
+
+		require.Len(t, *roleUpdates, 1)
+		assert.Equal(t, "alice", (*roleUpdates)[0].user)
+		assert.ElementsMatch(t, []string{"superuser", "basic_project_access"}, (*roleUpdates)[0].grant)
+		assert.Equal(t, []string{"release_admin"}, (*roleUpdates)[0].revoke)
+
+		for _, c := range rw.Result().Cookies() {
+			if c.Name == oidcNonceCookieName || c.Name == oidcVerifierCookieName || c.Name == oidcStateCookieName {
+				assert.Negative(t, c.MaxAge, "temporary cookie '%s' should be unset", c.Name)
+			}
+		}
+	})
+	t.Run("UsernameDefaultsToReconciledEmail", func(t *testing.T) {
+		m, p, roleUpdates := setup(t, evergreen.OIDCConfig{ExpectedEmailDomains: []string{"example.com"}})
+		rw := login(t, m, p, alice)
+		require.Equal(t, http.StatusFound, rw.Code, rw.Body.String())
+
+		u, err := m.GetUserByToken(t.Context(), loginToken(t, rw))
+		require.NoError(t, err)
+		assert.Equal(t, "alice", u.Username())
+		assert.Empty(t, *roleUpdates, "roles should not be updated without role mappings")
+	})
+	t.Run("NestedGroupsClaim", func(t *testing.T) {
+		m, p, _ := setup(t, evergreen.OIDCConfig{
+			UsernameClaim: "preferred_username",
+			GroupsClaim:   "realm_access.roles",
+			UserGroup:     "evergreen-users",
+		})
+		claims := jwt.MapClaims{
+			"sub":                "1234",
+			"email":              "alice@example.com",
+			"preferred_username": "alice",
+			"realm_access":       map[string]any{"roles": []string{"evergreen-users"}},
+		}
+		rw := login(t, m, p, claims)
+		assert.Equal(t, http.StatusFound, rw.Code, rw.Body.String())
+	})
+	t.Run("RejectsUserNotInGroup", func(t *testing.T) {
+		m, p, _ := setup(t, evergreen.OIDCConfig{UserGroup: "evergreen-operators"})
+		rw := login(t, m, p, alice)
+		assert.Equal(t, http.StatusInternalServerError, rw.Code)
+		assert.Contains(t, rw.Body.String(), "required group")
+	})
+	t.Run("RejectsStateMismatch", func(t *testing.T) {
+		m, p, _ := setup(t, evergreen.OIDCConfig{})
+		rw := startLogin(t, m)
+		code, _ := p.authorize(rw.Header().Get("Location"), alice)
+		rw = callback(t, m, rw.Result().Cookies(), code, "forged")
+		assert.Equal(t, http.StatusInternalServerError, rw.Code)
+		assert.Contains(t, rw.Body.String(), "state")
+	})
+	t.Run("RejectsWrongCodeVerifier", func(t *testing.T) {
+		m, p, _ := setup(t, evergreen.OIDCConfig{})
+		rw := startLogin(t, m)
+		code, state := p.authorize(rw.Header().Get("Location"), alice)
+		var cookies []*http.Cookie
+		for _, c := range rw.Result().Cookies() {
+			if c.Name == oidcVerifierCookieName {
+				c.Value = "intercepted-code-without-the-right-verifier-0123456789"
+			}
+			cookies = append(cookies, c)
+		}
+		rw = callback(t, m, cookies, code, state)
+		assert.Equal(t, http.StatusInternalServerError, rw.Code)
+		assert.Contains(t, rw.Body.String(), "redeeming authorization code")
+	})
+	t.Run("RejectsNonceMismatch", func(t *testing.T) {
+		m, p, _ := setup(t, evergreen.OIDCConfig{})
+		claims := jwt.MapClaims{"nonce": "replayed"}
+		for k, v := range alice {
+			claims[k] = v
+		}
+		rw := login(t, m, p, claims)
+		assert.Equal(t, http.StatusInternalServerError, rw.Code)
+		assert.Contains(t, rw.Body.String(), "nonce")
+	})
+	t.Run("RejectsInvalidIDTokens", func(t *testing.T) {
+		for tName, override := range map[string]jwt.MapClaims{
+			"Expired":       {"exp": time.Now().Add(-time.Hour).Unix()},
+			"WrongAudience": {"aud": "another-client"},
+			"WrongIssuer":   {"iss": "https://idp.example.com"},
+		} {
+			t.Run(tName, func(t *testing.T) {
+				m, p, _ := setup(t, evergreen.OIDCConfig{})
+				claims := jwt.MapClaims{}
+				for k, v := range alice {
+					claims[k] = v
+				}
+				for k, v := range override {
+					claims[k] = v
+				}
+				rw := login(t, m, p, claims)
+				assert.Equal(t, http.StatusInternalServerError, rw.Code)
+				assert.Contains(t, rw.Body.String(), "invalid ID token")
+			})
+		}
+	})
+	t.Run("RefetchesKeysAfterRotation", func(t *testing.T) {
+		m, p, _ := setup(t, evergreen.OIDCConfig{})
+		rw := login(t, m, p, alice)
+		require.Equal(t, http.StatusFound, rw.Code, rw.Body.String())
+		fetches := p.getJWKSFetches()
+
+		p.rotateKey()
+		rw = login(t, m, p, alice)
+		require.Equal(t, http.StatusFound, rw.Code, rw.Body.String())
+		assert.Greater(t, p.getJWKSFetches(), fetches, "keys should be refetched after rotation")
+	})
+	t.Run("DoesNotRefetchKeysTooOften", func(t *testing.T) {
+		m, p, _ := setup(t, evergreen.OIDCConfig{})
+		m.keyRefreshInterval = time.Hour
+		rw := login(t, m, p, alice)
+		require.Equal(t, http.StatusFound, rw.Code, rw.Body.String())
+
+		p.rotateKey()
+		rw = login(t, m, p, alice)
+		assert.Equal(t, http.StatusInternalServerError, rw.Code)
+	})
+	t.Run("ReauthorizeRefreshesTokensAndRoles", func(t *testing.T) {
+		m, p, roleUpdates := setup(t, evergreen.OIDCConfig{
+			UsernameClaim: "preferred_username",
+			RoleMappings:  []evergreen.OIDCRoleMapping{{Group: "evergreen-admins", Roles: []string{"superuser"}}},
+		})
+		rw := login(t, m, p, alice)
+		require.Equal(t, http.StatusFound, rw.Code, rw.Body.String())
+		u, err := m.GetUserByToken(t.Context(), loginToken(t, rw))
+		require.NoError(t, err)
+
+		demoted := jwt.MapClaims{}
+		for k, v := range alice {
+			demoted[k] = v
+		}
+		demoted["groups"] = []string{"evergreen-users"}
+		p.setRefreshClaims(u.GetRefreshToken(), demoted)
+
+		require.NoError(t, m.ReauthorizeUser(t.Context(), u))
+		reauthed, err := m.GetUserByID(t.Context(), "alice")
+		require.NoError(t, err)
+		assert.NotEqual(t, u.GetRefreshToken(), reauthed.GetRefreshToken())
+		assert.Empty(t, reauthed.Roles())
+
+		require.Len(t, *roleUpdates, 2)
+		assert.Empty(t, (*roleUpdates)[1].grant)
+		assert.Equal(t, []string{"superuser"}, (*roleUpdates)[1].revoke)
+	})
+	t.Run("ReauthorizeWithoutIDTokenKeepsClaims", func(t *testing.T) {
+		m, p, roleUpdates := setup(t, evergreen.OIDCConfig{
+			UsernameClaim: "preferred_username",
+			RoleMappings:  []evergreen.OIDCRoleMapping{{Group: "evergreen-admins", Roles: []string{"superuser"}}},
+		})
+		rw := login(t, m, p, alice)
+		require.Equal(t, http.StatusFound, rw.Code, rw.Body.String())
+		u, err := m.GetUserByToken(t.Context(), loginToken(t, rw))
+		require.NoError(t, err)
+
+		p.setOmitRefreshIDToken(true)
+		require.NoError(t, m.ReauthorizeUser(t.Context(), u))
+		reauthed, err := m.GetUserByID(t.Context(), "alice")
+		require.NoError(t, err)
+		assert.NotEqual(t, u.GetRefreshToken(), reauthed.GetRefreshToken())
+		assert.NotEqual(t, u.GetAccessToken(), reauthed.GetAccessToken())
+		assert.Equal(t, "Alice", reauthed.DisplayName())
+		assert.Equal(t, "alice@example.com", reauthed.Email())
+		assert.Equal(t, []string{"superuser"}, reauthed.Roles())
+		assert.Len(t, *roleUpdates, 1, "roles should not change without new claims")
+	})
+	t.Run("ReauthorizeFailsForDifferentUser", func(t *testing.T) {
+		m, p, _ := setup(t, evergreen.OIDCConfig{UsernameClaim: "preferred_username"})
+		rw := login(t, m, p, alice)
+		require.Equal(t, http.StatusFound, rw.Code, rw.Body.String())
+		u, err := m.GetUserByToken(t.Context(), loginToken(t, rw))
+		require.NoError(t, err)
+
+		p.setRefreshClaims(u.GetRefreshToken(), jwt.MapClaims{"sub": "5678", "preferred_username": "mallory"})
+		err = m.ReauthorizeUser(t.Context(), u)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "did not match")
+	})
+	t.Run("ReauthorizeReportsInvalidRefreshToken", func(t *testing.T) {
+		m, p, _ := setup(t, evergreen.OIDCConfig{})
+		rw := login(t, m, p, alice)
+		require.Equal(t, http.StatusFound, rw.Code, rw.Body.String())
+		u, err := m.GetUserByToken(t.Context(), loginToken(t, rw))
+		require.NoError(t, err)
+
+		p.revokeRefreshToken(u.GetRefreshToken())
+		err = m.ReauthorizeUser(t.Context(), u)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid_grant")
+		assert.Contains(t, err.Error(), oidcInvalidGrantMessage)
+	})
+	t.Run("DiscoveryFailureDoesNotPreventConstruction", func(t *testing.T) {
+		m, err := newOIDCUserManager(&evergreen.OIDCConfig{Issuer: "http://127.0.0.1:1", ClientID: mockOIDCClientID}, "https://evergreen.example.com/login/redirect/callback", "", usercache.NewInMemory(t.Context(), time.Hour), nil)
+		require.NoError(t, err)
+		rw := httptest.NewRecorder()
+		m.GetLoginHandler("")(rw, httptest.NewRequest(http.MethodGet, "/login/redirect", nil))
+		assert.Equal(t, http.StatusInternalServerError, rw.Code)
+	})
+}
+
+func TestGetOIDCRedirectPath(t *testing.T) {
+	for redirect, expected := range map[string]string{
+		"":                           "/",
+		"/waterfall/evergreen":       "/waterfall/evergreen",
+		"/task/abc?execution=1#logs": "/task/abc?execution=1#logs",
+		"https://evil.example.com":   "/",
+		"//evil.example.com":         "/",
+		"/\\evil.example.com":        "/",
+		"\\\\evil.example.com":       "/",
+		"javascript:alert(1)":        "/",
+		"waterfall":                  "/",
+	} {
+		assert.Equal(t, expected, getOIDCRedirectPath(redirect), redirect)
+	}
+}
@@ -138,6 +138,8 @@ func GetSettings(provider string) (ProviderSettings, error) {
 		return &MockProviderSettings{}, nil
 	case evergreen.ProviderNameDocker, evergreen.ProviderNameDockerMock:
 		return &dockerSettings{}, nil
+	case evergreen.ProviderNamePodman, evergreen.ProviderNamePodmanMock:
+		return &PodmanSettings{}, nil
 	}
 	return nil, errors.Errorf("invalid provider name '%s'", provider)
 }
@@ -165,6 +167,10 @@ func GetManager(ctx context.Context, env evergreen.Environment, mgrOpts ManagerO
 		provider = &dockerManager{env: env}
 	case evergreen.ProviderNameDockerMock:
 		provider = &dockerManager{env: env, client: &dockerClientMock{}}
+	case evergreen.ProviderNamePodman:
+		provider = &podmanManager{env: env}
+	case evergreen.ProviderNamePodmanMock:
+		provider = &podmanManager{env: env, client: newPodmanClientMock()}
 	default:
 		return nil, errors.Errorf("no known provider '%s'", mgrOpts.Provider)
 	}
@@ -0,0 +1,455 @@
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// defaultPodmanAgentPath is the path to the Evergreen binary in the
+	// container image if the distro doesn't specify one.
+	defaultPodmanAgentPath = "/evergreen"
+	// podmanVolumeMountDir is the directory in the container under which
+	// volumes are mounted if the attachment doesn't specify a mount path.
+	podmanVolumeMountDir = "/mnt"
+)
+
+// podmanManager implements the Manager and BatchManager interfaces for hosts
+// that are containers run by a local container runtime such as Podman. Unlike
+// the Docker provider, the containers don't need a parent host; they run
+// directly on the machine that serves the runtime's API socket, which allows
+// a fully self-contained Evergreen deployment on a single machine.
+type podmanManager struct {
+	client podmanClient
+	env    evergreen.Environment
+}
+
+// PodmanSettings are the distro provider settings for the Podman provider.
+type PodmanSettings struct {
+	// ImageURL is the container image to run. It must contain the Evergreen
+	// binary at AgentPath.
+	ImageURL string `mapstructure:"image_url" json:"image_url" bson:"image_url"`
+	// AgentPath is the path to the Evergreen binary in the image.
+	AgentPath string `mapstructure:"agent_path" json:"agent_path,omitempty" bson:"agent_path,omitempty"`
+	// Command, if set, overrides the command that starts the agent.
+	Command []string `mapstructure:"command" json:"command,omitempty" bson:"command,omitempty"`
+	// EnvironmentVars are additional environment variables in the form
+	// key=value to set in the container.
+	EnvironmentVars []string `mapstructure:"environment_vars" json:"environment_vars,omitempty" bson:"environment_vars,omitempty"`
+	// Network is the network mode for the container (e.g. "host").
+	Network string `mapstructure:"network" json:"network,omitempty" bson:"network,omitempty"`
+	// Privileged is whether the container runs with extended privileges.
+	Privileged bool `mapstructure:"privileged" json:"privileged,omitempty" bson:"privileged,omitempty"`
+}
+
+// Validate checks that the settings are valid.
+func (s *PodmanSettings) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	catcher.NewWhen(s.ImageURL == "", "image URL must be set")
+	catcher.ErrorfWhen(s.AgentPath != "" && !path.IsAbs(s.AgentPath), "agent path '%s' must be absolute", s.AgentPath)
+	return catcher.Resolve()
+}
+
+// FromDistroSettings loads the Podman settings from the distro.
+func (s *PodmanSettings) FromDistroSettings(d distro.Distro, _ string) error {
+	if len(d.ProviderSettingsList) != 0 {
+		bytes, err := d.ProviderSettingsList[0].MarshalBSON()
+		if err != nil {
+			return errors.Wrap(err, "marshalling provider setting into BSON")
+		}
+		if err := bson.Unmarshal(bytes, s); err != nil {
+			return errors.Wrap(err, "unmarshalling BSON into provider settings")
+		}
+	}
+	return nil
+}
+
+// Configure connects the manager to the container runtime's socket.
+func (m *podmanManager) Configure(ctx context.Context, s *evergreen.Settings) error {
+	if m.env == nil {
+		return errors.New("Podman manager requires a non-nil Evergreen environment")
+	}
+	if m.client == nil {
+		m.client = &podmanClientImpl{}
+	}
+	config := s.Providers.Podman
+	return errors.Wrap(m.client.Init(config.SocketPath, config.APIVersion), "initializing Podman client")
+}
+
+// SpawnHost creates and starts a new container for the host. The container
+// runs the agent directly, so the host is provisioned as soon as it starts.
+func (m *podmanManager) SpawnHost(ctx context.Context, h *host.Host) (*host.Host, error) {
+	if !evergreen.IsPodmanProvider(h.Distro.Provider) {
+		return nil, errors.Errorf("can't spawn instance of provider '%s' for distro '%s': distro provider is '%s'", evergreen.ProviderNamePodman, h.Distro.Id, h.Distro.Provider)
+	}
+
+	if h.Secret == "" {
+		if err := h.CreateSecret(ctx, false); err != nil {
+			return nil, errors.Wrapf(err, "creating secret for host '%s'", h.Id)
+		}
+	}
+
+	opts, err := m.containerOptions(h, h.Volumes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting container options for host '%s'", h.Id)
+	}
+	if err := m.createAndStartContainer(ctx, opts); err != nil {
+		grip.Info(ctx, message.WrapError(err, message.Fields{
+			"message": "spawn Podman container host failed",
+			"host_id": h.Id,
+		}))
+		return nil, err
+	}
+
+	if err := h.SetAgentRevision(ctx, evergreen.AgentVersion); err != nil {
+		return nil, errors.Wrapf(err, "setting agent revision on host '%s' to '%s'", h.Id, evergreen.AgentVersion)
+	}
+	if err := h.MarkAsProvisioned(ctx); err != nil {
+		return nil, errors.Wrapf(err, "marking host '%s' as provisioned", h.Id)
+	}
+
+	grip.Info(ctx, message.Fields{
+		"message": "created and started Podman container",
+		"host_id": h.Id,
+		"image":   opts.Image,
+	})
+
+	return h, nil
+}
+
+// containerOptions returns the options to create the container that backs
+// the host with the given volumes mounted.
+func (m *podmanManager) containerOptions(h *host.Host, volumes []host.VolumeAttachment) (podmanContainerOptions, error) {
+	settings := &PodmanSettings{}
+	if err := settings.FromDistroSettings(h.Distro, ""); err != nil {
+		return podmanContainerOptions{}, errors.Wrap(err, "getting provider settings from distro")
+	}
+	if err := settings.Validate(); err != nil {
+		return podmanContainerOptions{}, errors.Wrap(err, "invalid provider settings")
+	}
+
+	cmd := settings.Command
+	if len(cmd) == 0 {
+		agentPath := settings.AgentPath
+		if agentPath == "" {
+			agentPath = defaultPodmanAgentPath
+		}
+		cmd = h.AgentCommand(m.env.Settings(), agentPath)
+	}
+
+	mounts := make([]podmanMount, 0, len(volumes))
+	for _, v := range volumes {
+		mounts = append(mounts, podmanMount{
+			VolumeName: v.VolumeID,
+			Target:     podmanVolumeMountPath(v),
+		})
+	}
+
+	return podmanContainerOptions{
+		Name:       h.Id,
+		Image:      settings.ImageURL,
+		Cmd:        cmd,
+		Env:        append(append([]string{}, settings.EnvironmentVars...), h.AgentEnvSlice()...),
+		Labels:     map[string]string{podmanHostIDLabel: h.Id},
+		Mounts:     mounts,
+		Network:    settings.Network,
+		Privileged: settings.Privileged,
+	}, nil
+}
+
+// podmanVolumeMountPath returns the path in the container where the volume is
+// mounted. The attachment's device name is used if it's an absolute path.
+func podmanVolumeMountPath(v host.VolumeAttachment) string {
+	if path.IsAbs(v.DeviceName) {
+		return v.DeviceName
+	}
+	return path.Join(podmanVolumeMountDir, v.VolumeID)
+}
+
+func (m *podmanManager) createAndStartContainer(ctx context.Context, opts podmanContainerOptions) error {
+	if err := m.client.CreateContainer(ctx, opts); err != nil {
+		return errors.Wrapf(err, "creating container for host '%s'", opts.Name)
+	}
+	if err := m.client.StartContainer(ctx, opts.Name); err != nil {
+		catcher := grip.NewBasicCatcher()
+		catcher.Wrapf(err, "starting container for host '%s'", opts.Name)
+		catcher.Wrap(m.client.RemoveContainer(ctx, opts.Name), "removing container due to failure to start container")
+		return catcher.Resolve()
+	}
+	return nil
+}
+
+// recreateContainer replaces the host's container with a new one that mounts
+// the given volumes. Mounts can't be changed on an existing container, so this
+// is how volumes are attached and detached. Anything written outside of a
+// volume is lost.
+func (m *podmanManager) recreateContainer(ctx context.Context, h *host.Host, volumes []host.VolumeAttachment) error {
+	opts, err := m.containerOptions(h, volumes)
+	if err != nil {
+		return errors.Wrapf(err, "getting container options for host '%s'", h.Id)
+	}
+	if err := m.client.RemoveContainer(ctx, h.Id); err != nil && !isPodmanNotFound(err) {
+		return errors.Wrapf(err, "removing existing container for host '%s'", h.Id)
+	}
+	return m.createAndStartContainer(ctx, opts)
+}
+
+func (m *podmanManager) ModifyHost(context.Context, *host.Host, host.HostModifyOptions) error {
+	return errors.New("can't modify instances with Podman provider")
+}
+
+// GetInstanceState returns the status of the host's container.
+func (m *podmanManager) GetInstanceState(ctx context.Context, h *host.Host) (CloudInstanceState, error) {
+	info := CloudInstanceState{Status: StatusUnknown}
+	c, err := m.client.GetContainer(ctx, h.Id)
+	if err != nil {
+		if isPodmanNotFound(err) {
+			info.Status = StatusNonExistent
+			return info, nil
+		}
+		return info, errors.Wrapf(err, "getting container information for host '%s'", h.Id)
+	}
+	if c.ContainerJSONBase == nil || c.State == nil {
+		return info, nil
+	}
+	info.Status = podmanContainerStatus(string(c.State.Status))
+	if c.State.Error != "" {
+		info.StateReason = c.State.Error
+	} else if c.State.OOMKilled {
+		info.StateReason = "Out of memory"
+	}
+	return info, nil
+}
+
+// GetInstanceStatuses returns the statuses of the hosts' containers. Hosts
+// without a container are StatusNonExistent.
+func (m *podmanManager) GetInstanceStatuses(ctx context.Context, hosts []host.Host) (map[string]CloudStatus, error) {
+	containers, err := m.client.ListContainers(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing containers")
+	}
+	containerStatuses := make(map[string]CloudStatus, len(containers))
+	for _, c := range containers {
+		if hostID, ok := c.Labels[podmanHostIDLabel]; ok {
+			containerStatuses[hostID] = podmanContainerStatus(string(c.State))
+		}
+	}
+
+	statuses := make(map[string]CloudStatus, len(hosts))
+	for _, h := range hosts {
+		status, ok := containerStatuses[h.Id]
+		if !ok {
+			status = StatusNonExistent
+		}
+		statuses[h.Id] = status
+	}
+	return statuses, nil
+}
+
+// GetDNSName returns the container's name, which resolves to the container
+// on networks managed by the runtime.
+func (m *podmanManager) GetDNSName(ctx context.Context, h *host.Host) (string, error) {
+	return h.Id, nil
+}
+
+// TerminateInstance removes the host's container. Its volumes are kept.
+func (m *podmanManager) TerminateInstance(ctx context.Context, h *host.Host, user, reason string) error {
+	if h.Status == evergreen.HostTerminated {
+		return errors.Errorf("cannot terminate host '%s' because it's already marked as terminated", h.Id)
+	}
+
+	if err := m.client.RemoveContainer(ctx, h.Id); err != nil && !isPodmanNotFound(err) {
+		return errors.Wrapf(err, "removing container for host '%s'", h.Id)
+	}
+
+	grip.Info(ctx, message.Fields{
+		"message": "terminated Podman container",
+		"host_id": h.Id,
+		"user":    user,
+		"reason":  reason,
+	})
+
+	return errors.Wrap(h.Terminate(ctx, user, reason), "terminating host in DB")
+}
+
+// StopInstance stops the host's container without removing it.
+func (m *podmanManager) StopInstance(ctx context.Context, h *host.Host, shouldKeepOff bool, user string) error {
+	if !utility.StringSliceContains(evergreen.StoppableHostStatuses, h.Status) {
+		return errors.Errorf("host cannot be stopped because its status ('%s') is not a stoppable state", h.Status)
+	}
+	if err := m.client.StopContainer(ctx, h.Id); err != nil {
+		return errors.Wrapf(err, "stopping container for host '%s'", h.Id)
+	}
+
+	grip.Info(ctx, message.Fields{
+		"message":       "stopped instance",
+		"user":          user,
+		"host_provider": h.Distro.Provider,
+		"host_id":       h.Id,
+		"distro":        h.Distro.Id,
+	})
+
+	return errors.Wrap(h.SetStopped(ctx, shouldKeepOff, user), "marking DB host as stopped")
+}
+
+// StartInstance starts the host's stopped container.
+func (m *podmanManager) StartInstance(ctx context.Context, h *host.Host, user string) error {
+	if !utility.StringSliceContains(evergreen.StartableHostStatuses, h.Status) {
+		return errors.Errorf("host cannot be started because its status ('%s') is not a startable state", h.Status)
+	}
+	if err := m.client.StartContainer(ctx, h.Id); err != nil {
+		return errors.Wrapf(err, "starting container for host '%s'", h.Id)
+	}
+
+	grip.Info(ctx, message.Fields{
+		"message":       "started instance",
+		"user":          user,
+		"host_provider": h.Distro.Provider,
+		"host_id":       h.Id,
+		"distro":        h.Distro.Id,
+	})
+
+	return errors.Wrap(h.SetRunning(ctx, user), "marking DB host as running")
+}
+
+// RebootInstance restarts the host's container.
+func (m *podmanManager) RebootInstance(ctx context.Context, h *host.Host, user string) error {
+	if h.Status != evergreen.HostRunning {
+		return errors.Errorf("host cannot be rebooted because its status ('%s') is not a rebootable state", h.Status)
+	}
+	if err := m.client.RestartContainer(ctx, h.Id); err != nil {
+		return errors.Wrapf(err, "restarting container for host '%s'", h.Id)
+	}
+
+	grip.Info(ctx, message.Fields{
+		"message":       "rebooted instance",
+		"user":          user,
+		"host_provider": h.Distro.Provider,
+		"host_id":       h.Id,
+		"distro":        h.Distro.Id,
+	})
+
+	return nil
+}
+
+// AttachVolume mounts the named volume in the host's container. Since mounts
+// are fixed when a container is created, the container is recreated.
+func (m *podmanManager) AttachVolume(ctx context.Context, h *host.Host, attachment *host.VolumeAttachment) error {
+	if attachment == nil || attachment.VolumeID == "" {
+		return errors.New("volume attachment must specify a volume ID")
+	}
+	for _, v := range h.Volumes {
+		if v.VolumeID == attachment.VolumeID {
+			return errors.Errorf("volume '%s' is already attached to host '%s'", attachment.VolumeID, h.Id)
+		}
+	}
+
+	volumes := append(append([]host.VolumeAttachment{}, h.Volumes...), *attachment)
+	if err := m.recreateContainer(ctx, h, volumes); err != nil {
+		return errors.Wrapf(err, "recreating container to attach volume '%s'", attachment.VolumeID)
+	}
+
+	return errors.Wrap(h.AddVolumeToHost(ctx, attachment), "adding volume to host in DB")
+}
+
+// DetachVolume unmounts the named volume from the host's container by
+// recreating the container without it.
+func (m *podmanManager) DetachVolume(ctx context.Context, h *host.Host, volumeID string) error {
+	remaining := make([]host.VolumeAttachment, 0, len(h.Volumes))
+	for _, v := range h.Volumes {
+		if v.VolumeID != volumeID {
+			remaining = append(remaining, v)
+		}
+	}
+	if len(remaining) == len(h.Volumes) {
+		return errors.Errorf("volume '%s' is not attached to host '%s'", volumeID, h.Id)
+	}
+
+	if err := m.recreateContainer(ctx, h, remaining); err != nil {
+		return errors.Wrapf(err, "recreating container to detach volume '%s'", volumeID)
+	}
+
+	return errors.Wrap(h.RemoveVolumeFromHost(ctx, volumeID), "removing volume from host in DB")
+}
+
+// CreateVolume creates a named container volume.
+func (m *podmanManager) CreateVolume(ctx context.Context, volume *host.Volume) (*host.Volume, error) {
+	if volume.ID == "" {
+		volume.ID = fmt.Sprintf("evg-volume-%s", primitive.NewObjectID().Hex())
+	}
+	if err := m.client.CreateVolume(ctx, volume.ID); err != nil {
+		return nil, errors.Wrapf(err, "creating volume '%s'", volume.ID)
+	}
+	if err := volume.Insert(ctx); err != nil {
+		catcher := grip.NewBasicCatcher()
+		catcher.Wrap(err, "inserting volume into DB")
+		catcher.Wrap(m.client.RemoveVolume(ctx, volume.ID), "removing volume due to failure to insert it")
+		return nil, catcher.Resolve()
+	}
+	return volume, nil
+}
+
+// DeleteVolume removes a named container volume.
+func (m *podmanManager) DeleteVolume(ctx context.Context, volume *host.Volume) error {
+	if err := m.client.RemoveVolume(ctx, volume.ID); err != nil && !isPodmanNotFound(err) {
+		return errors.Wrapf(err, "removing volume '%s'", volume.ID)
+	}
+	return errors.Wrap(volume.Remove(ctx), "removing volume from DB")
+}
+
+func (m *podmanManager) ModifyVolume(context.Context, *host.Volume, *model.VolumeModifyOptions) error {
+	return errors.New("can't modify volume with Podman provider")
+}
+
+// GetVolumeAttachment returns the host that the volume is attached to, if any.
+func (m *podmanManager) GetVolumeAttachment(ctx context.Context, volumeID string) (*VolumeAttachment, error) {
+	h, err := host.FindHostWithVolume(ctx, volumeID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding host with volume '%s'", volumeID)
+	}
+	if h == nil {
+		return nil, nil
+	}
+	for _, v := range h.Volumes {
+		if v.VolumeID == volumeID {
+			return &VolumeAttachment{VolumeID: volumeID, HostID: h.Id, DeviceName: v.DeviceName}, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *podmanManager) CheckInstanceType(context.Context, string) error {
+	return errors.New("can't specify instance type with Podman provider")
+}
+
+func (m *podmanManager) AssociateIP(context.Context, *host.Host) error {
+	return errors.New("can't associate IP with Podman provider")
+}
+
+func (m *podmanManager) CleanupIP(context.Context, *host.Host) error {
+	return nil
+}
+
+// Cleanup is a noop for the Podman provider.
+func (m *podmanManager) Cleanup(context.Context) error {
+	return nil
+}
+
+// TimeTilNextPayment returns the amount of time until the next payment is due
+// for the host. For Podman this is not relevant.
+func (m *podmanManager) TimeTilNextPayment(_ *host.Host) time.Duration {
+	return time.Duration(0)
+}
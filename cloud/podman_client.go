@@ -0,0 +1,191 @@
+package cloud
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/volume"
+	docker "github.com/docker/docker/client"
+	"github.com/pkg/errors"
+)
+
+const (
+	// defaultPodmanSocketPath is the socket of the rootful Podman API service.
+	defaultPodmanSocketPath = "/run/podman/podman.sock"
+	// podmanHostIDLabel is the container label that identifies the Evergreen
+	// host that the container backs.
+	podmanHostIDLabel = "evergreen.host_id"
+	// podmanStopTimeoutSecs is how long to wait for a container to exit
+	// gracefully when stopping or restarting it before it's killed.
+	podmanStopTimeoutSecs = 30
+)
+
+// podmanClient wraps the calls to the local container runtime used by the
+// Podman provider.
+type podmanClient interface {
+	// Init connects to the API served at the given Unix socket path.
+	Init(socketPath, apiVersion string) error
+	// CreateContainer creates (but doesn't start) a container.
+	CreateContainer(context.Context, podmanContainerOptions) error
+	// GetContainer returns low-level information on a container.
+	GetContainer(context.Context, string) (*container.InspectResponse, error)
+	// ListContainers returns all the containers, including stopped ones,
+	// that back Evergreen hosts.
+	ListContainers(context.Context) ([]container.Summary, error)
+	StartContainer(context.Context, string) error
+	StopContainer(context.Context, string) error
+	RestartContainer(context.Context, string) error
+	// RemoveContainer forcibly removes a container, stopping it first if
+	// it's running.
+	RemoveContainer(context.Context, string) error
+	// CreateVolume creates a named volume.
+	CreateVolume(context.Context, string) error
+	// RemoveVolume removes a named volume.
+	RemoveVolume(context.Context, string) error
+}
+
+// podmanContainerOptions are the options to create a container.
+type podmanContainerOptions struct {
+	Name       string
+	Image      string
+	Cmd        []string
+	Env        []string
+	Labels     map[string]string
+	Mounts     []podmanMount
+	Network    string
+	Privileged bool
+}
+
+// podmanMount maps a named volume to a path in a container.
+type podmanMount struct {
+	VolumeName string
+	Target     string
+}
+
+// podmanClientImpl talks to Podman (or any other runtime serving the
+// Docker-compatible API, such as containerd behind a compatibility service)
+// over a local Unix socket.
+type podmanClientImpl struct {
+	client *docker.Client
+}
+
+func (c *podmanClientImpl) Init(socketPath, apiVersion string) error {
+	if socketPath == "" {
+		socketPath = defaultPodmanSocketPath
+	}
+	opts := []docker.Opt{
+		docker.WithHost("unix://" + socketPath),
+		docker.WithHTTPClient(&http.Client{}),
+	}
+	if apiVersion != "" {
+		opts = append(opts, docker.WithVersion(apiVersion))
+	} else {
+		opts = append(opts, docker.WithAPIVersionNegotiation())
+	}
+	client, err := docker.NewClientWithOpts(opts...)
+	if err != nil {
+		return errors.Wrapf(err, "initializing client for socket '%s'", socketPath)
+	}
+	c.client = client
+	return nil
+}
+
+func (c *podmanClientImpl) CreateContainer(ctx context.Context, opts podmanContainerOptions) error {
+	mounts := make([]mount.Mount, 0, len(opts.Mounts))
+	for _, m := range opts.Mounts {
+		mounts = append(mounts, mount.Mount{
+			Type:   mount.TypeVolume,
+			Source: m.VolumeName,
+			Target: m.Target,
+		})
+	}
+	containerConf := &container.Config{
+		Image:  opts.Image,
+		Cmd:    opts.Cmd,
+		Env:    opts.Env,
+		Labels: opts.Labels,
+	}
+	hostConf := &container.HostConfig{
+		Mounts:     mounts,
+		Privileged: opts.Privileged,
+	}
+	if opts.Network != "" {
+		hostConf.NetworkMode = container.NetworkMode(opts.Network)
+	}
+	_, err := c.client.ContainerCreate(ctx, containerConf, hostConf, &network.NetworkingConfig{}, nil, opts.Name)
+	return errors.Wrapf(err, "creating container '%s'", opts.Name)
+}
+
+func (c *podmanClientImpl) GetContainer(ctx context.Context, id string) (*container.InspectResponse, error) {
+	info, err := c.client.ContainerInspect(ctx, id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "inspecting container '%s'", id)
+	}
+	return &info, nil
+}
+
+func (c *podmanClientImpl) ListContainers(ctx context.Context) ([]container.Summary, error) {
+	containers, err := c.client.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", podmanHostIDLabel)),
+	})
+	return containers, errors.Wrap(err, "listing containers")
+}
+
+func (c *podmanClientImpl) StartContainer(ctx context.Context, id string) error {
+	return errors.Wrapf(c.client.ContainerStart(ctx, id, container.StartOptions{}), "starting container '%s'", id)
+}
+
+func (c *podmanClientImpl) StopContainer(ctx context.Context, id string) error {
+	timeout := podmanStopTimeoutSecs
+	return errors.Wrapf(c.client.ContainerStop(ctx, id, container.StopOptions{Timeout: &timeout}), "stopping container '%s'", id)
+}
+
+func (c *podmanClientImpl) RestartContainer(ctx context.Context, id string) error {
+	timeout := podmanStopTimeoutSecs
+	return errors.Wrapf(c.client.ContainerRestart(ctx, id, container.StopOptions{Timeout: &timeout}), "restarting container '%s'", id)
+}
+
+func (c *podmanClientImpl) RemoveContainer(ctx context.Context, id string) error {
+	return errors.Wrapf(c.client.ContainerRemove(ctx, id, container.RemoveOptions{Force: true}), "removing container '%s'", id)
+}
+
+func (c *podmanClientImpl) CreateVolume(ctx context.Context, name string) error {
+	_, err := c.client.VolumeCreate(ctx, volume.CreateOptions{Name: name})
+	return errors.Wrapf(err, "creating volume '%s'", name)
+}
+
+func (c *podmanClientImpl) RemoveVolume(ctx context.Context, name string) error {
+	return errors.Wrapf(c.client.VolumeRemove(ctx, name, true), "removing volume '%s'", name)
+}
+
+// isPodmanNotFound returns whether the error is due to a container or volume
+// not existing.
+func isPodmanNotFound(err error) bool {
+	return docker.IsErrNotFound(err)
+}
+
+// podmanContainerStatus converts a container's state into an Evergreen cloud
+// status. Unlike Docker containers, a Podman container that exited is
+// considered stopped, since it can be started again.
+func podmanContainerStatus(state string) CloudStatus {
+	switch strings.ToLower(state) {
+	case "created", "configured", "initialized", "restarting":
+		return StatusInitializing
+	case "running":
+		return StatusRunning
+	case "stopping":
+		return StatusStopping
+	case "paused", "exited", "stopped":
+		return StatusStopped
+	case "removing", "dead":
+		return StatusTerminated
+	default:
+		return StatusUnknown
+	}
+}
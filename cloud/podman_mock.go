@@ -0,0 +1,200 @@
+package cloud
+
+import (
+	"context"
+	"sync"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+)
+
+// podmanClientMock is an in-memory container runtime for testing the Podman
+// provider.
+type podmanClientMock struct {
+	mu         sync.Mutex
+	containers map[string]*podmanMockContainer
+	volumes    map[string]bool
+
+	// API call options
+	failInit   bool
+	failCreate bool
+	failStart  bool
+	failStop   bool
+}
+
+// podmanMockContainer is a container in the mock runtime.
+type podmanMockContainer struct {
+	opts     podmanContainerOptions
+	state    container.ContainerState
+	restarts int
+}
+
+func newPodmanClientMock() *podmanClientMock {
+	return &podmanClientMock{
+		containers: map[string]*podmanMockContainer{},
+		volumes:    map[string]bool{},
+	}
+}
+
+func (c *podmanClientMock) Init(string, string) error {
+	if c.failInit {
+		return errors.New("failed to initialize client")
+	}
+	return nil
+}
+
+func (c *podmanClientMock) CreateContainer(_ context.Context, opts podmanContainerOptions) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.failCreate {
+		return errors.New("failed to create container")
+	}
+	if _, ok := c.containers[opts.Name]; ok {
+		return errdefs.Conflict(errors.Errorf("container '%s' already exists", opts.Name))
+	}
+	for _, m := range opts.Mounts {
+		// Like Podman, implicitly create volumes that don't exist yet.
+		c.volumes[m.VolumeName] = true
+	}
+	c.containers[opts.Name] = &podmanMockContainer{opts: opts, state: container.StateCreated}
+	return nil
+}
+
+func (c *podmanClientMock) GetContainer(_ context.Context, id string) (*container.InspectResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cntr, err := c.getContainer(id)
+	if err != nil {
+		return nil, err
+	}
+	return &container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{
+			ID:   id,
+			Name: id,
+			State: &container.State{
+				Status:  cntr.state,
+				Running: cntr.state == container.StateRunning,
+			},
+		},
+		Config: &container.Config{
+			Image:  cntr.opts.Image,
+			Cmd:    cntr.opts.Cmd,
+			Env:    cntr.opts.Env,
+			Labels: cntr.opts.Labels,
+		},
+	}, nil
+}
+
+func (c *podmanClientMock) ListContainers(context.Context) ([]container.Summary, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var containers []container.Summary
+	for id, cntr := range c.containers {
+		if _, ok := cntr.opts.Labels[podmanHostIDLabel]; !ok {
+			continue
+		}
+		containers = append(containers, container.Summary{
+			ID:     id,
+			Names:  []string{"/" + id},
+			Image:  cntr.opts.Image,
+			Labels: cntr.opts.Labels,
+			State:  cntr.state,
+		})
+	}
+	return containers, nil
+}
+
+func (c *podmanClientMock) StartContainer(_ context.Context, id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.failStart {
+		return errors.New("failed to start container")
+	}
+	cntr, err := c.getContainer(id)
+	if err != nil {
+		return err
+	}
+	cntr.state = container.StateRunning
+	return nil
+}
+
+func (c *podmanClientMock) StopContainer(_ context.Context, id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.failStop {
+		return errors.New("failed to stop container")
+	}
+	cntr, err := c.getContainer(id)
+	if err != nil {
+		return err
+	}
+	cntr.state = container.StateExited
+	return nil
+}
+
+func (c *podmanClientMock) RestartContainer(_ context.Context, id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cntr, err := c.getContainer(id)
+	if err != nil {
+		return err
+	}
+	cntr.state = container.StateRunning
+	cntr.restarts++
+	return nil
+}
+
+func (c *podmanClientMock) RemoveContainer(_ context.Context, id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := c.getContainer(id); err != nil {
+		return err
+	}
+	delete(c.containers, id)
+	return nil
+}
+
+func (c *podmanClientMock) CreateVolume(_ context.Context, name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.volumes[name] {
+		return errdefs.Conflict(errors.Errorf("volume '%s' already exists", name))
+	}
+	c.volumes[name] = true
+	return nil
+}
+
+func (c *podmanClientMock) RemoveVolume(_ context.Context, name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.volumes[name] {
+		return errdefs.NotFound(errors.Errorf("volume '%s' not found", name))
+	}
+	for id, cntr := range c.containers {
+		for _, m := range cntr.opts.Mounts {
+			if m.VolumeName == name {
+				return errdefs.Conflict(errors.Errorf("volume '%s' is in use by container '%s'", name, id))
+			}
+		}
+	}
+	delete(c.volumes, name)
+	return nil
+}
+
+func (c *podmanClientMock) getContainer(id string) (*podmanMockContainer, error) {
+	cntr, ok := c.containers[id]
+	if !ok {
+		return nil, errdefs.NotFound(errors.Errorf("container '%s' not found", id))
+	}
+	return cntr, nil
+}
@@ -0,0 +1,215 @@
+package cloud
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/evergreen-ci/birch"
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPodmanSettings(t *testing.T) {
+	t.Run("ValidateRequiresImage", func(t *testing.T) {
+		s := PodmanSettings{}
+		assert.Error(t, s.Validate())
+		s.ImageURL = "localhost/evergreen-agent:latest"
+		assert.NoError(t, s.Validate())
+	})
+	t.Run("ValidateRequiresAbsoluteAgentPath", func(t *testing.T) {
+		s := PodmanSettings{ImageURL: "image", AgentPath: "evergreen"}
+		assert.Error(t, s.Validate())
+		s.AgentPath = "/usr/local/bin/evergreen"
+		assert.NoError(t, s.Validate())
+	})
+	t.Run("FromDistroSettings", func(t *testing.T) {
+		d := distro.Distro{
+			Provider: evergreen.ProviderNamePodman,
+			ProviderSettingsList: []*birch.Document{birch.NewDocument(
+				birch.EC.String("image_url", "image"),
+				birch.EC.String("network", "host"),
+				birch.EC.Boolean("privileged", true),
+			)},
+		}
+		s := PodmanSettings{}
+		require.NoError(t, s.FromDistroSettings(d, ""))
+		assert.Equal(t, "image", s.ImageURL)
+		assert.Equal(t, "host", s.Network)
+		assert.True(t, s.Privileged)
+	})
+}
+
+func TestPodmanContainerStatus(t *testing.T) {
+	assert.Equal(t, StatusInitializing, podmanContainerStatus(container.StateCreated))
+	assert.Equal(t, StatusRunning, podmanContainerStatus(container.StateRunning))
+	assert.Equal(t, StatusStopped, podmanContainerStatus(container.StateExited))
+	assert.Equal(t, StatusStopped, podmanContainerStatus(container.StatePaused))
+	assert.Equal(t, StatusTerminated, podmanContainerStatus(container.StateDead))
+	assert.Equal(t, StatusUnknown, podmanContainerStatus("bogus"))
+}
+
+func TestPodmanVolumeMountPath(t *testing.T) {
+	assert.Equal(t, "/data", podmanVolumeMountPath(host.VolumeAttachment{VolumeID: "v", DeviceName: "/data"}))
+	assert.Equal(t, "/mnt/v", podmanVolumeMountPath(host.VolumeAttachment{VolumeID: "v", DeviceName: "xvdb"}))
+}
+
+func TestPodmanManager(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	env := testutil.NewEnvironment(ctx, t)
+
+	d := distro.Distro{
+		Id:       "podman-distro",
+		Provider: evergreen.ProviderNamePodmanMock,
+		ProviderSettingsList: []*birch.Document{birch.NewDocument(
+			birch.EC.String("image_url", "localhost/evergreen-agent:latest"),
+		)},
+		WorkDir: "/data/mci",
+	}
+
+	for tName, tCase := range map[string]func(ctx context.Context, t *testing.T, m *podmanManager, client *podmanClientMock, h *host.Host){
+		"SpawnHostStartsContainerRunningAgent": func(ctx context.Context, t *testing.T, m *podmanManager, client *podmanClientMock, h *host.Host) {
+			_, err := m.SpawnHost(ctx, h)
+			require.NoError(t, err)
+
+			cntr, err := client.GetContainer(ctx, h.Id)
+			require.NoError(t, err)
+			assert.True(t, cntr.State.Running)
+			assert.Equal(t, "localhost/evergreen-agent:latest", cntr.Config.Image)
+			require.NotEmpty(t, cntr.Config.Cmd)
+			assert.Equal(t, defaultPodmanAgentPath, cntr.Config.Cmd[0])
+			assert.Contains(t, cntr.Config.Env, evergreen.HostIDEnvVar+"="+h.Id)
+			assert.Equal(t, h.Id, cntr.Config.Labels[podmanHostIDLabel])
+
+			dbHost, err := host.FindOneId(ctx, h.Id)
+			require.NoError(t, err)
+			require.NotZero(t, dbHost)
+			assert.Equal(t, evergreen.HostRunning, dbHost.Status)
+			assert.True(t, dbHost.Provisioned)
+		},
+		"SpawnHostRemovesContainerThatFailsToStart": func(ctx context.Context, t *testing.T, m *podmanManager, client *podmanClientMock, h *host.Host) {
+			client.failStart = true
+			_, err := m.SpawnHost(ctx, h)
+			assert.Error(t, err)
+			assert.Empty(t, client.containers)
+		},
+		"SpawnHostFailsForOtherProvider": func(ctx context.Context, t *testing.T, m *podmanManager, client *podmanClientMock, h *host.Host) {
+			h.Distro.Provider = evergreen.ProviderNameDocker
+			_, err := m.SpawnHost(ctx, h)
+			assert.Error(t, err)
+		},
+		"StopAndStartInstance": func(ctx context.Context, t *testing.T, m *podmanManager, client *podmanClientMock, h *host.Host) {
+			_, err := m.SpawnHost(ctx, h)
+			require.NoError(t, err)
+			h.Status = evergreen.HostRunning
+
+			require.NoError(t, m.StopInstance(ctx, h, false, evergreen.User))
+			state, err := m.GetInstanceState(ctx, h)
+			require.NoError(t, err)
+			assert.Equal(t, StatusStopped, state.Status)
+			assert.Equal(t, evergreen.HostStopped, h.Status)
+
+			require.NoError(t, m.StartInstance(ctx, h, evergreen.User))
+			state, err = m.GetInstanceState(ctx, h)
+			require.NoError(t, err)
+			assert.Equal(t, StatusRunning, state.Status)
+			assert.Equal(t, evergreen.HostRunning, h.Status)
+		},
+		"RebootInstanceRestartsContainer": func(ctx context.Context, t *testing.T, m *podmanManager, client *podmanClientMock, h *host.Host) {
+			_, err := m.SpawnHost(ctx, h)
+			require.NoError(t, err)
+			h.Status = evergreen.HostRunning
+
+			require.NoError(t, m.RebootInstance(ctx, h, evergreen.User))
+			assert.Equal(t, 1, client.containers[h.Id].restarts)
+		},
+		"RebootInstanceFailsForStoppedHost": func(ctx context.Context, t *testing.T, m *podmanManager, client *podmanClientMock, h *host.Host) {
+			h.Status = evergreen.HostStopped
+			assert.Error(t, m.RebootInstance(ctx, h, evergreen.User))
+		},
+		"TerminateInstanceRemovesContainer": func(ctx context.Context, t *testing.T, m *podmanManager, client *podmanClientMock, h *host.Host) {
+			_, err := m.SpawnHost(ctx, h)
+			require.NoError(t, err)
+
+			require.NoError(t, m.TerminateInstance(ctx, h, evergreen.User, "test"))
+			assert.Empty(t, client.containers)
+			state, err := m.GetInstanceState(ctx, h)
+			require.NoError(t, err)
+			assert.Equal(t, StatusNonExistent, state.Status)
+
+			dbHost, err := host.FindOneId(ctx, h.Id)
+			require.NoError(t, err)
+			require.NotZero(t, dbHost)
+			assert.Equal(t, evergreen.HostTerminated, dbHost.Status)
+		},
+		"GetInstanceStatuses": func(ctx context.Context, t *testing.T, m *podmanManager, client *podmanClientMock, h *host.Host) {
+			_, err := m.SpawnHost(ctx, h)
+			require.NoError(t, err)
+
+			statuses, err := m.GetInstanceStatuses(ctx, []host.Host{*h, {Id: "nonexistent"}})
+			require.NoError(t, err)
+			assert.Equal(t, map[string]CloudStatus{
+				h.Id:          StatusRunning,
+				"nonexistent": StatusNonExistent,
+			}, statuses)
+		},
+		"VolumeLifecycle": func(ctx context.Context, t *testing.T, m *podmanManager, client *podmanClientMock, h *host.Host) {
+			_, err := m.SpawnHost(ctx, h)
+			require.NoError(t, err)
+
+			v, err := m.CreateVolume(ctx, &host.Volume{CreatedBy: "user"})
+			require.NoError(t, err)
+			require.NotEmpty(t, v.ID)
+			assert.True(t, client.volumes[v.ID])
+
+			require.NoError(t, m.AttachVolume(ctx, h, &host.VolumeAttachment{VolumeID: v.ID, DeviceName: "/data"}))
+			require.Len(t, client.containers[h.Id].opts.Mounts, 1)
+			assert.Equal(t, podmanMount{VolumeName: v.ID, Target: "/data"}, client.containers[h.Id].opts.Mounts[0])
+			assert.Equal(t, container.StateRunning, client.containers[h.Id].state)
+			require.Len(t, h.Volumes, 1)
+
+			attachment, err := m.GetVolumeAttachment(ctx, v.ID)
+			require.NoError(t, err)
+			require.NotZero(t, attachment)
+			assert.Equal(t, h.Id, attachment.HostID)
+
+			assert.Error(t, m.AttachVolume(ctx, h, &host.VolumeAttachment{VolumeID: v.ID}), "volume should not be attachable twice")
+
+			require.NoError(t, m.DetachVolume(ctx, h, v.ID))
+			assert.Empty(t, client.containers[h.Id].opts.Mounts)
+			assert.Empty(t, h.Volumes)
+
+			require.NoError(t, m.DeleteVolume(ctx, v))
+			assert.False(t, client.volumes[v.ID])
+			dbVolume, err := host.FindVolumeByID(ctx, v.ID)
+			require.NoError(t, err)
+			assert.Zero(t, dbVolume)
+		},
+	} {
+		t.Run(tName, func(t *testing.T) {
+			require.NoError(t, db.ClearCollections(host.Collection, host.VolumesCollection))
+			defer func() {
+				assert.NoError(t, db.ClearCollections(host.Collection, host.VolumesCollection))
+			}()
+
+			tctx, tcancel := context.WithCancel(ctx)
+			defer tcancel()
+
+			client := newPodmanClientMock()
+			m := &podmanManager{env: env, client: client}
+			require.NoError(t, m.Configure(tctx, env.Settings()))
+
+			h := host.NewIntent(host.CreateOptions{Distro: d})
+			require.NoError(t, h.Insert(tctx))
+
+			tCase(tctx, t, m, client, h)
+		})
+	}
+}
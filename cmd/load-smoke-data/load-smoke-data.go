@@ -14,6 +14,7 @@ import (
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/notification"
 	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/model/testresult"
@@ -80,6 +81,12 @@ func insertFileDocsToDB(ctx context.Context, fn string, db *mongo.Database) erro
 		}); err != nil {
 			return errors.Wrap(err, "creating host index")
 		}
+	case notification.Collection:
+		if _, err = collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+			{Keys: notification.SentToURLIndex},
+		}); err != nil {
+			return errors.Wrap(err, "creating notification index")
+		}
 	}
 	scanner := bufio.NewScanner(file)
 	// Set the max buffer size to the max size of a Mongo document (16MB).
@@ -25,6 +25,7 @@ var (
 	AuthMultiKey                   = bsonutil.MustHaveTag(AuthConfig{}, "Multi")
 	AuthKanopyKey                  = bsonutil.MustHaveTag(AuthConfig{}, "Kanopy")
 	AuthOAuthKey                   = bsonutil.MustHaveTag(AuthConfig{}, "OAuth")
+	AuthOIDCKey                    = bsonutil.MustHaveTag(AuthConfig{}, "OIDC")
 	authPreferredTypeKey           = bsonutil.MustHaveTag(AuthConfig{}, "PreferredType")
 	authBackgroundReauthMinutesKey = bsonutil.MustHaveTag(AuthConfig{}, "BackgroundReauthMinutes")
 	AuthAllowServiceUsersKey       = bsonutil.MustHaveTag(AuthConfig{}, "AllowServiceUsers")
@@ -99,6 +100,47 @@ type OAuthConfig struct {
 	ConnectorID string `bson:"connector_id" json:"connector_id" yaml:"connector_id"`
 }
 
+// OIDCConfig configures the auth method that logs users in through a generic
+// OpenID Connect identity provider (e.g. Keycloak). This is for the UI, rather
+// than the API.
+type OIDCConfig struct {
+	// Issuer is the identity provider's issuer URL. The provider's endpoints
+	// and signing keys are discovered from it.
+	Issuer       string `bson:"issuer" json:"issuer" yaml:"issuer"`
+	ClientID     string `bson:"client_id" json:"client_id" yaml:"client_id"`
+	ClientSecret string `bson:"client_secret" json:"client_secret" yaml:"client_secret" secret:"true"`
+	// Scopes are requested in addition to the openid scope, which is always
+	// requested. If it's empty, the profile, email and offline_access scopes
+	// are requested.
+	Scopes []string `bson:"scopes" json:"scopes" yaml:"scopes"`
+	// UsernameClaim is the ID token claim used as the username. If it's
+	// empty, the email claim is used.
+	UsernameClaim string `bson:"username_claim" json:"username_claim" yaml:"username_claim"`
+	// GroupsClaim is the ID token claim containing the user's groups. Nested
+	// claims can be given as a dotted path (e.g. "realm_access.roles"). If
+	// it's empty, the groups claim is used.
+	GroupsClaim string `bson:"groups_claim" json:"groups_claim" yaml:"groups_claim"`
+	// UserGroup, if set, is the group a user must belong to in order to log
+	// in.
+	UserGroup string `bson:"user_group" json:"user_group" yaml:"user_group"`
+	// RoleMappings grant roles to users based on their groups. Roles that
+	// appear in a mapping are kept in sync with the user's groups each time
+	// they log in or are reauthorized.
+	RoleMappings       []OIDCRoleMapping `bson:"role_mappings" json:"role_mappings" yaml:"role_mappings"`
+	ExpireAfterMinutes int               `bson:"expire_after_minutes" json:"expire_after_minutes" yaml:"expire_after_minutes"`
+	// ExpectedEmailDomains is the allow-list of email domains whose local-part
+	// may be used as the username when the username comes from the email
+	// claim. It behaves the same as it does for Okta.
+	ExpectedEmailDomains []string `bson:"expected_email_domains" json:"expected_email_domains" yaml:"expected_email_domains"`
+}
+
+// OIDCRoleMapping grants roles to users who belong to a group in the identity
+// provider.
+type OIDCRoleMapping struct {
+	Group string   `bson:"group" json:"group" yaml:"group"`
+	Roles []string `bson:"roles" json:"roles" yaml:"roles"`
+}
+
 // AuthConfig contains the settings for the various auth managers.
 type AuthConfig struct {
 	// Okta contains the settings for our Okta web app, which is used exclusively for
@@ -110,6 +152,7 @@ type AuthConfig struct {
 	Multi                   *MultiAuthConfig  `bson:"multi" json:"multi" yaml:"multi"`
 	Kanopy                  *KanopyAuthConfig `bson:"kanopy" json:"kanopy" yaml:"kanopy"`
 	OAuth                   *OAuthConfig      `bson:"oauth" json:"oauth" yaml:"oauth"`
+	OIDC                    *OIDCConfig       `bson:"oidc,omitempty" json:"oidc" yaml:"oidc"`
 	AllowServiceUsers       bool              `bson:"allow_service_users" json:"allow_service_users" yaml:"allow_service_users"`
 	PreferredType           string            `bson:"preferred_type,omitempty" json:"preferred_type" yaml:"preferred_type"`
 	BackgroundReauthMinutes int               `bson:"background_reauth_minutes" json:"background_reauth_minutes" yaml:"background_reauth_minutes"`
@@ -130,6 +173,7 @@ func (c *AuthConfig) Set(ctx context.Context) error {
 			AuthMultiKey:                   c.Multi,
 			AuthKanopyKey:                  c.Kanopy,
 			AuthOAuthKey:                   c.OAuth,
+			AuthOIDCKey:                    c.OIDC,
 			authPreferredTypeKey:           c.PreferredType,
 			authBackgroundReauthMinutesKey: c.BackgroundReauthMinutes,
 			AuthAllowServiceUsersKey:       c.AllowServiceUsers,
@@ -162,9 +206,10 @@ func (c *AuthConfig) ValidateAndDefault() error {
 		AuthGithubKey,
 		AuthMultiKey,
 		AuthKanopyKey,
+		AuthOIDCKey,
 	}, c.PreferredType), "invalid auth type '%s'", c.PreferredType)
 
-	if c.Naive == nil && c.Github == nil && c.Okta == nil && c.Multi == nil && c.Kanopy == nil {
+	if c.Naive == nil && c.Github == nil && c.Okta == nil && c.Multi == nil && c.Kanopy == nil && c.OIDC == nil {
 		catcher.Add(errors.New("must specify one form of authentication"))
 	}
 
@@ -183,6 +228,8 @@ func (c *AuthConfig) ValidateAndDefault() error {
 				catcher.NewWhen(c.Github == nil, "GitHub settings cannot be empty if using in multi auth")
 			case AuthNaiveKey:
 				catcher.NewWhen(c.Naive == nil, "Naive settings cannot be empty if using in multi auth")
+			case AuthOIDCKey:
+				catcher.NewWhen(c.OIDC == nil, "OIDC settings cannot be empty if using in multi auth")
 			default:
 				catcher.Errorf("unrecognized auth mechanism '%s'", kind)
 			}
@@ -198,5 +245,15 @@ func (c *AuthConfig) ValidateAndDefault() error {
 		catcher.NewWhen(c.Kanopy.KeysetURL == "", "keyset URL cannot be empty if using Kanopy auth")
 	}
 
+	if c.OIDC != nil {
+		catcher.NewWhen(c.OIDC.Issuer == "", "issuer cannot be empty if using OIDC auth")
+		catcher.NewWhen(c.OIDC.ClientID == "", "client ID cannot be empty if using OIDC auth")
+		catcher.NewWhen(c.OIDC.ExpireAfterMinutes < 0, "expiration cannot be negative if using OIDC auth")
+		for i, mapping := range c.OIDC.RoleMappings {
+			catcher.ErrorfWhen(mapping.Group == "", "OIDC role mapping at index %d must specify a group", i)
+			catcher.ErrorfWhen(len(mapping.Roles) == 0, "OIDC role mapping for group '%s' must specify at least one role", mapping.Group)
+		}
+	}
+
 	return catcher.Resolve()
 }
@@ -51,6 +51,12 @@ type BucketsConfig struct {
 	TestResultsBucket BucketConfig `bson:"test_results_bucket" json:"test_results_bucket" yaml:"test_results_bucket"`
 	// Credentials for accessing the LogBucket.
 	Credentials S3Credentials `bson:"credentials" json:"credentials" yaml:"credentials"`
+	// CacheStores are the content-addressed stores written by chunked
+	// cache.save commands whose unreferenced blobs are garbage collected.
+	CacheStores []CacheStoreConfig `bson:"cache_stores" json:"cache_stores" yaml:"cache_stores"`
+	// CacheManifestTTLDays is how many days a chunked cache's manifest is kept
+	// before garbage collection removes it. Defaults to 30 when unset or <= 0.
+	CacheManifestTTLDays int `bson:"cache_manifest_ttl_days" json:"cache_manifest_ttl_days" yaml:"cache_manifest_ttl_days"`
 }
 
 var (
@@ -62,8 +68,33 @@ var (
 	BucketsConfigRetryFailedLogMoveMaxJobsPerRunKey = bsonutil.MustHaveTag(BucketsConfig{}, "RetryFailedLogMoveMaxJobsPerRun")
 	BucketsConfigTestResultsBucketKey               = bsonutil.MustHaveTag(BucketsConfig{}, "TestResultsBucket")
 	BucketsConfigCredentialsKey                     = bsonutil.MustHaveTag(BucketsConfig{}, "Credentials")
+	BucketsConfigCacheStoresKey                     = bsonutil.MustHaveTag(BucketsConfig{}, "CacheStores")
+	BucketsConfigCacheManifestTTLDaysKey            = bsonutil.MustHaveTag(BucketsConfig{}, "CacheManifestTTLDays")
 )
 
+// CacheStoreConfig identifies a content-addressed cache store in an S3 bucket.
+type CacheStoreConfig struct {
+	Bucket string `bson:"bucket" json:"bucket" yaml:"bucket"`
+	// Prefix is the key prefix of the store, which is the store_path given to
+	// the cache commands. Defaults to "evergreen-cache-store".
+	Prefix string `bson:"prefix" json:"prefix" yaml:"prefix"`
+	// Region defaults to us-east-1.
+	Region string `bson:"region" json:"region" yaml:"region"`
+	// RoleARN, if set, is assumed to access the bucket.
+	RoleARN    string `bson:"role_arn" json:"role_arn" yaml:"role_arn"`
+	ExternalID string `bson:"external_id" json:"external_id" yaml:"external_id"`
+}
+
+func (c *CacheStoreConfig) validate() error {
+	if c.Bucket == "" {
+		return errors.New("must specify bucket for cache store")
+	}
+	if c.Region == "" {
+		c.Region = DefaultS3Region
+	}
+	return nil
+}
+
 // BucketConfig represents the admin config for an individual bucket.
 type BucketConfig struct {
 	Name              string     `bson:"name" json:"name" yaml:"name"`
@@ -119,6 +150,8 @@ func (c *BucketsConfig) Set(ctx context.Context) error {
 				BucketsConfigRetryFailedLogMoveMaxJobsPerRunKey: c.RetryFailedLogMoveMaxJobsPerRun,
 				BucketsConfigTestResultsBucketKey:               c.TestResultsBucket,
 				BucketsConfigCredentialsKey:                     c.Credentials,
+				BucketsConfigCacheStoresKey:                     c.CacheStores,
+				BucketsConfigCacheManifestTTLDaysKey:            c.CacheManifestTTLDays,
 			},
 		}),
 		"updating config section '%s'", c.SectionId(),
@@ -136,6 +169,12 @@ func (c *BucketsConfig) ValidateAndDefault() error {
 	if c.RetryFailedLogMoveMaxJobsPerRun < 0 {
 		catcher.Add(errors.New("retry_failed_log_move_max_jobs_per_run cannot be negative"))
 	}
+	for i := range c.CacheStores {
+		catcher.Wrapf(c.CacheStores[i].validate(), "invalid cache store at index %d", i)
+	}
+	if c.CacheManifestTTLDays < 0 {
+		catcher.Add(errors.New("cache_manifest_ttl_days cannot be negative"))
+	}
 	return catcher.Resolve()
 }
 
@@ -12,12 +12,14 @@ import (
 var (
 	cloudProvidersAWSKey    = bsonutil.MustHaveTag(CloudProviders{}, "AWS")
 	cloudProvidersDockerKey = bsonutil.MustHaveTag(CloudProviders{}, "Docker")
+	cloudProvidersPodmanKey = bsonutil.MustHaveTag(CloudProviders{}, "Podman")
 )
 
 // CloudProviders stores configuration settings for the supported cloud host providers.
 type CloudProviders struct {
 	AWS    AWSConfig    `bson:"aws" json:"aws" yaml:"aws"`
 	Docker DockerConfig `bson:"docker" json:"docker" yaml:"docker"`
+	Podman PodmanConfig `bson:"podman" json:"podman" yaml:"podman"`
 }
 
 func (c *CloudProviders) SectionId() string { return "providers" }
@@ -31,6 +33,7 @@ func (c *CloudProviders) Set(ctx context.Context) error {
 		"$set": bson.M{
 			cloudProvidersAWSKey:    c.AWS,
 			cloudProvidersDockerKey: c.Docker,
+			cloudProvidersPodmanKey: c.Podman,
 		}}), "updating config section '%s'", c.SectionId(),
 	)
 }
@@ -164,3 +167,16 @@ type AWSClientType string
 type DockerConfig struct {
 	APIVersion string `bson:"api_version" json:"api_version" yaml:"api_version"`
 }
+
+// PodmanConfig stores connection info for the local container runtime used by
+// the Podman provider.
+type PodmanConfig struct {
+	// SocketPath is the path to the Unix socket serving the Docker-compatible
+	// API, such as the socket of `podman system service` or of a containerd
+	// compatibility service. If unset, the default rootful Podman socket is
+	// used.
+	SocketPath string `bson:"socket_path" json:"socket_path" yaml:"socket_path"`
+	// APIVersion is the version of the API to use. If unset, the version is
+	// negotiated with the server.
+	APIVersion string `bson:"api_version" json:"api_version" yaml:"api_version"`
+}
@@ -44,6 +44,9 @@ type ServiceFlags struct {
 	PodDiagnosticsDisabled             bool `bson:"pod_diagnostics_disabled" json:"pod_diagnostics_disabled"`
 	RetryFailedLogMoveEnabled          bool `bson:"retry_failed_log_move_enabled" json:"retry_failed_log_move_enabled"`
 	ProjectTranslationCacheEnabled     bool `bson:"project_translation_cache_enabled" json:"project_translation_cache_enabled"`
+	// LogRetentionEnabled turns on the compaction of finished tasks' logs
+	// and the enforcement of projects' log retention tiers.
+	LogRetentionEnabled bool `bson:"log_retention_enabled" json:"log_retention_enabled"`
 	// TaskQueueAutoUnscheduleDisabled stops the scheduler from unscheduling the patch tasks in a
 	// distro queue that has reached set threshold.
 	TaskQueueAutoUnscheduleDisabled bool `bson:"task_queue_auto_unschedule_disabled" json:"task_queue_auto_unschedule_disabled"`
@@ -120,6 +123,7 @@ func (c *ServiceFlags) Set(ctx context.Context) error {
 			podDiagnosticsDisabledKey:             c.PodDiagnosticsDisabled,
 			retryFailedLogMoveEnabledKey:          c.RetryFailedLogMoveEnabled,
 			projectTranslationCacheEnabledKey:     c.ProjectTranslationCacheEnabled,
+			logRetentionEnabledKey:                c.LogRetentionEnabled,
 			secondaryReadsDisabledKey:             c.SecondaryReadsDisabled,
 			backgroundCommandFailureEnabledKey:    c.BackgroundCommandFailureEnabled,
 			apiRateLimiterDisabledKey:             c.APIRateLimiterDisabled,
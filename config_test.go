@@ -288,6 +288,17 @@ func (s *AdminSuite) TestAuthConfig() {
 		Kanopy: &KanopyAuthConfig{
 			HeaderName: "internal_header",
 		},
+		OIDC: &OIDCConfig{
+			Issuer:        "https://keycloak.example.com/realms/evergreen",
+			ClientID:      "oidc_client",
+			ClientSecret:  "oidc_secret",
+			UsernameClaim: "preferred_username",
+			GroupsClaim:   "realm_access.roles",
+			RoleMappings: []OIDCRoleMapping{
+				{Group: "evg-admins", Roles: []string{"superuser"}},
+			},
+			ExpireAfterMinutes: 60,
+		},
 		BackgroundReauthMinutes: 60,
 	}
 
@@ -961,6 +961,12 @@ func (e *envState) initThirdPartySenders(ctx context.Context, tracer trace.Trace
 	}
 	e.senders[SenderEvergreenWebhook] = sender
 
+	sender, err = util.NewChatWebhookLogger()
+	if err != nil {
+		return errors.Wrap(err, "setting up chat webhook logger")
+	}
+	e.senders[SenderChatWebhook] = sender
+
 	sender, err = send.NewGenericLogger("evergreen", levelInfo)
 	if err != nil {
 		return errors.Wrap(err, "setting up Evergreen generic logger")
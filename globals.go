@@ -237,6 +237,9 @@ const (
 	// AutoRestartActivator represents the activator for tasks that have been
 	// automatically restarted via the retry_on_failure command flag.
 	AutoRestartActivator = "automatic_restart"
+	// CulpritFinderTaskActivator represents the activator for tasks activated
+	// by a culprit-finding bisection.
+	CulpritFinderTaskActivator = "culprit-finder"
 
 	// UnderwaterTaskUnscheduler is the caller associated with unscheduling
 	// and disabling tasks older than the task.UnschedulableThreshold from
@@ -720,6 +723,8 @@ const (
 	ProviderNameEc2Fleet   = "ec2-fleet"
 	ProviderNameDocker     = "docker"
 	ProviderNameDockerMock = "docker-mock"
+	ProviderNamePodman     = "podman"
+	ProviderNamePodmanMock = "podman-mock"
 	ProviderNameStatic     = "static"
 	ProviderNameMock       = "mock"
 
@@ -747,6 +752,12 @@ func IsDockerProvider(provider string) bool {
 		provider == ProviderNameDockerMock
 }
 
+// IsPodmanProvider returns true if the provider is Podman.
+func IsPodmanProvider(provider string) bool {
+	return provider == ProviderNamePodman ||
+		provider == ProviderNamePodmanMock
+}
+
 // EC2Tenancy represents the physical hardware tenancy for EC2 hosts.
 type EC2Tenancy string
 
@@ -771,6 +782,7 @@ var (
 		ProviderNameEc2Fleet,
 		ProviderNameMock,
 		ProviderNameDocker,
+		ProviderNamePodman,
 	}
 
 	// ProviderUserSpawnable includes all cloud provider types where a user can
@@ -940,12 +952,15 @@ const (
 	SenderJIRAComment
 	SenderEmail
 	SenderGeneric
+	// SenderChatWebhook sends messages to chat services' incoming webhooks,
+	// like Microsoft Teams and Mattermost.
+	SenderChatWebhook
 )
 
 func (k SenderKey) Validate() error {
 	switch k {
 	case SenderGithubStatus, SenderEvergreenWebhook, SenderSlack, SenderJIRAComment, SenderJIRAIssue,
-		SenderEmail, SenderGeneric:
+		SenderEmail, SenderGeneric, SenderChatWebhook:
 		return nil
 	default:
 		return errors.New("invalid sender defined")
@@ -968,6 +983,8 @@ func (k SenderKey) String() string {
 		return "jira-issue"
 	case SenderGeneric:
 		return "generic"
+	case SenderChatWebhook:
+		return "chat-webhook"
 	default:
 		return "<error:unknown>"
 	}
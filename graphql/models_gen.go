@@ -605,16 +605,38 @@ type TaskHistoryPagination struct {
 	OldestTaskOrder     int `json:"oldestTaskOrder"`
 }
 
+// TaskLogSearchOpts is the input for the taskLogs.searchLogs query.
+// Lines below minPriority are neither matched nor returned as context.
+type TaskLogSearchOpts struct {
+	CaseInsensitive *bool        `json:"caseInsensitive,omitempty"`
+	ContextLines    *int         `json:"contextLines,omitempty"`
+	Limit           *int         `json:"limit,omitempty"`
+	LogType         *TaskLogType `json:"logType,omitempty"`
+	MinPriority     *int         `json:"minPriority,omitempty"`
+	Pattern         string       `json:"pattern"`
+	Regex           *bool        `json:"regex,omitempty"`
+}
+
+type TaskLogSearchResult struct {
+	Matched   bool      `json:"matched"`
+	Message   string    `json:"message"`
+	Severity  string    `json:"severity"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
 // TaskLogs is the return value for the task.taskLogs query.
 // It contains the logs for a given task on a given execution.
 type TaskLogs struct {
-	AgentLogs  []*apimodels.LogMessage       `json:"agentLogs"`
-	AllLogs    []*apimodels.LogMessage       `json:"allLogs"`
-	EventLogs  []*model.TaskAPIEventLogEntry `json:"eventLogs"`
-	Execution  int                           `json:"execution"`
-	SystemLogs []*apimodels.LogMessage       `json:"systemLogs"`
-	TaskID     string                        `json:"taskId"`
-	TaskLogs   []*apimodels.LogMessage       `json:"taskLogs"`
+	AgentLogs []*apimodels.LogMessage       `json:"agentLogs"`
+	AllLogs   []*apimodels.LogMessage       `json:"allLogs"`
+	EventLogs []*model.TaskAPIEventLogEntry `json:"eventLogs"`
+	Execution int                           `json:"execution"`
+	// searchLogs returns the task's log lines matching the search, along with any
+	// requested context lines.
+	SearchLogs []*TaskLogSearchResult  `json:"searchLogs"`
+	SystemLogs []*apimodels.LogMessage `json:"systemLogs"`
+	TaskID     string                  `json:"taskId"`
+	TaskLogs   []*apimodels.LogMessage `json:"taskLogs"`
 }
 
 // TaskOwnerTeam is the return value for the taskOwnerTeam query.
@@ -1535,6 +1557,65 @@ func (e TaskHistoryDirection) MarshalJSON() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+type TaskLogType string
+
+const (
+	TaskLogTypeAgentLog  TaskLogType = "AGENT_LOG"
+	TaskLogTypeAllLogs   TaskLogType = "ALL_LOGS"
+	TaskLogTypeSystemLog TaskLogType = "SYSTEM_LOG"
+	TaskLogTypeTaskLog   TaskLogType = "TASK_LOG"
+)
+
+var AllTaskLogType = []TaskLogType{
+	TaskLogTypeAgentLog,
+	TaskLogTypeAllLogs,
+	TaskLogTypeSystemLog,
+	TaskLogTypeTaskLog,
+}
+
+func (e TaskLogType) IsValid() bool {
+	switch e {
+	case TaskLogTypeAgentLog, TaskLogTypeAllLogs, TaskLogTypeSystemLog, TaskLogTypeTaskLog:
+		return true
+	}
+	return false
+}
+
+func (e TaskLogType) String() string {
+	return string(e)
+}
+
+func (e *TaskLogType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TaskLogType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TaskLogType", str)
+	}
+	return nil
+}
+
+func (e TaskLogType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *TaskLogType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e TaskLogType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
 type TaskSortCategory string
 
 const (
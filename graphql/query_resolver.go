@@ -11,19 +11,23 @@ import (
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/graphql/loaders"
 	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/culprit"
 	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/pipeline"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	restModel "github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/evergreen-ci/gimlet"
 	"github.com/evergreen-ci/utility"
 	"github.com/mongodb/anser/bsonutil"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
 )
 
 // BbGetCreatedTickets is the resolver for the bbGetCreatedTickets field.
@@ -436,6 +440,31 @@ func (r *queryResolver) Patch(ctx context.Context, patchID string) (*restModel.A
 	return apiPatch, nil
 }
 
+// PatchCostEstimate is the resolver for the patchCostEstimate field.
+func (r *queryResolver) PatchCostEstimate(ctx context.Context, patchID string, variantsTasks []*VariantTasks) (*restModel.APIPatchCostEstimate, error) {
+	var selected []patch.VariantTasks
+	for _, vt := range variantsTasks {
+		variantTasks := patch.VariantTasks{
+			Variant: vt.Variant,
+			Tasks:   vt.Tasks,
+		}
+		for _, displayTask := range vt.DisplayTasks {
+			variantTasks.DisplayTasks = append(variantTasks.DisplayTasks, patch.DisplayTask{Name: displayTask.Name})
+		}
+		selected = append(selected, variantTasks)
+	}
+
+	estimate, err := data.EstimatePatchCost(ctx, patchID, selected)
+	if err != nil {
+		gimletErr, ok := errors.Cause(err).(gimlet.ErrorResponse)
+		if ok {
+			return nil, mapHTTPStatusToGqlError(ctx, gimletErr.StatusCode, err)
+		}
+		return nil, InternalServerError.Send(ctx, fmt.Sprintf("estimating cost of patch '%s': %s", patchID, err.Error()))
+	}
+	return estimate, nil
+}
+
 // GithubProjectConflicts is the resolver for the githubProjectConflicts field.
 func (r *queryResolver) GithubProjectConflicts(ctx context.Context, projectID string) (*model.GithubProjectConflicts, error) {
 	pRef, err := model.FindMergedProjectRefSecondary(ctx, projectID, "", false)
@@ -522,6 +551,66 @@ func (r *queryResolver) ProjectSettings(ctx context.Context, projectIdentifier s
 	return res, nil
 }
 
+// PipelineRuns is the resolver for the pipelineRuns field.
+func (r *queryResolver) PipelineRuns(ctx context.Context, projectIdentifier string, definitionID *string, limit *int) ([]*restModel.APIPipelineRun, error) {
+	runsLimit := utility.FromIntPtr(limit)
+	if runsLimit <= 0 {
+		runsLimit = defaultPipelineRunsLimit
+	}
+	if runsLimit > maxPipelineRunsLimit {
+		return nil, InputValidationError.Send(ctx, fmt.Sprintf("limit cannot exceed %d", maxPipelineRunsLimit))
+	}
+	projectRef, err := model.FindBranchProjectRef(ctx, projectIdentifier)
+	if err != nil {
+		return nil, InternalServerError.Send(ctx, fmt.Sprintf("fetching project '%s': %s", projectIdentifier, err.Error()))
+	}
+	if projectRef == nil {
+		return nil, ResourceNotFound.Send(ctx, fmt.Sprintf("project '%s' not found", projectIdentifier))
+	}
+
+	runs, err := pipeline.FindByProject(ctx, projectRef.Id, utility.FromStringPtr(definitionID), runsLimit)
+	if err != nil {
+		return nil, InternalServerError.Send(ctx, fmt.Sprintf("finding pipeline runs for project '%s': %s", projectIdentifier, err.Error()))
+	}
+	apiRuns := make([]*restModel.APIPipelineRun, 0, len(runs))
+	for _, run := range runs {
+		apiRun := &restModel.APIPipelineRun{}
+		apiRun.BuildFromService(run)
+		apiRuns = append(apiRuns, apiRun)
+	}
+	return apiRuns, nil
+}
+
+// Bisections is the resolver for the bisections field.
+func (r *queryResolver) Bisections(ctx context.Context, projectIdentifier string, limit *int) ([]*restModel.APIBisection, error) {
+	bisectionsLimit := utility.FromIntPtr(limit)
+	if bisectionsLimit <= 0 {
+		bisectionsLimit = defaultBisectionsLimit
+	}
+	if bisectionsLimit > maxBisectionsLimit {
+		return nil, InputValidationError.Send(ctx, fmt.Sprintf("limit cannot exceed %d", maxBisectionsLimit))
+	}
+	projectRef, err := model.FindBranchProjectRef(ctx, projectIdentifier)
+	if err != nil {
+		return nil, InternalServerError.Send(ctx, fmt.Sprintf("fetching project '%s': %s", projectIdentifier, err.Error()))
+	}
+	if projectRef == nil {
+		return nil, ResourceNotFound.Send(ctx, fmt.Sprintf("project '%s' not found", projectIdentifier))
+	}
+
+	bisections, err := culprit.FindByProject(ctx, projectRef.Id, bisectionsLimit)
+	if err != nil {
+		return nil, InternalServerError.Send(ctx, fmt.Sprintf("finding bisections for project '%s': %s", projectIdentifier, err.Error()))
+	}
+	apiBisections := make([]*restModel.APIBisection, 0, len(bisections))
+	for _, b := range bisections {
+		apiBisection := &restModel.APIBisection{}
+		apiBisection.BuildFromService(b)
+		apiBisections = append(apiBisections, apiBisection)
+	}
+	return apiBisections, nil
+}
+
 // RepoEvents is the resolver for the repoEvents field.
 func (r *queryResolver) RepoEvents(ctx context.Context, repoID string, limit *int, before *time.Time) (*ProjectEvents, error) {
 	timestamp := time.Now()
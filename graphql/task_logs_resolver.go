@@ -48,6 +48,11 @@ func (r *taskLogsResolver) EventLogs(ctx context.Context, obj *TaskLogs) ([]*res
 	return apiEventLogPointers, nil
 }
 
+// SearchLogs is the resolver for the searchLogs field.
+func (r *taskLogsResolver) SearchLogs(ctx context.Context, obj *TaskLogs, opts TaskLogSearchOpts) ([]*TaskLogSearchResult, error) {
+	return searchTaskLogs(ctx, obj, opts)
+}
+
 // SystemLogs is the resolver for the systemLogs field.
 func (r *taskLogsResolver) SystemLogs(ctx context.Context, obj *TaskLogs) ([]*apimodels.LogMessage, error) {
 	return getTaskLogs(ctx, obj, task.TaskLogTypeSystem)
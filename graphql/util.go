@@ -24,6 +24,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/log"
 	"github.com/evergreen-ci/evergreen/model/parsley"
 	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/evergreen/model/task"
@@ -36,6 +37,7 @@ import (
 	"github.com/evergreen-ci/gimlet"
 	"github.com/evergreen-ci/utility"
 	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/level"
 	"github.com/mongodb/grip/message"
 	"github.com/pkg/errors"
 	"github.com/vektah/gqlparser/v2/gqlerror"
@@ -967,6 +969,91 @@ func getTaskLogs(ctx context.Context, obj *TaskLogs, logType task.TaskLogType) (
 	return lines, nil
 }
 
+const (
+	defaultTaskLogSearchLimit = 100
+	maxTaskLogSearchLimit     = 1000
+)
+
+const (
+	defaultPipelineRunsLimit = 100
+	maxPipelineRunsLimit     = 1000
+)
+
+const (
+	defaultBisectionsLimit = 20
+	maxBisectionsLimit     = 100
+)
+
+func searchTaskLogs(ctx context.Context, obj *TaskLogs, opts TaskLogSearchOpts) ([]*TaskLogSearchResult, error) {
+	logType := task.TaskLogTypeAll
+	if opts.LogType != nil {
+		switch *opts.LogType {
+		case TaskLogTypeAgentLog:
+			logType = task.TaskLogTypeAgent
+		case TaskLogTypeSystemLog:
+			logType = task.TaskLogTypeSystem
+		case TaskLogTypeTaskLog:
+			logType = task.TaskLogTypeTask
+		}
+	}
+	limit := utility.FromIntPtr(opts.Limit)
+	if limit <= 0 {
+		limit = defaultTaskLogSearchLimit
+	}
+	if limit > maxTaskLogSearchLimit {
+		return nil, InputValidationError.Send(ctx, fmt.Sprintf("limit cannot exceed %d", maxTaskLogSearchLimit))
+	}
+	search := &log.SearchOptions{
+		Pattern:         opts.Pattern,
+		Regex:           utility.FromBoolPtr(opts.Regex),
+		CaseInsensitive: utility.FromBoolPtr(opts.CaseInsensitive),
+		MinPriority:     level.Priority(utility.FromIntPtr(opts.MinPriority)),
+		ContextLines:    utility.FromIntPtr(opts.ContextLines),
+	}
+	if err := search.Validate(); err != nil {
+		return nil, InputValidationError.Send(ctx, fmt.Sprintf("invalid search: %s", err.Error()))
+	}
+
+	dbTask, err := task.FindOneIdAndExecution(ctx, obj.TaskID, obj.Execution)
+	if err != nil {
+		return nil, InternalServerError.Send(ctx, fmt.Sprintf("finding task '%s': %s", obj.TaskID, err.Error()))
+	}
+	if dbTask == nil {
+		return nil, ResourceNotFound.Send(ctx, fmt.Sprintf("task '%s' not found", obj.TaskID))
+	}
+	if evergreen.IsUnstartedTaskStatus(dbTask.Status) {
+		return []*TaskLogSearchResult{}, nil
+	}
+
+	it, err := dbTask.GetTaskLogs(ctx, task.TaskLogGetOptions{
+		LogType:   logType,
+		LineLimit: limit,
+		Search:    search,
+	})
+	if err != nil {
+		return nil, InternalServerError.Send(ctx, fmt.Sprintf("searching logs for task '%s': %s", dbTask.Id, err.Error()))
+	}
+
+	results := []*TaskLogSearchResult{}
+	for it.Next() {
+		item := it.Item()
+		results = append(results, &TaskLogSearchResult{
+			Matched:   item.Matched,
+			Message:   item.Data,
+			Severity:  apimodels.GetSeverityMapping(item.Priority),
+			Timestamp: time.Unix(0, item.Timestamp),
+		})
+	}
+	catcher := grip.NewBasicCatcher()
+	catcher.Add(it.Err())
+	catcher.Add(it.Close())
+	if catcher.HasErrors() {
+		return nil, InternalServerError.Send(ctx, fmt.Sprintf("reading log search results for task '%s': %s", dbTask.Id, catcher.Resolve().Error()))
+	}
+
+	return results, nil
+}
+
 //////////////////////////////////////////
 // Helper functions for task test results.
 //////////////////////////////////////////
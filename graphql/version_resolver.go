@@ -14,6 +14,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/build"
 	"github.com/evergreen-ci/evergreen/model/cost"
 	"github.com/evergreen-ci/evergreen/model/manifest"
+	"github.com/evergreen-ci/evergreen/model/pipeline"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/model/testresult"
 	"github.com/evergreen-ci/evergreen/model/user"
@@ -610,12 +611,50 @@ func (r *versionResolver) UpstreamProject(ctx context.Context, obj *restModel.AP
 			Revision: upstreamBuild.Revision,
 			Version:  &apiVersion,
 		}
+	} else if v.TriggerType == model.ProjectTriggerLevelPipeline {
+		run, err := pipeline.FindOneId(ctx, v.TriggerID)
+		if err != nil {
+			return nil, InternalServerError.Send(ctx, fmt.Sprintf("fetching pipeline run '%s': %s", v.TriggerID, err.Error()))
+		}
+		if run == nil {
+			return nil, ResourceNotFound.Send(ctx, fmt.Sprintf("pipeline run '%s' not found", v.TriggerID))
+		}
+		// The upstream that completed the run is the one that triggered the
+		// version, so it's the most recently updated one.
+		var upstream *pipeline.Upstream
+		for i := range run.Upstreams {
+			if upstream == nil || run.Upstreams[i].UpdateTime.After(upstream.UpdateTime) {
+				upstream = &run.Upstreams[i]
+			}
+		}
+		if upstream == nil {
+			return nil, nil
+		}
+
+		upstreamProject = &UpstreamProject{
+			Revision: upstream.Revision,
+		}
+		if upstream.VersionID != "" {
+			upstreamVersion, err := model.VersionFindOneIdWithBuildVariants(ctx, upstream.VersionID)
+			if err != nil {
+				return nil, InternalServerError.Send(ctx, fmt.Sprintf("fetching upstream version '%s': %s", upstream.VersionID, err.Error()))
+			}
+			if upstreamVersion != nil {
+				apiVersion := restModel.APIVersion{}
+				apiVersion.BuildFromService(ctx, *upstreamVersion)
+				upstreamProject.Version = &apiVersion
+			}
+		}
+		projectID = upstream.ProjectID
 	} else if v.TriggerType == model.ProjectTriggerLevelPush {
 		projectID = v.TriggerID
 		upstreamProject = &UpstreamProject{
 			Revision: v.TriggerSHA,
 		}
 	}
+	if upstreamProject == nil {
+		return nil, nil
+	}
 	upstreamProjectRef, err := model.FindBranchProjectRefSecondary(ctx, projectID)
 	if err != nil {
 		return nil, InternalServerError.Send(ctx, fmt.Sprintf("fetching upstream project '%s': %s", projectID, err.Error()))
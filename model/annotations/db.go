@@ -25,12 +25,13 @@ var (
 )
 
 const (
-	Collection            = "task_annotations"
-	UIRequester           = "ui"
-	APIRequester          = "api"
-	WebhookRequester      = "webhook"
-	MaxMetadataLinks      = 1
-	MaxMetadataTextLength = 40
+	Collection             = "task_annotations"
+	UIRequester            = "ui"
+	APIRequester           = "api"
+	WebhookRequester       = "webhook"
+	CulpritFinderRequester = "culprit_finder"
+	MaxMetadataLinks       = 1
+	MaxMetadataTextLength  = 40
 )
 
 // FindOne gets one TaskAnnotation for the given query.
@@ -220,3 +220,23 @@ func ValidateMetadataLinks(links ...MetadataLink) error {
 	}
 	return catcher.Resolve()
 }
+
+// AddSuspectedCommitToAnnotation adds a commit found by a culprit-finding
+// bisection to the task's suspected issues.
+func AddSuspectedCommitToAnnotation(ctx context.Context, taskId string, execution int, issue IssueLink) error {
+	issue.Source = &Source{
+		Author:    "evergreen",
+		Time:      time.Now(),
+		Requester: CulpritFinderRequester,
+	}
+
+	_, err := db.Upsert(
+		ctx,
+		Collection,
+		ByTaskIdAndExecution(taskId, execution),
+		bson.M{
+			"$push": bson.M{SuspectedIssuesKey: issue},
+		},
+	)
+	return errors.Wrapf(err, "adding suspected commit to task annotation for task '%s'", taskId)
+}
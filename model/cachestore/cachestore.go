@@ -0,0 +1,201 @@
+// Package cachestore defines the layout of the content-addressed stores that
+// cache.save and cache.restore use for chunked caches, and the garbage
+// collection of blobs that no cache references anymore.
+//
+// A store lives under a key prefix in a bucket:
+//
+//	<prefix>/blobs/<hash[:2]>/<hash>            gzipped chunk, named by the SHA-256 of its contents
+//	<prefix>/manifests/<remote path>/<key>/<name>.json
+//	<prefix>/pending/<id>.json                  manifest of a cache.save in progress
+//
+// Every cache saved to the same store shares blobs, regardless of which
+// project or cache key saved them.
+package cachestore
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultPrefix is the key prefix of the store used when the cache
+	// commands don't specify one.
+	DefaultPrefix = "evergreen-cache-store"
+
+	// ManifestVersion is the version of the manifest format written by
+	// cache.save.
+	ManifestVersion = 1
+
+	// DefaultManifestTTL is how long a manifest is kept after it's written
+	// before garbage collection removes it. Once a manifest expires, the next
+	// cache.save for the same key writes a new one, reusing whichever blobs
+	// are still stored.
+	DefaultManifestTTL = 30 * 24 * time.Hour
+
+	// DefaultPendingTTL is how long a pending manifest protects its blobs
+	// from garbage collection. It only needs to outlast a single cache.save,
+	// so anything older was left behind by a save that didn't finish.
+	DefaultPendingTTL = 24 * time.Hour
+
+	blobsDir          = "blobs"
+	manifestsDir      = "manifests"
+	pendingDir        = "pending"
+	manifestExtension = ".json"
+)
+
+// EntryType is the kind of file system entry in a manifest.
+type EntryType string
+
+const (
+	EntryTypeFile    EntryType = "file"
+	EntryTypeDir     EntryType = "dir"
+	EntryTypeSymlink EntryType = "symlink"
+)
+
+// Manifest describes a saved cache. The contents of the regular files, in
+// entry order, are concatenated into a single stream that is split into the
+// listed chunks.
+type Manifest struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	Entries   []Entry   `json:"entries"`
+	Chunks    []Chunk   `json:"chunks"`
+}
+
+// Entry is a file, directory, or symlink in a cache.
+type Entry struct {
+	// Path is the slash-separated path of the entry, relative to the
+	// directory the cache is restored into.
+	Path string    `json:"path"`
+	Type EntryType `json:"type"`
+	// Mode holds the permission bits of the entry.
+	Mode uint32 `json:"mode"`
+	// Size is the number of bytes the entry contributes to the content
+	// stream. It's only set for regular files.
+	Size int64 `json:"size,omitempty"`
+	// LinkTarget is the raw target of a symlink.
+	LinkTarget string `json:"link_target,omitempty"`
+}
+
+// Chunk is a contiguous piece of the content stream.
+type Chunk struct {
+	// Hash is the hex-encoded SHA-256 of the chunk contents.
+	Hash string `json:"hash"`
+	Size int64  `json:"size"`
+}
+
+// Validate checks that the manifest is well-formed and safe to restore. It
+// doesn't check that the entry paths stay within any particular directory,
+// which is up to the caller restoring the cache.
+func (m *Manifest) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	catcher.ErrorfWhen(m.Version != ManifestVersion, "unsupported manifest version %d", m.Version)
+
+	var contentSize int64
+	seen := map[string]bool{}
+	for _, e := range m.Entries {
+		catcher.ErrorfWhen(e.Path == "" || path.IsAbs(e.Path) || path.Clean(e.Path) != e.Path, "entry path '%s' must be a clean relative path", e.Path)
+		catcher.ErrorfWhen(e.Path == ".." || strings.HasPrefix(e.Path, "../"), "entry path '%s' must not refer to a parent directory", e.Path)
+		catcher.ErrorfWhen(seen[e.Path], "duplicate entry path '%s'", e.Path)
+		seen[e.Path] = true
+		switch e.Type {
+		case EntryTypeFile:
+			catcher.ErrorfWhen(e.Size < 0, "entry '%s' has negative size", e.Path)
+			contentSize += e.Size
+		case EntryTypeDir:
+		case EntryTypeSymlink:
+			catcher.ErrorfWhen(e.LinkTarget == "", "symlink entry '%s' must have a target", e.Path)
+		default:
+			catcher.Errorf("entry '%s' has invalid type '%s'", e.Path, e.Type)
+		}
+	}
+
+	var chunkedSize int64
+	for _, c := range m.Chunks {
+		catcher.ErrorfWhen(!IsValidHash(c.Hash), "chunk hash '%s' is not a SHA-256 hex digest", c.Hash)
+		catcher.ErrorfWhen(c.Size <= 0, "chunk '%s' must have a positive size", c.Hash)
+		chunkedSize += c.Size
+	}
+	catcher.ErrorfWhen(contentSize != chunkedSize, "file sizes add up to %d bytes but chunks add up to %d bytes", contentSize, chunkedSize)
+
+	return catcher.Resolve()
+}
+
+// Blobs returns the distinct hashes of the chunks in the manifest, sorted.
+func (m *Manifest) Blobs() []string {
+	seen := map[string]bool{}
+	var hashes []string
+	for _, c := range m.Chunks {
+		if seen[c.Hash] {
+			continue
+		}
+		seen[c.Hash] = true
+		hashes = append(hashes, c.Hash)
+	}
+	sort.Strings(hashes)
+	return hashes
+}
+
+// MarshalManifest encodes a manifest for storage.
+func MarshalManifest(m *Manifest) ([]byte, error) {
+	b, err := json.Marshal(m)
+	return b, errors.Wrap(err, "marshalling manifest")
+}
+
+// UnmarshalManifest decodes and validates a stored manifest.
+func UnmarshalManifest(b []byte) (*Manifest, error) {
+	m := &Manifest{}
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil, errors.Wrap(err, "unmarshalling manifest")
+	}
+	if err := m.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid manifest")
+	}
+	return m, nil
+}
+
+// IsValidHash returns whether s is a lowercase hex-encoded SHA-256 digest.
+func IsValidHash(s string) bool {
+	if len(s) != 64 || strings.ToLower(s) != s {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+// BlobKey returns the key of the blob with the given hash in the store at
+// prefix.
+func BlobKey(prefix, hash string) string {
+	return path.Join(prefix, blobsDir, hash[:2], hash)
+}
+
+// ManifestKey returns the key of the manifest for the named cache saved under
+// remotePath with the given cache key.
+func ManifestKey(prefix, remotePath, key, name string) string {
+	return path.Join(prefix, manifestsDir, remotePath, key, name+manifestExtension)
+}
+
+// PendingKey returns the key of the pending manifest for the cache.save with
+// the given ID.
+func PendingKey(prefix, id string) string {
+	return path.Join(prefix, pendingDir, id+manifestExtension)
+}
+
+func blobsPrefix(prefix string) string {
+	return path.Join(prefix, blobsDir) + "/"
+}
+
+func manifestsPrefix(prefix string) string {
+	return path.Join(prefix, manifestsDir) + "/"
+}
+
+func pendingPrefix(prefix string) string {
+	return path.Join(prefix, pendingDir) + "/"
+}
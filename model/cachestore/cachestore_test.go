@@ -0,0 +1,211 @@
+package cachestore
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/pail"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func hashOf(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestManifestValidate(t *testing.T) {
+	validManifest := func() *Manifest {
+		return &Manifest{
+			Version: ManifestVersion,
+			Entries: []Entry{
+				{Path: "dir", Type: EntryTypeDir, Mode: 0755},
+				{Path: "dir/file", Type: EntryTypeFile, Mode: 0644, Size: 3},
+				{Path: "dir/link", Type: EntryTypeSymlink, LinkTarget: "file"},
+			},
+			Chunks: []Chunk{{Hash: hashOf("abc"), Size: 3}},
+		}
+	}
+
+	t.Run("Succeeds", func(t *testing.T) {
+		assert.NoError(t, validManifest().Validate())
+	})
+	t.Run("FailsWithUnknownVersion", func(t *testing.T) {
+		m := validManifest()
+		m.Version = ManifestVersion + 1
+		assert.Error(t, m.Validate())
+	})
+	for name, badPath := range map[string]string{
+		"Absolute":   "/etc/passwd",
+		"Parent":     "../file",
+		"Unclean":    "dir/../file",
+		"Empty":      "",
+		"ParentOnly": "..",
+	} {
+		t.Run("FailsWithPath"+name, func(t *testing.T) {
+			m := validManifest()
+			m.Entries[0].Path = badPath
+			assert.Error(t, m.Validate())
+		})
+	}
+	t.Run("FailsWithDuplicatePath", func(t *testing.T) {
+		m := validManifest()
+		m.Entries[2].Path = "dir/file"
+		assert.Error(t, m.Validate())
+	})
+	t.Run("FailsWithSymlinkWithoutTarget", func(t *testing.T) {
+		m := validManifest()
+		m.Entries[2].LinkTarget = ""
+		assert.Error(t, m.Validate())
+	})
+	t.Run("FailsWithInvalidHash", func(t *testing.T) {
+		m := validManifest()
+		m.Chunks[0].Hash = "not-a-hash"
+		assert.Error(t, m.Validate())
+	})
+	t.Run("FailsWhenChunksDoNotCoverFiles", func(t *testing.T) {
+		m := validManifest()
+		m.Entries[1].Size = 4
+		assert.Error(t, m.Validate())
+	})
+}
+
+func TestManifestRoundTrip(t *testing.T) {
+	m := &Manifest{
+		Version:   ManifestVersion,
+		CreatedAt: time.Now().Round(time.Second),
+		Entries:   []Entry{{Path: "file", Type: EntryTypeFile, Mode: 0600, Size: 6}},
+		Chunks: []Chunk{
+			{Hash: hashOf("abc"), Size: 3},
+			{Hash: hashOf("abc"), Size: 3},
+		},
+	}
+	b, err := MarshalManifest(m)
+	require.NoError(t, err)
+	decoded, err := UnmarshalManifest(b)
+	require.NoError(t, err)
+	assert.True(t, m.CreatedAt.Equal(decoded.CreatedAt))
+	assert.Equal(t, m.Entries, decoded.Entries)
+	assert.Equal(t, m.Chunks, decoded.Chunks)
+	assert.Equal(t, []string{hashOf("abc")}, decoded.Blobs())
+
+	_, err = UnmarshalManifest([]byte(`{"version": 1, "entries": [{"path": "../x", "type": "dir"}]}`))
+	assert.Error(t, err)
+}
+
+func TestKeys(t *testing.T) {
+	hash := hashOf("abc")
+	assert.Equal(t, "store/blobs/"+hash[:2]+"/"+hash, BlobKey("store", hash))
+	assert.Equal(t, "store/manifests/project/caches/key/deps.json", ManifestKey("store", "project/caches", "key", "deps"))
+	assert.Equal(t, "store/pending/id.json", PendingKey("store", "id"))
+}
+
+func TestCollectGarbage(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const prefix = "store"
+
+	putBlob := func(t *testing.T, bucket pail.Bucket, contents string) string {
+		hash := hashOf(contents)
+		require.NoError(t, bucket.Put(ctx, BlobKey(prefix, hash), bytes.NewBufferString(contents)))
+		return hash
+	}
+	putManifest := func(t *testing.T, bucket pail.Bucket, key string, createdAt time.Time, hashes ...string) {
+		m := &Manifest{Version: ManifestVersion, CreatedAt: createdAt}
+		for _, hash := range hashes {
+			m.Chunks = append(m.Chunks, Chunk{Hash: hash, Size: 1})
+		}
+		m.Entries = []Entry{{Path: "file", Type: EntryTypeFile, Size: int64(len(hashes))}}
+		b, err := MarshalManifest(m)
+		require.NoError(t, err)
+		require.NoError(t, bucket.Put(ctx, key, bytes.NewReader(b)))
+	}
+	exists := func(t *testing.T, bucket pail.Bucket, key string) bool {
+		ok, err := bucket.Exists(ctx, key)
+		require.NoError(t, err)
+		return ok
+	}
+
+	for tName, tCase := range map[string]func(t *testing.T, bucket pail.Bucket){
+		"RemovesOnlyUnreferencedBlobs": func(t *testing.T, bucket pail.Bucket) {
+			shared := putBlob(t, bucket, "a")
+			onlyFirst := putBlob(t, bucket, "b")
+			orphaned := putBlob(t, bucket, "c")
+			putManifest(t, bucket, ManifestKey(prefix, "project1", "key1", "deps"), time.Now(), shared, onlyFirst)
+			putManifest(t, bucket, ManifestKey(prefix, "project2", "key2", "deps"), time.Now(), shared)
+
+			res, err := CollectGarbage(ctx, bucket, GCOptions{Prefix: prefix})
+			require.NoError(t, err)
+			assert.Equal(t, 2, res.BlobsRetained)
+			assert.Equal(t, 1, res.BlobsRemoved)
+			assert.EqualValues(t, 1, res.BytesRemoved)
+			assert.Zero(t, res.ManifestsRemoved)
+
+			assert.True(t, exists(t, bucket, BlobKey(prefix, shared)))
+			assert.True(t, exists(t, bucket, BlobKey(prefix, onlyFirst)))
+			assert.False(t, exists(t, bucket, BlobKey(prefix, orphaned)))
+		},
+		"RemovesExpiredManifestsAndTheirBlobs": func(t *testing.T, bucket pail.Bucket) {
+			shared := putBlob(t, bucket, "a")
+			stale := putBlob(t, bucket, "b")
+			expiredKey := ManifestKey(prefix, "project", "old", "deps")
+			putManifest(t, bucket, expiredKey, time.Now().Add(-48*time.Hour), shared, stale)
+			putManifest(t, bucket, ManifestKey(prefix, "project", "new", "deps"), time.Now(), shared)
+
+			res, err := CollectGarbage(ctx, bucket, GCOptions{Prefix: prefix, ManifestTTL: 24 * time.Hour})
+			require.NoError(t, err)
+			assert.Equal(t, 1, res.ManifestsRemoved)
+			assert.Equal(t, 1, res.BlobsRemoved)
+
+			assert.False(t, exists(t, bucket, expiredKey))
+			assert.True(t, exists(t, bucket, BlobKey(prefix, shared)))
+			assert.False(t, exists(t, bucket, BlobKey(prefix, stale)))
+		},
+		"PendingManifestsProtectBlobsUntilTheyExpire": func(t *testing.T, bucket pail.Bucket) {
+			inProgress := putBlob(t, bucket, "a")
+			abandoned := putBlob(t, bucket, "b")
+			putManifest(t, bucket, PendingKey(prefix, "current"), time.Now(), inProgress)
+			abandonedKey := PendingKey(prefix, "abandoned")
+			putManifest(t, bucket, abandonedKey, time.Now().Add(-2*DefaultPendingTTL), abandoned)
+
+			res, err := CollectGarbage(ctx, bucket, GCOptions{Prefix: prefix})
+			require.NoError(t, err)
+			assert.Equal(t, 1, res.PendingRemoved)
+			assert.True(t, exists(t, bucket, BlobKey(prefix, inProgress)))
+			assert.False(t, exists(t, bucket, BlobKey(prefix, abandoned)))
+			assert.False(t, exists(t, bucket, abandonedKey))
+		},
+		"DryRunDoesNotRemoveAnything": func(t *testing.T, bucket pail.Bucket) {
+			orphaned := putBlob(t, bucket, "a")
+
+			res, err := CollectGarbage(ctx, bucket, GCOptions{Prefix: prefix, DryRun: true})
+			require.NoError(t, err)
+			assert.Equal(t, 1, res.BlobsRemoved)
+			assert.True(t, exists(t, bucket, BlobKey(prefix, orphaned)))
+		},
+		"FailsWithoutRemovingBlobsWhenManifestIsUnreadable": func(t *testing.T, bucket pail.Bucket) {
+			orphaned := putBlob(t, bucket, "a")
+			require.NoError(t, bucket.Put(ctx, ManifestKey(prefix, "project", "key", "deps"), bytes.NewBufferString("not json")))
+
+			_, err := CollectGarbage(ctx, bucket, GCOptions{Prefix: prefix})
+			assert.Error(t, err)
+			assert.True(t, exists(t, bucket, BlobKey(prefix, orphaned)))
+		},
+		"SucceedsWithEmptyStore": func(t *testing.T, bucket pail.Bucket) {
+			res, err := CollectGarbage(ctx, bucket, GCOptions{Prefix: prefix})
+			require.NoError(t, err)
+			assert.Zero(t, res)
+		},
+	} {
+		t.Run(tName, func(t *testing.T) {
+			bucket, err := pail.NewLocalBucket(pail.LocalOptions{Path: t.TempDir()})
+			require.NoError(t, err)
+			tCase(t, bucket)
+		})
+	}
+}
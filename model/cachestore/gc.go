@@ -0,0 +1,167 @@
+package cachestore
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/pail"
+	"github.com/pkg/errors"
+)
+
+// gcRemoveBatchSize is the maximum number of objects removed per request,
+// which is the limit S3 places on a multi-object delete.
+const gcRemoveBatchSize = 1000
+
+// GCOptions configure a garbage collection run over a store.
+type GCOptions struct {
+	// Prefix is the key prefix of the store.
+	Prefix string
+	// ManifestTTL is how long manifests are kept after they're written.
+	ManifestTTL time.Duration
+	// PendingTTL is how long pending manifests protect their blobs.
+	PendingTTL time.Duration
+	// DryRun reports what would be removed without removing anything.
+	DryRun bool
+}
+
+// GCResult summarizes a garbage collection run.
+type GCResult struct {
+	ManifestsRemoved int
+	PendingRemoved   int
+	BlobsRetained    int
+	BlobsRemoved     int
+	BytesRemoved     int64
+}
+
+// CollectGarbage removes expired manifests from the store, then removes every
+// blob that no remaining manifest (including the pending manifests of saves in
+// progress) references.
+//
+// Blobs are listed before manifests are read, so a blob uploaded by a save
+// that starts partway through a run is never considered for removal. A blob
+// can still be removed while a concurrent save is reusing it if the blob was
+// unreferenced when the run began. cache.restore treats the resulting missing
+// blob as a cache miss and removes the manifest that refers to it, so the next
+// cache.save of that key uploads the cache again.
+func CollectGarbage(ctx context.Context, bucket pail.Bucket, opts GCOptions) (GCResult, error) {
+	if opts.ManifestTTL <= 0 {
+		opts.ManifestTTL = DefaultManifestTTL
+	}
+	if opts.PendingTTL <= 0 {
+		opts.PendingTTL = DefaultPendingTTL
+	}
+	if opts.Prefix == "" {
+		opts.Prefix = DefaultPrefix
+	}
+
+	res := GCResult{}
+
+	blobSizes := map[string]int64{}
+	err := listKeys(ctx, bucket, blobsPrefix(opts.Prefix), func(item pail.BucketItem) error {
+		blobSizes[item.Name()] = item.Size()
+		return nil
+	})
+	if err != nil {
+		return res, errors.Wrap(err, "listing blobs")
+	}
+
+	now := time.Now()
+	referenced := map[string]bool{}
+	var expired []string
+	markReferences := func(ttl time.Duration, numRemoved *int) func(pail.BucketItem) error {
+		return func(item pail.BucketItem) error {
+			m, err := readManifest(ctx, item)
+			if err != nil {
+				// Without the manifest, it's unknown which blobs are still
+				// needed, so no blob can be safely removed.
+				return errors.Wrapf(err, "reading manifest '%s'", item.Name())
+			}
+			if now.Sub(m.CreatedAt) > ttl {
+				expired = append(expired, item.Name())
+				*numRemoved++
+				return nil
+			}
+			for _, hash := range m.Blobs() {
+				referenced[BlobKey(opts.Prefix, hash)] = true
+			}
+			return nil
+		}
+	}
+	if err := listKeys(ctx, bucket, manifestsPrefix(opts.Prefix), markReferences(opts.ManifestTTL, &res.ManifestsRemoved)); err != nil {
+		return res, errors.Wrap(err, "reading manifests")
+	}
+	if err := listKeys(ctx, bucket, pendingPrefix(opts.Prefix), markReferences(opts.PendingTTL, &res.PendingRemoved)); err != nil {
+		return res, errors.Wrap(err, "reading pending manifests")
+	}
+
+	var unreferenced []string
+	for key, size := range blobSizes {
+		if referenced[key] {
+			res.BlobsRetained++
+			continue
+		}
+		unreferenced = append(unreferenced, key)
+		res.BlobsRemoved++
+		res.BytesRemoved += size
+	}
+
+	if opts.DryRun {
+		return res, nil
+	}
+
+	// Manifests are removed before their blobs so that a failure partway
+	// through never leaves a manifest referring to removed blobs.
+	if err := removeKeys(ctx, bucket, expired); err != nil {
+		return res, errors.Wrap(err, "removing expired manifests")
+	}
+	if err := removeKeys(ctx, bucket, unreferenced); err != nil {
+		return res, errors.Wrap(err, "removing unreferenced blobs")
+	}
+
+	return res, nil
+}
+
+func listKeys(ctx context.Context, bucket pail.Bucket, prefix string, handle func(pail.BucketItem) error) error {
+	iter, err := bucket.List(ctx, prefix)
+	if err != nil {
+		return errors.Wrapf(err, "listing prefix '%s'", prefix)
+	}
+	for iter.Next(ctx) {
+		item := iter.Item()
+		// Skip directory markers, which aren't written by the cache commands
+		// but may have been created by other tools.
+		if strings.HasSuffix(item.Name(), "/") {
+			continue
+		}
+		if err := handle(item); err != nil {
+			return err
+		}
+	}
+	return errors.Wrapf(iter.Err(), "iterating over prefix '%s'", prefix)
+}
+
+func readManifest(ctx context.Context, item pail.BucketItem) (*Manifest, error) {
+	r, err := item.Get(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting manifest")
+	}
+	defer r.Close()
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading manifest")
+	}
+	return UnmarshalManifest(b)
+}
+
+func removeKeys(ctx context.Context, bucket pail.Bucket, keys []string) error {
+	for start := 0; start < len(keys); start += gcRemoveBatchSize {
+		end := min(start+gcRemoveBatchSize, len(keys))
+		if err := bucket.RemoveMany(ctx, keys[start:end]...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -70,6 +70,27 @@ func (c Cost) AdjustedTotal() float64 {
 		c.AdjustedS3LogStorageCost
 }
 
+// Add returns the sum of the cost components of c and other. Total and
+// ChildPatchesTotalCost are not summed.
+func (c Cost) Add(other Cost) Cost {
+	return Cost{
+		OnDemandEC2Cost:               c.OnDemandEC2Cost + other.OnDemandEC2Cost,
+		AdjustedEC2Cost:               c.AdjustedEC2Cost + other.AdjustedEC2Cost,
+		OnDemandEBSThroughputCost:     c.OnDemandEBSThroughputCost + other.OnDemandEBSThroughputCost,
+		AdjustedEBSThroughputCost:     c.AdjustedEBSThroughputCost + other.AdjustedEBSThroughputCost,
+		OnDemandEBSStorageCost:        c.OnDemandEBSStorageCost + other.OnDemandEBSStorageCost,
+		AdjustedEBSStorageCost:        c.AdjustedEBSStorageCost + other.AdjustedEBSStorageCost,
+		OnDemandS3ArtifactPutCost:     c.OnDemandS3ArtifactPutCost + other.OnDemandS3ArtifactPutCost,
+		AdjustedS3ArtifactPutCost:     c.AdjustedS3ArtifactPutCost + other.AdjustedS3ArtifactPutCost,
+		OnDemandS3LogPutCost:          c.OnDemandS3LogPutCost + other.OnDemandS3LogPutCost,
+		AdjustedS3LogPutCost:          c.AdjustedS3LogPutCost + other.AdjustedS3LogPutCost,
+		OnDemandS3ArtifactStorageCost: c.OnDemandS3ArtifactStorageCost + other.OnDemandS3ArtifactStorageCost,
+		AdjustedS3ArtifactStorageCost: c.AdjustedS3ArtifactStorageCost + other.AdjustedS3ArtifactStorageCost,
+		OnDemandS3LogStorageCost:      c.OnDemandS3LogStorageCost + other.OnDemandS3LogStorageCost,
+		AdjustedS3LogStorageCost:      c.AdjustedS3LogStorageCost + other.AdjustedS3LogStorageCost,
+	}
+}
+
 // RoundedBase returns a new Cost with the 7 adjusted fields individually rounded.
 func (c Cost) RoundedBase() Cost {
 	return Cost{
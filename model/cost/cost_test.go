@@ -56,6 +56,19 @@ func TestCostAdjustedTotal(t *testing.T) {
 	assert.InDelta(t, 10.0, withChildren.AdjustedTotal()+withChildren.ChildPatchesTotalCost, 1e-9)
 }
 
+func TestCostAdd(t *testing.T) {
+	a := Cost{OnDemandEC2Cost: 1, AdjustedEC2Cost: 0.5, AdjustedEBSStorageCost: 0.25, Total: 100}
+	b := Cost{OnDemandEC2Cost: 2, AdjustedEC2Cost: 1, AdjustedS3LogPutCost: 0.1, ChildPatchesTotalCost: 100}
+	sum := a.Add(b)
+	assert.InDelta(t, 3, sum.OnDemandEC2Cost, 1e-9)
+	assert.InDelta(t, 1.5, sum.AdjustedEC2Cost, 1e-9)
+	assert.InDelta(t, 0.25, sum.AdjustedEBSStorageCost, 1e-9)
+	assert.InDelta(t, 0.1, sum.AdjustedS3LogPutCost, 1e-9)
+	assert.Zero(t, sum.Total)
+	assert.Zero(t, sum.ChildPatchesTotalCost)
+	assert.InDelta(t, a.AdjustedTotal()+b.AdjustedTotal(), sum.AdjustedTotal(), 1e-9)
+}
+
 func TestRoundCost(t *testing.T) {
 	t.Run("ZeroInputReturnsZero", func(t *testing.T) {
 		assert.Equal(t, 0.0, RoundCost(0))
@@ -0,0 +1,210 @@
+package culprit
+
+import (
+	"math/bits"
+	"time"
+)
+
+// BisectionStatus is the state of a culprit-finding bisection.
+type BisectionStatus string
+
+const (
+	// BisectionRunning indicates that the bisection is still narrowing down
+	// the range of commits that could have caused the failures.
+	BisectionRunning BisectionStatus = "running"
+	// BisectionFound indicates that the bisection narrowed the range down to
+	// a single suspected commit.
+	BisectionFound BisectionStatus = "found"
+	// BisectionInconclusive indicates that the bisection stopped without a
+	// suspected commit, for example because a bisection task failed for a
+	// reason other than a test failure.
+	BisectionInconclusive BisectionStatus = "inconclusive"
+)
+
+// StepResult is the outcome of running the bisection tasks for one commit.
+type StepResult string
+
+const (
+	StepPending StepResult = "pending"
+	StepPassed  StepResult = "passed"
+	StepFailed  StepResult = "failed"
+)
+
+// TaskKey identifies the same task across versions.
+type TaskKey struct {
+	BuildVariant string `bson:"build_variant" json:"build_variant"`
+	DisplayName  string `bson:"display_name" json:"display_name"`
+}
+
+// Candidate is a mainline version in the bisection's range that has all of
+// the bisection's representative tasks, so it can be tested.
+type Candidate struct {
+	Order     int    `bson:"order"`
+	VersionID string `bson:"version_id"`
+	Revision  string `bson:"revision"`
+	// TaskIDs are the version's representative tasks.
+	TaskIDs []string `bson:"task_ids"`
+}
+
+// Step is a single iteration of the bisection, which runs the representative
+// tasks for one candidate.
+type Step struct {
+	Order      int        `bson:"order"`
+	VersionID  string     `bson:"version_id"`
+	Revision   string     `bson:"revision"`
+	TaskIDs    []string   `bson:"task_ids"`
+	Result     StepResult `bson:"result"`
+	StartedAt  time.Time  `bson:"started_at"`
+	FinishedAt time.Time  `bson:"finished_at,omitempty"`
+}
+
+// Bisection searches the mainline commits between the last version where a
+// group of correlated tasks passed and the version where they failed for the
+// commit that most likely caused the failures.
+type Bisection struct {
+	ID        string `bson:"_id"`
+	ProjectID string `bson:"project_id"`
+	// VersionID is the version whose failures started the bisection.
+	VersionID string `bson:"version_id"`
+	// Tasks are the tasks that failed together in the version and last
+	// passed in the same version.
+	Tasks []TaskKey `bson:"tasks"`
+	// FailedTaskIDs are the IDs of the failed tasks in the version.
+	FailedTaskIDs []string `bson:"failed_task_ids"`
+	// Representatives are the subset of tasks that are run to test each
+	// commit: one per build variant.
+	Representatives []TaskKey `bson:"representatives"`
+
+	// RangeStartOrder and RangeEndOrder are the revision order numbers of
+	// the last passing and first failing versions when the bisection
+	// started.
+	RangeStartOrder int `bson:"range_start_order"`
+	RangeEndOrder   int `bson:"range_end_order"`
+
+	// LastPassingOrder and FirstFailingOrder narrow as the bisection
+	// progresses.
+	LastPassingOrder      int    `bson:"last_passing_order"`
+	LastPassingVersionID  string `bson:"last_passing_version_id"`
+	FirstFailingOrder     int    `bson:"first_failing_order"`
+	FirstFailingVersionID string `bson:"first_failing_version_id"`
+	FirstFailingRevision  string `bson:"first_failing_revision"`
+
+	Candidates []Candidate `bson:"candidates,omitempty"`
+	Steps      []Step      `bson:"steps,omitempty"`
+
+	Status             BisectionStatus `bson:"status"`
+	SuspectedVersionID string          `bson:"suspected_version_id,omitempty"`
+	SuspectedRevision  string          `bson:"suspected_revision,omitempty"`
+	// Reason explains why an inconclusive bisection stopped.
+	Reason string `bson:"reason,omitempty"`
+
+	CreatedAt  time.Time `bson:"created_at"`
+	FinishedAt time.Time `bson:"finished_at,omitempty"`
+}
+
+// RemainingCandidates returns the candidates that are still between the last
+// passing and first failing versions.
+func (b *Bisection) RemainingCandidates() []Candidate {
+	remaining := []Candidate{}
+	for _, c := range b.Candidates {
+		if c.Order > b.LastPassingOrder && c.Order < b.FirstFailingOrder {
+			remaining = append(remaining, c)
+		}
+	}
+	return remaining
+}
+
+// NextCandidate returns the candidate halfway through the remaining range,
+// or nil if there are no candidates left to test. Candidates must be sorted
+// by order.
+func (b *Bisection) NextCandidate() *Candidate {
+	remaining := b.RemainingCandidates()
+	if len(remaining) == 0 {
+		return nil
+	}
+	return &remaining[(len(remaining)-1)/2]
+}
+
+// CurrentStep returns the step that is waiting for its tasks to finish, if
+// any.
+func (b *Bisection) CurrentStep() *Step {
+	if len(b.Steps) == 0 {
+		return nil
+	}
+	step := &b.Steps[len(b.Steps)-1]
+	if step.Result != StepPending {
+		return nil
+	}
+	return step
+}
+
+// StepsRemaining estimates how many more steps the bisection needs before it
+// finds a suspected commit.
+func (b *Bisection) StepsRemaining() int {
+	if b.Status != BisectionRunning {
+		return 0
+	}
+	// A binary search over n candidates takes at most ceil(log2(n+1))
+	// steps, including the current step's candidate.
+	return bits.Len(uint(len(b.RemainingCandidates())))
+}
+
+// AddStep starts a new step to test the candidate.
+func (b *Bisection) AddStep(c Candidate, now time.Time) {
+	b.Steps = append(b.Steps, Step{
+		Order:     c.Order,
+		VersionID: c.VersionID,
+		Revision:  c.Revision,
+		TaskIDs:   c.TaskIDs,
+		Result:    StepPending,
+		StartedAt: now,
+	})
+}
+
+// RecordResult finishes the current step and narrows the range.
+func (b *Bisection) RecordResult(result StepResult, now time.Time) {
+	step := b.CurrentStep()
+	if step == nil || result == StepPending {
+		return
+	}
+	step.Result = result
+	step.FinishedAt = now
+	switch result {
+	case StepPassed:
+		b.LastPassingOrder = step.Order
+		b.LastPassingVersionID = step.VersionID
+	case StepFailed:
+		b.FirstFailingOrder = step.Order
+		b.FirstFailingVersionID = step.VersionID
+		b.FirstFailingRevision = step.Revision
+	}
+}
+
+// Conclude marks the bisection as found if there are no candidates left to
+// test, with the first failing version as the suspect. It returns whether the
+// bisection was concluded.
+func (b *Bisection) Conclude(now time.Time) bool {
+	if b.Status != BisectionRunning || b.CurrentStep() != nil || len(b.RemainingCandidates()) > 0 {
+		return false
+	}
+	b.Status = BisectionFound
+	b.SuspectedVersionID = b.FirstFailingVersionID
+	b.SuspectedRevision = b.FirstFailingRevision
+	b.FinishedAt = now
+	return true
+}
+
+// Abandon marks the bisection as inconclusive.
+func (b *Bisection) Abandon(reason string, now time.Time) {
+	b.Status = BisectionInconclusive
+	b.Reason = reason
+	b.FinishedAt = now
+}
+
+// ShortRevision abbreviates a commit hash for display.
+func ShortRevision(revision string) string {
+	if len(revision) > 7 {
+		return revision[:7]
+	}
+	return revision
+}
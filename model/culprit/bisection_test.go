@@ -0,0 +1,105 @@
+package culprit
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func makeTestBisection() Bisection {
+	b := Bisection{
+		ID:                    "b",
+		LastPassingOrder:      10,
+		LastPassingVersionID:  "v10",
+		FirstFailingOrder:     17,
+		FirstFailingVersionID: "v17",
+		FirstFailingRevision:  "rev17",
+		Status:                BisectionRunning,
+	}
+	// Version 13 doesn't have the representative tasks, so it isn't a
+	// candidate.
+	for _, order := range []int{11, 12, 14, 15, 16} {
+		b.Candidates = append(b.Candidates, Candidate{
+			Order:     order,
+			VersionID: "v" + strconv.Itoa(order),
+			Revision:  "rev" + strconv.Itoa(order),
+		})
+	}
+	return b
+}
+
+func TestBisectionFindsCulprit(t *testing.T) {
+	now := time.Now()
+	b := makeTestBisection()
+	assert.Len(t, b.RemainingCandidates(), 5)
+	assert.Equal(t, 3, b.StepsRemaining())
+	assert.Nil(t, b.CurrentStep())
+	assert.False(t, b.Conclude(now), "should not conclude while candidates remain")
+
+	next := b.NextCandidate()
+	require.NotNil(t, next)
+	assert.Equal(t, 14, next.Order)
+	b.AddStep(*next, now)
+	require.NotNil(t, b.CurrentStep())
+	assert.False(t, b.Conclude(now), "should not conclude while a step is pending")
+
+	b.RecordResult(StepPassed, now)
+	assert.Nil(t, b.CurrentStep())
+	assert.Equal(t, 14, b.LastPassingOrder)
+	assert.Len(t, b.RemainingCandidates(), 2)
+
+	next = b.NextCandidate()
+	require.NotNil(t, next)
+	assert.Equal(t, 15, next.Order)
+	b.AddStep(*next, now)
+	b.RecordResult(StepFailed, now)
+	assert.Equal(t, 15, b.FirstFailingOrder)
+	assert.Equal(t, next.VersionID, b.FirstFailingVersionID)
+	assert.Empty(t, b.RemainingCandidates())
+	assert.Nil(t, b.NextCandidate())
+
+	require.True(t, b.Conclude(now))
+	assert.Equal(t, BisectionFound, b.Status)
+	assert.Equal(t, next.VersionID, b.SuspectedVersionID)
+	assert.Equal(t, next.Revision, b.SuspectedRevision)
+	assert.Equal(t, 0, b.StepsRemaining())
+	assert.Len(t, b.Steps, 2)
+}
+
+func TestBisectionConcludesWithoutCandidates(t *testing.T) {
+	b := makeTestBisection()
+	b.Candidates = nil
+	require.True(t, b.Conclude(time.Now()))
+	assert.Equal(t, "v17", b.SuspectedVersionID)
+	assert.Equal(t, "rev17", b.SuspectedRevision)
+}
+
+func TestBisectionRecordResultIgnoresPending(t *testing.T) {
+	now := time.Now()
+	b := makeTestBisection()
+	b.RecordResult(StepFailed, now)
+	assert.Equal(t, 17, b.FirstFailingOrder, "should not narrow the range without a step")
+
+	b.AddStep(*b.NextCandidate(), now)
+	b.RecordResult(StepPending, now)
+	assert.NotNil(t, b.CurrentStep())
+	assert.Equal(t, 10, b.LastPassingOrder)
+}
+
+func TestBisectionAbandon(t *testing.T) {
+	now := time.Now()
+	b := makeTestBisection()
+	b.Abandon("task failed to run", now)
+	assert.Equal(t, BisectionInconclusive, b.Status)
+	assert.Equal(t, "task failed to run", b.Reason)
+	assert.Equal(t, 0, b.StepsRemaining())
+	assert.False(t, b.Conclude(now))
+}
+
+func TestShortRevision(t *testing.T) {
+	assert.Equal(t, "abcdef1", ShortRevision("abcdef1234567890"))
+	assert.Equal(t, "abc", ShortRevision("abc"))
+}
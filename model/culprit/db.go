@@ -0,0 +1,83 @@
+package culprit
+
+import (
+	"context"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/mongodb/anser/bsonutil"
+	adb "github.com/mongodb/anser/db"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const Collection = "culprit_bisections"
+
+var (
+	IdKey              = bsonutil.MustHaveTag(Bisection{}, "ID")
+	ProjectIdKey       = bsonutil.MustHaveTag(Bisection{}, "ProjectID")
+	VersionIdKey       = bsonutil.MustHaveTag(Bisection{}, "VersionID")
+	TasksKey           = bsonutil.MustHaveTag(Bisection{}, "Tasks")
+	RangeStartOrderKey = bsonutil.MustHaveTag(Bisection{}, "RangeStartOrder")
+	StatusKey          = bsonutil.MustHaveTag(Bisection{}, "Status")
+	CreatedAtKey       = bsonutil.MustHaveTag(Bisection{}, "CreatedAt")
+)
+
+// FindOne gets one bisection for the given query.
+func FindOne(ctx context.Context, query db.Q) (*Bisection, error) {
+	b := &Bisection{}
+	err := db.FindOneQ(ctx, Collection, query, b)
+	if adb.ResultsNotFound(err) {
+		return nil, nil
+	}
+	return b, errors.Wrap(err, "finding bisection")
+}
+
+// Find gets every bisection matching the given query.
+func Find(ctx context.Context, query db.Q) ([]Bisection, error) {
+	bisections := []Bisection{}
+	if err := db.FindAllQ(ctx, Collection, query, &bisections); err != nil {
+		return nil, errors.Wrap(err, "finding bisections")
+	}
+	return bisections, nil
+}
+
+// FindOneId gets the bisection with the given ID.
+func FindOneId(ctx context.Context, id string) (*Bisection, error) {
+	return FindOne(ctx, db.Query(bson.M{IdKey: id}))
+}
+
+// FindRunning gets all bisections that haven't finished.
+func FindRunning(ctx context.Context) ([]Bisection, error) {
+	return Find(ctx, db.Query(bson.M{StatusKey: BisectionRunning}))
+}
+
+// FindByProject gets the project's most recent bisections, newest first.
+func FindByProject(ctx context.Context, projectID string, limit int) ([]Bisection, error) {
+	return Find(ctx, db.Query(bson.M{ProjectIdKey: projectID}).Sort([]string{"-" + CreatedAtKey}).Limit(limit))
+}
+
+// FindByVersion gets the bisections started by failures in the version.
+func FindByVersion(ctx context.Context, versionID string) ([]Bisection, error) {
+	return Find(ctx, db.Query(bson.M{VersionIdKey: versionID}))
+}
+
+// FindCoveringTask gets a bisection for the project that already searches the
+// range starting at the given last passing order for the task.
+func FindCoveringTask(ctx context.Context, projectID string, rangeStartOrder int, key TaskKey) (*Bisection, error) {
+	return FindOne(ctx, db.Query(bson.M{
+		ProjectIdKey:       projectID,
+		RangeStartOrderKey: rangeStartOrder,
+		TasksKey:           key,
+	}))
+}
+
+// Insert inserts the bisection.
+func (b *Bisection) Insert(ctx context.Context) error {
+	return errors.Wrapf(db.Insert(ctx, Collection, b), "inserting bisection '%s'", b.ID)
+}
+
+// Replace saves the bisection's current state.
+func (b *Bisection) Replace(ctx context.Context) error {
+	_, err := db.Replace(ctx, Collection, bson.M{IdKey: b.ID}, b)
+	return errors.Wrapf(err, "saving bisection '%s'", b.ID)
+}
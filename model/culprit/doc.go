@@ -0,0 +1,4 @@
+// Package culprit defines the data model for bisections that search a
+// project's mainline commits for the commit that caused a group of task
+// failures.
+package culprit
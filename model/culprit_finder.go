@@ -0,0 +1,402 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/annotations"
+	"github.com/evergreen-ci/evergreen/model/culprit"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// culpritFailedTask is a failed task in a version along with the most recent
+// earlier version where it passed.
+type culpritFailedTask struct {
+	task        task.Task
+	lastPassing task.Task
+}
+
+// StartCulpritFinding starts bisections to find the commits that caused the
+// failures in a mainline version, if the project has culprit finding enabled.
+// Failed tasks that last passed in the same version are assumed to have the
+// same cause, so each such group is bisected together.
+func StartCulpritFinding(ctx context.Context, v *Version) error {
+	if !utility.StringSliceContains(evergreen.SystemVersionRequesterTypes, v.Requester) {
+		return nil
+	}
+	pRef, err := FindMergedProjectRef(ctx, v.Identifier, v.Id, false)
+	if err != nil {
+		return errors.Wrapf(err, "finding project ref '%s'", v.Identifier)
+	}
+	if pRef == nil || !pRef.IsCulpritFinderEnabled() {
+		return nil
+	}
+
+	failedTasks, err := task.Find(ctx, task.FailedTasksByVersion(v.Id))
+	if err != nil {
+		return errors.Wrapf(err, "finding failed tasks for version '%s'", v.Id)
+	}
+
+	groups := map[int][]culpritFailedTask{}
+	for _, t := range failedTasks {
+		if !isCulpritCandidateFailure(&t) {
+			continue
+		}
+		lastPassing, err := t.PreviousCompletedTask(ctx, t.Project, []string{evergreen.TaskSucceeded})
+		if err != nil {
+			return errors.Wrapf(err, "finding last passing task for '%s'", t.Id)
+		}
+		if lastPassing == nil {
+			continue
+		}
+		existing, err := culprit.FindCoveringTask(ctx, v.Identifier, lastPassing.RevisionOrderNumber, culpritTaskKey(&t))
+		if err != nil {
+			return errors.Wrapf(err, "checking for an existing bisection for task '%s'", t.Id)
+		}
+		if existing != nil {
+			continue
+		}
+		groups[lastPassing.RevisionOrderNumber] = append(groups[lastPassing.RevisionOrderNumber], culpritFailedTask{task: t, lastPassing: *lastPassing})
+	}
+
+	catcher := grip.NewBasicCatcher()
+	for lastPassingOrder, group := range groups {
+		catcher.Wrapf(startBisection(ctx, v, lastPassingOrder, group), "starting bisection for tasks that last passed at order %d", lastPassingOrder)
+	}
+	return catcher.Resolve()
+}
+
+// isCulpritCandidateFailure returns whether the task failed in a way that a
+// code change could have caused, and isn't already part of a bisection or
+// stepback.
+func isCulpritCandidateFailure(t *task.Task) bool {
+	if utility.FromStringPtr(t.DisplayTaskId) != "" {
+		// Display tasks are bisected rather than their execution tasks.
+		return false
+	}
+	if t.ActivatedBy == evergreen.CulpritFinderTaskActivator || t.ActivatedBy == evergreen.StepbackTaskActivator {
+		return false
+	}
+	return isTestFailure(t)
+}
+
+// isTestFailure returns whether the task failed because of its own commands
+// rather than a setup or system problem.
+func isTestFailure(t *task.Task) bool {
+	if t.Status != evergreen.TaskFailed || t.Aborted {
+		return false
+	}
+	return t.Details.Type != evergreen.CommandTypeSetup && t.Details.Type != evergreen.CommandTypeSystem
+}
+
+func culpritTaskKey(t *task.Task) culprit.TaskKey {
+	return culprit.TaskKey{BuildVariant: t.BuildVariant, DisplayName: t.DisplayName}
+}
+
+// culpritRepresentatives picks the tasks that are run to test each commit in
+// the bisection: the fastest failed task in each build variant, so that a
+// failure that only happens in one variant is still detected.
+func culpritRepresentatives(group []culpritFailedTask) []culprit.TaskKey {
+	fastest := map[string]*task.Task{}
+	for i := range group {
+		t := &group[i].task
+		current, ok := fastest[t.BuildVariant]
+		if !ok || t.TimeTaken < current.TimeTaken || (t.TimeTaken == current.TimeTaken && t.DisplayName < current.DisplayName) {
+			fastest[t.BuildVariant] = t
+		}
+	}
+
+	representatives := make([]culprit.TaskKey, 0, len(fastest))
+	for _, t := range fastest {
+		representatives = append(representatives, culpritTaskKey(t))
+	}
+	sort.Slice(representatives, func(i, j int) bool {
+		return representatives[i].BuildVariant < representatives[j].BuildVariant
+	})
+	return representatives
+}
+
+func startBisection(ctx context.Context, v *Version, lastPassingOrder int, group []culpritFailedTask) error {
+	now := time.Now()
+	b := &culprit.Bisection{
+		ID:                    fmt.Sprintf("%s_%d_%d", v.Identifier, lastPassingOrder, v.RevisionOrderNumber),
+		ProjectID:             v.Identifier,
+		VersionID:             v.Id,
+		Representatives:       culpritRepresentatives(group),
+		RangeStartOrder:       lastPassingOrder,
+		RangeEndOrder:         v.RevisionOrderNumber,
+		LastPassingOrder:      lastPassingOrder,
+		LastPassingVersionID:  group[0].lastPassing.Version,
+		FirstFailingOrder:     v.RevisionOrderNumber,
+		FirstFailingVersionID: v.Id,
+		FirstFailingRevision:  v.Revision,
+		Status:                culprit.BisectionRunning,
+		CreatedAt:             now,
+	}
+	sort.Slice(group, func(i, j int) bool {
+		return group[i].task.Id < group[j].task.Id
+	})
+	for _, ft := range group {
+		b.Tasks = append(b.Tasks, culpritTaskKey(&ft.task))
+		b.FailedTaskIDs = append(b.FailedTaskIDs, ft.task.Id)
+	}
+
+	candidates, err := findCulpritCandidates(ctx, v.Identifier, b.RangeStartOrder, b.RangeEndOrder, b.Representatives)
+	if err != nil {
+		return errors.Wrap(err, "finding versions to bisect")
+	}
+	b.Candidates = candidates
+
+	next := b.NextCandidate()
+	if next != nil {
+		b.AddStep(*next, now)
+	}
+	concluded := b.Conclude(now)
+
+	if err = b.Insert(ctx); err != nil {
+		if db.IsDuplicateKey(err) {
+			return nil
+		}
+		return err
+	}
+	if next != nil {
+		if err = activateBisectionTasks(ctx, b, *next); err != nil {
+			return errors.Wrap(err, "starting first bisection step")
+		}
+	}
+
+	grip.Info(ctx, message.Fields{
+		"message":      "started culprit-finding bisection",
+		"bisection_id": b.ID,
+		"project_id":   b.ProjectID,
+		"version_id":   b.VersionID,
+		"num_tasks":    len(b.Tasks),
+		"candidates":   len(b.Candidates),
+		"concluded":    concluded,
+	})
+
+	if concluded {
+		return errors.Wrap(reportCulprit(ctx, b), "reporting suspected commit")
+	}
+	return nil
+}
+
+// findCulpritCandidates returns the mainline versions strictly between the
+// given orders that contain all of the representative tasks, sorted by order.
+func findCulpritCandidates(ctx context.Context, projectID string, startOrder, endOrder int, representatives []culprit.TaskKey) ([]culprit.Candidate, error) {
+	versions, err := VersionFind(ctx, db.Query(bson.M{
+		VersionIdentifierKey: projectID,
+		VersionRevisionOrderNumberKey: bson.M{
+			"$gt": startOrder,
+			"$lt": endOrder,
+		},
+		VersionRequesterKey: bson.M{
+			"$in": evergreen.SystemVersionRequesterTypes,
+		},
+	}).Sort([]string{VersionRevisionOrderNumberKey}).WithFields(VersionIdKey, VersionRevisionOrderNumberKey, VersionRevisionKey))
+	if err != nil {
+		return nil, errors.Wrap(err, "finding versions in range")
+	}
+	if len(versions) == 0 {
+		return nil, nil
+	}
+
+	versionIDs := make([]string, 0, len(versions))
+	for _, v := range versions {
+		versionIDs = append(versionIDs, v.Id)
+	}
+	variants := []string{}
+	displayNames := []string{}
+	for _, key := range representatives {
+		variants = append(variants, key.BuildVariant)
+		displayNames = append(displayNames, key.DisplayName)
+	}
+	tasks, err := task.FindWithFields(ctx, bson.M{
+		task.VersionKey:      bson.M{"$in": versionIDs},
+		task.BuildVariantKey: bson.M{"$in": variants},
+		task.DisplayNameKey:  bson.M{"$in": displayNames},
+	}, task.IdKey, task.VersionKey, task.BuildVariantKey, task.DisplayNameKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding tasks in range")
+	}
+	taskIDs := map[string]map[culprit.TaskKey]string{}
+	for i := range tasks {
+		if taskIDs[tasks[i].Version] == nil {
+			taskIDs[tasks[i].Version] = map[culprit.TaskKey]string{}
+		}
+		taskIDs[tasks[i].Version][culpritTaskKey(&tasks[i])] = tasks[i].Id
+	}
+
+	candidates := []culprit.Candidate{}
+	for _, v := range versions {
+		c := culprit.Candidate{
+			Order:     v.RevisionOrderNumber,
+			VersionID: v.Id,
+			Revision:  v.Revision,
+		}
+		for _, key := range representatives {
+			id, ok := taskIDs[v.Id][key]
+			if !ok {
+				break
+			}
+			c.TaskIDs = append(c.TaskIDs, id)
+		}
+		// Versions that are missing any of the tasks can't be tested.
+		if len(c.TaskIDs) == len(representatives) {
+			candidates = append(candidates, c)
+		}
+	}
+	return candidates, nil
+}
+
+// activateBisectionTasks activates the candidate's tasks that haven't run.
+func activateBisectionTasks(ctx context.Context, b *culprit.Bisection, c culprit.Candidate) error {
+	tasks, err := task.Find(ctx, task.ByIds(c.TaskIDs))
+	if err != nil {
+		return errors.Wrapf(err, "finding tasks for version '%s'", c.VersionID)
+	}
+	toActivate := []task.Task{}
+	for _, t := range tasks {
+		if !t.Activated && !t.IsFinished() {
+			toActivate = append(toActivate, t)
+		}
+	}
+	if len(toActivate) == 0 {
+		return nil
+	}
+
+	grip.Info(ctx, message.Fields{
+		"message":      "activating culprit-finding bisection tasks",
+		"bisection_id": b.ID,
+		"project_id":   b.ProjectID,
+		"version_id":   c.VersionID,
+		"order":        c.Order,
+		"task_ids":     c.TaskIDs,
+	})
+	return errors.Wrap(SetActiveState(ctx, evergreen.CulpritFinderTaskActivator, true, toActivate...), "activating bisection tasks")
+}
+
+// evaluateBisectionStep returns the result of a step given its tasks. If the
+// step can't be used to narrow the range, it returns a reason instead.
+func evaluateBisectionStep(tasks []task.Task, numExpected int) (culprit.StepResult, string) {
+	if len(tasks) != numExpected {
+		return culprit.StepPending, "bisection tasks no longer exist"
+	}
+	for _, t := range tasks {
+		if t.IsFinished() {
+			continue
+		}
+		if !t.Activated {
+			return culprit.StepPending, fmt.Sprintf("task '%s' was deactivated", t.Id)
+		}
+		return culprit.StepPending, ""
+	}
+
+	result := culprit.StepPassed
+	for _, t := range tasks {
+		if t.Status == evergreen.TaskSucceeded {
+			continue
+		}
+		if !isTestFailure(&t) {
+			return culprit.StepPending, fmt.Sprintf("task '%s' finished with status '%s'", t.Id, t.GetDisplayStatus())
+		}
+		result = culprit.StepFailed
+	}
+	return result, ""
+}
+
+// AdvanceBisection checks whether the running bisection's current step has
+// finished and, if so, narrows the range and either starts the next step or
+// reports the suspected commit.
+func AdvanceBisection(ctx context.Context, b *culprit.Bisection) error {
+	if b.Status != culprit.BisectionRunning {
+		return nil
+	}
+	now := time.Now()
+
+	if step := b.CurrentStep(); step != nil {
+		tasks, err := task.Find(ctx, task.ByIds(step.TaskIDs))
+		if err != nil {
+			return errors.Wrapf(err, "finding tasks for bisection '%s'", b.ID)
+		}
+		result, reason := evaluateBisectionStep(tasks, len(step.TaskIDs))
+		if reason != "" {
+			b.Abandon(reason, now)
+			grip.Info(ctx, message.Fields{
+				"message":      "culprit-finding bisection was inconclusive",
+				"bisection_id": b.ID,
+				"project_id":   b.ProjectID,
+				"reason":       reason,
+			})
+			return b.Replace(ctx)
+		}
+		if result == culprit.StepPending {
+			return nil
+		}
+		b.RecordResult(result, now)
+	}
+
+	if b.Conclude(now) {
+		if err := b.Replace(ctx); err != nil {
+			return err
+		}
+		return errors.Wrapf(reportCulprit(ctx, b), "reporting suspected commit for bisection '%s'", b.ID)
+	}
+
+	next := b.NextCandidate()
+	if next == nil {
+		return errors.Errorf("bisection '%s' has no candidate to test next", b.ID)
+	}
+	b.AddStep(*next, now)
+	if err := b.Replace(ctx); err != nil {
+		return err
+	}
+	return errors.Wrapf(activateBisectionTasks(ctx, b, *next), "starting next step of bisection '%s'", b.ID)
+}
+
+// reportCulprit annotates the failed tasks with the suspected commit and logs
+// an event so that subscribers are notified.
+func reportCulprit(ctx context.Context, b *culprit.Bisection) error {
+	issue := annotations.IssueLink{
+		URL:      fmt.Sprintf("%s/version/%s", evergreen.GetEnvironment().Settings().Ui.UIv2Url, b.SuspectedVersionID),
+		IssueKey: fmt.Sprintf("Suspected commit %s", culprit.ShortRevision(b.SuspectedRevision)),
+	}
+
+	tasks, err := task.FindWithFields(ctx, task.ByIds(b.FailedTaskIDs), task.IdKey, task.ExecutionKey)
+	if err != nil {
+		return errors.Wrap(err, "finding failed tasks")
+	}
+	catcher := grip.NewBasicCatcher()
+	for _, t := range tasks {
+		catcher.Wrapf(annotations.AddSuspectedCommitToAnnotation(ctx, t.Id, t.Execution, issue), "annotating task '%s'", t.Id)
+	}
+
+	event.LogVersionCulpritFoundEvent(ctx, b.VersionID, event.VersionEventData{
+		BisectionID:        b.ID,
+		SuspectedVersionID: b.SuspectedVersionID,
+		SuspectedRevision:  b.SuspectedRevision,
+		NumFailedTasks:     len(b.FailedTaskIDs),
+	})
+
+	grip.Info(ctx, message.Fields{
+		"message":              "culprit-finding bisection found suspected commit",
+		"bisection_id":         b.ID,
+		"project_id":           b.ProjectID,
+		"version_id":           b.VersionID,
+		"suspected_version_id": b.SuspectedVersionID,
+		"suspected_revision":   b.SuspectedRevision,
+		"num_steps":            len(b.Steps),
+	})
+
+	return catcher.Resolve()
+}
@@ -0,0 +1,219 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/annotations"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/culprit"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestCulpritRepresentatives(t *testing.T) {
+	group := []culpritFailedTask{
+		{task: task.Task{Id: "t1", BuildVariant: "bv2", DisplayName: "slow", TimeTaken: 100}},
+		{task: task.Task{Id: "t2", BuildVariant: "bv2", DisplayName: "fast", TimeTaken: 10}},
+		{task: task.Task{Id: "t3", BuildVariant: "bv1", DisplayName: "b", TimeTaken: 50}},
+		{task: task.Task{Id: "t4", BuildVariant: "bv1", DisplayName: "a", TimeTaken: 50}},
+	}
+	assert.Equal(t, []culprit.TaskKey{
+		{BuildVariant: "bv1", DisplayName: "a"},
+		{BuildVariant: "bv2", DisplayName: "fast"},
+	}, culpritRepresentatives(group))
+}
+
+func TestIsCulpritCandidateFailure(t *testing.T) {
+	for tName, tCase := range map[string]struct {
+		task     task.Task
+		expected bool
+	}{
+		"TestFailure": {
+			task:     task.Task{Status: evergreen.TaskFailed, Details: apimodels.TaskEndDetail{Type: evergreen.CommandTypeTest}},
+			expected: true,
+		},
+		"SetupFailure": {
+			task: task.Task{Status: evergreen.TaskFailed, Details: apimodels.TaskEndDetail{Type: evergreen.CommandTypeSetup}},
+		},
+		"SystemFailure": {
+			task: task.Task{Status: evergreen.TaskFailed, Details: apimodels.TaskEndDetail{Type: evergreen.CommandTypeSystem}},
+		},
+		"Aborted": {
+			task: task.Task{Status: evergreen.TaskFailed, Aborted: true},
+		},
+		"ExecutionTask": {
+			task: task.Task{Status: evergreen.TaskFailed, DisplayTaskId: utility.ToStringPtr("dt")},
+		},
+		"ActivatedByCulpritFinder": {
+			task: task.Task{Status: evergreen.TaskFailed, ActivatedBy: evergreen.CulpritFinderTaskActivator},
+		},
+		"ActivatedByStepback": {
+			task: task.Task{Status: evergreen.TaskFailed, ActivatedBy: evergreen.StepbackTaskActivator},
+		},
+	} {
+		t.Run(tName, func(t *testing.T) {
+			assert.Equal(t, tCase.expected, isCulpritCandidateFailure(&tCase.task))
+		})
+	}
+}
+
+func TestEvaluateBisectionStep(t *testing.T) {
+	passed := task.Task{Id: "passed", Activated: true, Status: evergreen.TaskSucceeded}
+	failed := task.Task{Id: "failed", Activated: true, Status: evergreen.TaskFailed}
+	running := task.Task{Id: "running", Activated: true, Status: evergreen.TaskStarted}
+	deactivated := task.Task{Id: "deactivated", Status: evergreen.TaskUndispatched}
+	setupFailed := task.Task{Id: "setup", Activated: true, Status: evergreen.TaskFailed, Details: apimodels.TaskEndDetail{Type: evergreen.CommandTypeSetup}}
+
+	result, reason := evaluateBisectionStep([]task.Task{passed, passed}, 2)
+	assert.Equal(t, culprit.StepPassed, result)
+	assert.Empty(t, reason)
+
+	result, reason = evaluateBisectionStep([]task.Task{passed, failed}, 2)
+	assert.Equal(t, culprit.StepFailed, result)
+	assert.Empty(t, reason)
+
+	result, reason = evaluateBisectionStep([]task.Task{failed, running}, 2)
+	assert.Equal(t, culprit.StepPending, result)
+	assert.Empty(t, reason)
+
+	_, reason = evaluateBisectionStep([]task.Task{passed, deactivated}, 2)
+	assert.Contains(t, reason, "deactivated")
+
+	_, reason = evaluateBisectionStep([]task.Task{failed, setupFailed}, 2)
+	assert.Contains(t, reason, "setup")
+
+	_, reason = evaluateBisectionStep([]task.Task{passed}, 2)
+	assert.NotEmpty(t, reason)
+}
+
+func TestCulpritFinding(t *testing.T) {
+	ctx := t.Context()
+	require.NoError(t, db.ClearCollections(task.Collection, build.Collection, VersionCollection, ProjectRefCollection,
+		culprit.Collection, annotations.Collection, event.EventCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(task.Collection, build.Collection, VersionCollection, ProjectRefCollection,
+			culprit.Collection, annotations.Collection, event.EventCollection))
+	}()
+
+	pRef := &ProjectRef{
+		Id:                   "proj",
+		Identifier:           "proj",
+		Enabled:              true,
+		CulpritFinderEnabled: utility.TruePtr(),
+	}
+	require.NoError(t, pRef.Insert(ctx))
+
+	// Version 1 passed, versions 2-4 didn't run the task, and version 5
+	// failed.
+	for order := 1; order <= 5; order++ {
+		versionID := fmt.Sprintf("v%d", order)
+		v := &Version{
+			Id:                  versionID,
+			Identifier:          "proj",
+			Revision:            fmt.Sprintf("revision%d", order),
+			RevisionOrderNumber: order,
+			Requester:           evergreen.RepotrackerVersionRequester,
+			Status:              evergreen.VersionCreated,
+		}
+		require.NoError(t, v.Insert(ctx))
+		b := &build.Build{
+			Id:           fmt.Sprintf("b%d", order),
+			Version:      versionID,
+			BuildVariant: "bv",
+			Requester:    evergreen.RepotrackerVersionRequester,
+			Status:       evergreen.BuildCreated,
+		}
+		require.NoError(t, b.Insert(ctx))
+		tsk := &task.Task{
+			Id:                  fmt.Sprintf("t%d", order),
+			Version:             versionID,
+			BuildId:             b.Id,
+			Project:             "proj",
+			BuildVariant:        "bv",
+			DisplayName:         "test",
+			Revision:            v.Revision,
+			RevisionOrderNumber: order,
+			Requester:           evergreen.RepotrackerVersionRequester,
+			Status:              evergreen.TaskUndispatched,
+		}
+		switch order {
+		case 1:
+			tsk.Activated = true
+			tsk.Status = evergreen.TaskSucceeded
+		case 5:
+			tsk.Activated = true
+			tsk.Status = evergreen.TaskFailed
+			tsk.Details = apimodels.TaskEndDetail{Status: evergreen.TaskFailed, Type: evergreen.CommandTypeTest}
+		}
+		require.NoError(t, tsk.Insert(ctx))
+	}
+
+	v5, err := VersionFindOneId(ctx, "v5")
+	require.NoError(t, err)
+	require.NotNil(t, v5)
+	require.NoError(t, StartCulpritFinding(ctx, v5))
+
+	bisections, err := culprit.FindByVersion(ctx, "v5")
+	require.NoError(t, err)
+	require.Len(t, bisections, 1)
+	b := bisections[0]
+	assert.Equal(t, culprit.BisectionRunning, b.Status)
+	assert.Equal(t, []string{"t5"}, b.FailedTaskIDs)
+	assert.Equal(t, 1, b.LastPassingOrder)
+	assert.Equal(t, 5, b.FirstFailingOrder)
+	require.Len(t, b.Candidates, 3)
+	require.Len(t, b.Steps, 1)
+	assert.Equal(t, "v3", b.Steps[0].VersionID)
+
+	t3, err := task.FindOneId(ctx, "t3")
+	require.NoError(t, err)
+	require.NotNil(t, t3)
+	assert.True(t, t3.Activated)
+	assert.Equal(t, evergreen.CulpritFinderTaskActivator, t3.ActivatedBy)
+
+	// Starting again for the same failures shouldn't start another
+	// bisection.
+	require.NoError(t, StartCulpritFinding(ctx, v5))
+	bisections, err = culprit.FindByVersion(ctx, "v5")
+	require.NoError(t, err)
+	assert.Len(t, bisections, 1)
+
+	// The step doesn't advance until its task finishes.
+	require.NoError(t, AdvanceBisection(ctx, &b))
+	assert.Len(t, b.Steps, 1)
+
+	require.NoError(t, task.UpdateOne(ctx, task.ById("t3"), bson.M{"$set": bson.M{task.StatusKey: evergreen.TaskSucceeded}}))
+	require.NoError(t, AdvanceBisection(ctx, &b))
+	require.Len(t, b.Steps, 2)
+	assert.Equal(t, culprit.StepPassed, b.Steps[0].Result)
+	assert.Equal(t, "v4", b.Steps[1].VersionID)
+
+	require.NoError(t, task.UpdateOne(ctx, task.ById("t4"), bson.M{"$set": bson.M{
+		task.StatusKey:  evergreen.TaskFailed,
+		task.DetailsKey: apimodels.TaskEndDetail{Status: evergreen.TaskFailed, Type: evergreen.CommandTypeTest},
+	}}))
+	require.NoError(t, AdvanceBisection(ctx, &b))
+	assert.Equal(t, culprit.BisectionFound, b.Status)
+	assert.Equal(t, "v4", b.SuspectedVersionID)
+	assert.Equal(t, "revision4", b.SuspectedRevision)
+
+	dbBisection, err := culprit.FindOneId(ctx, b.ID)
+	require.NoError(t, err)
+	require.NotNil(t, dbBisection)
+	assert.Equal(t, culprit.BisectionFound, dbBisection.Status)
+
+	annotation, err := annotations.FindOneByTaskIdAndExecution(ctx, "t5", 0)
+	require.NoError(t, err)
+	require.NotNil(t, annotation)
+	require.Len(t, annotation.SuspectedIssues, 1)
+	assert.Equal(t, "Suspected commit revisio", annotation.SuspectedIssues[0].IssueKey)
+	assert.Equal(t, annotations.CulpritFinderRequester, annotation.SuspectedIssues[0].Source.Requester)
+}
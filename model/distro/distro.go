@@ -578,7 +578,7 @@ func (d *Distro) GetImageID() (string, error) {
 	switch d.Provider {
 	case evergreen.ProviderNameEc2Fleet:
 		key = "ami"
-	case evergreen.ProviderNameDocker, evergreen.ProviderNameDockerMock:
+	case evergreen.ProviderNameDocker, evergreen.ProviderNameDockerMock, evergreen.ProviderNamePodman, evergreen.ProviderNamePodmanMock:
 		key = "image_url"
 	case evergreen.ProviderNameMock, evergreen.ProviderNameStatic:
 		return "", nil
@@ -93,3 +93,30 @@ func ApplyReferenceCostDataOrWarn(ctx context.Context, d *distro.Distro) {
 	d.CostData.OnDemandRate = ref.OnDemandMedian
 	d.CostData.SavingsPlanRate = ref.AdjustedFinanceMedian
 }
+
+// CostDataForDistro returns the distro's configured cost data or, if it has
+// none, the cost data from the EC2 reference price row for its instance type
+// and OS. It returns empty cost data if neither is available.
+func CostDataForDistro(ctx context.Context, d *distro.Distro) (distro.CostData, error) {
+	if d == nil {
+		return distro.CostData{}, nil
+	}
+	if d.CostData.IsConfigured() || !evergreen.IsEc2Provider(d.Provider) {
+		return d.CostData, nil
+	}
+	instanceType := ec2InstanceTypeFromDistro(d)
+	if instanceType == "" {
+		return distro.CostData{}, nil
+	}
+	ref, err := FindOne(ctx, ByInstanceTypeAndOS(instanceType, OperatingSystemFromImageID(d.ImageID)))
+	if err != nil {
+		return distro.CostData{}, errors.Wrapf(err, "loading EC2 reference price for distro '%s'", d.Id)
+	}
+	if ref == nil {
+		return distro.CostData{}, nil
+	}
+	return distro.CostData{
+		OnDemandRate:    ref.OnDemandMedian,
+		SavingsPlanRate: ref.AdjustedFinanceMedian,
+	}, nil
+}
@@ -0,0 +1,65 @@
+package event
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/grip"
+)
+
+// DigestMode describes how a subscription's notifications are grouped into a
+// single summary message.
+type DigestMode string
+
+const (
+	// DigestModeWindow collapses every notification for the same subscriber
+	// that occurs within the digest window.
+	DigestModeWindow DigestMode = "window"
+	// DigestModeVersion collapses notifications for the same subscriber and
+	// the same version that occur within the digest window.
+	DigestModeVersion DigestMode = "version"
+
+	DefaultDigestWindowMinutes = 15
+	maxDigestWindowMinutes     = 24 * 60
+)
+
+// DigestSubscriberTypes are the subscriber types that support digests.
+var DigestSubscriberTypes = []string{
+	SlackSubscriberType,
+	EmailSubscriberType,
+	EvergreenWebhookSubscriberType,
+}
+
+// DigestOptions configures collapsing a subscription's notifications into
+// periodic digests instead of sending one message per event.
+type DigestOptions struct {
+	Mode DigestMode `bson:"mode"`
+	// WindowMinutes is how long a digest collects notifications after the
+	// first one before it is sent.
+	WindowMinutes int `bson:"window_minutes,omitempty"`
+}
+
+// IsEnabled returns whether the options turn digests on.
+func (d *DigestOptions) IsEnabled() bool {
+	return d != nil && d.Mode != ""
+}
+
+// Window returns how long a digest collects notifications before it is sent.
+func (d *DigestOptions) Window() time.Duration {
+	if d == nil || d.WindowMinutes <= 0 {
+		return DefaultDigestWindowMinutes * time.Minute
+	}
+	return time.Duration(d.WindowMinutes) * time.Minute
+}
+
+func (d *DigestOptions) validate(subscriberType string) error {
+	if !d.IsEnabled() {
+		return nil
+	}
+	catcher := grip.NewBasicCatcher()
+	catcher.ErrorfWhen(d.Mode != DigestModeWindow && d.Mode != DigestModeVersion, "invalid digest mode '%s'", d.Mode)
+	catcher.NewWhen(d.WindowMinutes < 0, "digest window cannot be negative")
+	catcher.ErrorfWhen(d.WindowMinutes > maxDigestWindowMinutes, "digest window cannot exceed %d minutes", maxDigestWindowMinutes)
+	catcher.ErrorfWhen(!utility.StringSliceContains(DigestSubscriberTypes, subscriberType), "subscriber type '%s' does not support digests", subscriberType)
+	return catcher.Resolve()
+}
@@ -0,0 +1,65 @@
+package event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDigestOptions(t *testing.T) {
+	t.Run("NilIsDisabled", func(t *testing.T) {
+		var d *DigestOptions
+		assert.False(t, d.IsEnabled())
+		assert.Equal(t, DefaultDigestWindowMinutes*time.Minute, d.Window())
+		assert.NoError(t, d.validate(JIRAIssueSubscriberType))
+	})
+	t.Run("Window", func(t *testing.T) {
+		d := &DigestOptions{Mode: DigestModeWindow, WindowMinutes: 30}
+		assert.True(t, d.IsEnabled())
+		assert.Equal(t, 30*time.Minute, d.Window())
+	})
+	for name, testCase := range map[string]struct {
+		d              DigestOptions
+		subscriberType string
+		errorExpected  bool
+	}{
+		"ValidWindow": {
+			d:              DigestOptions{Mode: DigestModeWindow, WindowMinutes: 10},
+			subscriberType: SlackSubscriberType,
+		},
+		"ValidVersion": {
+			d:              DigestOptions{Mode: DigestModeVersion},
+			subscriberType: EmailSubscriberType,
+		},
+		"InvalidMode": {
+			d:              DigestOptions{Mode: "hourly"},
+			subscriberType: SlackSubscriberType,
+			errorExpected:  true,
+		},
+		"NegativeWindow": {
+			d:              DigestOptions{Mode: DigestModeWindow, WindowMinutes: -1},
+			subscriberType: EvergreenWebhookSubscriberType,
+			errorExpected:  true,
+		},
+		"WindowTooLong": {
+			d:              DigestOptions{Mode: DigestModeWindow, WindowMinutes: maxDigestWindowMinutes + 1},
+			subscriberType: SlackSubscriberType,
+			errorExpected:  true,
+		},
+		"UnsupportedSubscriber": {
+			d:              DigestOptions{Mode: DigestModeWindow},
+			subscriberType: JIRACommentSubscriberType,
+			errorExpected:  true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := testCase.d.validate(testCase.subscriberType)
+			if testCase.errorExpected {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
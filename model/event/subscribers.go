@@ -20,6 +20,8 @@ const (
 	EvergreenWebhookSubscriberType  = "evergreen-webhook"
 	EmailSubscriberType             = "email"
 	SlackSubscriberType             = "slack"
+	TeamsSubscriberType             = "teams"
+	MattermostSubscriberType        = "mattermost"
 	SubscriberTypeNone              = "none"
 	RunChildPatchSubscriberType     = "run-child-patch"
 
@@ -30,6 +32,12 @@ const (
 	webhookRetryLimit    = 10
 	webhookMinDelayLimit = 10000
 	webhookTimeoutLimit  = 30000
+
+	// DefaultChatWebhookRateLimitPerMinute is the number of notifications
+	// sent to a single chat webhook per minute when the subscriber does not
+	// specify a rate limit.
+	DefaultChatWebhookRateLimitPerMinute = 30
+	chatWebhookRateLimitPerMinuteLimit   = 600
 )
 
 var SubscriberTypes = []string{
@@ -41,6 +49,8 @@ var SubscriberTypes = []string{
 	EvergreenWebhookSubscriberType,
 	EmailSubscriberType,
 	SlackSubscriberType,
+	TeamsSubscriberType,
+	MattermostSubscriberType,
 	RunChildPatchSubscriberType,
 }
 
@@ -79,6 +89,10 @@ func (s *Subscriber) SetBSON(raw mgobson.Raw) error {
 		s.Target = &WebhookSubscriber{}
 	case JIRAIssueSubscriberType:
 		s.Target = &JIRAIssueSubscriber{}
+	case TeamsSubscriberType:
+		s.Target = &TeamsSubscriber{}
+	case MattermostSubscriberType:
+		s.Target = &MattermostSubscriber{}
 	case JIRACommentSubscriberType, EmailSubscriberType, SlackSubscriberType:
 		str := ""
 		s.Target = &str
@@ -122,6 +136,14 @@ func (s *Subscriber) Validate() error {
 		catcher.Add(v.validate())
 	case *WebhookSubscriber:
 		catcher.Add(v.validate())
+	case TeamsSubscriber:
+		catcher.Add(v.validate())
+	case *TeamsSubscriber:
+		catcher.Add(v.validate())
+	case MattermostSubscriber:
+		catcher.Add(v.validate())
+	case *MattermostSubscriber:
+		catcher.Add(v.validate())
 	}
 
 	return catcher.Resolve()
@@ -211,6 +233,86 @@ func (s *WebhookSubscriber) setHeader(key, value string) {
 	s.Headers = append(s.Headers, WebhookHeader{Key: key, Value: value})
 }
 
+// TeamsSubscriber is a Microsoft Teams incoming webhook, which receives
+// notifications as Adaptive Cards.
+type TeamsSubscriber struct {
+	URL string `bson:"url"`
+	// RateLimitPerMinute is the maximum number of notifications sent to the
+	// webhook per minute. If it's zero, the default limit is used.
+	RateLimitPerMinute int `bson:"rate_limit_per_minute,omitempty"`
+}
+
+func (s *TeamsSubscriber) String() string {
+	if len(s.URL) == 0 {
+		return "NIL_URL"
+	}
+	return s.URL
+}
+
+func (s *TeamsSubscriber) validate() error {
+	catcher := grip.NewBasicCatcher()
+	catcher.Add(util.ValidateWebhookURL(s.URL))
+	catcher.Add(validateChatWebhookRateLimit(s.RateLimitPerMinute))
+	return catcher.Resolve()
+}
+
+// GetRateLimitPerMinute returns the maximum number of notifications to send to
+// the webhook per minute.
+func (s *TeamsSubscriber) GetRateLimitPerMinute() int {
+	if s.RateLimitPerMinute == 0 {
+		return DefaultChatWebhookRateLimitPerMinute
+	}
+	return s.RateLimitPerMinute
+}
+
+// MattermostSubscriber is a Mattermost incoming webhook. Rocket.Chat and other
+// chat servers that accept Mattermost-compatible incoming webhooks can also
+// use it.
+type MattermostSubscriber struct {
+	URL string `bson:"url"`
+	// Channel optionally overrides the webhook's default channel.
+	Channel string `bson:"channel,omitempty"`
+	// Username optionally overrides the webhook's default username.
+	Username string `bson:"username,omitempty"`
+	// RateLimitPerMinute is the maximum number of notifications sent to the
+	// webhook per minute. If it's zero, the default limit is used.
+	RateLimitPerMinute int `bson:"rate_limit_per_minute,omitempty"`
+}
+
+func (s *MattermostSubscriber) String() string {
+	if len(s.URL) == 0 {
+		return "NIL_URL"
+	}
+	if len(s.Channel) == 0 {
+		return s.URL
+	}
+	return fmt.Sprintf("%s-%s", s.URL, s.Channel)
+}
+
+func (s *MattermostSubscriber) validate() error {
+	catcher := grip.NewBasicCatcher()
+	catcher.Add(util.ValidateWebhookURL(s.URL))
+	catcher.ErrorfWhen(strings.ContainsAny(s.Channel, " \t\n"), "channel '%s' cannot contain whitespace", s.Channel)
+	catcher.Add(validateChatWebhookRateLimit(s.RateLimitPerMinute))
+	return catcher.Resolve()
+}
+
+// GetRateLimitPerMinute returns the maximum number of notifications to send to
+// the webhook per minute.
+func (s *MattermostSubscriber) GetRateLimitPerMinute() int {
+	if s.RateLimitPerMinute == 0 {
+		return DefaultChatWebhookRateLimitPerMinute
+	}
+	return s.RateLimitPerMinute
+}
+
+func validateChatWebhookRateLimit(limit int) error {
+	catcher := grip.NewBasicCatcher()
+	catcher.NewWhen(limit < 0, "rate limit cannot be negative")
+	catcher.ErrorfWhen(limit > chatWebhookRateLimitPerMinuteLimit, "rate limit cannot be greater than %d notifications per minute", chatWebhookRateLimitPerMinuteLimit)
+	return catcher.Resolve()
+}
+
 type JIRAIssueSubscriber struct {
 	Project   string `bson:"project"`
 	IssueType string `bson:"issue_type"`
@@ -300,3 +402,17 @@ func NewSlackSubscriber(t string) Subscriber {
 		Target: t,
 	}
 }
+
+func NewTeamsSubscriber(s TeamsSubscriber) Subscriber {
+	return Subscriber{
+		Type:   TeamsSubscriberType,
+		Target: &s,
+	}
+}
+
+func NewMattermostSubscriber(s MattermostSubscriber) Subscriber {
+	return Subscriber{
+		Type:   MattermostSubscriberType,
+		Target: &s,
+	}
+}
@@ -225,6 +225,42 @@ func TestValidate(t *testing.T) {
 			},
 			errorExpected: false,
 		},
+		"ValidTeams": {
+			s:             NewTeamsSubscriber(TeamsSubscriber{URL: "https://example.webhook.office.com/webhookb2/abc"}),
+			errorExpected: false,
+		},
+		"TeamsMissingURL": {
+			s:             NewTeamsSubscriber(TeamsSubscriber{}),
+			errorExpected: true,
+		},
+		"TeamsURLWithPrivateIP": {
+			s:             NewTeamsSubscriber(TeamsSubscriber{URL: "https://10.0.0.1/webhook"}),
+			errorExpected: true,
+		},
+		"TeamsNegativeRateLimit": {
+			s:             NewTeamsSubscriber(TeamsSubscriber{URL: "https://example.com/webhook", RateLimitPerMinute: -1}),
+			errorExpected: true,
+		},
+		"ValidMattermost": {
+			s: NewMattermostSubscriber(MattermostSubscriber{
+				URL:                "https://chat.example.com/hooks/abc",
+				Channel:            "builds",
+				RateLimitPerMinute: 60,
+			}),
+			errorExpected: false,
+		},
+		"MattermostUnsupportedSchemeURL": {
+			s:             NewMattermostSubscriber(MattermostSubscriber{URL: "ftp://chat.example.com/hooks/abc"}),
+			errorExpected: true,
+		},
+		"MattermostChannelWithWhitespace": {
+			s:             NewMattermostSubscriber(MattermostSubscriber{URL: "https://chat.example.com/hooks/abc", Channel: "my builds"}),
+			errorExpected: true,
+		},
+		"MattermostRateLimitTooHigh": {
+			s:             NewMattermostSubscriber(MattermostSubscriber{URL: "https://chat.example.com/hooks/abc", RateLimitPerMinute: 100000}),
+			errorExpected: true,
+		},
 	} {
 		t.Run(name, func(t *testing.T) {
 			if testCase.errorExpected {
@@ -37,6 +37,7 @@ var (
 	subscriptionOwnerTypeKey      = bsonutil.MustHaveTag(Subscription{}, "OwnerType")
 	subscriptionTriggerDataKey    = bsonutil.MustHaveTag(Subscription{}, "TriggerData")
 	subscriptionLastUpdatedKey    = bsonutil.MustHaveTag(Subscription{}, "LastUpdated")
+	subscriptionDigestKey         = bsonutil.MustHaveTag(Subscription{}, "Digest")
 
 	filterObjectKey       = bsonutil.MustHaveTag(Filter{}, "Object")
 	filterIDKey           = bsonutil.MustHaveTag(Filter{}, "ID")
@@ -61,6 +62,8 @@ const (
 	BuildPercentChangeKey                            = "build-percent-change"
 	VersionDurationKey                               = "version-duration-secs"
 	VersionPercentChangeKey                          = "version-percent-change"
+	VersionCostThresholdKey                          = "version-cost-threshold"
+	ProjectBudgetPercentKey                          = "project-budget-percent"
 	TestRegexKey                                     = "test-regex"
 	RenotifyIntervalKey                              = "renotify-interval"
 	GeneralSubscriptionPatchOutcome                  = "patch-outcome"
@@ -94,6 +97,25 @@ const (
 	TriggerTaskStarted               = "task-started"
 	TriggerSpawnHostIdle             = "spawn-host-idle"
 	TriggerAlertableInstanceType     = "alertable-instance-type"
+	// TriggerVersionCostExceeds indicates that a finished version cost more
+	// than a threshold.
+	TriggerVersionCostExceeds = "version-cost-exceeds"
+	// TriggerProjectBudgetThreshold indicates that a finished version
+	// brought its project's spend past a percentage of one of its cost
+	// budgets.
+	TriggerProjectBudgetThreshold = "project-budget-threshold"
+	// TriggerCulpritFound indicates that a culprit-finding bisection found
+	// the commit suspected of causing failures in a version.
+	TriggerCulpritFound = "culprit-found"
+	// TriggerTestQuarantined indicates that a task's test was automatically
+	// quarantined for being flaky.
+	TriggerTestQuarantined = "test-quarantined"
+	// TriggerTestReleased indicates that a task's test was released from
+	// quarantine.
+	TriggerTestReleased = "test-released"
+	// TriggerPerfRegression indicates that a change point was found in the
+	// performance results of a task's test.
+	TriggerPerfRegression = "perf-regression"
 )
 
 type Subscription struct {
@@ -108,6 +130,9 @@ type Subscription struct {
 	Owner          string            `bson:"owner"`
 	TriggerData    map[string]string `bson:"trigger_data,omitempty"`
 	LastUpdated    time.Time         `bson:"last_updated,omitempty"`
+	// Digest, if set, collapses the subscription's notifications into
+	// periodic summary messages rather than sending one per event.
+	Digest *DigestOptions `bson:"digest,omitempty"`
 }
 
 type unmarshalSubscription struct {
@@ -121,6 +146,7 @@ type unmarshalSubscription struct {
 	OwnerType      OwnerType         `bson:"owner_type"`
 	Owner          string            `bson:"owner"`
 	TriggerData    map[string]string `bson:"trigger_data,omitempty"`
+	Digest         *DigestOptions    `bson:"digest,omitempty"`
 }
 
 func (d *Subscription) UnmarshalBSON(in []byte) error {
@@ -144,6 +170,7 @@ func (s *Subscription) SetBSON(raw mgobson.Raw) error {
 	s.Owner = temp.Owner
 	s.OwnerType = temp.OwnerType
 	s.TriggerData = temp.TriggerData
+	s.Digest = temp.Digest
 
 	return nil
 }
@@ -520,6 +547,9 @@ func (s *Subscription) Upsert(ctx context.Context) error {
 		subscriptionOwnerTypeKey:   s.OwnerType,
 		subscriptionTriggerDataKey: s.TriggerData,
 	}
+	if s.Digest != nil {
+		update[subscriptionDigestKey] = s.Digest
+	}
 	if !utility.IsZeroTime(s.LastUpdated) {
 		update[subscriptionLastUpdatedKey] = s.LastUpdated
 	}
@@ -677,6 +707,9 @@ func (s *Subscription) Validate() error {
 	catcher.Add(s.ValidateSelectors())
 	catcher.Add(s.runCustomValidation())
 	catcher.Add(s.Subscriber.Validate())
+	if s.Digest != nil {
+		catcher.Wrap(s.Digest.validate(s.Subscriber.Type), "invalid digest options")
+	}
 	return catcher.Resolve()
 }
 
@@ -695,6 +728,12 @@ func (s *Subscription) runCustomValidation() error {
 	if versionPercentVal, ok := s.TriggerData[VersionPercentChangeKey]; ok {
 		catcher.Wrap(validatePositiveFloat(versionPercentVal), "invalid version percentage runtime change")
 	}
+	if versionCostVal, ok := s.TriggerData[VersionCostThresholdKey]; ok {
+		catcher.Wrap(validatePositiveFloat(versionCostVal), "invalid version cost threshold")
+	}
+	if budgetPercentVal, ok := s.TriggerData[ProjectBudgetPercentKey]; ok {
+		catcher.Wrap(validatePositiveFloat(budgetPercentVal), "invalid project budget percentage")
+	}
 	if buildDurationVal, ok := s.TriggerData[BuildDurationKey]; ok {
 		catcher.Wrap(validatePositiveInt(buildDurationVal), "invalid build duration")
 	}
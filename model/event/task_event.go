@@ -15,6 +15,9 @@ func init() {
 	registry.AllowSubscription(ResourceTypeTask, TaskStarted)
 	registry.AllowSubscription(ResourceTypeTask, TaskFinished)
 	registry.AllowSubscription(ResourceTypeTask, TaskBlocked)
+	registry.AllowSubscription(ResourceTypeTask, TaskTestQuarantined)
+	registry.AllowSubscription(ResourceTypeTask, TaskTestReleased)
+	registry.AllowSubscription(ResourceTypeTask, TaskPerfRegression)
 }
 
 const (
@@ -35,6 +38,9 @@ const (
 	TaskPriorityChanged        = "TASK_PRIORITY_CHANGED"
 	TaskJiraAlertCreated       = "TASK_JIRA_ALERT_CREATED"
 	TaskDependenciesOverridden = "TASK_DEPENDENCIES_OVERRIDDEN"
+	TaskTestQuarantined        = "TASK_TEST_QUARANTINED"
+	TaskTestReleased           = "TASK_TEST_RELEASED"
+	TaskPerfRegression         = "TASK_PERF_REGRESSION"
 )
 
 // implements Data
@@ -48,6 +54,16 @@ type TaskEventData struct {
 
 	Timestamp time.Time `bson:"ts,omitempty" json:"timestamp,omitempty"`
 	Priority  int64     `bson:"pri,omitempty" json:"priority,omitempty"`
+
+	// TestName and FlakinessScore describe a test that was automatically
+	// quarantined or released.
+	TestName       string  `bson:"test_name,omitempty" json:"test_name,omitempty"`
+	FlakinessScore float64 `bson:"flakiness_score,omitempty" json:"flakiness_score,omitempty"`
+
+	// PerfMetric and PerfPercentChange describe a change point found in
+	// the performance results of one of the task's tests.
+	PerfMetric        string  `bson:"perf_metric,omitempty" json:"perf_metric,omitempty"`
+	PerfPercentChange float64 `bson:"perf_percent_change,omitempty" json:"perf_percent_change,omitempty"`
 }
 
 func logTaskEvent(ctx context.Context, taskId string, eventType string, eventData TaskEventData) {
@@ -108,6 +124,25 @@ func LogTaskPriority(ctx context.Context, taskId string, execution int, userId s
 	logTaskEvent(ctx, taskId, TaskPriorityChanged, TaskEventData{Execution: execution, UserId: userId, Priority: priority})
 }
 
+// LogTestQuarantined logs an event indicating that one of the task's tests
+// was automatically quarantined for being flaky.
+func LogTestQuarantined(ctx context.Context, taskId string, execution int, testName string, score float64) {
+	logTaskEvent(ctx, taskId, TaskTestQuarantined, TaskEventData{Execution: execution, TestName: testName, FlakinessScore: score})
+}
+
+// LogTestReleased logs an event indicating that one of the task's tests was
+// released from quarantine after passing enough times in a row.
+func LogTestReleased(ctx context.Context, taskId string, execution int, testName string, score float64) {
+	logTaskEvent(ctx, taskId, TaskTestReleased, TaskEventData{Execution: execution, TestName: testName, FlakinessScore: score})
+}
+
+// LogTaskPerfRegression logs an event indicating that a change point was found
+// in the performance results of one of the task's tests, starting at this
+// task.
+func LogTaskPerfRegression(ctx context.Context, taskId string, execution int, testName, metric string, percentChange float64) {
+	logTaskEvent(ctx, taskId, TaskPerfRegression, TaskEventData{Execution: execution, TestName: testName, PerfMetric: metric, PerfPercentChange: percentChange})
+}
+
 func LogTaskCreated(ctx context.Context, taskId string, execution int) {
 	logTaskEvent(ctx, taskId, TaskCreated, TaskEventData{Execution: execution})
 }
@@ -14,6 +14,7 @@ func init() {
 	registry.AllowSubscription(ResourceTypeVersion, VersionStateChange)
 	registry.AllowSubscription(ResourceTypeVersion, VersionGithubCheckFinished)
 	registry.AllowSubscription(ResourceTypeVersion, VersionChildrenCompletion)
+	registry.AllowSubscription(ResourceTypeVersion, VersionCulpritFound)
 }
 
 func versionEventDataFactory() any {
@@ -25,12 +26,19 @@ const (
 	VersionStateChange         = "STATE_CHANGE"
 	VersionGithubCheckFinished = "GITHUB_CHECK_FINISHED"
 	VersionChildrenCompletion  = "CHILDREN_FINISHED"
+	VersionCulpritFound        = "CULPRIT_FOUND"
 )
 
 type VersionEventData struct {
 	Status            string `bson:"status,omitempty" json:"status,omitempty"`
 	GithubCheckStatus string `bson:"github_check_status,omitempty" json:"github_check_status,omitempty"`
 	Author            string `bson:"author,omitempty" json:"author,omitempty"`
+
+	// Culprit-finding results, set for VersionCulpritFound events.
+	BisectionID        string `bson:"bisection_id,omitempty" json:"bisection_id,omitempty"`
+	SuspectedVersionID string `bson:"suspected_version_id,omitempty" json:"suspected_version_id,omitempty"`
+	SuspectedRevision  string `bson:"suspected_revision,omitempty" json:"suspected_revision,omitempty"`
+	NumFailedTasks     int    `bson:"num_failed_tasks,omitempty" json:"num_failed_tasks,omitempty"`
 }
 
 // logEventWithRetry attempts to log an event with a detached context and retries on failure.
@@ -123,3 +131,22 @@ func LogVersionChildrenCompletionEvent(ctx context.Context, id, status, author s
 		"author":        author,
 	})
 }
+
+// LogVersionCulpritFoundEvent logs that a bisection found the suspected commit
+// that caused failures in the version.
+func LogVersionCulpritFoundEvent(ctx context.Context, id string, data VersionEventData) {
+	event := EventLogEntry{
+		Timestamp:    time.Now().Truncate(0).Round(time.Millisecond),
+		ResourceId:   id,
+		ResourceType: ResourceTypeVersion,
+		EventType:    VersionCulpritFound,
+		Data:         &data,
+	}
+
+	logEventWithRetry(event, message.Fields{
+		"resource_type":        ResourceTypeVersion,
+		"version_id":           id,
+		"bisection_id":         data.BisectionID,
+		"suspected_version_id": data.SuspectedVersionID,
+	})
+}
@@ -0,0 +1,107 @@
+package flakytest
+
+import (
+	"context"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/mongodb/anser/bsonutil"
+	adb "github.com/mongodb/anser/db"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const Collection = "test_flakiness"
+
+var (
+	IdKey           = bsonutil.MustHaveTag(TestFlakiness{}, "ID")
+	ProjectIdKey    = bsonutil.MustHaveTag(TestFlakiness{}, "ProjectID")
+	BuildVariantKey = bsonutil.MustHaveTag(TestFlakiness{}, "BuildVariant")
+	TaskNameKey     = bsonutil.MustHaveTag(TestFlakiness{}, "TaskName")
+	TestNameKey     = bsonutil.MustHaveTag(TestFlakiness{}, "TestName")
+	ScoreKey        = bsonutil.MustHaveTag(TestFlakiness{}, "Score")
+	QuarantinedKey  = bsonutil.MustHaveTag(TestFlakiness{}, "Quarantined")
+	UpdatedAtKey    = bsonutil.MustHaveTag(TestFlakiness{}, "UpdatedAt")
+)
+
+// FindOptions filter the tests returned by FindByProject.
+type FindOptions struct {
+	BuildVariant    string
+	TaskName        string
+	OnlyQuarantined bool
+	Limit           int
+}
+
+// Find gets every test matching the given query.
+func Find(ctx context.Context, query db.Q) ([]TestFlakiness, error) {
+	tests := []TestFlakiness{}
+	if err := db.FindAllQ(ctx, Collection, query, &tests); err != nil {
+		return nil, errors.Wrap(err, "finding test flakiness")
+	}
+	return tests, nil
+}
+
+// FindOneId gets the test with the given ID.
+func FindOneId(ctx context.Context, id string) (*TestFlakiness, error) {
+	f := &TestFlakiness{}
+	err := db.FindOneQ(ctx, Collection, db.Query(bson.M{IdKey: id}), f)
+	if adb.ResultsNotFound(err) {
+		return nil, nil
+	}
+	return f, errors.Wrapf(err, "finding test flakiness '%s'", id)
+}
+
+// FindByProject gets the project's tests, most flaky first.
+func FindByProject(ctx context.Context, projectID string, opts FindOptions) ([]TestFlakiness, error) {
+	filter := bson.M{ProjectIdKey: projectID}
+	if opts.BuildVariant != "" {
+		filter[BuildVariantKey] = opts.BuildVariant
+	}
+	if opts.TaskName != "" {
+		filter[TaskNameKey] = opts.TaskName
+	}
+	if opts.OnlyQuarantined {
+		filter[QuarantinedKey] = true
+	}
+	q := db.Query(filter).Sort([]string{"-" + ScoreKey, TestNameKey})
+	if opts.Limit > 0 {
+		q = q.Limit(opts.Limit)
+	}
+	return Find(ctx, q)
+}
+
+// FindQuarantinedForTask gets the quarantined tests of the task in the given
+// project and build variant.
+func FindQuarantinedForTask(ctx context.Context, projectID, buildVariant, taskName string) ([]TestFlakiness, error) {
+	return Find(ctx, db.Query(bson.M{
+		ProjectIdKey:    projectID,
+		BuildVariantKey: buildVariant,
+		TaskNameKey:     taskName,
+		QuarantinedKey:  true,
+	}))
+}
+
+// FindQuarantinedByProject gets all of the project's quarantined tests.
+func FindQuarantinedByProject(ctx context.Context, projectID string) ([]TestFlakiness, error) {
+	return Find(ctx, db.Query(bson.M{
+		ProjectIdKey:   projectID,
+		QuarantinedKey: true,
+	}))
+}
+
+// Replace saves the test's current state.
+func (f *TestFlakiness) Replace(ctx context.Context) error {
+	_, err := db.Replace(ctx, Collection, bson.M{IdKey: f.ID}, f)
+	return errors.Wrapf(err, "saving flakiness of test '%s'", f.TestName)
+}
+
+// RemoveStale deletes the scores of the project's tests that aren't
+// quarantined and haven't been scored since the given time.
+func RemoveStale(ctx context.Context, projectID string, before time.Time) error {
+	err := db.RemoveAll(ctx, Collection, bson.M{
+		ProjectIdKey:   projectID,
+		QuarantinedKey: false,
+		UpdatedAtKey:   bson.M{"$lt": before},
+	})
+	return errors.Wrapf(err, "removing stale test flakiness for project '%s'", projectID)
+}
@@ -0,0 +1,4 @@
+// Package flakytest scores how flaky each test in a project is from its
+// recent mainline history and tracks which tests are automatically
+// quarantined because of it.
+package flakytest
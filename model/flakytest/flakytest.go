@@ -0,0 +1,175 @@
+package flakytest
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+const (
+	// retryPassWeight, alternationWeight and failureRateWeight are how much
+	// each signal contributes to a test's flakiness score. Retries that pass
+	// and mixed results on the same revision are the strongest signs that a
+	// failure wasn't caused by the code under test.
+	retryPassWeight   = 0.4
+	alternationWeight = 0.4
+	failureRateWeight = 0.2
+)
+
+// TestRun is the outcome of a test in one execution of a task.
+type TestRun struct {
+	TaskID     string
+	Execution  int
+	Revision   string
+	FinishTime time.Time
+	Failed     bool
+}
+
+// Scores are the flakiness signals computed from a test's runs.
+type Scores struct {
+	NumRuns     int
+	NumFailures int
+	// FailureRate is the fraction of runs that failed.
+	FailureRate float64
+	// RetryPassRate is the fraction of failures that passed when the same
+	// task was restarted.
+	RetryPassRate float64
+	// AlternationRate is the fraction of revisions with a failure that also
+	// had a passing run.
+	AlternationRate float64
+	// Score combines the signals into a single value between 0 and 1.
+	Score float64
+}
+
+// Compute scores a test from its runs.
+func Compute(runs []TestRun) Scores {
+	s := Scores{NumRuns: len(runs)}
+	if len(runs) == 0 {
+		return s
+	}
+
+	byTask := map[string][]TestRun{}
+	failedRevisions := map[string]bool{}
+	passedRevisions := map[string]bool{}
+	for _, r := range runs {
+		byTask[r.TaskID] = append(byTask[r.TaskID], r)
+		if r.Failed {
+			s.NumFailures++
+			failedRevisions[r.Revision] = true
+		} else {
+			passedRevisions[r.Revision] = true
+		}
+	}
+	s.FailureRate = float64(s.NumFailures) / float64(len(runs))
+
+	if s.NumFailures > 0 {
+		passedAfterRetry := 0
+		for _, executions := range byTask {
+			sort.Slice(executions, func(i, j int) bool { return executions[i].Execution < executions[j].Execution })
+			for i, r := range executions {
+				if !r.Failed {
+					continue
+				}
+				for _, later := range executions[i+1:] {
+					if !later.Failed {
+						passedAfterRetry++
+						break
+					}
+				}
+			}
+		}
+		s.RetryPassRate = float64(passedAfterRetry) / float64(s.NumFailures)
+
+		mixedRevisions := 0
+		for revision := range failedRevisions {
+			if passedRevisions[revision] {
+				mixedRevisions++
+			}
+		}
+		s.AlternationRate = float64(mixedRevisions) / float64(len(failedRevisions))
+	}
+
+	s.Score = retryPassWeight*s.RetryPassRate + alternationWeight*s.AlternationRate + failureRateWeight*s.FailureRate
+	return s
+}
+
+// CleanRunsSince returns the number of consecutive passing runs, counting back
+// from the most recent one, that finished after the given time.
+func CleanRunsSince(runs []TestRun, since time.Time) int {
+	sorted := make([]TestRun, len(runs))
+	copy(sorted, runs)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].FinishTime.After(sorted[j].FinishTime) })
+
+	clean := 0
+	for _, r := range sorted {
+		if !r.FinishTime.After(since) || r.Failed {
+			break
+		}
+		clean++
+	}
+	return clean
+}
+
+// TestFlakiness is the most recent flakiness score of a test and whether it's
+// automatically quarantined.
+type TestFlakiness struct {
+	ID           string `bson:"_id" json:"id"`
+	ProjectID    string `bson:"project_id" json:"project_id"`
+	BuildVariant string `bson:"build_variant" json:"build_variant"`
+	TaskName     string `bson:"task_name" json:"task_name"`
+	TestName     string `bson:"test_name" json:"test_name"`
+
+	NumRuns         int     `bson:"num_runs" json:"num_runs"`
+	NumFailures     int     `bson:"num_failures" json:"num_failures"`
+	FailureRate     float64 `bson:"failure_rate" json:"failure_rate"`
+	RetryPassRate   float64 `bson:"retry_pass_rate" json:"retry_pass_rate"`
+	AlternationRate float64 `bson:"alternation_rate" json:"alternation_rate"`
+	Score           float64 `bson:"score" json:"score"`
+
+	// LastTaskID and LastExecution are the most recent task run of the test.
+	LastTaskID    string `bson:"last_task_id" json:"last_task_id"`
+	LastExecution int    `bson:"last_execution" json:"last_execution"`
+
+	Quarantined   bool      `bson:"quarantined" json:"quarantined"`
+	QuarantinedAt time.Time `bson:"quarantined_at,omitempty" json:"quarantined_at,omitempty"`
+	// CleanRuns is the number of consecutive passing runs since the test
+	// was quarantined.
+	CleanRuns  int       `bson:"clean_runs" json:"clean_runs"`
+	ReleasedAt time.Time `bson:"released_at,omitempty" json:"released_at,omitempty"`
+	UpdatedAt  time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// MakeID returns the ID of the test's flakiness document.
+func MakeID(projectID, buildVariant, taskName, testName string) string {
+	hash := sha1.New()
+	for _, s := range []string{projectID, buildVariant, taskName, testName} {
+		_, _ = io.WriteString(hash, s)
+		_, _ = io.WriteString(hash, "\x00")
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
+
+// SetScores records the test's latest scores.
+func (f *TestFlakiness) SetScores(s Scores) {
+	f.NumRuns = s.NumRuns
+	f.NumFailures = s.NumFailures
+	f.FailureRate = s.FailureRate
+	f.RetryPassRate = s.RetryPassRate
+	f.AlternationRate = s.AlternationRate
+	f.Score = s.Score
+}
+
+// Quarantine marks the test as quarantined.
+func (f *TestFlakiness) Quarantine(now time.Time) {
+	f.Quarantined = true
+	f.QuarantinedAt = now
+	f.CleanRuns = 0
+}
+
+// Release marks the test as no longer quarantined.
+func (f *TestFlakiness) Release(now time.Time) {
+	f.Quarantined = false
+	f.ReleasedAt = now
+}
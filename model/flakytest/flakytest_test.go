@@ -0,0 +1,94 @@
+package flakytest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompute(t *testing.T) {
+	now := time.Now()
+
+	t.Run("NoRuns", func(t *testing.T) {
+		assert.Zero(t, Compute(nil))
+	})
+	t.Run("AlwaysPasses", func(t *testing.T) {
+		s := Compute([]TestRun{
+			{TaskID: "t1", Revision: "r1", FinishTime: now},
+			{TaskID: "t2", Revision: "r2", FinishTime: now},
+		})
+		assert.Equal(t, 2, s.NumRuns)
+		assert.Zero(t, s.NumFailures)
+		assert.Zero(t, s.Score)
+	})
+	t.Run("ConsistentFailureIsNotFlaky", func(t *testing.T) {
+		s := Compute([]TestRun{
+			{TaskID: "t1", Revision: "r1", Failed: true},
+			{TaskID: "t1", Execution: 1, Revision: "r1", Failed: true},
+			{TaskID: "t2", Revision: "r2", Failed: true},
+		})
+		assert.Equal(t, 3, s.NumFailures)
+		assert.Equal(t, 1.0, s.FailureRate)
+		assert.Zero(t, s.RetryPassRate)
+		assert.Zero(t, s.AlternationRate)
+		assert.InDelta(t, failureRateWeight, s.Score, 0.0001)
+	})
+	t.Run("PassesAfterRetry", func(t *testing.T) {
+		s := Compute([]TestRun{
+			{TaskID: "t1", Execution: 1, Revision: "r1"},
+			{TaskID: "t1", Revision: "r1", Failed: true},
+			{TaskID: "t2", Revision: "r2", Failed: true},
+			{TaskID: "t3", Revision: "r3"},
+		})
+		assert.Equal(t, 2, s.NumFailures)
+		assert.InDelta(t, 0.5, s.FailureRate, 0.0001)
+		assert.InDelta(t, 0.5, s.RetryPassRate, 0.0001, "only one of the two failures passed when retried")
+		assert.InDelta(t, 0.5, s.AlternationRate, 0.0001, "only one of the two failing revisions also passed")
+		assert.InDelta(t, retryPassWeight*0.5+alternationWeight*0.5+failureRateWeight*0.5, s.Score, 0.0001)
+	})
+	t.Run("AlternatesOnSameRevision", func(t *testing.T) {
+		s := Compute([]TestRun{
+			{TaskID: "t1", Revision: "r1", Failed: true},
+			{TaskID: "t2", Revision: "r1"},
+		})
+		assert.Zero(t, s.RetryPassRate, "the passing run was in a different task")
+		assert.Equal(t, 1.0, s.AlternationRate)
+	})
+}
+
+func TestCleanRunsSince(t *testing.T) {
+	now := time.Now()
+	runs := []TestRun{
+		{FinishTime: now.Add(-time.Hour)},
+		{FinishTime: now.Add(-4 * time.Hour), Failed: true},
+		{FinishTime: now.Add(-2 * time.Hour)},
+		{FinishTime: now.Add(-3 * time.Hour)},
+	}
+
+	assert.Equal(t, 3, CleanRunsSince(runs, now.Add(-24*time.Hour)), "should stop counting at the most recent failure")
+	assert.Equal(t, 2, CleanRunsSince(runs, now.Add(-150*time.Minute)), "should only count runs after the given time")
+	assert.Zero(t, CleanRunsSince(runs, now))
+	assert.Zero(t, CleanRunsSince(append(runs, TestRun{FinishTime: now, Failed: true}), now.Add(-24*time.Hour)))
+}
+
+func TestMakeID(t *testing.T) {
+	id := MakeID("project", "bv", "task", "test")
+	assert.Equal(t, id, MakeID("project", "bv", "task", "test"))
+	assert.NotEqual(t, id, MakeID("project", "bv", "task", "test2"))
+	assert.NotEqual(t, MakeID("project", "bv", "ta", "sktest"), MakeID("project", "bv", "task", "test"), "fields should not run together")
+}
+
+func TestQuarantineAndRelease(t *testing.T) {
+	now := time.Now()
+	f := TestFlakiness{CleanRuns: 4}
+	f.Quarantine(now)
+	assert.True(t, f.Quarantined)
+	assert.Equal(t, now, f.QuarantinedAt)
+	assert.Zero(t, f.CleanRuns)
+
+	later := now.Add(time.Hour)
+	f.Release(later)
+	assert.False(t, f.Quarantined)
+	assert.Equal(t, later, f.ReleasedAt)
+}
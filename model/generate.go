@@ -457,9 +457,14 @@ func validateGeneratedProjectMaxTasks(ctx context.Context, v *Version, taskID st
 	if err != nil {
 		return errors.Wrap(err, "getting admin settings")
 	}
-	totalTasks := numExistingTasks + tasksToBeCreated
-	if settings.TaskLimits.MaxTasksPerVersion > 0 && totalTasks > settings.TaskLimits.MaxTasksPerVersion {
-		return errors.Errorf("version's total number of tasks after generation (%d) exceeds maximum limit (%d)", totalTasks, settings.TaskLimits.MaxTasksPerVersion)
+	return checkGeneratedTasksLimit(numExistingTasks+tasksToBeCreated, settings.TaskLimits.MaxTasksPerVersion)
+}
+
+// checkGeneratedTasksLimit returns an error if the version's total number of
+// tasks after generation exceeds maxTasks, if it is positive.
+func checkGeneratedTasksLimit(totalTasks, maxTasks int) error {
+	if maxTasks > 0 && totalTasks > maxTasks {
+		return errors.Errorf("version's total number of tasks after generation (%d) exceeds maximum limit (%d)", totalTasks, maxTasks)
 	}
 	return nil
 }
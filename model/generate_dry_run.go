@@ -0,0 +1,131 @@
+package model
+
+import (
+	"context"
+	"sort"
+
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+)
+
+// GeneratedProjectDiff describes what a generated project adds to a project
+// config.
+type GeneratedProjectDiff struct {
+	// BuildVariants are the variants that did not exist before generation.
+	BuildVariants []string
+	// Tasks, TaskGroups and Functions are the definitions added by the
+	// generated project.
+	Tasks      []string
+	TaskGroups []string
+	Functions  []string
+	// VariantTasks are the variant-task pairs that would be created.
+	VariantTasks []TVPair
+	// Dependencies are the dependency edges that did not exist before
+	// generation, including new dependencies of existing tasks.
+	Dependencies []task.DependencyEdge
+}
+
+// DryRun merges the generated project into the project config exactly as
+// generate.tasks would, but without saving anything, and reports what the
+// generated project would add. It checks the generated project against the
+// original project, and checks the merged project for dependency cycles. If
+// maxTasks is positive, it also checks that the variant-tasks the original
+// project would create plus the ones the generated project adds don't exceed
+// it. The merged project is returned so that it can be validated further. pp
+// is modified in place.
+func (g *GeneratedProject) DryRun(ctx context.Context, p *Project, pp *ParserProject, maxTasks int) (*Project, *GeneratedProjectDiff, error) {
+	cachedProject := cacheProjectData(p)
+	if err := g.validateGeneratedProject(cachedProject); err != nil {
+		return nil, nil, errors.Wrap(err, "generated project is invalid")
+	}
+
+	if pp.Functions == nil {
+		pp.Functions = map[string]*YAMLCommandSet{}
+	}
+	newPP, err := g.addGeneratedProjectToConfig(pp, cachedProject)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "creating config from generated config")
+	}
+	newP, err := TranslateProject(ctx, newPP)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, TranslateProjectError)
+	}
+
+	catcher := grip.NewBasicCatcher()
+	graph := newP.DependencyGraph()
+	if cycles := graph.Cycles(); len(cycles) > 0 {
+		catcher.Wrapf(DependencyCycleError, "'%s'", cycles)
+	}
+	diff := g.diff(p, newP, newP.FindAllBuildVariantTasks())
+	numTasks := len(diff.VariantTasks)
+	for _, t := range p.FindAllBuildVariantTasks() {
+		if !t.IsDisabled() {
+			numTasks++
+		}
+	}
+	catcher.Add(checkGeneratedTasksLimit(numTasks, maxTasks))
+
+	return newP, diff, catcher.Resolve()
+}
+
+func (g *GeneratedProject) diff(oldP, newP *Project, newTasks []BuildVariantTaskUnit) *GeneratedProjectDiff {
+	diff := &GeneratedProjectDiff{}
+
+	oldVariants := map[string]bool{}
+	for _, bv := range oldP.BuildVariants {
+		oldVariants[bv.Name] = true
+	}
+	for _, bv := range g.BuildVariants {
+		if !oldVariants[bv.Name] {
+			diff.BuildVariants = append(diff.BuildVariants, bv.Name)
+		}
+	}
+	for _, t := range g.Tasks {
+		diff.Tasks = append(diff.Tasks, t.Name)
+	}
+	for _, tg := range g.TaskGroups {
+		diff.TaskGroups = append(diff.TaskGroups, tg.Name)
+	}
+	for name := range g.Functions {
+		diff.Functions = append(diff.Functions, name)
+	}
+
+	oldTasks := oldP.FindAllBuildVariantTasks()
+	oldPairs := map[TVPair]bool{}
+	for _, t := range oldTasks {
+		oldPairs[t.ToTVPair()] = true
+	}
+	for _, t := range newTasks {
+		if !oldPairs[t.ToTVPair()] {
+			diff.VariantTasks = append(diff.VariantTasks, t.ToTVPair())
+		}
+	}
+
+	oldEdges := map[task.DependencyEdge]bool{}
+	for _, edge := range dependenciesForTaskUnit(oldTasks, oldP) {
+		oldEdges[edge] = true
+	}
+	for _, edge := range dependenciesForTaskUnit(newTasks, newP) {
+		if !oldEdges[edge] {
+			oldEdges[edge] = true
+			diff.Dependencies = append(diff.Dependencies, edge)
+		}
+	}
+
+	sort.Strings(diff.BuildVariants)
+	sort.Strings(diff.Tasks)
+	sort.Strings(diff.TaskGroups)
+	sort.Strings(diff.Functions)
+	sort.Slice(diff.VariantTasks, func(i, j int) bool {
+		return diff.VariantTasks[i].String() < diff.VariantTasks[j].String()
+	})
+	sort.Slice(diff.Dependencies, func(i, j int) bool {
+		if diff.Dependencies[i].From != diff.Dependencies[j].From {
+			return diff.Dependencies[i].From.String() < diff.Dependencies[j].From.String()
+		}
+		return diff.Dependencies[i].To.String() < diff.Dependencies[j].To.String()
+	})
+
+	return diff
+}
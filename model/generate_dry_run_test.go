@@ -0,0 +1,116 @@
+package model
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratedProjectDryRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const projectYAML = `
+tasks:
+  - name: generator
+    commands:
+      - command: generate.tasks
+        params:
+          files: [generated.json]
+  - name: compile
+
+buildvariants:
+  - name: ubuntu
+    run_on: ubuntu2204-small
+    tasks:
+      - name: generator
+      - name: compile
+`
+
+	load := func(t *testing.T) (*Project, *ParserProject) {
+		p := &Project{}
+		pp, err := LoadProjectInto(ctx, []byte(projectYAML), &GetProjectOpts{ReadFileFrom: ReadFromLocal}, "", p)
+		require.NoError(t, err)
+		return p, pp
+	}
+
+	t.Run("ReportsAddedVariantsTasksAndDependencies", func(t *testing.T) {
+		p, pp := load(t)
+		g, err := ParseProjectFromJSONString(`{
+			"functions": {"run tests": [{"command": "shell.exec"}]},
+			"tasks": [
+				{"name": "test", "depends_on": [{"name": "compile"}], "commands": [{"func": "run tests"}]}
+			],
+			"buildvariants": [
+				{"name": "ubuntu", "tasks": [{"name": "test"}]},
+				{"name": "windows", "run_on": ["windows-small"], "tasks": [{"name": "compile"}, {"name": "test"}]}
+			]
+		}`)
+		require.NoError(t, err)
+
+		newP, diff, err := g.DryRun(ctx, p, pp, 0)
+		require.NoError(t, err)
+		require.NotNil(t, newP)
+		assert.Len(t, newP.BuildVariants, 2)
+
+		assert.Equal(t, []string{"windows"}, diff.BuildVariants)
+		assert.Equal(t, []string{"test"}, diff.Tasks)
+		assert.Equal(t, []string{"run tests"}, diff.Functions)
+		assert.Empty(t, diff.TaskGroups)
+		assert.Equal(t, []TVPair{
+			{Variant: "ubuntu", TaskName: "test"},
+			{Variant: "windows", TaskName: "compile"},
+			{Variant: "windows", TaskName: "test"},
+		}, diff.VariantTasks)
+		assert.Equal(t, []task.DependencyEdge{
+			{From: task.TaskNode{Name: "test", Variant: "ubuntu"}, To: task.TaskNode{Name: "compile", Variant: "ubuntu"}},
+			{From: task.TaskNode{Name: "test", Variant: "windows"}, To: task.TaskNode{Name: "compile", Variant: "windows"}},
+		}, diff.Dependencies)
+	})
+	t.Run("ReportsCycles", func(t *testing.T) {
+		p, pp := load(t)
+		g, err := ParseProjectFromJSONString(`{
+			"tasks": [
+				{"name": "a", "depends_on": [{"name": "b"}]},
+				{"name": "b", "depends_on": [{"name": "a"}]}
+			],
+			"buildvariants": [{"name": "ubuntu", "tasks": [{"name": "a"}, {"name": "b"}]}]
+		}`)
+		require.NoError(t, err)
+
+		_, diff, err := g.DryRun(ctx, p, pp, 0)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), DependencyCycleError.Error())
+		require.NotNil(t, diff)
+		assert.Len(t, diff.VariantTasks, 2)
+	})
+	t.Run("ReportsTaskLimit", func(t *testing.T) {
+		p, pp := load(t)
+		g, err := ParseProjectFromJSONString(`{
+			"tasks": [{"name": "test"}],
+			"buildvariants": [{"name": "ubuntu", "tasks": [{"name": "test"}]}]
+		}`)
+		require.NoError(t, err)
+
+		_, _, err = g.DryRun(ctx, p, pp, 2)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "version's total number of tasks after generation (3) exceeds maximum limit (2)")
+
+		p, pp = load(t)
+		_, _, err = g.DryRun(ctx, p, pp, 3)
+		assert.NoError(t, err)
+	})
+	t.Run("RejectsRedefinitions", func(t *testing.T) {
+		p, pp := load(t)
+		g, err := ParseProjectFromJSONString(`{"tasks": [{"name": "compile"}]}`)
+		require.NoError(t, err)
+
+		_, diff, err := g.DryRun(ctx, p, pp, 0)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "generated project is invalid")
+		assert.Nil(t, diff)
+	})
+}
@@ -0,0 +1,179 @@
+package lease
+
+import (
+	"context"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/mongodb/anser/bsonutil"
+	adb "github.com/mongodb/anser/db"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const Collection = "task_leases"
+
+var (
+	IdKey         = bsonutil.MustHaveTag(Lease{}, "ID")
+	ProjectIdKey  = bsonutil.MustHaveTag(Lease{}, "ProjectID")
+	NameKey       = bsonutil.MustHaveTag(Lease{}, "Name")
+	SlotKey       = bsonutil.MustHaveTag(Lease{}, "Slot")
+	TaskIdKey     = bsonutil.MustHaveTag(Lease{}, "TaskID")
+	ExecutionKey  = bsonutil.MustHaveTag(Lease{}, "Execution")
+	AcquiredAtKey = bsonutil.MustHaveTag(Lease{}, "AcquiredAt")
+	RenewedAtKey  = bsonutil.MustHaveTag(Lease{}, "RenewedAt")
+	ExpiresAtKey  = bsonutil.MustHaveTag(Lease{}, "ExpiresAt")
+)
+
+// Find gets every lease matching the given query.
+func Find(ctx context.Context, query db.Q) ([]Lease, error) {
+	leases := []Lease{}
+	if err := db.FindAllQ(ctx, Collection, query, &leases); err != nil {
+		return nil, errors.Wrap(err, "finding leases")
+	}
+	return leases, nil
+}
+
+// FindOneId gets the lease with the given ID.
+func FindOneId(ctx context.Context, id string) (*Lease, error) {
+	l := &Lease{}
+	err := db.FindOneQ(ctx, Collection, db.Query(bson.M{IdKey: id}), l)
+	if adb.ResultsNotFound(err) {
+		return nil, nil
+	}
+	return l, errors.Wrapf(err, "finding lease '%s'", id)
+}
+
+// FindByProject gets the project's leases that are held as of the given time.
+func FindByProject(ctx context.Context, projectID string, now time.Time) ([]Lease, error) {
+	return Find(ctx, db.Query(bson.M{
+		ProjectIdKey: projectID,
+		ExpiresAtKey: bson.M{"$gt": now},
+	}).Sort([]string{NameKey, SlotKey}))
+}
+
+// FindHolders gets the leases on the project's lock that are held as of the
+// given time.
+func FindHolders(ctx context.Context, projectID, name string, now time.Time) ([]Lease, error) {
+	return Find(ctx, db.Query(bson.M{
+		ProjectIdKey: projectID,
+		NameKey:      name,
+		ExpiresAtKey: bson.M{"$gt": now},
+	}).Sort([]string{SlotKey}))
+}
+
+// Acquire tries to take one of the given number of slots of the project's
+// lock for the task execution. A slot can be taken if no one has held it yet,
+// its lease has expired, or the task execution already holds it. If the task
+// execution already holds a slot, that lease is renewed. It returns the lease
+// if the lock was acquired and nil if every slot is held by other tasks.
+func Acquire(ctx context.Context, projectID, name string, slots int, taskID string, execution int, now time.Time) (*Lease, error) {
+	if slots < 1 || slots > MaxSlots {
+		return nil, errors.Errorf("number of slots must be between 1 and %d", MaxSlots)
+	}
+
+	held, err := Find(ctx, db.Query(bson.M{
+		ProjectIdKey: projectID,
+		NameKey:      name,
+		TaskIdKey:    taskID,
+		ExecutionKey: execution,
+		ExpiresAtKey: bson.M{"$gt": now},
+	}).Sort([]string{SlotKey}).Limit(1))
+	if err != nil {
+		return nil, errors.Wrapf(err, "checking if task '%s' already holds lock '%s'", taskID, name)
+	}
+	if len(held) > 0 {
+		l := held[0]
+		if err = renew(ctx, bson.M{IdKey: l.ID, TaskIdKey: taskID, ExecutionKey: execution}, now); err != nil {
+			return nil, errors.Wrapf(err, "renewing lease '%s'", l.ID)
+		}
+		l.RenewedAt = now
+		l.ExpiresAt = now.Add(DefaultTTL)
+		return &l, nil
+	}
+
+	for slot := 0; slot < slots; slot++ {
+		l := Lease{
+			ID:         leaseID(projectID, name, slot),
+			ProjectID:  projectID,
+			Name:       name,
+			Slot:       slot,
+			TaskID:     taskID,
+			Execution:  execution,
+			AcquiredAt: now,
+			RenewedAt:  now,
+			ExpiresAt:  now.Add(DefaultTTL),
+		}
+		// If the slot is held by another task, the filter doesn't match and
+		// the upsert tries to insert a lease with the same ID, which fails.
+		_, err = db.Upsert(ctx, Collection, bson.M{
+			IdKey:        l.ID,
+			ExpiresAtKey: bson.M{"$lte": now},
+		}, bson.M{"$set": bson.M{
+			ProjectIdKey:  l.ProjectID,
+			NameKey:       l.Name,
+			SlotKey:       l.Slot,
+			TaskIdKey:     l.TaskID,
+			ExecutionKey:  l.Execution,
+			AcquiredAtKey: l.AcquiredAt,
+			RenewedAtKey:  l.RenewedAt,
+			ExpiresAtKey:  l.ExpiresAt,
+		}})
+		if db.IsDuplicateKey(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "acquiring lease '%s'", l.ID)
+		}
+		return &l, nil
+	}
+
+	return nil, nil
+}
+
+// Release releases the task execution's hold on the project's lock. It is a
+// no-op if the task execution does not hold the lock, so an earlier execution
+// of a restarted task can't release the lock held by a later one.
+func Release(ctx context.Context, projectID, name, taskID string, execution int) error {
+	err := db.RemoveAll(ctx, Collection, bson.M{
+		ProjectIdKey: projectID,
+		NameKey:      name,
+		TaskIdKey:    taskID,
+		ExecutionKey: execution,
+	})
+	return errors.Wrapf(err, "releasing lock '%s' held by task '%s' execution %d", name, taskID, execution)
+}
+
+// ReleaseAllForTask releases every lock held by the task execution.
+func ReleaseAllForTask(ctx context.Context, taskID string, execution int) error {
+	err := db.RemoveAll(ctx, Collection, bson.M{TaskIdKey: taskID, ExecutionKey: execution})
+	return errors.Wrapf(err, "releasing locks held by task '%s' execution %d", taskID, execution)
+}
+
+// ForceRelease releases the project's lock regardless of which tasks hold it.
+func ForceRelease(ctx context.Context, projectID, name string) error {
+	err := db.RemoveAll(ctx, Collection, bson.M{
+		ProjectIdKey: projectID,
+		NameKey:      name,
+	})
+	return errors.Wrapf(err, "force releasing lock '%s' in project '%s'", name, projectID)
+}
+
+// RenewForTask extends every unexpired lease held by the task execution by
+// DefaultTTL from the given time. Expired leases are not renewed, since
+// another task may have acquired them since.
+func RenewForTask(ctx context.Context, taskID string, execution int, now time.Time) error {
+	return errors.Wrapf(renew(ctx, bson.M{
+		TaskIdKey:    taskID,
+		ExecutionKey: execution,
+		ExpiresAtKey: bson.M{"$gt": now},
+	}, now), "renewing leases held by task '%s'", taskID)
+}
+
+func renew(ctx context.Context, query bson.M, now time.Time) error {
+	_, err := db.UpdateAll(ctx, Collection, query, bson.M{"$set": bson.M{
+		RenewedAtKey: now,
+		ExpiresAtKey: now.Add(DefaultTTL),
+	}})
+	return err
+}
@@ -0,0 +1,182 @@
+package lease
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	testutil.Setup()
+}
+
+func TestLeases(t *testing.T) {
+	defer func() {
+		assert.NoError(t, db.ClearCollections(Collection))
+	}()
+	now := time.Now().Round(time.Millisecond)
+
+	for tName, tCase := range map[string]func(t *testing.T){
+		"AcquiresFreeLock": func(t *testing.T) {
+			l, err := Acquire(t.Context(), "project", "staging", 1, "t1", 0, now)
+			require.NoError(t, err)
+			require.NotNil(t, l)
+			assert.Equal(t, "project/staging/0", l.ID)
+			assert.Equal(t, "t1", l.TaskID)
+			assert.True(t, l.ExpiresAt.Equal(now.Add(DefaultTTL)))
+
+			holders, err := FindHolders(t.Context(), "project", "staging", now)
+			require.NoError(t, err)
+			require.Len(t, holders, 1)
+			assert.Equal(t, "t1", holders[0].TaskID)
+		},
+		"DoesNotAcquireHeldLock": func(t *testing.T) {
+			l, err := Acquire(t.Context(), "project", "staging", 1, "t1", 0, now)
+			require.NoError(t, err)
+			require.NotNil(t, l)
+
+			l, err = Acquire(t.Context(), "project", "staging", 1, "t2", 0, now)
+			require.NoError(t, err)
+			assert.Nil(t, l)
+		},
+		"ReacquiringHeldLockRenewsIt": func(t *testing.T) {
+			_, err := Acquire(t.Context(), "project", "staging", 2, "t1", 0, now)
+			require.NoError(t, err)
+
+			later := now.Add(time.Minute)
+			l, err := Acquire(t.Context(), "project", "staging", 2, "t1", 0, later)
+			require.NoError(t, err)
+			require.NotNil(t, l)
+			assert.Zero(t, l.Slot, "should not take a second slot")
+			assert.True(t, l.ExpiresAt.Equal(later.Add(DefaultTTL)))
+		},
+		"AcquiresAvailableSlots": func(t *testing.T) {
+			for i, taskID := range []string{"t1", "t2"} {
+				l, err := Acquire(t.Context(), "project", "license", 2, taskID, 0, now)
+				require.NoError(t, err)
+				require.NotNil(t, l)
+				assert.Equal(t, i, l.Slot)
+			}
+			l, err := Acquire(t.Context(), "project", "license", 2, "t3", 0, now)
+			require.NoError(t, err)
+			assert.Nil(t, l)
+		},
+		"AcquiresExpiredLock": func(t *testing.T) {
+			_, err := Acquire(t.Context(), "project", "staging", 1, "t1", 0, now)
+			require.NoError(t, err)
+
+			l, err := Acquire(t.Context(), "project", "staging", 1, "t2", 0, now.Add(DefaultTTL))
+			require.NoError(t, err)
+			require.NotNil(t, l)
+			assert.Equal(t, "t2", l.TaskID)
+		},
+		"ScopesLocksToProject": func(t *testing.T) {
+			_, err := Acquire(t.Context(), "project", "staging", 1, "t1", 0, now)
+			require.NoError(t, err)
+			l, err := Acquire(t.Context(), "other", "staging", 1, "t2", 0, now)
+			require.NoError(t, err)
+			assert.NotNil(t, l)
+		},
+		"RejectsInvalidSlots": func(t *testing.T) {
+			_, err := Acquire(t.Context(), "project", "staging", 0, "t1", 0, now)
+			assert.Error(t, err)
+			_, err = Acquire(t.Context(), "project", "staging", MaxSlots+1, "t1", 0, now)
+			assert.Error(t, err)
+		},
+		"ReleasesOnlyTaskLease": func(t *testing.T) {
+			_, err := Acquire(t.Context(), "project", "license", 2, "t1", 0, now)
+			require.NoError(t, err)
+			_, err = Acquire(t.Context(), "project", "license", 2, "t2", 0, now)
+			require.NoError(t, err)
+
+			require.NoError(t, Release(t.Context(), "project", "license", "t1", 0))
+			require.NoError(t, Release(t.Context(), "project", "license", "t1", 0), "releasing should be idempotent")
+
+			holders, err := FindHolders(t.Context(), "project", "license", now)
+			require.NoError(t, err)
+			require.Len(t, holders, 1)
+			assert.Equal(t, "t2", holders[0].TaskID)
+		},
+		"DoesNotReleaseLeaseOfLaterExecution": func(t *testing.T) {
+			_, err := Acquire(t.Context(), "project", "license", 1, "t1", 1, now)
+			require.NoError(t, err)
+
+			require.NoError(t, Release(t.Context(), "project", "license", "t1", 0))
+			require.NoError(t, ReleaseAllForTask(t.Context(), "t1", 0))
+			holders, err := FindHolders(t.Context(), "project", "license", now)
+			require.NoError(t, err)
+			require.Len(t, holders, 1, "an earlier execution should not release the lease")
+			assert.Equal(t, 1, holders[0].Execution)
+
+			require.NoError(t, Release(t.Context(), "project", "license", "t1", 1))
+			holders, err = FindHolders(t.Context(), "project", "license", now)
+			require.NoError(t, err)
+			assert.Empty(t, holders)
+		},
+		"ReleasesAllForTask": func(t *testing.T) {
+			_, err := Acquire(t.Context(), "project", "staging", 1, "t1", 0, now)
+			require.NoError(t, err)
+			_, err = Acquire(t.Context(), "project", "license", 1, "t1", 0, now)
+			require.NoError(t, err)
+			_, err = Acquire(t.Context(), "project", "other", 1, "t2", 0, now)
+			require.NoError(t, err)
+
+			require.NoError(t, ReleaseAllForTask(t.Context(), "t1", 0))
+			leases, err := FindByProject(t.Context(), "project", now)
+			require.NoError(t, err)
+			require.Len(t, leases, 1)
+			assert.Equal(t, "t2", leases[0].TaskID)
+		},
+		"ForceReleases": func(t *testing.T) {
+			_, err := Acquire(t.Context(), "project", "license", 2, "t1", 0, now)
+			require.NoError(t, err)
+			_, err = Acquire(t.Context(), "project", "license", 2, "t2", 0, now)
+			require.NoError(t, err)
+
+			require.NoError(t, ForceRelease(t.Context(), "project", "license"))
+			holders, err := FindHolders(t.Context(), "project", "license", now)
+			require.NoError(t, err)
+			assert.Empty(t, holders)
+		},
+		"RenewsUnexpiredLeases": func(t *testing.T) {
+			_, err := Acquire(t.Context(), "project", "staging", 1, "t1", 0, now)
+			require.NoError(t, err)
+			_, err = Acquire(t.Context(), "project", "license", 1, "t1", 0, now.Add(-2*DefaultTTL))
+			require.NoError(t, err)
+
+			later := now.Add(time.Minute)
+			require.NoError(t, RenewForTask(t.Context(), "t1", 0, later))
+
+			l, err := FindOneId(t.Context(), leaseID("project", "staging", 0))
+			require.NoError(t, err)
+			require.NotNil(t, l)
+			assert.True(t, l.ExpiresAt.Equal(later.Add(DefaultTTL)))
+
+			l, err = FindOneId(t.Context(), leaseID("project", "license", 0))
+			require.NoError(t, err)
+			require.NotNil(t, l)
+			assert.True(t, l.IsExpired(later), "expired lease should not be renewed")
+		},
+		"DoesNotRenewOtherExecution": func(t *testing.T) {
+			_, err := Acquire(t.Context(), "project", "staging", 1, "t1", 0, now)
+			require.NoError(t, err)
+
+			later := now.Add(time.Minute)
+			require.NoError(t, RenewForTask(t.Context(), "t1", 1, later))
+			l, err := FindOneId(t.Context(), leaseID("project", "staging", 0))
+			require.NoError(t, err)
+			require.NotNil(t, l)
+			assert.True(t, l.ExpiresAt.Equal(now.Add(DefaultTTL)))
+		},
+	} {
+		t.Run(tName, func(t *testing.T) {
+			require.NoError(t, db.ClearCollections(Collection))
+
+			tCase(t)
+		})
+	}
+}
@@ -0,0 +1,6 @@
+// Package lease implements project-scoped locks that tasks hold for a limited
+// time. Tasks acquire and release them with the lock.acquire and lock.release
+// commands, the agent heartbeat keeps them alive while the task runs, and
+// they are released when the task ends or expire if the agent stops
+// heartbeating.
+package lease
@@ -0,0 +1,45 @@
+package lease
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultTTL is how long a lease lasts without being renewed. The agent
+	// heartbeat renews a task's leases well within this window.
+	DefaultTTL = 5 * time.Minute
+	// MaxSlots is the maximum number of tasks that can hold the same lock at
+	// once.
+	MaxSlots = 100
+)
+
+// Lease is a task's hold on one slot of a named lock in a project. A lock with
+// a single slot is a mutex, and a lock with several slots is a semaphore that
+// lets up to that many tasks hold it at once.
+type Lease struct {
+	// ID is the project, lock name and slot that uniquely identify the lease.
+	ID        string `bson:"_id" json:"id"`
+	ProjectID string `bson:"project_id" json:"project_id"`
+	Name      string `bson:"name" json:"name"`
+	Slot      int    `bson:"slot" json:"slot"`
+
+	// TaskID and Execution identify the task holding the lease.
+	TaskID    string `bson:"task_id" json:"task_id"`
+	Execution int    `bson:"execution" json:"execution"`
+
+	AcquiredAt time.Time `bson:"acquired_at" json:"acquired_at"`
+	RenewedAt  time.Time `bson:"renewed_at" json:"renewed_at"`
+	// ExpiresAt is when the lease stops being held unless it's renewed.
+	ExpiresAt time.Time `bson:"expires_at" json:"expires_at"`
+}
+
+// IsExpired returns whether the lease was no longer held as of the given
+// time.
+func (l *Lease) IsExpired(now time.Time) bool {
+	return !l.ExpiresAt.After(now)
+}
+
+func leaseID(projectID, name string, slot int) string {
+	return fmt.Sprintf("%s/%s/%d", projectID, name, slot)
+}
@@ -0,0 +1,326 @@
+package log
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/evergreen-ci/pail"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/level"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultCompactedChunkSize is the default maximum size, in bytes, of
+	// the uncompressed data in a chunk written by a compaction.
+	DefaultCompactedChunkSize = 32 * 1024 * 1024
+
+	// generationSequenceOffset is the range of chunk sequences reserved for
+	// each compaction generation. Chunks appended by log senders always use
+	// sequences in the range of generation 0.
+	generationSequenceOffset = 1000000000
+	// lastGenerationSequence is the offset, within the range of a
+	// compaction generation, of the sequence of the last chunk written by
+	// the compaction. A generation is only complete once that chunk exists.
+	lastGenerationSequence = generationSequenceOffset - 1
+)
+
+// CompactOptions represents the arguments for compacting Evergreen logs.
+type CompactOptions struct {
+	// LogNames are the names of the logs to compact, prefixes may be
+	// specified. Each log is compacted separately. At least one name must
+	// be specified.
+	LogNames []string
+	// Generation identifies the compaction. The chunks written by the
+	// compaction are ordered after the chunks of every earlier generation,
+	// which are its input. Chunks left over from a previous attempt at the
+	// same generation are replaced. Must be greater than 0.
+	Generation int
+	// TailN, if greater than 0, only keeps the last N lines of each log.
+	TailN int
+	// MinPriority only keeps the lines at or above this priority.
+	MinPriority level.Priority
+	// MaxChunkSize is the maximum size, in bytes, of the uncompressed data
+	// in each chunk written by the compaction. Lines are never split, so a
+	// single line larger than the maximum gets its own chunk. Defaults to
+	// DefaultCompactedChunkSize.
+	MaxChunkSize int
+}
+
+func (o *CompactOptions) validate() error {
+	catcher := grip.NewBasicCatcher()
+	catcher.NewWhen(len(o.LogNames) == 0, "must specify at least one log name")
+	catcher.NewWhen(o.Generation <= 0, "generation must be greater than 0")
+	catcher.NewWhen(o.TailN < 0, "tail cannot be negative")
+	catcher.NewWhen(o.MaxChunkSize < 0, "max chunk size cannot be negative")
+	if o.MaxChunkSize == 0 {
+		o.MaxChunkSize = DefaultCompactedChunkSize
+	}
+
+	return catcher.Resolve()
+}
+
+func (o CompactOptions) filtered() bool {
+	return o.TailN > 0 || o.MinPriority > 0
+}
+
+// CompactResult summarizes the work done by a compaction.
+type CompactResult struct {
+	// LogsCompacted is the number of logs that were rewritten.
+	LogsCompacted int
+	// ChunksRead is the number of existing chunks that were read.
+	ChunksRead int
+	// ChunksWritten is the number of new chunks that were written.
+	ChunksWritten int
+	// LinesWritten is the number of lines in the new chunks.
+	LinesWritten int
+	// BytesUploaded is the number of bytes uploaded to storage, as
+	// reported by the bucket.
+	BytesUploaded int64
+	// PutRequests is the number of PUT requests made to storage.
+	PutRequests int
+}
+
+// Compact rewrites each of the given logs into as few chunks as possible,
+// optionally dropping lines that don't match the tail and priority filters.
+// The new chunks keep the key format read by Get, but the chunks they replace
+// are not removed; once the caller has recorded that the generation is
+// complete, it should call RemoveOlderGenerations. Get only reads the chunks
+// of a generation once all of them are written, and then ignores the chunks
+// of earlier generations.
+//
+// Logs stored in a single chunk are left alone unless lines are filtered out,
+// as are logs that a later generation has already rewritten.
+func (s *logServiceV0) Compact(ctx context.Context, opts CompactOptions) (CompactResult, error) {
+	var res CompactResult
+	if err := opts.validate(); err != nil {
+		return res, errors.Wrap(err, "invalid compact options")
+	}
+
+	chunkGroups, err := s.listLogChunks(ctx, opts.LogNames)
+	if err != nil {
+		return res, errors.Wrap(err, "getting log chunks")
+	}
+
+	for _, group := range chunkGroups {
+		var sources, stale []chunkInfo
+		var compactedLater bool
+		for _, chunk := range group.chunks {
+			switch gen := chunkGeneration(chunk); {
+			case gen < opts.Generation:
+				sources = append(sources, chunk)
+			case gen == opts.Generation:
+				stale = append(stale, chunk)
+			default:
+				compactedLater = true
+			}
+		}
+		if compactedLater {
+			// A later generation already rewrote this log, for example
+			// an earlier execution of a task compacted before the task
+			// was restarted.
+			continue
+		}
+		// Read the earlier generations the same way Get does, so that a
+		// generation whose older chunks were not removed yet, or that was
+		// interrupted, doesn't duplicate lines.
+		sources = latestGenerationChunks(sources)
+		if len(sources) == 0 || (len(sources) == 1 && len(stale) == 0 && !opts.filtered()) {
+			continue
+		}
+
+		if len(stale) > 0 {
+			if err = s.removeChunks(ctx, stale); err != nil {
+				return res, errors.Wrapf(err, "removing chunks left over by a previous compaction of log '%s'", group.name)
+			}
+		}
+		if err = s.compactChunkGroup(ctx, group.name, sources, opts, &res); err != nil {
+			return res, errors.Wrapf(err, "compacting log '%s'", group.name)
+		}
+		res.LogsCompacted++
+		res.ChunksRead += len(sources)
+	}
+
+	return res, nil
+}
+
+func (s *logServiceV0) compactChunkGroup(ctx context.Context, logName string, sources []chunkInfo, opts CompactOptions, res *CompactResult) error {
+	it := newChunkIterator(ctx, chunkIteratorOptions{
+		bucket: s.bucket,
+		chunks: sources,
+		parser: s.getParser(logName),
+		tailN:  opts.TailN,
+	})
+	defer func() {
+		grip.Warning(ctx, errors.Wrap(it.Close(), "closing log chunk iterator"))
+	}()
+
+	var (
+		buf        bytes.Buffer
+		sequence   = opts.Generation * generationSequenceOffset
+		start, end int64
+		numLines   int
+		written    int
+	)
+	flush := func(force bool) error {
+		if numLines == 0 && !force {
+			return nil
+		}
+
+		key := logName + "/" + s.createChunkKey(sequence, start, end, numLines)
+		uploaded, puts, err := s.putChunk(ctx, key, buf.Bytes())
+		res.PutRequests += puts
+		res.BytesUploaded += uploaded
+		if err != nil {
+			return err
+		}
+		res.ChunksWritten++
+		res.LinesWritten += numLines
+		written++
+
+		sequence++
+		buf.Reset()
+		numLines = 0
+		return nil
+	}
+
+	for it.Next() {
+		line := it.Item()
+		if line.Priority < opts.MinPriority {
+			continue
+		}
+
+		raw := s.formatRawLine(line)
+		if numLines > 0 && buf.Len()+len(raw) > opts.MaxChunkSize {
+			if err := flush(false); err != nil {
+				return err
+			}
+		}
+		if numLines == 0 {
+			start = line.Timestamp
+		}
+		end = line.Timestamp
+		buf.WriteString(raw)
+		numLines++
+	}
+	if err := it.Err(); err != nil {
+		return errors.Wrap(err, "reading log lines")
+	}
+
+	// The last chunk marks the generation as complete, so it must be
+	// written after every other chunk.
+	sequence = opts.Generation*generationSequenceOffset + lastGenerationSequence
+	if written > 0 || numLines > 0 {
+		return flush(true)
+	}
+
+	// Every line was filtered out. Write an empty chunk anyway so that
+	// removing the older generations leaves an empty log behind rather than
+	// the unfiltered one.
+	start, end = sources[0].start, sources[len(sources)-1].end
+	return flush(true)
+}
+
+// RemoveOlderGenerations removes the chunks written before the given
+// compaction generation from each of the given logs that the generation
+// completely rewrote. Logs that were not rewritten by the generation, or whose
+// rewrite was interrupted, are left alone.
+// Returns the number of chunks removed.
+func (s *logServiceV0) RemoveOlderGenerations(ctx context.Context, logNames []string, generation int) (int, error) {
+	chunkGroups, err := s.listLogChunks(ctx, logNames)
+	if err != nil {
+		return 0, errors.Wrap(err, "getting log chunks")
+	}
+
+	var toRemove []chunkInfo
+	for _, group := range chunkGroups {
+		var older []chunkInfo
+		var rewritten bool
+		for _, chunk := range group.chunks {
+			switch gen := chunkGeneration(chunk); {
+			case gen < generation:
+				older = append(older, chunk)
+			case gen == generation && isLastGenerationChunk(chunk):
+				rewritten = true
+			}
+		}
+		if rewritten {
+			toRemove = append(toRemove, older...)
+		}
+	}
+
+	return len(toRemove), errors.Wrap(s.removeChunks(ctx, toRemove), "removing chunks")
+}
+
+// Delete removes every chunk of the given logs. Returns the number of chunks
+// removed.
+func (s *logServiceV0) Delete(ctx context.Context, logNames []string) (int, error) {
+	chunkGroups, err := s.listLogChunks(ctx, logNames)
+	if err != nil {
+		return 0, errors.Wrap(err, "getting log chunks")
+	}
+
+	var toRemove []chunkInfo
+	for _, group := range chunkGroups {
+		toRemove = append(toRemove, group.chunks...)
+	}
+
+	return len(toRemove), errors.Wrap(s.removeChunks(ctx, toRemove), "removing chunks")
+}
+
+func (s *logServiceV0) removeChunks(ctx context.Context, chunks []chunkInfo) error {
+	if len(chunks) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(chunks))
+	for _, chunk := range chunks {
+		keys = append(keys, chunk.key)
+	}
+
+	return s.bucket.RemoveMany(ctx, keys...)
+}
+
+// putChunk writes the raw lines of a chunk to the bucket. Returns the number
+// of bytes uploaded and the number of PUT requests made, which are only
+// reported by S3 buckets.
+func (s *logServiceV0) putChunk(ctx context.Context, key string, rawLines []byte) (int64, int, error) {
+	// S3 buckets report post-compression bytes; non-S3 fall through to plain Put and incur no S3 cost.
+	if pc, ok := s.bucket.(pail.StreamPutCounterWithBytes); ok {
+		puts, uploadedBytes, err := pc.PutWithCountAndBytes(ctx, key, bytes.NewReader(rawLines))
+		return uploadedBytes, puts, errors.Wrap(err, "writing log chunk to bucket")
+	}
+
+	return 0, 0, errors.Wrap(s.bucket.Put(ctx, key, bytes.NewReader(rawLines)), "writing log chunk to bucket")
+}
+
+// chunkGeneration returns the compaction generation that wrote the chunk.
+func chunkGeneration(chunk chunkInfo) int {
+	return chunk.sequence / generationSequenceOffset
+}
+
+// isLastGenerationChunk returns whether the chunk is the last one written by
+// a compaction, which means that its generation is complete.
+func isLastGenerationChunk(chunk chunkInfo) bool {
+	return chunk.sequence > 0 && chunk.sequence%generationSequenceOffset == lastGenerationSequence
+}
+
+// latestGenerationChunks returns the chunks of the latest complete
+// compaction generation, given chunks sorted by sequence. The chunks appended
+// by log senders form generation 0, which is always complete.
+func latestGenerationChunks(chunks []chunkInfo) []chunkInfo {
+	latest := 0
+	for _, chunk := range chunks {
+		if isLastGenerationChunk(chunk) {
+			latest = max(latest, chunkGeneration(chunk))
+		}
+	}
+
+	var filtered []chunkInfo
+	for _, chunk := range chunks {
+		if chunkGeneration(chunk) == latest {
+			filtered = append(filtered, chunk)
+		}
+	}
+	return filtered
+}
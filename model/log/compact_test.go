@@ -0,0 +1,249 @@
+package log
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/pail"
+	"github.com/mongodb/grip/level"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompact(t *testing.T) {
+	ctx := t.Context()
+
+	const logName = "project/task/0/task_logs/task"
+	appendLines := func(t *testing.T, svc *logServiceV0, numChunks, linesPerChunk int) []LogLine {
+		var all []LogLine
+		ts := time.Now().UnixNano()
+		for i := 0; i < numChunks; i++ {
+			var lines []LogLine
+			for j := 0; j < linesPerChunk; j++ {
+				priority := level.Info
+				if j == linesPerChunk-1 {
+					priority = level.Error
+				}
+				lines = append(lines, LogLine{
+					LogName:   logName,
+					Priority:  priority,
+					Timestamp: ts,
+					Data:      fmt.Sprintf("chunk %d line %d", i, j),
+				})
+				ts++
+			}
+			require.NoError(t, ignoreBytes(svc.Append(ctx, logName, 0, lines)))
+			all = append(all, lines...)
+		}
+		return all
+	}
+	countChunks := func(t *testing.T, svc *logServiceV0) int {
+		keys, err := svc.GetChunkKeys(ctx, []string{logName})
+		require.NoError(t, err)
+		return len(keys)
+	}
+	newService := func(t *testing.T) *logServiceV0 {
+		bucket, err := pail.NewLocalBucket(pail.LocalOptions{Path: t.TempDir()})
+		require.NoError(t, err)
+		return NewLogServiceV0(bucket)
+	}
+
+	for tName, tCase := range map[string]func(*testing.T, *logServiceV0){
+		"MergesChunksWithoutChangingLines": func(t *testing.T, svc *logServiceV0) {
+			lines := appendLines(t, svc, 10, 5)
+
+			res, err := svc.Compact(ctx, CompactOptions{LogNames: []string{logName}, Generation: 1})
+			require.NoError(t, err)
+			assert.Equal(t, 1, res.LogsCompacted)
+			assert.Equal(t, 10, res.ChunksRead)
+			assert.Equal(t, 1, res.ChunksWritten)
+			assert.Equal(t, len(lines), res.LinesWritten)
+
+			removed, err := svc.RemoveOlderGenerations(ctx, []string{logName}, 1)
+			require.NoError(t, err)
+			assert.Equal(t, 10, removed)
+			assert.Equal(t, 1, countChunks(t, svc))
+			assert.Equal(t, lines, readLogLines(t, svc, ctx, GetOptions{LogNames: []string{logName}}))
+			assert.Equal(t, lines[len(lines)-3:], readLogLines(t, svc, ctx, GetOptions{LogNames: []string{logName}, TailN: 3}))
+		},
+		"SplitsLargeLogsIntoMultipleChunks": func(t *testing.T, svc *logServiceV0) {
+			lines := appendLines(t, svc, 4, 10)
+
+			res, err := svc.Compact(ctx, CompactOptions{LogNames: []string{logName}, Generation: 1, MaxChunkSize: 200})
+			require.NoError(t, err)
+			assert.Greater(t, res.ChunksWritten, 1)
+			_, err = svc.RemoveOlderGenerations(ctx, []string{logName}, 1)
+			require.NoError(t, err)
+			assert.Equal(t, res.ChunksWritten, countChunks(t, svc))
+			assert.Equal(t, lines, readLogLines(t, svc, ctx, GetOptions{LogNames: []string{logName}}))
+		},
+		"KeepsOnlyTail": func(t *testing.T, svc *logServiceV0) {
+			lines := appendLines(t, svc, 3, 5)
+
+			_, err := svc.Compact(ctx, CompactOptions{LogNames: []string{logName}, Generation: 1, TailN: 7})
+			require.NoError(t, err)
+			_, err = svc.RemoveOlderGenerations(ctx, []string{logName}, 1)
+			require.NoError(t, err)
+			assert.Equal(t, lines[len(lines)-7:], readLogLines(t, svc, ctx, GetOptions{LogNames: []string{logName}}))
+		},
+		"KeepsOnlyLinesAboveMinPriority": func(t *testing.T, svc *logServiceV0) {
+			lines := appendLines(t, svc, 3, 5)
+
+			_, err := svc.Compact(ctx, CompactOptions{LogNames: []string{logName}, Generation: 1, MinPriority: level.Error})
+			require.NoError(t, err)
+			_, err = svc.RemoveOlderGenerations(ctx, []string{logName}, 1)
+			require.NoError(t, err)
+			assert.Equal(t, []LogLine{lines[4], lines[9], lines[14]}, readLogLines(t, svc, ctx, GetOptions{LogNames: []string{logName}}))
+		},
+		"LeavesEmptyLogWhenEverythingIsFiltered": func(t *testing.T, svc *logServiceV0) {
+			appendLines(t, svc, 3, 5)
+
+			_, err := svc.Compact(ctx, CompactOptions{LogNames: []string{logName}, Generation: 1, MinPriority: level.Critical})
+			require.NoError(t, err)
+			_, err = svc.RemoveOlderGenerations(ctx, []string{logName}, 1)
+			require.NoError(t, err)
+			assert.Equal(t, 1, countChunks(t, svc))
+			assert.Empty(t, readLogLines(t, svc, ctx, GetOptions{LogNames: []string{logName}}))
+		},
+		"SkipsSingleChunkLogs": func(t *testing.T, svc *logServiceV0) {
+			lines := appendLines(t, svc, 1, 5)
+
+			res, err := svc.Compact(ctx, CompactOptions{LogNames: []string{logName}, Generation: 1})
+			require.NoError(t, err)
+			assert.Zero(t, res.LogsCompacted)
+			removed, err := svc.RemoveOlderGenerations(ctx, []string{logName}, 1)
+			require.NoError(t, err)
+			assert.Zero(t, removed)
+			assert.Equal(t, lines, readLogLines(t, svc, ctx, GetOptions{LogNames: []string{logName}}))
+		},
+		"ReplacesChunksFromInterruptedAttempt": func(t *testing.T, svc *logServiceV0) {
+			lines := appendLines(t, svc, 5, 5)
+
+			_, err := svc.Compact(ctx, CompactOptions{LogNames: []string{logName}, Generation: 1, MaxChunkSize: 100})
+			require.NoError(t, err)
+			res, err := svc.Compact(ctx, CompactOptions{LogNames: []string{logName}, Generation: 1})
+			require.NoError(t, err)
+			assert.Equal(t, 1, res.ChunksWritten)
+			_, err = svc.RemoveOlderGenerations(ctx, []string{logName}, 1)
+			require.NoError(t, err)
+			assert.Equal(t, 1, countChunks(t, svc))
+			assert.Equal(t, lines, readLogLines(t, svc, ctx, GetOptions{LogNames: []string{logName}}))
+		},
+		"GetIgnoresOlderGenerationsBeforeTheyAreRemoved": func(t *testing.T, svc *logServiceV0) {
+			lines := appendLines(t, svc, 5, 5)
+
+			_, err := svc.Compact(ctx, CompactOptions{LogNames: []string{logName}, Generation: 1, MaxChunkSize: 100})
+			require.NoError(t, err)
+			assert.Equal(t, lines, readLogLines(t, svc, ctx, GetOptions{LogNames: []string{logName}}), "lines should not be duplicated")
+
+			_, err = svc.Compact(ctx, CompactOptions{LogNames: []string{logName}, Generation: 2, TailN: 2})
+			require.NoError(t, err)
+			assert.Equal(t, lines[len(lines)-2:], readLogLines(t, svc, ctx, GetOptions{LogNames: []string{logName}}), "only the latest generation should be read")
+		},
+		"GetIgnoresInterruptedGenerations": func(t *testing.T, svc *logServiceV0) {
+			lines := appendLines(t, svc, 5, 5)
+
+			_, err := svc.Compact(ctx, CompactOptions{LogNames: []string{logName}, Generation: 1, MaxChunkSize: 100})
+			require.NoError(t, err)
+			keys, err := svc.GetChunkKeys(ctx, []string{logName})
+			require.NoError(t, err)
+			var lastKey string
+			for _, key := range keys {
+				chunk, err := svc.parseChunkKey(logName, key[len(logName)+1:])
+				require.NoError(t, err)
+				if isLastGenerationChunk(chunk) {
+					lastKey = key
+				}
+			}
+			require.NotEmpty(t, lastKey)
+			require.NoError(t, svc.bucket.Remove(ctx, lastKey))
+
+			assert.Equal(t, lines, readLogLines(t, svc, ctx, GetOptions{LogNames: []string{logName}}), "incomplete generations should not be read")
+			removed, err := svc.RemoveOlderGenerations(ctx, []string{logName}, 1)
+			require.NoError(t, err)
+			assert.Zero(t, removed, "chunks should not be removed for incomplete generations")
+
+			res, err := svc.Compact(ctx, CompactOptions{LogNames: []string{logName}, Generation: 1})
+			require.NoError(t, err)
+			assert.Equal(t, 1, res.LogsCompacted)
+			_, err = svc.RemoveOlderGenerations(ctx, []string{logName}, 1)
+			require.NoError(t, err)
+			assert.Equal(t, 1, countChunks(t, svc))
+			assert.Equal(t, lines, readLogLines(t, svc, ctx, GetOptions{LogNames: []string{logName}}))
+		},
+		"LaterGenerationsReadEarlierOnes": func(t *testing.T, svc *logServiceV0) {
+			lines := appendLines(t, svc, 5, 5)
+
+			_, err := svc.Compact(ctx, CompactOptions{LogNames: []string{logName}, Generation: 1})
+			require.NoError(t, err)
+			_, err = svc.RemoveOlderGenerations(ctx, []string{logName}, 1)
+			require.NoError(t, err)
+			_, err = svc.Compact(ctx, CompactOptions{LogNames: []string{logName}, Generation: 2, TailN: 2})
+			require.NoError(t, err)
+			_, err = svc.RemoveOlderGenerations(ctx, []string{logName}, 2)
+			require.NoError(t, err)
+			assert.Equal(t, lines[len(lines)-2:], readLogLines(t, svc, ctx, GetOptions{LogNames: []string{logName}}))
+
+			res, err := svc.Compact(ctx, CompactOptions{LogNames: []string{logName}, Generation: 1})
+			require.NoError(t, err)
+			assert.Zero(t, res.LogsCompacted, "earlier generations should not compact later ones")
+			_, err = svc.RemoveOlderGenerations(ctx, []string{logName}, 1)
+			require.NoError(t, err)
+			assert.Equal(t, lines[len(lines)-2:], readLogLines(t, svc, ctx, GetOptions{LogNames: []string{logName}}))
+		},
+		"SkipsLogsOfEarlierExecutionsCompactedByLaterGenerations": func(t *testing.T, svc *logServiceV0) {
+			lines := appendLines(t, svc, 5, 5)
+			_, err := svc.Compact(ctx, CompactOptions{LogNames: []string{logName}, Generation: 2, TailN: 2})
+			require.NoError(t, err)
+			_, err = svc.RemoveOlderGenerations(ctx, []string{logName}, 2)
+			require.NoError(t, err)
+
+			const restartedLogName = "project/task/1/task_logs/task"
+			restartedLines := []LogLine{
+				{LogName: restartedLogName, Priority: level.Info, Timestamp: time.Now().UnixNano(), Data: "restarted 0"},
+				{LogName: restartedLogName, Priority: level.Info, Timestamp: time.Now().UnixNano() + 1, Data: "restarted 1"},
+			}
+			for _, line := range restartedLines {
+				require.NoError(t, ignoreBytes(svc.Append(ctx, restartedLogName, 0, []LogLine{line})))
+			}
+
+			res, err := svc.Compact(ctx, CompactOptions{LogNames: []string{"project/task/"}, Generation: 1})
+			require.NoError(t, err)
+			assert.Equal(t, 1, res.LogsCompacted)
+			_, err = svc.RemoveOlderGenerations(ctx, []string{"project/task/"}, 1)
+			require.NoError(t, err)
+			assert.Equal(t, lines[len(lines)-2:], readLogLines(t, svc, ctx, GetOptions{LogNames: []string{logName}}))
+			assert.Equal(t, restartedLines, readLogLines(t, svc, ctx, GetOptions{LogNames: []string{restartedLogName}}))
+		},
+		"DeleteRemovesEveryChunk": func(t *testing.T, svc *logServiceV0) {
+			appendLines(t, svc, 5, 5)
+
+			removed, err := svc.Delete(ctx, []string{logName})
+			require.NoError(t, err)
+			assert.Equal(t, 5, removed)
+			assert.Zero(t, countChunks(t, svc))
+		},
+		"FailsWithInvalidOptions": func(t *testing.T, svc *logServiceV0) {
+			_, err := svc.Compact(ctx, CompactOptions{LogNames: []string{logName}})
+			assert.Error(t, err)
+			_, err = svc.Compact(ctx, CompactOptions{Generation: 1})
+			assert.Error(t, err)
+		},
+	} {
+		t.Run(tName, func(t *testing.T) {
+			tCase(t, newService(t))
+		})
+	}
+}
+
+func TestChunkGeneration(t *testing.T) {
+	assert.Equal(t, 0, chunkGeneration(chunkInfo{sequence: 42}))
+	assert.Equal(t, 1, chunkGeneration(chunkInfo{sequence: generationSequenceOffset}))
+	assert.Equal(t, 2, chunkGeneration(chunkInfo{sequence: 2*generationSequenceOffset + 3}))
+
+	svc := &logServiceV0{}
+	chunk, err := svc.parseChunkKey("log", svc.createChunkKey(generationSequenceOffset+1, 1, 2, 3))
+	require.NoError(t, err)
+	assert.Equal(t, 1, chunkGeneration(chunk))
+}
@@ -2,6 +2,7 @@ package log
 
 import (
 	"context"
+	"fmt"
 	"math/rand"
 	"sort"
 	"strings"
@@ -543,3 +544,56 @@ func newRandCharSetString(length int) string {
 	}
 	return string(b)
 }
+
+func TestSearchIterator(t *testing.T) {
+	lines := make([]LogLine, 20)
+	for i := range lines {
+		lines[i] = LogLine{Priority: level.Info, Timestamp: int64(i), Data: fmt.Sprintf("line %d", i)}
+	}
+	lines[3].Priority = level.Debug
+	lines[5].Data = "match"
+	lines[7].Data = "match"
+	lines[15].Data = "match"
+
+	read := func(t *testing.T, opts SearchOptions, lineLimit int) []int64 {
+		it, err := newSearchIterator(newBasicIterator(lines), opts, lineLimit)
+		require.NoError(t, err)
+
+		var timestamps []int64
+		for it.Next() {
+			item := it.Item()
+			assert.Equal(t, item.Data == "match", item.Matched)
+			timestamps = append(timestamps, item.Timestamp)
+		}
+		require.NoError(t, it.Err())
+		assert.True(t, it.Exhausted())
+		assert.NoError(t, it.Close())
+
+		return timestamps
+	}
+
+	t.Run("MatchesOnly", func(t *testing.T) {
+		assert.Equal(t, []int64{5, 7, 15}, read(t, SearchOptions{Pattern: "match"}, 0))
+	})
+	t.Run("ContextLinesDoNotRepeat", func(t *testing.T) {
+		assert.Equal(t, []int64{2, 3, 4, 5, 6, 7, 8, 9, 10, 12, 13, 14, 15, 16, 17, 18}, read(t, SearchOptions{Pattern: "match", ContextLines: 3}, 0))
+	})
+	t.Run("ContextLinesRespectMinPriority", func(t *testing.T) {
+		assert.Equal(t, []int64{1, 2, 4, 5, 6, 7, 8, 9}, read(t, SearchOptions{Pattern: "match", ContextLines: 3, MinPriority: level.Info}, 0)[:8])
+	})
+	t.Run("LineLimitIncludesContextLines", func(t *testing.T) {
+		assert.Equal(t, []int64{4, 5, 6}, read(t, SearchOptions{Pattern: "match", ContextLines: 1}, 3))
+	})
+	t.Run("InvalidOptions", func(t *testing.T) {
+		for name, opts := range map[string]SearchOptions{
+			"EmptyPattern":        {},
+			"InvalidRegex":        {Pattern: "[", Regex: true},
+			"TooManyContextLines": {Pattern: "match", ContextLines: maxSearchContextLines + 1},
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, err := newSearchIterator(newBasicIterator(lines), opts, 0)
+				assert.Error(t, err)
+			})
+		}
+	})
+}
@@ -10,4 +10,8 @@ type LogLine struct {
 	Priority  level.Priority
 	Timestamp int64
 	Data      string
+	// Matched is true if the line matched the search of the request that
+	// returned it. Context lines returned alongside matches leave it
+	// unset.
+	Matched bool
 }
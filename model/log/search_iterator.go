@@ -0,0 +1,155 @@
+package log
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/mongodb/grip/level"
+	"github.com/pkg/errors"
+)
+
+// maxSearchContextLines is the maximum number of context lines that may be
+// requested around each search match.
+const maxSearchContextLines = 100
+
+// SearchOptions represents the arguments for searching Evergreen logs.
+type SearchOptions struct {
+	// Pattern is the substring, or regular expression if Regex is set, to
+	// match against the data of each log line. Must be specified.
+	Pattern string
+	// Regex interprets Pattern as an RE2 regular expression.
+	Regex bool
+	// CaseInsensitive matches Pattern regardless of case.
+	CaseInsensitive bool
+	// MinPriority is the priority floor of the search. Lines below this
+	// priority are neither matched nor returned as context.
+	MinPriority level.Priority
+	// ContextLines is the number of lines to return before and after
+	// each matching line. Ignored if less than or equal to 0.
+	ContextLines int
+}
+
+// Validate checks that the search options are valid.
+func (o SearchOptions) Validate() error {
+	_, err := o.matcher()
+	return err
+}
+
+func (o SearchOptions) matcher() (func(string) bool, error) {
+	if o.Pattern == "" {
+		return nil, errors.New("search pattern must be specified")
+	}
+	if o.ContextLines > maxSearchContextLines {
+		return nil, errors.Errorf("cannot request more than %d context lines", maxSearchContextLines)
+	}
+
+	if o.Regex {
+		pattern := o.Pattern
+		if o.CaseInsensitive {
+			pattern = "(?i)" + pattern
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, errors.Wrap(err, "compiling search pattern")
+		}
+
+		return re.MatchString, nil
+	}
+	if o.CaseInsensitive {
+		pattern := strings.ToLower(o.Pattern)
+		return func(data string) bool { return strings.Contains(strings.ToLower(data), pattern) }, nil
+	}
+
+	return func(data string) bool { return strings.Contains(data, o.Pattern) }, nil
+}
+
+// searchIterator filters the lines of a log iterator to those matching a
+// search, along with their surrounding context lines. Only the context lines
+// preceding the next match are buffered, so the underlying log is never read
+// into memory.
+type searchIterator struct {
+	it        LogIterator
+	match     func(string) bool
+	opts      SearchOptions
+	lineLimit int
+
+	before    []LogLine
+	pending   []LogLine
+	after     int
+	count     int
+	item      LogLine
+	exhausted bool
+	closed    bool
+}
+
+// newSearchIterator returns a LogIterator that only returns the lines of the
+// given iterator matching the search options. If lineLimit is greater than
+// 0, at most lineLimit lines, including context lines, are returned.
+func newSearchIterator(it LogIterator, opts SearchOptions, lineLimit int) (*searchIterator, error) {
+	match, err := opts.matcher()
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid search options")
+	}
+
+	return &searchIterator{
+		it:        it,
+		match:     match,
+		opts:      opts,
+		lineLimit: lineLimit,
+	}, nil
+}
+
+func (i *searchIterator) Next() bool {
+	if i.exhausted || i.closed {
+		return false
+	}
+	if i.lineLimit > 0 && i.count >= i.lineLimit {
+		i.exhausted = true
+		return false
+	}
+
+	for len(i.pending) == 0 {
+		if !i.it.Next() {
+			i.exhausted = true
+			return false
+		}
+
+		line := i.it.Item()
+		switch {
+		case line.Priority < i.opts.MinPriority:
+			continue
+		case i.match(line.Data):
+			line.Matched = true
+			i.pending = append(append(i.pending, i.before...), line)
+			i.before = i.before[:0]
+			i.after = i.opts.ContextLines
+		case i.after > 0:
+			i.pending = append(i.pending, line)
+			i.after--
+		case i.opts.ContextLines > 0:
+			if len(i.before) == i.opts.ContextLines {
+				copy(i.before, i.before[1:])
+				i.before = i.before[:len(i.before)-1]
+			}
+			i.before = append(i.before, line)
+		}
+	}
+
+	i.item = i.pending[0]
+	i.pending = i.pending[1:]
+	i.count++
+
+	return true
+}
+
+func (i *searchIterator) Item() LogLine { return i.item }
+
+func (i *searchIterator) Exhausted() bool { return i.exhausted }
+
+func (i *searchIterator) Err() error { return i.it.Err() }
+
+func (i *searchIterator) Close() error {
+	i.closed = true
+
+	return i.it.Close()
+}
@@ -44,6 +44,10 @@ type GetOptions struct {
 	// TailN is the number of lines to read from the tail of the log.
 	// Ignored if less than or equal to 0.
 	TailN int
+	// Search, if set, filters the log lines to those matching the search
+	// and their context lines. LineLimit and TailN then apply to the lines
+	// returned by the search rather than the lines read from the log.
+	Search *SearchOptions
 }
 
 // LineParser functions parse a raw log line into the service representation of
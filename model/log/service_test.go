@@ -361,12 +361,82 @@ func TestLogService(t *testing.T) {
 							lines2[2],
 						},
 					},
+					{
+						name: "SearchSubstring",
+						opts: GetOptions{
+							LogNames: []string{log0, "common"},
+							Search:   &SearchOptions{Pattern: "common prefix"},
+						},
+						expectedLines: []LogLine{
+							matched(lines1[0]),
+							matched(lines2[0]),
+						},
+					},
+					{
+						name: "SearchRegexAboveMinPriority",
+						opts: GetOptions{
+							LogNames: []string{log0, "common"},
+							Search: &SearchOptions{
+								Pattern:         "^another",
+								Regex:           true,
+								CaseInsensitive: true,
+								MinPriority:     level.Info,
+							},
+						},
+						expectedLines: []LogLine{
+							matched(lines0[1]),
+							matched(lines1[1]),
+						},
+					},
+					{
+						name: "SearchWithContextLines",
+						opts: GetOptions{
+							LogNames: []string{log0, "common"},
+							Search: &SearchOptions{
+								Pattern:      "same timestamp",
+								ContextLines: 1,
+							},
+						},
+						expectedLines: []LogLine{
+							lines2[0],
+							matched(lines2[1]),
+							lines0[1],
+						},
+					},
+					{
+						name: "SearchWithLineLimit",
+						opts: GetOptions{
+							LogNames:  []string{log0, "common"},
+							LineLimit: 2,
+							Search:    &SearchOptions{Pattern: "line"},
+						},
+						expectedLines: []LogLine{
+							matched(lines2[1]),
+							matched(lines0[1]),
+						},
+					},
+					{
+						name: "SearchWithTailN",
+						opts: GetOptions{
+							LogNames: []string{log2},
+							TailN:    1,
+							Search:   &SearchOptions{Pattern: "prefix"},
+						},
+						expectedLines: []LogLine{matched(lines2[0])},
+					},
 				} {
 					t.Run(test.name, func(t *testing.T) {
 						actualLines := readLogLines(t, svc, ctx, test.opts)
 						assert.Equal(t, test.expectedLines, actualLines)
 					})
 				}
+				t.Run("InvalidSearch", func(t *testing.T) {
+					_, err := svc.Get(ctx, GetOptions{
+						LogNames: []string{log0},
+						Search:   &SearchOptions{Pattern: "(", Regex: true},
+					})
+					assert.Error(t, err)
+				})
 			})
 		})
 	}
@@ -387,4 +457,9 @@ func readLogLines(t *testing.T, svc LogService, ctx context.Context, opts GetOpt
 
 }
 
+func matched(line LogLine) LogLine {
+	line.Matched = true
+	return line
+}
+
 func ignoreBytes(_ int64, _ int, err error) error { return err }
@@ -1,7 +1,6 @@
 package log
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"sort"
@@ -26,6 +25,12 @@ func NewLogServiceV0(bucket pail.Bucket) *logServiceV0 {
 }
 
 func (s *logServiceV0) Get(ctx context.Context, getOpts GetOptions) (LogIterator, error) {
+	if getOpts.Search != nil {
+		if err := getOpts.Search.Validate(); err != nil {
+			return nil, errors.Wrap(err, "invalid search options")
+		}
+	}
+
 	allLogChunks, firstStart, firstEnd, err := s.getLogChunks(ctx, getOpts.LogNames)
 	if err != nil {
 		return nil, errors.Wrap(err, "getting log chunks")
@@ -41,6 +46,13 @@ func (s *logServiceV0) Get(ctx context.Context, getOpts GetOptions) (LogIterator
 		}
 	}
 
+	// Searches apply the line limit and tail to the lines they return, so
+	// the underlying iterators must read the whole time range.
+	lineLimit, tailN := getOpts.LineLimit, getOpts.TailN
+	if getOpts.Search != nil {
+		lineLimit, tailN = 0, 0
+	}
+
 	var its []LogIterator
 	for _, chunks := range allLogChunks {
 		its = append(its, newChunkIterator(ctx, chunkIteratorOptions{
@@ -49,16 +61,30 @@ func (s *logServiceV0) Get(ctx context.Context, getOpts GetOptions) (LogIterator
 			parser:    s.getParser(chunks.name),
 			start:     start,
 			end:       end,
-			lineLimit: getOpts.LineLimit,
-			tailN:     getOpts.TailN,
+			lineLimit: lineLimit,
+			tailN:     tailN,
 		}))
 	}
 
+	var it LogIterator
 	if len(its) == 1 {
-		return its[0], nil
+		if getOpts.Search == nil {
+			return its[0], nil
+		}
+		it = its[0]
+	} else {
+		it = newMergingIterator(lineLimit, its...)
 	}
 
-	it := newMergingIterator(getOpts.LineLimit, its...)
+	if getOpts.Search != nil {
+		searchLimit := getOpts.LineLimit
+		if getOpts.TailN > 0 {
+			searchLimit = 0
+		}
+		if it, err = newSearchIterator(it, *getOpts.Search, searchLimit); err != nil {
+			return nil, err
+		}
+	}
 	if getOpts.TailN > 0 {
 		return newTailIterator(it, getOpts.TailN)
 	}
@@ -76,25 +102,47 @@ func (s *logServiceV0) Append(ctx context.Context, logName string, sequence int,
 	}
 
 	key := fmt.Sprintf("%s/%s", logName, s.createChunkKey(sequence, lines[0].Timestamp, lines[len(lines)-1].Timestamp, len(lines)))
-	// S3 buckets report post-compression bytes; non-S3 fall through to plain Put and incur no S3 cost.
-	var puts int
-	var uploadedBytes int64
-	var err error
-	if pc, ok := s.bucket.(pail.StreamPutCounterWithBytes); ok {
-		puts, uploadedBytes, err = pc.PutWithCountAndBytes(ctx, key, bytes.NewReader(rawLines))
-	} else {
-		err = s.bucket.Put(ctx, key, bytes.NewReader(rawLines))
-	}
+	uploadedBytes, puts, err := s.putChunk(ctx, key, rawLines)
 	if err != nil {
-		return 0, puts, errors.Wrap(err, "writing log chunk to bucket")
+		return 0, puts, err
 	}
 
 	return uploadedBytes, puts, nil
 }
 
-// getLogChunks maps each logical log to its chunk files stored in pail-backed
-// bucket storage for the given prefix.
+// getLogChunks maps each logical log to the chunk files to read from
+// pail-backed bucket storage for the given prefix. Only the chunks of the
+// latest complete compaction generation of each log are returned so that
+// readers never see a log both before and after it was compacted, or a
+// compaction that has not finished writing.
 func (s *logServiceV0) getLogChunks(ctx context.Context, logNames []string) ([]chunkGroup, int64, int64, error) {
+	chunkGroups, err := s.listLogChunks(ctx, logNames)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	var start, end int64
+	for i, group := range chunkGroups {
+		chunks := latestGenerationChunks(group.chunks)
+		chunkGroups[i].chunks = chunks
+		// Find the first specified log's time range.
+		if len(chunks) > 0 && strings.HasPrefix(group.name, logNames[0]) {
+			if start == 0 || (start > 0 && start > chunks[0].start) {
+				start = chunks[0].start
+			}
+			if end < chunks[len(chunks)-1].end {
+				end = chunks[len(chunks)-1].end
+			}
+		}
+	}
+
+	return chunkGroups, start, end, nil
+}
+
+// listLogChunks maps each logical log to all of its chunk files, of every
+// compaction generation, stored in pail-backed bucket storage for the given
+// prefix. The chunks of each log are sorted in the order they are read.
+func (s *logServiceV0) listLogChunks(ctx context.Context, logNames []string) ([]chunkGroup, error) {
 	// To reduce potentially expensive list calls, use the LCP of the
 	// given log names when calling `bucket.List`. Key names that do not
 	// have one of the log names as a prefix will get filtered out.
@@ -111,7 +159,7 @@ func (s *logServiceV0) getLogChunks(ctx context.Context, logNames []string) ([]c
 
 	it, err := s.bucket.List(ctx, prefix)
 	if err != nil {
-		return nil, 0, 0, errors.Wrap(err, "listing log chunks")
+		return nil, errors.Wrap(err, "listing log chunks")
 	}
 
 	var orderedLogNames []string
@@ -133,7 +181,7 @@ func (s *logServiceV0) getLogChunks(ctx context.Context, logNames []string) ([]c
 
 		chunk, err := s.parseChunkKey(logName, chunkKey)
 		if err != nil {
-			return nil, 0, 0, errors.Wrapf(err, "parsing chunk key '%s'", chunkKey)
+			return nil, errors.Wrapf(err, "parsing chunk key '%s'", chunkKey)
 		}
 
 		if _, ok := logChunks[logName]; !ok {
@@ -142,13 +190,11 @@ func (s *logServiceV0) getLogChunks(ctx context.Context, logNames []string) ([]c
 		logChunks[logName] = append(logChunks[logName], chunk)
 	}
 	if err = it.Err(); err != nil {
-		return nil, 0, 0, errors.Wrap(err, "iterating log chunks")
+		return nil, errors.Wrap(err, "iterating log chunks")
 	}
 
-	var start, end int64
-	for name, chunks := range logChunks {
-		// Sort each set of chunks by start order for log iterating and
-		// find the first specified log's time range.
+	for _, chunks := range logChunks {
+		// Sort each set of chunks by start order for log iterating.
 		sort.Slice(chunks, func(i, j int) bool {
 			switch {
 			case chunks[i].sequence != chunks[j].sequence:
@@ -159,14 +205,6 @@ func (s *logServiceV0) getLogChunks(ctx context.Context, logNames []string) ([]c
 				return chunks[i].upload < chunks[j].upload
 			}
 		})
-		if strings.HasPrefix(name, logNames[0]) {
-			if start == 0 || (start > 0 && start > chunks[0].start) {
-				start = chunks[0].start
-			}
-			if end < chunks[len(chunks)-1].end {
-				end = chunks[len(chunks)-1].end
-			}
-		}
 	}
 
 	// Preserve the order that pail returns the log names to ensure a
@@ -179,7 +217,7 @@ func (s *logServiceV0) getLogChunks(ctx context.Context, logNames []string) ([]c
 		})
 	}
 
-	return chunkGroups, start, end, nil
+	return chunkGroups, nil
 }
 
 // createChunkKey returns a pail-backed bucket storage key that encodes the
@@ -280,7 +318,7 @@ func (s *logServiceV0) getParser(logName string) LineParser {
 
 // GetChunkKeys returns all log chunk keys for the given log names.
 func (s *logServiceV0) GetChunkKeys(ctx context.Context, logNames []string) ([]string, error) {
-	chunkGroups, _, _, err := s.getLogChunks(ctx, logNames)
+	chunkGroups, err := s.listLogChunks(ctx, logNames)
 	if err != nil {
 		return nil, err
 	}
@@ -172,3 +172,52 @@ func TestDepsMatrixIntegration(t *testing.T) {
 		})
 	})
 }
+
+func TestRulesMatrixIntegration(t *testing.T) {
+	Convey("With a sample matrix project using exclude and include rules", t, func() {
+		p := Project{}
+		bytes, err := os.ReadFile(filepath.Join(testutil.GetDirectoryOfFile(),
+			"testdata", "matrix_rules.yml"))
+		So(err, ShouldBeNil)
+		Convey("the project should parse properly", func() {
+			ctx := context.Background()
+			pp, err := LoadProjectInto(ctx, bytes, nil, "rules", &p)
+			So(err, ShouldBeNil)
+			Convey("and contain the correct variants", func() {
+				So(len(p.BuildVariants), ShouldEqual, 3+6)
+				Convey("excluding cells matching a rule", func() {
+					So(findRegularVariant(p.BuildVariants, "test__os~windows_arch~arm64_client_os~windows"), ShouldBeNil)
+					So(findRegularVariant(p.BuildVariants, "test__os~macos_arch~x86_64_client_os~ubuntu"), ShouldBeNil)
+				})
+				Convey("including cells matching an include rule", func() {
+					v := findRegularVariant(p.BuildVariants, "test__os~ubuntu_arch~x86_64_client_os~windows")
+					So(v, ShouldNotBeNil)
+					So(v.DisplayName, ShouldEqual, "Test ubuntu x86_64 with windows client")
+				})
+				Convey("with dependencies on the variant with the same OS", func() {
+					v := findRegularVariant(p.BuildVariants, "test__os~macos_arch~arm64_client_os~macos")
+					So(v, ShouldNotBeNil)
+					So(len(v.Tasks), ShouldEqual, 1)
+					So(v.Tasks[0].DependsOn, ShouldResemble, []TaskUnitDependency{{
+						Name:    "compile",
+						Variant: "compile__os~macos_arch~x86_64",
+					}})
+				})
+			})
+			Convey("and expand its matrices", func() {
+				expansions, err := pp.ExpandMatrices()
+				So(err, ShouldBeNil)
+				So(len(expansions), ShouldEqual, 2)
+				So(expansions[0].Name, ShouldEqual, "compile")
+				So(len(expansions[0].Variants), ShouldEqual, 3)
+				So(len(expansions[0].Excluded), ShouldEqual, 0)
+				So(expansions[1].Name, ShouldEqual, "test")
+				So(len(expansions[1].Variants), ShouldEqual, 6)
+				So(len(expansions[1].Excluded), ShouldEqual, 3*2*3-6)
+				for _, v := range expansions[1].Variants {
+					So(v.Included, ShouldEqual, v.Name == "test__os~ubuntu_arch~x86_64_client_os~windows")
+				}
+			})
+		})
+	})
+}
@@ -26,8 +26,24 @@ var (
 	subscriberKey = bsonutil.MustHaveTag(Notification{}, "Subscriber")
 	sentAtKey     = bsonutil.MustHaveTag(Notification{}, "SentAt")
 	errorKey      = bsonutil.MustHaveTag(Notification{}, "Error")
+
+	subscriptionIDKey   = bsonutil.MustHaveTag(Notification{}, "SubscriptionID")
+	deliveryStatusKey   = bsonutil.MustHaveTag(Notification{}, "DeliveryStatus")
+	deliveryAttemptsKey = bsonutil.MustHaveTag(Notification{}, "DeliveryAttempts")
+	nextAttemptAtKey    = bsonutil.MustHaveTag(Notification{}, "NextAttemptAt")
+
+	subscriberTypeKey      = bsonutil.GetDottedKeyName(subscriberKey, "type")
+	subscriberTargetURLKey = bsonutil.GetDottedKeyName(subscriberKey, "target", "url")
 )
 
+// SentToURLIndex is the index used by CountSentToURLSince to rate limit the
+// notifications sent to a chat webhook URL without scanning the collection.
+var SentToURLIndex = bson.D{
+	{Key: subscriberTypeKey, Value: 1},
+	{Key: subscriberTargetURLKey, Value: 1},
+	{Key: sentAtKey, Value: 1},
+}
+
 type unmarshalNotification struct {
 	ID         string           `bson:"_id"`
 	Subscriber event.Subscriber `bson:"subscriber"`
@@ -36,6 +52,11 @@ type unmarshalNotification struct {
 	SentAt   time.Time            `bson:"sent_at,omitempty"`
 	Error    string               `bson:"error,omitempty"`
 	Metadata NotificationMetadata `bson:"metadata,omitempty"`
+
+	SubscriptionID   string         `bson:"subscription_id,omitempty"`
+	DeliveryStatus   DeliveryStatus `bson:"delivery_status,omitempty"`
+	DeliveryAttempts int            `bson:"delivery_attempts,omitempty"`
+	NextAttemptAt    time.Time      `bson:"next_attempt_at,omitempty"`
 }
 
 func (d *Notification) UnmarshalBSON(in []byte) error {
@@ -65,6 +86,12 @@ func (n *Notification) SetBSON(raw mgobson.Raw) error {
 	case event.SlackSubscriberType:
 		n.Payload = &SlackPayload{}
 
+	case event.TeamsSubscriberType:
+		n.Payload = &TeamsPayload{}
+
+	case event.MattermostSubscriberType:
+		n.Payload = &MattermostPayload{}
+
 	case event.GithubPullRequestSubscriberType, event.GithubCheckSubscriberType, event.GithubMergeSubscriberType:
 		n.Payload = &message.GithubStatus{}
 
@@ -81,6 +108,10 @@ func (n *Notification) SetBSON(raw mgobson.Raw) error {
 	n.SentAt = temp.SentAt
 	n.Error = temp.Error
 	n.Metadata = temp.Metadata
+	n.SubscriptionID = temp.SubscriptionID
+	n.DeliveryStatus = temp.DeliveryStatus
+	n.DeliveryAttempts = temp.DeliveryAttempts
+	n.NextAttemptAt = temp.NextAttemptAt
 
 	return nil
 }
@@ -122,13 +153,34 @@ func FindByEventID(ctx context.Context, id string) ([]Notification, error) {
 	return notifications, err
 }
 
+// FindUnprocessed returns the notifications that haven't been sent, excluding
+// webhook deliveries that are waiting to be retried.
 func FindUnprocessed(ctx context.Context) ([]Notification, error) {
 	notifications := []Notification{}
-	err := db.FindAllQ(ctx, Collection, db.Query(bson.M{sentAtKey: bson.M{"$exists": false}}), &notifications)
+	err := db.FindAllQ(ctx, Collection, db.Query(bson.M{
+		sentAtKey: bson.M{"$exists": false},
+		"$or": []bson.M{
+			{nextAttemptAtKey: bson.M{"$exists": false}},
+			{nextAttemptAtKey: bson.M{"$lte": time.Now()}},
+		},
+	}), &notifications)
 
 	return notifications, errors.Wrap(err, "finding unprocessed notifications")
 }
 
+// CountSentToURLSince returns the number of notifications of the given
+// subscriber type that were sent to the target URL since the given time. It
+// runs for every chat webhook notification sent, so it relies on
+// SentToURLIndex.
+func CountSentToURLSince(ctx context.Context, subscriberType, url string, since time.Time) (int, error) {
+	count, err := db.Count(ctx, Collection, bson.M{
+		subscriberTypeKey:      subscriberType,
+		subscriberTargetURLKey: url,
+		sentAtKey:              bson.M{"$gte": since},
+	})
+	return count, errors.Wrap(err, "counting sent notifications")
+}
+
 func byID(id string) db.Q {
 	return db.Query(bson.M{
 		idKey: id,
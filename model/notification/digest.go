@@ -0,0 +1,212 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/mongodb/anser/bsonutil"
+	adb "github.com/mongodb/anser/db"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	DigestsCollection = "notification_digests"
+
+	// maxDigestItems is the maximum number of items a digest keeps. Once
+	// reached, the oldest items are dropped but still counted.
+	maxDigestItems = 100
+)
+
+var (
+	digestIDKey         = bsonutil.MustHaveTag(Digest{}, "ID")
+	digestSubscriberKey = bsonutil.MustHaveTag(Digest{}, "Subscriber")
+	digestModeKey       = bsonutil.MustHaveTag(Digest{}, "Mode")
+	digestVersionIDKey  = bsonutil.MustHaveTag(Digest{}, "VersionID")
+	digestItemsKey      = bsonutil.MustHaveTag(Digest{}, "Items")
+	digestTotalKey      = bsonutil.MustHaveTag(Digest{}, "Total")
+	digestCreatedAtKey  = bsonutil.MustHaveTag(Digest{}, "CreatedAt")
+	digestFlushAtKey    = bsonutil.MustHaveTag(Digest{}, "FlushAt")
+)
+
+// DigestItem is a single event collected into a digest.
+type DigestItem struct {
+	EventID        string    `bson:"event_id" json:"event_id"`
+	SubscriptionID string    `bson:"subscription_id" json:"subscription_id"`
+	Trigger        string    `bson:"trigger" json:"trigger"`
+	Object         string    `bson:"object" json:"object"`
+	ID             string    `bson:"id" json:"id"`
+	DisplayName    string    `bson:"display_name" json:"display_name"`
+	Project        string    `bson:"project" json:"project"`
+	VersionID      string    `bson:"version_id,omitempty" json:"version_id,omitempty"`
+	BuildVariant   string    `bson:"build_variant,omitempty" json:"build_variant,omitempty"`
+	Status         string    `bson:"status" json:"status"`
+	Failed         bool      `bson:"failed" json:"failed"`
+	URL            string    `bson:"url" json:"url"`
+	FailedTests    []string  `bson:"failed_tests,omitempty" json:"failed_tests,omitempty"`
+	Time           time.Time `bson:"time" json:"time"`
+}
+
+// Digest collects the notifications for a subscriber until it is flushed
+// into a single summary notification.
+type Digest struct {
+	ID         string           `bson:"_id"`
+	Subscriber event.Subscriber `bson:"subscriber"`
+	Mode       event.DigestMode `bson:"mode"`
+	VersionID  string           `bson:"version_id,omitempty"`
+	Items      []DigestItem     `bson:"items"`
+	// Total is the number of items ever added to the digest, which can
+	// exceed the number of stored items.
+	Total     int       `bson:"total"`
+	CreatedAt time.Time `bson:"created_at"`
+	FlushAt   time.Time `bson:"flush_at"`
+}
+
+// makeDigestID returns the ID of the pending digest that an item for the given
+// subscription belongs to.
+func makeDigestID(sub *event.Subscription, versionID string) string {
+	if sub.Digest.Mode == event.DigestModeVersion {
+		return fmt.Sprintf("%s-%s-%s", sub.Subscriber.String(), sub.Digest.Mode, versionID)
+	}
+	return fmt.Sprintf("%s-%s", sub.Subscriber.String(), sub.Digest.Mode)
+}
+
+// AddToDigest adds the item to the subscription's pending digest, starting a
+// new digest if there isn't one.
+func AddToDigest(ctx context.Context, sub *event.Subscription, item DigestItem) error {
+	if !sub.Digest.IsEnabled() {
+		return errors.Errorf("subscription '%s' does not have digests enabled", sub.ID)
+	}
+	if sub.Digest.Mode == event.DigestModeVersion && item.VersionID == "" {
+		return errors.Errorf("cannot add item for %s '%s' to version digest without a version", item.Object, item.ID)
+	}
+	versionID := ""
+	if sub.Digest.Mode == event.DigestModeVersion {
+		versionID = item.VersionID
+	}
+
+	now := time.Now()
+	_, err := db.Upsert(ctx, DigestsCollection, bson.M{
+		digestIDKey: makeDigestID(sub, versionID),
+	}, bson.M{
+		"$setOnInsert": bson.M{
+			digestSubscriberKey: sub.Subscriber,
+			digestModeKey:       sub.Digest.Mode,
+			digestVersionIDKey:  versionID,
+			digestCreatedAtKey:  now,
+			digestFlushAtKey:    now.Add(sub.Digest.Window()),
+		},
+		"$push": bson.M{
+			digestItemsKey: bson.M{
+				"$each":  []DigestItem{item},
+				"$slice": -maxDigestItems,
+			},
+		},
+		"$inc": bson.M{digestTotalKey: 1},
+	})
+	return errors.Wrapf(err, "adding item to digest for subscription '%s'", sub.ID)
+}
+
+// FindDigestIDsToFlush returns the IDs of the digests whose windows have
+// closed as of the given time.
+func FindDigestIDsToFlush(ctx context.Context, now time.Time) ([]string, error) {
+	digests := []Digest{}
+	q := db.Query(bson.M{
+		digestFlushAtKey: bson.M{"$lte": now},
+	}).WithFields(digestIDKey)
+	if err := db.FindAllQ(ctx, DigestsCollection, q, &digests); err != nil {
+		return nil, errors.Wrap(err, "finding digests to flush")
+	}
+
+	ids := make([]string, 0, len(digests))
+	for _, d := range digests {
+		ids = append(ids, d.ID)
+	}
+	return ids, nil
+}
+
+// ClaimDigest atomically removes the digest with the given ID and returns it,
+// so that items added afterwards start a new digest. It returns nil if the
+// digest has already been claimed.
+func ClaimDigest(ctx context.Context, id string) (*Digest, error) {
+	d := &Digest{}
+	_, err := db.FindAndModify(ctx, DigestsCollection, bson.M{digestIDKey: id}, nil, adb.Change{Remove: true}, d)
+	if adb.ResultsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "claiming digest '%s'", id)
+	}
+	return d, nil
+}
+
+// RestoreDigest puts back a digest that was claimed but couldn't be sent. If
+// items have started a new digest since it was claimed, the claimed items are
+// merged into it ahead of the new ones, and the earlier flush time is kept.
+func RestoreDigest(ctx context.Context, d *Digest) error {
+	_, err := db.Upsert(ctx, DigestsCollection, bson.M{
+		digestIDKey: d.ID,
+	}, bson.M{
+		"$setOnInsert": bson.M{
+			digestSubscriberKey: d.Subscriber,
+			digestModeKey:       d.Mode,
+			digestVersionIDKey:  d.VersionID,
+		},
+		"$min": bson.M{
+			digestCreatedAtKey: d.CreatedAt,
+			digestFlushAtKey:   d.FlushAt,
+		},
+		"$push": bson.M{
+			digestItemsKey: bson.M{
+				"$each":     d.Items,
+				"$position": 0,
+				"$slice":    -maxDigestItems,
+			},
+		},
+		"$inc": bson.M{digestTotalKey: d.Total},
+	})
+	return errors.Wrapf(err, "restoring digest '%s'", d.ID)
+}
+
+// NewDigestNotification returns a notification that sends the digest's summary
+// payload to its subscriber.
+func NewDigestNotification(d *Digest, payload any) (*Notification, error) {
+	if payload == nil {
+		return nil, errors.New("cannot create notification with nil payload")
+	}
+	return &Notification{
+		ID:         fmt.Sprintf("digest-%s-%d", d.ID, d.CreatedAt.UnixNano()),
+		Subscriber: d.Subscriber,
+		Payload:    payload,
+	}, nil
+}
+
+// UniqueItems returns the digest's items, omitting repeats of the same event
+// that were added by more than one of the subscriber's subscriptions.
+func (d *Digest) UniqueItems() []DigestItem {
+	seen := map[string]bool{}
+	items := make([]DigestItem, 0, len(d.Items))
+	for _, item := range d.Items {
+		key := item.EventID + "-" + item.ID
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		items = append(items, item)
+	}
+	return items
+}
+
+// FailedItems returns the digest's unique items that describe failures.
+func (d *Digest) FailedItems() []DigestItem {
+	failed := []DigestItem{}
+	for _, item := range d.UniqueItems() {
+		if item.Failed {
+			failed = append(failed, item)
+		}
+	}
+	return failed
+}
@@ -0,0 +1,108 @@
+package notification
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDigests(t *testing.T) {
+	require.NoError(t, db.ClearCollections(DigestsCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(DigestsCollection))
+	}()
+
+	sub := &event.Subscription{
+		ID:      "sub",
+		Trigger: event.TriggerFailure,
+		Subscriber: event.Subscriber{
+			Type:   event.SlackSubscriberType,
+			Target: "#evergreen",
+		},
+		Digest: &event.DigestOptions{Mode: event.DigestModeVersion, WindowMinutes: 10},
+	}
+
+	t.Run("RequiresDigestEnabled", func(t *testing.T) {
+		noDigest := *sub
+		noDigest.Digest = nil
+		assert.Error(t, AddToDigest(t.Context(), &noDigest, DigestItem{ID: "t1", VersionID: "v1"}))
+	})
+	t.Run("VersionModeRequiresVersion", func(t *testing.T) {
+		assert.Error(t, AddToDigest(t.Context(), sub, DigestItem{ID: "t1"}))
+	})
+	t.Run("GroupsItemsByVersion", func(t *testing.T) {
+		require.NoError(t, AddToDigest(t.Context(), sub, DigestItem{EventID: "e1", ID: "t1", VersionID: "v1", Failed: true}))
+		require.NoError(t, AddToDigest(t.Context(), sub, DigestItem{EventID: "e2", ID: "t2", VersionID: "v1"}))
+		require.NoError(t, AddToDigest(t.Context(), sub, DigestItem{EventID: "e3", ID: "t3", VersionID: "v2"}))
+
+		ids, err := FindDigestIDsToFlush(t.Context(), time.Now())
+		require.NoError(t, err)
+		assert.Empty(t, ids, "digests should not be flushed before their window closes")
+
+		ids, err = FindDigestIDsToFlush(t.Context(), time.Now().Add(11*time.Minute))
+		require.NoError(t, err)
+		require.Len(t, ids, 2)
+		assert.Contains(t, ids, makeDigestID(sub, "v1"))
+
+		d, err := ClaimDigest(t.Context(), makeDigestID(sub, "v1"))
+		require.NoError(t, err)
+		require.NotNil(t, d)
+		assert.Equal(t, sub.Subscriber, d.Subscriber)
+		assert.Equal(t, "v1", d.VersionID)
+		assert.Equal(t, 2, d.Total)
+		require.Len(t, d.Items, 2)
+		assert.Len(t, d.FailedItems(), 1)
+
+		d, err = ClaimDigest(t.Context(), makeDigestID(sub, "v1"))
+		assert.NoError(t, err)
+		assert.Nil(t, d, "digest should only be claimed once")
+	})
+	t.Run("RestoreDigest", func(t *testing.T) {
+		require.NoError(t, AddToDigest(t.Context(), sub, DigestItem{EventID: "e4", ID: "t4", VersionID: "v3"}))
+		claimed, err := ClaimDigest(t.Context(), makeDigestID(sub, "v3"))
+		require.NoError(t, err)
+		require.NotNil(t, claimed)
+
+		require.NoError(t, AddToDigest(t.Context(), sub, DigestItem{EventID: "e5", ID: "t5", VersionID: "v3"}))
+		require.NoError(t, RestoreDigest(t.Context(), claimed))
+
+		d, err := ClaimDigest(t.Context(), makeDigestID(sub, "v3"))
+		require.NoError(t, err)
+		require.NotNil(t, d)
+		assert.Equal(t, sub.Subscriber, d.Subscriber)
+		assert.Equal(t, 2, d.Total)
+		require.Len(t, d.Items, 2)
+		assert.Equal(t, "t4", d.Items[0].ID, "restored items should come before newer ones")
+		assert.Equal(t, "t5", d.Items[1].ID)
+		assert.True(t, claimed.CreatedAt.Equal(d.CreatedAt))
+		assert.True(t, claimed.FlushAt.Equal(d.FlushAt))
+
+		require.NoError(t, RestoreDigest(t.Context(), claimed))
+		d, err = ClaimDigest(t.Context(), makeDigestID(sub, "v3"))
+		require.NoError(t, err)
+		require.NotNil(t, d, "digest should be restored even if no new one was started")
+		assert.Equal(t, claimed.Items, d.Items)
+	})
+	t.Run("NewDigestNotification", func(t *testing.T) {
+		d := &Digest{ID: "digest", Subscriber: sub.Subscriber, CreatedAt: time.Now()}
+		n, err := NewDigestNotification(d, &SlackPayload{Body: "digest"})
+		require.NoError(t, err)
+		assert.Equal(t, sub.Subscriber, n.Subscriber)
+		assert.Contains(t, n.ID, "digest-digest-")
+
+		_, err = NewDigestNotification(d, nil)
+		assert.Error(t, err)
+	})
+	t.Run("UniqueItems", func(t *testing.T) {
+		d := &Digest{Items: []DigestItem{
+			{EventID: "e1", ID: "t1", SubscriptionID: "sub1"},
+			{EventID: "e1", ID: "t1", SubscriptionID: "sub2"},
+			{EventID: "e2", ID: "t1"},
+		}}
+		assert.Len(t, d.UniqueItems(), 2)
+	})
+}
@@ -2,6 +2,7 @@ package notification
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -54,6 +55,14 @@ type Notification struct {
 	SentAt   time.Time            `bson:"sent_at,omitempty"`
 	Error    string               `bson:"error,omitempty"`
 	Metadata NotificationMetadata `bson:"metadata,omitempty"`
+
+	// SubscriptionID is the subscription that generated the notification.
+	SubscriptionID string `bson:"subscription_id,omitempty"`
+	// DeliveryStatus, DeliveryAttempts and NextAttemptAt track the retries of
+	// webhook notifications that could not be delivered.
+	DeliveryStatus   DeliveryStatus `bson:"delivery_status,omitempty"`
+	DeliveryAttempts int            `bson:"delivery_attempts,omitempty"`
+	NextAttemptAt    time.Time      `bson:"next_attempt_at,omitempty"`
 }
 
 type NotificationMetadata struct {
@@ -81,6 +90,9 @@ func (n *Notification) SenderKey() (evergreen.SenderKey, error) {
 	case event.SlackSubscriberType:
 		return evergreen.SenderSlack, nil
 
+	case event.TeamsSubscriberType, event.MattermostSubscriberType:
+		return evergreen.SenderChatWebhook, nil
+
 	case event.GithubPullRequestSubscriberType, event.GithubCheckSubscriberType, event.GithubMergeSubscriberType:
 		return evergreen.SenderGithubStatus, nil
 
@@ -185,6 +197,52 @@ func (n *Notification) Composer(ctx context.Context) (message.Composer, error) {
 
 		return message.NewSlackMessage(level.Notice, formattedTarget, payload.Body, payload.Attachments), nil
 
+	case event.TeamsSubscriberType:
+		sub, ok := n.Subscriber.Target.(*event.TeamsSubscriber)
+		if !ok {
+			return nil, errors.New("teams subscriber is invalid")
+		}
+
+		payload, ok := n.Payload.(*TeamsPayload)
+		if !ok || payload == nil {
+			return nil, errors.New("teams payload is invalid")
+		}
+
+		body, err := json.Marshal(payload)
+		if err != nil {
+			return nil, errors.Wrap(err, "marshalling teams payload")
+		}
+
+		return util.NewChatWebhookMessage(util.ChatWebhook{
+			NotificationID: n.ID,
+			URL:            sub.URL,
+			Body:           body,
+		}), nil
+
+	case event.MattermostSubscriberType:
+		sub, ok := n.Subscriber.Target.(*event.MattermostSubscriber)
+		if !ok {
+			return nil, errors.New("mattermost subscriber is invalid")
+		}
+
+		payload, ok := n.Payload.(*MattermostPayload)
+		if !ok || payload == nil {
+			return nil, errors.New("mattermost payload is invalid")
+		}
+
+		payload.Channel = sub.Channel
+		payload.Username = sub.Username
+		body, err := json.Marshal(payload)
+		if err != nil {
+			return nil, errors.Wrap(err, "marshalling mattermost payload")
+		}
+
+		return util.NewChatWebhookMessage(util.ChatWebhook{
+			NotificationID: n.ID,
+			URL:            sub.URL,
+			Body:           body,
+		}), nil
+
 	case event.GithubPullRequestSubscriberType:
 		sub := n.Subscriber.Target.(*event.GithubPullRequestSubscriber)
 		payload, ok := n.Payload.(*message.GithubStatus)
@@ -306,6 +364,8 @@ type NotificationStats struct {
 	Slack             int `json:"slack" bson:"slack" yaml:"slack"`
 	GithubCheck       int `json:"github_check" bson:"github_check" yaml:"github_check"`
 	GithubMerge       int `json:"github_merge" bson:"github_merge" yaml:"github_merge"`
+	Teams             int `json:"teams" bson:"teams" yaml:"teams"`
+	Mattermost        int `json:"mattermost" bson:"mattermost" yaml:"mattermost"`
 }
 
 func CollectUnsentNotificationStats(ctx context.Context) (*NotificationStats, error) {
@@ -365,6 +425,12 @@ func CollectUnsentNotificationStats(ctx context.Context) (*NotificationStats, er
 		case event.SlackSubscriberType:
 			nStats.Slack = data.Count
 
+		case event.TeamsSubscriberType:
+			nStats.Teams = data.Count
+
+		case event.MattermostSubscriberType:
+			nStats.Mattermost = data.Count
+
 		default:
 			grip.Error(ctx, message.Fields{
 				"message": fmt.Sprintf("unknown subscriber '%s'", data.Key),
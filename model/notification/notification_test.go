@@ -18,6 +18,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type notificationSuite struct {
@@ -410,6 +411,80 @@ func (s *notificationSuite) TestSlackPayload() {
 	s.True(c.Loggable())
 }
 
+func (s *notificationSuite) TestTeamsPayload() {
+	s.n.ID = "1"
+	s.n.Subscriber = event.NewTeamsSubscriber(event.TeamsSubscriber{URL: "https://example.com/webhook"})
+	s.n.Payload = NewTeamsPayload([]AdaptiveCardElement{
+		{Type: AdaptiveCardTextBlock, Text: "Hi"},
+	}, nil)
+
+	s.NoError(InsertMany(s.T().Context(), s.n))
+
+	n, err := Find(s.T().Context(), s.n.ID)
+	s.NoError(err)
+	s.Require().NotNil(n)
+
+	s.Equal(s.n, *n)
+
+	c, err := n.Composer(s.T().Context())
+	s.NoError(err)
+	s.Require().NotNil(c)
+	s.True(c.Loggable())
+	raw, ok := c.Raw().(*util.ChatWebhook)
+	s.Require().True(ok)
+	s.Equal("https://example.com/webhook", raw.URL)
+	s.Contains(string(raw.Body), `"contentType":"application/vnd.microsoft.card.adaptive"`)
+	s.Contains(string(raw.Body), `"$schema":"http://adaptivecards.io/schemas/adaptive-card.json"`)
+}
+
+func (s *notificationSuite) TestMattermostPayload() {
+	s.n.ID = "1"
+	s.n.Subscriber = event.NewMattermostSubscriber(event.MattermostSubscriber{
+		URL:      "https://example.com/hooks/abc",
+		Channel:  "builds",
+		Username: "evergreen",
+	})
+	s.n.Payload = &MattermostPayload{
+		Text:        "Hi",
+		Attachments: []MattermostAttachment{{Title: "Patch", Color: "#4ead4a"}},
+	}
+
+	s.NoError(InsertMany(s.T().Context(), s.n))
+
+	n, err := Find(s.T().Context(), s.n.ID)
+	s.NoError(err)
+	s.Require().NotNil(n)
+
+	s.Equal(s.n, *n)
+
+	c, err := n.Composer(s.T().Context())
+	s.NoError(err)
+	s.Require().NotNil(c)
+	s.True(c.Loggable())
+	raw, ok := c.Raw().(*util.ChatWebhook)
+	s.Require().True(ok)
+	s.Equal("https://example.com/hooks/abc", raw.URL)
+	s.JSONEq(`{"text":"Hi","channel":"builds","username":"evergreen","attachments":[{"title":"Patch","color":"#4ead4a"}]}`, string(raw.Body))
+}
+
+func (s *notificationSuite) TestCountSentToURLSince() {
+	url := "https://example.com/webhook"
+	now := time.Now()
+	notifications := []Notification{
+		{ID: "recent", Subscriber: event.NewTeamsSubscriber(event.TeamsSubscriber{URL: url}), Payload: &TeamsPayload{}, SentAt: now},
+		{ID: "old", Subscriber: event.NewTeamsSubscriber(event.TeamsSubscriber{URL: url}), Payload: &TeamsPayload{}, SentAt: now.Add(-time.Hour)},
+		{ID: "unsent", Subscriber: event.NewTeamsSubscriber(event.TeamsSubscriber{URL: url}), Payload: &TeamsPayload{}},
+		{ID: "other-url", Subscriber: event.NewTeamsSubscriber(event.TeamsSubscriber{URL: "https://example.com/other"}), Payload: &TeamsPayload{}, SentAt: now},
+		{ID: "other-type", Subscriber: event.NewMattermostSubscriber(event.MattermostSubscriber{URL: url}), Payload: &MattermostPayload{}, SentAt: now},
+	}
+	s.Require().NoError(InsertMany(s.T().Context(), notifications...))
+	s.Require().NoError(db.EnsureIndex(Collection, mongo.IndexModel{Keys: SentToURLIndex}))
+
+	count, err := CountSentToURLSince(s.T().Context(), event.TeamsSubscriberType, url, now.Add(-time.Minute))
+	s.NoError(err)
+	s.Equal(1, count)
+}
+
 func (s *notificationSuite) TestGithubPayload() {
 	s.n.ID = "1"
 	s.n.Subscriber.Type = event.GithubPullRequestSubscriberType
@@ -439,7 +514,8 @@ func (s *notificationSuite) TestCollectUnsentNotificationStats() {
 	types := []string{event.GithubPullRequestSubscriberType, event.EmailSubscriberType,
 		event.SlackSubscriberType, event.EvergreenWebhookSubscriberType,
 		event.JIRACommentSubscriberType, event.JIRAIssueSubscriberType,
-		event.GithubCheckSubscriberType, event.GithubMergeSubscriberType}
+		event.GithubCheckSubscriberType, event.GithubMergeSubscriberType,
+		event.TeamsSubscriberType, event.MattermostSubscriberType}
 
 	n := []Notification{}
 	// add one of every notification, unsent
@@ -6,3 +6,127 @@ type SlackPayload struct {
 	Body        string                    `bson:"body"`
 	Attachments []message.SlackAttachment `bson:"attachments"`
 }
+
+const (
+	teamsMessageType          = "message"
+	adaptiveCardContentType   = "application/vnd.microsoft.card.adaptive"
+	adaptiveCardSchema        = "http://adaptivecards.io/schemas/adaptive-card.json"
+	adaptiveCardType          = "AdaptiveCard"
+	adaptiveCardSchemaVersion = "1.4"
+	adaptiveCardFullWidth     = "Full"
+
+	// Adaptive Card element types, actions and text styles.
+	AdaptiveCardTextBlock = "TextBlock"
+	AdaptiveCardFactSet   = "FactSet"
+	AdaptiveCardOpenURL   = "Action.OpenUrl"
+	AdaptiveCardBolder    = "Bolder"
+	AdaptiveCardMedium    = "Medium"
+	AdaptiveCardSmall     = "Small"
+	AdaptiveCardGood      = "Good"
+	AdaptiveCardAttention = "Attention"
+	AdaptiveCardWarning   = "Warning"
+)
+
+// TeamsPayload is the message body sent to a Microsoft Teams incoming webhook,
+// which wraps a single Adaptive Card.
+type TeamsPayload struct {
+	Type        string            `bson:"type" json:"type"`
+	Attachments []TeamsAttachment `bson:"attachments" json:"attachments"`
+}
+
+// TeamsAttachment is an attachment to a Microsoft Teams message.
+type TeamsAttachment struct {
+	ContentType string       `bson:"content_type" json:"contentType"`
+	Content     AdaptiveCard `bson:"content" json:"content"`
+}
+
+// AdaptiveCard is an Adaptive Card, as documented at
+// https://adaptivecards.io/explorer/AdaptiveCard.html.
+type AdaptiveCard struct {
+	Schema  string                `bson:"schema" json:"$schema"`
+	Type    string                `bson:"type" json:"type"`
+	Version string                `bson:"version" json:"version"`
+	Body    []AdaptiveCardElement `bson:"body" json:"body"`
+	Actions []AdaptiveCardAction  `bson:"actions,omitempty" json:"actions,omitempty"`
+	MSTeams *AdaptiveCardMSTeams  `bson:"msteams,omitempty" json:"msteams,omitempty"`
+}
+
+// AdaptiveCardElement is a TextBlock or FactSet element of an Adaptive Card.
+type AdaptiveCardElement struct {
+	Type      string             `bson:"type" json:"type"`
+	Text      string             `bson:"text,omitempty" json:"text,omitempty"`
+	Size      string             `bson:"size,omitempty" json:"size,omitempty"`
+	Weight    string             `bson:"weight,omitempty" json:"weight,omitempty"`
+	Color     string             `bson:"color,omitempty" json:"color,omitempty"`
+	IsSubtle  bool               `bson:"is_subtle,omitempty" json:"isSubtle,omitempty"`
+	Wrap      bool               `bson:"wrap,omitempty" json:"wrap,omitempty"`
+	Separator bool               `bson:"separator,omitempty" json:"separator,omitempty"`
+	Facts     []AdaptiveCardFact `bson:"facts,omitempty" json:"facts,omitempty"`
+}
+
+// AdaptiveCardFact is a single title and value pair of a FactSet.
+type AdaptiveCardFact struct {
+	Title string `bson:"title" json:"title"`
+	Value string `bson:"value" json:"value"`
+}
+
+// AdaptiveCardAction is an action that opens a URL.
+type AdaptiveCardAction struct {
+	Type  string `bson:"type" json:"type"`
+	Title string `bson:"title" json:"title"`
+	URL   string `bson:"url" json:"url"`
+}
+
+// AdaptiveCardMSTeams holds the Microsoft Teams-specific card settings.
+type AdaptiveCardMSTeams struct {
+	Width string `bson:"width,omitempty" json:"width,omitempty"`
+}
+
+// NewTeamsPayload returns a Microsoft Teams message containing an Adaptive Card
+// with the given body and actions.
+func NewTeamsPayload(body []AdaptiveCardElement, actions []AdaptiveCardAction) *TeamsPayload {
+	return &TeamsPayload{
+		Type: teamsMessageType,
+		Attachments: []TeamsAttachment{
+			{
+				ContentType: adaptiveCardContentType,
+				Content: AdaptiveCard{
+					Schema:  adaptiveCardSchema,
+					Type:    adaptiveCardType,
+					Version: adaptiveCardSchemaVersion,
+					Body:    body,
+					Actions: actions,
+					MSTeams: &AdaptiveCardMSTeams{Width: adaptiveCardFullWidth},
+				},
+			},
+		},
+	}
+}
+
+// MattermostPayload is the message body sent to a Mattermost-compatible
+// incoming webhook.
+type MattermostPayload struct {
+	Text        string                 `bson:"text" json:"text"`
+	Channel     string                 `bson:"channel,omitempty" json:"channel,omitempty"`
+	Username    string                 `bson:"username,omitempty" json:"username,omitempty"`
+	Attachments []MattermostAttachment `bson:"attachments,omitempty" json:"attachments,omitempty"`
+}
+
+// MattermostAttachment is a message attachment, which Mattermost and
+// Rocket.Chat both render with a colored side bar.
+type MattermostAttachment struct {
+	Fallback  string            `bson:"fallback,omitempty" json:"fallback,omitempty"`
+	Color     string            `bson:"color,omitempty" json:"color,omitempty"`
+	Title     string            `bson:"title,omitempty" json:"title,omitempty"`
+	TitleLink string            `bson:"title_link,omitempty" json:"title_link,omitempty"`
+	Text      string            `bson:"text,omitempty" json:"text,omitempty"`
+	Fields    []MattermostField `bson:"fields,omitempty" json:"fields,omitempty"`
+	Footer    string            `bson:"footer,omitempty" json:"footer,omitempty"`
+}
+
+// MattermostField is a field of a message attachment.
+type MattermostField struct {
+	Title string `bson:"title" json:"title"`
+	Value string `bson:"value" json:"value"`
+	Short bool   `bson:"short" json:"short"`
+}
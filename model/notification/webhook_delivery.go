@@ -0,0 +1,218 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	mgobson "github.com/evergreen-ci/evergreen/db/mgo/bson"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	WebhookDeliveriesCollection = "webhook_deliveries"
+
+	// MaxWebhookDeliveryAttempts is the number of times a webhook
+	// notification is sent before it's moved to the dead-letter queue. Each
+	// of these attempts can itself retry the request up to the subscriber's
+	// configured number of retries.
+	MaxWebhookDeliveryAttempts = 8
+
+	webhookRetryBaseDelay = time.Minute
+	webhookRetryMaxDelay  = time.Hour
+)
+
+// DeliveryStatus is the status of a webhook notification that could not be
+// delivered.
+type DeliveryStatus string
+
+const (
+	// DeliveryStatusRetrying indicates that delivery failed and will be
+	// retried after the notification's next attempt time.
+	DeliveryStatusRetrying DeliveryStatus = "retrying"
+	// DeliveryStatusDeadLetter indicates that delivery failed too many times
+	// and won't be retried unless it's replayed.
+	DeliveryStatusDeadLetter DeliveryStatus = "dead-letter"
+)
+
+var (
+	webhookDeliveryIDKey             = bsonutil.MustHaveTag(WebhookDelivery{}, "ID")
+	webhookDeliverySubscriptionIDKey = bsonutil.MustHaveTag(WebhookDelivery{}, "SubscriptionID")
+	webhookDeliveryTimeKey           = bsonutil.MustHaveTag(WebhookDelivery{}, "Time")
+)
+
+// WebhookDelivery is a log entry for a single HTTP request made to deliver a
+// webhook notification.
+type WebhookDelivery struct {
+	ID             string `bson:"_id"`
+	NotificationID string `bson:"notification_id"`
+	SubscriptionID string `bson:"subscription_id,omitempty"`
+	URL            string `bson:"url"`
+	// Attempt is the notification's delivery attempt that made the request.
+	Attempt    int    `bson:"attempt"`
+	StatusCode int    `bson:"status_code,omitempty"`
+	LatencyMS  int64  `bson:"latency_ms"`
+	Error      string `bson:"error,omitempty"`
+	// Time is when the request was sent.
+	Time time.Time `bson:"time"`
+}
+
+// Insert inserts the webhook delivery log entry.
+func (d *WebhookDelivery) Insert(ctx context.Context) error {
+	if d.ID == "" {
+		d.ID = mgobson.NewObjectId().Hex()
+	}
+	return errors.Wrapf(db.Insert(ctx, WebhookDeliveriesCollection, d), "inserting webhook delivery for notification '%s'", d.NotificationID)
+}
+
+// FindWebhookDeliveriesForSubscription returns the subscription's most recent
+// webhook delivery log entries, newest first.
+func FindWebhookDeliveriesForSubscription(ctx context.Context, subscriptionID string, limit int) ([]WebhookDelivery, error) {
+	deliveries := []WebhookDelivery{}
+	q := db.Query(bson.M{
+		webhookDeliverySubscriptionIDKey: subscriptionID,
+	}).Sort([]string{"-" + webhookDeliveryTimeKey, "-" + webhookDeliveryIDKey}).Limit(limit)
+	if err := db.FindAllQ(ctx, WebhookDeliveriesCollection, q, &deliveries); err != nil {
+		return nil, errors.Wrapf(err, "finding webhook deliveries for subscription '%s'", subscriptionID)
+	}
+	return deliveries, nil
+}
+
+// FindFailedWebhookNotifications returns the subscription's webhook
+// notifications that are waiting to be retried or have been moved to the
+// dead-letter queue.
+func FindFailedWebhookNotifications(ctx context.Context, subscriptionID string) ([]Notification, error) {
+	notifications := []Notification{}
+	q := db.Query(bson.M{
+		subscriptionIDKey: subscriptionID,
+		bsonutil.GetDottedKeyName(subscriberKey, "type"): event.EvergreenWebhookSubscriberType,
+		deliveryStatusKey: bson.M{"$in": []DeliveryStatus{DeliveryStatusRetrying, DeliveryStatusDeadLetter}},
+	}).Sort([]string{"-" + nextAttemptAtKey})
+	if err := db.FindAllQ(ctx, Collection, q, &notifications); err != nil {
+		return nil, errors.Wrapf(err, "finding failed webhook notifications for subscription '%s'", subscriptionID)
+	}
+	return notifications, nil
+}
+
+// webhookRetryDelay returns how long to wait before the next attempt to
+// deliver a notification that has failed the given number of times. The delay
+// doubles after every attempt, up to a maximum.
+func webhookRetryDelay(attempts int) time.Duration {
+	delay := webhookRetryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= webhookRetryMaxDelay {
+			return webhookRetryMaxDelay
+		}
+	}
+	return delay
+}
+
+// MarkDeliveryFailed records a failed attempt to deliver the webhook
+// notification. The notification is left unsent to be retried with
+// exponential backoff until it has been attempted MaxWebhookDeliveryAttempts
+// times, after which it's marked sent with the error and moved to the
+// dead-letter queue.
+func (n *Notification) MarkDeliveryFailed(ctx context.Context, sendErr error) error {
+	if sendErr == nil {
+		return nil
+	}
+	if len(n.ID) == 0 {
+		return errors.New("notification has no ID")
+	}
+
+	now := time.Now().Truncate(time.Millisecond)
+	attempts := n.DeliveryAttempts + 1
+	set := bson.M{
+		errorKey:            sendErr.Error(),
+		deliveryAttemptsKey: attempts,
+	}
+	if attempts >= MaxWebhookDeliveryAttempts {
+		set[deliveryStatusKey] = DeliveryStatusDeadLetter
+		set[sentAtKey] = now
+		set[nextAttemptAtKey] = now
+	} else {
+		set[deliveryStatusKey] = DeliveryStatusRetrying
+		set[nextAttemptAtKey] = now.Add(webhookRetryDelay(attempts))
+	}
+
+	if err := db.UpdateId(ctx, Collection, n.ID, bson.M{"$set": set}); err != nil {
+		return errors.Wrap(err, "marking notification delivery as failed")
+	}
+
+	n.Error = sendErr.Error()
+	n.DeliveryAttempts = attempts
+	n.DeliveryStatus = set[deliveryStatusKey].(DeliveryStatus)
+	n.NextAttemptAt = set[nextAttemptAtKey].(time.Time)
+	if n.DeliveryStatus == DeliveryStatusDeadLetter {
+		n.SentAt = now
+	}
+
+	return nil
+}
+
+// MarkDelivered marks a webhook notification that was delivered as sent and
+// clears the status, error and next attempt time left by any earlier failed
+// attempts, so that it's no longer listed as a failed delivery.
+func (n *Notification) MarkDelivered(ctx context.Context) error {
+	if len(n.ID) == 0 {
+		return errors.New("notification has no ID")
+	}
+
+	now := time.Now().Truncate(time.Millisecond)
+	err := db.UpdateId(ctx, Collection, n.ID, bson.M{
+		"$set": bson.M{
+			sentAtKey: now,
+		},
+		"$unset": bson.M{
+			errorKey:          1,
+			deliveryStatusKey: 1,
+			nextAttemptAtKey:  1,
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "marking notification as delivered")
+	}
+
+	n.SentAt = now
+	n.Error = ""
+	n.DeliveryStatus = ""
+	n.NextAttemptAt = time.Time{}
+
+	return nil
+}
+
+// Replay moves a dead-lettered webhook notification back to the unsent
+// notifications so that it's delivered again with a fresh set of attempts.
+func (n *Notification) Replay(ctx context.Context) error {
+	if n.DeliveryStatus != DeliveryStatusDeadLetter {
+		return errors.Errorf("notification '%s' is not in the dead-letter queue", n.ID)
+	}
+
+	err := db.Update(ctx, Collection, bson.M{
+		idKey:             n.ID,
+		deliveryStatusKey: DeliveryStatusDeadLetter,
+	}, bson.M{
+		"$unset": bson.M{
+			sentAtKey:           1,
+			errorKey:            1,
+			deliveryStatusKey:   1,
+			deliveryAttemptsKey: 1,
+			nextAttemptAtKey:    1,
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "replaying notification '%s'", n.ID)
+	}
+
+	n.SentAt = time.Time{}
+	n.Error = ""
+	n.DeliveryStatus = ""
+	n.DeliveryAttempts = 0
+	n.NextAttemptAt = time.Time{}
+
+	return nil
+}
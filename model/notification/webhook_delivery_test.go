@@ -0,0 +1,143 @@
+package notification
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookRetryDelay(t *testing.T) {
+	assert.Equal(t, time.Minute, webhookRetryDelay(1))
+	assert.Equal(t, 2*time.Minute, webhookRetryDelay(2))
+	assert.Equal(t, 32*time.Minute, webhookRetryDelay(6))
+	assert.Equal(t, time.Hour, webhookRetryDelay(7))
+	assert.Equal(t, time.Hour, webhookRetryDelay(100))
+}
+
+func TestWebhookDeliveryRetries(t *testing.T) {
+	require.NoError(t, db.ClearCollections(Collection, WebhookDeliveriesCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(Collection, WebhookDeliveriesCollection))
+	}()
+
+	makeNotification := func(t *testing.T, id, subscriptionID string) *Notification {
+		n := &Notification{
+			ID:             id,
+			SubscriptionID: subscriptionID,
+			Subscriber: event.Subscriber{
+				Type:   event.EvergreenWebhookSubscriberType,
+				Target: &event.WebhookSubscriber{URL: "https://example.com", Secret: []byte("secret")},
+			},
+			Payload: &util.EvergreenWebhook{Body: []byte("{}")},
+		}
+		require.NoError(t, InsertMany(t.Context(), *n))
+		return n
+	}
+	sendErr := errors.New("webhook response was 503 (Service Unavailable)")
+
+	t.Run("FailedDeliveryIsRetriedLater", func(t *testing.T) {
+		n := makeNotification(t, "retrying", "sub")
+		require.NoError(t, n.MarkDeliveryFailed(t.Context(), sendErr))
+		assert.Equal(t, DeliveryStatusRetrying, n.DeliveryStatus)
+		assert.Equal(t, 1, n.DeliveryAttempts)
+		assert.True(t, n.SentAt.IsZero())
+
+		dbNotification, err := Find(t.Context(), n.ID)
+		require.NoError(t, err)
+		require.NotNil(t, dbNotification)
+		assert.Equal(t, DeliveryStatusRetrying, dbNotification.DeliveryStatus)
+		assert.Equal(t, sendErr.Error(), dbNotification.Error)
+		assert.True(t, dbNotification.NextAttemptAt.After(time.Now()))
+
+		unprocessed, err := FindUnprocessed(t.Context())
+		require.NoError(t, err)
+		assert.Empty(t, unprocessed, "notification should not be resent before its next attempt")
+
+		require.NoError(t, db.UpdateId(t.Context(), Collection, n.ID, map[string]any{
+			"$set": map[string]any{nextAttemptAtKey: time.Now().Add(-time.Minute)},
+		}))
+		unprocessed, err = FindUnprocessed(t.Context())
+		require.NoError(t, err)
+		require.Len(t, unprocessed, 1)
+		assert.Equal(t, n.ID, unprocessed[0].ID)
+
+		failed, err := FindFailedWebhookNotifications(t.Context(), "sub")
+		require.NoError(t, err)
+		require.Len(t, failed, 1)
+		assert.Equal(t, n.ID, failed[0].ID)
+
+		assert.Error(t, n.Replay(t.Context()), "only dead-lettered notifications can be replayed")
+	})
+	t.Run("DeadLetterAndReplay", func(t *testing.T) {
+		n := makeNotification(t, "dead-letter", "sub")
+		n.DeliveryAttempts = MaxWebhookDeliveryAttempts - 1
+		require.NoError(t, n.MarkDeliveryFailed(t.Context(), sendErr))
+		assert.Equal(t, DeliveryStatusDeadLetter, n.DeliveryStatus)
+		assert.False(t, n.SentAt.IsZero())
+
+		dbNotification, err := Find(t.Context(), n.ID)
+		require.NoError(t, err)
+		require.NotNil(t, dbNotification)
+		assert.Equal(t, DeliveryStatusDeadLetter, dbNotification.DeliveryStatus)
+		assert.False(t, dbNotification.SentAt.IsZero())
+
+		require.NoError(t, dbNotification.Replay(t.Context()))
+		dbNotification, err = Find(t.Context(), n.ID)
+		require.NoError(t, err)
+		require.NotNil(t, dbNotification)
+		assert.Empty(t, dbNotification.DeliveryStatus)
+		assert.Zero(t, dbNotification.DeliveryAttempts)
+		assert.True(t, dbNotification.SentAt.IsZero())
+		assert.Empty(t, dbNotification.Error)
+	})
+	t.Run("RetryThenSuccess", func(t *testing.T) {
+		n := makeNotification(t, "retried", "retried-sub")
+		require.NoError(t, n.MarkDeliveryFailed(t.Context(), sendErr))
+		failed, err := FindFailedWebhookNotifications(t.Context(), n.SubscriptionID)
+		require.NoError(t, err)
+		require.Len(t, failed, 1)
+
+		require.NoError(t, n.MarkDelivered(t.Context()))
+		failed, err = FindFailedWebhookNotifications(t.Context(), n.SubscriptionID)
+		require.NoError(t, err)
+		assert.Empty(t, failed, "delivered notification should not be listed as failed")
+
+		dbNotification, err := Find(t.Context(), n.ID)
+		require.NoError(t, err)
+		require.NotNil(t, dbNotification)
+		assert.False(t, dbNotification.SentAt.IsZero())
+		assert.Empty(t, dbNotification.DeliveryStatus)
+		assert.Empty(t, dbNotification.Error)
+		assert.True(t, dbNotification.NextAttemptAt.IsZero())
+		assert.Equal(t, 1, dbNotification.DeliveryAttempts)
+		assert.Error(t, dbNotification.Replay(t.Context()), "delivered notifications cannot be replayed")
+	})
+	t.Run("DeliveryLog", func(t *testing.T) {
+		now := time.Now().Truncate(time.Millisecond)
+		for i := 0; i < 3; i++ {
+			d := WebhookDelivery{
+				NotificationID: "n",
+				SubscriptionID: "sub",
+				URL:            "https://example.com",
+				StatusCode:     503,
+				LatencyMS:      int64(i),
+				Time:           now.Add(time.Duration(i) * time.Second),
+			}
+			require.NoError(t, d.Insert(t.Context()))
+		}
+		other := WebhookDelivery{NotificationID: "other", SubscriptionID: "other-sub", Time: now}
+		require.NoError(t, other.Insert(t.Context()))
+
+		deliveries, err := FindWebhookDeliveriesForSubscription(t.Context(), "sub", 2)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 2)
+		assert.EqualValues(t, 2, deliveries[0].LatencyMS, "newest delivery should be first")
+		assert.EqualValues(t, 1, deliveries[1].LatencyMS)
+	})
+}
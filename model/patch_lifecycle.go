@@ -90,6 +90,11 @@ func ValidateTVPairs(p *Project, in []TVPair) error {
 // Given a patch version and a list of variant/task pairs, creates the set of new builds that
 // do not exist yet out of the set of pairs, and adds tasks for builds which already exist.
 func addNewTasksAndBuildsForPatch(ctx context.Context, p *patch.Patch, creationInfo TaskCreationInfo, caller string) error {
+	if creationInfo.ProjectRef != nil {
+		if err := creationInfo.ProjectRef.CheckCostBudgetHardStop(ctx, creationInfo.Version.Requester); err != nil {
+			return err
+		}
+	}
 	existingBuilds, err := build.Find(ctx, build.ByIds(creationInfo.Version.BuildIds).WithFields(build.IdKey, build.BuildVariantKey, build.CreateTimeKey, build.RequesterKey))
 	if err != nil {
 		return err
@@ -740,6 +745,9 @@ func FinalizePatch(ctx context.Context, p *patch.Patch, requester string, transl
 	if projectRef == nil {
 		return nil, errors.Errorf("project '%s' not found", p.Project)
 	}
+	if err = projectRef.CheckCostBudgetHardStop(ctx, requester); err != nil {
+		return nil, err
+	}
 
 	project := translatedProject
 	if project == nil {
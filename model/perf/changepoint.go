@@ -0,0 +1,213 @@
+package perf
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	// DefaultMinSegmentLength is the fewest points there can be between two
+	// change points, so that a single noisy result isn't reported as a shift.
+	DefaultMinSegmentLength = 5
+	// DefaultPenaltyFactor scales the penalty, in multiples of the log of the
+	// number of points, that adding a change point must overcome.
+	DefaultPenaltyFactor = 3.0
+	// DefaultMinPercentChange is the smallest shift in a series' mean, as a
+	// percentage of its mean before the shift, that's reported.
+	DefaultMinPercentChange = 5.0
+)
+
+// DetectorOptions configure the change point detector.
+type DetectorOptions struct {
+	MinSegmentLength int
+	PenaltyFactor    float64
+	MinPercentChange float64
+}
+
+func (o *DetectorOptions) setDefaults() {
+	if o.MinSegmentLength <= 0 {
+		o.MinSegmentLength = DefaultMinSegmentLength
+	}
+	if o.PenaltyFactor <= 0 {
+		o.PenaltyFactor = DefaultPenaltyFactor
+	}
+	if o.MinPercentChange <= 0 {
+		o.MinPercentChange = DefaultMinPercentChange
+	}
+}
+
+// Change is a shift in the mean of a series of values.
+type Change struct {
+	// Index is the index of the first value after the shift.
+	Index         int
+	BeforeMean    float64
+	AfterMean     float64
+	PercentChange float64
+}
+
+// DetectChanges finds the shifts in the mean of the values, which are in
+// order, using PELT (Pruned Exact Linear Time) to find the segmentation that
+// minimizes the squared error of each segment from its mean plus a penalty
+// for each change point. The values are scaled by a noise estimate that isn't
+// affected by the shifts themselves, so the penalty doesn't depend on the
+// metric's units, and a running median removes single outliers, which would
+// otherwise look like a pair of shifts. Shifts smaller than the minimum
+// percent change are ignored.
+func DetectChanges(values []float64, opts DetectorOptions) []Change {
+	opts.setDefaults()
+	n := len(values)
+	if n < 2*opts.MinSegmentLength {
+		return nil
+	}
+
+	smoothed := medianFilter(values)
+	sigma := noiseEstimate(values)
+	if sigma == 0 {
+		sigma = stdDev(values)
+	}
+	if sigma == 0 {
+		return nil
+	}
+
+	// Prefix sums of the scaled values and their squares give the cost of
+	// any segment in constant time.
+	sums := make([]float64, n+1)
+	squares := make([]float64, n+1)
+	for i, v := range smoothed {
+		scaled := v / sigma
+		sums[i+1] = sums[i] + scaled
+		squares[i+1] = squares[i] + scaled*scaled
+	}
+	cost := func(start, end int) float64 {
+		length := float64(end - start)
+		sum := sums[end] - sums[start]
+		return squares[end] - squares[start] - sum*sum/length
+	}
+
+	penalty := opts.PenaltyFactor * math.Log(float64(n))
+	minLength := opts.MinSegmentLength
+
+	// best[t] is the lowest total cost of segmenting the first t values and
+	// last[t] is where the final segment of that segmentation starts.
+	best := make([]float64, n+1)
+	last := make([]int, n+1)
+	for i := range best {
+		best[i] = math.Inf(1)
+	}
+	best[0] = -penalty
+	candidates := []int{0}
+	for t := minLength; t <= n; t++ {
+		segmentCosts := make(map[int]float64, len(candidates))
+		for _, start := range candidates {
+			if t-start < minLength {
+				continue
+			}
+			segmentCosts[start] = best[start] + cost(start, t)
+			if total := segmentCosts[start] + penalty; total < best[t] {
+				best[t] = total
+				last[t] = start
+			}
+		}
+
+		// Prune the starts that can never be part of an optimal
+		// segmentation of a longer prefix.
+		pruned := candidates[:0]
+		for _, start := range candidates {
+			if c, ok := segmentCosts[start]; ok && c > best[t] {
+				continue
+			}
+			pruned = append(pruned, start)
+		}
+		candidates = pruned
+		if t+minLength <= n && !math.IsInf(best[t], 1) {
+			candidates = append(candidates, t)
+		}
+	}
+
+	boundaries := []int{n}
+	for t := n; t > 0; t = last[t] {
+		boundaries = append(boundaries, last[t])
+	}
+	sort.Ints(boundaries)
+
+	changes := []Change{}
+	for i := 1; i+1 < len(boundaries); i++ {
+		before := mean(values[boundaries[i-1]:boundaries[i]])
+		after := mean(values[boundaries[i]:boundaries[i+1]])
+		if before == 0 {
+			continue
+		}
+		percent := (after - before) / math.Abs(before) * 100
+		if math.Abs(percent) < opts.MinPercentChange {
+			continue
+		}
+		changes = append(changes, Change{
+			Index:         boundaries[i],
+			BeforeMean:    before,
+			AfterMean:     after,
+			PercentChange: percent,
+		})
+	}
+	return changes
+}
+
+// medianFilter replaces each value with the median of it and its neighbors,
+// which removes isolated outliers but keeps the edges of shifts in place.
+func medianFilter(values []float64) []float64 {
+	filtered := make([]float64, len(values))
+	copy(filtered, values)
+	for i := 1; i+1 < len(values); i++ {
+		window := []float64{values[i-1], values[i], values[i+1]}
+		sort.Float64s(window)
+		filtered[i] = window[1]
+	}
+	return filtered
+}
+
+// noiseEstimate estimates the standard deviation of the noise in the values
+// from the median absolute difference between consecutive values, which a few
+// shifts in the mean barely affect.
+func noiseEstimate(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+	diffs := make([]float64, 0, len(values)-1)
+	for i := 1; i < len(values); i++ {
+		diffs = append(diffs, math.Abs(values[i]-values[i-1]))
+	}
+	sort.Float64s(diffs)
+
+	var median float64
+	if mid := len(diffs) / 2; len(diffs)%2 == 0 {
+		median = (diffs[mid-1] + diffs[mid]) / 2
+	} else {
+		median = diffs[mid]
+	}
+	// The difference of two independent normal values has a standard
+	// deviation of sqrt(2) times theirs, and the median absolute deviation
+	// of a normal distribution is 0.6745 times its standard deviation.
+	return median / (0.6745 * math.Sqrt2)
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+func stdDev(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+	m := mean(values)
+	var sum float64
+	for _, v := range values {
+		sum += (v - m) * (v - m)
+	}
+	return math.Sqrt(sum / float64(len(values)-1))
+}
@@ -0,0 +1,77 @@
+package perf
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// noisySeries returns values around each of the means for the given number
+// of points, with reproducible noise of the given standard deviation.
+func noisySeries(seed int64, stdDev float64, segments ...struct {
+	mean float64
+	n    int
+}) []float64 {
+	r := rand.New(rand.NewSource(seed))
+	values := []float64{}
+	for _, s := range segments {
+		for i := 0; i < s.n; i++ {
+			values = append(values, s.mean+r.NormFloat64()*stdDev)
+		}
+	}
+	return values
+}
+
+type segment = struct {
+	mean float64
+	n    int
+}
+
+func TestDetectChanges(t *testing.T) {
+	t.Run("TooFewPoints", func(t *testing.T) {
+		assert.Empty(t, DetectChanges([]float64{1, 2, 100, 100}, DetectorOptions{}))
+	})
+	t.Run("ConstantSeries", func(t *testing.T) {
+		values := make([]float64, 50)
+		for i := range values {
+			values[i] = 42
+		}
+		assert.Empty(t, DetectChanges(values, DetectorOptions{}))
+	})
+	t.Run("NoiseOnly", func(t *testing.T) {
+		values := noisySeries(1, 2, segment{mean: 100, n: 200})
+		assert.Empty(t, DetectChanges(values, DetectorOptions{}))
+	})
+	t.Run("SingleDrop", func(t *testing.T) {
+		values := noisySeries(2, 2, segment{mean: 100, n: 60}, segment{mean: 80, n: 40})
+		changes := DetectChanges(values, DetectorOptions{})
+		require.Len(t, changes, 1)
+		assert.InDelta(t, 60, changes[0].Index, 1)
+		assert.InDelta(t, 100, changes[0].BeforeMean, 1)
+		assert.InDelta(t, 80, changes[0].AfterMean, 1)
+		assert.InDelta(t, -20, changes[0].PercentChange, 2)
+	})
+	t.Run("DropAndRecovery", func(t *testing.T) {
+		values := noisySeries(3, 1, segment{mean: 50, n: 30}, segment{mean: 65, n: 30}, segment{mean: 50, n: 30})
+		changes := DetectChanges(values, DetectorOptions{})
+		require.Len(t, changes, 2)
+		assert.InDelta(t, 30, changes[0].Index, 1)
+		assert.Greater(t, changes[0].PercentChange, 0.0)
+		assert.InDelta(t, 60, changes[1].Index, 1)
+		assert.Less(t, changes[1].PercentChange, 0.0)
+	})
+	t.Run("IgnoresSmallShifts", func(t *testing.T) {
+		values := noisySeries(4, 0.1, segment{mean: 100, n: 50}, segment{mean: 102, n: 50})
+		assert.Empty(t, DetectChanges(values, DetectorOptions{}))
+		changes := DetectChanges(values, DetectorOptions{MinPercentChange: 1})
+		require.Len(t, changes, 1)
+		assert.InDelta(t, 50, changes[0].Index, 1)
+	})
+	t.Run("IgnoresOutlier", func(t *testing.T) {
+		values := noisySeries(5, 1, segment{mean: 100, n: 80})
+		values[40] = 300
+		assert.Empty(t, DetectChanges(values, DetectorOptions{}))
+	})
+}
@@ -0,0 +1,206 @@
+package perf
+
+import (
+	"context"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/mongodb/anser/bsonutil"
+	adb "github.com/mongodb/anser/db"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	Collection             = "perf_points"
+	ChangePointsCollection = "perf_change_points"
+)
+
+var (
+	IdKey         = bsonutil.MustHaveTag(Point{}, "ID")
+	SeriesIdKey   = bsonutil.MustHaveTag(Point{}, "SeriesID")
+	ProjectIdKey  = bsonutil.MustHaveTag(SeriesKey{}, "ProjectID")
+	VariantKey    = bsonutil.MustHaveTag(SeriesKey{}, "Variant")
+	TaskNameKey   = bsonutil.MustHaveTag(SeriesKey{}, "TaskName")
+	TestNameKey   = bsonutil.MustHaveTag(SeriesKey{}, "TestName")
+	MetricKey     = bsonutil.MustHaveTag(SeriesKey{}, "Metric")
+	OrderKey      = bsonutil.MustHaveTag(Point{}, "Order")
+	TaskIdKey     = bsonutil.MustHaveTag(Point{}, "TaskID")
+	MainlineKey   = bsonutil.MustHaveTag(Point{}, "Mainline")
+	CreatedAtKey  = bsonutil.MustHaveTag(Point{}, "CreatedAt")
+	DetectedAtKey = bsonutil.MustHaveTag(ChangePoint{}, "DetectedAt")
+)
+
+// FindOptions filter the points and change points returned by FindPoints and
+// FindChangePoints. Empty fields match everything.
+type FindOptions struct {
+	Variant  string
+	TaskName string
+	TestName string
+	Metric   string
+	// MainlineOnly excludes points from patches.
+	MainlineOnly bool
+	Limit        int
+}
+
+func (o FindOptions) filter(projectID string) bson.M {
+	filter := bson.M{ProjectIdKey: projectID}
+	if o.Variant != "" {
+		filter[VariantKey] = o.Variant
+	}
+	if o.TaskName != "" {
+		filter[TaskNameKey] = o.TaskName
+	}
+	if o.TestName != "" {
+		filter[TestNameKey] = o.TestName
+	}
+	if o.Metric != "" {
+		filter[MetricKey] = o.Metric
+	}
+	return filter
+}
+
+// Find gets every point matching the given query.
+func Find(ctx context.Context, query db.Q) ([]Point, error) {
+	points := []Point{}
+	if err := db.FindAllQ(ctx, Collection, query, &points); err != nil {
+		return nil, errors.Wrap(err, "finding perf points")
+	}
+	return points, nil
+}
+
+// FindOneId gets the point with the given ID.
+func FindOneId(ctx context.Context, id string) (*Point, error) {
+	p := &Point{}
+	err := db.FindOneQ(ctx, Collection, db.Query(bson.M{IdKey: id}), p)
+	if adb.ResultsNotFound(err) {
+		return nil, nil
+	}
+	return p, errors.Wrapf(err, "finding perf point '%s'", id)
+}
+
+// FindPoints gets the project's points, most recent order first.
+func FindPoints(ctx context.Context, projectID string, opts FindOptions) ([]Point, error) {
+	filter := opts.filter(projectID)
+	if opts.MainlineOnly {
+		filter[MainlineKey] = true
+	}
+	q := db.Query(filter).Sort([]string{"-" + OrderKey, SeriesIdKey})
+	if opts.Limit > 0 {
+		q = q.Limit(opts.Limit)
+	}
+	return Find(ctx, q)
+}
+
+// FindMainlineHistory gets up to the given number of the most recent mainline
+// points in the series, in order.
+func FindMainlineHistory(ctx context.Context, seriesID string, limit int) ([]Point, error) {
+	q := db.Query(bson.M{
+		SeriesIdKey: seriesID,
+		MainlineKey: true,
+	}).Sort([]string{"-" + OrderKey})
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	points, err := Find(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+		points[i], points[j] = points[j], points[i]
+	}
+	return points, nil
+}
+
+// FindProjectsWithNewPoints gets the IDs of the projects that have mainline
+// points created since the given time.
+func FindProjectsWithNewPoints(ctx context.Context, since time.Time) ([]string, error) {
+	res, err := evergreen.GetEnvironment().DB().Collection(Collection).Distinct(ctx, ProjectIdKey, bson.M{
+		MainlineKey:  true,
+		CreatedAtKey: bson.M{"$gte": since},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "finding projects with new perf points")
+	}
+	return distinctStrings(res), nil
+}
+
+// FindSeriesWithNewPoints gets the IDs of the project's series that have
+// mainline points created since the given time.
+func FindSeriesWithNewPoints(ctx context.Context, projectID string, since time.Time) ([]string, error) {
+	res, err := evergreen.GetEnvironment().DB().Collection(Collection).Distinct(ctx, SeriesIdKey, bson.M{
+		ProjectIdKey: projectID,
+		MainlineKey:  true,
+		CreatedAtKey: bson.M{"$gte": since},
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding series with new perf points in project '%s'", projectID)
+	}
+	return distinctStrings(res), nil
+}
+
+func distinctStrings(res []any) []string {
+	out := make([]string, 0, len(res))
+	for _, r := range res {
+		if s, ok := r.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// UpsertPoints saves the points, replacing any earlier points from the same
+// tasks.
+func UpsertPoints(ctx context.Context, points []Point) error {
+	if len(points) == 0 {
+		return nil
+	}
+	writes := make([]mongo.WriteModel, 0, len(points))
+	for _, p := range points {
+		writes = append(writes, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{IdKey: p.ID}).
+			SetReplacement(p).
+			SetUpsert(true))
+	}
+	_, err := evergreen.GetEnvironment().DB().Collection(Collection).BulkWrite(ctx, writes)
+	return errors.Wrap(err, "upserting perf points")
+}
+
+// FindChangePoints gets the project's change points, most recently detected
+// first.
+func FindChangePoints(ctx context.Context, projectID string, opts FindOptions) ([]ChangePoint, error) {
+	q := db.Query(opts.filter(projectID)).Sort([]string{"-" + DetectedAtKey, "-" + OrderKey})
+	if opts.Limit > 0 {
+		q = q.Limit(opts.Limit)
+	}
+	changePoints := []ChangePoint{}
+	if err := db.FindAllQ(ctx, ChangePointsCollection, q, &changePoints); err != nil {
+		return nil, errors.Wrap(err, "finding perf change points")
+	}
+	return changePoints, nil
+}
+
+// FindChangePointsForSeries gets all of the series' change points.
+func FindChangePointsForSeries(ctx context.Context, seriesID string) ([]ChangePoint, error) {
+	changePoints := []ChangePoint{}
+	q := db.Query(bson.M{SeriesIdKey: seriesID}).Sort([]string{OrderKey})
+	if err := db.FindAllQ(ctx, ChangePointsCollection, q, &changePoints); err != nil {
+		return nil, errors.Wrapf(err, "finding change points for series '%s'", seriesID)
+	}
+	return changePoints, nil
+}
+
+// Insert saves a new change point. It returns false without an error if the
+// change point was already saved.
+func (c *ChangePoint) Insert(ctx context.Context) (bool, error) {
+	err := db.Insert(ctx, ChangePointsCollection, c)
+	if db.IsDuplicateKey(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "inserting change point '%s'", c.ID)
+	}
+	return true, nil
+}
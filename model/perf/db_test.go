@@ -0,0 +1,120 @@
+package perf
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	testutil.Setup()
+}
+
+func TestPerfDB(t *testing.T) {
+	defer func() {
+		assert.NoError(t, db.ClearCollections(Collection, ChangePointsCollection))
+	}()
+	now := time.Now().Round(time.Millisecond)
+	key := SeriesKey{ProjectID: "project", Variant: "bv", TaskName: "task", TestName: "test", Metric: "ops_per_sec"}
+	makePoint := func(taskID string, order int, value float64, mainline bool) Point {
+		return Point{
+			ID:        MakePointID(key.ID(), taskID),
+			SeriesID:  key.ID(),
+			SeriesKey: key,
+			Value:     value,
+			Order:     order,
+			TaskID:    taskID,
+			Mainline:  mainline,
+			CreatedAt: now,
+		}
+	}
+
+	for tName, tCase := range map[string]func(t *testing.T){
+		"LaterExecutionReplacesPoint": func(t *testing.T) {
+			p := makePoint("t1", 1, 10, true)
+			require.NoError(t, UpsertPoints(t.Context(), []Point{p}))
+			p.Execution = 1
+			p.Value = 20
+			require.NoError(t, UpsertPoints(t.Context(), []Point{p}))
+
+			dbPoint, err := FindOneId(t.Context(), p.ID)
+			require.NoError(t, err)
+			require.NotNil(t, dbPoint)
+			assert.Equal(t, 1, dbPoint.Execution)
+			assert.Equal(t, 20.0, dbPoint.Value)
+			assert.Equal(t, key, dbPoint.SeriesKey)
+		},
+		"FindMainlineHistoryIsInOrder": func(t *testing.T) {
+			require.NoError(t, UpsertPoints(t.Context(), []Point{
+				makePoint("t3", 3, 30, true),
+				makePoint("t1", 1, 10, true),
+				makePoint("patch", 4, 40, false),
+				makePoint("t2", 2, 20, true),
+			}))
+
+			history, err := FindMainlineHistory(t.Context(), key.ID(), 2)
+			require.NoError(t, err)
+			require.Len(t, history, 2)
+			assert.Equal(t, "t2", history[0].TaskID)
+			assert.Equal(t, "t3", history[1].TaskID)
+
+			points, err := FindPoints(t.Context(), "project", FindOptions{Metric: "ops_per_sec"})
+			require.NoError(t, err)
+			require.Len(t, points, 4)
+			assert.Equal(t, "patch", points[0].TaskID)
+
+			points, err = FindPoints(t.Context(), "project", FindOptions{MainlineOnly: true, Limit: 1})
+			require.NoError(t, err)
+			require.Len(t, points, 1)
+			assert.Equal(t, "t3", points[0].TaskID)
+		},
+		"FindsSeriesWithNewPoints": func(t *testing.T) {
+			old := makePoint("t1", 1, 10, true)
+			old.CreatedAt = now.Add(-time.Hour)
+			patch := makePoint("patch", 2, 10, false)
+			patch.SeriesID = "other"
+			require.NoError(t, UpsertPoints(t.Context(), []Point{old, patch}))
+
+			projects, err := FindProjectsWithNewPoints(t.Context(), now.Add(-time.Minute))
+			require.NoError(t, err)
+			assert.Empty(t, projects)
+
+			require.NoError(t, UpsertPoints(t.Context(), []Point{makePoint("t2", 2, 10, true)}))
+			projects, err = FindProjectsWithNewPoints(t.Context(), now.Add(-time.Minute))
+			require.NoError(t, err)
+			assert.Equal(t, []string{"project"}, projects)
+
+			series, err := FindSeriesWithNewPoints(t.Context(), "project", now.Add(-time.Minute))
+			require.NoError(t, err)
+			assert.Equal(t, []string{key.ID()}, series)
+		},
+		"ChangePointIsInsertedOnce": func(t *testing.T) {
+			cp := NewChangePoint(makePoint("t5", 5, 50, true), Change{BeforeMean: 100, AfterMean: 50, PercentChange: -50}, now)
+			inserted, err := cp.Insert(t.Context())
+			require.NoError(t, err)
+			assert.True(t, inserted)
+			inserted, err = cp.Insert(t.Context())
+			require.NoError(t, err)
+			assert.False(t, inserted)
+
+			changePoints, err := FindChangePoints(t.Context(), "project", FindOptions{TestName: "test"})
+			require.NoError(t, err)
+			require.Len(t, changePoints, 1)
+			assert.Equal(t, -50.0, changePoints[0].PercentChange)
+			assert.Equal(t, "t5", changePoints[0].TaskID)
+
+			changePoints, err = FindChangePointsForSeries(t.Context(), key.ID())
+			require.NoError(t, err)
+			assert.Len(t, changePoints, 1)
+		},
+	} {
+		t.Run(tName, func(t *testing.T) {
+			require.NoError(t, db.ClearCollections(Collection, ChangePointsCollection))
+			tCase(t)
+		})
+	}
+}
@@ -0,0 +1,4 @@
+// Package perf stores the performance results that tasks report with
+// perf.send as time series per project, build variant, task, test and metric,
+// and finds the change points in those series' mainline history.
+package perf
@@ -0,0 +1,268 @@
+package perf
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/poplar"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+)
+
+// SeriesKey identifies one time series of performance results: the values of
+// a metric of a test, run with the same arguments, in a task of a build
+// variant.
+type SeriesKey struct {
+	ProjectID string           `bson:"project_id" json:"project_id"`
+	Variant   string           `bson:"variant" json:"variant"`
+	TaskName  string           `bson:"task_name" json:"task_name"`
+	TestName  string           `bson:"test_name" json:"test_name"`
+	Args      map[string]int32 `bson:"args,omitempty" json:"args,omitempty"`
+	Metric    string           `bson:"metric" json:"metric"`
+}
+
+// ID returns the ID shared by every point in the series.
+func (k SeriesKey) ID() string {
+	hash := sha1.New()
+	for _, s := range []string{k.ProjectID, k.Variant, k.TaskName, k.TestName, FormatArgs(k.Args), k.Metric} {
+		_, _ = io.WriteString(hash, s)
+		_, _ = io.WriteString(hash, "\x00")
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
+
+// FormatArgs returns the test arguments as comma-separated key=value pairs
+// sorted by key.
+func FormatArgs(args map[string]int32) string {
+	keys := make([]string, 0, len(args))
+	for k := range args {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%d", k, args[k]))
+	}
+	return strings.Join(pairs, ",")
+}
+
+// Point is the value of a series' metric from one task. A task's later
+// executions replace the values reported by its earlier ones.
+type Point struct {
+	ID        string `bson:"_id" json:"id"`
+	SeriesID  string `bson:"series_id" json:"series_id"`
+	SeriesKey `bson:",inline"`
+
+	MetricType string  `bson:"metric_type,omitempty" json:"metric_type,omitempty"`
+	Value      float64 `bson:"value" json:"value"`
+
+	// Order is the revision order number of the task's version, which orders
+	// the points of the series.
+	Order     int    `bson:"order" json:"order"`
+	Version   string `bson:"version" json:"version"`
+	TaskID    string `bson:"task_id" json:"task_id"`
+	Execution int    `bson:"execution" json:"execution"`
+	// Mainline is whether the task ran in a mainline commit version rather
+	// than a patch. Only mainline points are checked for change points.
+	Mainline bool `bson:"mainline" json:"mainline"`
+
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+}
+
+// MakePointID returns the ID of the series' point from the given task.
+func MakePointID(seriesID, taskID string) string {
+	return fmt.Sprintf("%s.%s", seriesID, taskID)
+}
+
+// PointsFromReport flattens a poplar report into the points of its tests'
+// metrics. Subtests are named after their parents, separated by a slash. The
+// report must already have the task's information filled in.
+func PointsFromReport(report *poplar.Report, now time.Time) ([]Point, error) {
+	catcher := grip.NewBasicCatcher()
+	catcher.NewWhen(report.Project == "", "report must have a project")
+	catcher.NewWhen(report.Variant == "", "report must have a build variant")
+	catcher.NewWhen(report.TaskName == "", "report must have a task name")
+	catcher.NewWhen(report.TaskID == "", "report must have a task ID")
+	if catcher.HasErrors() {
+		return nil, catcher.Resolve()
+	}
+
+	points := []Point{}
+	var addTest func(test poplar.Test, parentName string)
+	addTest = func(test poplar.Test, parentName string) {
+		if test.Info.TestName == "" {
+			catcher.Errorf("test '%s' is missing a name", test.ID)
+			return
+		}
+		name := test.Info.TestName
+		if parentName != "" {
+			name = parentName + "/" + name
+		}
+
+		createdAt := test.CompletedAt
+		if createdAt.IsZero() {
+			createdAt = now
+		}
+		for _, metric := range test.Metrics {
+			if metric.Name == "" {
+				catcher.Errorf("test '%s' has a metric without a name", name)
+				continue
+			}
+			value, err := metricValue(metric.Value)
+			if err != nil {
+				catcher.Wrapf(err, "metric '%s' of test '%s'", metric.Name, name)
+				continue
+			}
+
+			key := SeriesKey{
+				ProjectID: report.Project,
+				Variant:   report.Variant,
+				TaskName:  report.TaskName,
+				TestName:  name,
+				Args:      test.Info.Arguments,
+				Metric:    metric.Name,
+			}
+			seriesID := key.ID()
+			points = append(points, Point{
+				ID:         MakePointID(seriesID, report.TaskID),
+				SeriesID:   seriesID,
+				SeriesKey:  key,
+				MetricType: metric.Type,
+				Value:      value,
+				Order:      report.Order,
+				Version:    report.Version,
+				TaskID:     report.TaskID,
+				Execution:  report.Execution,
+				Mainline:   report.Mainline,
+				CreatedAt:  createdAt,
+			})
+		}
+
+		for _, subTest := range test.SubTests {
+			addTest(subTest, name)
+		}
+	}
+	for _, test := range report.Tests {
+		addTest(test, "")
+	}
+
+	return points, catcher.Resolve()
+}
+
+// metricValue converts the value of a poplar metric, which depends on the
+// format the report was read from, to a float.
+func metricValue(value any) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case json.Number:
+		f, err := v.Float64()
+		return f, errors.Wrapf(err, "parsing value '%s'", v)
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, errors.Wrapf(err, "parsing value '%s'", v)
+	default:
+		return 0, errors.Errorf("value of type %T is not a number", value)
+	}
+}
+
+// Poplar metric types whose direction of improvement is known. Any other type
+// could be better either higher or lower.
+const (
+	MetricTypeThroughput       = "THROUGHPUT"
+	MetricTypeLatency          = "LATENCY"
+	metricTypePercentilePrefix = "PERCENTILE"
+)
+
+// HigherIsBetter returns whether higher values of a metric of the given poplar
+// type are better, and whether that's known for the type. Throughputs are
+// better higher, and latencies and their percentiles are better lower.
+func HigherIsBetter(metricType string) (higherIsBetter bool, known bool) {
+	metricType = strings.ToUpper(metricType)
+	switch {
+	case metricType == MetricTypeThroughput:
+		return true, true
+	case metricType == MetricTypeLatency, strings.HasPrefix(metricType, metricTypePercentilePrefix):
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+// IsRegression returns whether a change in the mean of a metric of the given
+// poplar type is in the direction of worse performance. It's false if the
+// metric type doesn't say which direction is worse.
+func IsRegression(metricType string, percentChange float64) bool {
+	higherIsBetter, known := HigherIsBetter(metricType)
+	if !known {
+		return false
+	}
+	if higherIsBetter {
+		return percentChange < 0
+	}
+	return percentChange > 0
+}
+
+// ChangePoint is a point in the mainline history of a series where its values
+// shifted, which may be a performance regression.
+type ChangePoint struct {
+	ID        string `bson:"_id" json:"id"`
+	SeriesID  string `bson:"series_id" json:"series_id"`
+	SeriesKey `bson:",inline"`
+
+	// Order, Version, TaskID and Execution identify the first point after
+	// the shift.
+	Order     int    `bson:"order" json:"order"`
+	Version   string `bson:"version" json:"version"`
+	TaskID    string `bson:"task_id" json:"task_id"`
+	Execution int    `bson:"execution" json:"execution"`
+
+	// BeforeMean and AfterMean are the means of the values in the segments
+	// of the history before and after the shift.
+	BeforeMean float64 `bson:"before_mean" json:"before_mean"`
+	AfterMean  float64 `bson:"after_mean" json:"after_mean"`
+	// PercentChange is how much the mean changed, relative to its value
+	// before the shift.
+	PercentChange float64 `bson:"percent_change" json:"percent_change"`
+	// MetricType is the poplar type of the metric at the shift, which
+	// determines whether the shift is a regression.
+	MetricType string `bson:"metric_type,omitempty" json:"metric_type,omitempty"`
+	// Regression is whether the shift made performance worse.
+	Regression bool `bson:"regression" json:"regression"`
+
+	DetectedAt time.Time `bson:"detected_at" json:"detected_at"`
+}
+
+// NewChangePoint returns the change point found at the given point.
+func NewChangePoint(p Point, change Change, now time.Time) ChangePoint {
+	return ChangePoint{
+		ID:            fmt.Sprintf("%s.%d", p.SeriesID, p.Order),
+		SeriesID:      p.SeriesID,
+		SeriesKey:     p.SeriesKey,
+		Order:         p.Order,
+		Version:       p.Version,
+		TaskID:        p.TaskID,
+		Execution:     p.Execution,
+		BeforeMean:    change.BeforeMean,
+		AfterMean:     change.AfterMean,
+		PercentChange: change.PercentChange,
+		MetricType:    p.MetricType,
+		Regression:    IsRegression(p.MetricType, change.PercentChange),
+		DetectedAt:    now,
+	}
+}
@@ -0,0 +1,124 @@
+package perf
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/poplar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeriesKeyID(t *testing.T) {
+	key := SeriesKey{ProjectID: "project", Variant: "bv", TaskName: "task", TestName: "test", Metric: "ops_per_sec"}
+	assert.Equal(t, key.ID(), key.ID())
+
+	withArgs := key
+	withArgs.Args = map[string]int32{"threads": 8, "size": 1}
+	assert.NotEqual(t, key.ID(), withArgs.ID())
+	assert.Equal(t, "size=1,threads=8", FormatArgs(withArgs.Args))
+
+	otherMetric := key
+	otherMetric.Metric = "latency"
+	assert.NotEqual(t, key.ID(), otherMetric.ID())
+}
+
+func TestPointsFromReport(t *testing.T) {
+	now := time.Now().Round(time.Millisecond)
+	completed := now.Add(-time.Minute)
+	report := &poplar.Report{
+		Project:   "project",
+		Version:   "version",
+		Order:     12,
+		Variant:   "bv",
+		TaskName:  "task",
+		TaskID:    "task_id",
+		Execution: 1,
+		Mainline:  true,
+		Tests: []poplar.Test{
+			{
+				Info:        poplar.TestInfo{TestName: "insert", Arguments: map[string]int32{"threads": 4}},
+				CompletedAt: completed,
+				Metrics: []poplar.TestMetrics{
+					{Name: "ops_per_sec", Type: "THROUGHPUT", Value: 1200.5},
+					{Name: "count", Value: int64(7)},
+					{Name: "p99", Value: json.Number("3.5")},
+				},
+				SubTests: []poplar.Test{
+					{
+						Info:    poplar.TestInfo{TestName: "warmup"},
+						Metrics: []poplar.TestMetrics{{Name: "ops_per_sec", Value: 10}},
+					},
+				},
+			},
+		},
+	}
+
+	t.Run("FlattensTestsAndSubtests", func(t *testing.T) {
+		points, err := PointsFromReport(report, now)
+		require.NoError(t, err)
+		require.Len(t, points, 4)
+
+		assert.Equal(t, "insert", points[0].TestName)
+		assert.Equal(t, "ops_per_sec", points[0].Metric)
+		assert.Equal(t, "THROUGHPUT", points[0].MetricType)
+		assert.Equal(t, 1200.5, points[0].Value)
+		assert.Equal(t, map[string]int32{"threads": 4}, points[0].Args)
+		assert.Equal(t, 12, points[0].Order)
+		assert.Equal(t, 1, points[0].Execution)
+		assert.True(t, points[0].Mainline)
+		assert.True(t, completed.Equal(points[0].CreatedAt))
+		assert.Equal(t, points[0].SeriesKey.ID(), points[0].SeriesID)
+		assert.Equal(t, MakePointID(points[0].SeriesID, "task_id"), points[0].ID)
+
+		assert.Equal(t, 7.0, points[1].Value)
+		assert.Equal(t, 3.5, points[2].Value)
+
+		assert.Equal(t, "insert/warmup", points[3].TestName)
+		assert.Equal(t, 10.0, points[3].Value)
+		assert.True(t, now.Equal(points[3].CreatedAt))
+		assert.NotEqual(t, points[0].SeriesID, points[3].SeriesID)
+	})
+	t.Run("RejectsNonNumericValues", func(t *testing.T) {
+		bad := *report
+		bad.Tests = []poplar.Test{{
+			Info:    poplar.TestInfo{TestName: "test"},
+			Metrics: []poplar.TestMetrics{{Name: "m", Value: []int{1}}},
+		}}
+		_, err := PointsFromReport(&bad, now)
+		assert.Error(t, err)
+	})
+	t.Run("RequiresTaskInformation", func(t *testing.T) {
+		_, err := PointsFromReport(&poplar.Report{Tests: report.Tests}, now)
+		assert.Error(t, err)
+	})
+}
+
+func TestIsRegression(t *testing.T) {
+	for tName, tCase := range map[string]struct {
+		metricType    string
+		percentChange float64
+		expected      bool
+	}{
+		"ThroughputDecrease":  {metricType: MetricTypeThroughput, percentChange: -10, expected: true},
+		"ThroughputIncrease":  {metricType: MetricTypeThroughput, percentChange: 10},
+		"LatencyIncrease":     {metricType: MetricTypeLatency, percentChange: 10, expected: true},
+		"LatencyDecrease":     {metricType: MetricTypeLatency, percentChange: -10},
+		"PercentileIncrease":  {metricType: "PERCENTILE_99", percentChange: 10, expected: true},
+		"LowercaseThroughput": {metricType: "throughput", percentChange: -10, expected: true},
+		"UnknownType":         {metricType: "MEAN", percentChange: 10},
+		"NoType":              {percentChange: -10},
+	} {
+		t.Run(tName, func(t *testing.T) {
+			assert.Equal(t, tCase.expected, IsRegression(tCase.metricType, tCase.percentChange))
+		})
+	}
+
+	t.Run("NewChangePoint", func(t *testing.T) {
+		p := Point{SeriesID: "series", MetricType: MetricTypeThroughput, Order: 5}
+		cp := NewChangePoint(p, Change{BeforeMean: 100, AfterMean: 80, PercentChange: -20}, time.Now())
+		assert.Equal(t, MetricTypeThroughput, cp.MetricType)
+		assert.True(t, cp.Regression)
+	})
+}
@@ -0,0 +1,170 @@
+package pipeline
+
+import (
+	"context"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/mongodb/anser/bsonutil"
+	adb "github.com/mongodb/anser/db"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const Collection = "pipeline_runs"
+
+var (
+	IdKey                = bsonutil.MustHaveTag(Run{}, "ID")
+	ProjectIdKey         = bsonutil.MustHaveTag(Run{}, "ProjectID")
+	DefinitionIdKey      = bsonutil.MustHaveTag(Run{}, "DefinitionID")
+	MatchKeyKey          = bsonutil.MustHaveTag(Run{}, "MatchKey")
+	StatusKey            = bsonutil.MustHaveTag(Run{}, "Status")
+	UpstreamsKey         = bsonutil.MustHaveTag(Run{}, "Upstreams")
+	DownstreamVersionKey = bsonutil.MustHaveTag(Run{}, "DownstreamVersion")
+	ErrorKey             = bsonutil.MustHaveTag(Run{}, "Error")
+	CreateTimeKey        = bsonutil.MustHaveTag(Run{}, "CreateTime")
+	UpdateTimeKey        = bsonutil.MustHaveTag(Run{}, "UpdateTime")
+	TriggerTimeKey       = bsonutil.MustHaveTag(Run{}, "TriggerTime")
+
+	UpstreamProjectIdKey = bsonutil.MustHaveTag(Upstream{}, "ProjectID")
+)
+
+// Find gets every run matching the given query.
+func Find(ctx context.Context, query db.Q) ([]Run, error) {
+	runs := []Run{}
+	if err := db.FindAllQ(ctx, Collection, query, &runs); err != nil {
+		return nil, errors.Wrap(err, "finding pipeline runs")
+	}
+	return runs, nil
+}
+
+// FindOneId gets the run with the given ID.
+func FindOneId(ctx context.Context, id string) (*Run, error) {
+	r := &Run{}
+	err := db.FindOneQ(ctx, Collection, db.Query(bson.M{IdKey: id}), r)
+	if adb.ResultsNotFound(err) {
+		return nil, nil
+	}
+	return r, errors.Wrapf(err, "finding pipeline run '%s'", id)
+}
+
+// FindByProject gets the runs of the project's pipelines, most recently
+// updated first. If the definition ID is set, only that pipeline's runs are
+// returned.
+func FindByProject(ctx context.Context, projectID, definitionID string, limit int) ([]Run, error) {
+	filter := bson.M{ProjectIdKey: projectID}
+	if definitionID != "" {
+		filter[DefinitionIdKey] = definitionID
+	}
+	q := db.Query(filter).Sort([]string{"-" + UpdateTimeKey})
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	return Find(ctx, q)
+}
+
+// SetUpstream records the state of an upstream project's version in the
+// pipeline's run for the match key, creating the run with every upstream
+// project pending if it doesn't exist yet, and returns the updated run.
+func SetUpstream(ctx context.Context, projectID, definitionID, matchKey string, upstreamProjects []string, upstream Upstream, now time.Time) (*Run, error) {
+	id := MakeRunID(projectID, definitionID, matchKey)
+	pending := make([]Upstream, 0, len(upstreamProjects))
+	for _, p := range upstreamProjects {
+		pending = append(pending, Upstream{ProjectID: p, Status: UpstreamStatusPending})
+	}
+	_, err := db.Upsert(ctx, Collection, bson.M{IdKey: id}, bson.M{
+		"$setOnInsert": bson.M{
+			ProjectIdKey:    projectID,
+			DefinitionIdKey: definitionID,
+			MatchKeyKey:     matchKey,
+			StatusKey:       RunStatusWaiting,
+			UpstreamsKey:    pending,
+			CreateTimeKey:   now,
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "creating pipeline run '%s'", id)
+	}
+
+	// Each upstream project's state is set on its own so that versions of
+	// different upstream projects finishing at the same time don't overwrite
+	// each other's.
+	err = db.Update(ctx, Collection, bson.M{
+		IdKey: id,
+		bsonutil.GetDottedKeyName(UpstreamsKey, UpstreamProjectIdKey): upstream.ProjectID,
+	}, bson.M{
+		"$set": bson.M{
+			bsonutil.GetDottedKeyName(UpstreamsKey, "$"): upstream,
+			UpdateTimeKey: now,
+		},
+	})
+	if adb.ResultsNotFound(err) {
+		// The upstream project was added to the pipeline after the run was
+		// created.
+		err = db.Update(ctx, Collection, bson.M{
+			IdKey: id,
+			bsonutil.GetDottedKeyName(UpstreamsKey, UpstreamProjectIdKey): bson.M{"$ne": upstream.ProjectID},
+		}, bson.M{
+			"$push": bson.M{UpstreamsKey: upstream},
+			"$set":  bson.M{UpdateTimeKey: now},
+		})
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "setting upstream project '%s' in pipeline run '%s'", upstream.ProjectID, id)
+	}
+
+	run, err := FindOneId(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if run == nil {
+		return nil, errors.Errorf("pipeline run '%s' not found", id)
+	}
+	return run, nil
+}
+
+// Claim marks a run that's waiting or failed as triggered. It returns false
+// if the run was already claimed, so that only one caller creates its
+// downstream version.
+func Claim(ctx context.Context, id string, now time.Time) (bool, error) {
+	err := db.Update(ctx, Collection, bson.M{
+		IdKey:     id,
+		StatusKey: bson.M{"$in": []string{RunStatusWaiting, RunStatusFailed}},
+	}, bson.M{
+		"$set": bson.M{
+			StatusKey:      RunStatusTriggered,
+			TriggerTimeKey: now,
+			UpdateTimeKey:  now,
+		},
+		"$unset": bson.M{ErrorKey: 1},
+	})
+	if adb.ResultsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "claiming pipeline run '%s'", id)
+	}
+	return true, nil
+}
+
+// SetDownstreamVersion records the version that the triggered run created.
+func SetDownstreamVersion(ctx context.Context, id, versionID string, now time.Time) error {
+	return errors.Wrapf(db.UpdateId(ctx, Collection, id, bson.M{
+		"$set": bson.M{
+			DownstreamVersionKey: versionID,
+			UpdateTimeKey:        now,
+		},
+	}), "setting downstream version of pipeline run '%s'", id)
+}
+
+// SetFailed marks the run as failed because its downstream version couldn't
+// be created.
+func SetFailed(ctx context.Context, id string, runErr error, now time.Time) error {
+	return errors.Wrapf(db.UpdateId(ctx, Collection, id, bson.M{
+		"$set": bson.M{
+			StatusKey:     RunStatusFailed,
+			ErrorKey:      runErr.Error(),
+			UpdateTimeKey: now,
+		},
+	}), "marking pipeline run '%s' failed", id)
+}
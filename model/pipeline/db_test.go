@@ -0,0 +1,138 @@
+package pipeline
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	testutil.Setup()
+}
+
+func TestPipelineRunDB(t *testing.T) {
+	defer func() {
+		assert.NoError(t, db.ClearCollections(Collection))
+	}()
+	now := time.Now().Round(time.Millisecond)
+	upstreams := []string{"server", "driver"}
+
+	for tName, tCase := range map[string]func(t *testing.T){
+		"SetUpstreamCreatesRunWithPendingUpstreams": func(t *testing.T) {
+			run, err := SetUpstream(t.Context(), "integration", "def", "abc123", upstreams, Upstream{
+				ProjectID:  "server",
+				Status:     UpstreamStatusSucceeded,
+				VersionID:  "server_v1",
+				Revision:   "abc123",
+				UpdateTime: now,
+			}, now)
+			require.NoError(t, err)
+			assert.Equal(t, MakeRunID("integration", "def", "abc123"), run.ID)
+			assert.Equal(t, RunStatusWaiting, run.Status)
+			assert.Equal(t, "abc123", run.MatchKey)
+			require.Len(t, run.Upstreams, 2)
+			require.NotNil(t, run.GetUpstream("server"))
+			assert.Equal(t, UpstreamStatusSucceeded, run.GetUpstream("server").Status)
+			assert.Equal(t, "server_v1", run.GetUpstream("server").VersionID)
+			require.NotNil(t, run.GetUpstream("driver"))
+			assert.Equal(t, UpstreamStatusPending, run.GetUpstream("driver").Status)
+			assert.False(t, run.Ready(upstreams))
+		},
+		"SetUpstreamUpdatesExistingRun": func(t *testing.T) {
+			_, err := SetUpstream(t.Context(), "integration", "def", "abc123", upstreams, Upstream{ProjectID: "server", Status: UpstreamStatusSucceeded}, now)
+			require.NoError(t, err)
+			_, err = SetUpstream(t.Context(), "integration", "def", "abc123", upstreams, Upstream{ProjectID: "driver", Status: UpstreamStatusFailed}, now)
+			require.NoError(t, err)
+			run, err := SetUpstream(t.Context(), "integration", "def", "abc123", upstreams, Upstream{ProjectID: "driver", Status: UpstreamStatusSucceeded}, now.Add(time.Minute))
+			require.NoError(t, err)
+
+			require.Len(t, run.Upstreams, 2)
+			assert.True(t, run.Ready(upstreams))
+			assert.True(t, now.Add(time.Minute).Equal(run.UpdateTime))
+		},
+		"SetUpstreamAddsNewUpstream": func(t *testing.T) {
+			_, err := SetUpstream(t.Context(), "integration", "def", "abc123", upstreams, Upstream{ProjectID: "server", Status: UpstreamStatusSucceeded}, now)
+			require.NoError(t, err)
+			run, err := SetUpstream(t.Context(), "integration", "def", "abc123", append(upstreams, "tools"), Upstream{ProjectID: "tools", Status: UpstreamStatusSucceeded}, now)
+			require.NoError(t, err)
+
+			require.Len(t, run.Upstreams, 3)
+			require.NotNil(t, run.GetUpstream("tools"))
+			assert.Equal(t, UpstreamStatusSucceeded, run.GetUpstream("tools").Status)
+		},
+		"ClaimOnlySucceedsOnce": func(t *testing.T) {
+			run, err := SetUpstream(t.Context(), "integration", "def", "abc123", upstreams, Upstream{ProjectID: "server", Status: UpstreamStatusSucceeded}, now)
+			require.NoError(t, err)
+
+			claimed, err := Claim(t.Context(), run.ID, now)
+			require.NoError(t, err)
+			assert.True(t, claimed)
+			claimed, err = Claim(t.Context(), run.ID, now)
+			require.NoError(t, err)
+			assert.False(t, claimed)
+
+			require.NoError(t, SetDownstreamVersion(t.Context(), run.ID, "integration_v1", now))
+			dbRun, err := FindOneId(t.Context(), run.ID)
+			require.NoError(t, err)
+			require.NotNil(t, dbRun)
+			assert.Equal(t, RunStatusTriggered, dbRun.Status)
+			assert.Equal(t, "integration_v1", dbRun.DownstreamVersion)
+			assert.True(t, now.Equal(dbRun.TriggerTime))
+		},
+		"FailedRunCanBeClaimedAgain": func(t *testing.T) {
+			run, err := SetUpstream(t.Context(), "integration", "def", "abc123", upstreams, Upstream{ProjectID: "server", Status: UpstreamStatusSucceeded}, now)
+			require.NoError(t, err)
+			claimed, err := Claim(t.Context(), run.ID, now)
+			require.NoError(t, err)
+			require.True(t, claimed)
+			require.NoError(t, SetFailed(t.Context(), run.ID, errors.New("config file not found"), now))
+
+			dbRun, err := FindOneId(t.Context(), run.ID)
+			require.NoError(t, err)
+			require.NotNil(t, dbRun)
+			assert.Equal(t, RunStatusFailed, dbRun.Status)
+			assert.Equal(t, "config file not found", dbRun.Error)
+
+			claimed, err = Claim(t.Context(), run.ID, now)
+			require.NoError(t, err)
+			assert.True(t, claimed)
+			dbRun, err = FindOneId(t.Context(), run.ID)
+			require.NoError(t, err)
+			require.NotNil(t, dbRun)
+			assert.Empty(t, dbRun.Error)
+		},
+		"FindByProject": func(t *testing.T) {
+			_, err := SetUpstream(t.Context(), "integration", "def", "abc123", upstreams, Upstream{ProjectID: "server"}, now)
+			require.NoError(t, err)
+			_, err = SetUpstream(t.Context(), "integration", "def", "def456", upstreams, Upstream{ProjectID: "server"}, now.Add(time.Minute))
+			require.NoError(t, err)
+			_, err = SetUpstream(t.Context(), "integration", "other_def", "abc123", upstreams, Upstream{ProjectID: "server"}, now)
+			require.NoError(t, err)
+			_, err = SetUpstream(t.Context(), "other_project", "def", "abc123", upstreams, Upstream{ProjectID: "server"}, now)
+			require.NoError(t, err)
+
+			runs, err := FindByProject(t.Context(), "integration", "", 0)
+			require.NoError(t, err)
+			assert.Len(t, runs, 3)
+
+			runs, err = FindByProject(t.Context(), "integration", "def", 0)
+			require.NoError(t, err)
+			require.Len(t, runs, 2)
+			assert.Equal(t, "def456", runs[0].MatchKey, "most recently updated run should be first")
+
+			runs, err = FindByProject(t.Context(), "integration", "def", 1)
+			require.NoError(t, err)
+			assert.Len(t, runs, 1)
+		},
+	} {
+		t.Run(tName, func(t *testing.T) {
+			require.NoError(t, db.ClearCollections(Collection))
+			tCase(t)
+		})
+	}
+}
@@ -0,0 +1,4 @@
+// Package pipeline tracks the runs of fan-in pipelines, which create a
+// version of a downstream project once every one of the pipeline's upstream
+// projects has succeeded for the same revision, git tag or manifest.
+package pipeline
@@ -0,0 +1,95 @@
+package pipeline
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	// RunStatusWaiting means the run is waiting on some of its upstream
+	// projects to succeed.
+	RunStatusWaiting = "waiting"
+	// RunStatusTriggered means the run's downstream version was created.
+	RunStatusTriggered = "triggered"
+	// RunStatusFailed means the run's downstream version couldn't be created.
+	// It's tried again the next time one of its upstream versions succeeds.
+	RunStatusFailed = "failed"
+)
+
+const (
+	UpstreamStatusPending   = "pending"
+	UpstreamStatusSucceeded = "succeeded"
+	UpstreamStatusFailed    = "failed"
+)
+
+// Run is a pipeline's progress toward creating one downstream version, which
+// it does once every upstream project has succeeded for the run's match key.
+type Run struct {
+	ID string `bson:"_id" json:"id"`
+	// ProjectID is the downstream project that the pipeline is defined in.
+	ProjectID    string `bson:"project_id" json:"project_id"`
+	DefinitionID string `bson:"definition_id" json:"definition_id"`
+	// MatchKey is the revision, git tag or manifest revision shared by the
+	// upstream versions in the run.
+	MatchKey  string     `bson:"match_key" json:"match_key"`
+	Status    string     `bson:"status" json:"status"`
+	Upstreams []Upstream `bson:"upstreams" json:"upstreams"`
+
+	// DownstreamVersion is the version created once the run was triggered.
+	DownstreamVersion string `bson:"downstream_version,omitempty" json:"downstream_version,omitempty"`
+	// Error is why the downstream version couldn't be created.
+	Error string `bson:"error,omitempty" json:"error,omitempty"`
+
+	CreateTime  time.Time `bson:"create_time" json:"create_time"`
+	UpdateTime  time.Time `bson:"update_time" json:"update_time"`
+	TriggerTime time.Time `bson:"trigger_time,omitempty" json:"trigger_time,omitempty"`
+}
+
+// Upstream is the state of an upstream project's most recent version in a
+// run.
+type Upstream struct {
+	ProjectID  string    `bson:"project_id" json:"project_id"`
+	Status     string    `bson:"status" json:"status"`
+	VersionID  string    `bson:"version_id,omitempty" json:"version_id,omitempty"`
+	Revision   string    `bson:"revision,omitempty" json:"revision,omitempty"`
+	UpdateTime time.Time `bson:"update_time,omitempty" json:"update_time,omitempty"`
+}
+
+// MakeRunID returns the ID of the pipeline's run for the given match key.
+func MakeRunID(projectID, definitionID, matchKey string) string {
+	hash := sha1.New()
+	for _, s := range []string{projectID, definitionID, matchKey} {
+		_, _ = io.WriteString(hash, s)
+		_, _ = io.WriteString(hash, "\x00")
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
+
+// GetUpstream returns the state of the given upstream project in the run, or
+// nil if the run has none for it.
+func (r *Run) GetUpstream(projectID string) *Upstream {
+	for i := range r.Upstreams {
+		if r.Upstreams[i].ProjectID == projectID {
+			return &r.Upstreams[i]
+		}
+	}
+	return nil
+}
+
+// Ready returns whether the run hasn't been triggered yet and every one of the
+// given upstream projects has succeeded in it, so its downstream version can
+// be created.
+func (r *Run) Ready(upstreamProjects []string) bool {
+	if r.Status == RunStatusTriggered || len(upstreamProjects) == 0 {
+		return false
+	}
+	for _, projectID := range upstreamProjects {
+		u := r.GetUpstream(projectID)
+		if u == nil || u.Status != UpstreamStatusSucceeded {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,65 @@
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMakeRunID(t *testing.T) {
+	id := MakeRunID("project", "definition", "abc123")
+	assert.Equal(t, id, MakeRunID("project", "definition", "abc123"))
+	assert.NotEqual(t, id, MakeRunID("project", "definition", "def456"))
+	assert.NotEqual(t, id, MakeRunID("project", "other_definition", "abc123"))
+	assert.NotEqual(t, MakeRunID("ab", "c", "key"), MakeRunID("a", "bc", "key"), "fields should not run together")
+}
+
+func TestRunReady(t *testing.T) {
+	upstreams := []string{"server", "driver"}
+	for tName, tCase := range map[string]struct {
+		run      Run
+		expected bool
+	}{
+		"AllSucceeded": {
+			run: Run{Status: RunStatusWaiting, Upstreams: []Upstream{
+				{ProjectID: "server", Status: UpstreamStatusSucceeded},
+				{ProjectID: "driver", Status: UpstreamStatusSucceeded},
+			}},
+			expected: true,
+		},
+		"SomePending": {
+			run: Run{Status: RunStatusWaiting, Upstreams: []Upstream{
+				{ProjectID: "server", Status: UpstreamStatusSucceeded},
+				{ProjectID: "driver", Status: UpstreamStatusPending},
+			}},
+		},
+		"SomeFailed": {
+			run: Run{Status: RunStatusWaiting, Upstreams: []Upstream{
+				{ProjectID: "server", Status: UpstreamStatusSucceeded},
+				{ProjectID: "driver", Status: UpstreamStatusFailed},
+			}},
+		},
+		"MissingUpstream": {
+			run: Run{Status: RunStatusWaiting, Upstreams: []Upstream{
+				{ProjectID: "server", Status: UpstreamStatusSucceeded},
+			}},
+		},
+		"AlreadyTriggered": {
+			run: Run{Status: RunStatusTriggered, Upstreams: []Upstream{
+				{ProjectID: "server", Status: UpstreamStatusSucceeded},
+				{ProjectID: "driver", Status: UpstreamStatusSucceeded},
+			}},
+		},
+		"RetriesFailedRun": {
+			run: Run{Status: RunStatusFailed, Upstreams: []Upstream{
+				{ProjectID: "server", Status: UpstreamStatusSucceeded},
+				{ProjectID: "driver", Status: UpstreamStatusSucceeded},
+			}},
+			expected: true,
+		},
+	} {
+		t.Run(tName, func(t *testing.T) {
+			assert.Equal(t, tCase.expected, tCase.run.Ready(upstreams))
+		})
+	}
+}
@@ -1172,45 +1172,49 @@ func PopulateExpansions(ctx context.Context, t *task.Task, h *host.Host, knownHo
 		expansions.Put("trigger_event_identifier", t.TriggerID)
 		expansions.Put("trigger_event_type", t.TriggerType)
 		expansions.Put("trigger_id", t.TriggerEvent)
-		var upstreamProjectID string
-		if t.TriggerType == ProjectTriggerLevelTask {
-			var upstreamTask *task.Task
-			upstreamTask, err = task.FindOneId(ctx, t.TriggerID)
-			if err != nil {
-				return nil, errors.Wrap(err, "finding task")
-			}
-			if upstreamTask == nil {
-				return nil, errors.New("upstream task not found")
+		// Pipelines wait on more than one upstream project, so there's no single
+		// upstream to describe.
+		if t.TriggerType != ProjectTriggerLevelPipeline {
+			var upstreamProjectID string
+			if t.TriggerType == ProjectTriggerLevelTask {
+				var upstreamTask *task.Task
+				upstreamTask, err = task.FindOneId(ctx, t.TriggerID)
+				if err != nil {
+					return nil, errors.Wrap(err, "finding task")
+				}
+				if upstreamTask == nil {
+					return nil, errors.New("upstream task not found")
+				}
+				expansions.Put("trigger_status", upstreamTask.Status)
+				expansions.Put("trigger_revision", upstreamTask.Revision)
+				expansions.Put("trigger_version", upstreamTask.Version)
+				upstreamProjectID = upstreamTask.Project
+			} else if t.TriggerType == ProjectTriggerLevelBuild {
+				var upstreamBuild *build.Build
+				upstreamBuild, err = build.FindOneId(ctx, t.TriggerID)
+				if err != nil {
+					return nil, errors.Wrap(err, "finding build")
+				}
+				if upstreamBuild == nil {
+					return nil, errors.New("upstream build not found")
+				}
+				expansions.Put("trigger_status", upstreamBuild.Status)
+				expansions.Put("trigger_revision", upstreamBuild.Revision)
+				expansions.Put("trigger_version", upstreamBuild.Version)
+				upstreamProjectID = upstreamBuild.Project
 			}
-			expansions.Put("trigger_status", upstreamTask.Status)
-			expansions.Put("trigger_revision", upstreamTask.Revision)
-			expansions.Put("trigger_version", upstreamTask.Version)
-			upstreamProjectID = upstreamTask.Project
-		} else if t.TriggerType == ProjectTriggerLevelBuild {
-			var upstreamBuild *build.Build
-			upstreamBuild, err = build.FindOneId(ctx, t.TriggerID)
+			var upstreamProject *ProjectRef
+			upstreamProject, err = FindBranchProjectRef(ctx, upstreamProjectID)
 			if err != nil {
-				return nil, errors.Wrap(err, "finding build")
+				return nil, errors.Wrap(err, "finding project")
 			}
-			if upstreamBuild == nil {
-				return nil, errors.New("upstream build not found")
+			if upstreamProject == nil {
+				return nil, errors.Errorf("upstream project '%s' not found", t.Project)
 			}
-			expansions.Put("trigger_status", upstreamBuild.Status)
-			expansions.Put("trigger_revision", upstreamBuild.Revision)
-			expansions.Put("trigger_version", upstreamBuild.Version)
-			upstreamProjectID = upstreamBuild.Project
-		}
-		var upstreamProject *ProjectRef
-		upstreamProject, err = FindBranchProjectRef(ctx, upstreamProjectID)
-		if err != nil {
-			return nil, errors.Wrap(err, "finding project")
-		}
-		if upstreamProject == nil {
-			return nil, errors.Errorf("upstream project '%s' not found", t.Project)
+			expansions.Put("trigger_repo_owner", upstreamProject.Owner)
+			expansions.Put("trigger_repo_name", upstreamProject.Repo)
+			expansions.Put("trigger_branch", upstreamProject.Branch)
 		}
-		expansions.Put("trigger_repo_owner", upstreamProject.Owner)
-		expansions.Put("trigger_repo_name", upstreamProject.Repo)
-		expansions.Put("trigger_branch", upstreamProject.Branch)
 	}
 
 	v, err := VersionFindOneId(ctx, t.Version)
@@ -0,0 +1,381 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/cost"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// CostBudgetPeriod is the period of time over which a cost budget applies.
+type CostBudgetPeriod string
+
+const (
+	// CostBudgetPeriodDaily is a budget for the current UTC day.
+	CostBudgetPeriodDaily CostBudgetPeriod = "daily"
+	// CostBudgetPeriodMonthly is a budget for the current UTC month.
+	CostBudgetPeriodMonthly CostBudgetPeriod = "monthly"
+)
+
+// Start returns the start of the period containing the given time.
+func (p CostBudgetPeriod) Start(t time.Time) time.Time {
+	t = t.UTC()
+	if p == CostBudgetPeriodMonthly {
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// CostBudgetSettings configures the cost budgets of a project. The spend
+// counted against a budget is the actual cost of the project's versions that
+// were created during the budget's period. Budgets are in dollars, and a
+// budget of 0 is unlimited.
+type CostBudgetSettings struct {
+	// DailyBudget is the budget for all of the project's versions in a day.
+	DailyBudget float64 `bson:"daily_budget,omitempty" json:"daily_budget,omitempty" yaml:"daily_budget,omitempty"`
+	// MonthlyBudget is the budget for all of the project's versions in a
+	// month.
+	MonthlyBudget float64 `bson:"monthly_budget,omitempty" json:"monthly_budget,omitempty" yaml:"monthly_budget,omitempty"`
+	// RequesterBudgets are budgets for the project's versions with a
+	// particular requester. They apply in addition to the project-wide
+	// budgets.
+	RequesterBudgets []RequesterCostBudget `bson:"requester_budgets,omitempty" json:"requester_budgets,omitempty" yaml:"requester_budgets,omitempty"`
+	// HardStop, if true, prevents patch tasks from being activated once any
+	// budget that applies to them is exhausted.
+	HardStop bool `bson:"hard_stop,omitempty" json:"hard_stop,omitempty" yaml:"hard_stop,omitempty"`
+	// PatchConfirmationThreshold, if set, is the estimated cost in dollars
+	// above which a patch must be explicitly confirmed before it's
+	// scheduled.
+	PatchConfirmationThreshold float64 `bson:"patch_confirmation_threshold,omitempty" json:"patch_confirmation_threshold,omitempty" yaml:"patch_confirmation_threshold,omitempty"`
+}
+
+// RequesterCostBudget is a cost budget for the versions with a particular
+// requester.
+type RequesterCostBudget struct {
+	// Requester is the requester of the versions the budget applies to.
+	Requester string `bson:"requester" json:"requester" yaml:"requester"`
+	// DailyBudget is the budget for the versions in a day.
+	DailyBudget float64 `bson:"daily_budget,omitempty" json:"daily_budget,omitempty" yaml:"daily_budget,omitempty"`
+	// MonthlyBudget is the budget for the versions in a month.
+	MonthlyBudget float64 `bson:"monthly_budget,omitempty" json:"monthly_budget,omitempty" yaml:"monthly_budget,omitempty"`
+}
+
+// IsZero returns whether none of the cost budget settings are configured.
+func (s CostBudgetSettings) IsZero() bool {
+	return !s.HasBudget() && !s.HardStop && s.PatchConfirmationThreshold == 0
+}
+
+// HasBudget returns whether the project has any cost budgets configured.
+func (s CostBudgetSettings) HasBudget() bool {
+	return s.DailyBudget != 0 || s.MonthlyBudget != 0 || len(s.RequesterBudgets) != 0
+}
+
+// RequiresPatchConfirmation returns whether a patch with the given estimated
+// cost must be confirmed before it's scheduled.
+func (s CostBudgetSettings) RequiresPatchConfirmation(estimatedCost float64) bool {
+	return s.PatchConfirmationThreshold > 0 && estimatedCost > s.PatchConfirmationThreshold
+}
+
+// Validate checks that the cost budget settings are valid.
+func (s CostBudgetSettings) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	catcher.NewWhen(s.DailyBudget < 0, "daily budget cannot be negative")
+	catcher.NewWhen(s.MonthlyBudget < 0, "monthly budget cannot be negative")
+	catcher.NewWhen(s.PatchConfirmationThreshold < 0, "patch confirmation threshold cannot be negative")
+	catcher.NewWhen(s.HardStop && !s.HasBudget(), "cannot enable the hard stop without a budget")
+
+	requesters := map[string]bool{}
+	for _, b := range s.RequesterBudgets {
+		catcher.ErrorfWhen(!utility.StringSliceContains(evergreen.AllRequesterTypes, b.Requester), "invalid requester '%s' for cost budget", b.Requester)
+		catcher.ErrorfWhen(requesters[b.Requester], "duplicate cost budget for requester '%s'", b.Requester)
+		catcher.ErrorfWhen(b.DailyBudget < 0, "daily budget for requester '%s' cannot be negative", b.Requester)
+		catcher.ErrorfWhen(b.MonthlyBudget < 0, "monthly budget for requester '%s' cannot be negative", b.Requester)
+		requesters[b.Requester] = true
+	}
+
+	return catcher.Resolve()
+}
+
+// CostBudgetUsage is the spend against a single cost budget in its current
+// period.
+type CostBudgetUsage struct {
+	Period CostBudgetPeriod
+	// Requester is the requester the budget applies to, or empty if it
+	// applies to the entire project.
+	Requester string
+	// Start is the start of the budget's current period.
+	Start time.Time
+	// Budget is the budget in dollars.
+	Budget float64
+	// Spent is the amount spent in the current period in dollars.
+	Spent float64
+}
+
+// IsExhausted returns whether the budget has been fully spent.
+func (u CostBudgetUsage) IsExhausted() bool {
+	return u.Spent >= u.Budget
+}
+
+// PercentSpent returns the percentage of the budget that has been spent.
+func (u CostBudgetUsage) PercentSpent() float64 {
+	return 100 * u.Spent / u.Budget
+}
+
+// String returns a description of the budget.
+func (u CostBudgetUsage) String() string {
+	if u.Requester == "" {
+		return fmt.Sprintf("%s budget of $%.2f", u.Period, u.Budget)
+	}
+	return fmt.Sprintf("%s budget of $%.2f for requester '%s'", u.Period, u.Budget, u.Requester)
+}
+
+// GetCostBudgetUsage returns the current spend against each of the project's
+// cost budgets that apply to versions with the given requester.
+func (p *ProjectRef) GetCostBudgetUsage(ctx context.Context, requester string) ([]CostBudgetUsage, error) {
+	var usages []CostBudgetUsage
+	addUsage := func(period CostBudgetPeriod, budgetRequester string, budget float64) {
+		if budget > 0 {
+			usages = append(usages, CostBudgetUsage{Period: period, Requester: budgetRequester, Budget: budget})
+		}
+	}
+	addUsage(CostBudgetPeriodDaily, "", p.CostBudget.DailyBudget)
+	addUsage(CostBudgetPeriodMonthly, "", p.CostBudget.MonthlyBudget)
+	for _, b := range p.CostBudget.RequesterBudgets {
+		if b.Requester == requester {
+			addUsage(CostBudgetPeriodDaily, b.Requester, b.DailyBudget)
+			addUsage(CostBudgetPeriodMonthly, b.Requester, b.MonthlyBudget)
+		}
+	}
+
+	now := time.Now()
+	for i := range usages {
+		usages[i].Start = usages[i].Period.Start(now)
+		spent, err := getProjectVersionCost(ctx, p.Id, usages[i].Requester, usages[i].Start)
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting spend against %s", usages[i].String())
+		}
+		usages[i].Spent = spent
+	}
+
+	return usages, nil
+}
+
+// CostBudgetNotificationsCollection records the budget thresholds that
+// subscriptions have already been notified about in each budget period.
+const CostBudgetNotificationsCollection = "cost_budget_notifications"
+
+// costBudgetNotification records the highest percentage of a budget in its
+// current period that a subscription has been notified about.
+type costBudgetNotification struct {
+	ID              string    `bson:"_id"`
+	SubscriptionID  string    `bson:"subscription_id"`
+	ProjectID       string    `bson:"project_id"`
+	PeriodStart     time.Time `bson:"period_start"`
+	NotifiedPercent float64   `bson:"notified_percent"`
+}
+
+var (
+	costBudgetSettingsHardStopKey = bsonutil.MustHaveTag(CostBudgetSettings{}, "HardStop")
+
+	costBudgetNotificationIDKey              = bsonutil.MustHaveTag(costBudgetNotification{}, "ID")
+	costBudgetNotificationSubscriptionIDKey  = bsonutil.MustHaveTag(costBudgetNotification{}, "SubscriptionID")
+	costBudgetNotificationProjectIDKey       = bsonutil.MustHaveTag(costBudgetNotification{}, "ProjectID")
+	costBudgetNotificationPeriodStartKey     = bsonutil.MustHaveTag(costBudgetNotification{}, "PeriodStart")
+	costBudgetNotificationNotifiedPercentKey = bsonutil.MustHaveTag(costBudgetNotification{}, "NotifiedPercent")
+)
+
+// ClaimCostBudgetNotification atomically records that the subscription is
+// being notified that the project's spend reached the given percentage of the
+// budget in its current period. It returns false if the subscription was
+// already notified about that percentage or a higher one in this period, so
+// that exactly one of the versions finishing after the spend crosses the
+// threshold sends the notification, regardless of the order they finish in.
+func ClaimCostBudgetNotification(ctx context.Context, subscriptionID, projectID string, usage CostBudgetUsage, percent float64) (bool, error) {
+	id := fmt.Sprintf("%s-%s-%s-%s-%d", subscriptionID, projectID, usage.Period, usage.Requester, usage.Start.Unix())
+	_, err := db.Upsert(ctx, CostBudgetNotificationsCollection, bson.M{
+		costBudgetNotificationIDKey:              id,
+		costBudgetNotificationNotifiedPercentKey: bson.M{"$lt": percent},
+	}, bson.M{"$set": bson.M{
+		costBudgetNotificationSubscriptionIDKey:  subscriptionID,
+		costBudgetNotificationProjectIDKey:       projectID,
+		costBudgetNotificationPeriodStartKey:     usage.Start,
+		costBudgetNotificationNotifiedPercentKey: percent,
+	}})
+	if db.IsDuplicateKey(err) {
+		// The record exists but has already reached the percentage.
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "claiming notification for %s", usage.String())
+	}
+	return true, nil
+}
+
+// CheckCostBudgetHardStop returns an error if the project does not allow
+// activating tasks with the given requester because a cost budget that applies
+// to them is exhausted. Only patch tasks are ever stopped.
+func (p *ProjectRef) CheckCostBudgetHardStop(ctx context.Context, requester string) error {
+	if !p.CostBudget.HardStop || !evergreen.IsPatchRequester(requester) {
+		return nil
+	}
+
+	usages, err := p.GetCostBudgetUsage(ctx, requester)
+	if err != nil {
+		return errors.Wrapf(err, "getting cost budget usage for project '%s'", p.Id)
+	}
+	for _, u := range usages {
+		if u.IsExhausted() {
+			return errors.Errorf("project '%s' has spent $%.2f of its %s, so no more patch tasks can be activated", p.Identifier, u.Spent, u.String())
+		}
+	}
+
+	return nil
+}
+
+// getProjectVersionCost returns the total actual cost of the project's
+// versions created since the given time. If requester is set, only versions
+// with that requester are included.
+func getProjectVersionCost(ctx context.Context, projectID, requester string, since time.Time) (float64, error) {
+	match := bson.M{
+		VersionIdentifierKey: projectID,
+		VersionCreateTimeKey: bson.M{"$gte": since},
+	}
+	if requester != "" {
+		match[VersionRequesterKey] = requester
+	}
+
+	var costFields []any
+	for _, key := range []string{
+		cost.AdjustedEC2CostKey,
+		cost.AdjustedEBSThroughputCostKey,
+		cost.AdjustedEBSStorageCostKey,
+		cost.AdjustedS3ArtifactPutCostKey,
+		cost.AdjustedS3LogPutCostKey,
+		cost.AdjustedS3ArtifactStorageCostKey,
+		cost.AdjustedS3LogStorageCostKey,
+	} {
+		costFields = append(costFields, bson.M{"$ifNull": []any{"$" + bsonutil.GetDottedKeyName(VersionCostKey, key), 0}})
+	}
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$group": bson.M{
+			"_id":   nil,
+			"total": bson.M{"$sum": bson.M{"$add": costFields}},
+		}},
+	}
+
+	cursor, err := evergreen.GetEnvironment().DB().Collection(VersionCollection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, errors.Wrap(err, "aggregating version costs")
+	}
+	var res []struct {
+		Total float64 `bson:"total"`
+	}
+	if err = cursor.All(ctx, &res); err != nil {
+		return 0, errors.Wrap(err, "decoding version costs")
+	}
+	if len(res) == 0 {
+		return 0, nil
+	}
+
+	return res[0].Total, nil
+}
+
+// checkCostBudgetHardStops returns an error if any of the patch tasks cannot be
+// activated because its project's cost budget is exhausted.
+func checkCostBudgetHardStops(ctx context.Context, tasks []task.Task) error {
+	var projectIDs []string
+	for _, t := range tasks {
+		if evergreen.IsPatchRequester(t.Requester) {
+			projectIDs = append(projectIDs, t.Project)
+		}
+	}
+	if len(projectIDs) == 0 {
+		return nil
+	}
+	// Activating tasks is frequent, so only merge the project refs of the
+	// projects that enable the hard stop.
+	hardStopProjects, err := findCostBudgetHardStopProjects(ctx, utility.UniqueStrings(projectIDs))
+	if err != nil {
+		return errors.Wrap(err, "finding projects with a cost budget hard stop")
+	}
+	if len(hardStopProjects) == 0 {
+		return nil
+	}
+
+	checked := map[string]bool{}
+	for _, t := range tasks {
+		key := t.Project + "/" + t.Requester
+		if !evergreen.IsPatchRequester(t.Requester) || !hardStopProjects[t.Project] || checked[key] {
+			continue
+		}
+		checked[key] = true
+
+		pRef, err := FindMergedProjectRef(ctx, t.Project, t.Version, false)
+		if err != nil {
+			return errors.Wrapf(err, "finding project '%s'", t.Project)
+		}
+		if pRef == nil {
+			continue
+		}
+		if err := pRef.CheckCostBudgetHardStop(ctx, t.Requester); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// findCostBudgetHardStopProjects returns the subset of the given projects
+// that enable the cost budget hard stop, either themselves or through their
+// repo. Only the fields needed to tell are read.
+func findCostBudgetHardStopProjects(ctx context.Context, projectIDs []string) (map[string]bool, error) {
+	hardStopKey := bsonutil.GetDottedKeyName(projectRefCostBudgetKey, costBudgetSettingsHardStopKey)
+	var pRefs []ProjectRef
+	q := db.Query(byIds(projectIDs...)).WithFields(ProjectRefIdKey, ProjectRefRepoRefIdKey, hardStopKey)
+	if err := db.FindAllQ(ctx, ProjectRefCollection, q, &pRefs); err != nil {
+		return nil, errors.Wrap(err, "finding project refs")
+	}
+
+	hardStop := map[string]bool{}
+	var repoRefIDs []string
+	for _, pRef := range pRefs {
+		if pRef.CostBudget.HardStop {
+			hardStop[pRef.Id] = true
+		} else if pRef.UseRepoSettings() {
+			repoRefIDs = append(repoRefIDs, pRef.RepoRefId)
+		}
+	}
+	if len(repoRefIDs) == 0 {
+		return hardStop, nil
+	}
+
+	var repoRefs []RepoRef
+	q = db.Query(bson.M{
+		RepoRefIdKey: bson.M{"$in": utility.UniqueStrings(repoRefIDs)},
+		hardStopKey:  true,
+	}).WithFields(RepoRefIdKey)
+	if err := db.FindAllQ(ctx, RepoRefCollection, q, &repoRefs); err != nil {
+		return nil, errors.Wrap(err, "finding repo refs")
+	}
+	hardStopRepos := map[string]bool{}
+	for _, repoRef := range repoRefs {
+		hardStopRepos[repoRef.Id] = true
+	}
+	for _, pRef := range pRefs {
+		if pRef.UseRepoSettings() && hardStopRepos[pRef.RepoRefId] {
+			hardStop[pRef.Id] = true
+		}
+	}
+
+	return hardStop, nil
+}
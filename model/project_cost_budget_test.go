@@ -0,0 +1,183 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/cost"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCostBudgetSettingsValidate(t *testing.T) {
+	for tName, tCase := range map[string]struct {
+		settings CostBudgetSettings
+		valid    bool
+	}{
+		"Empty":          {valid: true},
+		"ProjectBudgets": {settings: CostBudgetSettings{DailyBudget: 10, MonthlyBudget: 200, HardStop: true}, valid: true},
+		"RequesterBudgets": {settings: CostBudgetSettings{RequesterBudgets: []RequesterCostBudget{
+			{Requester: evergreen.PatchVersionRequester, DailyBudget: 5},
+			{Requester: evergreen.GithubPRRequester, MonthlyBudget: 50},
+		}}, valid: true},
+		"ConfirmationThreshold":   {settings: CostBudgetSettings{PatchConfirmationThreshold: 25}, valid: true},
+		"NegativeBudget":          {settings: CostBudgetSettings{DailyBudget: -1}},
+		"NegativeThreshold":       {settings: CostBudgetSettings{PatchConfirmationThreshold: -1}},
+		"HardStopWithoutBudget":   {settings: CostBudgetSettings{HardStop: true}},
+		"InvalidRequester":        {settings: CostBudgetSettings{RequesterBudgets: []RequesterCostBudget{{Requester: "nonexistent", DailyBudget: 5}}}},
+		"DuplicateRequester":      {settings: CostBudgetSettings{RequesterBudgets: []RequesterCostBudget{{Requester: evergreen.PatchVersionRequester, DailyBudget: 5}, {Requester: evergreen.PatchVersionRequester, MonthlyBudget: 5}}}},
+		"NegativeRequesterBudget": {settings: CostBudgetSettings{RequesterBudgets: []RequesterCostBudget{{Requester: evergreen.PatchVersionRequester, MonthlyBudget: -5}}}},
+	} {
+		t.Run(tName, func(t *testing.T) {
+			err := tCase.settings.Validate()
+			if tCase.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestRequiresPatchConfirmation(t *testing.T) {
+	settings := CostBudgetSettings{}
+	assert.False(t, settings.RequiresPatchConfirmation(1000), "no threshold should never require confirmation")
+
+	settings.PatchConfirmationThreshold = 10
+	assert.False(t, settings.RequiresPatchConfirmation(10))
+	assert.True(t, settings.RequiresPatchConfirmation(10.01))
+	assert.False(t, settings.IsZero())
+	assert.False(t, settings.HasBudget())
+}
+
+func TestCostBudgetPeriodStart(t *testing.T) {
+	now := time.Date(2024, time.March, 15, 13, 30, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC), CostBudgetPeriodDaily.Start(now))
+	assert.Equal(t, time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), CostBudgetPeriodMonthly.Start(now))
+}
+
+func TestCostBudgetUsage(t *testing.T) {
+	setup := func(t *testing.T) *ProjectRef {
+		require.NoError(t, db.ClearCollections(VersionCollection, ProjectRefCollection, task.Collection, build.Collection))
+		pRef := &ProjectRef{
+			Id:         "project",
+			Identifier: "project",
+			CostBudget: CostBudgetSettings{
+				DailyBudget:      10,
+				RequesterBudgets: []RequesterCostBudget{{Requester: evergreen.PatchVersionRequester, DailyBudget: 4}},
+			},
+		}
+		require.NoError(t, pRef.Insert(t.Context()))
+
+		for _, v := range []Version{
+			{Id: "mainline", Identifier: pRef.Id, Requester: evergreen.RepotrackerVersionRequester, CreateTime: time.Now(), Cost: cost.Cost{AdjustedEC2Cost: 3, AdjustedS3LogPutCost: 1}},
+			{Id: "patch", Identifier: pRef.Id, Requester: evergreen.PatchVersionRequester, CreateTime: time.Now(), Cost: cost.Cost{AdjustedEC2Cost: 2}},
+			{Id: "old", Identifier: pRef.Id, Requester: evergreen.PatchVersionRequester, CreateTime: time.Now().Add(-72 * time.Hour), Cost: cost.Cost{AdjustedEC2Cost: 100}},
+			{Id: "other_project", Identifier: "other", Requester: evergreen.PatchVersionRequester, CreateTime: time.Now(), Cost: cost.Cost{AdjustedEC2Cost: 100}},
+		} {
+			require.NoError(t, v.Insert(t.Context()))
+		}
+
+		return pRef
+	}
+
+	t.Run("IncludesProjectAndRequesterBudgets", func(t *testing.T) {
+		pRef := setup(t)
+		usages, err := pRef.GetCostBudgetUsage(t.Context(), evergreen.PatchVersionRequester)
+		require.NoError(t, err)
+		require.Len(t, usages, 2)
+
+		assert.Equal(t, CostBudgetPeriodDaily, usages[0].Period)
+		assert.Empty(t, usages[0].Requester)
+		assert.InDelta(t, 6, usages[0].Spent, 1e-9)
+		assert.False(t, usages[0].IsExhausted())
+
+		assert.Equal(t, evergreen.PatchVersionRequester, usages[1].Requester)
+		assert.InDelta(t, 2, usages[1].Spent, 1e-9)
+		assert.InDelta(t, 50, usages[1].PercentSpent(), 1e-9)
+	})
+	t.Run("OmitsOtherRequesterBudgets", func(t *testing.T) {
+		pRef := setup(t)
+		usages, err := pRef.GetCostBudgetUsage(t.Context(), evergreen.RepotrackerVersionRequester)
+		require.NoError(t, err)
+		require.Len(t, usages, 1)
+		assert.Empty(t, usages[0].Requester)
+	})
+	t.Run("HardStop", func(t *testing.T) {
+		pRef := setup(t)
+		pRef.CostBudget.RequesterBudgets[0].DailyBudget = 2
+		assert.NoError(t, pRef.CheckCostBudgetHardStop(t.Context(), evergreen.PatchVersionRequester), "hard stop should be disabled")
+
+		pRef.CostBudget.HardStop = true
+		assert.Error(t, pRef.CheckCostBudgetHardStop(t.Context(), evergreen.PatchVersionRequester))
+		assert.NoError(t, pRef.CheckCostBudgetHardStop(t.Context(), evergreen.RepotrackerVersionRequester), "mainline tasks should never be stopped")
+	})
+	t.Run("SetActiveStateRefusesPatchTasksOverBudget", func(t *testing.T) {
+		pRef := setup(t)
+		pRef.CostBudget.HardStop = true
+		pRef.CostBudget.RequesterBudgets[0].DailyBudget = 2
+		require.NoError(t, pRef.Replace(t.Context()))
+
+		tsk := task.Task{Id: "t1", Project: pRef.Id, Version: "patch", BuildId: "b1", Requester: evergreen.PatchVersionRequester, Status: evergreen.TaskUndispatched}
+		require.NoError(t, tsk.Insert(t.Context()))
+		assert.Error(t, SetActiveState(t.Context(), "user", true, tsk))
+
+		dbTask, err := task.FindOneId(t.Context(), tsk.Id)
+		require.NoError(t, err)
+		require.NotNil(t, dbTask)
+		assert.False(t, dbTask.Activated)
+	})
+}
+
+func TestClaimCostBudgetNotification(t *testing.T) {
+	require.NoError(t, db.ClearCollections(CostBudgetNotificationsCollection))
+	t.Cleanup(func() {
+		assert.NoError(t, db.ClearCollections(CostBudgetNotificationsCollection))
+	})
+	start := CostBudgetPeriodDaily.Start(time.Now())
+	usage := CostBudgetUsage{Period: CostBudgetPeriodDaily, Start: start, Budget: 10, Spent: 9}
+
+	claimed, err := ClaimCostBudgetNotification(t.Context(), "sub", "project", usage, 50)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+
+	claimed, err = ClaimCostBudgetNotification(t.Context(), "sub", "project", usage, 50)
+	require.NoError(t, err)
+	assert.False(t, claimed, "the threshold should only be claimed once per period")
+
+	claimed, err = ClaimCostBudgetNotification(t.Context(), "sub", "project", usage, 80)
+	require.NoError(t, err)
+	assert.True(t, claimed, "a higher threshold should be claimed")
+
+	claimed, err = ClaimCostBudgetNotification(t.Context(), "other_sub", "project", usage, 50)
+	require.NoError(t, err)
+	assert.True(t, claimed, "each subscription should be claimed separately")
+
+	nextPeriod := usage
+	nextPeriod.Start = start.Add(24 * time.Hour)
+	claimed, err = ClaimCostBudgetNotification(t.Context(), "sub", "project", nextPeriod, 50)
+	require.NoError(t, err)
+	assert.True(t, claimed, "the threshold should be claimed again in a new period")
+}
+
+func TestFindCostBudgetHardStopProjects(t *testing.T) {
+	require.NoError(t, db.ClearCollections(ProjectRefCollection, RepoRefCollection))
+	repoRef := RepoRef{ProjectRef{Id: "repo", CostBudget: CostBudgetSettings{DailyBudget: 10, HardStop: true}}}
+	require.NoError(t, repoRef.Replace(t.Context()))
+	for _, pRef := range []ProjectRef{
+		{Id: "hard_stop", CostBudget: CostBudgetSettings{DailyBudget: 10, HardStop: true}},
+		{Id: "budget_only", CostBudget: CostBudgetSettings{DailyBudget: 10}},
+		{Id: "no_budget"},
+		{Id: "inherits_hard_stop", RepoRefId: "repo"},
+	} {
+		require.NoError(t, pRef.Insert(t.Context()))
+	}
+
+	hardStop, err := findCostBudgetHardStopProjects(t.Context(), []string{"hard_stop", "budget_only", "no_budget", "inherits_hard_stop", "nonexistent"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"hard_stop": true, "inherits_hard_stop": true}, hardStop)
+}
@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"sort"
 
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/grip"
 	"github.com/pkg/errors"
 )
 
@@ -20,7 +22,8 @@ import (
 //  and moved into a separate "matrices" slice.
 //   2. A tag selector evaluator is constructed for evaluating axis selectors
 //   3. The matrix and axis definitions are passed to buildMatrixVariants, which
-//  creates all combinations of matrix cells and removes excluded ones.
+//  creates all combinations of matrix cells, removes excluded ones and adds
+//  included ones.
 //   4. During the generation of a single cell, we merge all axis values for the cell
 //  together to create a fully filled-in variant. Matrix rules concerning non-task settings
 //  are evaluated as well. Rules `add_tasks` and `remove_tasks` are stored in the variant
@@ -42,6 +45,15 @@ type matrix struct {
 	RunOn       parserStringSlice `yaml:"run_on,omitempty" bson:"run_on,omitempty"`
 	Tasks       parserBVTaskUnits `yaml:"tasks,omitempty" bson:"tasks,omitempty"`
 	Rules       []matrixRule      `yaml:"rules,omitempty" bson:"rules,omitempty"`
+
+	// ExcludeRules and IncludeRules remove cells from and add cells to the
+	// matrix spec based on predicates on the cells' values.
+	ExcludeRules []matrixCellRule `yaml:"exclude,omitempty" bson:"exclude_rules,omitempty"`
+	IncludeRules []matrixCellRule `yaml:"include,omitempty" bson:"include_rules,omitempty"`
+	// DependsOn is the variant-level dependencies of each generated variant.
+	// Like tasks, it's expanded with the cell's axis values, so a dependency
+	// can select a variant with the same value for an axis.
+	DependsOn parserDependencies `yaml:"depends_on,omitempty" bson:"depends_on,omitempty"`
 }
 
 // matrixAxis represents one axis of a matrix definition.
@@ -153,6 +165,20 @@ func (mdef matrixDefinition) allCells() ([]matrixValue, error) {
 	return cells, nil
 }
 
+// withSpecAxes returns a copy of the definition in which every axis of the
+// matrix spec that the definition doesn't mention takes all of the spec's
+// values for that axis, so that the definition only produces complete cells.
+func (mdef matrixDefinition) withSpecAxes(spec matrixDefinition) matrixDefinition {
+	cpy := matrixDefinition{}
+	for axis, vals := range spec {
+		cpy[axis] = vals
+	}
+	for axis, vals := range mdef {
+		cpy[axis] = vals
+	}
+	return cpy
+}
+
 // evaluatedCopy returns a copy of the definition with its tag selectors evaluated.
 func (mdef matrixDefinition) evaluatedCopy(ase *axisSelectorEvaluator) (matrixDefinition, []error) {
 	var errs []error
@@ -184,6 +210,18 @@ func (mdef matrixDefinition) contains(mv matrixValue) bool {
 	return true
 }
 
+// selects returns whether a value is selected by a definition. Unlike
+// contains, axes that the definition doesn't mention match any value.
+func (mdef matrixDefinition) selects(mv matrixValue) bool {
+	for axis, vals := range mdef {
+		v, ok := mv[axis]
+		if !ok || !utility.StringSliceContains(vals, v) {
+			return false
+		}
+	}
+	return true
+}
+
 // matrixDefinitions is a helper type for parsing either a single definition
 // or a slice of definitions from YAML.
 type matrixDefinitions []matrixDefinition
@@ -214,6 +252,16 @@ func (mds matrixDefinitions) contain(v matrixValue) bool {
 	return false
 }
 
+// selects returns true if *any* of the definitions select the given value.
+func (mds matrixDefinitions) selects(v matrixValue) bool {
+	for _, m := range mds {
+		if m.selects(v) {
+			return true
+		}
+	}
+	return false
+}
+
 // evaluatedCopies is like evaluatedCopy, but for multiple definitions.
 func (mds matrixDefinitions) evaluatedCopies(ase *axisSelectorEvaluator) (matrixDefinitions, []error) {
 	var out matrixDefinitions
@@ -256,6 +304,64 @@ func GetVariantsWithMatrices(ase *axisSelectorEvaluator, axes []matrixAxis, bvs
 	return append(regularBVs, matrixBVs...), errs
 }
 
+// MatrixVariant describes a variant generated from a matrix cell.
+type MatrixVariant struct {
+	Name        string            `yaml:"name"`
+	DisplayName string            `yaml:"display_name,omitempty"`
+	Cell        map[string]string `yaml:"cell"`
+	// Included is whether the cell was added by an include rule.
+	Included bool `yaml:"included,omitempty"`
+}
+
+// MatrixExpansion describes the variants generated by a matrix and the cells
+// that it excluded.
+type MatrixExpansion struct {
+	Name     string              `yaml:"matrix_name"`
+	Variants []MatrixVariant     `yaml:"variants"`
+	Excluded []map[string]string `yaml:"excluded,omitempty"`
+}
+
+// ExpandMatrices returns the variants generated by each of the project's
+// matrices, sorted by name, along with the cells that each matrix excluded.
+func (pp *ParserProject) ExpandMatrices() ([]MatrixExpansion, error) {
+	catcher := grip.NewBasicCatcher()
+	ase := NewAxisSelectorEvaluator(pp.Axes)
+	_, matrices := sieveMatrixVariants(pp.BuildVariants)
+	expansions := []MatrixExpansion{}
+	for i, m := range matrices {
+		cells, errs := m.evaluateCells(ase)
+		if len(errs) > 0 {
+			catcher.Extend(errs)
+			continue
+		}
+		expansion := MatrixExpansion{Name: m.Id, Variants: []MatrixVariant{}}
+		for _, cell := range cells.cells {
+			v, err := buildMatrixVariant(pp.Axes, cell, &matrices[i], ase)
+			if err != nil {
+				catcher.Wrapf(err, "building cell '%v' for matrix '%s'", cell, m.Id)
+				continue
+			}
+			expansion.Variants = append(expansion.Variants, MatrixVariant{
+				Name:        v.Name,
+				DisplayName: v.DisplayName,
+				Cell:        cell,
+				Included:    cells.included[cell.String()],
+			})
+		}
+		sort.Slice(expansion.Variants, func(i, j int) bool {
+			return expansion.Variants[i].Name < expansion.Variants[j].Name
+		})
+		sort.Slice(cells.excluded, func(i, j int) bool {
+			return cells.excluded[i].String() < cells.excluded[j].String()
+		})
+		for _, cell := range cells.excluded {
+			expansion.Excluded = append(expansion.Excluded, cell)
+		}
+		expansions = append(expansions, expansion)
+	}
+	return expansions, catcher.Resolve()
+}
+
 // buildMatrixVariants takes in a list of axis definitions, an axisSelectorEvaluator, and a slice of
 // matrix definitions. It returns a slice of parserBuildVariants constructed according to
 // our matrix specification.
@@ -265,41 +371,115 @@ func buildMatrixVariants(axes []matrixAxis, ase *axisSelectorEvaluator, matrices
 	// for each matrix, build out its declarations
 	matrixVariants := []parserBV{}
 	for i, m := range matrices {
-		// for each axis value, iterate through possible inputs
-		evaluatedSpec, evalErrs := m.Spec.evaluatedCopy(ase)
+		cells, evalErrs := m.evaluateCells(ase)
 		if len(evalErrs) > 0 {
 			errs = append(errs, evalErrs...)
 			continue
 		}
-		evaluatedExcludes, evalErrs := m.Exclude.evaluatedCopies(ase)
-		if len(evalErrs) > 0 {
-			errs = append(errs, evalErrs...)
+		for _, cell := range cells.cells {
+			v, err := buildMatrixVariant(axes, cell, &matrices[i], ase)
+			if err != nil {
+				errs = append(errs, errors.Wrapf(err, "building cell '%v' for matrix '%s'", cell, m.Id))
+				continue
+			}
+			matrixVariants = append(matrixVariants, *v)
+		}
+	}
+	return matrixVariants, errs
+}
+
+// matrixCells are the cells of a matrix that variants are generated for.
+type matrixCells struct {
+	cells []matrixValue
+	// included are the cells that were added by include rules, keyed by
+	// their string representation.
+	included map[string]bool
+	// excluded are the cells of the matrix spec that were excluded.
+	excluded []matrixValue
+}
+
+// evaluateCells returns every cell of the matrix spec that isn't excluded,
+// followed by the cells added by the matrix's include rules.
+func (m *matrix) evaluateCells(ase *axisSelectorEvaluator) (matrixCells, []error) {
+	var errs []error
+	// for each axis value, iterate through possible inputs
+	evaluatedSpec, evalErrs := m.Spec.evaluatedCopy(ase)
+	errs = append(errs, evalErrs...)
+	evaluatedExcludes, evalErrs := m.Exclude.evaluatedCopies(ase)
+	errs = append(errs, evalErrs...)
+	excludeRules, evalErrs := evaluateCellRules(ase, m.ExcludeRules, "exclude")
+	errs = append(errs, evalErrs...)
+	includeRules, evalErrs := evaluateCellRules(ase, m.IncludeRules, "include")
+	errs = append(errs, evalErrs...)
+	if len(errs) > 0 {
+		return matrixCells{}, errs
+	}
+	unpruned, err := evaluatedSpec.allCells()
+	if err != nil {
+		return matrixCells{}, []error{err}
+	}
+
+	res := matrixCells{included: map[string]bool{}}
+	seen := map[string]bool{}
+	var excludedBySpec, excludedByRules int
+	for _, cell := range unpruned {
+		excludeSpecMatches := evaluatedExcludes.contain(cell)
+		excludeRuleMatches := excludeRules.match(cell)
+		if excludeSpecMatches {
+			excludedBySpec++
+		}
+		if excludeRuleMatches {
+			excludedByRules++
+		}
+		if excludeSpecMatches || excludeRuleMatches {
+			res.excluded = append(res.excluded, cell)
 			continue
 		}
-		unpruned, err := evaluatedSpec.allCells()
-		if err != nil {
-			// If allCells fails we should exit immediately
-			return nil, []error{err}
-		}
-		pruned := []parserBV{}
-		for _, cell := range unpruned {
-			// create the variant if it isn't excluded
-			if !evaluatedExcludes.contain(cell) {
-				v, err := buildMatrixVariant(axes, cell, &matrices[i], ase)
-				if err != nil {
-					errs = append(errs, errors.Wrapf(err, "building cell '%v' for matrix '%s'", cell, m.Id))
+		res.cells = append(res.cells, cell)
+		seen[cell.String()] = true
+	}
+	// safety check to make sure the exclude fields are actually working
+	if len(m.Exclude) > 0 && excludedBySpec == 0 {
+		errs = append(errs, errors.Errorf("exclude field did not exclude anything for matrix '%s'", m.Id))
+	}
+	if len(m.ExcludeRules) > 0 && excludedByRules == 0 {
+		errs = append(errs, errors.Errorf("exclude rules did not exclude anything for matrix '%s'", m.Id))
+	}
+
+	// included cells are added even if the matrix spec would have excluded
+	// them.
+	for i, r := range includeRules {
+		for _, def := range r.If {
+			candidates, err := def.withSpecAxes(evaluatedSpec).allCells()
+			if err != nil {
+				errs = append(errs, errors.Wrapf(err, "evaluating include rule %d for matrix '%s'", i, m.Id))
+				continue
+			}
+			for _, cell := range candidates {
+				key := cell.String()
+				if seen[key] || !r.matches(cell) {
 					continue
 				}
-				pruned = append(pruned, *v)
+				seen[key] = true
+				res.included[key] = true
+				res.cells = append(res.cells, cell)
 			}
 		}
-		// safety check to make sure the exclude field is actually working
-		if len(m.Exclude) > 0 && len(unpruned) == len(pruned) {
-			errs = append(errs, errors.Errorf("exclude field did not exclude anything for matrix '%s'", m.Id))
+	}
+	if len(m.IncludeRules) > 0 && len(res.included) == 0 {
+		errs = append(errs, errors.Errorf("include rules did not include anything for matrix '%s'", m.Id))
+	}
+	if len(res.included) > 0 {
+		stillExcluded := []matrixValue{}
+		for _, cell := range res.excluded {
+			if !res.included[cell.String()] {
+				stillExcluded = append(stillExcluded, cell)
+			}
 		}
-		matrixVariants = append(matrixVariants, pruned...)
+		res.excluded = stillExcluded
 	}
-	return matrixVariants, errs
+
+	return res, errs
 }
 
 // buildMatrixVariant does the heavy lifting of building a matrix variant based on axis information.
@@ -378,6 +558,10 @@ func buildMatrixVariant(axes []matrixAxis, mv matrixValue, m *matrix, ase *axisS
 		}
 		v.Tasks = append(v.Tasks, expTask)
 	}
+	v.DependsOn, err = expandParserDependencies(m.DependsOn, v.Expansions)
+	if err != nil {
+		return nil, errors.Wrap(err, "processing depends_on")
+	}
 
 	// evaluate rules for matching matrix values
 	for i, rule := range m.Rules {
@@ -405,6 +589,103 @@ func buildMatrixVariant(axes []matrixAxis, mv matrixValue, m *matrix, ase *axisS
 	return &v, nil
 }
 
+// matrixCellRule is a predicate on the cells of a matrix, which is used to
+// exclude cells from or include cells in the matrix. A cell matches the rule
+// if it satisfies every condition that the rule sets.
+type matrixCellRule struct {
+	// If matches cells that are selected by any of the definitions. Unlike
+	// exclude_spec, axes that a definition doesn't mention match any value.
+	// For include rules, the cells of the definitions are the candidate
+	// cells to add.
+	If matrixDefinitions `yaml:"if,omitempty" bson:"if,omitempty"`
+	// Unless matches cells that aren't selected by any of the definitions.
+	Unless matrixDefinitions `yaml:"unless,omitempty" bson:"unless,omitempty"`
+	// SameValue matches cells that have the same value for all of the axes.
+	SameValue parserStringSlice `yaml:"same_value,omitempty" bson:"same_value,omitempty"`
+	// DifferentValue matches cells that don't have the same value for all of
+	// the axes.
+	DifferentValue parserStringSlice `yaml:"different_value,omitempty" bson:"different_value,omitempty"`
+}
+
+// matches returns whether the cell satisfies every condition of the rule.
+func (r matrixCellRule) matches(mv matrixValue) bool {
+	if len(r.If) > 0 && !r.If.selects(mv) {
+		return false
+	}
+	if len(r.Unless) > 0 && r.Unless.selects(mv) {
+		return false
+	}
+	if len(r.SameValue) > 0 {
+		if same, ok := axisValuesEqual(mv, r.SameValue); !ok || !same {
+			return false
+		}
+	}
+	if len(r.DifferentValue) > 0 {
+		if same, ok := axisValuesEqual(mv, r.DifferentValue); !ok || same {
+			return false
+		}
+	}
+	return true
+}
+
+// axisValuesEqual returns whether the cell has the same value for all of the
+// given axes. It returns false for ok if the cell doesn't have every axis.
+func axisValuesEqual(mv matrixValue, axes []string) (same, ok bool) {
+	same = true
+	for _, axis := range axes {
+		v, found := mv[axis]
+		if !found {
+			return false, false
+		}
+		if v != mv[axes[0]] {
+			same = false
+		}
+	}
+	return same, true
+}
+
+// matrixCellRules is a set of matrix cell rules.
+type matrixCellRules []matrixCellRule
+
+// match returns true if *any* of the rules match the given value.
+func (rs matrixCellRules) match(mv matrixValue) bool {
+	for _, r := range rs {
+		if r.matches(mv) {
+			return true
+		}
+	}
+	return false
+}
+
+// evaluateCellRules returns copies of the rules with their tag selectors
+// evaluated, validating that each rule can be applied.
+func evaluateCellRules(ase *axisSelectorEvaluator, rules []matrixCellRule, kind string) (matrixCellRules, []error) {
+	var errs []error
+	evaluated := matrixCellRules{}
+	for i, r := range rules {
+		var evalErrs []error
+		newR := matrixCellRule{SameValue: r.SameValue, DifferentValue: r.DifferentValue}
+		newR.If, evalErrs = r.If.evaluatedCopies(ase)
+		errs = append(errs, evalErrs...)
+		newR.Unless, evalErrs = r.Unless.evaluatedCopies(ase)
+		errs = append(errs, evalErrs...)
+		if len(r.SameValue) == 1 {
+			errs = append(errs, errors.Errorf("%s rule %d must compare at least two axes in same_value", kind, i))
+		}
+		if len(r.DifferentValue) == 1 {
+			errs = append(errs, errors.Errorf("%s rule %d must compare at least two axes in different_value", kind, i))
+		}
+		if kind == "include" && len(r.If) == 0 {
+			errs = append(errs, errors.Errorf("include rule %d must define the cells to include with 'if'", i))
+		}
+		if len(r.If) == 0 && len(r.Unless) == 0 && len(r.SameValue) == 0 && len(r.DifferentValue) == 0 {
+			errs = append(errs, errors.Errorf("%s rule %d must have at least one condition", kind, i))
+		}
+		evaluated = append(evaluated, newR)
+	}
+	return evaluated, errs
+}
+
 // matrixRule allows users to manipulate arbitrary matrix values using selectors.
 type matrixRule struct {
 	If   matrixDefinitions `yaml:"if" bson:"if,omitempty"`
@@ -509,21 +790,30 @@ func expandParserBVTask(pbvt parserBVTaskUnit, exp util.Expansions) (parserBVTas
 	if err != nil {
 		return parserBVTaskUnit{}, errors.Wrap(err, "expanding distros")
 	}
+	newTask.DependsOn, err = expandParserDependencies(pbvt.DependsOn, exp)
+	if err != nil {
+		return parserBVTaskUnit{}, err
+	}
+	return newTask, nil
+}
+
+// expandParserDependencies expands strings inside dependencies.
+func expandParserDependencies(deps parserDependencies, exp util.Expansions) (parserDependencies, error) {
+	var err error
 	var newDeps parserDependencies
-	for i, d := range pbvt.DependsOn {
+	for i, d := range deps {
 		newDep := d
 		newDep.Status, err = exp.ExpandString(d.Status)
 		if err != nil {
-			return parserBVTaskUnit{}, errors.Wrapf(err, "expanding depends_on[%d/%d].status", i, len(pbvt.DependsOn))
+			return nil, errors.Wrapf(err, "expanding depends_on[%d/%d].status", i, len(deps))
 		}
 		newDep.TaskSelector, err = expandTaskSelector(d.TaskSelector, exp)
 		if err != nil {
-			return parserBVTaskUnit{}, errors.Wrapf(err, "expanding depends_on[%d/%d]", i, len(pbvt.DependsOn))
+			return nil, errors.Wrapf(err, "expanding depends_on[%d/%d]", i, len(deps))
 		}
 		newDeps = append(newDeps, newDep)
 	}
-	newTask.DependsOn = newDeps
-	return newTask, nil
+	return newDeps, nil
 }
 
 // expandTaskSelector expands strings inside task selectors.
@@ -708,3 +708,129 @@ func TestRulesEvaluation(t *testing.T) {
 		})
 	})
 }
+
+func TestMatrixCellRules(t *testing.T) {
+	Convey("With a set of test axes", t, func() {
+		axes := []matrixAxis{
+			{
+				Id: "server",
+				Values: []axisValue{
+					{Id: "linux", Tags: []string{"posix"}},
+					{Id: "macos", Tags: []string{"posix"}},
+					{Id: "windows"},
+				},
+			},
+			{
+				Id: "client",
+				Values: []axisValue{
+					{Id: "linux", Tags: []string{"posix"}},
+					{Id: "macos", Tags: []string{"posix"}},
+					{Id: "windows"},
+				},
+			},
+			{
+				Id: "arch",
+				Values: []axisValue{
+					{Id: "x86_64"},
+					{Id: "arm64"},
+				},
+			},
+		}
+		ase := NewAxisSelectorEvaluator(axes)
+		Convey("a rule should match cells satisfying all of its conditions", func() {
+			rule := matrixCellRule{
+				If:             matrixDefinitions{{"server": []string{"linux", "macos"}}},
+				Unless:         matrixDefinitions{{"arch": []string{"arm64"}}},
+				DifferentValue: []string{"server", "client"},
+			}
+			So(rule.matches(matrixValue{"server": "linux", "client": "windows", "arch": "x86_64"}), ShouldBeTrue)
+			So(rule.matches(matrixValue{"server": "windows", "client": "linux", "arch": "x86_64"}), ShouldBeFalse)
+			So(rule.matches(matrixValue{"server": "linux", "client": "windows", "arch": "arm64"}), ShouldBeFalse)
+			So(rule.matches(matrixValue{"server": "linux", "client": "linux", "arch": "x86_64"}), ShouldBeFalse)
+			So(rule.matches(matrixValue{"server": "linux", "arch": "x86_64"}), ShouldBeFalse)
+
+			same := matrixCellRule{SameValue: []string{"server", "client"}}
+			So(same.matches(matrixValue{"server": "macos", "client": "macos"}), ShouldBeTrue)
+			So(same.matches(matrixValue{"server": "macos", "client": "linux"}), ShouldBeFalse)
+		})
+		Convey("invalid rules should fail to evaluate", func() {
+			_, errs := evaluateCellRules(ase, []matrixCellRule{
+				{},
+				{SameValue: []string{"server"}},
+				{If: matrixDefinitions{{"server": []string{"salmon"}}}},
+			}, "exclude")
+			So(len(errs), ShouldEqual, 3)
+			_, errs = evaluateCellRules(ase, []matrixCellRule{{SameValue: []string{"server", "client"}}}, "include")
+			So(len(errs), ShouldEqual, 1)
+		})
+		Convey("a matrix with exclude and include rules", func() {
+			m := matrix{
+				Id: "compat",
+				Spec: matrixDefinition{
+					"server": []string{"*"},
+					"client": []string{"*"},
+					"arch":   []string{"*"},
+				},
+				ExcludeRules: []matrixCellRule{
+					{If: matrixDefinitions{{"server": []string{"windows"}, "arch": []string{"arm64"}}}},
+					{DifferentValue: []string{"server", "client"}, Unless: matrixDefinitions{{"server": []string{".posix"}, "client": []string{".posix"}}}},
+				},
+				IncludeRules: []matrixCellRule{
+					{If: matrixDefinitions{{"server": []string{"*"}, "client": []string{"windows"}, "arch": []string{"x86_64"}}}},
+				},
+			}
+			Convey("should exclude and include the right cells", func() {
+				cells, errs := m.evaluateCells(ase)
+				So(errs, ShouldBeNil)
+				// posix servers and clients in any combination, plus windows
+				// with itself, plus windows clients.
+				So(len(cells.cells), ShouldEqual, 2*2*2+1+2)
+				So(cells.cells, ShouldContainResembling, matrixValue{"server": "linux", "client": "macos", "arch": "arm64"})
+				So(cells.cells, ShouldContainResembling, matrixValue{"server": "windows", "client": "windows", "arch": "x86_64"})
+				So(cells.cells, ShouldContainResembling, matrixValue{"server": "macos", "client": "windows", "arch": "x86_64"})
+				cellKeys := map[string]bool{}
+				for _, cell := range cells.cells {
+					cellKeys[cell.String()] = true
+				}
+				So(cellKeys[matrixValue{"server": "windows", "client": "windows", "arch": "arm64"}.String()], ShouldBeFalse)
+				So(cellKeys[matrixValue{"server": "windows", "client": "linux", "arch": "x86_64"}.String()], ShouldBeFalse)
+				So(len(cells.included), ShouldEqual, 2)
+				So(len(cells.excluded), ShouldEqual, 3*3*2-(2*2*2+1)-2)
+			})
+			Convey("should fill axes omitted by an include rule with the spec's values", func() {
+				m.ExcludeRules = []matrixCellRule{{If: matrixDefinitions{{"server": []string{"windows"}}}}}
+				m.IncludeRules = []matrixCellRule{{If: matrixDefinitions{{"server": []string{"windows"}, "client": []string{"linux"}}}}}
+				cells, errs := m.evaluateCells(ase)
+				So(errs, ShouldBeNil)
+				So(len(cells.included), ShouldEqual, 2)
+				So(cells.cells, ShouldContainResembling, matrixValue{"server": "windows", "client": "linux", "arch": "x86_64"})
+				So(cells.cells, ShouldContainResembling, matrixValue{"server": "windows", "client": "linux", "arch": "arm64"})
+				for _, cell := range cells.cells {
+					So(len(cell), ShouldEqual, 3)
+				}
+			})
+			Convey("should fail if the exclude rules exclude nothing", func() {
+				m.ExcludeRules = []matrixCellRule{{If: matrixDefinitions{{"arch": []string{"arm64"}, "server": []string{"linux"}, "client": []string{"linux"}}}, Unless: matrixDefinitions{{"arch": []string{"arm64"}}}}}
+				_, errs := m.evaluateCells(ase)
+				So(len(errs), ShouldEqual, 2)
+			})
+		})
+		Convey("a matrix with depends_on should expand it for each cell", func() {
+			m := matrix{
+				Id:   "test",
+				Spec: matrixDefinition{"server": []string{"*"}, "client": []string{"linux"}},
+				DependsOn: parserDependencies{{TaskSelector: taskSelector{
+					Name:    "compile",
+					Variant: &variantSelector{MatrixSelector: matrixDefinition{"server": []string{"${server}"}}},
+				}}},
+			}
+			vs, errs := buildMatrixVariants(axes, ase, []matrix{m})
+			So(errs, ShouldBeNil)
+			So(len(vs), ShouldEqual, 3)
+			for _, v := range vs {
+				So(len(v.DependsOn), ShouldEqual, 1)
+				So(v.DependsOn[0].TaskSelector.Variant.MatrixSelector, ShouldResemble, matrixDefinition{"server": []string{v.MatrixVal["server"]}})
+			}
+		})
+	})
+}
@@ -887,9 +887,13 @@ func processIntermediateProjectIncludes(ctx context.Context, intermediateProject
 		AutoUpdateModuleRevisions: projectOpts.AutoUpdateModuleRevisions,
 		IsIncludedFile:            true,
 		LocalIncludeDir:           projectOpts.LocalIncludeDir,
+		Worktree:                  projectOpts.Worktree,
+		RepoSourceToken:           projectOpts.RepoSourceToken,
 	}
 	if projectOpts.Ref != nil {
-		localOpts.Worktree = dirs.getWorktreeForOwnerRepoWorker(projectOpts.Ref.Owner, projectOpts.Ref.Repo, workerIdx)
+		if worktree := dirs.getWorktreeForOwnerRepoWorker(projectOpts.Ref.Owner, projectOpts.Ref.Repo, workerIdx); worktree != "" {
+			localOpts.Worktree = worktree
+		}
 	}
 	localOpts.UpdateReadFileFrom(include.FileName)
 
@@ -1334,6 +1338,11 @@ const (
 	ReadFromLocal     = "local"
 	ReadFromPatch     = "patch"
 	ReadFromPatchDiff = "patch_diff"
+	// ReadFromGitLab reads files from the project's GitLab repository.
+	ReadFromGitLab = "gitlab"
+	// ReadFromGit reads files from the git clone of the project's repository
+	// in Worktree.
+	ReadFromGit = "git"
 )
 
 // readFromRemoteSource returns true if the readFrom option requires retrieving
@@ -1366,6 +1375,9 @@ type GetProjectOpts struct {
 	// LocalIncludeDir is the base directory for resolving relative include
 	// file paths when ReadFileFrom is ReadFromLocal.
 	LocalIncludeDir string
+	// RepoSourceToken is the access token used to read files when
+	// ReadFileFrom is ReadFromGitLab.
+	RepoSourceToken string
 	// EnableYAMLAnchors opts into cross-file YAML anchor and alias support.
 	EnableYAMLAnchors bool
 	// cacheEnabled routes the translate step through the content-hash translation cache. It is only
@@ -1426,6 +1438,21 @@ func retrieveFile(ctx context.Context, opts GetProjectOpts) ([]byte, error) {
 	switch opts.ReadFileFrom {
 	case ReadFromLocal:
 		return readLocalInclude(opts.RemotePath, opts.LocalIncludeDir)
+	case ReadFromGitLab:
+		fileContents, err := getFileFromGitLab(ctx, opts)
+		if err != nil {
+			return nil, errors.Wrapf(err, "fetching project config file at revision '%s' from GitLab", opts.Revision)
+		}
+		return fileContents, nil
+	case ReadFromGit:
+		if opts.Worktree == "" {
+			return nil, errors.New("reading files using git requires a git clone")
+		}
+		fileContents, err := thirdparty.GitShowFile(ctx, opts.Worktree, opts.Revision, opts.RemotePath)
+		if err != nil {
+			return nil, errors.Wrapf(err, "fetching project config file at revision '%s' using git", opts.Revision)
+		}
+		return fileContents, nil
 	case ReadFromPatch:
 		fileContents, err := getFileForPatchDiff(ctx, opts)
 		if err != nil {
@@ -30,6 +30,7 @@ import (
 	"github.com/mongodb/anser/bsonutil"
 	adb "github.com/mongodb/anser/db"
 	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/level"
 	"github.com/mongodb/grip/message"
 	"github.com/mongodb/grip/recovery"
 	"github.com/mongodb/jasper"
@@ -61,6 +62,7 @@ type ProjectRef struct {
 	WaterfallDisabled      *bool               `bson:"waterfall_disabled,omitempty" json:"waterfall_disabled,omitempty" yaml:"waterfall_disabled"`
 	StepbackDisabled       *bool               `bson:"stepback_disabled,omitempty" json:"stepback_disabled,omitempty" yaml:"stepback_disabled"`
 	StepbackBisect         *bool               `bson:"stepback_bisect,omitempty" json:"stepback_bisect,omitempty" yaml:"stepback_bisect"`
+	CulpritFinderEnabled   *bool               `bson:"culprit_finder_enabled,omitempty" json:"culprit_finder_enabled,omitempty" yaml:"culprit_finder_enabled"`
 	VersionControlEnabled  *bool               `bson:"version_control_enabled,omitempty" json:"version_control_enabled,omitempty" yaml:"version_control_enabled"`
 	PRTestingEnabled       *bool               `bson:"pr_testing_enabled,omitempty" json:"pr_testing_enabled,omitempty" yaml:"pr_testing_enabled"`
 	ManualPRTestingEnabled *bool               `bson:"manual_pr_testing_enabled,omitempty" json:"manual_pr_testing_enabled,omitempty" yaml:"manual_pr_testing_enabled"`
@@ -69,6 +71,9 @@ type ProjectRef struct {
 	DeactivatePrevious     *bool               `bson:"deactivate_previous,omitempty" json:"deactivate_previous,omitempty" yaml:"deactivate_previous"`
 	NotifyOnBuildFailure   *bool               `bson:"notify_on_failure,omitempty" json:"notify_on_failure,omitempty"`
 	Triggers               []TriggerDefinition `bson:"triggers" json:"triggers"`
+	// Pipelines are fan-in triggers that create a version of this project
+	// once all of their upstream projects have succeeded.
+	Pipelines []PipelineDefinition `bson:"pipelines" json:"pipelines"`
 	// PatchTriggerAliases contains all aliases defined for the project.
 	PatchTriggerAliases []patch.PatchTriggerDefinition `bson:"patch_trigger_aliases" json:"patch_trigger_aliases"`
 	// GithubPRTriggerAliases are aliases attached to GitHub PR patch intents.
@@ -148,6 +153,19 @@ type ProjectRef struct {
 	// TaskOwnership contains default team ownership settings for tasks. This is related to Foliage Web Services (FWS).
 	TaskOwnership TaskOwnershipSettings `bson:"task_ownership,omitempty" json:"task_ownership,omitzero" yaml:"task_ownership,omitempty"`
 
+	// LogRetention configures how long the logs of the project's finished tasks are kept.
+	LogRetention LogRetentionSettings `bson:"log_retention,omitempty" json:"log_retention,omitzero" yaml:"log_retention,omitempty"`
+
+	// CostBudget configures the project's daily and monthly cost budgets.
+	CostBudget CostBudgetSettings `bson:"cost_budget,omitempty" json:"cost_budget,omitzero" yaml:"cost_budget,omitempty"`
+
+	// FlakyTestQuarantine configures automatically quarantining the project's flaky tests.
+	FlakyTestQuarantine FlakyTestQuarantineSettings `bson:"flaky_test_quarantine,omitempty" json:"flaky_test_quarantine,omitzero" yaml:"flaky_test_quarantine,omitempty"`
+
+	// RepoSource configures where the project's repository is hosted if it is
+	// not on GitHub.
+	RepoSource RepoSourceSettings `bson:"repo_source,omitempty" json:"repo_source,omitzero" yaml:"repo_source,omitempty"`
+
 	// RunEveryMainlineCommit indicates that the project should activate the versions for all mainline commits.
 	// This goes against Evergreen's optimization of only activating the latest commit in a series of mainline commits.
 	// This is used for projects that use tasks on mainline commits to trigger downstream processes, like deployments.
@@ -457,6 +475,36 @@ type TriggerDefinition struct {
 	UnscheduleDownstreamVersions bool   `bson:"unschedule_downstream_versions,omitempty" json:"unschedule_downstream_versions,omitempty"`
 }
 
+// PipelineDefinition is a fan-in trigger, which creates a version of the
+// project once every one of its upstream projects has succeeded for the same
+// revision, git tag or manifest.
+type PipelineDefinition struct {
+	// ID is used to track the runs of the pipeline.
+	ID        string             `bson:"id" json:"id"`
+	Upstreams []PipelineUpstream `bson:"upstreams" json:"upstreams"`
+	// MatchBy is what the upstream versions must share to be part of the
+	// same run: their revision, a git tag or the revision of a repository in
+	// their manifests.
+	MatchBy string `bson:"match_by" json:"match_by"`
+	// ManifestRepo is the owner/repo of the repository whose revision the
+	// upstream versions must share when matching by manifest. An upstream
+	// project that tracks that repository matches by its own revision.
+	ManifestRepo string `bson:"manifest_repo,omitempty" json:"manifest_repo,omitempty"`
+
+	// definitions for tasks to run for this pipeline
+	ConfigFile                   string `bson:"config_file,omitempty" json:"config_file,omitempty"`
+	Alias                        string `bson:"alias,omitempty" json:"alias,omitempty"`
+	UnscheduleDownstreamVersions bool   `bson:"unschedule_downstream_versions,omitempty" json:"unschedule_downstream_versions,omitempty"`
+}
+
+// PipelineUpstream is a project that a pipeline waits on.
+type PipelineUpstream struct {
+	Project string `bson:"project" json:"project"`
+	// BuildVariantRegex limits the builds that must succeed to those whose
+	// variant matches. Otherwise, all of the version's activated builds must.
+	BuildVariantRegex string `bson:"variant_regex,omitempty" json:"variant_regex,omitempty"`
+}
+
 type PeriodicBuildDefinition struct {
 	ID            string    `bson:"id" json:"id"`
 	ConfigFile    string    `bson:"config_file" json:"config_file"`
@@ -465,6 +513,38 @@ type PeriodicBuildDefinition struct {
 	Alias         string    `bson:"alias,omitempty" json:"alias,omitempty"`
 	Message       string    `bson:"message,omitempty" json:"message,omitempty"`
 	NextRunTime   time.Time `bson:"next_run_time,omitempty" json:"next_run_time,omitempty"`
+	// Timezone is the IANA time zone that the cron is evaluated in. It
+	// defaults to UTC.
+	Timezone string `bson:"timezone,omitempty" json:"timezone,omitempty"`
+	// BlackoutWindows are periods, such as release freezes, during which
+	// scheduled runs are skipped.
+	BlackoutWindows []PeriodicBuildBlackoutWindow `bson:"blackout_windows,omitempty" json:"blackout_windows,omitempty"`
+	// SkipIfUnchanged skips a scheduled run if the tracked branch's most
+	// recent revision is the same as the last periodic build's.
+	SkipIfUnchanged bool `bson:"skip_if_unchanged,omitempty" json:"skip_if_unchanged,omitempty"`
+	// LastManualRunBy and LastManualRunTime record who last ran the periodic
+	// build on demand, outside of its schedule, and when.
+	LastManualRunBy   string    `bson:"last_manual_run_by,omitempty" json:"last_manual_run_by,omitempty"`
+	LastManualRunTime time.Time `bson:"last_manual_run_time,omitempty" json:"last_manual_run_time,omitempty"`
+}
+
+// PeriodicBuildBlackoutWindow is a period during which a periodic build's
+// scheduled runs are skipped.
+type PeriodicBuildBlackoutWindow struct {
+	Start  time.Time `bson:"start" json:"start"`
+	End    time.Time `bson:"end" json:"end"`
+	Reason string    `bson:"reason,omitempty" json:"reason,omitempty"`
+}
+
+// InBlackout returns the blackout window that the given time falls in, if
+// any.
+func (d *PeriodicBuildDefinition) InBlackout(t time.Time) *PeriodicBuildBlackoutWindow {
+	for i, w := range d.BlackoutWindows {
+		if !t.Before(w.Start) && t.Before(w.End) {
+			return &d.BlackoutWindows[i]
+		}
+	}
+	return nil
 }
 
 type WorkstationConfig struct {
@@ -509,6 +589,158 @@ type TaskOwnershipSettings struct {
 	DefaultMothraTeamForBreakingCommit string `bson:"default_mothra_team_for_breaking_commit,omitempty" json:"default_mothra_team_for_breaking_commit,omitempty" yaml:"default_mothra_team_for_breaking_commit,omitempty"`
 }
 
+// LogReduction is how the logs of a task are reduced once they are past the
+// full retention period.
+type LogReduction string
+
+const (
+	// LogReductionTail keeps the last lines of each log.
+	LogReductionTail LogReduction = "tail"
+	// LogReductionErrors keeps the lines of each log logged at error
+	// priority or above.
+	LogReductionErrors LogReduction = "errors"
+)
+
+// DefaultLogRetentionTailLines is the default number of lines kept in each log
+// by the tail reduction.
+const DefaultLogRetentionTailLines = 1000
+
+// LogRetentionSettings configures the retention tiers of the logs of this
+// project's finished tasks. Logs are always compacted shortly after a task
+// finishes. Afterwards, they are kept in full for ReduceAfterDays days, then
+// only the lines selected by Reduction are kept until DeleteAfterDays days
+// have passed, at which point they are deleted.
+type LogRetentionSettings struct {
+	// ReduceAfterDays is the number of days after a task finishes that its
+	// logs are kept in full. Logs are never reduced if this is 0.
+	ReduceAfterDays int `bson:"reduce_after_days,omitempty" json:"reduce_after_days,omitempty" yaml:"reduce_after_days,omitempty"`
+	// Reduction is how the logs are reduced. Defaults to LogReductionTail.
+	Reduction LogReduction `bson:"reduction,omitempty" json:"reduction,omitempty" yaml:"reduction,omitempty"`
+	// TailLines is the number of lines kept in each log by the tail
+	// reduction. Defaults to DefaultLogRetentionTailLines.
+	TailLines int `bson:"tail_lines,omitempty" json:"tail_lines,omitempty" yaml:"tail_lines,omitempty"`
+	// DeleteAfterDays is the number of days after a task finishes that its
+	// logs are deleted. Logs are never deleted if this is 0.
+	DeleteAfterDays int `bson:"delete_after_days,omitempty" json:"delete_after_days,omitempty" yaml:"delete_after_days,omitempty"`
+}
+
+// IsZero returns whether the project has no log retention tiers configured.
+func (s LogRetentionSettings) IsZero() bool {
+	return s.ReduceAfterDays == 0 && s.DeleteAfterDays == 0
+}
+
+// Validate checks that the log retention settings are valid.
+func (s LogRetentionSettings) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	catcher.NewWhen(s.ReduceAfterDays < 0, "days before reducing logs cannot be negative")
+	catcher.NewWhen(s.DeleteAfterDays < 0, "days before deleting logs cannot be negative")
+	catcher.NewWhen(s.TailLines < 0, "number of tail lines cannot be negative")
+	catcher.ErrorfWhen(s.ReduceAfterDays > 0 && s.DeleteAfterDays > 0 && s.DeleteAfterDays <= s.ReduceAfterDays,
+		"logs must be deleted after they are reduced, but deletion is after %d days and reduction is after %d days", s.DeleteAfterDays, s.ReduceAfterDays)
+	switch s.Reduction {
+	case "", LogReductionTail, LogReductionErrors:
+	default:
+		catcher.Errorf("unrecognized log reduction '%s'", s.Reduction)
+	}
+
+	return catcher.Resolve()
+}
+
+// ReductionOptions returns the options to reduce the logs of a task with.
+func (s LogRetentionSettings) ReductionOptions() task.LogReductionOptions {
+	if s.Reduction == LogReductionErrors {
+		return task.LogReductionOptions{MinPriority: level.Error}
+	}
+
+	tailLines := s.TailLines
+	if tailLines == 0 {
+		tailLines = DefaultLogRetentionTailLines
+	}
+
+	return task.LogReductionOptions{TailN: tailLines}
+}
+
+const (
+	// DefaultFlakyTestQuarantineThreshold is the default flakiness score at
+	// or above which a test is quarantined.
+	DefaultFlakyTestQuarantineThreshold = 0.3
+	// DefaultFlakyTestMinRuns is the default number of runs a test needs
+	// before it can be quarantined.
+	DefaultFlakyTestMinRuns = 10
+	// DefaultFlakyTestReleaseAfterCleanRuns is the default number of
+	// consecutive passing runs after which a quarantined test is released.
+	DefaultFlakyTestReleaseAfterCleanRuns = 10
+)
+
+// FlakyTestQuarantineSettings configures automatically quarantining the
+// project's flaky tests. Each test is periodically given a flakiness score
+// from its recent mainline history, and tests that score at or above the
+// threshold are skipped by test selection until they pass enough times in a
+// row.
+type FlakyTestQuarantineSettings struct {
+	// Enabled is whether flaky tests are quarantined automatically.
+	Enabled bool `bson:"enabled,omitempty" json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	// Threshold is the flakiness score, between 0 and 1, at or above which a
+	// test is quarantined. Defaults to DefaultFlakyTestQuarantineThreshold.
+	Threshold float64 `bson:"threshold,omitempty" json:"threshold,omitempty" yaml:"threshold,omitempty"`
+	// MinRuns is the number of runs a test must have before it can be
+	// quarantined. Defaults to DefaultFlakyTestMinRuns.
+	MinRuns int `bson:"min_runs,omitempty" json:"min_runs,omitempty" yaml:"min_runs,omitempty"`
+	// ReleaseAfterCleanRuns is the number of consecutive passing runs after
+	// which a quarantined test is released. Defaults to
+	// DefaultFlakyTestReleaseAfterCleanRuns.
+	ReleaseAfterCleanRuns int `bson:"release_after_clean_runs,omitempty" json:"release_after_clean_runs,omitempty" yaml:"release_after_clean_runs,omitempty"`
+}
+
+// Validate checks that the flaky test quarantine settings are valid.
+func (s FlakyTestQuarantineSettings) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	catcher.NewWhen(s.Threshold < 0 || s.Threshold > 1, "flakiness threshold must be between 0 and 1")
+	catcher.NewWhen(s.MinRuns < 0, "minimum number of runs cannot be negative")
+	catcher.NewWhen(s.ReleaseAfterCleanRuns < 0, "number of clean runs before release cannot be negative")
+	return catcher.Resolve()
+}
+
+// ValidateFlakyTestQuarantine checks that the project's flaky test quarantine
+// settings are valid. Quarantined tests are only skipped by test selection, so
+// quarantine can only be enabled if the project allows test selection.
+func (p *ProjectRef) ValidateFlakyTestQuarantine() error {
+	if err := p.FlakyTestQuarantine.Validate(); err != nil {
+		return err
+	}
+	if p.FlakyTestQuarantine.Enabled && !p.IsTestSelectionAllowed() {
+		return errors.New("flaky test quarantine requires test selection to be allowed")
+	}
+	return nil
+}
+
+// GetThreshold returns the flakiness score at or above which a test is
+// quarantined.
+func (s FlakyTestQuarantineSettings) GetThreshold() float64 {
+	if s.Threshold == 0 {
+		return DefaultFlakyTestQuarantineThreshold
+	}
+	return s.Threshold
+}
+
+// GetMinRuns returns the number of runs a test must have before it can be
+// quarantined.
+func (s FlakyTestQuarantineSettings) GetMinRuns() int {
+	if s.MinRuns == 0 {
+		return DefaultFlakyTestMinRuns
+	}
+	return s.MinRuns
+}
+
+// GetReleaseAfterCleanRuns returns the number of consecutive passing runs
+// after which a quarantined test is released.
+func (s FlakyTestQuarantineSettings) GetReleaseAfterCleanRuns() int {
+	if s.ReleaseAfterCleanRuns == 0 {
+		return DefaultFlakyTestReleaseAfterCleanRuns
+	}
+	return s.ReleaseAfterCleanRuns
+}
+
 var (
 	// bson fields for the ProjectRef struct
 	ProjectRefIdKey                                 = bsonutil.MustHaveTag(ProjectRef{}, "Id")
@@ -540,11 +772,13 @@ var (
 	projectRefWaterfallDisabledKey                  = bsonutil.MustHaveTag(ProjectRef{}, "WaterfallDisabled")
 	projectRefStepbackDisabledKey                   = bsonutil.MustHaveTag(ProjectRef{}, "StepbackDisabled")
 	projectRefStepbackBisectKey                     = bsonutil.MustHaveTag(ProjectRef{}, "StepbackBisect")
+	projectRefCulpritFinderEnabledKey               = bsonutil.MustHaveTag(ProjectRef{}, "CulpritFinderEnabled")
 	projectRefVersionControlEnabledKey              = bsonutil.MustHaveTag(ProjectRef{}, "VersionControlEnabled")
 	projectRefNotifyOnFailureKey                    = bsonutil.MustHaveTag(ProjectRef{}, "NotifyOnBuildFailure")
 	projectRefSpawnHostScriptPathKey                = bsonutil.MustHaveTag(ProjectRef{}, "SpawnHostScriptPath")
 	projectRefDebugSpawnHostsDisabledKey            = bsonutil.MustHaveTag(ProjectRef{}, "DebugSpawnHostsDisabled")
 	projectRefTriggersKey                           = bsonutil.MustHaveTag(ProjectRef{}, "Triggers")
+	projectRefPipelinesKey                          = bsonutil.MustHaveTag(ProjectRef{}, "Pipelines")
 	projectRefPatchTriggerAliasesKey                = bsonutil.MustHaveTag(ProjectRef{}, "PatchTriggerAliases")
 	projectRefGithubPRTriggerAliasesKey             = bsonutil.MustHaveTag(ProjectRef{}, "GithubPRTriggerAliases")
 	projectRefGithubMQTriggerAliasesKey             = bsonutil.MustHaveTag(ProjectRef{}, "GithubMQTriggerAliases")
@@ -565,9 +799,15 @@ var (
 	projectRefNumAutoRestartedTasksKey              = bsonutil.MustHaveTag(ProjectRef{}, "NumAutoRestartedTasks")
 	projectRefTestSelectionKey                      = bsonutil.MustHaveTag(ProjectRef{}, "TestSelection")
 	projectRefTaskOwnershipKey                      = bsonutil.MustHaveTag(ProjectRef{}, "TaskOwnership")
-
-	commitQueueEnabledKey       = bsonutil.MustHaveTag(CommitQueueParams{}, "Enabled")
-	triggerDefinitionProjectKey = bsonutil.MustHaveTag(TriggerDefinition{}, "Project")
+	projectRefLogRetentionKey                       = bsonutil.MustHaveTag(ProjectRef{}, "LogRetention")
+	projectRefCostBudgetKey                         = bsonutil.MustHaveTag(ProjectRef{}, "CostBudget")
+	projectRefFlakyTestQuarantineKey                = bsonutil.MustHaveTag(ProjectRef{}, "FlakyTestQuarantine")
+	projectRefRepoSourceKey                         = bsonutil.MustHaveTag(ProjectRef{}, "RepoSource")
+
+	commitQueueEnabledKey          = bsonutil.MustHaveTag(CommitQueueParams{}, "Enabled")
+	triggerDefinitionProjectKey    = bsonutil.MustHaveTag(TriggerDefinition{}, "Project")
+	pipelineDefinitionUpstreamsKey = bsonutil.MustHaveTag(PipelineDefinition{}, "Upstreams")
+	pipelineUpstreamProjectKey     = bsonutil.MustHaveTag(PipelineUpstream{}, "Project")
 )
 
 func (p *ProjectRef) IsRestricted() bool {
@@ -602,6 +842,10 @@ func (p *ProjectRef) IsStepbackBisect() bool {
 	return utility.FromBoolPtr(p.StepbackBisect)
 }
 
+func (p *ProjectRef) IsCulpritFinderEnabled() bool {
+	return utility.FromBoolPtr(p.CulpritFinderEnabled)
+}
+
 func (p *ProjectRef) IsAutoPRTestingEnabled() bool {
 	return utility.FromBoolPtr(p.PRTestingEnabled)
 }
@@ -711,8 +955,11 @@ const (
 	ProjectTriggerLevelTask  = "task"
 	ProjectTriggerLevelBuild = "build"
 	ProjectTriggerLevelPush  = "push"
-	intervalPrefix           = "@every"
-	maxBatchTime             = 153722867 // math.MaxInt64 / 60 / 1_000_000_000
+	// ProjectTriggerLevelPipeline is the trigger type of versions created by
+	// pipelines.
+	ProjectTriggerLevelPipeline = "pipeline"
+	intervalPrefix              = "@every"
+	maxBatchTime                = 153722867 // math.MaxInt64 / 60 / 1_000_000_000
 )
 
 type ProjectPageSection string
@@ -2414,6 +2661,7 @@ func SaveProjectPageForSection(ctx context.Context, projectId string, p *Project
 			projectRefWaterfallDisabledKey:       p.WaterfallDisabled,
 			projectRefStepbackDisabledKey:        p.StepbackDisabled,
 			projectRefStepbackBisectKey:          p.StepbackBisect,
+			projectRefCulpritFinderEnabledKey:    p.CulpritFinderEnabled,
 			projectRefVersionControlEnabledKey:   p.VersionControlEnabled,
 			ProjectRefDeactivatePreviousKey:      p.DeactivatePrevious,
 			projectRefRepotrackerDisabledKey:     p.RepotrackerDisabled,
@@ -2421,6 +2669,10 @@ func SaveProjectPageForSection(ctx context.Context, projectId string, p *Project
 			ProjectRefDisabledStatsCacheKey:      p.DisabledStatsCache,
 			projectRefDebugSpawnHostsDisabledKey: p.DebugSpawnHostsDisabled,
 			projectRefRunEveryMainlineCommitKey:  p.RunEveryMainlineCommit,
+			projectRefLogRetentionKey:            p.LogRetention,
+			projectRefCostBudgetKey:              p.CostBudget,
+			projectRefFlakyTestQuarantineKey:     p.FlakyTestQuarantine,
+			projectRefRepoSourceKey:              p.RepoSource,
 		}
 		// Allow a user to modify owner and repo only if they are editing an unattached project
 		if !isRepo && !p.UseRepoSettings() && !defaultToRepo {
@@ -3039,6 +3291,10 @@ func (p *ProjectRef) ValidateOwnerAndRepo(validOrgs []string) error {
 	if p.Owner == "" || p.Repo == "" {
 		return errors.New("no owner/repo specified")
 	}
+	if p.RepoSource.GetProvider() != RepoProviderGitHub {
+		// The allowed organizations only apply to GitHub.
+		return nil
+	}
 
 	return validateOwner(p.Owner, validOrgs)
 }
@@ -3283,12 +3539,20 @@ func getNextRunTime(baseTime time.Time, definition *PeriodicBuildDefinition) (ti
 	if definition.IntervalHours > 0 {
 		nextRunTime = baseTime.Add(time.Duration(definition.IntervalHours) * time.Hour)
 	} else {
+		if definition.Timezone != "" {
+			loc, err := time.LoadLocation(definition.Timezone)
+			if err != nil {
+				return time.Time{}, errors.Wrapf(err, "loading timezone '%s'", definition.Timezone)
+			}
+			// The cron schedule is evaluated in the base time's location.
+			baseTime = baseTime.In(loc)
+		}
 		nextRunTime, err = GetNextCronTime(baseTime, definition.Cron)
 		if err != nil {
 			return time.Time{}, errors.Wrap(err, "getting next run time with cron")
 		}
 	}
-	return nextRunTime, nil
+	return nextRunTime.UTC(), nil
 }
 
 // UpdateNextPeriodicBuild updates the periodic build run time for either the project
@@ -3316,7 +3580,41 @@ func UpdateNextPeriodicBuild(ctx context.Context, projectId string, definition *
 			return errors.Wrap(err, "updating next run time")
 		}
 	}
-	// Get the branch project on its own so we can determine where to update the run time.
+	return updatePeriodicBuildDefinition(ctx, projectId, definition.ID, bson.M{"next_run_time": nextRunTime})
+}
+
+// RecordPeriodicBuildManualRun records that the user ran the periodic build
+// on demand at the given time.
+func RecordPeriodicBuildManualRun(ctx context.Context, projectId, definitionID, user string, runTime time.Time) error {
+	return updatePeriodicBuildDefinition(ctx, projectId, definitionID, bson.M{
+		"last_manual_run_by":   user,
+		"last_manual_run_time": runTime,
+	})
+}
+
+// PreservePeriodicBuildManualRuns sets the last manual run of each updated
+// periodic build definition to that of the existing definition with the same
+// ID. The last manual run is only recorded by running the periodic build, so it
+// should never be changed by saving the definitions.
+func PreservePeriodicBuildManualRuns(existing, updated []PeriodicBuildDefinition) {
+	for i := range updated {
+		updated[i].LastManualRunBy = ""
+		updated[i].LastManualRunTime = time.Time{}
+		for _, d := range existing {
+			if d.ID == updated[i].ID {
+				updated[i].LastManualRunBy = d.LastManualRunBy
+				updated[i].LastManualRunTime = d.LastManualRunTime
+				break
+			}
+		}
+	}
+}
+
+// updatePeriodicBuildDefinition sets the given fields of the periodic build
+// definition for either the project or repo ref depending on where it's
+// defined.
+func updatePeriodicBuildDefinition(ctx context.Context, projectId, definitionID string, fields bson.M) error {
+	// Get the branch project on its own so we can determine where to update the definition.
 	projectRef, err := FindBranchProjectRef(ctx, projectId)
 	if err != nil {
 		return errors.Wrap(err, "finding branch project")
@@ -3340,7 +3638,7 @@ func UpdateNextPeriodicBuild(ctx context.Context, projectId string, definition *
 			return errors.Errorf("repo '%s' not found", projectRef.RepoRefId)
 		}
 		for _, d := range repoRef.PeriodicBuilds {
-			if d.ID == definition.ID {
+			if d.ID == definitionID {
 				collection = RepoRefCollection
 				buildsKey = RepoRefPeriodicBuildsKey
 				documentIdKey = RepoRefIdKey
@@ -3353,14 +3651,16 @@ func UpdateNextPeriodicBuild(ctx context.Context, projectId string, definition *
 		documentIdKey: idToUpdate,
 		buildsKey: bson.M{
 			"$elemMatch": bson.M{
-				"id": definition.ID,
+				"id": definitionID,
 			},
 		},
 	}
+	set := bson.M{}
+	for field, value := range fields {
+		set[bsonutil.GetDottedKeyName(buildsKey, "$", field)] = value
+	}
 	update := bson.M{
-		"$set": bson.M{
-			bsonutil.GetDottedKeyName(buildsKey, "$", "next_run_time"): nextRunTime,
-		},
+		"$set": set,
 	}
 
 	res, err := evergreen.GetEnvironment().DB().Collection(collection).UpdateOne(ctx,
@@ -3371,10 +3671,10 @@ func UpdateNextPeriodicBuild(ctx context.Context, projectId string, definition *
 		return errors.Wrapf(err, "updating task")
 	}
 	if res.MatchedCount == 0 {
-		return errors.Errorf("periodic build definition '%s' on project '%s' not found", definition.ID, idToUpdate)
+		return errors.Errorf("periodic build definition '%s' on project '%s' not found", definitionID, idToUpdate)
 	}
 	if res.UpsertedCount+res.ModifiedCount == 0 {
-		return errors.Errorf("periodic build definition '%s' on project '%s' was not updated", definition.ID, idToUpdate)
+		return errors.Errorf("periodic build definition '%s' on project '%s' was not updated", definitionID, idToUpdate)
 	}
 	return nil
 }
@@ -3477,6 +3777,91 @@ func (t *TriggerDefinition) Validate(ctx context.Context, downstreamProject stri
 	return nil
 }
 
+const (
+	PipelineMatchByRevision = "revision"
+	PipelineMatchByGitTag   = "git_tag"
+	PipelineMatchByManifest = "manifest"
+)
+
+var validPipelineMatchBy = []string{PipelineMatchByRevision, PipelineMatchByGitTag, PipelineMatchByManifest}
+
+// Validate checks that the pipeline's upstream projects exist and that it
+// can create a downstream version, and fills in its defaults.
+func (d *PipelineDefinition) Validate(ctx context.Context, downstreamProject string) error {
+	if len(d.Upstreams) == 0 {
+		return errors.New("must specify at least one upstream project")
+	}
+	seen := map[string]bool{}
+	upstreamRepos := []string{}
+	for i, u := range d.Upstreams {
+		upstreamProject, err := FindBranchProjectRef(ctx, u.Project)
+		if err != nil {
+			return errors.Wrapf(err, "finding upstream project '%s'", u.Project)
+		}
+		if upstreamProject == nil {
+			return errors.Errorf("project '%s' not found", u.Project)
+		}
+		if upstreamProject.Id == downstreamProject {
+			return errors.New("a project cannot be its own upstream")
+		}
+		if seen[upstreamProject.Id] {
+			return errors.Errorf("upstream project '%s' is listed more than once", u.Project)
+		}
+		seen[upstreamProject.Id] = true
+		upstreamRepos = utility.UniqueStrings(append(upstreamRepos, fmt.Sprintf("%s/%s", upstreamProject.Owner, upstreamProject.Repo)))
+		if _, err = regexp.Compile(u.BuildVariantRegex); err != nil {
+			return errors.Wrapf(err, "invalid variant regex '%s'", u.BuildVariantRegex)
+		}
+
+		// should be saved using its ID, in case the user used the project's identifier
+		d.Upstreams[i].Project = upstreamProject.Id
+	}
+
+	if d.MatchBy == "" {
+		d.MatchBy = PipelineMatchByRevision
+	}
+	if !utility.StringSliceContains(validPipelineMatchBy, d.MatchBy) {
+		return errors.Errorf("invalid match by '%s'", d.MatchBy)
+	}
+	// Versions of different repositories never share a revision, so the
+	// pipeline could never run.
+	if d.MatchBy == PipelineMatchByRevision && len(upstreamRepos) > 1 {
+		return errors.Errorf("upstream projects track different repositories (%s), so they must be matched by '%s' or '%s' instead of '%s'", strings.Join(upstreamRepos, ", "), PipelineMatchByManifest, PipelineMatchByGitTag, PipelineMatchByRevision)
+	}
+	if d.MatchBy == PipelineMatchByManifest {
+		if owner, repo, ok := strings.Cut(d.ManifestRepo, "/"); !ok || owner == "" || repo == "" {
+			return errors.Errorf("manifest repo '%s' must be in the form owner/repo", d.ManifestRepo)
+		}
+	}
+	if d.ConfigFile == "" {
+		return errors.New("must provide a config file")
+	}
+	if d.ID == "" {
+		d.ID = utility.RandomString()
+	}
+	return nil
+}
+
+// UpstreamProjects returns the IDs of the projects the pipeline waits on.
+func (d *PipelineDefinition) UpstreamProjects() []string {
+	projects := make([]string, 0, len(d.Upstreams))
+	for _, u := range d.Upstreams {
+		projects = append(projects, u.Project)
+	}
+	return projects
+}
+
+// GetUpstream returns the given upstream project of the pipeline, or nil if
+// the pipeline doesn't wait on it.
+func (d *PipelineDefinition) GetUpstream(project string) *PipelineUpstream {
+	for i := range d.Upstreams {
+		if d.Upstreams[i].Project == project {
+			return &d.Upstreams[i]
+		}
+	}
+	return nil
+}
+
 var validTriggerStatuses = []string{"", AllStatuses, evergreen.VersionSucceeded, evergreen.VersionFailed}
 
 func ValidateTriggerDefinition(ctx context.Context, definition patch.PatchTriggerDefinition, parentProject string) (patch.PatchTriggerDefinition, error) {
@@ -3543,6 +3928,15 @@ func (d *PeriodicBuildDefinition) Validate() error {
 		_, err := getCronParserSchedule(d.Cron)
 		catcher.Wrap(err, "parsing cron")
 	}
+	if d.Timezone != "" {
+		catcher.NewWhen(d.Cron == "", "a timezone can only be used with cron")
+		_, err := time.LoadLocation(d.Timezone)
+		catcher.Wrapf(err, "invalid timezone '%s'", d.Timezone)
+	}
+	for i, w := range d.BlackoutWindows {
+		catcher.ErrorfWhen(utility.IsZeroTime(w.Start) || utility.IsZeroTime(w.End), "blackout window %d must have a start and end", i)
+		catcher.ErrorfWhen(!w.End.After(w.Start), "blackout window %d must end after it starts", i)
+	}
 
 	if d.ID == "" {
 		d.ID = utility.RandomString()
@@ -3564,6 +3958,39 @@ func IsWebhookConfigured(ctx context.Context, project string, version string) (e
 	}
 }
 
+// FindPipelineDownstreamProjects finds the enabled projects with a pipeline
+// that waits on the given project, including the projects that inherit such a
+// pipeline from their repo. The projects are merged with their repo settings.
+func FindPipelineDownstreamProjects(ctx context.Context, project string) ([]ProjectRef, error) {
+	projectRefs := []ProjectRef{}
+	if err := db.Aggregate(ctx, ProjectRefCollection, projectRefPipelineForMatchingPipeline(project), &projectRefs); err != nil {
+		return nil, err
+	}
+
+	return addLoggerAndRepoSettingsToProjects(ctx, projectRefs)
+}
+
+// projectRefPipelineForMatchingPipeline is an aggregation pipeline to find
+// projects that are enabled and that have a pipeline waiting on this project,
+// or that default to the repo, which has a pipeline waiting on this project.
+func projectRefPipelineForMatchingPipeline(project string) []bson.M {
+	return []bson.M{
+		lookupRepoStep,
+		{"$match": bson.M{
+			ProjectRefEnabledKey: true,
+			"$or": []bson.M{
+				{
+					bsonutil.GetDottedKeyName(projectRefPipelinesKey, pipelineDefinitionUpstreamsKey, pipelineUpstreamProjectKey): project,
+				},
+				{
+					projectRefPipelinesKey: nil,
+					bsonutil.GetDottedKeyName("repo_ref", RepoRefPipelinesKey, pipelineDefinitionUpstreamsKey, pipelineUpstreamProjectKey): project,
+				},
+			},
+		}},
+	}
+}
+
 // projectRefPipelineForMatchingTrigger is an aggregation pipeline to find projects that are
 // 1) explicitly enabled, or that default to the repo which is enabled, and
 // 2) they have triggers defined for this project, or they default to the repo, which has a trigger for this project defined.
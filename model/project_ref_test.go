@@ -23,6 +23,7 @@ import (
 	"github.com/evergreen-ci/gimlet"
 	"github.com/evergreen-ci/utility"
 	"github.com/google/go-github/v70/github"
+	"github.com/mongodb/grip/level"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -2985,36 +2986,178 @@ func TestCanEnableCommitQueue(t *testing.T) {
 
 func TestValidatePeriodicBuildDefinition(t *testing.T) {
 	assert := assert.New(t)
-	testCases := map[PeriodicBuildDefinition]bool{
+	now := time.Now()
+	testCases := []struct {
+		definition PeriodicBuildDefinition
+		shouldPass bool
+	}{
+		{
+			definition: PeriodicBuildDefinition{
+				IntervalHours: 24,
+				ConfigFile:    "foo.yml",
+				Alias:         "myAlias",
+			},
+			shouldPass: true,
+		},
+		{
+			definition: PeriodicBuildDefinition{
+				IntervalHours: 0,
+				ConfigFile:    "foo.yml",
+				Alias:         "myAlias",
+			},
+			shouldPass: false,
+		},
 		{
-			IntervalHours: 24,
-			ConfigFile:    "foo.yml",
-			Alias:         "myAlias",
-		}: true,
+			definition: PeriodicBuildDefinition{
+				IntervalHours: 24,
+				ConfigFile:    "",
+				Alias:         "myAlias",
+			},
+			shouldPass: false,
+		},
 		{
-			IntervalHours: 0,
-			ConfigFile:    "foo.yml",
-			Alias:         "myAlias",
-		}: false,
+			definition: PeriodicBuildDefinition{
+				IntervalHours: 24,
+				ConfigFile:    "foo.yml",
+				Alias:         "",
+			},
+			shouldPass: true,
+		},
 		{
-			IntervalHours: 24,
-			ConfigFile:    "",
-			Alias:         "myAlias",
-		}: false,
+			definition: PeriodicBuildDefinition{
+				Cron:       "0 2 * * *",
+				Timezone:   "America/New_York",
+				ConfigFile: "foo.yml",
+			},
+			shouldPass: true,
+		},
 		{
-			IntervalHours: 24,
-			ConfigFile:    "foo.yml",
-			Alias:         "",
-		}: true,
+			definition: PeriodicBuildDefinition{
+				Cron:       "0 2 * * *",
+				Timezone:   "Mars/Olympus_Mons",
+				ConfigFile: "foo.yml",
+			},
+			shouldPass: false,
+		},
+		{
+			definition: PeriodicBuildDefinition{
+				IntervalHours: 24,
+				Timezone:      "America/New_York",
+				ConfigFile:    "foo.yml",
+			},
+			shouldPass: false,
+		},
+		{
+			definition: PeriodicBuildDefinition{
+				IntervalHours:   24,
+				ConfigFile:      "foo.yml",
+				BlackoutWindows: []PeriodicBuildBlackoutWindow{{Start: now, End: now.Add(time.Hour), Reason: "release freeze"}},
+			},
+			shouldPass: true,
+		},
+		{
+			definition: PeriodicBuildDefinition{
+				IntervalHours:   24,
+				ConfigFile:      "foo.yml",
+				BlackoutWindows: []PeriodicBuildBlackoutWindow{{Start: now, End: now.Add(-time.Hour)}},
+			},
+			shouldPass: false,
+		},
+		{
+			definition: PeriodicBuildDefinition{
+				IntervalHours:   24,
+				ConfigFile:      "foo.yml",
+				BlackoutWindows: []PeriodicBuildBlackoutWindow{{Start: now}},
+			},
+			shouldPass: false,
+		},
 	}
 
-	for testCase, shouldPass := range testCases {
-		if shouldPass {
-			assert.NoError(testCase.Validate())
+	for _, testCase := range testCases {
+		if testCase.shouldPass {
+			assert.NoError(testCase.definition.Validate())
 		} else {
-			assert.Error(testCase.Validate())
+			assert.Error(testCase.definition.Validate())
 		}
-		assert.NotEmpty(testCase.ID)
+		assert.NotEmpty(testCase.definition.ID)
+	}
+}
+
+func TestGetNextRunTime(t *testing.T) {
+	t.Run("Interval", func(t *testing.T) {
+		base := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+		next, err := getNextRunTime(base, &PeriodicBuildDefinition{IntervalHours: 6})
+		require.NoError(t, err)
+		assert.True(t, next.Equal(base.Add(6*time.Hour)))
+	})
+	t.Run("CronDefaultsToUTC", func(t *testing.T) {
+		base := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+		next, err := getNextRunTime(base, &PeriodicBuildDefinition{Cron: "0 2 * * *"})
+		require.NoError(t, err)
+		assert.True(t, next.Equal(time.Date(2026, 3, 2, 2, 0, 0, 0, time.UTC)))
+	})
+	t.Run("CronInTimezone", func(t *testing.T) {
+		loc, err := time.LoadLocation("America/New_York")
+		require.NoError(t, err)
+		base := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+		next, err := getNextRunTime(base, &PeriodicBuildDefinition{Cron: "0 2 * * *", Timezone: "America/New_York"})
+		require.NoError(t, err)
+		assert.True(t, next.Equal(time.Date(2026, 3, 2, 2, 0, 0, 0, loc)))
+		assert.Equal(t, time.UTC, next.Location())
+	})
+	t.Run("CronInTimezoneAcrossDaylightSavingTime", func(t *testing.T) {
+		loc, err := time.LoadLocation("America/New_York")
+		require.NoError(t, err)
+		// Daylight saving time starts in New York on March 8, 2026.
+		base := time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC)
+		next, err := getNextRunTime(base, &PeriodicBuildDefinition{Cron: "0 2 * * *", Timezone: "America/New_York"})
+		require.NoError(t, err)
+		assert.True(t, next.Equal(time.Date(2026, 3, 9, 2, 0, 0, 0, loc)))
+		assert.Equal(t, 6, next.Hour())
+	})
+	t.Run("InvalidTimezone", func(t *testing.T) {
+		_, err := getNextRunTime(time.Now(), &PeriodicBuildDefinition{Cron: "0 2 * * *", Timezone: "Nowhere/Nothing"})
+		assert.Error(t, err)
+	})
+}
+
+func TestPeriodicBuildInBlackout(t *testing.T) {
+	now := time.Now()
+	d := PeriodicBuildDefinition{
+		BlackoutWindows: []PeriodicBuildBlackoutWindow{
+			{Start: now.Add(-time.Hour), End: now.Add(time.Hour), Reason: "release freeze"},
+			{Start: now.Add(24 * time.Hour), End: now.Add(48 * time.Hour)},
+		},
+	}
+
+	window := d.InBlackout(now)
+	require.NotNil(t, window)
+	assert.Equal(t, "release freeze", window.Reason)
+	assert.NotNil(t, d.InBlackout(now.Add(24*time.Hour)))
+	assert.Nil(t, d.InBlackout(now.Add(2*time.Hour)))
+	assert.Nil(t, d.InBlackout(now.Add(48*time.Hour)), "end of window should be exclusive")
+	assert.Nil(t, (&PeriodicBuildDefinition{}).InBlackout(now))
+}
+
+func TestPreservePeriodicBuildManualRuns(t *testing.T) {
+	runTime := time.Now().Round(time.Second)
+	existing := []PeriodicBuildDefinition{
+		{ID: "d1", LastManualRunBy: "me", LastManualRunTime: runTime},
+		{ID: "d2"},
+	}
+	updated := []PeriodicBuildDefinition{
+		{ID: "d1", Message: "changed"},
+		{ID: "d2", LastManualRunBy: "someone_else", LastManualRunTime: runTime},
+		{ID: "d3", LastManualRunBy: "someone_else", LastManualRunTime: runTime},
+	}
+
+	PreservePeriodicBuildManualRuns(existing, updated)
+	assert.Equal(t, "changed", updated[0].Message)
+	assert.Equal(t, "me", updated[0].LastManualRunBy)
+	assert.True(t, runTime.Equal(updated[0].LastManualRunTime))
+	for _, d := range updated[1:] {
+		assert.Empty(t, d.LastManualRunBy, "manual run should not be set by saving definition '%s'", d.ID)
+		assert.True(t, utility.IsZeroTime(d.LastManualRunTime))
 	}
 }
 
@@ -4303,3 +4446,197 @@ func TestArtifactCredentialsSurviveProjectSettingsWrites(t *testing.T) {
 		})
 	}
 }
+
+func TestLogRetentionSettings(t *testing.T) {
+	t.Run("Validate", func(t *testing.T) {
+		for tName, tCase := range map[string]struct {
+			settings LogRetentionSettings
+			valid    bool
+		}{
+			"Empty":                 {valid: true},
+			"ReduceOnly":            {settings: LogRetentionSettings{ReduceAfterDays: 30}, valid: true},
+			"DeleteOnly":            {settings: LogRetentionSettings{DeleteAfterDays: 90}, valid: true},
+			"ReduceThenDelete":      {settings: LogRetentionSettings{ReduceAfterDays: 30, Reduction: LogReductionErrors, DeleteAfterDays: 90}, valid: true},
+			"DeleteBeforeReduce":    {settings: LogRetentionSettings{ReduceAfterDays: 30, DeleteAfterDays: 30}},
+			"NegativeDays":          {settings: LogRetentionSettings{ReduceAfterDays: -1}},
+			"NegativeTailLines":     {settings: LogRetentionSettings{ReduceAfterDays: 30, TailLines: -1}},
+			"UnrecognizedReduction": {settings: LogRetentionSettings{ReduceAfterDays: 30, Reduction: "head"}},
+		} {
+			t.Run(tName, func(t *testing.T) {
+				err := tCase.settings.Validate()
+				if tCase.valid {
+					assert.NoError(t, err)
+				} else {
+					assert.Error(t, err)
+				}
+			})
+		}
+	})
+	t.Run("ReductionOptions", func(t *testing.T) {
+		assert.Equal(t, task.LogReductionOptions{TailN: DefaultLogRetentionTailLines}, LogRetentionSettings{}.ReductionOptions())
+		assert.Equal(t, task.LogReductionOptions{TailN: 50}, LogRetentionSettings{Reduction: LogReductionTail, TailLines: 50}.ReductionOptions())
+		assert.Equal(t, task.LogReductionOptions{MinPriority: level.Error}, LogRetentionSettings{Reduction: LogReductionErrors, TailLines: 50}.ReductionOptions())
+	})
+}
+
+func TestFlakyTestQuarantineSettings(t *testing.T) {
+	t.Run("Validate", func(t *testing.T) {
+		for tName, tCase := range map[string]struct {
+			settings FlakyTestQuarantineSettings
+			valid    bool
+		}{
+			"Empty":             {valid: true},
+			"Configured":        {settings: FlakyTestQuarantineSettings{Enabled: true, Threshold: 0.5, MinRuns: 5, ReleaseAfterCleanRuns: 20}, valid: true},
+			"NegativeThreshold": {settings: FlakyTestQuarantineSettings{Threshold: -0.1}},
+			"ThresholdAboveOne": {settings: FlakyTestQuarantineSettings{Threshold: 1.5}},
+			"NegativeMinRuns":   {settings: FlakyTestQuarantineSettings{MinRuns: -1}},
+			"NegativeCleanRuns": {settings: FlakyTestQuarantineSettings{ReleaseAfterCleanRuns: -1}},
+		} {
+			t.Run(tName, func(t *testing.T) {
+				err := tCase.settings.Validate()
+				if tCase.valid {
+					assert.NoError(t, err)
+				} else {
+					assert.Error(t, err)
+				}
+			})
+		}
+	})
+	t.Run("RequiresTestSelection", func(t *testing.T) {
+		pRef := ProjectRef{FlakyTestQuarantine: FlakyTestQuarantineSettings{Enabled: true}}
+		assert.Error(t, pRef.ValidateFlakyTestQuarantine())
+
+		pRef.TestSelection.Allowed = utility.TruePtr()
+		assert.NoError(t, pRef.ValidateFlakyTestQuarantine())
+
+		pRef = ProjectRef{FlakyTestQuarantine: FlakyTestQuarantineSettings{Threshold: 0.5}}
+		assert.NoError(t, pRef.ValidateFlakyTestQuarantine(), "disabled quarantine should not require test selection")
+	})
+	t.Run("Defaults", func(t *testing.T) {
+		settings := FlakyTestQuarantineSettings{}
+		assert.Equal(t, DefaultFlakyTestQuarantineThreshold, settings.GetThreshold())
+		assert.Equal(t, DefaultFlakyTestMinRuns, settings.GetMinRuns())
+		assert.Equal(t, DefaultFlakyTestReleaseAfterCleanRuns, settings.GetReleaseAfterCleanRuns())
+
+		settings = FlakyTestQuarantineSettings{Threshold: 0.5, MinRuns: 5, ReleaseAfterCleanRuns: 20}
+		assert.Equal(t, 0.5, settings.GetThreshold())
+		assert.Equal(t, 5, settings.GetMinRuns())
+		assert.Equal(t, 20, settings.GetReleaseAfterCleanRuns())
+	})
+}
+
+func TestValidatePipelineDefinition(t *testing.T) {
+	ctx := t.Context()
+	require.NoError(t, db.ClearCollections(ProjectRefCollection))
+	for _, pRef := range []ProjectRef{
+		{Id: "server_id", Identifier: "server", Owner: "mongodb", Repo: "mongo"},
+		{Id: "tools_id", Identifier: "tools", Owner: "mongodb", Repo: "mongo"},
+		{Id: "driver_id", Identifier: "driver", Owner: "mongodb", Repo: "mongo-go-driver"},
+		{Id: "downstream_id", Identifier: "downstream", Owner: "mongodb", Repo: "mongo"},
+	} {
+		require.NoError(t, pRef.Insert(ctx))
+	}
+
+	for tName, tCase := range map[string]func(t *testing.T){
+		"SetsDefaults": func(t *testing.T) {
+			def := PipelineDefinition{
+				Upstreams:  []PipelineUpstream{{Project: "server"}, {Project: "tools_id", BuildVariantRegex: "^linux"}},
+				ConfigFile: "release.yml",
+			}
+			require.NoError(t, def.Validate(ctx, "downstream_id"))
+			assert.NotEmpty(t, def.ID)
+			assert.Equal(t, PipelineMatchByRevision, def.MatchBy)
+			assert.Equal(t, []string{"server_id", "tools_id"}, def.UpstreamProjects(), "upstreams should be saved by ID")
+		},
+		"AllowsManifest": func(t *testing.T) {
+			def := PipelineDefinition{
+				Upstreams:    []PipelineUpstream{{Project: "server"}},
+				MatchBy:      PipelineMatchByManifest,
+				ManifestRepo: "mongodb/mongo",
+				ConfigFile:   "release.yml",
+			}
+			assert.NoError(t, def.Validate(ctx, "downstream_id"))
+		},
+		"AllowsGitTagAcrossRepositories": func(t *testing.T) {
+			def := PipelineDefinition{
+				Upstreams:  []PipelineUpstream{{Project: "server"}, {Project: "driver"}},
+				MatchBy:    PipelineMatchByGitTag,
+				ConfigFile: "release.yml",
+			}
+			assert.NoError(t, def.Validate(ctx, "downstream_id"))
+		},
+		"RejectsRevisionAcrossRepositories": func(t *testing.T) {
+			def := PipelineDefinition{Upstreams: []PipelineUpstream{{Project: "server"}, {Project: "driver"}}, ConfigFile: "release.yml"}
+			assert.Error(t, def.Validate(ctx, "downstream_id"))
+		},
+		"RequiresUpstreams": func(t *testing.T) {
+			def := PipelineDefinition{ConfigFile: "release.yml"}
+			assert.Error(t, def.Validate(ctx, "downstream_id"))
+		},
+		"RequiresExistingUpstream": func(t *testing.T) {
+			def := PipelineDefinition{Upstreams: []PipelineUpstream{{Project: "nonexistent"}}, ConfigFile: "release.yml"}
+			assert.Error(t, def.Validate(ctx, "downstream_id"))
+		},
+		"RejectsSelfAsUpstream": func(t *testing.T) {
+			def := PipelineDefinition{Upstreams: []PipelineUpstream{{Project: "downstream"}}, ConfigFile: "release.yml"}
+			assert.Error(t, def.Validate(ctx, "downstream_id"))
+		},
+		"RejectsDuplicateUpstreams": func(t *testing.T) {
+			def := PipelineDefinition{Upstreams: []PipelineUpstream{{Project: "server"}, {Project: "server_id"}}, ConfigFile: "release.yml"}
+			assert.Error(t, def.Validate(ctx, "downstream_id"))
+		},
+		"RejectsInvalidRegex": func(t *testing.T) {
+			def := PipelineDefinition{Upstreams: []PipelineUpstream{{Project: "server", BuildVariantRegex: "["}}, ConfigFile: "release.yml"}
+			assert.Error(t, def.Validate(ctx, "downstream_id"))
+		},
+		"RejectsInvalidMatchBy": func(t *testing.T) {
+			def := PipelineDefinition{Upstreams: []PipelineUpstream{{Project: "server"}}, MatchBy: "branch", ConfigFile: "release.yml"}
+			assert.Error(t, def.Validate(ctx, "downstream_id"))
+		},
+		"RequiresManifestRepo": func(t *testing.T) {
+			def := PipelineDefinition{Upstreams: []PipelineUpstream{{Project: "server"}}, MatchBy: PipelineMatchByManifest, ManifestRepo: "mongo", ConfigFile: "release.yml"}
+			assert.Error(t, def.Validate(ctx, "downstream_id"))
+		},
+		"RequiresConfigFile": func(t *testing.T) {
+			def := PipelineDefinition{Upstreams: []PipelineUpstream{{Project: "server"}}}
+			assert.Error(t, def.Validate(ctx, "downstream_id"))
+		},
+	} {
+		t.Run(tName, tCase)
+	}
+}
+
+func TestFindPipelineDownstreamProjects(t *testing.T) {
+	ctx := t.Context()
+	require.NoError(t, db.ClearCollections(ProjectRefCollection, RepoRefCollection))
+	repoRef := RepoRef{ProjectRef{
+		Id:        "repo",
+		Pipelines: []PipelineDefinition{{ID: "repo_release", Upstreams: []PipelineUpstream{{Project: "driver"}}}},
+	}}
+	require.NoError(t, repoRef.Replace(ctx))
+	for _, pRef := range []ProjectRef{
+		{Id: "downstream", Enabled: true, Pipelines: []PipelineDefinition{{ID: "release", Upstreams: []PipelineUpstream{{Project: "server"}, {Project: "tools"}}}}},
+		{Id: "disabled", Enabled: false, Pipelines: []PipelineDefinition{{ID: "release", Upstreams: []PipelineUpstream{{Project: "server"}}}}},
+		{Id: "unrelated", Enabled: true, Pipelines: []PipelineDefinition{{ID: "release", Upstreams: []PipelineUpstream{{Project: "tools"}}}}},
+		{Id: "inherits_repo", Enabled: true, RepoRefId: "repo"},
+		{Id: "overrides_repo", Enabled: true, RepoRefId: "repo", Pipelines: []PipelineDefinition{{ID: "release", Upstreams: []PipelineUpstream{{Project: "tools"}}}}},
+	} {
+		require.NoError(t, pRef.Insert(ctx))
+	}
+
+	projects, err := FindPipelineDownstreamProjects(ctx, "server")
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+	assert.Equal(t, "downstream", projects[0].Id)
+
+	projects, err = FindPipelineDownstreamProjects(ctx, "tools")
+	require.NoError(t, err)
+	assert.Len(t, projects, 3)
+
+	projects, err = FindPipelineDownstreamProjects(ctx, "driver")
+	require.NoError(t, err)
+	require.Len(t, projects, 1, "only projects that don't define their own pipelines should inherit the repo's")
+	assert.Equal(t, "inherits_repo", projects[0].Id)
+	require.Len(t, projects[0].Pipelines, 1, "pipelines should be merged from the repo")
+	assert.Equal(t, "repo_release", projects[0].Pipelines[0].ID)
+}
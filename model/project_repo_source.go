@@ -0,0 +1,154 @@
+package model
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+)
+
+// RepoProvider is the kind of host that a project's repository lives on.
+type RepoProvider string
+
+const (
+	// RepoProviderGitHub is a repository on GitHub. This is the default.
+	RepoProviderGitHub RepoProvider = "github"
+	// RepoProviderGitLab is a repository on GitLab, which is read through the
+	// GitLab REST API.
+	RepoProviderGitLab RepoProvider = "gitlab"
+	// RepoProviderGit is a repository on any git server, which is read
+	// through git itself.
+	RepoProviderGit RepoProvider = "git"
+)
+
+// DefaultGitLabURL is the GitLab instance used for GitLab repositories if no
+// URL is configured.
+const DefaultGitLabURL = "https://gitlab.com"
+
+// RepoSourceSettings configures where the repotracker reads the project's
+// mainline commits and configuration from. For GitHub and GitLab, the
+// project's owner and repo identify the repository; on GitLab, the owner is
+// the repository's (possibly nested) group.
+type RepoSourceSettings struct {
+	// Provider is the kind of host the repository lives on. Defaults to
+	// RepoProviderGitHub.
+	Provider RepoProvider `bson:"provider,omitempty" json:"provider,omitempty" yaml:"provider,omitempty"`
+	// URL is the base URL of the GitLab instance for GitLab repositories, or
+	// the remote URL of the repository for git repositories.
+	URL string `bson:"url,omitempty" json:"url,omitempty" yaml:"url,omitempty"`
+	// TokenVar is the name of the project variable holding the access token
+	// used to read the repository. For git repositories, the token is sent as
+	// the password for HTTP(S) remotes.
+	TokenVar string `bson:"token_var,omitempty" json:"token_var,omitempty" yaml:"token_var,omitempty"`
+}
+
+// IsZero returns whether the project uses the default repository source.
+func (s RepoSourceSettings) IsZero() bool {
+	return s == RepoSourceSettings{}
+}
+
+// GetProvider returns the kind of host the repository lives on.
+func (s RepoSourceSettings) GetProvider() RepoProvider {
+	if s.Provider == "" {
+		return RepoProviderGitHub
+	}
+	return s.Provider
+}
+
+// GetGitLabURL returns the base URL of the GitLab instance.
+func (s RepoSourceSettings) GetGitLabURL() string {
+	if s.URL == "" {
+		return DefaultGitLabURL
+	}
+	return s.URL
+}
+
+// Validate checks that the repository source settings are valid.
+func (s RepoSourceSettings) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	switch s.GetProvider() {
+	case RepoProviderGitHub:
+		catcher.NewWhen(s.URL != "", "cannot set a URL for a GitHub repository")
+		catcher.NewWhen(s.TokenVar != "", "cannot set a token variable for a GitHub repository")
+	case RepoProviderGitLab:
+		if s.URL != "" {
+			u, err := url.Parse(s.URL)
+			if err != nil {
+				catcher.Wrapf(err, "parsing GitLab URL '%s'", s.URL)
+			} else if (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+				catcher.Errorf("GitLab URL '%s' must be an absolute HTTP(S) URL", s.URL)
+			}
+		}
+	case RepoProviderGit:
+		if s.URL == "" {
+			catcher.New("must specify the remote URL of a git repository")
+		} else {
+			catcher.Add(validateGitRemoteURL(s.URL))
+		}
+	default:
+		catcher.Errorf("unrecognized repository provider '%s'", s.Provider)
+	}
+	return catcher.Resolve()
+}
+
+// scpLikeGitURLRegex matches SCP-like git remotes of the form
+// user@host:path.
+var scpLikeGitURLRegex = regexp.MustCompile(`^[A-Za-z0-9._-]+@[A-Za-z0-9.-]+:[^:]`)
+
+// validateGitRemoteURL checks that the git remote URL refers to a remote
+// server. The repotracker clones the remote on the app server, so local
+// remotes (e.g. file://), remote helpers (e.g. ext::) and URLs that git could
+// interpret as options are rejected.
+func validateGitRemoteURL(remoteURL string) error {
+	if strings.HasPrefix(remoteURL, "-") {
+		return errors.Errorf("git remote URL '%s' cannot begin with '-'", remoteURL)
+	}
+	if scpLikeGitURLRegex.MatchString(remoteURL) {
+		return nil
+	}
+	u, err := url.Parse(remoteURL)
+	if err != nil {
+		return errors.Wrapf(err, "parsing git remote URL '%s'", remoteURL)
+	}
+	if (u.Scheme != "https" && u.Scheme != "ssh") || u.Host == "" {
+		return errors.Errorf("git remote URL '%s' must be an HTTPS, SSH or SCP-like (user@host:path) URL", remoteURL)
+	}
+	return nil
+}
+
+// GetRepoSourceToken returns the access token used to read the project's
+// repository from its non-GitHub repository source. It returns an empty
+// token if none is configured.
+func (p *ProjectRef) GetRepoSourceToken(ctx context.Context) (string, error) {
+	if p.RepoSource.TokenVar == "" {
+		return "", nil
+	}
+
+	vars, err := FindMergedProjectVars(ctx, p.Id)
+	if err != nil {
+		return "", errors.Wrapf(err, "getting project vars for project '%s'", p.Id)
+	}
+	if vars == nil || vars.Vars[p.RepoSource.TokenVar] == "" {
+		return "", errors.Errorf("project variable '%s' holding the repository access token is not set", p.RepoSource.TokenVar)
+	}
+	return vars.Vars[p.RepoSource.TokenVar], nil
+}
+
+// getFileFromGitLab retrieves a file of the project's GitLab repository.
+func getFileFromGitLab(ctx context.Context, opts GetProjectOpts) ([]byte, error) {
+	if opts.Ref == nil {
+		return nil, errors.New("project ref is required to read files from GitLab")
+	}
+
+	client, err := thirdparty.NewGitLabClient(opts.Ref.RepoSource.GetGitLabURL(), opts.RepoSourceToken)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating GitLab client")
+	}
+	defer client.Close()
+
+	return client.GetFileContent(ctx, opts.Ref.Owner+"/"+opts.Ref.Repo, opts.Revision, opts.RemotePath)
+}
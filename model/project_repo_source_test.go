@@ -0,0 +1,90 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepoSourceSettingsValidate(t *testing.T) {
+	for name, testCase := range map[string]struct {
+		settings RepoSourceSettings
+		isValid  bool
+	}{
+		"DefaultIsValid": {
+			settings: RepoSourceSettings{},
+			isValid:  true,
+		},
+		"GitHubWithURLIsInvalid": {
+			settings: RepoSourceSettings{Provider: RepoProviderGitHub, URL: "https://github.com"},
+		},
+		"GitHubWithTokenVarIsInvalid": {
+			settings: RepoSourceSettings{TokenVar: "token"},
+		},
+		"GitLabWithoutURLIsValid": {
+			settings: RepoSourceSettings{Provider: RepoProviderGitLab},
+			isValid:  true,
+		},
+		"GitLabWithURLAndTokenVarIsValid": {
+			settings: RepoSourceSettings{Provider: RepoProviderGitLab, URL: "https://gitlab.example.com", TokenVar: "token"},
+			isValid:  true,
+		},
+		"GitLabWithRelativeURLIsInvalid": {
+			settings: RepoSourceSettings{Provider: RepoProviderGitLab, URL: "gitlab.example.com"},
+		},
+		"GitWithURLIsValid": {
+			settings: RepoSourceSettings{Provider: RepoProviderGit, URL: "git@git.example.com:team/repo.git"},
+			isValid:  true,
+		},
+		"GitWithHTTPSURLIsValid": {
+			settings: RepoSourceSettings{Provider: RepoProviderGit, URL: "https://git.example.com/team/repo.git"},
+			isValid:  true,
+		},
+		"GitWithSSHURLIsValid": {
+			settings: RepoSourceSettings{Provider: RepoProviderGit, URL: "ssh://git@git.example.com/team/repo.git"},
+			isValid:  true,
+		},
+		"GitWithFileURLIsInvalid": {
+			settings: RepoSourceSettings{Provider: RepoProviderGit, URL: "file:///etc"},
+		},
+		"GitWithLocalPathIsInvalid": {
+			settings: RepoSourceSettings{Provider: RepoProviderGit, URL: "/srv/repo.git"},
+		},
+		"GitWithRemoteHelperIsInvalid": {
+			settings: RepoSourceSettings{Provider: RepoProviderGit, URL: "ext::sh -c touch% /tmp/pwned"},
+		},
+		"GitWithOptionURLIsInvalid": {
+			settings: RepoSourceSettings{Provider: RepoProviderGit, URL: "--upload-pack=touch /tmp/pwned"},
+		},
+		"GitWithHTTPURLIsInvalid": {
+			settings: RepoSourceSettings{Provider: RepoProviderGit, URL: "http://git.example.com/team/repo.git"},
+		},
+		"GitWithoutURLIsInvalid": {
+			settings: RepoSourceSettings{Provider: RepoProviderGit},
+		},
+		"UnrecognizedProviderIsInvalid": {
+			settings: RepoSourceSettings{Provider: "svn", URL: "svn://example.com"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := testCase.settings.Validate()
+			if testCase.isValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestRepoSourceSettingsDefaults(t *testing.T) {
+	settings := RepoSourceSettings{}
+	assert.True(t, settings.IsZero())
+	assert.Equal(t, RepoProviderGitHub, settings.GetProvider())
+	assert.Equal(t, DefaultGitLabURL, settings.GetGitLabURL())
+
+	settings = RepoSourceSettings{Provider: RepoProviderGitLab, URL: "https://gitlab.example.com"}
+	assert.False(t, settings.IsZero())
+	assert.Equal(t, RepoProviderGitLab, settings.GetProvider())
+	assert.Equal(t, "https://gitlab.example.com", settings.GetGitLabURL())
+}
@@ -32,6 +32,7 @@ var (
 	RepoRefAdminsKey         = bsonutil.MustHaveTag(RepoRef{}, "Admins")
 	RepoRefPeriodicBuildsKey = bsonutil.MustHaveTag(RepoRef{}, "PeriodicBuilds")
 	RepoRefTriggersKey       = bsonutil.MustHaveTag(RepoRef{}, "Triggers")
+	RepoRefPipelinesKey      = bsonutil.MustHaveTag(RepoRef{}, "Pipelines")
 )
 
 func (r *RepoRef) Add(ctx context.Context, creator *user.DBUser) error {
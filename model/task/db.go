@@ -95,6 +95,7 @@ var (
 	TaskCostKey                   = bsonutil.MustHaveTag(Task{}, "TaskCost")
 	PredictedTaskCostKey          = bsonutil.MustHaveTag(Task{}, "PredictedTaskCost")
 	S3UsageKey                    = bsonutil.MustHaveTag(Task{}, "S3Usage")
+	LogRetentionStageKey          = bsonutil.MustHaveTag(Task{}, "LogRetentionStage")
 	ExpectedDurationKey           = bsonutil.MustHaveTag(Task{}, "ExpectedDuration")
 	ExpectedDurationStddevKey     = bsonutil.MustHaveTag(Task{}, "ExpectedDurationStdDev")
 	DurationPredictionKey         = bsonutil.MustHaveTag(Task{}, "DurationPrediction")
@@ -2965,6 +2966,20 @@ func abortAndMarkResetTasks(ctx context.Context, filter bson.M, taskIDs []string
 	return err
 }
 
+// FindCompletedByDistroActivatedBetween returns up to limit completed tasks
+// that ran on the given distro and were activated in the given time range,
+// ordered by activation time.
+func FindCompletedByDistroActivatedBetween(ctx context.Context, distroID string, start, end time.Time, limit int) ([]Task, error) {
+	filter := bson.M{
+		DistroIdKey:      distroID,
+		StatusKey:        bson.M{"$in": evergreen.TaskCompletedStatuses},
+		ActivatedTimeKey: bson.M{"$gte": start, "$lt": end},
+		DisplayOnlyKey:   bson.M{"$ne": true},
+	}
+
+	return FindAll(ctx, db.Query(filter).Sort([]string{ActivatedTimeKey}).Limit(limit))
+}
+
 // FindCompletedTasksByBuild returns all completed tasks belonging to the
 // given build ID. Excludes execution tasks. If no taskIDs are specified, all
 // completed tasks belonging to the build are returned.
@@ -105,3 +105,27 @@ func getExpectedDurationsForWindow(ctx context.Context, name, project, buildVari
 
 	return results, nil
 }
+
+// GetExpectedDuration returns the average duration of the task with the given
+// display name in the build variant over the past week, or a default if there
+// is no history. Unlike FetchExpectedDuration, it does not need an existing
+// task and does not persist the estimate, so it can be used to estimate tasks
+// that have not been created yet.
+func GetExpectedDuration(ctx context.Context, project, buildVariant, displayName string) (time.Duration, error) {
+	cacheKey := estimateCacheKey{project: project, buildVariant: buildVariant, taskDisplayName: displayName}
+	if stats, ok := expectedDurationCache.Get(cacheKey); ok {
+		return stats.Average, nil
+	}
+
+	vals, err := getExpectedDurationsForWindow(ctx, displayName, project, buildVariant, time.Now().Add(-taskCompletionEstimateWindow), time.Now())
+	if err != nil {
+		return 0, errors.Wrapf(err, "getting expected duration for task '%s' in build variant '%s'", displayName, buildVariant)
+	}
+	if len(vals) != 1 || vals[0].ExpectedDuration == 0 {
+		return defaultTaskDuration, nil
+	}
+
+	stats := util.DurationStats{Average: time.Duration(vals[0].ExpectedDuration), StdDev: time.Duration(vals[0].StdDev)}
+	expectedDurationCache.Add(cacheKey, stats)
+	return stats.Average, nil
+}
@@ -0,0 +1,261 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/log"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/level"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// LogRetentionStage is the retention tier reached by the logs of a task.
+type LogRetentionStage string
+
+const (
+	// LogRetentionStageFull indicates that the logs are stored as they were
+	// written by the agent.
+	LogRetentionStageFull LogRetentionStage = ""
+	// LogRetentionStageCompacted indicates that the logs were merged into
+	// a few large chunks without dropping any lines.
+	LogRetentionStageCompacted LogRetentionStage = "compacted"
+	// LogRetentionStageReduced indicates that only the tail or the error
+	// lines of the logs were kept.
+	LogRetentionStageReduced LogRetentionStage = "reduced"
+	// LogRetentionStageDeleted indicates that the logs were deleted.
+	LogRetentionStageDeleted LogRetentionStage = "deleted"
+)
+
+// logRetentionStages are the log retention stages in the order they are
+// reached.
+var logRetentionStages = []LogRetentionStage{
+	LogRetentionStageFull,
+	LogRetentionStageCompacted,
+	LogRetentionStageReduced,
+	LogRetentionStageDeleted,
+}
+
+// Each retention tier that rewrites the logs does so with its own compaction
+// generation so that an interrupted rewrite can be safely redone.
+const (
+	logCompactionGeneration = 1
+	logReductionGeneration  = 2
+)
+
+// LogReductionOptions represents the arguments for reducing the logs of a
+// task.
+type LogReductionOptions struct {
+	// TailN, if greater than 0, only keeps the last N lines of each log.
+	TailN int
+	// MinPriority only keeps the lines at or above this priority.
+	MinPriority level.Priority
+}
+
+type logRetentionService interface {
+	Compact(context.Context, log.CompactOptions) (log.CompactResult, error)
+	RemoveOlderGenerations(context.Context, []string, int) (int, error)
+	Delete(context.Context, []string) (int, error)
+}
+
+// CompactLogs merges the chunks of each of the task's logs into a few large
+// chunks. The task logs and test logs of every execution of the task are
+// compacted, as long as they are stored in the current log buckets of the
+// task. It is a no-op if the logs already reached a later retention tier.
+func (t *Task) CompactLogs(ctx context.Context) (log.CompactResult, error) {
+	switch t.LogRetentionStage {
+	case LogRetentionStageFull:
+	case LogRetentionStageCompacted:
+		// The chunks may not have been removed if a previous attempt
+		// was interrupted.
+		return log.CompactResult{}, t.removeOlderLogGenerations(ctx, logCompactionGeneration)
+	default:
+		return log.CompactResult{}, nil
+	}
+
+	res, err := t.rewriteLogs(ctx, log.CompactOptions{Generation: logCompactionGeneration})
+	if err != nil {
+		return res, errors.Wrap(err, "compacting logs")
+	}
+	// The stage must be persisted before the compacted chunks are removed,
+	// otherwise a retry would rewrite the logs from only the chunks that
+	// were not removed yet.
+	if err = t.SetLogRetentionStage(ctx, LogRetentionStageCompacted); err != nil {
+		return res, err
+	}
+
+	return res, t.removeOlderLogGenerations(ctx, logCompactionGeneration)
+}
+
+// ReduceLogs rewrites each of the task's logs to only keep the lines matching
+// the reduction options, compacting them first if necessary. It is a no-op if
+// the logs were already deleted.
+func (t *Task) ReduceLogs(ctx context.Context, opts LogReductionOptions) (log.CompactResult, error) {
+	switch t.LogRetentionStage {
+	case LogRetentionStageFull:
+		if _, err := t.CompactLogs(ctx); err != nil {
+			return log.CompactResult{}, err
+		}
+	case LogRetentionStageCompacted:
+		if err := t.removeOlderLogGenerations(ctx, logCompactionGeneration); err != nil {
+			return log.CompactResult{}, err
+		}
+	case LogRetentionStageReduced:
+		return log.CompactResult{}, t.removeOlderLogGenerations(ctx, logReductionGeneration)
+	default:
+		return log.CompactResult{}, nil
+	}
+
+	res, err := t.rewriteLogs(ctx, log.CompactOptions{
+		Generation:  logReductionGeneration,
+		TailN:       opts.TailN,
+		MinPriority: opts.MinPriority,
+	})
+	if err != nil {
+		return res, errors.Wrap(err, "reducing logs")
+	}
+	if err = t.SetLogRetentionStage(ctx, LogRetentionStageReduced); err != nil {
+		return res, err
+	}
+
+	return res, t.removeOlderLogGenerations(ctx, logReductionGeneration)
+}
+
+// DeleteLogs removes the task logs and test logs of every execution of the
+// task from its current log buckets. Returns the number of chunks removed.
+func (t *Task) DeleteLogs(ctx context.Context) (int, error) {
+	var removed int
+	err := t.forEachLogService(ctx, func(svc logRetentionService, logNames []string) error {
+		n, err := svc.Delete(ctx, logNames)
+		removed += n
+		return err
+	})
+	if err != nil {
+		return removed, errors.Wrap(err, "deleting logs")
+	}
+
+	return removed, t.SetLogRetentionStage(ctx, LogRetentionStageDeleted)
+}
+
+// SetLogRetentionStage sets the retention tier reached by the task's logs.
+func (t *Task) SetLogRetentionStage(ctx context.Context, stage LogRetentionStage) error {
+	if err := UpdateOne(
+		ctx,
+		bson.M{IdKey: t.Id},
+		bson.M{"$set": bson.M{LogRetentionStageKey: stage}},
+	); err != nil {
+		return errors.Wrapf(err, "setting log retention stage to '%s'", stage)
+	}
+	t.LogRetentionStage = stage
+
+	return nil
+}
+
+// FindLogRetentionCandidates finds up to limit finished tasks that finished in
+// the given time range and whose logs have not reached the given retention
+// stage yet, oldest first. The project filter and the start of the range are
+// ignored if they are empty.
+func FindLogRetentionCandidates(ctx context.Context, project string, finishedAfter, finishedBefore time.Time, stage LogRetentionStage, limit int) ([]Task, error) {
+	filter, err := logRetentionCandidatesFilter(project, finishedAfter, finishedBefore, stage)
+	if err != nil {
+		return nil, err
+	}
+
+	return FindAll(ctx, db.Query(filter).
+		WithFields(IdKey, ProjectKey, StatusKey, FinishTimeKey, TaskOutputInfoKey).
+		Sort([]string{FinishTimeKey}).
+		Limit(limit))
+}
+
+// CountLogRetentionCandidates counts the tasks that FindLogRetentionCandidates
+// would find without a limit.
+func CountLogRetentionCandidates(ctx context.Context, project string, finishedAfter, finishedBefore time.Time, stage LogRetentionStage) (int, error) {
+	filter, err := logRetentionCandidatesFilter(project, finishedAfter, finishedBefore, stage)
+	if err != nil {
+		return 0, err
+	}
+
+	return Count(ctx, db.Query(filter))
+}
+
+func logRetentionCandidatesFilter(project string, finishedAfter, finishedBefore time.Time, stage LogRetentionStage) (bson.M, error) {
+	idx := slices.Index(logRetentionStages, stage)
+	if idx < 0 {
+		return nil, errors.Errorf("unrecognized log retention stage '%s'", stage)
+	}
+
+	finishTime := bson.M{"$lte": finishedBefore}
+	if !finishedAfter.IsZero() {
+		finishTime["$gt"] = finishedAfter
+	}
+	filter := bson.M{
+		StatusKey:            bson.M{"$in": evergreen.TaskCompletedStatuses},
+		FinishTimeKey:        finishTime,
+		DisplayOnlyKey:       bson.M{"$ne": true},
+		LogRetentionStageKey: bson.M{"$nin": logRetentionStages[idx:]},
+	}
+	if project != "" {
+		filter[ProjectKey] = project
+	}
+
+	return filter, nil
+}
+
+func (t *Task) rewriteLogs(ctx context.Context, opts log.CompactOptions) (log.CompactResult, error) {
+	var total log.CompactResult
+	err := t.forEachLogService(ctx, func(svc logRetentionService, logNames []string) error {
+		opts.LogNames = logNames
+		res, err := svc.Compact(ctx, opts)
+		total.LogsCompacted += res.LogsCompacted
+		total.ChunksRead += res.ChunksRead
+		total.ChunksWritten += res.ChunksWritten
+		total.LinesWritten += res.LinesWritten
+		total.BytesUploaded += res.BytesUploaded
+		total.PutRequests += res.PutRequests
+		return err
+	})
+
+	return total, err
+}
+
+func (t *Task) removeOlderLogGenerations(ctx context.Context, generation int) error {
+	return errors.Wrap(t.forEachLogService(ctx, func(svc logRetentionService, logNames []string) error {
+		_, err := svc.RemoveOlderGenerations(ctx, logNames, generation)
+		return err
+	}), "removing older log generations")
+}
+
+// forEachLogService calls fn once per log bucket of the task with the log
+// name prefix covering every execution of the task.
+func (t *Task) forEachLogService(ctx context.Context, fn func(logRetentionService, []string) error) error {
+	output, ok := t.GetTaskOutputSafe()
+	if !ok {
+		return nil
+	}
+
+	// Log keys of every execution start with the ID of the latest one,
+	// including those of archived executions.
+	logNames := []string{fmt.Sprintf("%s/%s/", t.Project, t.Id)}
+	taskLogsCfg := getBucketConfigForProject(t.Project, output.TaskLogs.BucketConfig)
+	bucketCfgs := []evergreen.BucketConfig{taskLogsCfg}
+	if testLogsCfg := getBucketConfigForProject(t.Project, output.TestLogs.BucketConfig); testLogsCfg.Name != "" && testLogsCfg.Name != taskLogsCfg.Name {
+		bucketCfgs = append(bucketCfgs, testLogsCfg)
+	}
+
+	catcher := grip.NewBasicCatcher()
+	for _, cfg := range bucketCfgs {
+		b, err := newBucket(ctx, cfg, output.TaskLogs.AWSCredentials)
+		if err != nil {
+			catcher.Wrapf(err, "getting log bucket '%s'", cfg.Name)
+			continue
+		}
+		catcher.Wrapf(fn(log.NewLogServiceV0(b), logNames), "bucket '%s'", cfg.Name)
+	}
+
+	return catcher.Resolve()
+}
@@ -0,0 +1,168 @@
+package task
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/log"
+	"github.com/mongodb/grip/level"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogRetention(t *testing.T) {
+	const numChunks = 5
+
+	setup := func(t *testing.T) (*Task, []log.LogLine) {
+		require.NoError(t, db.ClearCollections(Collection))
+		tsk := &Task{
+			Id:        "task",
+			Project:   "project",
+			Execution: 1,
+			Status:    evergreen.TaskSucceeded,
+			TaskOutputInfo: &TaskOutput{
+				TaskLogs: TaskLogOutput{
+					Version: 1,
+					BucketConfig: evergreen.BucketConfig{
+						Type: evergreen.BucketTypeLocal,
+						Name: t.TempDir(),
+					},
+				},
+			},
+		}
+		require.NoError(t, tsk.Insert(t.Context()))
+
+		output, ok := tsk.GetTaskOutputSafe()
+		require.True(t, ok)
+		bucket, err := newBucket(t.Context(), output.TaskLogs.BucketConfig, nil)
+		require.NoError(t, err)
+		svc := log.NewLogServiceV0(bucket)
+
+		var lines []log.LogLine
+		ts := time.Now().UnixNano()
+		for execution := 0; execution <= tsk.Execution; execution++ {
+			logTask := *tsk
+			logTask.Execution = execution
+			logName := getLogName(logTask, TaskLogTypeTask, output.TaskLogs.ID())
+			for i := range numChunks {
+				chunk := []log.LogLine{
+					{LogName: logName, Priority: level.Info, Timestamp: ts, Data: fmt.Sprintf("info %d", i)},
+					{LogName: logName, Priority: level.Error, Timestamp: ts + 1, Data: fmt.Sprintf("error %d", i)},
+				}
+				_, _, err = svc.Append(t.Context(), logName, i, chunk)
+				require.NoError(t, err)
+				if execution == tsk.Execution {
+					lines = append(lines, chunk...)
+				}
+				ts += 2
+			}
+		}
+
+		return tsk, lines
+	}
+	readLines := func(t *testing.T, tsk *Task) []log.LogLine {
+		it, err := tsk.GetTaskLogs(t.Context(), TaskLogGetOptions{LogType: TaskLogTypeTask})
+		require.NoError(t, err)
+		var lines []log.LogLine
+		for it.Next() {
+			lines = append(lines, it.Item())
+		}
+		require.NoError(t, it.Err())
+		require.NoError(t, it.Close())
+		return lines
+	}
+	checkStage := func(t *testing.T, tsk *Task, expected LogRetentionStage) {
+		assert.Equal(t, expected, tsk.LogRetentionStage)
+		dbTask, err := FindOneId(t.Context(), tsk.Id)
+		require.NoError(t, err)
+		require.NotNil(t, dbTask)
+		assert.Equal(t, expected, dbTask.LogRetentionStage)
+	}
+
+	t.Run("CompactLogsKeepsEveryLine", func(t *testing.T) {
+		tsk, lines := setup(t)
+
+		res, err := tsk.CompactLogs(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, 2, res.LogsCompacted, "every execution should be compacted")
+		assert.Equal(t, 2*numChunks, res.ChunksRead)
+		assert.Equal(t, 2, res.ChunksWritten)
+		checkStage(t, tsk, LogRetentionStageCompacted)
+		assert.Equal(t, lines, readLines(t, tsk))
+
+		res, err = tsk.CompactLogs(t.Context())
+		require.NoError(t, err)
+		assert.Zero(t, res.ChunksWritten, "compacting again should be a no-op")
+	})
+	t.Run("ReduceLogsKeepsTail", func(t *testing.T) {
+		tsk, lines := setup(t)
+
+		_, err := tsk.ReduceLogs(t.Context(), LogReductionOptions{TailN: 3})
+		require.NoError(t, err)
+		checkStage(t, tsk, LogRetentionStageReduced)
+		assert.Equal(t, lines[len(lines)-3:], readLines(t, tsk))
+	})
+	t.Run("ReduceLogsKeepsErrors", func(t *testing.T) {
+		tsk, lines := setup(t)
+
+		_, err := tsk.CompactLogs(t.Context())
+		require.NoError(t, err)
+		_, err = tsk.ReduceLogs(t.Context(), LogReductionOptions{MinPriority: level.Error})
+		require.NoError(t, err)
+		checkStage(t, tsk, LogRetentionStageReduced)
+
+		var errorLines []log.LogLine
+		for _, line := range lines {
+			if line.Priority >= level.Error {
+				errorLines = append(errorLines, line)
+			}
+		}
+		assert.Equal(t, errorLines, readLines(t, tsk))
+	})
+	t.Run("DeleteLogsRemovesEveryExecution", func(t *testing.T) {
+		tsk, _ := setup(t)
+
+		removed, err := tsk.DeleteLogs(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, 2*numChunks, removed)
+		checkStage(t, tsk, LogRetentionStageDeleted)
+		assert.Empty(t, readLines(t, tsk))
+
+		res, err := tsk.ReduceLogs(t.Context(), LogReductionOptions{TailN: 1})
+		require.NoError(t, err)
+		assert.Zero(t, res.ChunksWritten, "deleted logs should not be reduced")
+	})
+}
+
+func TestFindLogRetentionCandidates(t *testing.T) {
+	require.NoError(t, db.ClearCollections(Collection))
+	now := time.Now().Round(time.Millisecond)
+	for i, id := range []string{"newest", "oldest", "middle", "reduced"} {
+		tsk := &Task{
+			Id:         id,
+			Project:    "project",
+			Status:     evergreen.TaskSucceeded,
+			FinishTime: now.Add(-time.Duration(i) * time.Hour),
+		}
+		switch id {
+		case "oldest":
+			tsk.FinishTime = now.Add(-10 * time.Hour)
+		case "reduced":
+			tsk.LogRetentionStage = LogRetentionStageReduced
+		}
+		require.NoError(t, tsk.Insert(t.Context()))
+	}
+
+	tasks, err := FindLogRetentionCandidates(t.Context(), "project", time.Time{}, now, LogRetentionStageCompacted, 2)
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	assert.Equal(t, "oldest", tasks[0].Id, "oldest tasks should be found first")
+	assert.Equal(t, "middle", tasks[1].Id)
+
+	count, err := CountLogRetentionCandidates(t.Context(), "project", time.Time{}, now, LogRetentionStageCompacted)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count, "tasks past the stage should not be counted")
+}
@@ -263,6 +263,8 @@ type Task struct {
 	TaskCost cost.Cost `bson:"cost,omitempty" json:"cost,omitempty"`
 	// S3Usage tracks S3 API usage for cost calculation
 	S3Usage s3usage.S3Usage `bson:"s3_usage,omitempty" json:"s3_usage,omitempty"`
+	// LogRetentionStage is the retention tier reached by the task's logs.
+	LogRetentionStage LogRetentionStage `bson:"log_retention_stage,omitempty" json:"log_retention_stage,omitempty"`
 	// WaitSinceDependenciesMet is populated in GetDistroQueueInfo, used for host allocation
 	WaitSinceDependenciesMet time.Duration `bson:"wait_since_dependencies_met,omitempty" json:"wait_since_dependencies_met,omitempty"`
 
@@ -2385,6 +2387,7 @@ func resetTaskUpdate(t *Task, caller string, prediction *CostPredictionResult) [
 		t.HasAnnotations = false
 		t.TaskCost = cost.Cost{}
 		t.S3Usage = s3usage.S3Usage{}
+		t.LogRetentionStage = LogRetentionStageFull
 		if prediction != nil {
 			t.SetPredictedCost(prediction.PredictedCost)
 		}
@@ -2431,6 +2434,7 @@ func resetTaskUpdate(t *Task, caller string, prediction *CostPredictionResult) [
 				HasAnnotationsKey,
 				TaskCostKey,
 				S3UsageKey,
+				LogRetentionStageKey,
 			},
 		},
 		addDisplayStatusCache,
@@ -4322,6 +4326,22 @@ func CalculateEBSStorageAdjustedCost(runtimeSeconds float64, mountPoints []ec2mo
 	return onDemandCost * (1 - ebsConfig.EBSDiscount)
 }
 
+// EstimateTaskCost estimates the EC2 and EBS cost of running a task for the
+// given runtime on the distro, using the given distro cost data rather than
+// the distro's own so that callers can supply fallback pricing.
+func EstimateTaskCost(runtimeSeconds float64, d *distro.Distro, costData distro.CostData, financeConfig evergreen.CostConfig) cost.Cost {
+	c := CalculateTaskCost(runtimeSeconds, costData, financeConfig)
+	mountPoints, err := ec2settings.MountPointsForDistro(d, "")
+	if err != nil || len(mountPoints) == 0 {
+		return c
+	}
+	c.OnDemandEBSThroughputCost = CalculateEBSThroughputOnDemandCost(runtimeSeconds, mountPoints)
+	c.AdjustedEBSThroughputCost = CalculateEBSThroughputAdjustedCost(runtimeSeconds, mountPoints, financeConfig.EBSCost)
+	c.OnDemandEBSStorageCost = CalculateEBSStorageOnDemandCost(runtimeSeconds, mountPoints)
+	c.AdjustedEBSStorageCost = CalculateEBSStorageAdjustedCost(runtimeSeconds, mountPoints, financeConfig.EBSCost)
+	return c
+}
+
 func (t *Task) getFinanceConfigAndDistro(ctx context.Context) (evergreen.CostConfig, distro.CostData, *distro.Distro, error) {
 	financeConfig := evergreen.CostConfig{}
 	if err := financeConfig.Get(ctx); err != nil {
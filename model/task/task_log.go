@@ -71,6 +71,9 @@ type TaskLogGetOptions struct {
 	// TailN is the number of lines to read from the tail of the log.
 	// Ignored if less than or equal to 0.
 	TailN int
+	// Search, if set, only returns the log lines matching the search and
+	// their context lines.
+	Search *log.SearchOptions
 }
 
 // NewTaskLogSender returns a new task log sender for the given task run.
@@ -172,6 +175,7 @@ func getTaskLogs(ctx context.Context, task Task, getOpts TaskLogGetOptions) (log
 		End:       getOpts.End,
 		LineLimit: getOpts.LineLimit,
 		TailN:     getOpts.TailN,
+		Search:    getOpts.Search,
 	})
 }
 
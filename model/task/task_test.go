@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/evergreen-ci/birch"
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/apimodels"
 	"github.com/evergreen-ci/evergreen/db"
@@ -5085,6 +5086,45 @@ func TestCalculateTaskCost(t *testing.T) {
 	assert.False(t, taskCost.IsZero())
 }
 
+func TestEstimateTaskCost(t *testing.T) {
+	runtimeSeconds := 3600.0
+	costData := distro.CostData{OnDemandRate: 0.20, SavingsPlanRate: 0.10}
+	financeConfig := evergreen.CostConfig{
+		FinanceFormula:      0.6,
+		SavingsPlanDiscount: 0.5,
+		OnDemandDiscount:    0.04,
+		EBSCost:             evergreen.EBSCostConfig{EBSDiscount: 0.2},
+	}
+
+	t.Run("WithoutMountPoints", func(t *testing.T) {
+		d := &distro.Distro{Id: "d", Provider: evergreen.ProviderNameEc2Fleet}
+		estimate := EstimateTaskCost(runtimeSeconds, d, costData, financeConfig)
+		assert.Equal(t, CalculateTaskCost(runtimeSeconds, costData, financeConfig), estimate)
+	})
+	t.Run("IncludesEBSCosts", func(t *testing.T) {
+		d := &distro.Distro{
+			Id:       "d",
+			Provider: evergreen.ProviderNameEc2Fleet,
+			ProviderSettingsList: []*birch.Document{birch.NewDocument(
+				birch.EC.String("region", evergreen.DefaultEC2Region),
+				birch.EC.Array("mount_points", birch.NewArray(
+					birch.VC.Document(birch.NewDocument(
+						birch.EC.String("volume_type", evergreen.VolumeTypeGp3),
+						birch.EC.Int32("throughput", 225),
+						birch.EC.Int32("size", 100),
+					)),
+				)),
+			)},
+		}
+		estimate := EstimateTaskCost(runtimeSeconds, d, costData, financeConfig)
+		assert.Equal(t, CalculateOnDemandCost(runtimeSeconds, costData, financeConfig), estimate.OnDemandEC2Cost)
+		assert.Positive(t, estimate.OnDemandEBSThroughputCost)
+		assert.InDelta(t, estimate.OnDemandEBSThroughputCost*0.8, estimate.AdjustedEBSThroughputCost, 1e-9)
+		assert.Positive(t, estimate.OnDemandEBSStorageCost)
+		assert.InDelta(t, estimate.OnDemandEBSStorageCost*0.8, estimate.AdjustedEBSStorageCost, 1e-9)
+	})
+}
+
 func TestTaskCostIsZero(t *testing.T) {
 	zeroTaskCost := cost.Cost{}
 	assert.True(t, zeroTaskCost.IsZero())
@@ -44,6 +44,9 @@ type TestLogGetOptions struct {
 	// TailN is the number of lines to read from the tail of the log.
 	// Ignored if less than or equal to 0.
 	TailN int
+	// Search, if set, only returns the log lines matching the search and
+	// their context lines.
+	Search *log.SearchOptions
 }
 
 // NewTestLogSender returns a new test log sender for the given task run.
@@ -100,6 +103,7 @@ func getTestLogs(ctx context.Context, task Task, getOpts TestLogGetOptions) (log
 		DefaultTimeRangeOfFirstLog: true,
 		LineLimit:                  getOpts.LineLimit,
 		TailN:                      getOpts.TailN,
+		Search:                     getOpts.Search,
 	})
 }
 
@@ -38,6 +38,12 @@ type StatusChanges struct {
 }
 
 func SetActiveState(ctx context.Context, caller string, active bool, tasks ...task.Task) error {
+	if active {
+		if err := checkCostBudgetHardStops(ctx, tasks); err != nil {
+			return err
+		}
+	}
+
 	tasksToActivate := []task.Task{}
 	versionIdsSet := map[string]bool{}
 	buildToTaskMap := map[string]task.Task{}
@@ -945,7 +951,7 @@ func attemptStepbackAndDeactivatePrevious(ctx context.Context, t *task.Task, sta
 	}
 
 	// Deactivate previous occurrences of the same task only if this one passed on mainline commits.
-	if t.Status == evergreen.TaskSucceeded && t.Requester == evergreen.RepotrackerVersionRequester && t.ActivatedBy != evergreen.StepbackTaskActivator {
+	if t.Status == evergreen.TaskSucceeded && t.Requester == evergreen.RepotrackerVersionRequester && t.ActivatedBy != evergreen.StepbackTaskActivator && t.ActivatedBy != evergreen.CulpritFinderTaskActivator {
 		shouldDeactivatePrevious := getDeactivatePrevious(t, pRef, project)
 		if shouldDeactivatePrevious {
 			grip.Error(ctx, message.WrapError(DeactivatePreviousTasks(ctx, t, caller), message.Fields{
@@ -1218,6 +1224,10 @@ func UpdateUnblockedDependencies(ctx context.Context, dependencies []task.Task)
 // has failed but not on system failure.
 func evalStepback(ctx context.Context, t *task.Task, status string,
 	pRef *ProjectRef, project *Project) error {
+	// Culprit-finding bisections choose which tasks to run on their own.
+	if t.ActivatedBy == evergreen.CulpritFinderTaskActivator {
+		return nil
+	}
 	s, err := getStepback(ctx, t.Id, pRef, project)
 	if err != nil {
 		return errors.WithStack(err)
@@ -38,6 +38,7 @@ func Admin() cli.Command {
 			updateServiceUser(),
 			getServiceUsers(),
 			deleteServiceUser(),
+			adminSimulateScheduler(),
 		},
 	}
 }
@@ -150,6 +150,9 @@ func ftDetectors() []ftDetector {
 		ftCommandDetector("attach_results", "attach.results", "attach.results"),
 		ftCommandDetector("attach_xunit_results", "attach.xunit_results", "attach.xunit_results"),
 		ftCommandDetector("gotest_parse_files", "gotest.parse_files", "gotest.parse_files"),
+		ftCommandDetector("tap_parse_files", "tap.parse_files", "tap.parse_files"),
+		ftCommandDetector("ctrf_parse_files", "ctrf.parse_files", "ctrf.parse_files"),
+		ftCommandDetector("pytest_parse_files", "pytest.parse_files", "pytest.parse_files"),
 		ftCommandDetector("perf_send", "perf.send", "perf.send"),
 		ftCommandDetector("ec2_assume_role", "ec2.assume_role", "ec2.assume_role"),
 	}
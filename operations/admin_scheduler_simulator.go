@@ -0,0 +1,208 @@
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	restmodel "github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/scheduler"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/level"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+	"gopkg.in/yaml.v2"
+)
+
+// simulatorSettings are alternative distro settings to compare against the
+// workload's own settings, in the same format as the distro REST routes.
+type simulatorSettings struct {
+	PlannerSettings       *restmodel.APIPlannerSettings       `json:"planner_settings"`
+	HostAllocatorSettings *restmodel.APIHostAllocatorSettings `json:"host_allocator_settings"`
+}
+
+type simulatorResult struct {
+	Settings string                     `yaml:"settings"`
+	Report   *scheduler.SimulatorReport `yaml:"report"`
+}
+
+// adminSimulateScheduler returns the `evergreen admin simulate-scheduler`
+// subcommand, which replays a recorded workload through the scheduler's
+// planner and host allocator to compare distro settings offline. With
+// --record, it instead records the recent workload of a distro into the
+// workload file.
+func adminSimulateScheduler() cli.Command {
+	const (
+		workloadFlagName        = "workload"
+		recordFlagName          = "record"
+		distroFlagName          = "distro"
+		sinceFlagName           = "since"
+		settingsFlagName        = "settings"
+		intervalFlagName        = "interval"
+		hostStartupTimeFlagName = "host-startup-time"
+		hostIdleTimeFlagName    = "host-idle-time"
+		costPerHourFlagName     = "cost-per-hour"
+	)
+
+	return cli.Command{
+		Name:   "simulate-scheduler",
+		Usage:  "replay a recorded task workload through the scheduler to compare distro settings",
+		Before: setPlainLogger,
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  joinFlagNames(workloadFlagName, "w"),
+				Usage: "JSON file containing the distro and tasks to replay, or to write to with --record",
+			},
+			cli.BoolFlag{
+				Name:  recordFlagName,
+				Usage: "record the tasks that were activated on --distro in the last --since into the workload file instead of simulating",
+			},
+			cli.StringFlag{
+				Name:  joinFlagNames(distroFlagName, "d"),
+				Usage: "the distro whose workload to record",
+			},
+			cli.DurationFlag{
+				Name:  sinceFlagName,
+				Usage: "how far back to record the distro's workload",
+				Value: 24 * time.Hour,
+			},
+			cli.StringSliceFlag{
+				Name:  joinFlagNames(settingsFlagName, "s"),
+				Usage: "JSON file(s) containing planner_settings and/or host_allocator_settings to compare against the workload distro's settings",
+			},
+			cli.DurationFlag{
+				Name:  intervalFlagName,
+				Usage: "how often the scheduler runs",
+				Value: time.Minute,
+			},
+			cli.DurationFlag{
+				Name:  hostStartupTimeFlagName,
+				Usage: "how long new hosts take to start running tasks",
+				Value: 8 * time.Minute,
+			},
+			cli.DurationFlag{
+				Name:  hostIdleTimeFlagName,
+				Usage: "how long hosts can be idle before they're terminated, if the distro does not set it",
+				Value: 4 * time.Minute,
+			},
+			cli.Float64Flag{
+				Name:  costPerHourFlagName,
+				Usage: "the cost of running a host for an hour",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			workloadPath := c.String(workloadFlagName)
+			if workloadPath == "" {
+				return errors.New("must specify a workload file")
+			}
+			if c.Bool(recordFlagName) {
+				confPath := c.Parent().Parent().String(ConfFlagName)
+				return recordSimulatorWorkload(ctx, confPath, c.String(distroFlagName), c.Duration(sinceFlagName), workloadPath)
+			}
+			workload := scheduler.SimulatorWorkload{}
+			if err := readJSONFile(workloadPath, &workload); err != nil {
+				return errors.Wrap(err, "reading workload")
+			}
+
+			opts := scheduler.SimulatorOptions{
+				Interval:        c.Duration(intervalFlagName),
+				HostStartupTime: c.Duration(hostStartupTimeFlagName),
+				HostIdleTime:    c.Duration(hostIdleTimeFlagName),
+				HostCostPerHour: c.Float64(costPerHourFlagName),
+			}
+
+			// The scheduler logs as it would in production, which isn't
+			// useful here.
+			l := grip.GetSender().Level()
+			l.Threshold = level.Error
+			grip.Error(ctx, errors.Wrap(grip.SetLevel(l), "increasing log level to suppress scheduler logs"))
+
+			results := []simulatorResult{}
+			report, err := scheduler.Simulate(ctx, workload, opts)
+			if err != nil {
+				return errors.Wrap(err, "simulating workload with the distro's settings")
+			}
+			results = append(results, simulatorResult{Settings: "distro", Report: report})
+
+			for _, path := range c.StringSlice(settingsFlagName) {
+				settings := simulatorSettings{}
+				if err := readJSONFile(path, &settings); err != nil {
+					return errors.Wrap(err, "reading settings")
+				}
+				settingsOpts := opts
+				if settings.PlannerSettings != nil {
+					plannerSettings := settings.PlannerSettings.ToService()
+					settingsOpts.PlannerSettings = &plannerSettings
+				}
+				if settings.HostAllocatorSettings != nil {
+					hostAllocatorSettings := settings.HostAllocatorSettings.ToService()
+					settingsOpts.HostAllocatorSettings = &hostAllocatorSettings
+				}
+				report, err := scheduler.Simulate(ctx, workload, settingsOpts)
+				if err != nil {
+					return errors.Wrapf(err, "simulating workload with settings '%s'", path)
+				}
+				results = append(results, simulatorResult{Settings: filepath.Base(path), Report: report})
+			}
+
+			out, err := yaml.Marshal(results)
+			if err != nil {
+				return errors.Wrap(err, "marshalling simulation results into YAML")
+			}
+			fmt.Print(string(out))
+
+			return nil
+		},
+	}
+}
+
+// recordSimulatorWorkload writes the workload of the distro over the given
+// period to the workload file.
+func recordSimulatorWorkload(ctx context.Context, confPath, distroID string, since time.Duration, workloadPath string) error {
+	if distroID == "" {
+		return errors.New("must specify a distro to record")
+	}
+	if since <= 0 {
+		return errors.New("the period to record must be positive")
+	}
+
+	conf, err := NewClientSettings(confPath)
+	if err != nil {
+		return errors.Wrap(err, "loading configuration")
+	}
+	client, err := conf.setupRestCommunicator(ctx, false)
+	if err != nil {
+		return errors.Wrap(err, "setting up REST communicator")
+	}
+	defer client.Close()
+
+	end := time.Now()
+	workload, err := client.GetSchedulerWorkload(ctx, distroID, end.Add(-since), end)
+	if err != nil {
+		return errors.Wrapf(err, "recording workload for distro '%s'", distroID)
+	}
+	out, err := json.MarshalIndent(workload, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "marshalling workload into JSON")
+	}
+	if err := os.WriteFile(workloadPath, out, 0644); err != nil {
+		return errors.Wrapf(err, "writing workload file '%s'", workloadPath)
+	}
+	grip.Infof(ctx, "Recorded %d tasks for distro '%s' into '%s'.", len(workload.Tasks), distroID, workloadPath)
+
+	return nil
+}
+
+func readJSONFile(path string, out any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return errors.Wrapf(err, "reading file '%s'", path)
+	}
+	return errors.Wrapf(json.Unmarshal(data, out), "unmarshalling JSON from file '%s'", path)
+}
@@ -17,6 +17,7 @@ func Evaluate() cli.Command {
 		taskFlagName        = "tasks"
 		variantsFlagName    = "variants"
 		diffableFlagName    = "diffable"
+		matrixFlagName      = "matrix"
 		yamlAnchorsFlagName = "yaml-anchors"
 	)
 
@@ -32,6 +33,10 @@ func Evaluate() cli.Command {
 				Name:  variantsFlagName,
 				Usage: "only show variant definitions",
 			},
+			cli.BoolFlag{
+				Name:  matrixFlagName,
+				Usage: "only show the cells and variants generated by each matrix, including the cells each matrix excludes",
+			},
 			cli.BoolFlag{
 				Name:  diffableFlagName,
 				Usage: "show the project configuration in an ordered, diff-friendly format",
@@ -75,10 +80,13 @@ func Evaluate() cli.Command {
 				LocalIncludeDir:   cwd,
 				EnableYAMLAnchors: c.Bool(yamlAnchorsFlagName),
 			}
-			_, err = model.LoadProjectInto(ctx, configBytes, opts, "", p)
+			pp, err := model.LoadProjectInto(ctx, configBytes, opts, "", p)
 			if err != nil {
 				return errors.Wrap(err, "loading project")
 			}
+			if c.Bool(matrixFlagName) {
+				return printEvaluatedMatrices(pp, p)
+			}
 			if diffable {
 				sortTasksByName := model.ProjectTasksByName(p.Tasks)
 				sort.Sort(sortTasksByName)
@@ -118,3 +126,48 @@ func Evaluate() cli.Command {
 		},
 	}
 }
+
+// evaluatedMatrixVariant is a variant generated from a matrix cell along with
+// its fully evaluated tasks and dependencies.
+type evaluatedMatrixVariant struct {
+	model.MatrixVariant `yaml:",inline"`
+	Tasks               []model.BuildVariantTaskUnit `yaml:"tasks,omitempty"`
+}
+
+type evaluatedMatrix struct {
+	Name     string                   `yaml:"matrix_name"`
+	Variants []evaluatedMatrixVariant `yaml:"variants"`
+	Excluded []map[string]string      `yaml:"excluded,omitempty"`
+}
+
+// printEvaluatedMatrices prints the cells and variants generated by each of
+// the project's matrices.
+func printEvaluatedMatrices(pp *model.ParserProject, p *model.Project) error {
+	expansions, err := pp.ExpandMatrices()
+	if err != nil {
+		return errors.Wrap(err, "expanding matrices")
+	}
+
+	out := struct {
+		Matrices []evaluatedMatrix `yaml:"matrices"`
+	}{Matrices: []evaluatedMatrix{}}
+	for _, expansion := range expansions {
+		m := evaluatedMatrix{Name: expansion.Name, Excluded: expansion.Excluded}
+		for _, v := range expansion.Variants {
+			evaluated := evaluatedMatrixVariant{MatrixVariant: v}
+			if bv := p.FindBuildVariant(v.Name); bv != nil {
+				evaluated.Tasks = bv.Tasks
+			}
+			m.Variants = append(m.Variants, evaluated)
+		}
+		out.Matrices = append(out.Matrices, m)
+	}
+
+	outYAML, err := yaml.Marshal(out)
+	if err != nil {
+		return errors.Wrap(err, "marshalling evaluated matrices YAML")
+	}
+
+	fmt.Println(string(outYAML))
+	return nil
+}
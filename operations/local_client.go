@@ -6,15 +6,21 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
+	neturl "net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"syscall"
 	"time"
 
+	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/agent/taskexec"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/testresult"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/utility"
 	"github.com/mongodb/grip"
@@ -34,6 +40,14 @@ const (
 	setupFlagName       = "setup"
 	tailFlagName        = "tail"
 	debugTaskIDFlagName = "task-id"
+	failedOnlyFlagName  = "failed-only"
+	artifactsFlagName   = "artifacts"
+
+	breakpointNameFlagName      = "name"
+	breakpointStepFlagName      = "step"
+	breakpointCommandFlagName   = "command"
+	breakpointFunctionFlagName  = "function"
+	breakpointOnFailureFlagName = "on-failure"
 )
 
 // getRootContext walks up the cli.Context chain to find the root context,
@@ -163,6 +177,71 @@ func Debug() cli.Command {
 				ArgsUsage: "<step_number>",
 				Action:    runUntilCmd,
 			},
+			{
+				Name:   "continue",
+				Usage:  "Run steps until the next breakpoint",
+				Action: continueCmd,
+			},
+			{
+				Name:  "breakpoint",
+				Usage: "Manage breakpoints",
+				Subcommands: []cli.Command{
+					{
+						Name:  "add",
+						Usage: "Add a breakpoint that stops 'continue'",
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:  breakpointNameFlagName,
+								Usage: "Name of the breakpoint (generated if not provided)",
+							},
+							cli.StringFlag{
+								Name:  breakpointStepFlagName,
+								Usage: "Stop before a step number (e.g. '3', '2.1', 'pre:1')",
+							},
+							cli.StringFlag{
+								Name:  breakpointCommandFlagName,
+								Usage: "Stop before every step running a command (e.g. 's3.put')",
+							},
+							cli.StringFlag{
+								Name:  breakpointFunctionFlagName,
+								Usage: "Stop before every call to a function",
+							},
+							cli.BoolFlag{
+								Name:  breakpointOnFailureFlagName,
+								Usage: "Stop after the first step that fails",
+							},
+						},
+						Action: addBreakpointCmd,
+					},
+					{
+						Name:      "remove",
+						Usage:     "Remove a breakpoint",
+						ArgsUsage: "<name>",
+						Action:    removeBreakpointCmd,
+					},
+					{
+						Name:   "list",
+						Usage:  "List all breakpoints",
+						Action: listBreakpointsCmd,
+					},
+					{
+						Name:   "clear",
+						Usage:  "Remove all breakpoints",
+						Action: clearBreakpointsCmd,
+					},
+				},
+			},
+			{
+				Name:   "snapshots",
+				Usage:  "List the expansion snapshots taken each time the session stopped",
+				Action: listSnapshotsCmd,
+			},
+			{
+				Name:      "diff",
+				Usage:     "Diff the expansions snapshotted at two steps",
+				ArgsUsage: "<from_step> <to_step>",
+				Action:    diffSnapshotsCmd,
+			},
 			{
 				Name:   "list-steps",
 				Usage:  "List all steps in the current task",
@@ -199,6 +278,21 @@ func Debug() cli.Command {
 				},
 				Action: viewLogsCmd,
 			},
+			{
+				Name:  "results",
+				Usage: "View test results and artifacts attached during the debug session",
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  failedOnlyFlagName,
+						Usage: "Show only failed tests",
+					},
+					cli.BoolFlag{
+						Name:  artifactsFlagName,
+						Usage: "Show attached artifacts instead of test results",
+					},
+				},
+				Action: viewResultsCmd,
+			},
 		},
 	}
 }
@@ -551,10 +645,13 @@ func selectTaskCmd(c *cli.Context) error {
 	taskName := c.Args().Get(0)
 	variantName := c.String("variant")
 
-	// Clear previous session logs when selecting a new task.
+	// Clear previous session logs and storage when selecting a new task.
 	if err := taskexec.ClearSessionLogs(); err != nil {
 		grip.Warning(context.Background(), errors.Wrap(err, "clearing previous session logs"))
 	}
+	if err := taskexec.ClearSessionStorage(); err != nil {
+		grip.Warning(context.Background(), errors.Wrap(err, "clearing previous session storage"))
+	}
 
 	url, err := getDaemonURL()
 	if err != nil {
@@ -633,6 +730,196 @@ func runUntilCmd(c *cli.Context) error {
 	return postAndStreamResponse(fmt.Sprintf("%s/step/run-until/%s", url, stepNum), nil)
 }
 
+// continueCmd runs until the next breakpoint with streaming output.
+func continueCmd(c *cli.Context) error {
+	url, err := getDaemonURL()
+	if err != nil {
+		return err
+	}
+
+	return postAndStreamStepResponse(url + "/step/continue")
+}
+
+// breakpointFromFlags builds a breakpoint from the flags of the add command.
+// Exactly one condition must be given.
+func breakpointFromFlags(c *cli.Context) (taskexec.Breakpoint, error) {
+	bp := taskexec.Breakpoint{Name: c.String(breakpointNameFlagName)}
+	numConditions := 0
+	for flagName, bpType := range map[string]taskexec.BreakpointType{
+		breakpointStepFlagName:     taskexec.BreakpointStep,
+		breakpointCommandFlagName:  taskexec.BreakpointCommand,
+		breakpointFunctionFlagName: taskexec.BreakpointFunction,
+	} {
+		if value := c.String(flagName); value != "" {
+			bp.Type = bpType
+			bp.Value = value
+			numConditions++
+		}
+	}
+	if c.Bool(breakpointOnFailureFlagName) {
+		bp.Type = taskexec.BreakpointFailure
+		numConditions++
+	}
+	if numConditions != 1 {
+		return bp, errors.Errorf("must specify exactly one of --%s, --%s, --%s or --%s",
+			breakpointStepFlagName, breakpointCommandFlagName, breakpointFunctionFlagName, breakpointOnFailureFlagName)
+	}
+
+	return bp, nil
+}
+
+// addBreakpointCmd adds a breakpoint.
+func addBreakpointCmd(c *cli.Context) error {
+	bp, err := breakpointFromFlags(c)
+	if err != nil {
+		return err
+	}
+
+	url, err := getDaemonURL()
+	if err != nil {
+		return err
+	}
+
+	resp, err := postJSON(url+"/breakpoint/add", bp)
+	if err != nil {
+		return err
+	}
+
+	added, _ := resp["breakpoint"].(map[string]any)
+	fmt.Printf("Added breakpoint %v\n", added["name"])
+	return nil
+}
+
+// removeBreakpointCmd removes a breakpoint.
+func removeBreakpointCmd(c *cli.Context) error {
+	if c.NArg() < 1 {
+		return errors.New("breakpoint name required")
+	}
+	name := c.Args().Get(0)
+
+	url, err := getDaemonURL()
+	if err != nil {
+		return err
+	}
+
+	if _, err := postJSON(fmt.Sprintf("%s/breakpoint/remove/%s", url, name), nil); err != nil {
+		return err
+	}
+
+	fmt.Printf("Removed breakpoint %s\n", name)
+	return nil
+}
+
+// clearBreakpointsCmd removes all breakpoints.
+func clearBreakpointsCmd(c *cli.Context) error {
+	url, err := getDaemonURL()
+	if err != nil {
+		return err
+	}
+
+	if _, err := postJSON(url+"/breakpoint/clear", nil); err != nil {
+		return err
+	}
+
+	fmt.Println("Removed all breakpoints")
+	return nil
+}
+
+// listBreakpointsCmd lists all breakpoints.
+func listBreakpointsCmd(c *cli.Context) error {
+	url, err := getDaemonURL()
+	if err != nil {
+		return err
+	}
+
+	var result struct {
+		Breakpoints []taskexec.Breakpoint `json:"breakpoints"`
+	}
+	if err := getJSON(url+"/breakpoint/list", &result); err != nil {
+		return err
+	}
+
+	if len(result.Breakpoints) == 0 {
+		fmt.Println("No breakpoints set.")
+		return nil
+	}
+	fmt.Println("Breakpoints:")
+	for _, bp := range result.Breakpoints {
+		fmt.Printf("  %s\n", bp.String())
+	}
+
+	return nil
+}
+
+// listSnapshotsCmd lists the expansion snapshots.
+func listSnapshotsCmd(c *cli.Context) error {
+	url, err := getDaemonURL()
+	if err != nil {
+		return err
+	}
+
+	var result struct {
+		Snapshots []taskexec.ExpansionSnapshot `json:"snapshots"`
+	}
+	if err := getJSON(url+"/snapshot/list", &result); err != nil {
+		return err
+	}
+
+	if len(result.Snapshots) == 0 {
+		fmt.Println("No snapshots taken.")
+		return nil
+	}
+	fmt.Println("Snapshots:")
+	for _, snapshot := range result.Snapshots {
+		fmt.Printf("  step %s after %s (%d expansions, %s)\n", snapshot.StepNumber, snapshot.Reason, len(snapshot.Expansions), snapshot.CreatedAt.Format(time.Kitchen))
+	}
+
+	return nil
+}
+
+// diffSnapshotsCmd diffs the expansion snapshots taken at two steps.
+func diffSnapshotsCmd(c *cli.Context) error {
+	if c.NArg() < 2 {
+		return errors.New("two step numbers required")
+	}
+
+	url, err := getDaemonURL()
+	if err != nil {
+		return err
+	}
+
+	query := neturl.Values{}
+	query.Set("from", c.Args().Get(0))
+	query.Set("to", c.Args().Get(1))
+	var diff taskexec.ExpansionDiff
+	if err := getJSON(fmt.Sprintf("%s/snapshot/diff?%s", url, query.Encode()), &diff); err != nil {
+		return err
+	}
+
+	printExpansionDiff(os.Stdout, diff)
+	return nil
+}
+
+// printExpansionDiff writes one line per added, removed or changed expansion,
+// sorted by key.
+func printExpansionDiff(w io.Writer, diff taskexec.ExpansionDiff) {
+	if diff.IsEmpty() {
+		fmt.Fprintf(w, "No expansion changes between steps %s and %s\n", diff.From, diff.To)
+		return
+	}
+
+	fmt.Fprintf(w, "Expansion changes from step %s to step %s:\n", diff.From, diff.To)
+	for _, key := range slices.Sorted(maps.Keys(diff.Added)) {
+		fmt.Fprintf(w, "+ %s=%s\n", key, diff.Added[key])
+	}
+	for _, key := range slices.Sorted(maps.Keys(diff.Removed)) {
+		fmt.Fprintf(w, "- %s=%s\n", key, diff.Removed[key])
+	}
+	for _, change := range diff.Changed {
+		fmt.Fprintf(w, "~ %s: %s -> %s\n", change.Key, change.Old, change.New)
+	}
+}
+
 // jumpToCmd jumps to a specific step
 func jumpToCmd(c *cli.Context) error {
 	if c.NArg() < 1 {
@@ -809,6 +1096,85 @@ func viewLogsCmd(c *cli.Context) error {
 	return nil
 }
 
+// viewResultsCmd displays the test results or artifacts attached during the
+// debug session.
+func viewResultsCmd(c *cli.Context) error {
+	if c.Bool(artifactsFlagName) {
+		files, err := taskexec.ReadArtifacts()
+		if err != nil {
+			return errors.Wrap(err, "reading artifacts")
+		}
+		if len(files) == 0 {
+			grip.Info(context.Background(), "No artifacts found.")
+			return nil
+		}
+		printArtifacts(os.Stdout, files)
+		return nil
+	}
+
+	results, err := taskexec.ReadTestResults()
+	if err != nil {
+		return errors.Wrap(err, "reading test results")
+	}
+	if len(results) == 0 {
+		grip.Info(context.Background(), "No test results found.")
+		return nil
+	}
+	printTestResults(os.Stdout, results, c.Bool(failedOnlyFlagName))
+	return nil
+}
+
+// printTestResults writes one line per test result followed by a summary of
+// the pass and fail counts.
+func printTestResults(w io.Writer, results []testresult.TestResult, failedOnly bool) {
+	var numFailed int
+	for _, r := range results {
+		failed := r.Status == evergreen.TestFailedStatus
+		if failed {
+			numFailed++
+		}
+		if failedOnly && !failed {
+			continue
+		}
+
+		status := "✓"
+		switch r.Status {
+		case evergreen.TestFailedStatus:
+			status = "✗"
+		case evergreen.TestSkippedStatus, evergreen.TestSilentlyFailedStatus:
+			status = "-"
+		}
+		line := fmt.Sprintf("%s %s (%s)", status, r.GetDisplayTestName(), r.Status)
+		if duration := r.Duration(); duration > 0 {
+			line = fmt.Sprintf("%s %s", line, duration)
+		}
+		fmt.Fprintln(w, line)
+	}
+	fmt.Fprintf(w, "\n%d tests, %d failed\n", len(results), numFailed)
+}
+
+// printArtifacts writes one line per artifact with its local location.
+func printArtifacts(w io.Writer, files []artifact.File) {
+	for _, f := range files {
+		fmt.Fprintf(w, "%s: %s/%s\n", f.Name, f.Bucket, f.FileKey)
+	}
+}
+
+func getJSON(url string, out any) error {
+	resp, err := http.Get(url)
+	if err != nil {
+		return errors.Wrap(err, "sending GET request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyData, _ := io.ReadAll(resp.Body)
+		return errors.Errorf("request failed with status %d: %s", resp.StatusCode, string(bodyData))
+	}
+
+	return errors.Wrap(json.NewDecoder(resp.Body).Decode(out), "decoding response")
+}
+
 func postJSON(url string, body any) (map[string]any, error) {
 	var reqBody io.Reader
 	if body != nil {
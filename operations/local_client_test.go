@@ -11,7 +11,9 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/agent/taskexec"
+	"github.com/evergreen-ci/evergreen/model/testresult"
 	"github.com/evergreen-ci/evergreen/rest/client"
 	restmodel "github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/gorilla/mux"
@@ -498,3 +500,105 @@ func TestValidateDebugLocal(t *testing.T) {
 		assert.Contains(t, err.Error(), "debug spawn hosts currently disabled")
 	})
 }
+
+func TestPrintTestResults(t *testing.T) {
+	results := []testresult.TestResult{
+		{TestName: "test_pass", Status: evergreen.TestSucceededStatus},
+		{TestName: "test_fail", Status: evergreen.TestFailedStatus},
+		{TestName: "test_skip", Status: evergreen.TestSkippedStatus},
+	}
+
+	t.Run("AllResults", func(t *testing.T) {
+		var buf bytes.Buffer
+		printTestResults(&buf, results, false)
+		out := buf.String()
+		assert.Contains(t, out, "✓ test_pass (pass)")
+		assert.Contains(t, out, "✗ test_fail (fail)")
+		assert.Contains(t, out, "- test_skip (skip)")
+		assert.Contains(t, out, "3 tests, 1 failed")
+	})
+	t.Run("FailedOnly", func(t *testing.T) {
+		var buf bytes.Buffer
+		printTestResults(&buf, results, true)
+		out := buf.String()
+		assert.NotContains(t, out, "test_pass")
+		assert.NotContains(t, out, "test_skip")
+		assert.Contains(t, out, "✗ test_fail (fail)")
+		assert.Contains(t, out, "3 tests, 1 failed")
+	})
+}
+
+func TestBreakpointFromFlags(t *testing.T) {
+	makeContext := func(t *testing.T, args ...string) *cli.Context {
+		set := flag.NewFlagSet("test", 0)
+		set.String(breakpointNameFlagName, "", "")
+		set.String(breakpointStepFlagName, "", "")
+		set.String(breakpointCommandFlagName, "", "")
+		set.String(breakpointFunctionFlagName, "", "")
+		set.Bool(breakpointOnFailureFlagName, false, "")
+		require.NoError(t, set.Parse(args))
+		return cli.NewContext(cli.NewApp(), set, nil)
+	}
+
+	t.Run("Step", func(t *testing.T) {
+		bp, err := breakpointFromFlags(makeContext(t, "--step", "pre:1.2", "--name", "setup"))
+		require.NoError(t, err)
+		assert.Equal(t, taskexec.Breakpoint{Name: "setup", Type: taskexec.BreakpointStep, Value: "pre:1.2"}, bp)
+	})
+	t.Run("OnFailure", func(t *testing.T) {
+		bp, err := breakpointFromFlags(makeContext(t, "--on-failure"))
+		require.NoError(t, err)
+		assert.Equal(t, taskexec.BreakpointFailure, bp.Type)
+		assert.Empty(t, bp.Value)
+	})
+	t.Run("NoCondition", func(t *testing.T) {
+		_, err := breakpointFromFlags(makeContext(t, "--name", "setup"))
+		assert.Error(t, err)
+	})
+	t.Run("MultipleConditions", func(t *testing.T) {
+		_, err := breakpointFromFlags(makeContext(t, "--command", "s3.put", "--on-failure"))
+		assert.Error(t, err)
+	})
+}
+
+func TestPrintExpansionDiff(t *testing.T) {
+	t.Run("Changes", func(t *testing.T) {
+		var buf bytes.Buffer
+		printExpansionDiff(&buf, taskexec.ExpansionDiff{
+			From:    "1",
+			To:      "pre:2",
+			Added:   map[string]string{"b": "2", "a": "1"},
+			Removed: map[string]string{"c": "3"},
+			Changed: []taskexec.ExpansionChange{{Key: "d", Old: "old", New: "new"}},
+		})
+		assert.Equal(t, "Expansion changes from step 1 to step pre:2:\n+ a=1\n+ b=2\n- c=3\n~ d: old -> new\n", buf.String())
+	})
+	t.Run("NoChanges", func(t *testing.T) {
+		var buf bytes.Buffer
+		printExpansionDiff(&buf, taskexec.ExpansionDiff{From: "1", To: "2"})
+		assert.Contains(t, buf.String(), "No expansion changes")
+	})
+}
+
+func TestReadTestResultsFromSession(t *testing.T) {
+	tempDir := t.TempDir()
+	setHomeDir(t, tempDir)
+
+	results, err := taskexec.ReadTestResults()
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	sessionDir := filepath.Join(tempDir, ".evergreen-local", "storage", "session")
+	require.NoError(t, os.MkdirAll(sessionDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(sessionDir, "test_results.json"), []byte(`[{"test_name": "test1", "status": "fail"}]`), 0644))
+
+	results, err = taskexec.ReadTestResults()
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "test1", results[0].TestName)
+
+	require.NoError(t, taskexec.ClearSessionStorage())
+	results, err = taskexec.ReadTestResults()
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
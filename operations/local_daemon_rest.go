@@ -46,6 +46,13 @@ func (d *localDaemonREST) Start() error {
 	router.HandleFunc("/step/run-all", d.handleRunAll).Methods("POST")
 	router.HandleFunc("/step/run-until/{step}", d.handleRunUntil).Methods("POST")
 	router.HandleFunc("/step/jump/{step}", d.handleJumpTo).Methods("POST")
+	router.HandleFunc("/step/continue", d.handleContinue).Methods("POST")
+	router.HandleFunc("/breakpoint/list", d.handleListBreakpoints).Methods("GET")
+	router.HandleFunc("/breakpoint/add", d.handleAddBreakpoint).Methods("POST")
+	router.HandleFunc("/breakpoint/remove/{name}", d.handleRemoveBreakpoint).Methods("POST")
+	router.HandleFunc("/breakpoint/clear", d.handleClearBreakpoints).Methods("POST")
+	router.HandleFunc("/snapshot/list", d.handleListSnapshots).Methods("GET")
+	router.HandleFunc("/snapshot/diff", d.handleDiffSnapshots).Methods("GET")
 	router.HandleFunc("/variable/set", d.handleSetVariable).Methods("POST")
 	router.HandleFunc("/status", d.handleStatus).Methods("GET")
 
@@ -465,3 +472,141 @@ func (d *localDaemonREST) handleStatus(w http.ResponseWriter, r *http.Request) {
 
 	grip.Error(r.Context(), json.NewEncoder(w).Encode(response))
 }
+
+// handleContinue runs steps until the next breakpoint with streaming output.
+func (d *localDaemonREST) handleContinue(w http.ResponseWriter, r *http.Request) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.executor == nil {
+		http.Error(w, "no configuration loaded", http.StatusBadRequest)
+		return
+	}
+
+	if d.noMoreSteps(w) {
+		return
+	}
+
+	d.withStreaming(r.Context(), w, func(ctx context.Context) error {
+		_, err := d.executor.Continue(ctx)
+		return err
+	})
+}
+
+// handleListBreakpoints lists the breakpoints in the debug session.
+func (d *localDaemonREST) handleListBreakpoints(w http.ResponseWriter, r *http.Request) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.executor == nil {
+		http.Error(w, "no configuration loaded", http.StatusBadRequest)
+		return
+	}
+
+	grip.Error(r.Context(), json.NewEncoder(w).Encode(map[string]any{
+		"breakpoints": d.executor.GetDebugState().Breakpoints,
+	}))
+}
+
+// handleAddBreakpoint adds a breakpoint to the debug session.
+func (d *localDaemonREST) handleAddBreakpoint(w http.ResponseWriter, r *http.Request) {
+	var bp taskexec.Breakpoint
+	if err := json.NewDecoder(r.Body).Decode(&bp); err != nil {
+		http.Error(w, errors.Wrap(err, "adding breakpoint").Error(), http.StatusBadRequest)
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.executor == nil {
+		http.Error(w, "no configuration loaded", http.StatusBadRequest)
+		return
+	}
+
+	added, err := d.executor.GetDebugState().AddBreakpoint(bp)
+	if err != nil {
+		http.Error(w, errors.Wrap(err, "adding breakpoint").Error(), http.StatusBadRequest)
+		return
+	}
+
+	grip.Error(r.Context(), json.NewEncoder(w).Encode(map[string]any{
+		"success":    true,
+		"breakpoint": added,
+	}))
+}
+
+// handleRemoveBreakpoint removes a breakpoint from the debug session.
+func (d *localDaemonREST) handleRemoveBreakpoint(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.executor == nil {
+		http.Error(w, "no configuration loaded", http.StatusBadRequest)
+		return
+	}
+
+	if err := d.executor.GetDebugState().RemoveBreakpoint(name); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	grip.Error(r.Context(), json.NewEncoder(w).Encode(map[string]bool{"success": true}))
+}
+
+// handleClearBreakpoints removes all breakpoints from the debug session.
+func (d *localDaemonREST) handleClearBreakpoints(w http.ResponseWriter, r *http.Request) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.executor == nil {
+		http.Error(w, "no configuration loaded", http.StatusBadRequest)
+		return
+	}
+
+	d.executor.GetDebugState().ClearBreakpoints()
+	grip.Error(r.Context(), json.NewEncoder(w).Encode(map[string]bool{"success": true}))
+}
+
+// handleListSnapshots lists the expansion snapshots taken at each stop.
+func (d *localDaemonREST) handleListSnapshots(w http.ResponseWriter, r *http.Request) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.executor == nil {
+		http.Error(w, "no configuration loaded", http.StatusBadRequest)
+		return
+	}
+
+	grip.Error(r.Context(), json.NewEncoder(w).Encode(map[string]any{
+		"snapshots": d.executor.GetDebugState().Snapshots,
+	}))
+}
+
+// handleDiffSnapshots diffs the expansion snapshots taken at two steps.
+func (d *localDaemonREST) handleDiffSnapshots(w http.ResponseWriter, r *http.Request) {
+	from := r.URL.Query().Get("from")
+	to := r.URL.Query().Get("to")
+	if from == "" || to == "" {
+		http.Error(w, "both 'from' and 'to' steps are required", http.StatusBadRequest)
+		return
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.executor == nil {
+		http.Error(w, "no configuration loaded", http.StatusBadRequest)
+		return
+	}
+
+	diff, err := d.executor.GetDebugState().DiffSnapshots(from, to)
+	if err != nil {
+		http.Error(w, errors.Wrap(err, "diffing snapshots").Error(), http.StatusNotFound)
+		return
+	}
+
+	grip.Error(r.Context(), json.NewEncoder(w).Encode(diff))
+}
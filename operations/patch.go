@@ -312,7 +312,7 @@ func Patch() cli.Command {
 				if err != nil {
 					return err
 				}
-				if shouldContinue {
+				if shouldContinue && checkPatchCostEstimate(ctx, comm, params.SkipConfirm, patchId) {
 					if err = ac.FinalizePatch(ctx, patchId); err != nil {
 						return errors.Wrapf(err, "finalizing patch '%s'", patchId)
 					}
@@ -382,6 +382,40 @@ func checkForLargeNumFinalizedTasks(ctx context.Context, comm client.Communicato
 	return true, nil
 }
 
+// checkPatchCostEstimate prints the estimated cost of an un-finalized patch and,
+// if the estimate exceeds the project's patch confirmation threshold, prompts
+// the user to confirm. It returns true if the finalization process should go
+// through, and false otherwise. Failing to estimate the cost does not prevent
+// finalizing the patch, and nothing is printed if cost estimates aren't
+// available.
+func checkPatchCostEstimate(ctx context.Context, comm client.Communicator, skipConfirm bool, patchId string) bool {
+	if skipConfirm {
+		return true
+	}
+	estimate, err := comm.GetPatchCostEstimate(ctx, patchId)
+	if err != nil {
+		grip.Warning(ctx, errors.Wrapf(err, "could not estimate the cost of patch '%s'", patchId))
+		return true
+	}
+	if estimate == nil {
+		return true
+	}
+
+	total := estimate.Total.AdjustedTotal()
+	grip.Infof(ctx, "Estimated patch cost: $%.2f", total)
+	for _, v := range estimate.Variants {
+		msg := fmt.Sprintf("    %s: $%.2f for %d task(s)", utility.FromStringPtr(v.BuildVariant), v.Cost.AdjustedTotal(), v.NumTasks)
+		if v.NumUnpricedTasks > 0 {
+			msg += fmt.Sprintf(" (%d without pricing)", v.NumUnpricedTasks)
+		}
+		grip.Info(ctx, msg)
+	}
+	if estimate.RequiresConfirmation {
+		return confirm(fmt.Sprintf("The estimated cost of $%.2f exceeds the project's threshold of $%.2f. Finalize anyway?", total, utility.FromFloat64Ptr(estimate.Threshold)), false)
+	}
+	return true
+}
+
 func getParametersFromInput(params []string) ([]patch.Parameter, error) {
 	res := []patch.Parameter{}
 	catcher := grip.NewBasicCatcher()
@@ -581,7 +615,7 @@ func PatchFile() cli.Command {
 				if err != nil {
 					return err
 				}
-				if shouldContinue {
+				if shouldContinue && checkPatchCostEstimate(ctx, comm, params.SkipConfirm, patchId) {
 					if err = ac.FinalizePatch(ctx, patchId); err != nil {
 						return errors.Wrapf(err, "finalizing patch '%s'", patchId)
 					}
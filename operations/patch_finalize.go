@@ -12,11 +12,15 @@ func PatchFinalize() cli.Command {
 	return cli.Command{
 		Name:   "finalize-patch",
 		Usage:  "finalize an existing patch",
-		Flags:  addPatchIDFlag(),
+		Flags:  addSkipConfirmFlag(addPatchIDFlag()...),
 		Before: mergeBeforeFuncs(autoUpdateCLI, requirePatchIDFlag),
 		Action: func(c *cli.Context) error {
 			confPath := c.Parent().String(ConfFlagName)
 			patchID := c.String(patchIDFlagName)
+			skipConfirm := c.Bool(skipConfirmFlagName)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
 
 			conf, err := NewClientSettings(confPath)
 			if err != nil {
@@ -28,7 +32,17 @@ func PatchFinalize() cli.Command {
 				return errors.Wrap(err, "setting up legacy Evergreen client")
 			}
 
-			if err = ac.FinalizePatch(context.Background(), patchID); err != nil {
+			comm, err := conf.setupRestCommunicator(ctx, false)
+			if err != nil {
+				return errors.Wrap(err, "setting up REST communicator")
+			}
+			defer comm.Close()
+
+			if !checkPatchCostEstimate(ctx, comm, skipConfirm, patchID) {
+				return nil
+			}
+
+			if err = ac.FinalizePatch(ctx, patchID); err != nil {
 				return err
 			}
 
@@ -96,7 +96,7 @@ func PatchSetModule() cli.Command {
 				if err != nil {
 					return err
 				}
-				if shouldContinue {
+				if shouldContinue && checkPatchCostEstimate(ctx, comm, params.SkipConfirm, patchID) {
 					if err = ac.FinalizePatch(ctx, patchID); err != nil {
 						return errors.Wrapf(err, "finalizing patch '%s'", patchID)
 					}
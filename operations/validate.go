@@ -18,7 +18,10 @@ import (
 )
 
 func Validate() cli.Command {
-	const yamlAnchorsFlagName = "yaml-anchors"
+	const (
+		yamlAnchorsFlagName = "yaml-anchors"
+		generatedFlagName   = "generated"
+	)
 
 	return cli.Command{
 		Name:  "validate",
@@ -38,6 +41,9 @@ func Validate() cli.Command {
 		}, cli.BoolFlag{
 			Name:  yamlAnchorsFlagName,
 			Usage: "(BETA) enable cross-file YAML anchors in included files",
+		}, cli.StringSliceFlag{
+			Name:  generatedFlagName,
+			Usage: "dry run generate.tasks with the given generated JSON file(s) and show what would be added to the project config",
 		}),
 		Before: mergeBeforeFuncs(autoUpdateCLI, setPlainLogger, requirePathFlag),
 		Action: func(c *cli.Context) error {
@@ -50,6 +56,7 @@ func Validate() cli.Command {
 			errorOnWarnings := c.Bool(errorOnWarningsFlagName)
 			projectID := c.String(projectFlagName)
 			enableAnchors := c.Bool(yamlAnchorsFlagName)
+			generatedPaths := c.StringSlice(generatedFlagName)
 			localModulePaths := c.StringSlice(localModulesFlagName)
 			localModuleMap, err := getLocalModulesFromInput(localModulePaths)
 			if err != nil {
@@ -74,6 +81,17 @@ func Validate() cli.Command {
 				return errors.Wrapf(err, "getting file info for path '%s'", path)
 			}
 
+			if len(generatedPaths) > 0 {
+				if fileInfo.Mode()&os.ModeDir != 0 {
+					return errors.New("cannot dry run generated files against a directory")
+				}
+				projectYaml, err := loadProjectYAML(path, quiet, errorOnWarnings, enableAnchors, localModuleMap, projectID)
+				if err != nil {
+					return err
+				}
+				return validateGeneratedRemotely(conf, projectYaml, path, generatedPaths, projectID)
+			}
+
 			if fileInfo.Mode()&os.ModeDir != 0 { // directory
 				files, err := os.ReadDir(path)
 				if err != nil {
@@ -183,6 +201,44 @@ func validateProjectRemotely(conf *ClientSettings, projectYaml []byte, path stri
 	return nil
 }
 
+// validateGeneratedRemotely sends the project YAML and the generated JSON files
+// to the server to dry run generate.tasks, and prints what would be added.
+func validateGeneratedRemotely(conf *ClientSettings, projectYaml []byte, path string, generatedPaths []string, projectID string) error {
+	ctx := context.Background()
+	generated := make([]string, 0, len(generatedPaths))
+	for _, generatedPath := range generatedPaths {
+		data, err := os.ReadFile(generatedPath)
+		if err != nil {
+			return errors.Wrapf(err, "reading generated file '%s'", generatedPath)
+		}
+		generated = append(generated, string(data))
+	}
+
+	client, err := conf.setupRestCommunicator(ctx, false)
+	if err != nil {
+		return errors.Wrap(err, "setting up REST communicator")
+	}
+	defer client.Close()
+
+	result, err := client.ValidateGenerated(ctx, projectYaml, generated, projectID)
+	if err != nil {
+		return errors.Wrapf(err, "dry running generated files for project '%s'", projectID)
+	}
+
+	diff, err := yaml.Marshal(result.Diff)
+	if err != nil {
+		return errors.Wrap(err, "marshalling generated project diff into YAML")
+	}
+	grip.Info(ctx, string(diff))
+	if len(result.Errors) > 0 {
+		grip.Info(ctx, result.Errors)
+		return errors.Errorf("generate.tasks would fail for %s", path)
+	}
+	grip.Infof(ctx, "generate.tasks would succeed for %s", path)
+
+	return nil
+}
+
 // loadProjectIntoWithValidation returns a warning (instead of an error) if there's an error with unmarshalling strictly
 func loadProjectIntoWithValidation(ctx context.Context, data []byte, opts *model.GetProjectOpts, errorOnWarnings bool,
 	project *model.Project, projectID string) (*model.ParserProject, *model.ProjectConfig, validator.ValidationErrors) {
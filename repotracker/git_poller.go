@@ -0,0 +1,265 @@
+package repotracker
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	// gitTokenDefaultUsername is the username sent along with the access
+	// token to HTTP(S) git remotes whose URL does not specify one.
+	gitTokenDefaultUsername = "oauth2"
+
+	// mergeBaseSearchDepth is the number of commits of history fetched to
+	// find the merge base of a revision that is no longer on the branch.
+	mergeBaseSearchDepth = 1000
+	// mergeBaseTimeout is the time allowed to find the merge base.
+	mergeBaseTimeout = time.Minute
+)
+
+// GitRepositoryPoller is a RepoPoller for repositories hosted on any git
+// server, which reads the repository by making minimal git clones of it.
+type GitRepositoryPoller struct {
+	ProjectRef *model.ProjectRef
+	// Settings gates the project translation cache when loading remote configs; may be nil.
+	Settings *evergreen.Settings
+}
+
+// NewGitRepositoryPoller constructs and returns a pointer to a
+// GitRepositoryPoller struct.
+func NewGitRepositoryPoller(projectRef *model.ProjectRef, settings *evergreen.Settings) *GitRepositoryPoller {
+	return &GitRepositoryPoller{
+		ProjectRef: projectRef,
+		Settings:   settings,
+	}
+}
+
+// remoteURL returns the URL of the git remote, including the access token if
+// one is configured.
+func (p *GitRepositoryPoller) remoteURL(ctx context.Context) (string, error) {
+	token, err := p.ProjectRef.GetRepoSourceToken(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "getting git access token")
+	}
+	if token == "" {
+		return p.ProjectRef.RepoSource.URL, nil
+	}
+
+	u, err := url.Parse(p.ProjectRef.RepoSource.URL)
+	if err != nil || u.Scheme != "https" {
+		return "", errors.New("access tokens can only be used with HTTPS git remotes")
+	}
+	username := gitTokenDefaultUsername
+	if u.User != nil && u.User.Username() != "" {
+		username = u.User.Username()
+	}
+	u.User = url.UserPassword(username, token)
+	return u.String(), nil
+}
+
+// clone makes a minimal clone of the repository. Callers must clean up the
+// returned directory with cleanup.
+func (p *GitRepositoryPoller) clone(ctx context.Context, opts thirdparty.GitCloneOptions) (string, error) {
+	remoteURL, err := p.remoteURL(ctx)
+	if err != nil {
+		return "", err
+	}
+	dir, err := thirdparty.GitCloneMinimalFromURL(ctx, remoteURL, opts)
+	return dir, errors.Wrapf(err, "git cloning repository for project '%s'", p.ProjectRef.Id)
+}
+
+func (p *GitRepositoryPoller) cleanup(ctx context.Context, dir string) {
+	grip.Warning(ctx, message.WrapError(os.RemoveAll(dir), message.Fields{
+		"message":            "could not clean up git clone directory",
+		"project":            p.ProjectRef.Id,
+		"project_identifier": p.ProjectRef.Identifier,
+		"dir":                dir,
+	}))
+}
+
+// gitCommitToRevision converts a git commit to Evergreen's revision model.
+func gitCommitToRevision(commit thirdparty.GitCommit) model.Revision {
+	return model.Revision{
+		Author:          commit.AuthorName,
+		AuthorEmail:     commit.AuthorEmail,
+		RevisionMessage: commit.Message,
+		Revision:        commit.Hash,
+		CreateTime:      commit.CommitTime,
+	}
+}
+
+// GetRemoteConfig fetches the contents of a remote git repository's
+// configuration data as at a given revision.
+func (p *GitRepositoryPoller) GetRemoteConfig(ctx context.Context, projectFileRevision string) (model.ProjectInfo, error) {
+	dir, err := p.clone(ctx, thirdparty.GitCloneOptions{
+		Revision: projectFileRevision,
+		Depth:    1,
+	})
+	if err != nil {
+		return model.ProjectInfo{}, err
+	}
+	defer p.cleanup(ctx, dir)
+
+	opts := model.GetProjectOpts{
+		Ref:          p.ProjectRef,
+		RemotePath:   p.ProjectRef.RemotePath,
+		Revision:     projectFileRevision,
+		ReadFileFrom: model.ReadFromGit,
+		Worktree:     dir,
+	}
+	return model.GetProjectFromFile(ctx, opts, p.Settings)
+}
+
+// GetChangedFiles fetches the paths of the files changed by the given
+// revision.
+func (p *GitRepositoryPoller) GetChangedFiles(ctx context.Context, commitRevision string) ([]string, error) {
+	// Clone the revision's parent as well to compare against it.
+	dir, err := p.clone(ctx, thirdparty.GitCloneOptions{
+		Revision: commitRevision,
+		Depth:    2,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer p.cleanup(ctx, dir)
+
+	files, err := thirdparty.GitChangedFiles(ctx, dir, commitRevision)
+	return files, errors.Wrapf(err, "loading commit '%s'", commitRevision)
+}
+
+// GetRevisionsSince fetches all the commits on the project's branch that were
+// made after the given revision, in order of most to least recent. If it
+// cannot find the revision within maxRevisionsToSearch commits, it attempts to
+// use the merge base of the revision and the branch instead.
+func (p *GitRepositoryPoller) GetRevisionsSince(ctx context.Context, revision string, maxRevisionsToSearch int) ([]model.Revision, error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+	defer cancel()
+
+	dir, err := p.clone(ctx, thirdparty.GitCloneOptions{
+		Branch: p.ProjectRef.Branch,
+		Depth:  maxRevisionsToSearch,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer p.cleanup(ctx, dir)
+
+	commits, err := thirdparty.GitLog(ctx, dir, "HEAD", maxRevisionsToSearch)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting commits of branch '%s'", p.ProjectRef.Branch)
+	}
+
+	var foundLatest bool
+	revisions := []model.Revision{}
+	for _, commit := range commits {
+		if commit.Hash == revision {
+			foundLatest = true
+			break
+		}
+		revisions = append(revisions, gitCommitToRevision(commit))
+	}
+
+	if !foundLatest {
+		var baseCommit *thirdparty.GitCommit
+		if len(commits) > 0 {
+			baseCommit, err = p.getMergeBase(ctx, revision, commits[0].Hash)
+		} else {
+			err = errors.New("no recent commit found")
+		}
+		if len(revision) < 10 {
+			return nil, errors.Errorf("invalid revision '%s'", revision)
+		}
+		if err != nil {
+			return []model.Revision{}, setMergeBaseRepotrackerError(ctx, p.ProjectRef, revision, err)
+		}
+
+		revisions = append(revisions, gitCommitToRevision(*baseCommit))
+
+		if err = updateLastRevisionToMergeBase(ctx, p.ProjectRef, "git poller", revision, baseCommit.Hash); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(revisions) == 0 {
+		grip.Info(ctx, message.Fields{
+			"source":             "git poller",
+			"message":            "no new revisions",
+			"last_revision":      revision,
+			"project":            p.ProjectRef.Id,
+			"project_identifier": p.ProjectRef.Identifier,
+		})
+	}
+
+	return revisions, nil
+}
+
+// getMergeBase returns the merge base of the revision, which may no longer be
+// on the project's branch, and a revision on the branch. It only fetches the
+// most recent mergeBaseSearchDepth commits of each, so it fails if the merge
+// base is older than that. It has its own timeout rather than sharing the
+// caller's since it makes its own clone.
+func (p *GitRepositoryPoller) getMergeBase(ctx context.Context, revision, branchRevision string) (*thirdparty.GitCommit, error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), mergeBaseTimeout)
+	defer cancel()
+
+	dir, err := p.clone(ctx, thirdparty.GitCloneOptions{
+		Branch: p.ProjectRef.Branch,
+		Depth:  mergeBaseSearchDepth,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer p.cleanup(ctx, dir)
+
+	if err = thirdparty.GitFetchRevision(ctx, dir, revision, mergeBaseSearchDepth); err != nil {
+		return nil, err
+	}
+	baseRevision, err := thirdparty.GitMergeBase(ctx, dir, revision, branchRevision)
+	if err != nil {
+		return nil, err
+	}
+	commits, err := thirdparty.GitLog(ctx, dir, baseRevision, 1)
+	if err != nil {
+		return nil, errors.Wrapf(err, "loading base commit '%s'", baseRevision)
+	}
+	if len(commits) == 0 {
+		return nil, errors.Errorf("base commit '%s' not found", baseRevision)
+	}
+	return &commits[0], nil
+}
+
+// GetRecentRevisions fetches the most recent maxRevisions commits on the
+// project's branch.
+func (p *GitRepositoryPoller) GetRecentRevisions(ctx context.Context, maxRevisions int) ([]model.Revision, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	dir, err := p.clone(ctx, thirdparty.GitCloneOptions{
+		Branch: p.ProjectRef.Branch,
+		Depth:  maxRevisions,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer p.cleanup(ctx, dir)
+
+	commits, err := thirdparty.GitLog(ctx, dir, "HEAD", maxRevisions)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting commits of branch '%s'", p.ProjectRef.Branch)
+	}
+
+	var revisions []model.Revision
+	for _, commit := range commits {
+		revisions = append(revisions, gitCommitToRevision(commit))
+	}
+	return revisions, nil
+}
@@ -0,0 +1,148 @@
+package repotracker
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runTestGitCommand(t *testing.T, dir string, args ...string) string {
+	var stdout, stderr strings.Builder
+	cmd := exec.CommandContext(t.Context(), "git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	require.NoError(t, cmd.Run(), "git %s failed: '%s'", args[0], stderr.String())
+	return strings.TrimSpace(stdout.String())
+}
+
+// makeTestGitRepo creates a local git repository on the main branch with one
+// commit per given message and returns its directory and the commit hashes
+// from oldest to newest.
+func makeTestGitRepo(t *testing.T, messages ...string) (string, []string) {
+	dir := t.TempDir()
+	runTestGitCommand(t, dir, "init", "--quiet", "--initial-branch=main")
+	runTestGitCommand(t, dir, "config", "user.name", "Evergreen")
+	runTestGitCommand(t, dir, "config", "user.email", "evergreen@example.com")
+
+	var hashes []string
+	for i, message := range messages {
+		file := message + ".txt"
+		contents := message
+		if i == 0 {
+			file = "evergreen.yml"
+			contents = "tasks:\n- name: compile\nbuildvariants:\n- name: ubuntu\n  run_on: ubuntu\n  tasks:\n  - compile\n"
+		}
+		require.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte(contents), 0644))
+		runTestGitCommand(t, dir, "add", file)
+		runTestGitCommand(t, dir, "commit", "--quiet", "--message", message)
+		hashes = append(hashes, runTestGitCommand(t, dir, "rev-parse", "HEAD"))
+	}
+	return dir, hashes
+}
+
+func skipIfGitCannotCloneRevision(t *testing.T) {
+	out, _ := exec.CommandContext(t.Context(), "git", "clone", "-h").CombinedOutput()
+	if !strings.Contains(string(out), "--revision") {
+		t.Skip("installed git does not support cloning a specific revision")
+	}
+}
+
+func TestGitRepositoryPoller(t *testing.T) {
+	repoDir, hashes := makeTestGitRepo(t, "first", "second", "third", "fourth")
+	poller := NewGitRepositoryPoller(&model.ProjectRef{
+		Id:         "project",
+		Owner:      "owner",
+		Repo:       "repo",
+		Branch:     "main",
+		RemotePath: "evergreen.yml",
+		RepoSource: model.RepoSourceSettings{
+			Provider: model.RepoProviderGit,
+			URL:      "file://" + repoDir,
+		},
+	}, nil)
+
+	t.Run("GetRecentRevisions", func(t *testing.T) {
+		revisions, err := poller.GetRecentRevisions(t.Context(), 2)
+		require.NoError(t, err)
+		require.Len(t, revisions, 2)
+		assert.Equal(t, hashes[3], revisions[0].Revision)
+		assert.Equal(t, "fourth", revisions[0].RevisionMessage)
+		assert.Equal(t, "Evergreen", revisions[0].Author)
+		assert.Equal(t, "evergreen@example.com", revisions[0].AuthorEmail)
+		assert.False(t, revisions[0].CreateTime.IsZero())
+		assert.Equal(t, hashes[2], revisions[1].Revision)
+	})
+	t.Run("GetRevisionsSince", func(t *testing.T) {
+		revisions, err := poller.GetRevisionsSince(t.Context(), hashes[1], 10)
+		require.NoError(t, err)
+		require.Len(t, revisions, 2)
+		assert.Equal(t, hashes[3], revisions[0].Revision)
+		assert.Equal(t, hashes[2], revisions[1].Revision)
+	})
+	t.Run("GetRevisionsSinceLatestRevisionIsEmpty", func(t *testing.T) {
+		revisions, err := poller.GetRevisionsSince(t.Context(), hashes[3], 10)
+		require.NoError(t, err)
+		assert.Empty(t, revisions)
+	})
+	t.Run("GetMergeBaseOfRevisionNotOnBranch", func(t *testing.T) {
+		runTestGitCommand(t, repoDir, "checkout", "--quiet", "-b", "other", hashes[1])
+		require.NoError(t, os.WriteFile(filepath.Join(repoDir, "other.txt"), []byte("other"), 0644))
+		runTestGitCommand(t, repoDir, "add", "other.txt")
+		runTestGitCommand(t, repoDir, "commit", "--quiet", "--message", "other")
+		otherHash := runTestGitCommand(t, repoDir, "rev-parse", "HEAD")
+		runTestGitCommand(t, repoDir, "checkout", "--quiet", "main")
+
+		baseCommit, err := poller.getMergeBase(t.Context(), otherHash, hashes[3])
+		require.NoError(t, err)
+		assert.Equal(t, hashes[1], baseCommit.Hash)
+		assert.Equal(t, "second", baseCommit.Message)
+	})
+	t.Run("GetChangedFiles", func(t *testing.T) {
+		skipIfGitCannotCloneRevision(t)
+
+		files, err := poller.GetChangedFiles(t.Context(), hashes[2])
+		require.NoError(t, err)
+		assert.Equal(t, []string{"third.txt"}, files)
+	})
+	t.Run("GetRemoteConfig", func(t *testing.T) {
+		skipIfGitCannotCloneRevision(t)
+
+		projectInfo, err := poller.GetRemoteConfig(t.Context(), hashes[3])
+		require.NoError(t, err)
+		require.NotNil(t, projectInfo.Project)
+		require.Len(t, projectInfo.Project.Tasks, 1)
+		assert.Equal(t, "compile", projectInfo.Project.Tasks[0].Name)
+	})
+	t.Run("FailsForNonexistentBranch", func(t *testing.T) {
+		ref := *poller.ProjectRef
+		ref.Branch = "nonexistent"
+		_, err := NewGitRepositoryPoller(&ref, nil).GetRecentRevisions(t.Context(), 2)
+		assert.Error(t, err)
+	})
+}
+
+func TestNewRepoPoller(t *testing.T) {
+	for provider, expected := range map[model.RepoProvider]RepoPoller{
+		"":                       &GithubRepositoryPoller{},
+		model.RepoProviderGitHub: &GithubRepositoryPoller{},
+		model.RepoProviderGitLab: &GitLabRepositoryPoller{},
+		model.RepoProviderGit:    &GitRepositoryPoller{},
+	} {
+		t.Run(string(provider), func(t *testing.T) {
+			poller, err := NewRepoPoller(&model.ProjectRef{RepoSource: model.RepoSourceSettings{Provider: provider}}, nil)
+			require.NoError(t, err)
+			assert.IsType(t, expected, poller)
+		})
+	}
+	t.Run("FailsForUnrecognizedProvider", func(t *testing.T) {
+		_, err := NewRepoPoller(&model.ProjectRef{RepoSource: model.RepoSourceSettings{Provider: "svn"}}, nil)
+		assert.Error(t, err)
+	})
+}
@@ -195,18 +195,7 @@ func (gRepoPoller *GithubRepositoryPoller) GetRevisionsSince(ctx context.Context
 		}
 		if err != nil {
 			// unable to get merge base commit so set projectRef revision details with a blank base revision
-			revisionDetails := &model.RepositoryErrorDetails{
-				Exists:            true,
-				InvalidRevision:   revision[:10],
-				MergeBaseRevision: "",
-			}
-			revisionError := errors.Wrapf(err,
-				"unable to find a suggested merge base commit for revision '%s', must fix on projects settings page",
-				revision)
-			if err := gRepoPoller.ProjectRef.SetRepotrackerError(ctx, revisionDetails); err != nil {
-				return []model.Revision{}, errors.Wrap(err, "setting repotracker error")
-			}
-			return []model.Revision{}, revisionError
+			return []model.Revision{}, setMergeBaseRepotrackerError(ctx, gRepoPoller.ProjectRef, revision, err)
 		}
 
 		// automatically set the newly found base revision as base revision and append revisions
@@ -216,16 +205,8 @@ func (gRepoPoller *GithubRepositoryPoller) GetRevisionsSince(ctx context.Context
 		}
 		revisions = append(revisions, githubCommitToRevision(commit))
 
-		grip.Info(ctx, message.Fields{
-			"message":            "updating last repo revision for project",
-			"source":             "github poller",
-			"old_revision":       revision,
-			"new_revision":       baseRevision,
-			"project":            gRepoPoller.ProjectRef.Id,
-			"project_identifier": gRepoPoller.ProjectRef.Identifier,
-		})
-		if err = model.UpdateLastRevision(ctx, gRepoPoller.ProjectRef.Id, baseRevision); err != nil {
-			return nil, errors.Wrapf(err, "updating last revision to base revision '%s'", baseRevision)
+		if err = updateLastRevisionToMergeBase(ctx, gRepoPoller.ProjectRef, "github poller", revision, baseRevision); err != nil {
+			return nil, err
 		}
 	}
 
@@ -0,0 +1,217 @@
+package repotracker
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// GitLabRepositoryPoller is a RepoPoller for repositories hosted on GitLab,
+// which reads the repository through the GitLab REST API.
+type GitLabRepositoryPoller struct {
+	ProjectRef *model.ProjectRef
+	// Settings gates the project translation cache when loading remote configs; may be nil.
+	Settings *evergreen.Settings
+}
+
+// NewGitLabRepositoryPoller constructs and returns a pointer to a
+// GitLabRepositoryPoller struct.
+func NewGitLabRepositoryPoller(projectRef *model.ProjectRef, settings *evergreen.Settings) *GitLabRepositoryPoller {
+	return &GitLabRepositoryPoller{
+		ProjectRef: projectRef,
+		Settings:   settings,
+	}
+}
+
+// projectPath returns the path of the GitLab project, which GitLab uses to
+// identify it.
+func (p *GitLabRepositoryPoller) projectPath() string {
+	return p.ProjectRef.Owner + "/" + p.ProjectRef.Repo
+}
+
+func (p *GitLabRepositoryPoller) newClient(ctx context.Context) (*thirdparty.GitLabClient, error) {
+	token, err := p.ProjectRef.GetRepoSourceToken(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting GitLab access token")
+	}
+	return thirdparty.NewGitLabClient(p.ProjectRef.RepoSource.GetGitLabURL(), token)
+}
+
+// gitLabCommitToRevision converts a GitLab commit to Evergreen's revision
+// model.
+func gitLabCommitToRevision(commit thirdparty.GitLabCommit) model.Revision {
+	return model.Revision{
+		Author:          commit.AuthorName,
+		AuthorEmail:     commit.AuthorEmail,
+		RevisionMessage: strings.TrimRight(commit.Message, "\n"),
+		Revision:        commit.ID,
+		CreateTime:      commit.CommittedDate,
+	}
+}
+
+// GetRemoteConfig fetches the contents of a remote GitLab repository's
+// configuration data as at a given revision.
+func (p *GitLabRepositoryPoller) GetRemoteConfig(ctx context.Context, projectFileRevision string) (model.ProjectInfo, error) {
+	token, err := p.ProjectRef.GetRepoSourceToken(ctx)
+	if err != nil {
+		return model.ProjectInfo{}, errors.Wrap(err, "getting GitLab access token")
+	}
+
+	opts := model.GetProjectOpts{
+		Ref:             p.ProjectRef,
+		RemotePath:      p.ProjectRef.RemotePath,
+		Revision:        projectFileRevision,
+		ReadFileFrom:    model.ReadFromGitLab,
+		RepoSourceToken: token,
+	}
+	return model.GetProjectFromFile(ctx, opts, p.Settings)
+}
+
+// GetChangedFiles fetches the paths of the files changed by the given
+// revision.
+func (p *GitLabRepositoryPoller) GetChangedFiles(ctx context.Context, commitRevision string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	client, err := p.newClient(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating GitLab client")
+	}
+	defer client.Close()
+
+	diffs, err := client.GetCommitDiff(ctx, p.projectPath(), commitRevision)
+	if err != nil {
+		return nil, errors.Wrapf(err, "loading commit '%s'", commitRevision)
+	}
+
+	files := []string{}
+	for _, diff := range diffs {
+		files = append(files, diff.NewPath)
+		// A renamed file changes both its old and new paths.
+		if diff.OldPath != "" && diff.OldPath != diff.NewPath {
+			files = append(files, diff.OldPath)
+		}
+	}
+	return files, nil
+}
+
+// GetRevisionsSince fetches all the commits on the project's branch that were
+// made after the given revision, in order of most to least recent. If it
+// cannot find the revision within maxRevisionsToSearch commits, it attempts to
+// use the merge base of the revision and the branch instead.
+func (p *GitLabRepositoryPoller) GetRevisionsSince(ctx context.Context, revision string, maxRevisionsToSearch int) ([]model.Revision, error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+	defer cancel()
+
+	client, err := p.newClient(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating GitLab client")
+	}
+	defer client.Close()
+
+	var foundLatest bool
+	var firstCommit *thirdparty.GitLabCommit
+	revisions := []model.Revision{}
+	page := 1
+
+	for page > 0 && (maxRevisionsToSearch <= 0 || len(revisions) < maxRevisionsToSearch) {
+		var commits []thirdparty.GitLabCommit
+		commits, page, err = client.GetCommits(ctx, p.projectPath(), p.ProjectRef.Branch, page)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range commits {
+			if maxRevisionsToSearch > 0 && len(revisions) >= maxRevisionsToSearch {
+				break
+			}
+			if firstCommit == nil {
+				firstCommit = &commits[i]
+			}
+			if commits[i].ID == revision {
+				foundLatest = true
+				break
+			}
+			revisions = append(revisions, gitLabCommitToRevision(commits[i]))
+		}
+		if foundLatest {
+			break
+		}
+	}
+
+	if !foundLatest {
+		var baseRevision string
+		if firstCommit != nil {
+			baseRevision, err = client.GetMergeBase(ctx, p.projectPath(), revision, firstCommit.ID)
+		} else {
+			err = errors.New("no recent commit found")
+		}
+		if len(revision) < 10 {
+			return nil, errors.Errorf("invalid revision '%s'", revision)
+		}
+		if err != nil {
+			return []model.Revision{}, setMergeBaseRepotrackerError(ctx, p.ProjectRef, revision, err)
+		}
+
+		commit, err := client.GetCommit(ctx, p.projectPath(), baseRevision)
+		if err != nil {
+			return nil, errors.Wrapf(err, "loading base commit '%s'", baseRevision)
+		}
+		revisions = append(revisions, gitLabCommitToRevision(*commit))
+
+		if err = updateLastRevisionToMergeBase(ctx, p.ProjectRef, "gitlab poller", revision, baseRevision); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(revisions) == 0 {
+		grip.Info(ctx, message.Fields{
+			"source":             "gitlab poller",
+			"message":            "no new revisions",
+			"last_revision":      revision,
+			"project":            p.ProjectRef.Id,
+			"project_identifier": p.ProjectRef.Identifier,
+		})
+	}
+
+	return revisions, nil
+}
+
+// GetRecentRevisions fetches the most recent maxRevisions commits on the
+// project's branch.
+func (p *GitLabRepositoryPoller) GetRecentRevisions(ctx context.Context, maxRevisions int) ([]model.Revision, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	client, err := p.newClient(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating GitLab client")
+	}
+	defer client.Close()
+
+	var revisions []model.Revision
+	page := 1
+	for page > 0 && len(revisions) < maxRevisions {
+		var commits []thirdparty.GitLabCommit
+		commits, page, err = client.GetCommits(ctx, p.projectPath(), p.ProjectRef.Branch, page)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, commit := range commits {
+			if len(revisions) == maxRevisions {
+				break
+			}
+			revisions = append(revisions, gitLabCommitToRevision(commit))
+		}
+	}
+
+	return revisions, nil
+}
@@ -0,0 +1,108 @@
+package repotracker
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitLabRepositoryPoller(t *testing.T) {
+	const projectPrefix = "/api/v4/projects/group%2Frepo/"
+	const config = "tasks:\n- name: compile\nbuildvariants:\n- name: ubuntu\n  run_on: ubuntu\n  tasks:\n  - compile\n"
+	commits := []thirdparty.GitLabCommit{
+		{ID: "4444444444444444444444444444444444444444", Message: "fourth\n", AuthorName: "Evergreen", AuthorEmail: "evergreen@example.com", CommittedDate: time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC)},
+		{ID: "3333333333333333333333333333333333333333", Message: "third\n", AuthorName: "Evergreen", AuthorEmail: "evergreen@example.com", CommittedDate: time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)},
+		{ID: "2222222222222222222222222222222222222222", Message: "second\n", AuthorName: "Evergreen", AuthorEmail: "evergreen@example.com", CommittedDate: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{ID: "1111111111111111111111111111111111111111", Message: "first\n", AuthorName: "Evergreen", AuthorEmail: "evergreen@example.com", CommittedDate: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case projectPrefix + "repository/commits":
+			assert.Equal(t, "main", r.URL.Query().Get("ref_name"))
+			// Return two commits per page to exercise pagination.
+			switch r.URL.Query().Get("page") {
+			case "", "1":
+				w.Header().Set("X-Next-Page", "2")
+				assert.NoError(t, json.NewEncoder(w).Encode(commits[:2]))
+			default:
+				assert.NoError(t, json.NewEncoder(w).Encode(commits[2:]))
+			}
+		case projectPrefix + "repository/commits/" + commits[1].ID + "/diff":
+			assert.NoError(t, json.NewEncoder(w).Encode([]thirdparty.GitLabDiff{
+				{OldPath: "a.txt", NewPath: "a.txt"},
+				{OldPath: "old.txt", NewPath: "new.txt", RenamedFile: true},
+			}))
+		case projectPrefix + "repository/files/evergreen.yml/raw":
+			if r.URL.Query().Get("ref") != commits[0].ID {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, err := w.Write([]byte(config))
+			assert.NoError(t, err)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	poller := NewGitLabRepositoryPoller(&model.ProjectRef{
+		Id:         "project",
+		Owner:      "group",
+		Repo:       "repo",
+		Branch:     "main",
+		RemotePath: "evergreen.yml",
+		RepoSource: model.RepoSourceSettings{
+			Provider: model.RepoProviderGitLab,
+			URL:      srv.URL,
+		},
+	}, nil)
+
+	t.Run("GetRecentRevisions", func(t *testing.T) {
+		revisions, err := poller.GetRecentRevisions(t.Context(), 3)
+		require.NoError(t, err)
+		require.Len(t, revisions, 3)
+		assert.Equal(t, commits[0].ID, revisions[0].Revision)
+		assert.Equal(t, "fourth", revisions[0].RevisionMessage)
+		assert.Equal(t, "Evergreen", revisions[0].Author)
+		assert.Equal(t, "evergreen@example.com", revisions[0].AuthorEmail)
+		assert.True(t, commits[0].CommittedDate.Equal(revisions[0].CreateTime))
+		assert.Equal(t, commits[2].ID, revisions[2].Revision)
+	})
+	t.Run("GetRevisionsSince", func(t *testing.T) {
+		revisions, err := poller.GetRevisionsSince(t.Context(), commits[3].ID, 10)
+		require.NoError(t, err)
+		require.Len(t, revisions, 3)
+		assert.Equal(t, commits[0].ID, revisions[0].Revision)
+		assert.Equal(t, commits[2].ID, revisions[2].Revision)
+	})
+	t.Run("GetRevisionsSinceLatestRevisionIsEmpty", func(t *testing.T) {
+		revisions, err := poller.GetRevisionsSince(t.Context(), commits[0].ID, 10)
+		require.NoError(t, err)
+		assert.Empty(t, revisions)
+	})
+	t.Run("GetChangedFiles", func(t *testing.T) {
+		files, err := poller.GetChangedFiles(t.Context(), commits[1].ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a.txt", "new.txt", "old.txt"}, files)
+	})
+	t.Run("GetRemoteConfig", func(t *testing.T) {
+		projectInfo, err := poller.GetRemoteConfig(t.Context(), commits[0].ID)
+		require.NoError(t, err)
+		require.NotNil(t, projectInfo.Project)
+		require.Len(t, projectInfo.Project.Tasks, 1)
+		assert.Equal(t, "compile", projectInfo.Project.Tasks[0].Name)
+	})
+	t.Run("GetRemoteConfigReturnsFileNotFound", func(t *testing.T) {
+		_, err := poller.GetRemoteConfig(t.Context(), commits[1].ID)
+		require.Error(t, err)
+		assert.True(t, thirdparty.IsFileNotFound(err))
+	})
+}
@@ -73,6 +73,37 @@ type RepoPoller interface {
 	GetRecentRevisions(ctx context.Context, numNewRepoRevisionsToFetch int) ([]model.Revision, error)
 }
 
+// setMergeBaseRepotrackerError records on the project that its last revision
+// could not be found on its branch and that no merge base could be suggested,
+// so it must be fixed on the project settings page.
+func setMergeBaseRepotrackerError(ctx context.Context, projectRef *model.ProjectRef, revision string, mergeBaseErr error) error {
+	revisionDetails := &model.RepositoryErrorDetails{
+		Exists:            true,
+		InvalidRevision:   revision[:10],
+		MergeBaseRevision: "",
+	}
+	if err := projectRef.SetRepotrackerError(ctx, revisionDetails); err != nil {
+		return errors.Wrap(err, "setting repotracker error")
+	}
+	return errors.Wrapf(mergeBaseErr,
+		"unable to find a suggested merge base commit for revision '%s', must fix on projects settings page",
+		revision)
+}
+
+// updateLastRevisionToMergeBase replaces the project's last revision, which
+// could not be found on its branch, with its merge base with the branch.
+func updateLastRevisionToMergeBase(ctx context.Context, projectRef *model.ProjectRef, source, revision, baseRevision string) error {
+	grip.Info(ctx, message.Fields{
+		"message":            "updating last repo revision for project",
+		"source":             source,
+		"old_revision":       revision,
+		"new_revision":       baseRevision,
+		"project":            projectRef.Id,
+		"project_identifier": projectRef.Identifier,
+	})
+	return errors.Wrapf(model.UpdateLastRevision(ctx, projectRef.Id, baseRevision), "updating last revision to base revision '%s'", baseRevision)
+}
+
 type projectConfigError struct {
 	Errors   []string
 	Warnings []string
@@ -738,7 +769,13 @@ func ShellVersionFromRevision(ctx context.Context, ref *model.ProjectRef, metada
 			revision = metadata.SourceVersion.Revision
 			createTime = metadata.SourceVersion.CreateTime
 		}
-		v.Id = util.CleanName(fmt.Sprintf("%s_%s_%s", ref.Identifier, revision, metadata.TriggerDefinitionID))
+		idSuffix := metadata.TriggerDefinitionID
+		if metadata.TriggerType == model.ProjectTriggerLevelPipeline {
+			// A source version can complete more than one of a pipeline's
+			// runs, so the run identifies the version instead.
+			idSuffix = metadata.TriggerID
+		}
+		v.Id = util.CleanName(fmt.Sprintf("%s_%s_%s", ref.Identifier, revision, idSuffix))
 		v.Requester = evergreen.TriggerRequester
 		v.CreateTime = createTime
 	} else if metadata.IsAdHoc {
@@ -22,15 +22,34 @@ const (
 )
 
 func getTracker(conf *evergreen.Settings, project model.ProjectRef) (*RepoTracker, error) {
+	poller, err := NewRepoPoller(&project, conf)
+	if err != nil {
+		return nil, err
+	}
 	tracker := &RepoTracker{
 		Settings:   conf,
 		ProjectRef: &project,
-		RepoPoller: NewGithubRepositoryPoller(&project, conf),
+		RepoPoller: poller,
 	}
 
 	return tracker, nil
 }
 
+// NewRepoPoller returns the RepoPoller for the host of the project's
+// repository.
+func NewRepoPoller(project *model.ProjectRef, conf *evergreen.Settings) (RepoPoller, error) {
+	switch provider := project.RepoSource.GetProvider(); provider {
+	case model.RepoProviderGitHub:
+		return NewGithubRepositoryPoller(project, conf), nil
+	case model.RepoProviderGitLab:
+		return NewGitLabRepositoryPoller(project, conf), nil
+	case model.RepoProviderGit:
+		return NewGitRepositoryPoller(project, conf), nil
+	default:
+		return nil, errors.Errorf("unrecognized repository provider '%s' for project '%s'", provider, project.Id)
+	}
+}
+
 func CollectRevisionsForProject(ctx context.Context, conf *evergreen.Settings, project model.ProjectRef) error {
 	if !project.Enabled || project.IsRepotrackerDisabled() {
 		return errors.Errorf("project disabled: %s", project.Id)
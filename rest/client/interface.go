@@ -11,6 +11,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/manifest"
 	restmodel "github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/scheduler"
 	"github.com/evergreen-ci/evergreen/validator"
 )
 
@@ -89,6 +90,9 @@ type Communicator interface {
 	ListAliases(context.Context, string, bool) ([]model.ProjectAlias, error)
 	ListPatchTriggerAliases(context.Context, string) ([]string, error)
 	GetDistroByName(context.Context, string) (*restmodel.APIDistro, error)
+	// GetSchedulerWorkload records the tasks that were activated on the
+	// distro in the given time range as a scheduler simulator workload.
+	GetSchedulerWorkload(ctx context.Context, distroID string, start, end time.Time) (*scheduler.SimulatorWorkload, error)
 
 	// Get project settings by project ID
 	GetProject(context.Context, string) (*restmodel.APIProjectRef, error)
@@ -142,12 +146,21 @@ type Communicator interface {
 	// GetEstimatedGeneratedTasks returns the estimated number of generated tasks to be created by an unfinalized patch.
 	GetEstimatedGeneratedTasks(context.Context, string, []model.TVPair) (int, error)
 
+	// GetPatchCostEstimate returns the predicted cost of the tasks selected in a patch.
+	// It returns nil if cost estimates aren't available for the patch.
+	GetPatchCostEstimate(context.Context, string) (*restmodel.APIPatchCostEstimate, error)
+
 	// RevokeGitHubDynamicAccessToken revokes the given GitHub dynamic access tokens.
 	RevokeGitHubDynamicAccessTokens(ctx context.Context, taskID string, tokens []string) error
 
 	// Validate validates a project configuration file.
 	Validate(ctx context.Context, data []byte, quiet bool, projectID string) (validator.ValidationErrors, error)
 
+	// ValidateGenerated merges generated JSON files into a project
+	// configuration file without saving anything, and returns what
+	// generate.tasks would add.
+	ValidateGenerated(ctx context.Context, data []byte, generated []string, projectID string) (*validator.GeneratedValidationResult, error)
+
 	// SendPanicReport sends a panic report to the evergreen service.
 	SendPanicReport(ctx context.Context, details *restmodel.PanicReport) error
 }
@@ -22,6 +22,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/manifest"
 	"github.com/evergreen-ci/evergreen/rest/model"
 	restmodel "github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/scheduler"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/evergreen/validator"
 	"github.com/evergreen-ci/gimlet"
@@ -639,6 +640,36 @@ func (c *communicatorImpl) GetEstimatedGeneratedTasks(ctx context.Context, patch
 	return utility.FromIntPtr(numTasksToFinalize.NumTasksToFinalize), nil
 }
 
+// GetPatchCostEstimate returns the predicted cost of the tasks selected in a patch.
+func (c *communicatorImpl) GetPatchCostEstimate(ctx context.Context, patchId string) (*model.APIPatchCostEstimate, error) {
+	info := requestInfo{
+		method: http.MethodGet,
+		path:   fmt.Sprintf("patches/%s/estimated_cost", patchId),
+	}
+	resp, err := c.request(ctx, info, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "sending request to estimate patch cost")
+	}
+	defer resp.Body.Close()
+
+	// The route returns 503 when the server has no finance configuration and
+	// 403 when costs are hidden for the patch's project, neither of which is
+	// an error for the caller.
+	if resp.StatusCode == http.StatusServiceUnavailable || resp.StatusCode == http.StatusForbidden {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, util.RespError(resp, "estimating patch cost")
+	}
+
+	estimate := &model.APIPatchCostEstimate{}
+	if err = utility.ReadJSON(resp.Body, estimate); err != nil {
+		return nil, errors.Wrap(err, "reading JSON response body")
+	}
+
+	return estimate, nil
+}
+
 func (c *communicatorImpl) RevokeGitHubDynamicAccessTokens(ctx context.Context, taskId string, tokens []string) error {
 	info := requestInfo{
 		method: http.MethodDelete,
@@ -1448,6 +1479,29 @@ func (c *communicatorImpl) GetDistroByName(ctx context.Context, id string) (*res
 
 }
 
+func (c *communicatorImpl) GetSchedulerWorkload(ctx context.Context, distroID string, start, end time.Time) (*scheduler.SimulatorWorkload, error) {
+	params := url.Values{}
+	params.Set("start", start.Format(time.RFC3339))
+	params.Set("end", end.Format(time.RFC3339))
+	info := requestInfo{
+		method: http.MethodGet,
+		path:   fmt.Sprintf("distros/%s/scheduler_workload?%s", distroID, params.Encode()),
+	}
+
+	resp, err := c.retryRequest(ctx, info, nil)
+	if err != nil {
+		return nil, util.RespError(resp, errors.Wrapf(err, "getting scheduler workload for distro '%s'", distroID).Error())
+	}
+	defer resp.Body.Close()
+
+	workload := &scheduler.SimulatorWorkload{}
+	if err = utility.ReadJSON(resp.Body, workload); err != nil {
+		return nil, errors.Wrap(err, "reading JSON response body")
+	}
+
+	return workload, nil
+}
+
 func (c *communicatorImpl) GetClientURLs(ctx context.Context, distroID string) ([]string, error) {
 	info := requestInfo{
 		method: http.MethodGet,
@@ -1795,6 +1849,31 @@ func (c *communicatorImpl) Validate(ctx context.Context, data []byte, quiet bool
 	return nil, nil
 }
 
+func (c *communicatorImpl) ValidateGenerated(ctx context.Context, data []byte, generated []string, projectID string) (*validator.GeneratedValidationResult, error) {
+	info := requestInfo{
+		method:     http.MethodPost,
+		path:       "validate/generated",
+		retryOn413: true,
+	}
+
+	body := validator.GeneratedValidationInput{
+		ProjectYaml: data,
+		ProjectID:   projectID,
+		Generated:   generated,
+	}
+	resp, err := c.retryRequest(ctx, info, body)
+	if err != nil {
+		return nil, util.RespError(resp, errors.Wrap(err, "validating generated project").Error())
+	}
+	defer resp.Body.Close()
+
+	result := &validator.GeneratedValidationResult{}
+	if err = utility.ReadJSON(resp.Body, result); err != nil {
+		return nil, errors.Wrap(err, "reading JSON response body")
+	}
+	return result, nil
+}
+
 func (c *communicatorImpl) SendPanicReport(ctx context.Context, details *model.PanicReport) error {
 	info := requestInfo{
 		method: http.MethodPost,
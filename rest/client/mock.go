@@ -13,6 +13,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/manifest"
 	"github.com/evergreen-ci/evergreen/rest/model"
 	restmodel "github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/scheduler"
 	"github.com/evergreen-ci/evergreen/validator"
 	"github.com/evergreen-ci/utility"
 	"github.com/pkg/errors"
@@ -46,6 +47,9 @@ type Mock struct {
 
 	ValidateResult validator.ValidationErrors
 	ValidateErr    error
+
+	ValidateGeneratedResult *validator.GeneratedValidationResult
+	ValidateGeneratedErr    error
 }
 
 func (c *Mock) Close() {}
@@ -327,6 +331,10 @@ func (c *Mock) GetDistroByName(context.Context, string) (*model.APIDistro, error
 	return nil, nil
 }
 
+func (c *Mock) GetSchedulerWorkload(context.Context, string, time.Time, time.Time) (*scheduler.SimulatorWorkload, error) {
+	return nil, nil
+}
+
 func (c *Mock) UpdateServiceUser(context.Context, string, string, []string) error {
 	return nil
 }
@@ -361,6 +369,10 @@ func (c *Mock) GetEstimatedGeneratedTasks(ctx context.Context, patchId string, t
 	return 0, nil
 }
 
+func (c *Mock) GetPatchCostEstimate(ctx context.Context, patchId string) (*restmodel.APIPatchCostEstimate, error) {
+	return &restmodel.APIPatchCostEstimate{PatchId: utility.ToStringPtr(patchId)}, nil
+}
+
 func (c *Mock) GetTaskLogs(ctx context.Context, opts GetTaskLogsOptions) (io.ReadCloser, error) {
 	return nil, nil
 }
@@ -377,6 +389,10 @@ func (c *Mock) Validate(ctx context.Context, data []byte, quiet bool, projectID
 	return c.ValidateResult, c.ValidateErr
 }
 
+func (c *Mock) ValidateGenerated(ctx context.Context, data []byte, generated []string, projectID string) (*validator.GeneratedValidationResult, error) {
+	return c.ValidateGeneratedResult, c.ValidateGeneratedErr
+}
+
 func (c *Mock) SendPanicReport(ctx context.Context, details *model.PanicReport) error {
 	return nil
 }
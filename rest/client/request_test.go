@@ -127,3 +127,34 @@ func (s *RequestTestSuite) TestValidateRequestInfo() {
 		s.Error(err)
 	}
 }
+
+func TestGetPatchCostEstimate(t *testing.T) {
+	for tName, tCase := range map[string]struct {
+		status      int
+		body        string
+		expectNil   bool
+		expectError bool
+	}{
+		"ReturnsEstimate":                      {status: http.StatusOK, body: `{"patch_id": "p"}`},
+		"NotConfiguredIsNotAnError":            {status: http.StatusServiceUnavailable, body: `{"message": "finance configuration is not set up"}`, expectNil: true},
+		"HiddenForProjectIsNotAnError":         {status: http.StatusForbidden, body: `{"message": "cost is not available for project 'p'"}`, expectNil: true},
+		"UnexpectedErrorIsReturnedToTheCaller": {status: http.StatusInternalServerError, body: `{"message": "oops"}`, expectNil: true, expectError: true},
+	} {
+		t.Run(tName, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				rw.WriteHeader(tCase.status)
+				_, _ = rw.Write([]byte(tCase.body))
+			}))
+			t.Cleanup(srv.Close)
+
+			c := &communicatorImpl{serverURL: srv.URL, httpClient: srv.Client()}
+			estimate, err := c.GetPatchCostEstimate(t.Context(), "p")
+			if tCase.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tCase.expectNil, estimate == nil)
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/ec2instancereferenceprice"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/task"
+	restModel "github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
+	"github.com/pkg/errors"
+)
+
+// distroPricing is the pricing used to estimate the cost of tasks running on
+// a distro.
+type distroPricing struct {
+	distro   *distro.Distro
+	costData distro.CostData
+}
+
+// EstimatePatchCost predicts the cost of running the given tasks in a patch
+// from each task's expected duration and the pricing of the distro it runs
+// on. If no tasks are given, the tasks currently selected in the patch are
+// estimated.
+func EstimatePatchCost(ctx context.Context, patchID string, variantsTasks []patch.VariantTasks) (*restModel.APIPatchCostEstimate, error) {
+	if err := ValidatePatchID(patchID); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	p, err := patch.FindOneId(ctx, patchID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding patch '%s'", patchID)
+	}
+	if p == nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("patch '%s' not found", patchID),
+		}
+	}
+	if len(variantsTasks) == 0 {
+		variantsTasks = p.VariantsTasks
+	}
+
+	financeConfig := evergreen.CostConfig{}
+	if err = financeConfig.Get(ctx); err != nil {
+		return nil, errors.Wrap(err, "getting finance configuration")
+	}
+	if financeConfig.ShouldHideCost(p.Project) {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusForbidden,
+			Message:    fmt.Sprintf("cost is not available for project '%s'", p.Project),
+		}
+	}
+	if !financeConfig.IsConfigured() {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "finance configuration is not set up",
+		}
+	}
+
+	project, _, err := model.FindAndTranslateProjectForPatch(ctx, evergreen.GetEnvironment().Settings(), p)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding project for patch '%s'", patchID)
+	}
+	pRef, err := model.FindMergedProjectRef(ctx, p.Project, p.Version, false)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding project ref '%s'", p.Project)
+	}
+	if pRef == nil {
+		return nil, errors.Errorf("project ref '%s' not found", p.Project)
+	}
+	aliases, err := distro.NewDistroAliasesLookupTable(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting distro aliases")
+	}
+
+	pricing := map[string]*distroPricing{}
+	getPricing := func(distroID string) (*distroPricing, error) {
+		if dp, ok := pricing[distroID]; ok {
+			return dp, nil
+		}
+		d, err := distro.FindOneId(ctx, distroID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "finding distro '%s'", distroID)
+		}
+		costData, err := ec2instancereferenceprice.CostDataForDistro(ctx, d)
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting pricing for distro '%s'", distroID)
+		}
+		dp := &distroPricing{distro: d, costData: costData}
+		pricing[distroID] = dp
+		return dp, nil
+	}
+
+	estimate := &restModel.APIPatchCostEstimate{PatchId: utility.ToStringPtr(patchID)}
+	for _, vt := range variantsTasks {
+		bv := project.FindBuildVariant(vt.Variant)
+		if bv == nil {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("build variant '%s' not found in project", vt.Variant),
+			}
+		}
+
+		variantEstimate := restModel.APIVariantCostEstimate{BuildVariant: utility.ToStringPtr(vt.Variant)}
+		for _, taskName := range tasksToEstimate(bv, vt) {
+			bvt := project.FindTaskForVariant(taskName, vt.Variant)
+			if bvt == nil {
+				return nil, gimlet.ErrorResponse{
+					StatusCode: http.StatusBadRequest,
+					Message:    fmt.Sprintf("task '%s' not found in build variant '%s'", taskName, vt.Variant),
+				}
+			}
+			variantEstimate.NumTasks++
+
+			runOn := bvt.RunOn
+			if len(runOn) == 0 {
+				runOn = bv.RunOn
+			}
+			runOn = aliases.Expand(runOn)
+			if len(runOn) == 0 {
+				variantEstimate.NumUnpricedTasks++
+				continue
+			}
+			dp, err := getPricing(runOn[0])
+			if err != nil {
+				return nil, err
+			}
+			if dp.distro == nil || !dp.costData.IsConfigured() {
+				variantEstimate.NumUnpricedTasks++
+				continue
+			}
+
+			duration, err := task.GetExpectedDuration(ctx, p.Project, vt.Variant, taskName)
+			if err != nil {
+				return nil, errors.Wrapf(err, "getting expected duration of task '%s' in build variant '%s'", taskName, vt.Variant)
+			}
+			variantEstimate.Cost = variantEstimate.Cost.Add(task.EstimateTaskCost(duration.Seconds(), dp.distro, dp.costData, financeConfig))
+		}
+		estimate.AddVariant(variantEstimate)
+	}
+
+	if pRef.CostBudget.PatchConfirmationThreshold > 0 {
+		estimate.Threshold = utility.ToFloat64Ptr(pRef.CostBudget.PatchConfirmationThreshold)
+		estimate.RequiresConfirmation = pRef.CostBudget.RequiresPatchConfirmation(estimate.Total.AdjustedTotal())
+	}
+
+	return estimate, nil
+}
+
+// tasksToEstimate returns the names of the execution tasks selected in the
+// variant, including the execution tasks of any selected display tasks that
+// do not list them explicitly.
+func tasksToEstimate(bv *model.BuildVariant, vt patch.VariantTasks) []string {
+	tasks := append([]string{}, vt.Tasks...)
+	for _, dt := range vt.DisplayTasks {
+		execTasks := dt.ExecTasks
+		if len(execTasks) == 0 {
+			for _, bvDisplayTask := range bv.DisplayTasks {
+				if bvDisplayTask.Name == dt.Name {
+					execTasks = bvDisplayTask.ExecTasks
+					break
+				}
+			}
+		}
+		for _, et := range execTasks {
+			if !utility.StringSliceContains(tasks, et) {
+				tasks = append(tasks, et)
+			}
+		}
+	}
+	return tasks
+}
@@ -277,6 +277,18 @@ func SaveProjectSettingsForSection(ctx context.Context, projectId string, change
 		if err = mergedSection.ValidateEnabledRepotracker(); err != nil {
 			return nil, err
 		}
+		if err = mergedSection.LogRetention.Validate(); err != nil {
+			return nil, errors.Wrap(err, "validating log retention settings")
+		}
+		if err = mergedSection.CostBudget.Validate(); err != nil {
+			return nil, errors.Wrap(err, "validating cost budget settings")
+		}
+		if err = mergedSection.ValidateFlakyTestQuarantine(); err != nil {
+			return nil, errors.Wrap(err, "validating flaky test quarantine settings")
+		}
+		if err = mergedSection.RepoSource.Validate(); err != nil {
+			return nil, errors.Wrap(err, "validating repository source settings")
+		}
 		// Validate owner/repo if the project is enabled or owner/repo is populated.
 		// This validation is cheap so it makes sense to be strict about this.
 		if mergedSection.Enabled || (mergedSection.Owner != "" && mergedSection.Repo != "") {
@@ -456,6 +468,7 @@ func SaveProjectSettingsForSection(ctx context.Context, projectId string, change
 		if catcher.HasErrors() {
 			return nil, errors.Wrap(catcher.Resolve(), "invalid periodic build definition")
 		}
+		model.PreservePeriodicBuildManualRuns(before.ProjectRef.PeriodicBuilds, newProjectRef.PeriodicBuilds)
 	case model.ProjectPageTriggersSection:
 		if !isRepo { // Check this for project refs only, as repo projects won't have last version information stored.
 			repository, err := model.FindRepository(ctx, projectId)
@@ -11,10 +11,13 @@ import (
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
+	serviceModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/flakytest"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/model/testresult"
 	"github.com/evergreen-ci/evergreen/rest/model"
 	testselection "github.com/evergreen-ci/test-selection-client"
+	"github.com/evergreen-ci/utility"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
 	"github.com/pkg/errors"
@@ -116,8 +119,27 @@ func newTestSelectionClient(c *http.Client) *testselection.APIClient {
 
 // SelectTests uses the test selection service to return a filtered set of tests
 // to run based on the provided SelectTestsRequest. It returns the list of
-// selected tests.
+// selected tests. If the request uses the built-in history strategy, the tests
+// are selected in-process instead. Either way, tests that Evergreen has
+// automatically quarantined for being flaky are not selected for patches.
 func SelectTests(ctx context.Context, req model.SelectTestsRequest) ([]string, error) {
+	var (
+		selectedTests []string
+		err           error
+	)
+	if UsesHistoryStrategy(req) {
+		selectedTests, err = selectTestsByHistory(ctx, req)
+	} else {
+		selectedTests, err = selectTestsWithService(ctx, req)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return removeAutoQuarantinedTests(ctx, req, selectedTests), nil
+}
+
+// selectTestsWithService selects tests using the test selection service.
+func selectTestsWithService(ctx context.Context, req model.SelectTestsRequest) ([]string, error) {
 	ctx, cancel := context.WithTimeout(ctx, testSelectionSelectTimeout)
 	defer cancel()
 	c := newTestSelectionClient(testSelectionHTTPClient)
@@ -185,9 +207,63 @@ func SelectTests(ctx context.Context, req model.SelectTestsRequest) ([]string, e
 	return selectedTests, nil
 }
 
+// findAutoQuarantinedTests returns the names of the requested task's tests
+// that are automatically quarantined for being flaky. Mainline tasks still run
+// quarantined tests so that they can pass enough times to be released. Tests
+// are only quarantined while the project has quarantine enabled, since
+// quarantined tests are no longer scored or released once it is disabled.
+func findAutoQuarantinedTests(ctx context.Context, req model.SelectTestsRequest) (map[string]bool, error) {
+	if utility.StringSliceContains(evergreen.SystemVersionRequesterTypes, req.Requester) {
+		return nil, nil
+	}
+	pRef, err := serviceModel.FindMergedProjectRef(ctx, req.Project, "", false)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding project ref '%s'", req.Project)
+	}
+	if pRef == nil || !pRef.FlakyTestQuarantine.Enabled || !pRef.IsTestSelectionAllowed() {
+		return nil, nil
+	}
+	tests, err := flakytest.FindQuarantinedForTask(ctx, req.Project, req.BuildVariant, req.TaskName)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding automatically quarantined tests")
+	}
+	quarantined := make(map[string]bool, len(tests))
+	for _, t := range tests {
+		quarantined[t.TestName] = true
+	}
+	return quarantined, nil
+}
+
+// removeAutoQuarantinedTests removes the automatically quarantined tests from
+// the selected tests. Since this is best effort, the selected tests are
+// returned unchanged if the quarantined tests can't be found.
+func removeAutoQuarantinedTests(ctx context.Context, req model.SelectTestsRequest, selectedTests []string) []string {
+	quarantined, err := findAutoQuarantinedTests(ctx, req)
+	if err != nil {
+		grip.Error(ctx, message.WrapError(err, message.Fields{
+			"message":       "error removing automatically quarantined tests from selected tests",
+			"project_id":    req.Project,
+			"build_variant": req.BuildVariant,
+			"task_id":       req.TaskID,
+			"task_name":     req.TaskName,
+		}))
+		return selectedTests
+	}
+	if len(quarantined) == 0 {
+		return selectedTests
+	}
+	kept := make([]string, 0, len(selectedTests))
+	for _, name := range selectedTests {
+		if !quarantined[name] {
+			kept = append(kept, name)
+		}
+	}
+	return kept
+}
+
 // RecordQuarantinedTestsSkipped snapshots the tests that test selection
-// skipped because they are quarantined in TSS, appending them to the task
-// run's test_results record.
+// skipped because they are quarantined, either in TSS or automatically by
+// Evergreen, appending them to the task run's test_results record.
 func RecordQuarantinedTestsSkipped(ctx context.Context, env evergreen.Environment, req model.SelectTestsRequest, selectedTests []string) error {
 	quarantinedTests, err := findQuarantinedSkippedTests(ctx, req, selectedTests)
 	if err != nil {
@@ -225,35 +301,53 @@ func RecordQuarantinedTestsSkipped(ctx context.Context, env evergreen.Environmen
 }
 
 // findQuarantinedSkippedTests returns the tests that test selection skipped
-// specifically because they are quarantined in TSS. When the request names
-// its tests, only the skipped subset is checked for quarantine status; when
-// it does not (the select-known-tests path), the full test set is unknown, so
-// the variant's quarantine state determines which known tests were withheld.
+// specifically because they are quarantined, either in TSS or automatically by
+// Evergreen. When the request names its tests, only the skipped subset is
+// checked for quarantine status; when it does not (the select-known-tests
+// path), the full test set is unknown, so the variant's quarantine state
+// determines which known tests were withheld.
 func findQuarantinedSkippedTests(ctx context.Context, req model.SelectTestsRequest, selectedTests []string) ([]testresult.QuarantinedTest, error) {
 	selected := make(map[string]bool, len(selectedTests))
 	for _, name := range selectedTests {
 		selected[name] = true
 	}
 
+	autoQuarantined, err := findAutoQuarantinedTests(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
 	var quarantinedNames []string
-	if len(req.Tests) > 0 {
-		var skipped []string
+	if UsesHistoryStrategy(req) {
+		// The built-in history strategy doesn't use TSS, so only tests
+		// quarantined by Evergreen could have been skipped.
+		for name := range autoQuarantined {
+			if !selected[name] {
+				quarantinedNames = append(quarantinedNames, name)
+			}
+		}
+		sort.Strings(quarantinedNames)
+	} else if len(req.Tests) > 0 {
+		var skipped, skippedNotAutoQuarantined []string
 		seen := make(map[string]bool, len(req.Tests))
 		for _, name := range req.Tests {
 			if !selected[name] && !seen[name] {
 				skipped = append(skipped, name)
 				seen[name] = true
+				if !autoQuarantined[name] {
+					skippedNotAutoQuarantined = append(skippedNotAutoQuarantined, name)
+				}
 			}
 		}
 		if len(skipped) == 0 {
 			return nil, nil
 		}
-		statuses, err := GetTestsQuarantineStatus(ctx, req.Project, req.BuildVariant, req.TaskName, skipped)
+		statuses, err := GetTestsQuarantineStatus(ctx, req.Project, req.BuildVariant, req.TaskName, skippedNotAutoQuarantined)
 		if err != nil {
 			return nil, errors.Wrap(err, "getting quarantine status for skipped tests")
 		}
 		for _, name := range skipped {
-			if statuses[name] {
+			if autoQuarantined[name] || statuses[name] {
 				quarantinedNames = append(quarantinedNames, name)
 			}
 		}
@@ -262,8 +356,13 @@ func findQuarantinedSkippedTests(ctx context.Context, req model.SelectTestsReque
 		if err != nil {
 			return nil, errors.Wrap(err, "getting variant quarantine status")
 		}
+		for name := range autoQuarantined {
+			if !selected[name] {
+				quarantinedNames = append(quarantinedNames, name)
+			}
+		}
 		for name, isQuarantined := range variantState[req.TaskName] {
-			if isQuarantined && !selected[name] {
+			if isQuarantined && !selected[name] && !autoQuarantined[name] {
 				quarantinedNames = append(quarantinedNames, name)
 			}
 		}
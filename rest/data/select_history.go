@@ -0,0 +1,317 @@
+package data
+
+import (
+	"context"
+	"math"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/utility"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"golang.org/x/sync/errgroup"
+)
+
+// HistoryStrategy is the name of the built-in test selection strategy. Unlike
+// the other strategies, it is evaluated by Evergreen itself using the test
+// results of the task's recent mainline runs, so it does not require the test
+// selection service.
+const HistoryStrategy = "evergreen_history"
+
+const (
+	// historyMaxTasks is the number of recent mainline runs of the task
+	// whose test results are considered.
+	historyMaxTasks = 20
+	// historyMinRuns is the number of runs a test must have in its history
+	// before it can be pruned. Tests with less history than this are always
+	// selected.
+	historyMinRuns = 3
+	// historyRecencyHalfLife is how long it takes for the weight of a test's
+	// most recent failure to halve.
+	historyRecencyHalfLife = 7 * 24 * time.Hour
+	// historyMinStemLength is the shortest file stem that can relate a test
+	// to a changed file, since short stems like "io" or "db" are part of
+	// too many unrelated names.
+	historyMinStemLength = 3
+
+	historyFailureRateWeight  = 0.4
+	historyFlakinessWeight    = 0.2
+	historyRecencyWeight      = 0.2
+	historyChangedFilesWeight = 0.2
+)
+
+// UsesHistoryStrategy returns whether the request asks for the built-in
+// history strategy.
+func UsesHistoryStrategy(req model.SelectTestsRequest) bool {
+	return slices.Contains(req.Strategies, HistoryStrategy)
+}
+
+// taskTestHistory is the outcome of each test in a single past task run.
+type taskTestHistory struct {
+	finishTime time.Time
+	statuses   map[string]string
+}
+
+// testScore is the history-based score of a single test.
+type testScore struct {
+	name  string
+	score float64
+	// keep is whether the test should be selected regardless of its score.
+	keep bool
+}
+
+// selectTestsByHistory selects tests in-process based on the test results of
+// the task's recent mainline runs. Tests that have recently failed, are
+// flaky, or relate to the files changed in the patch are ranked first, and
+// tests that have a stable passing history and no relation to the change are
+// pruned.
+func selectTestsByHistory(ctx context.Context, req model.SelectTestsRequest) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, testSelectionSelectTimeout)
+	defer cancel()
+
+	history, err := getTaskTestHistory(ctx, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting task test history")
+	}
+	changedFiles, err := getChangedFiles(ctx, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting changed files")
+	}
+
+	candidates := req.Tests
+	if len(candidates) == 0 {
+		candidates = knownTests(history)
+	}
+
+	var selected []string
+	for _, s := range scoreTestsByHistory(candidates, history, changedFiles, time.Now()) {
+		if s.keep || s.score > 0 {
+			selected = append(selected, s.name)
+		}
+	}
+	return selected, nil
+}
+
+// getTaskTestHistory returns the test outcomes of the most recent completed
+// mainline runs of the requested task, ordered from oldest to newest.
+func getTaskTestHistory(ctx context.Context, req model.SelectTestsRequest) ([]taskTestHistory, error) {
+	query := db.Query(bson.M{
+		task.ProjectKey:      req.Project,
+		task.BuildVariantKey: req.BuildVariant,
+		task.DisplayNameKey:  req.TaskName,
+		task.RequesterKey:    bson.M{"$in": evergreen.SystemVersionRequesterTypes},
+		task.StatusKey:       bson.M{"$in": evergreen.TaskCompletedStatuses},
+		task.IdKey:           bson.M{"$ne": req.TaskID},
+	}).Sort([]string{"-" + task.FinishTimeKey}).Limit(historyMaxTasks)
+	tasks, err := task.FindAll(ctx, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding recent mainline tasks")
+	}
+
+	env := evergreen.GetEnvironment()
+	history := make([]taskTestHistory, len(tasks))
+	eg, ctx := errgroup.WithContext(ctx)
+	for i := range tasks {
+		// Tasks are sorted newest first, so fill the history in reverse.
+		idx := len(tasks) - 1 - i
+		t := tasks[i]
+		eg.Go(func() error {
+			results, err := t.GetTestResults(ctx, env, nil)
+			if err != nil {
+				return errors.Wrapf(err, "getting test results for task '%s'", t.Id)
+			}
+			statuses := make(map[string]string, len(results.Results))
+			for _, r := range results.Results {
+				statuses[r.GetDisplayTestName()] = r.Status
+			}
+			history[idx] = taskTestHistory{finishTime: t.FinishTime, statuses: statuses}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// getChangedFiles returns the files modified by the patch that the requested
+// task belongs to. Mainline tasks have no changed files.
+func getChangedFiles(ctx context.Context, req model.SelectTestsRequest) ([]string, error) {
+	if !evergreen.IsPatchRequester(req.Requester) {
+		return nil, nil
+	}
+	t, err := task.FindOneIdWithFields(ctx, req.TaskID, task.VersionKey)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding task '%s'", req.TaskID)
+	}
+	if t == nil {
+		return nil, nil
+	}
+	p, err := patch.FindOneId(ctx, t.Version)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding patch '%s'", t.Version)
+	}
+	if p == nil {
+		return nil, nil
+	}
+	return p.FilesChanged(), nil
+}
+
+// knownTests returns the names of all the tests in the history.
+func knownTests(history []taskTestHistory) []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, h := range history {
+		for name := range h.statuses {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// scoreTestsByHistory scores each candidate test from its recent failure
+// rate, how often its status flipped between runs, the time since it last
+// failed and whether it relates to one of the changed files. The scores are
+// returned from highest to lowest. Tests without enough history are always
+// kept and ranked first.
+func scoreTestsByHistory(candidates []string, history []taskTestHistory, changedFiles []string, now time.Time) []testScore {
+	scores := make([]testScore, 0, len(candidates))
+	seen := make(map[string]bool, len(candidates))
+	for _, name := range candidates {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		var (
+			runs, failures, flips int
+			lastStatus            string
+			lastFailure           time.Time
+		)
+		for _, h := range history {
+			status, ok := h.statuses[name]
+			if !ok {
+				continue
+			}
+			runs++
+			failed := isFailedTestStatus(status)
+			if failed {
+				failures++
+				lastFailure = h.finishTime
+			}
+			if lastStatus != "" && isFailedTestStatus(lastStatus) != failed {
+				flips++
+			}
+			lastStatus = status
+		}
+
+		s := testScore{name: name, keep: runs < historyMinRuns}
+		if runs > 0 {
+			s.score += historyFailureRateWeight * float64(failures) / float64(runs)
+		}
+		if runs > 1 {
+			s.score += historyFlakinessWeight * float64(flips) / float64(runs-1)
+		}
+		if !utility.IsZeroTime(lastFailure) {
+			age := now.Sub(lastFailure)
+			if age < 0 {
+				age = 0
+			}
+			s.score += historyRecencyWeight * math.Pow(0.5, float64(age)/float64(historyRecencyHalfLife))
+		}
+		if relatesToChangedFiles(name, changedFiles) {
+			s.score += historyChangedFilesWeight
+		}
+		scores = append(scores, s)
+	}
+
+	sort.SliceStable(scores, func(i, j int) bool {
+		if scores[i].keep != scores[j].keep {
+			return scores[i].keep
+		}
+		return scores[i].score > scores[j].score
+	})
+	return scores
+}
+
+func isFailedTestStatus(status string) bool {
+	return status == evergreen.TestFailedStatus || status == evergreen.TestSilentlyFailedStatus
+}
+
+// relatesToChangedFiles returns whether the test name and one of the changed
+// files share a file stem (e.g. "test_foo" and "src/foo.py" both contain
+// "foo"). One stem must contain the other as whole words, so "io" does not
+// relate to "audio".
+func relatesToChangedFiles(testName string, changedFiles []string) bool {
+	testStem := fileStem(testName)
+	if len(testStem) < historyMinStemLength {
+		return false
+	}
+	for _, f := range changedFiles {
+		changedStem := fileStem(f)
+		if len(changedStem) < historyMinStemLength {
+			continue
+		}
+		if containsWords(testStem, changedStem) || containsWords(changedStem, testStem) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsWords returns whether sub occurs in s bounded on both sides by the
+// start or end of s or by a non-alphanumeric character.
+func containsWords(s, sub string) bool {
+	isWordChar := func(b byte) bool {
+		return b >= 'a' && b <= 'z' || b >= '0' && b <= '9'
+	}
+	for offset := 0; ; {
+		i := strings.Index(s[offset:], sub)
+		if i < 0 {
+			return false
+		}
+		start, end := offset+i, offset+i+len(sub)
+		if (start == 0 || !isWordChar(s[start-1])) && (end == len(s) || !isWordChar(s[end])) {
+			return true
+		}
+		offset = start + 1
+	}
+}
+
+// fileStem returns the lowercased base name of the path without its
+// extension and without test prefixes and suffixes. Only affixes delimited by
+// a separator or, for CamelCase names, by a change of case are removed so that
+// names like "contest" are kept whole.
+func fileStem(p string) string {
+	stem := path.Base(strings.ReplaceAll(p, "\\", "/"))
+	stem = strings.TrimSuffix(stem, path.Ext(stem))
+	for _, affix := range []string{"Test", "Spec"} {
+		if rest, ok := strings.CutPrefix(stem, affix); ok && rest != "" && unicode.IsUpper(rune(rest[0])) {
+			stem = rest
+		}
+		if rest, ok := strings.CutSuffix(stem, affix); ok && rest != "" && !unicode.IsUpper(rune(rest[len(rest)-1])) {
+			stem = rest
+		}
+	}
+	stem = strings.ToLower(stem)
+	for _, affix := range []string{"test_", "spec_"} {
+		stem = strings.TrimPrefix(stem, affix)
+	}
+	for _, affix := range []string{"_test", ".test", "_spec", ".spec"} {
+		stem = strings.TrimSuffix(stem, affix)
+	}
+	return strings.Trim(stem, "_-.")
+}
@@ -0,0 +1,139 @@
+package data
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsesHistoryStrategy(t *testing.T) {
+	assert.True(t, UsesHistoryStrategy(model.SelectTestsRequest{Strategies: []string{HistoryStrategy}}))
+	assert.False(t, UsesHistoryStrategy(model.SelectTestsRequest{Strategies: []string{"other_strategy"}}))
+	assert.False(t, UsesHistoryStrategy(model.SelectTestsRequest{}))
+}
+
+func TestScoreTestsByHistory(t *testing.T) {
+	now := time.Now()
+	makeHistory := func(statuses ...map[string]string) []taskTestHistory {
+		history := make([]taskTestHistory, 0, len(statuses))
+		for i, s := range statuses {
+			history = append(history, taskTestHistory{
+				finishTime: now.Add(-time.Duration(len(statuses)-i) * 24 * time.Hour),
+				statuses:   s,
+			})
+		}
+		return history
+	}
+	pass := evergreen.TestSucceededStatus
+	fail := evergreen.TestFailedStatus
+
+	t.Run("StablePassingTestIsPruned", func(t *testing.T) {
+		history := makeHistory(
+			map[string]string{"stable": pass},
+			map[string]string{"stable": pass},
+			map[string]string{"stable": pass},
+		)
+		scores := scoreTestsByHistory([]string{"stable"}, history, nil, now)
+		require.Len(t, scores, 1)
+		assert.False(t, scores[0].keep)
+		assert.Zero(t, scores[0].score)
+	})
+	t.Run("TestWithoutEnoughHistoryIsKept", func(t *testing.T) {
+		history := makeHistory(
+			map[string]string{"stable": pass},
+			map[string]string{"stable": pass, "new": pass},
+			map[string]string{"stable": pass, "new": pass},
+		)
+		scores := scoreTestsByHistory([]string{"stable", "new", "unknown"}, history, nil, now)
+		require.Len(t, scores, 3)
+		assert.Equal(t, "new", scores[0].name)
+		assert.True(t, scores[0].keep)
+		assert.Equal(t, "unknown", scores[1].name)
+		assert.True(t, scores[1].keep)
+		assert.Equal(t, "stable", scores[2].name)
+		assert.False(t, scores[2].keep)
+	})
+	t.Run("FailingTestsAreRankedByFailureRateAndRecency", func(t *testing.T) {
+		history := makeHistory(
+			map[string]string{"old_failure": fail, "frequent_failure": fail, "recent_failure": pass},
+			map[string]string{"old_failure": pass, "frequent_failure": fail, "recent_failure": pass},
+			map[string]string{"old_failure": pass, "frequent_failure": fail, "recent_failure": fail},
+		)
+		scores := scoreTestsByHistory([]string{"old_failure", "recent_failure", "frequent_failure"}, history, nil, now)
+		require.Len(t, scores, 3)
+		assert.Equal(t, "frequent_failure", scores[0].name)
+		assert.Equal(t, "recent_failure", scores[1].name)
+		assert.Equal(t, "old_failure", scores[2].name)
+		for _, s := range scores {
+			assert.Positive(t, s.score)
+		}
+	})
+	t.Run("FlakyTestRanksAboveConsistentFailureWithSameRate", func(t *testing.T) {
+		history := makeHistory(
+			map[string]string{"flaky": fail, "broken": pass},
+			map[string]string{"flaky": pass, "broken": pass},
+			map[string]string{"flaky": fail, "broken": fail},
+			map[string]string{"flaky": pass, "broken": fail},
+		)
+		scores := scoreTestsByHistory([]string{"broken", "flaky"}, history, nil, now.Add(-2*24*time.Hour))
+		require.Len(t, scores, 2)
+		assert.Equal(t, "flaky", scores[0].name)
+	})
+	t.Run("ChangedFileOverlapSelectsStableTest", func(t *testing.T) {
+		history := makeHistory(
+			map[string]string{"test_parser.py": pass, "test_lexer.py": pass},
+			map[string]string{"test_parser.py": pass, "test_lexer.py": pass},
+			map[string]string{"test_parser.py": pass, "test_lexer.py": pass},
+		)
+		scores := scoreTestsByHistory([]string{"test_lexer.py", "test_parser.py"}, history, []string{"src/parser.py"}, now)
+		require.Len(t, scores, 2)
+		assert.Equal(t, "test_parser.py", scores[0].name)
+		assert.Positive(t, scores[0].score)
+		assert.Zero(t, scores[1].score)
+	})
+	t.Run("DuplicateCandidatesAreScoredOnce", func(t *testing.T) {
+		scores := scoreTestsByHistory([]string{"test", "test"}, nil, nil, now)
+		assert.Len(t, scores, 1)
+	})
+}
+
+func TestRelatesToChangedFiles(t *testing.T) {
+	assert.True(t, relatesToChangedFiles("rest/data/select_test.go", []string{"rest/data/select.go"}))
+	assert.True(t, relatesToChangedFiles("TestParser", []string{"parser.go"}))
+	assert.True(t, relatesToChangedFiles("jstests/core/find_and_modify.js", []string{"src/mongo/db/find_and_modify.cpp"}))
+	assert.False(t, relatesToChangedFiles("test_lexer.py", []string{"src/parser.py"}))
+	assert.False(t, relatesToChangedFiles("test_io.py", []string{"a.py"}), "very short file stems should not match")
+	assert.False(t, relatesToChangedFiles("test_parser.py", nil))
+	assert.True(t, relatesToChangedFiles("test_user.py", []string{"src/user_service.py"}))
+	assert.False(t, relatesToChangedFiles("test_io.py", []string{"src/audio.py"}), "very short test stems should not match")
+	assert.False(t, relatesToChangedFiles("TestDB", []string{"model/dbuser.go"}), "very short test stems should not match")
+	assert.False(t, relatesToChangedFiles("test_user.py", []string{"src/superuser.py"}), "stems should only match as whole words")
+}
+
+func TestFileStem(t *testing.T) {
+	for name, expected := range map[string]string{
+		"rest/data/select_test.go":   "select",
+		"tests/test_parser.py":       "parser",
+		"src/parser.spec.ts":         "parser",
+		"spec/user_spec.rb":          "user",
+		"TestParser":                 "parser",
+		"ParserTest.java":            "parser",
+		"src/contest.py":             "contest",
+		"src/latest_spectrum.cpp":    "latest_spectrum",
+		"jstests\\core\\testdata.js": "testdata",
+	} {
+		assert.Equal(t, expected, fileStem(name), name)
+	}
+}
+
+func TestKnownTests(t *testing.T) {
+	history := []taskTestHistory{
+		{statuses: map[string]string{"b": evergreen.TestSucceededStatus, "a": evergreen.TestFailedStatus}},
+		{statuses: map[string]string{"c": evergreen.TestSucceededStatus, "a": evergreen.TestSucceededStatus}},
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, knownTests(history))
+}
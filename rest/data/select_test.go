@@ -11,10 +11,13 @@ import (
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
+	serviceModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/flakytest"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/model/testresult"
 	"github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/evergreen-ci/utility"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/level"
 	"github.com/mongodb/grip/message"
@@ -29,6 +32,21 @@ func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
 	return f(req)
 }
 
+// insertQuarantineProjectRef inserts a project that allows test selection and
+// has automatic flaky test quarantine enabled or disabled.
+func insertQuarantineProjectRef(t *testing.T, projectID string, enabled bool) {
+	require.NoError(t, db.ClearCollections(serviceModel.ProjectRefCollection))
+	t.Cleanup(func() {
+		assert.NoError(t, db.ClearCollections(serviceModel.ProjectRefCollection))
+	})
+	pRef := serviceModel.ProjectRef{
+		Id:                  projectID,
+		TestSelection:       serviceModel.TestSelectionSettings{Allowed: utility.TruePtr()},
+		FlakyTestQuarantine: serviceModel.FlakyTestQuarantineSettings{Enabled: enabled},
+	}
+	require.NoError(t, pRef.Insert(t.Context()))
+}
+
 func captureGripMessages(t *testing.T) *send.InternalSender {
 	originalSender := grip.GetSender()
 	sender := send.MakeInternalLogger()
@@ -874,4 +892,120 @@ func TestRecordQuarantinedTestsSkipped(t *testing.T) {
 		assert.Equal(t, 2, samples[0].QuarantinedTestsSkippedCount)
 		assert.Len(t, samples[0].QuarantinedTests, 2)
 	})
+
+	setupAutoQuarantine := func(t *testing.T, enabled bool) {
+		insertQuarantineProjectRef(t, projectID, enabled)
+		require.NoError(t, db.ClearCollections(flakytest.Collection))
+		t.Cleanup(func() {
+			assert.NoError(t, db.ClearCollections(flakytest.Collection))
+		})
+		for _, f := range []flakytest.TestFlakiness{
+			{ID: "f0", ProjectID: projectID, BuildVariant: bvName, TaskName: taskName, TestName: "test0", Quarantined: true},
+			{ID: "f1", ProjectID: projectID, BuildVariant: bvName, TaskName: taskName, TestName: "test1", Quarantined: true},
+			{ID: "f2", ProjectID: projectID, BuildVariant: bvName, TaskName: taskName, TestName: "test2"},
+		} {
+			require.NoError(t, f.Replace(ctx))
+		}
+	}
+
+	t.Run("HistoryStrategyRecordsAutoQuarantinedTests", func(t *testing.T) {
+		setupTask(t)
+		setupAutoQuarantine(t, true)
+		hits := newTSSServer(t, nil, nil)
+
+		req := baseReq
+		req.Strategies = []string{HistoryStrategy}
+		require.NoError(t, RecordQuarantinedTestsSkipped(ctx, env, req, []string{"test2"}))
+
+		assert.Zero(t, *hits, "the test selection service should not be used with the built-in history strategy")
+		record := findRecord(t)
+		assert.Equal(t, []testresult.QuarantinedTest{{TestName: "test0"}, {TestName: "test1"}}, record.QuarantinedTests)
+		assert.Equal(t, 2, findTask(t).NumQuarantinedTestsSkipped)
+	})
+
+	t.Run("NamedTestsPathRecordsAutoQuarantinedTests", func(t *testing.T) {
+		setupTask(t)
+		setupAutoQuarantine(t, true)
+		newTSSServer(t, map[string]map[string]any{
+			"test2": {"state": "stable"},
+			"test3": {"state": "manually_quarantined"},
+		}, nil)
+
+		req := baseReq
+		req.Tests = []string{"test0", "test1", "test2", "test3"}
+		require.NoError(t, RecordQuarantinedTestsSkipped(ctx, env, req, []string{"test1"}))
+
+		record := findRecord(t)
+		assert.Equal(t, []testresult.QuarantinedTest{{TestName: "test0"}, {TestName: "test3"}}, record.QuarantinedTests)
+		assert.Equal(t, 2, findTask(t).NumQuarantinedTestsSkipped)
+	})
+
+	t.Run("KnownTestsPathRecordsAutoQuarantinedTests", func(t *testing.T) {
+		setupTask(t)
+		setupAutoQuarantine(t, true)
+		newTSSServer(t, nil, map[string]map[string]any{
+			taskName: {
+				"task_name": taskName,
+				"test_stats": map[string]any{
+					"test0": map[string]any{"state": "manually_quarantined"},
+					"test3": map[string]any{"state": "manually_quarantined"},
+				},
+			},
+		})
+
+		require.NoError(t, RecordQuarantinedTestsSkipped(ctx, env, baseReq, []string{"test2"}))
+
+		record := findRecord(t)
+		assert.Equal(t, []testresult.QuarantinedTest{{TestName: "test0"}, {TestName: "test1"}, {TestName: "test3"}}, record.QuarantinedTests)
+		assert.Equal(t, 3, findTask(t).NumQuarantinedTestsSkipped)
+	})
+
+	t.Run("DisabledQuarantineRecordsNoAutoQuarantinedTests", func(t *testing.T) {
+		setupTask(t)
+		setupAutoQuarantine(t, false)
+		newTSSServer(t, nil, nil)
+
+		req := baseReq
+		req.Strategies = []string{HistoryStrategy}
+		require.NoError(t, RecordQuarantinedTestsSkipped(ctx, env, req, []string{"test2"}))
+
+		assert.Zero(t, countRecords(t))
+		assert.Zero(t, findTask(t).NumQuarantinedTestsSkipped)
+	})
+}
+
+func TestRemoveAutoQuarantinedTests(t *testing.T) {
+	ctx := t.Context()
+	insertQuarantineProjectRef(t, "project", true)
+	require.NoError(t, db.ClearCollections(flakytest.Collection))
+	t.Cleanup(func() {
+		assert.NoError(t, db.ClearCollections(flakytest.Collection))
+	})
+	for _, f := range []flakytest.TestFlakiness{
+		{ID: "f0", ProjectID: "project", BuildVariant: "bv", TaskName: "task", TestName: "test0", Quarantined: true},
+		{ID: "f1", ProjectID: "project", BuildVariant: "bv", TaskName: "task", TestName: "test1"},
+		{ID: "f2", ProjectID: "project", BuildVariant: "other_bv", TaskName: "task", TestName: "test1", Quarantined: true},
+	} {
+		require.NoError(t, f.Replace(ctx))
+	}
+	req := model.SelectTestsRequest{
+		Project:      "project",
+		Requester:    evergreen.PatchVersionRequester,
+		BuildVariant: "bv",
+		TaskName:     "task",
+	}
+	selected := []string{"test0", "test1", "test2"}
+
+	t.Run("RemovesQuarantinedTestsFromPatches", func(t *testing.T) {
+		assert.Equal(t, []string{"test1", "test2"}, removeAutoQuarantinedTests(ctx, req, selected))
+	})
+	t.Run("KeepsQuarantinedTestsInMainline", func(t *testing.T) {
+		mainlineReq := req
+		mainlineReq.Requester = evergreen.RepotrackerVersionRequester
+		assert.Equal(t, selected, removeAutoQuarantinedTests(ctx, mainlineReq, selected), "mainline tasks should keep running quarantined tests so they can be released")
+	})
+	t.Run("KeepsQuarantinedTestsWhenQuarantineIsDisabled", func(t *testing.T) {
+		insertQuarantineProjectRef(t, "project", false)
+		assert.Equal(t, selected, removeAutoQuarantinedTests(ctx, req, selected), "tests should not stay quarantined after the project disables quarantine")
+	})
 }
@@ -133,6 +133,7 @@ const (
 	GithubPreferredType = "github"
 	MultiPreferredType  = "multi"
 	KanopyPreferredType = "kanopy"
+	OIDCPreferredType   = "oidc"
 )
 
 // BuildFromService builds a model from the service layer
@@ -607,6 +608,7 @@ type APIAuthConfig struct {
 	Multi                   *APIMultiAuthConfig  `json:"multi"`
 	Kanopy                  *APIKanopyAuthConfig `json:"kanopy"`
 	OAuth                   *APIOAuthConfig      `json:"oauth"`
+	OIDC                    *APIOIDCConfig       `json:"oidc"`
 	PreferredType           *string              `json:"preferred_type"`
 	BackgroundReauthMinutes int                  `json:"background_reauth_minutes"`
 	AllowServiceUsers       bool                 `json:"allow_service_users"`
@@ -651,6 +653,12 @@ func (a *APIAuthConfig) BuildFromService(h any) error {
 				return errors.Wrap(err, "converting OAuth settings to API model")
 			}
 		}
+		if v.OIDC != nil {
+			a.OIDC = &APIOIDCConfig{}
+			if err := a.OIDC.BuildFromService(v.OIDC); err != nil {
+				return errors.Wrap(err, "converting OIDC auth settings to API model")
+			}
+		}
 		a.PreferredType = utility.ToStringPtr(v.PreferredType)
 		a.BackgroundReauthMinutes = v.BackgroundReauthMinutes
 		a.AllowServiceUsers = v.AllowServiceUsers
@@ -667,6 +675,7 @@ func (a *APIAuthConfig) ToService() (any, error) {
 	var multi *evergreen.MultiAuthConfig
 	var kanopy *evergreen.KanopyAuthConfig
 	var oauth *evergreen.OAuthConfig
+	var oidc *evergreen.OIDCConfig
 	var ok bool
 
 	i, err := a.Okta.ToService()
@@ -733,6 +742,16 @@ func (a *APIAuthConfig) ToService() (any, error) {
 			return nil, errors.Errorf("programmatic error: expected OAuth config but got type %T", i)
 		}
 	}
+	i, err = a.OIDC.ToService()
+	if err != nil {
+		return nil, errors.Wrap(err, "converting OIDC auth config to service model")
+	}
+	if i != nil {
+		oidc, ok = i.(*evergreen.OIDCConfig)
+		if !ok {
+			return nil, errors.Errorf("programmatic error: expected OIDC auth config but got type %T", i)
+		}
+	}
 
 	return evergreen.AuthConfig{
 		Okta:                    okta,
@@ -741,6 +760,7 @@ func (a *APIAuthConfig) ToService() (any, error) {
 		Multi:                   multi,
 		Kanopy:                  kanopy,
 		OAuth:                   oauth,
+		OIDC:                    oidc,
 		PreferredType:           utility.FromStringPtr(a.PreferredType),
 		BackgroundReauthMinutes: a.BackgroundReauthMinutes,
 		AllowServiceUsers:       a.AllowServiceUsers,
@@ -786,11 +806,39 @@ type APIBucketsConfig struct {
 	LongRetentionProjects          []string        `json:"long_retention_projects"`
 	RetryFailedLogMoveLookbackDays *int            `json:"retry_failed_log_move_lookback_days,omitempty"`
 	// Kept for Spruce backward compatibility.
-	RetryFailedLogMoveLookbackMonths *int             `json:"retry_failed_log_move_lookback_months,omitempty"`
-	RetryFailedLogMoveMaxJobsPerRun  *int             `json:"retry_failed_log_move_max_jobs_per_run,omitempty"`
-	TestResultsBucket                APIBucketConfig  `json:"test_results_bucket"`
-	InternalBuckets                  []string         `json:"internal_buckets"`
-	Credentials                      APIS3Credentials `json:"credentials"`
+	RetryFailedLogMoveLookbackMonths *int                  `json:"retry_failed_log_move_lookback_months,omitempty"`
+	RetryFailedLogMoveMaxJobsPerRun  *int                  `json:"retry_failed_log_move_max_jobs_per_run,omitempty"`
+	TestResultsBucket                APIBucketConfig       `json:"test_results_bucket"`
+	InternalBuckets                  []string              `json:"internal_buckets"`
+	Credentials                      APIS3Credentials      `json:"credentials"`
+	CacheStores                      []APICacheStoreConfig `json:"cache_stores"`
+	CacheManifestTTLDays             *int                  `json:"cache_manifest_ttl_days,omitempty"`
+}
+
+type APICacheStoreConfig struct {
+	Bucket     *string `json:"bucket"`
+	Prefix     *string `json:"prefix"`
+	Region     *string `json:"region"`
+	RoleARN    *string `json:"role_arn"`
+	ExternalID *string `json:"external_id,omitempty"`
+}
+
+func (a *APICacheStoreConfig) BuildFromService(v evergreen.CacheStoreConfig) {
+	a.Bucket = utility.ToStringPtr(v.Bucket)
+	a.Prefix = utility.ToStringPtr(v.Prefix)
+	a.Region = utility.ToStringPtr(v.Region)
+	a.RoleARN = utility.ToStringPtr(v.RoleARN)
+	a.ExternalID = utility.ToStringPtr(v.ExternalID)
+}
+
+func (a *APICacheStoreConfig) ToService() evergreen.CacheStoreConfig {
+	return evergreen.CacheStoreConfig{
+		Bucket:     utility.FromStringPtr(a.Bucket),
+		Prefix:     utility.FromStringPtr(a.Prefix),
+		Region:     utility.FromStringPtr(a.Region),
+		RoleARN:    utility.FromStringPtr(a.RoleARN),
+		ExternalID: utility.FromStringPtr(a.ExternalID),
+	}
 }
 
 type APIBucketConfig struct {
@@ -876,6 +924,14 @@ func (a *APIBucketsConfig) BuildFromService(h any) error {
 			return errors.Wrap(err, "converting S3 credentials to API model")
 		}
 		a.Credentials = creds
+
+		a.CacheStores = nil
+		for _, store := range v.CacheStores {
+			apiStore := APICacheStoreConfig{}
+			apiStore.BuildFromService(store)
+			a.CacheStores = append(a.CacheStores, apiStore)
+		}
+		a.CacheManifestTTLDays = utility.ToIntPtr(v.CacheManifestTTLDays)
 	default:
 		return errors.Errorf("programmatic error: expected bucket config but got type %T", h)
 	}
@@ -898,6 +954,11 @@ func (a *APIBucketsConfig) ToService() (any, error) {
 		lookbackDays = a.RetryFailedLogMoveLookbackMonths
 	}
 
+	var cacheStores []evergreen.CacheStoreConfig
+	for _, store := range a.CacheStores {
+		cacheStores = append(cacheStores, store.ToService())
+	}
+
 	return evergreen.BucketsConfig{
 		LogBucket:                       a.LogBucket.ToService(),
 		LogBucketLongRetention:          a.LogBucketLongRetention.ToService(),
@@ -907,6 +968,8 @@ func (a *APIBucketsConfig) ToService() (any, error) {
 		RetryFailedLogMoveMaxJobsPerRun: utility.FromIntPtr(a.RetryFailedLogMoveMaxJobsPerRun),
 		TestResultsBucket:               a.TestResultsBucket.ToService(),
 		Credentials:                     creds,
+		CacheStores:                     cacheStores,
+		CacheManifestTTLDays:            utility.FromIntPtr(a.CacheManifestTTLDays),
 	}, nil
 }
 
@@ -1193,6 +1256,77 @@ func (a *APIOAuthConfig) ToService() (any, error) {
 	}, nil
 }
 
+type APIOIDCConfig struct {
+	Issuer               *string              `json:"issuer"`
+	ClientID             *string              `json:"client_id"`
+	ClientSecret         *string              `json:"client_secret"`
+	Scopes               []string             `json:"scopes"`
+	UsernameClaim        *string              `json:"username_claim"`
+	GroupsClaim          *string              `json:"groups_claim"`
+	UserGroup            *string              `json:"user_group"`
+	RoleMappings         []APIOIDCRoleMapping `json:"role_mappings"`
+	ExpireAfterMinutes   int                  `json:"expire_after_minutes"`
+	ExpectedEmailDomains []string             `json:"expected_email_domains"`
+}
+
+type APIOIDCRoleMapping struct {
+	Group *string  `json:"group"`
+	Roles []string `json:"roles"`
+}
+
+func (a *APIOIDCConfig) BuildFromService(h any) error {
+	switch v := h.(type) {
+	case *evergreen.OIDCConfig:
+		if v == nil {
+			return nil
+		}
+		a.Issuer = utility.ToStringPtr(v.Issuer)
+		a.ClientID = utility.ToStringPtr(v.ClientID)
+		a.ClientSecret = utility.ToStringPtr(v.ClientSecret)
+		a.Scopes = v.Scopes
+		a.UsernameClaim = utility.ToStringPtr(v.UsernameClaim)
+		a.GroupsClaim = utility.ToStringPtr(v.GroupsClaim)
+		a.UserGroup = utility.ToStringPtr(v.UserGroup)
+		a.RoleMappings = nil
+		for _, mapping := range v.RoleMappings {
+			a.RoleMappings = append(a.RoleMappings, APIOIDCRoleMapping{
+				Group: utility.ToStringPtr(mapping.Group),
+				Roles: mapping.Roles,
+			})
+		}
+		a.ExpireAfterMinutes = v.ExpireAfterMinutes
+		a.ExpectedEmailDomains = v.ExpectedEmailDomains
+		return nil
+	default:
+		return errors.Errorf("programmatic error: expected OIDC config but got type %T", h)
+	}
+}
+
+func (a *APIOIDCConfig) ToService() (any, error) {
+	if a == nil {
+		return nil, nil
+	}
+	var mappings []evergreen.OIDCRoleMapping
+	for _, mapping := range a.RoleMappings {
+		mappings = append(mappings, evergreen.OIDCRoleMapping{
+			Group: utility.FromStringPtr(mapping.Group),
+			Roles: mapping.Roles,
+		})
+	}
+	return &evergreen.OIDCConfig{
+		Issuer:               utility.FromStringPtr(a.Issuer),
+		ClientID:             utility.FromStringPtr(a.ClientID),
+		ClientSecret:         utility.FromStringPtr(a.ClientSecret),
+		Scopes:               a.Scopes,
+		UsernameClaim:        utility.FromStringPtr(a.UsernameClaim),
+		GroupsClaim:          utility.FromStringPtr(a.GroupsClaim),
+		UserGroup:            utility.FromStringPtr(a.UserGroup),
+		RoleMappings:         mappings,
+		ExpireAfterMinutes:   a.ExpireAfterMinutes,
+		ExpectedEmailDomains: a.ExpectedEmailDomains,
+	}, nil
+}
+
 // APIBanner is a public structure representing the banner part of the admin settings
 type APIBanner struct {
 	Text  *string `json:"banner"`
@@ -1540,6 +1674,7 @@ func (a *APIProjectCreationConfig) ToService() (any, error) {
 type APICloudProviders struct {
 	AWS    *APIAWSConfig    `json:"aws"`
 	Docker *APIDockerConfig `json:"docker"`
+	Podman *APIPodmanConfig `json:"podman"`
 }
 
 func (a *APICloudProviders) BuildFromService(h any) error {
@@ -1547,12 +1682,16 @@ func (a *APICloudProviders) BuildFromService(h any) error {
 	case evergreen.CloudProviders:
 		a.AWS = &APIAWSConfig{}
 		a.Docker = &APIDockerConfig{}
+		a.Podman = &APIPodmanConfig{}
 		if err := a.AWS.BuildFromService(v.AWS); err != nil {
 			return err
 		}
 		if err := a.Docker.BuildFromService(v.Docker); err != nil {
 			return err
 		}
+		if err := a.Podman.BuildFromService(v.Podman); err != nil {
+			return err
+		}
 	default:
 		return errors.Errorf("programmatic error: expected cloud provider config but got type %T", h)
 	}
@@ -1568,6 +1707,10 @@ func (a *APICloudProviders) ToService() (any, error) {
 	if err != nil {
 		return nil, err
 	}
+	podman, err := a.Podman.ToService()
+	if err != nil {
+		return nil, err
+	}
 
 	config := evergreen.CloudProviders{}
 
@@ -1579,6 +1722,10 @@ func (a *APICloudProviders) ToService() (any, error) {
 		config.Docker = docker.(evergreen.DockerConfig)
 	}
 
+	if podman != nil {
+		config.Podman = podman.(evergreen.PodmanConfig)
+	}
+
 	return config, nil
 }
 
@@ -1974,6 +2121,32 @@ func (a *APIDockerConfig) ToService() (any, error) {
 	}, nil
 }
 
+type APIPodmanConfig struct {
+	SocketPath *string `json:"socket_path"`
+	APIVersion *string `json:"api_version"`
+}
+
+func (a *APIPodmanConfig) BuildFromService(h any) error {
+	switch v := h.(type) {
+	case evergreen.PodmanConfig:
+		a.SocketPath = utility.ToStringPtr(v.SocketPath)
+		a.APIVersion = utility.ToStringPtr(v.APIVersion)
+	default:
+		return errors.Errorf("programmatic error: expected Podman config but got type %T", h)
+	}
+	return nil
+}
+
+func (a *APIPodmanConfig) ToService() (any, error) {
+	if a == nil {
+		return nil, nil
+	}
+	return evergreen.PodmanConfig{
+		SocketPath: utility.FromStringPtr(a.SocketPath),
+		APIVersion: utility.FromStringPtr(a.APIVersion),
+	}, nil
+}
+
 type APIRepoTrackerConfig struct {
 	NumNewRepoRevisionsToFetch int `json:"revs_to_fetch"`
 	MaxRepoRevisionsToSearch   int `json:"max_revs_to_search"`
@@ -2147,6 +2320,7 @@ type APIServiceFlags struct {
 	PodDiagnosticsDisabled             bool `json:"pod_diagnostics_disabled"`
 	RetryFailedLogMoveEnabled          bool `json:"retry_failed_log_move_enabled"`
 	ProjectTranslationCacheEnabled     bool `json:"project_translation_cache_enabled"`
+	LogRetentionEnabled                bool `json:"log_retention_enabled"`
 	ContainerIsolationEnabled          bool `json:"container_isolation_enabled"`
 	LiveArtifactCredentialsDisabled    bool `json:"live_artifact_credentials_disabled"`
 
@@ -2613,6 +2787,7 @@ func (as *APIServiceFlags) BuildFromService(h any) error {
 		as.PodDiagnosticsDisabled = v.PodDiagnosticsDisabled
 		as.RetryFailedLogMoveEnabled = v.RetryFailedLogMoveEnabled
 		as.ProjectTranslationCacheEnabled = v.ProjectTranslationCacheEnabled
+		as.LogRetentionEnabled = v.LogRetentionEnabled
 		as.ContainerIsolationEnabled = v.ContainerIsolationEnabled
 		as.LiveArtifactCredentialsDisabled = v.LiveArtifactCredentialsDisabled
 		as.BackgroundCommandFailureEnabled = v.BackgroundCommandFailureEnabled
@@ -2668,6 +2843,7 @@ func (as *APIServiceFlags) ToService() (any, error) {
 		PodDiagnosticsDisabled:             as.PodDiagnosticsDisabled,
 		RetryFailedLogMoveEnabled:          as.RetryFailedLogMoveEnabled,
 		ProjectTranslationCacheEnabled:     as.ProjectTranslationCacheEnabled,
+		LogRetentionEnabled:                as.LogRetentionEnabled,
 		BackgroundCommandFailureEnabled:    as.BackgroundCommandFailureEnabled,
 		ContainerIsolationEnabled:          as.ContainerIsolationEnabled,
 		LiveArtifactCredentialsDisabled:    as.LiveArtifactCredentialsDisabled,
@@ -118,6 +118,10 @@ func TestModelConversion(t *testing.T) {
 	assert.EqualValues(testSettings.AuthConfig.OAuth.Issuer, utility.FromStringPtr(apiSettings.AuthConfig.OAuth.Issuer))
 	assert.EqualValues(testSettings.AuthConfig.OAuth.ClientID, utility.FromStringPtr(apiSettings.AuthConfig.OAuth.ClientID))
 	assert.EqualValues(testSettings.AuthConfig.OAuth.ConnectorID, utility.FromStringPtr(apiSettings.AuthConfig.OAuth.ConnectorID))
+	assert.EqualValues(testSettings.AuthConfig.OIDC.Issuer, utility.FromStringPtr(apiSettings.AuthConfig.OIDC.Issuer))
+	assert.EqualValues(testSettings.AuthConfig.OIDC.ClientID, utility.FromStringPtr(apiSettings.AuthConfig.OIDC.ClientID))
+	assert.EqualValues(testSettings.AuthConfig.OIDC.RoleMappings[0].Group, utility.FromStringPtr(apiSettings.AuthConfig.OIDC.RoleMappings[0].Group))
+	assert.EqualValues(testSettings.AuthConfig.OIDC.RoleMappings[0].Roles, apiSettings.AuthConfig.OIDC.RoleMappings[0].Roles)
 	assert.Equal(len(testSettings.AuthConfig.Github.Users), len(apiSettings.AuthConfig.Github.Users))
 	assert.Equal(testSettings.OktaServiceConfig.ClientID, utility.FromStringPtr(apiSettings.OktaServiceConfig.ClientID))
 	assert.Equal(testSettings.OktaServiceConfig.ClientSecret, utility.FromStringPtr(apiSettings.OktaServiceConfig.ClientSecret))
@@ -134,6 +138,14 @@ func TestModelConversion(t *testing.T) {
 	assert.Equal(testSettings.Buckets.TestResultsBucket.DBName, utility.FromStringPtr(apiSettings.Buckets.TestResultsBucket.DBName))
 	assert.Equal(testSettings.Buckets.TestResultsBucket.TestResultsPrefix, utility.FromStringPtr(apiSettings.Buckets.TestResultsBucket.TestResultsPrefix))
 	assert.Equal(testSettings.Buckets.TestResultsBucket.RoleARN, utility.FromStringPtr(apiSettings.Buckets.TestResultsBucket.RoleARN))
+	require.Len(apiSettings.Buckets.CacheStores, len(testSettings.Buckets.CacheStores))
+	for i, store := range testSettings.Buckets.CacheStores {
+		assert.Equal(store.Bucket, utility.FromStringPtr(apiSettings.Buckets.CacheStores[i].Bucket))
+		assert.Equal(store.Prefix, utility.FromStringPtr(apiSettings.Buckets.CacheStores[i].Prefix))
+		assert.Equal(store.Region, utility.FromStringPtr(apiSettings.Buckets.CacheStores[i].Region))
+		assert.Equal(store.RoleARN, utility.FromStringPtr(apiSettings.Buckets.CacheStores[i].RoleARN))
+	}
+	assert.EqualValues(testSettings.Buckets.CacheManifestTTLDays, utility.FromIntPtr(apiSettings.Buckets.CacheManifestTTLDays))
 	assert.EqualValues(testSettings.Buckets.Credentials.Key, utility.FromStringPtr(apiSettings.Buckets.Credentials.Key))
 	assert.EqualValues(testSettings.Buckets.Credentials.Secret, utility.FromStringPtr(apiSettings.Buckets.Credentials.Secret))
 	assert.EqualValues(testSettings.Buckets.Credentials.Bucket, utility.FromStringPtr(apiSettings.Buckets.Credentials.Bucket))
@@ -194,6 +206,7 @@ func TestModelConversion(t *testing.T) {
 	assert.EqualValues(testSettings.ServiceFlags.CPUDegradedModeDisabled, apiSettings.ServiceFlags.DegradedModeDisabled)
 	assert.EqualValues(testSettings.ServiceFlags.ElasticIPsDisabled, apiSettings.ServiceFlags.ElasticIPsDisabled)
 	assert.EqualValues(testSettings.ServiceFlags.PodDiagnosticsDisabled, apiSettings.ServiceFlags.PodDiagnosticsDisabled)
+	assert.EqualValues(testSettings.ServiceFlags.LogRetentionEnabled, apiSettings.ServiceFlags.LogRetentionEnabled)
 	assert.EqualValues(testSettings.SingleTaskDistro.ProjectTasksPairs[0].ProjectID, apiSettings.SingleTaskDistro.ProjectTasksPairs[0].ProjectID)
 	assert.ElementsMatch(testSettings.SingleTaskDistro.ProjectTasksPairs[0].AllowedTasks, apiSettings.SingleTaskDistro.ProjectTasksPairs[0].AllowedTasks)
 	assert.EqualValues(testSettings.Slack.Level, utility.FromStringPtr(apiSettings.Slack.Level))
@@ -254,6 +267,8 @@ func TestModelConversion(t *testing.T) {
 	assert.Equal(len(testSettings.AuthConfig.Github.Users), len(dbSettings.AuthConfig.Github.Users))
 	assert.EqualValues(testSettings.AuthConfig.Multi.ReadWrite[0], dbSettings.AuthConfig.Multi.ReadWrite[0])
 	assert.EqualValues(testSettings.AuthConfig.Kanopy.Issuer, dbSettings.AuthConfig.Kanopy.Issuer)
+	assert.EqualValues(testSettings.AuthConfig.OIDC.Issuer, dbSettings.AuthConfig.OIDC.Issuer)
+	assert.EqualValues(testSettings.AuthConfig.OIDC.RoleMappings, dbSettings.AuthConfig.OIDC.RoleMappings)
 	assert.Equal(testSettings.Buckets.LogBucket.Name, utility.FromStringPtr(apiSettings.Buckets.LogBucket.Name))
 	assert.EqualValues(testSettings.Buckets.LogBucket.Type, utility.FromStringPtr(apiSettings.Buckets.LogBucket.Type))
 	assert.Equal(testSettings.Buckets.LogBucket.DBName, utility.FromStringPtr(apiSettings.Buckets.LogBucket.DBName))
@@ -304,6 +319,7 @@ func TestModelConversion(t *testing.T) {
 	assert.EqualValues(testSettings.ServiceFlags.CPUDegradedModeDisabled, apiSettings.ServiceFlags.DegradedModeDisabled)
 	assert.EqualValues(testSettings.ServiceFlags.ElasticIPsDisabled, apiSettings.ServiceFlags.ElasticIPsDisabled)
 	assert.EqualValues(testSettings.ServiceFlags.PodDiagnosticsDisabled, dbSettings.ServiceFlags.PodDiagnosticsDisabled)
+	assert.EqualValues(testSettings.ServiceFlags.LogRetentionEnabled, dbSettings.ServiceFlags.LogRetentionEnabled)
 	assert.EqualValues(testSettings.SingleTaskDistro.ProjectTasksPairs[0].ProjectID, dbSettings.SingleTaskDistro.ProjectTasksPairs[0].ProjectID)
 	assert.ElementsMatch(testSettings.SingleTaskDistro.ProjectTasksPairs[0].AllowedTasks, dbSettings.SingleTaskDistro.ProjectTasksPairs[0].AllowedTasks)
 	assert.EqualValues(testSettings.Slack.Level, dbSettings.Slack.Level)
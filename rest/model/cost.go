@@ -1,6 +1,9 @@
 package model
 
-import "github.com/evergreen-ci/evergreen"
+import (
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/cost"
+)
 
 // shouldHideCostForProject reports whether cost fields should be suppressed in API responses for the
 // given project ID.
@@ -18,3 +21,40 @@ func shouldHideCostForProject(projectID string) bool {
 	}
 	return settings.Cost.ShouldHideCost(projectID)
 }
+
+// APIPatchCostEstimate is the predicted cost of running a patch's selected
+// tasks.
+type APIPatchCostEstimate struct {
+	PatchId *string `json:"patch_id"`
+	// Predicted cost of each build variant's selected tasks.
+	Variants []APIVariantCostEstimate `json:"variants"`
+	// Predicted cost of all of the selected tasks.
+	Total cost.Cost `json:"total"`
+	// Estimated cost in dollars above which the project requires the patch to
+	// be confirmed before it's scheduled. Unset if the project has no
+	// threshold.
+	Threshold *float64 `json:"threshold,omitempty"`
+	// Whether the predicted total is above the project's threshold.
+	RequiresConfirmation bool `json:"requires_confirmation"`
+}
+
+// APIVariantCostEstimate is the predicted cost of running the selected tasks
+// in a single build variant.
+type APIVariantCostEstimate struct {
+	BuildVariant *string `json:"build_variant"`
+	// Number of selected tasks in the build variant.
+	NumTasks int `json:"num_tasks"`
+	// Number of selected tasks that run on distros without pricing data,
+	// which are not included in the predicted cost.
+	NumUnpricedTasks int `json:"num_unpriced_tasks,omitempty"`
+	// Predicted cost of the selected tasks.
+	Cost cost.Cost `json:"cost"`
+}
+
+// AddVariant adds a build variant's estimate to the patch estimate's total.
+func (e *APIPatchCostEstimate) AddVariant(v APIVariantCostEstimate) {
+	v.Cost.Total = v.Cost.AdjustedTotal()
+	e.Variants = append(e.Variants, v)
+	e.Total = e.Total.Add(v.Cost)
+	e.Total.Total = e.Total.AdjustedTotal()
+}
@@ -0,0 +1,112 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/culprit"
+	"github.com/evergreen-ci/utility"
+)
+
+// APIBisection is a culprit-finding bisection and its progress.
+type APIBisection struct {
+	// Identifier for the bisection.
+	ID *string `json:"id"`
+	// Identifier for the project.
+	ProjectID *string `json:"project_id"`
+	// Identifier for the version whose failures started the bisection.
+	VersionID *string `json:"version_id"`
+	// Either "running", "found" or "inconclusive".
+	Status *string `json:"status"`
+	// The failed tasks being bisected, which last passed in the same version.
+	Tasks []APIBisectionTask `json:"tasks"`
+	// The tasks that are run to test each commit.
+	RepresentativeTasks []APIBisectionTask `json:"representative_tasks"`
+	// Revision order numbers of the last passing and first failing versions
+	// when the bisection started.
+	RangeStartOrder int `json:"range_start_order"`
+	RangeEndOrder   int `json:"range_end_order"`
+	// The current range of commits that could have caused the failures.
+	LastPassingOrder      int     `json:"last_passing_order"`
+	LastPassingVersionID  *string `json:"last_passing_version_id"`
+	FirstFailingOrder     int     `json:"first_failing_order"`
+	FirstFailingVersionID *string `json:"first_failing_version_id"`
+	// Number of versions in the current range that can still be tested.
+	CandidatesRemaining int `json:"candidates_remaining"`
+	// Estimated number of steps left before the bisection finishes.
+	StepsRemaining int `json:"steps_remaining"`
+	// The versions that have been tested, in order.
+	Steps []APIBisectionStep `json:"steps"`
+	// The version and commit suspected of causing the failures, once found.
+	SuspectedVersionID *string `json:"suspected_version_id,omitempty"`
+	SuspectedRevision  *string `json:"suspected_revision,omitempty"`
+	// Why the bisection was inconclusive, if it was.
+	Reason     *string    `json:"reason,omitempty"`
+	CreatedAt  *time.Time `json:"created_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+}
+
+type APIBisectionTask struct {
+	BuildVariant *string `json:"build_variant"`
+	DisplayName  *string `json:"display_name"`
+}
+
+// APIBisectionStep is a version tested by a bisection.
+type APIBisectionStep struct {
+	Order     int      `json:"order"`
+	VersionID *string  `json:"version_id"`
+	Revision  *string  `json:"revision"`
+	TaskIDs   []string `json:"task_ids"`
+	// Either "pending", "passed" or "failed".
+	Result     *string    `json:"result"`
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+}
+
+func buildAPIBisectionTasks(keys []culprit.TaskKey) []APIBisectionTask {
+	tasks := make([]APIBisectionTask, 0, len(keys))
+	for _, key := range keys {
+		tasks = append(tasks, APIBisectionTask{
+			BuildVariant: utility.ToStringPtr(key.BuildVariant),
+			DisplayName:  utility.ToStringPtr(key.DisplayName),
+		})
+	}
+	return tasks
+}
+
+func (b *APIBisection) BuildFromService(bisection culprit.Bisection) {
+	b.ID = utility.ToStringPtr(bisection.ID)
+	b.ProjectID = utility.ToStringPtr(bisection.ProjectID)
+	b.VersionID = utility.ToStringPtr(bisection.VersionID)
+	b.Status = utility.ToStringPtr(string(bisection.Status))
+	b.Tasks = buildAPIBisectionTasks(bisection.Tasks)
+	b.RepresentativeTasks = buildAPIBisectionTasks(bisection.Representatives)
+	b.RangeStartOrder = bisection.RangeStartOrder
+	b.RangeEndOrder = bisection.RangeEndOrder
+	b.LastPassingOrder = bisection.LastPassingOrder
+	b.LastPassingVersionID = utility.ToStringPtr(bisection.LastPassingVersionID)
+	b.FirstFailingOrder = bisection.FirstFailingOrder
+	b.FirstFailingVersionID = utility.ToStringPtr(bisection.FirstFailingVersionID)
+	b.CandidatesRemaining = len(bisection.RemainingCandidates())
+	b.StepsRemaining = bisection.StepsRemaining()
+	b.Steps = make([]APIBisectionStep, 0, len(bisection.Steps))
+	for _, step := range bisection.Steps {
+		b.Steps = append(b.Steps, APIBisectionStep{
+			Order:      step.Order,
+			VersionID:  utility.ToStringPtr(step.VersionID),
+			Revision:   utility.ToStringPtr(step.Revision),
+			TaskIDs:    step.TaskIDs,
+			Result:     utility.ToStringPtr(string(step.Result)),
+			StartedAt:  ToTimePtr(step.StartedAt),
+			FinishedAt: ToTimePtr(step.FinishedAt),
+		})
+	}
+	if bisection.SuspectedVersionID != "" {
+		b.SuspectedVersionID = utility.ToStringPtr(bisection.SuspectedVersionID)
+		b.SuspectedRevision = utility.ToStringPtr(bisection.SuspectedRevision)
+	}
+	if bisection.Reason != "" {
+		b.Reason = utility.ToStringPtr(bisection.Reason)
+	}
+	b.CreatedAt = ToTimePtr(bisection.CreatedAt)
+	b.FinishedAt = ToTimePtr(bisection.FinishedAt)
+}
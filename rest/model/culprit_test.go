@@ -0,0 +1,64 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/culprit"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIBisectionBuildFromService(t *testing.T) {
+	now := time.Now()
+	b := culprit.Bisection{
+		ID:                    "b",
+		ProjectID:             "proj",
+		VersionID:             "v5",
+		Tasks:                 []culprit.TaskKey{{BuildVariant: "bv", DisplayName: "test"}},
+		Representatives:       []culprit.TaskKey{{BuildVariant: "bv", DisplayName: "test"}},
+		RangeStartOrder:       1,
+		RangeEndOrder:         5,
+		LastPassingOrder:      3,
+		LastPassingVersionID:  "v3",
+		FirstFailingOrder:     5,
+		FirstFailingVersionID: "v5",
+		Candidates: []culprit.Candidate{
+			{Order: 2, VersionID: "v2"},
+			{Order: 3, VersionID: "v3"},
+			{Order: 4, VersionID: "v4"},
+		},
+		Steps: []culprit.Step{
+			{Order: 3, VersionID: "v3", Revision: "r3", TaskIDs: []string{"t3"}, Result: culprit.StepPassed, StartedAt: now, FinishedAt: now},
+			{Order: 4, VersionID: "v4", Revision: "r4", TaskIDs: []string{"t4"}, Result: culprit.StepPending, StartedAt: now},
+		},
+		Status:    culprit.BisectionRunning,
+		CreatedAt: now,
+	}
+
+	apiBisection := APIBisection{}
+	apiBisection.BuildFromService(b)
+	assert.Equal(t, "b", utility.FromStringPtr(apiBisection.ID))
+	assert.Equal(t, "running", utility.FromStringPtr(apiBisection.Status))
+	require.Len(t, apiBisection.Tasks, 1)
+	assert.Equal(t, "test", utility.FromStringPtr(apiBisection.Tasks[0].DisplayName))
+	assert.Equal(t, 1, apiBisection.CandidatesRemaining)
+	assert.Equal(t, 1, apiBisection.StepsRemaining)
+	require.Len(t, apiBisection.Steps, 2)
+	assert.Equal(t, "passed", utility.FromStringPtr(apiBisection.Steps[0].Result))
+	assert.NotNil(t, apiBisection.Steps[0].FinishedAt)
+	assert.Nil(t, apiBisection.Steps[1].FinishedAt)
+	assert.Nil(t, apiBisection.SuspectedVersionID)
+	assert.Nil(t, apiBisection.FinishedAt)
+
+	b.RecordResult(culprit.StepFailed, now)
+	require.True(t, b.Conclude(now))
+	apiBisection = APIBisection{}
+	apiBisection.BuildFromService(b)
+	assert.Equal(t, "found", utility.FromStringPtr(apiBisection.Status))
+	assert.Equal(t, "v4", utility.FromStringPtr(apiBisection.SuspectedVersionID))
+	assert.Equal(t, "r4", utility.FromStringPtr(apiBisection.SuspectedRevision))
+	assert.Equal(t, 0, apiBisection.StepsRemaining)
+	assert.NotNil(t, apiBisection.FinishedAt)
+}
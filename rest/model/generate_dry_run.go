@@ -0,0 +1,64 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/utility"
+)
+
+// APIGeneratedProjectDiff describes what generate.tasks would add to a
+// project config.
+type APIGeneratedProjectDiff struct {
+	// Build variants that did not exist before generation.
+	BuildVariants []string `json:"build_variants" yaml:"build_variants,omitempty"`
+	// Task, task group and function definitions added by generation.
+	Tasks      []string `json:"tasks" yaml:"tasks,omitempty"`
+	TaskGroups []string `json:"task_groups" yaml:"task_groups,omitempty"`
+	Functions  []string `json:"functions" yaml:"functions,omitempty"`
+	// Variant-task pairs that would be created.
+	VariantTasks []APIVariantTask `json:"variant_tasks" yaml:"variant_tasks,omitempty"`
+	// Dependencies that did not exist before generation.
+	Dependencies []APIGeneratedDependency `json:"dependencies" yaml:"dependencies,omitempty"`
+}
+
+type APIVariantTask struct {
+	BuildVariant *string `json:"build_variant" yaml:"build_variant"`
+	DisplayName  *string `json:"display_name" yaml:"display_name"`
+}
+
+// APIGeneratedDependency is a dependency of one variant task on another.
+type APIGeneratedDependency struct {
+	Task      APIVariantTask `json:"task" yaml:"task"`
+	DependsOn APIVariantTask `json:"depends_on" yaml:"depends_on"`
+	Status    *string        `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+func (d *APIGeneratedProjectDiff) BuildFromService(diff model.GeneratedProjectDiff) {
+	d.BuildVariants = diff.BuildVariants
+	d.Tasks = diff.Tasks
+	d.TaskGroups = diff.TaskGroups
+	d.Functions = diff.Functions
+	d.VariantTasks = make([]APIVariantTask, 0, len(diff.VariantTasks))
+	for _, pair := range diff.VariantTasks {
+		d.VariantTasks = append(d.VariantTasks, APIVariantTask{
+			BuildVariant: utility.ToStringPtr(pair.Variant),
+			DisplayName:  utility.ToStringPtr(pair.TaskName),
+		})
+	}
+	d.Dependencies = make([]APIGeneratedDependency, 0, len(diff.Dependencies))
+	for _, edge := range diff.Dependencies {
+		dep := APIGeneratedDependency{
+			Task: APIVariantTask{
+				BuildVariant: utility.ToStringPtr(edge.From.Variant),
+				DisplayName:  utility.ToStringPtr(edge.From.Name),
+			},
+			DependsOn: APIVariantTask{
+				BuildVariant: utility.ToStringPtr(edge.To.Variant),
+				DisplayName:  utility.ToStringPtr(edge.To.Name),
+			},
+		}
+		if edge.Status != "" {
+			dep.Status = utility.ToStringPtr(edge.Status)
+		}
+		d.Dependencies = append(d.Dependencies, dep)
+	}
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIGeneratedProjectDiffBuildFromService(t *testing.T) {
+	diff := model.GeneratedProjectDiff{
+		BuildVariants: []string{"windows"},
+		Tasks:         []string{"test"},
+		Functions:     []string{"run tests"},
+		VariantTasks: []model.TVPair{
+			{Variant: "windows", TaskName: "test"},
+		},
+		Dependencies: []task.DependencyEdge{
+			{
+				From:   task.TaskNode{Variant: "windows", Name: "test"},
+				To:     task.TaskNode{Variant: "windows", Name: "compile"},
+				Status: evergreen.TaskFailed,
+			},
+		},
+	}
+
+	apiDiff := APIGeneratedProjectDiff{}
+	apiDiff.BuildFromService(diff)
+	assert.Equal(t, diff.BuildVariants, apiDiff.BuildVariants)
+	assert.Equal(t, diff.Tasks, apiDiff.Tasks)
+	assert.Empty(t, apiDiff.TaskGroups)
+	assert.Equal(t, diff.Functions, apiDiff.Functions)
+	require.Len(t, apiDiff.VariantTasks, 1)
+	assert.Equal(t, "windows", utility.FromStringPtr(apiDiff.VariantTasks[0].BuildVariant))
+	assert.Equal(t, "test", utility.FromStringPtr(apiDiff.VariantTasks[0].DisplayName))
+	require.Len(t, apiDiff.Dependencies, 1)
+	assert.Equal(t, "test", utility.FromStringPtr(apiDiff.Dependencies[0].Task.DisplayName))
+	assert.Equal(t, "compile", utility.FromStringPtr(apiDiff.Dependencies[0].DependsOn.DisplayName))
+	assert.Equal(t, evergreen.TaskFailed, utility.FromStringPtr(apiDiff.Dependencies[0].Status))
+}
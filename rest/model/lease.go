@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/lease"
+	"github.com/evergreen-ci/utility"
+)
+
+// APILease is a task's hold on a slot of a project lock acquired with the
+// lock.acquire command.
+type APILease struct {
+	ProjectID *string `json:"project_id"`
+	// Name of the lock.
+	Name *string `json:"name"`
+	// Slot of the lock that the task holds. Locks with more than one slot can
+	// be held by that many tasks at once.
+	Slot int `json:"slot"`
+	// The task execution holding the lock.
+	TaskID     *string    `json:"task_id"`
+	Execution  int        `json:"execution"`
+	AcquiredAt *time.Time `json:"acquired_at"`
+	RenewedAt  *time.Time `json:"renewed_at"`
+	// When the lease expires unless the task's heartbeat renews it.
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+func (l *APILease) BuildFromService(dbLease lease.Lease) {
+	l.ProjectID = utility.ToStringPtr(dbLease.ProjectID)
+	l.Name = utility.ToStringPtr(dbLease.Name)
+	l.Slot = dbLease.Slot
+	l.TaskID = utility.ToStringPtr(dbLease.TaskID)
+	l.Execution = dbLease.Execution
+	l.AcquiredAt = ToTimePtr(dbLease.AcquiredAt)
+	l.RenewedAt = ToTimePtr(dbLease.RenewedAt)
+	l.ExpiresAt = ToTimePtr(dbLease.ExpiresAt)
+}
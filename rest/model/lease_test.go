@@ -0,0 +1,35 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/lease"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPILeaseBuildFromService(t *testing.T) {
+	now := time.Now().Round(time.Second)
+	l := lease.Lease{
+		ID:         "project/staging/1",
+		ProjectID:  "project",
+		Name:       "staging",
+		Slot:       1,
+		TaskID:     "t1",
+		Execution:  2,
+		AcquiredAt: now,
+		RenewedAt:  now,
+		ExpiresAt:  now.Add(lease.DefaultTTL),
+	}
+
+	apiLease := APILease{}
+	apiLease.BuildFromService(l)
+	assert.Equal(t, "project", utility.FromStringPtr(apiLease.ProjectID))
+	assert.Equal(t, "staging", utility.FromStringPtr(apiLease.Name))
+	assert.Equal(t, 1, apiLease.Slot)
+	assert.Equal(t, "t1", utility.FromStringPtr(apiLease.TaskID))
+	assert.Equal(t, 2, apiLease.Execution)
+	assert.True(t, now.Equal(utility.FromTimePtr(apiLease.AcquiredAt)))
+	assert.True(t, now.Add(lease.DefaultTTL).Equal(utility.FromTimePtr(apiLease.ExpiresAt)))
+}
@@ -23,6 +23,8 @@ type apiNotificationStats struct {
 	EvergreenWebhook  int `json:"evergreen_webhook"`
 	Email             int `json:"email"`
 	Slack             int `json:"slack"`
+	Teams             int `json:"teams"`
+	Mattermost        int `json:"mattermost"`
 }
 
 func (n *apiNotificationStats) BuildFromService(data notification.NotificationStats) {
@@ -32,4 +34,6 @@ func (n *apiNotificationStats) BuildFromService(data notification.NotificationSt
 	n.EvergreenWebhook = data.EvergreenWebhook
 	n.Email = data.Email
 	n.Slack = data.Slack
+	n.Teams = data.Teams
+	n.Mattermost = data.Mattermost
 }
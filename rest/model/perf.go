@@ -0,0 +1,100 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/perf"
+	"github.com/evergreen-ci/utility"
+)
+
+// APIPerfSeriesKey identifies a time series of performance results.
+type APIPerfSeriesKey struct {
+	ProjectID *string `json:"project_id"`
+	Variant   *string `json:"variant"`
+	TaskName  *string `json:"task_name"`
+	// Name of the test. Subtests are named after their parents, separated by
+	// a slash.
+	TestName *string `json:"test_name"`
+	// Arguments the test ran with, such as the number of threads.
+	Args   map[string]int32 `json:"args,omitempty"`
+	Metric *string          `json:"metric"`
+}
+
+func (k *APIPerfSeriesKey) BuildFromService(key perf.SeriesKey) {
+	k.ProjectID = utility.ToStringPtr(key.ProjectID)
+	k.Variant = utility.ToStringPtr(key.Variant)
+	k.TaskName = utility.ToStringPtr(key.TaskName)
+	k.TestName = utility.ToStringPtr(key.TestName)
+	k.Args = key.Args
+	k.Metric = utility.ToStringPtr(key.Metric)
+}
+
+// APIPerfPoint is the value of a performance metric reported by one task.
+type APIPerfPoint struct {
+	SeriesID *string `json:"series_id"`
+	APIPerfSeriesKey
+	MetricType *string `json:"metric_type"`
+	Value      float64 `json:"value"`
+	// Revision order number of the task's version.
+	Order     int     `json:"order"`
+	Version   *string `json:"version"`
+	TaskID    *string `json:"task_id"`
+	Execution int     `json:"execution"`
+	// Whether the task ran in a mainline commit version rather than a patch.
+	Mainline  bool       `json:"mainline"`
+	CreatedAt *time.Time `json:"created_at"`
+}
+
+func (p *APIPerfPoint) BuildFromService(point perf.Point) {
+	p.SeriesID = utility.ToStringPtr(point.SeriesID)
+	p.APIPerfSeriesKey.BuildFromService(point.SeriesKey)
+	p.MetricType = utility.ToStringPtr(point.MetricType)
+	p.Value = point.Value
+	p.Order = point.Order
+	p.Version = utility.ToStringPtr(point.Version)
+	p.TaskID = utility.ToStringPtr(point.TaskID)
+	p.Execution = point.Execution
+	p.Mainline = point.Mainline
+	p.CreatedAt = ToTimePtr(point.CreatedAt)
+}
+
+// APIPerfChangePoint is a shift in the mainline history of a performance
+// series, which may be a regression.
+type APIPerfChangePoint struct {
+	ID       *string `json:"id"`
+	SeriesID *string `json:"series_id"`
+	APIPerfSeriesKey
+	// The first task, and its version's revision order number, after the
+	// shift.
+	Order     int     `json:"order"`
+	Version   *string `json:"version"`
+	TaskID    *string `json:"task_id"`
+	Execution int     `json:"execution"`
+	// Means of the values before and after the shift.
+	BeforeMean float64 `json:"before_mean"`
+	AfterMean  float64 `json:"after_mean"`
+	// How much the mean changed, relative to its value before the shift.
+	PercentChange float64 `json:"percent_change"`
+	// The poplar type of the metric.
+	MetricType *string `json:"metric_type"`
+	// Whether the shift made performance worse, which is only known for
+	// throughput, latency and percentile metrics.
+	Regression bool       `json:"regression"`
+	DetectedAt *time.Time `json:"detected_at"`
+}
+
+func (c *APIPerfChangePoint) BuildFromService(cp perf.ChangePoint) {
+	c.ID = utility.ToStringPtr(cp.ID)
+	c.SeriesID = utility.ToStringPtr(cp.SeriesID)
+	c.APIPerfSeriesKey.BuildFromService(cp.SeriesKey)
+	c.Order = cp.Order
+	c.Version = utility.ToStringPtr(cp.Version)
+	c.TaskID = utility.ToStringPtr(cp.TaskID)
+	c.Execution = cp.Execution
+	c.BeforeMean = cp.BeforeMean
+	c.AfterMean = cp.AfterMean
+	c.PercentChange = cp.PercentChange
+	c.MetricType = utility.ToStringPtr(cp.MetricType)
+	c.Regression = cp.Regression
+	c.DetectedAt = ToTimePtr(cp.DetectedAt)
+}
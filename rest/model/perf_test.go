@@ -0,0 +1,84 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/perf"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIPerfBuildFromService(t *testing.T) {
+	now := time.Now().Round(time.Second)
+	key := perf.SeriesKey{
+		ProjectID: "project",
+		Variant:   "bv",
+		TaskName:  "task",
+		TestName:  "insert",
+		Args:      map[string]int32{"threads": 4},
+		Metric:    "ops_per_sec",
+	}
+
+	t.Run("Point", func(t *testing.T) {
+		point := perf.Point{
+			ID:         perf.MakePointID(key.ID(), "t1"),
+			SeriesID:   key.ID(),
+			SeriesKey:  key,
+			MetricType: "THROUGHPUT",
+			Value:      1200.5,
+			Order:      10,
+			Version:    "v1",
+			TaskID:     "t1",
+			Execution:  1,
+			Mainline:   true,
+			CreatedAt:  now,
+		}
+
+		apiPoint := APIPerfPoint{}
+		apiPoint.BuildFromService(point)
+		assert.Equal(t, key.ID(), utility.FromStringPtr(apiPoint.SeriesID))
+		assert.Equal(t, "project", utility.FromStringPtr(apiPoint.ProjectID))
+		assert.Equal(t, "insert", utility.FromStringPtr(apiPoint.TestName))
+		assert.Equal(t, map[string]int32{"threads": 4}, apiPoint.Args)
+		assert.Equal(t, "ops_per_sec", utility.FromStringPtr(apiPoint.Metric))
+		assert.Equal(t, "THROUGHPUT", utility.FromStringPtr(apiPoint.MetricType))
+		assert.Equal(t, 1200.5, apiPoint.Value)
+		assert.Equal(t, 10, apiPoint.Order)
+		assert.Equal(t, "t1", utility.FromStringPtr(apiPoint.TaskID))
+		assert.Equal(t, 1, apiPoint.Execution)
+		assert.True(t, apiPoint.Mainline)
+		assert.True(t, now.Equal(utility.FromTimePtr(apiPoint.CreatedAt)))
+	})
+	t.Run("ChangePoint", func(t *testing.T) {
+		cp := perf.ChangePoint{
+			ID:            "cp",
+			SeriesID:      key.ID(),
+			SeriesKey:     key,
+			Order:         12,
+			Version:       "v3",
+			TaskID:        "t3",
+			BeforeMean:    100,
+			AfterMean:     80,
+			PercentChange: -20,
+			MetricType:    perf.MetricTypeThroughput,
+			Regression:    true,
+			DetectedAt:    now,
+		}
+
+		apiCP := APIPerfChangePoint{}
+		apiCP.BuildFromService(cp)
+		assert.Equal(t, "cp", utility.FromStringPtr(apiCP.ID))
+		assert.Equal(t, "bv", utility.FromStringPtr(apiCP.Variant))
+		assert.Equal(t, "task", utility.FromStringPtr(apiCP.TaskName))
+		assert.Equal(t, 12, apiCP.Order)
+		assert.Equal(t, "v3", utility.FromStringPtr(apiCP.Version))
+		assert.Equal(t, "t3", utility.FromStringPtr(apiCP.TaskID))
+		assert.Equal(t, 100.0, apiCP.BeforeMean)
+		assert.Equal(t, 80.0, apiCP.AfterMean)
+		assert.Equal(t, -20.0, apiCP.PercentChange)
+		assert.Equal(t, perf.MetricTypeThroughput, utility.FromStringPtr(apiCP.MetricType))
+		assert.True(t, apiCP.Regression)
+		assert.True(t, now.Equal(utility.FromTimePtr(apiCP.DetectedAt)))
+	})
+}
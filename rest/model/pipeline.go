@@ -0,0 +1,63 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/pipeline"
+	"github.com/evergreen-ci/utility"
+)
+
+// APIPipelineRun is a pipeline's progress toward creating a downstream version
+// for one revision, git tag or manifest revision.
+type APIPipelineRun struct {
+	ID           *string `json:"id"`
+	ProjectID    *string `json:"project_id"`
+	DefinitionID *string `json:"definition_id"`
+	// The revision, git tag or manifest revision shared by the run's upstream
+	// versions.
+	MatchKey *string `json:"match_key"`
+	// One of waiting, triggered or failed.
+	Status    *string                  `json:"status"`
+	Upstreams []APIPipelineRunUpstream `json:"upstreams"`
+	// The version created once every upstream project succeeded.
+	DownstreamVersion *string `json:"downstream_version"`
+	// Why the downstream version couldn't be created.
+	Error       *string    `json:"error"`
+	CreateTime  *time.Time `json:"create_time"`
+	UpdateTime  *time.Time `json:"update_time"`
+	TriggerTime *time.Time `json:"trigger_time"`
+}
+
+// APIPipelineRunUpstream is the state of an upstream project's most recent
+// version in a pipeline run.
+type APIPipelineRunUpstream struct {
+	ProjectID *string `json:"project_id"`
+	// One of pending, succeeded or failed.
+	Status     *string    `json:"status"`
+	VersionID  *string    `json:"version_id"`
+	Revision   *string    `json:"revision"`
+	UpdateTime *time.Time `json:"update_time"`
+}
+
+func (r *APIPipelineRun) BuildFromService(run pipeline.Run) {
+	r.ID = utility.ToStringPtr(run.ID)
+	r.ProjectID = utility.ToStringPtr(run.ProjectID)
+	r.DefinitionID = utility.ToStringPtr(run.DefinitionID)
+	r.MatchKey = utility.ToStringPtr(run.MatchKey)
+	r.Status = utility.ToStringPtr(run.Status)
+	r.Upstreams = make([]APIPipelineRunUpstream, 0, len(run.Upstreams))
+	for _, u := range run.Upstreams {
+		r.Upstreams = append(r.Upstreams, APIPipelineRunUpstream{
+			ProjectID:  utility.ToStringPtr(u.ProjectID),
+			Status:     utility.ToStringPtr(u.Status),
+			VersionID:  utility.ToStringPtr(u.VersionID),
+			Revision:   utility.ToStringPtr(u.Revision),
+			UpdateTime: ToTimePtr(u.UpdateTime),
+		})
+	}
+	r.DownstreamVersion = utility.ToStringPtr(run.DownstreamVersion)
+	r.Error = utility.ToStringPtr(run.Error)
+	r.CreateTime = ToTimePtr(run.CreateTime)
+	r.UpdateTime = ToTimePtr(run.UpdateTime)
+	r.TriggerTime = ToTimePtr(run.TriggerTime)
+}
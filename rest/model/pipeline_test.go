@@ -0,0 +1,50 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/pipeline"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIPipelineRunBuildFromService(t *testing.T) {
+	now := time.Now().Round(time.Second)
+	run := pipeline.Run{
+		ID:           pipeline.MakeRunID("downstream", "def", "abc123"),
+		ProjectID:    "downstream",
+		DefinitionID: "def",
+		MatchKey:     "abc123",
+		Status:       pipeline.RunStatusTriggered,
+		Upstreams: []pipeline.Upstream{
+			{ProjectID: "up1", Status: pipeline.UpstreamStatusSucceeded, VersionID: "v1", Revision: "abc123", UpdateTime: now},
+			{ProjectID: "up2", Status: pipeline.UpstreamStatusPending},
+		},
+		DownstreamVersion: "downstream_v",
+		CreateTime:        now.Add(-time.Hour),
+		UpdateTime:        now,
+		TriggerTime:       now,
+	}
+
+	apiRun := APIPipelineRun{}
+	apiRun.BuildFromService(run)
+	assert.Equal(t, run.ID, utility.FromStringPtr(apiRun.ID))
+	assert.Equal(t, "downstream", utility.FromStringPtr(apiRun.ProjectID))
+	assert.Equal(t, "def", utility.FromStringPtr(apiRun.DefinitionID))
+	assert.Equal(t, "abc123", utility.FromStringPtr(apiRun.MatchKey))
+	assert.Equal(t, pipeline.RunStatusTriggered, utility.FromStringPtr(apiRun.Status))
+	assert.Equal(t, "downstream_v", utility.FromStringPtr(apiRun.DownstreamVersion))
+	assert.Empty(t, utility.FromStringPtr(apiRun.Error))
+	require.NotNil(t, apiRun.TriggerTime)
+	assert.True(t, now.Equal(*apiRun.TriggerTime))
+
+	require.Len(t, apiRun.Upstreams, 2)
+	assert.Equal(t, "up1", utility.FromStringPtr(apiRun.Upstreams[0].ProjectID))
+	assert.Equal(t, pipeline.UpstreamStatusSucceeded, utility.FromStringPtr(apiRun.Upstreams[0].Status))
+	assert.Equal(t, "v1", utility.FromStringPtr(apiRun.Upstreams[0].VersionID))
+	require.NotNil(t, apiRun.Upstreams[0].UpdateTime)
+	assert.Equal(t, "up2", utility.FromStringPtr(apiRun.Upstreams[1].ProjectID))
+	assert.Nil(t, apiRun.Upstreams[1].UpdateTime)
+}
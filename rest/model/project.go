@@ -93,6 +93,66 @@ func (t *APITriggerDefinition) BuildFromService(triggerDef model.TriggerDefiniti
 	t.DateCutoff = triggerDef.DateCutoff
 }
 
+type APIPipelineDefinition struct {
+	// Identifier for the definition.
+	ID *string `json:"id"`
+	// Projects that must succeed before the pipeline runs.
+	Upstreams []APIPipelineUpstream `json:"upstreams"`
+	// What the upstream versions must share: revision, git_tag, or manifest.
+	MatchBy *string `json:"match_by"`
+	// Repository (owner/repo) whose revision the upstream versions must
+	// share when matching by manifest.
+	ManifestRepo *string `json:"manifest_repo"`
+	// Project configuration file for the pipeline.
+	ConfigFile *string `json:"config_file"`
+	// Alias to run for the pipeline.
+	Alias *string `json:"alias"`
+	// Deactivate downstream versions created by this pipeline.
+	UnscheduleDownstreamVersions *bool `json:"unschedule_downstream_versions"`
+}
+
+type APIPipelineUpstream struct {
+	// Identifier of project to wait on.
+	Project *string `json:"project"`
+	// Build variant regex to match. If empty, all activated builds must succeed.
+	BuildVariantRegex *string `json:"variant_regex"`
+}
+
+func (d *APIPipelineDefinition) ToService() model.PipelineDefinition {
+	upstreams := []model.PipelineUpstream{}
+	for _, u := range d.Upstreams {
+		upstreams = append(upstreams, model.PipelineUpstream{
+			Project:           utility.FromStringPtr(u.Project),
+			BuildVariantRegex: utility.FromStringPtr(u.BuildVariantRegex),
+		})
+	}
+	return model.PipelineDefinition{
+		ID:                           utility.FromStringPtr(d.ID),
+		Upstreams:                    upstreams,
+		MatchBy:                      utility.FromStringPtr(d.MatchBy),
+		ManifestRepo:                 utility.FromStringPtr(d.ManifestRepo),
+		ConfigFile:                   utility.FromStringPtr(d.ConfigFile),
+		Alias:                        utility.FromStringPtr(d.Alias),
+		UnscheduleDownstreamVersions: utility.FromBoolPtr(d.UnscheduleDownstreamVersions),
+	}
+}
+
+func (d *APIPipelineDefinition) BuildFromService(def model.PipelineDefinition) {
+	d.ID = utility.ToStringPtr(def.ID)
+	d.Upstreams = []APIPipelineUpstream{}
+	for _, u := range def.Upstreams {
+		d.Upstreams = append(d.Upstreams, APIPipelineUpstream{
+			Project:           utility.ToStringPtr(u.Project),
+			BuildVariantRegex: utility.ToStringPtr(u.BuildVariantRegex),
+		})
+	}
+	d.MatchBy = utility.ToStringPtr(def.MatchBy)
+	d.ManifestRepo = utility.ToStringPtr(def.ManifestRepo)
+	d.ConfigFile = utility.ToStringPtr(def.ConfigFile)
+	d.Alias = utility.ToStringPtr(def.Alias)
+	d.UnscheduleDownstreamVersions = utility.ToBoolPtr(def.UnscheduleDownstreamVersions)
+}
+
 type APIPatchTriggerDefinition struct {
 	// Alias to run in the downstream project.
 	Alias *string `json:"alias"`
@@ -193,6 +253,42 @@ type APIPeriodicBuildDefinition struct {
 	Message *string `json:"message,omitempty"`
 	// Next time that the periodic build will run.
 	NextRunTime *time.Time `json:"next_run_time,omitempty"`
+	// IANA time zone, such as America/New_York, that the cron specification
+	// is evaluated in. Defaults to UTC.
+	Timezone *string `json:"timezone,omitempty"`
+	// Periods, such as release freezes, during which scheduled runs are
+	// skipped.
+	BlackoutWindows []APIPeriodicBuildBlackoutWindow `json:"blackout_windows,omitempty"`
+	// Skip a scheduled run if the tracked branch's most recent commit is the
+	// same as the last periodic build's.
+	SkipIfUnchanged *bool `json:"skip_if_unchanged,omitempty"`
+	// User who last ran the periodic build on demand.
+	LastManualRunBy *string `json:"last_manual_run_by,omitempty"`
+	// Time that the periodic build was last run on demand.
+	LastManualRunTime *time.Time `json:"last_manual_run_time,omitempty"`
+}
+
+type APIPeriodicBuildBlackoutWindow struct {
+	// Start of the blackout window.
+	Start *time.Time `json:"start"`
+	// End of the blackout window.
+	End *time.Time `json:"end"`
+	// Reason for the blackout, such as a release freeze.
+	Reason *string `json:"reason,omitempty"`
+}
+
+func (w *APIPeriodicBuildBlackoutWindow) ToService() model.PeriodicBuildBlackoutWindow {
+	return model.PeriodicBuildBlackoutWindow{
+		Start:  utility.FromTimePtr(w.Start),
+		End:    utility.FromTimePtr(w.End),
+		Reason: utility.FromStringPtr(w.Reason),
+	}
+}
+
+func (w *APIPeriodicBuildBlackoutWindow) BuildFromService(window model.PeriodicBuildBlackoutWindow) {
+	w.Start = utility.ToTimePtr(window.Start)
+	w.End = utility.ToTimePtr(window.End)
+	w.Reason = utility.ToStringPtr(window.Reason)
 }
 
 type APIExternalLink struct {
@@ -246,6 +342,13 @@ func (bd *APIPeriodicBuildDefinition) ToService() model.PeriodicBuildDefinition
 	buildDef.Alias = utility.FromStringPtr(bd.Alias)
 	buildDef.Message = utility.FromStringPtr(bd.Message)
 	buildDef.NextRunTime = utility.FromTimePtr(bd.NextRunTime)
+	buildDef.Timezone = utility.FromStringPtr(bd.Timezone)
+	for _, w := range bd.BlackoutWindows {
+		buildDef.BlackoutWindows = append(buildDef.BlackoutWindows, w.ToService())
+	}
+	buildDef.SkipIfUnchanged = utility.FromBoolPtr(bd.SkipIfUnchanged)
+	buildDef.LastManualRunBy = utility.FromStringPtr(bd.LastManualRunBy)
+	buildDef.LastManualRunTime = utility.FromTimePtr(bd.LastManualRunTime)
 	return buildDef
 }
 
@@ -257,6 +360,16 @@ func (bd *APIPeriodicBuildDefinition) BuildFromService(params model.PeriodicBuil
 	bd.Alias = utility.ToStringPtr(params.Alias)
 	bd.Message = utility.ToStringPtr(params.Message)
 	bd.NextRunTime = utility.ToTimePtr(params.NextRunTime)
+	bd.Timezone = utility.ToStringPtr(params.Timezone)
+	bd.BlackoutWindows = nil
+	for _, w := range params.BlackoutWindows {
+		window := APIPeriodicBuildBlackoutWindow{}
+		window.BuildFromService(w)
+		bd.BlackoutWindows = append(bd.BlackoutWindows, window)
+	}
+	bd.SkipIfUnchanged = utility.ToBoolPtr(params.SkipIfUnchanged)
+	bd.LastManualRunBy = utility.ToStringPtr(params.LastManualRunBy)
+	bd.LastManualRunTime = utility.ToTimePtr(params.LastManualRunTime)
 }
 
 type APICommitQueueParams struct {
@@ -507,6 +620,138 @@ func (to *APITaskOwnershipSettings) BuildFromService(settings model.TaskOwnershi
 	to.DefaultMothraTeamForBreakingCommit = utility.ToStringPtr(settings.DefaultMothraTeamForBreakingCommit)
 }
 
+type APILogRetentionSettings struct {
+	// Number of days after a task finishes that its logs are kept in full.
+	ReduceAfterDays *int `json:"reduce_after_days,omitempty"`
+	// How logs are reduced, either "tail" or "errors".
+	Reduction *string `json:"reduction,omitempty"`
+	// Number of lines kept in each log by the tail reduction.
+	TailLines *int `json:"tail_lines,omitempty"`
+	// Number of days after a task finishes that its logs are deleted.
+	DeleteAfterDays *int `json:"delete_after_days,omitempty"`
+}
+
+func (lr *APILogRetentionSettings) ToService() model.LogRetentionSettings {
+	return model.LogRetentionSettings{
+		ReduceAfterDays: utility.FromIntPtr(lr.ReduceAfterDays),
+		Reduction:       model.LogReduction(utility.FromStringPtr(lr.Reduction)),
+		TailLines:       utility.FromIntPtr(lr.TailLines),
+		DeleteAfterDays: utility.FromIntPtr(lr.DeleteAfterDays),
+	}
+}
+
+func (lr *APILogRetentionSettings) BuildFromService(settings model.LogRetentionSettings) {
+	lr.ReduceAfterDays = utility.ToIntPtr(settings.ReduceAfterDays)
+	lr.Reduction = utility.ToStringPtr(string(settings.Reduction))
+	lr.TailLines = utility.ToIntPtr(settings.TailLines)
+	lr.DeleteAfterDays = utility.ToIntPtr(settings.DeleteAfterDays)
+}
+
+type APICostBudgetSettings struct {
+	// Budget in dollars for all of the project's versions created in a UTC day.
+	DailyBudget *float64 `json:"daily_budget,omitempty"`
+	// Budget in dollars for all of the project's versions created in a UTC month.
+	MonthlyBudget *float64 `json:"monthly_budget,omitempty"`
+	// Budgets for the project's versions with a particular requester.
+	RequesterBudgets []APIRequesterCostBudget `json:"requester_budgets,omitempty"`
+	// Whether to refuse to activate patch tasks once a budget is exhausted.
+	HardStop *bool `json:"hard_stop,omitempty"`
+	// Estimated cost in dollars above which a patch must be confirmed before
+	// it's scheduled.
+	PatchConfirmationThreshold *float64 `json:"patch_confirmation_threshold,omitempty"`
+}
+
+type APIRequesterCostBudget struct {
+	// Requester of the versions the budget applies to.
+	Requester *string `json:"requester"`
+	// Budget in dollars for the versions created in a UTC day.
+	DailyBudget *float64 `json:"daily_budget,omitempty"`
+	// Budget in dollars for the versions created in a UTC month.
+	MonthlyBudget *float64 `json:"monthly_budget,omitempty"`
+}
+
+func (cb *APICostBudgetSettings) ToService() model.CostBudgetSettings {
+	settings := model.CostBudgetSettings{
+		DailyBudget:                utility.FromFloat64Ptr(cb.DailyBudget),
+		MonthlyBudget:              utility.FromFloat64Ptr(cb.MonthlyBudget),
+		HardStop:                   utility.FromBoolPtr(cb.HardStop),
+		PatchConfirmationThreshold: utility.FromFloat64Ptr(cb.PatchConfirmationThreshold),
+	}
+	for _, b := range cb.RequesterBudgets {
+		settings.RequesterBudgets = append(settings.RequesterBudgets, model.RequesterCostBudget{
+			Requester:     utility.FromStringPtr(b.Requester),
+			DailyBudget:   utility.FromFloat64Ptr(b.DailyBudget),
+			MonthlyBudget: utility.FromFloat64Ptr(b.MonthlyBudget),
+		})
+	}
+	return settings
+}
+
+func (cb *APICostBudgetSettings) BuildFromService(settings model.CostBudgetSettings) {
+	cb.DailyBudget = utility.ToFloat64Ptr(settings.DailyBudget)
+	cb.MonthlyBudget = utility.ToFloat64Ptr(settings.MonthlyBudget)
+	cb.HardStop = utility.ToBoolPtr(settings.HardStop)
+	cb.PatchConfirmationThreshold = utility.ToFloat64Ptr(settings.PatchConfirmationThreshold)
+	cb.RequesterBudgets = nil
+	for _, b := range settings.RequesterBudgets {
+		cb.RequesterBudgets = append(cb.RequesterBudgets, APIRequesterCostBudget{
+			Requester:     utility.ToStringPtr(b.Requester),
+			DailyBudget:   utility.ToFloat64Ptr(b.DailyBudget),
+			MonthlyBudget: utility.ToFloat64Ptr(b.MonthlyBudget),
+		})
+	}
+}
+
+type APIFlakyTestQuarantineSettings struct {
+	// Whether flaky tests are quarantined automatically.
+	Enabled *bool `json:"enabled,omitempty"`
+	// Flakiness score, between 0 and 1, at or above which a test is quarantined.
+	Threshold *float64 `json:"threshold,omitempty"`
+	// Number of runs a test must have before it can be quarantined.
+	MinRuns *int `json:"min_runs,omitempty"`
+	// Number of consecutive passing runs after which a quarantined test is released.
+	ReleaseAfterCleanRuns *int `json:"release_after_clean_runs,omitempty"`
+}
+
+func (fq *APIFlakyTestQuarantineSettings) ToService() model.FlakyTestQuarantineSettings {
+	return model.FlakyTestQuarantineSettings{
+		Enabled:               utility.FromBoolPtr(fq.Enabled),
+		Threshold:             utility.FromFloat64Ptr(fq.Threshold),
+		MinRuns:               utility.FromIntPtr(fq.MinRuns),
+		ReleaseAfterCleanRuns: utility.FromIntPtr(fq.ReleaseAfterCleanRuns),
+	}
+}
+
+func (fq *APIFlakyTestQuarantineSettings) BuildFromService(settings model.FlakyTestQuarantineSettings) {
+	fq.Enabled = utility.ToBoolPtr(settings.Enabled)
+	fq.Threshold = utility.ToFloat64Ptr(settings.Threshold)
+	fq.MinRuns = utility.ToIntPtr(settings.MinRuns)
+	fq.ReleaseAfterCleanRuns = utility.ToIntPtr(settings.ReleaseAfterCleanRuns)
+}
+
+type APIRepoSourceSettings struct {
+	// Kind of host the repository lives on: github (the default), gitlab or git.
+	Provider *string `json:"provider,omitempty"`
+	// Base URL of the GitLab instance, or remote URL of the git repository.
+	URL *string `json:"url,omitempty"`
+	// Name of the project variable holding the access token used to read the repository.
+	TokenVar *string `json:"token_var,omitempty"`
+}
+
+func (rs *APIRepoSourceSettings) ToService() model.RepoSourceSettings {
+	return model.RepoSourceSettings{
+		Provider: model.RepoProvider(utility.FromStringPtr(rs.Provider)),
+		URL:      utility.FromStringPtr(rs.URL),
+		TokenVar: utility.FromStringPtr(rs.TokenVar),
+	}
+}
+
+func (rs *APIRepoSourceSettings) BuildFromService(settings model.RepoSourceSettings) {
+	rs.Provider = utility.ToStringPtr(string(settings.Provider))
+	rs.URL = utility.ToStringPtr(settings.URL)
+	rs.TokenVar = utility.ToStringPtr(settings.TokenVar)
+}
+
 type APIProjectRef struct {
 	Id *string `json:"id"`
 	// GitHub org name.
@@ -574,6 +819,9 @@ type APIProjectRef struct {
 	DebugSpawnHostsDisabled *bool `json:"debug_spawn_hosts_disabled"`
 	// Use bisect stepback instead of linear.
 	StepbackBisect *bool `json:"stepback_bisect"`
+	// Bisect mainline failures across the version to find the commit that
+	// caused them.
+	CulpritFinderEnabled *bool `json:"culprit_finder_enabled"`
 	// Enable setting project aliases from version-controlled project configs.
 	VersionControlEnabled *bool `json:"version_control_enabled"`
 	// Disable stats caching.
@@ -599,6 +847,9 @@ type APIProjectRef struct {
 	Revision *string `json:"revision"`
 	// List of triggers for the project.
 	Triggers []APITriggerDefinition `json:"triggers"`
+	// List of pipelines, which trigger the project once all of their upstream
+	// projects have succeeded.
+	Pipelines []APIPipelineDefinition `json:"pipelines"`
 	// List of GitHub pull request trigger aliases.
 	GithubPRTriggerAliases []*string `json:"github_trigger_aliases"`
 	// List of GitHub merge queue trigger aliases.
@@ -633,6 +884,14 @@ type APIProjectRef struct {
 	TestSelection APITestSelectionSettings `json:"test_selection,omitzero"`
 	// Task ownership settings. This is related to Foliage Web Services (FWS).
 	TaskOwnership APITaskOwnershipSettings `json:"task_ownership,omitempty"`
+	// Log retention tiers of the project's finished tasks.
+	LogRetention APILogRetentionSettings `json:"log_retention,omitzero"`
+	// Cost budgets of the project.
+	CostBudget APICostBudgetSettings `json:"cost_budget,omitzero"`
+	// Automatic quarantine of the project's flaky tests.
+	FlakyTestQuarantine APIFlakyTestQuarantineSettings `json:"flaky_test_quarantine,omitzero"`
+	// Where the project's repository is hosted if it is not on GitHub.
+	RepoSource APIRepoSourceSettings `json:"repo_source,omitzero"`
 	// Whether or not to run every mainline commit version.
 	RunEveryMainlineCommit *bool `json:"run_every_mainline_commit,omitzero"`
 }
@@ -669,6 +928,7 @@ func (p *APIProjectRef) ToService() (*model.ProjectRef, error) {
 		WaterfallDisabled:                utility.BoolPtrCopy(p.WaterfallDisabled),
 		StepbackDisabled:                 utility.BoolPtrCopy(p.StepbackDisabled),
 		StepbackBisect:                   utility.BoolPtrCopy(p.StepbackBisect),
+		CulpritFinderEnabled:             utility.BoolPtrCopy(p.CulpritFinderEnabled),
 		VersionControlEnabled:            utility.BoolPtrCopy(p.VersionControlEnabled),
 		DisabledStatsCache:               utility.BoolPtrCopy(p.DisabledStatsCache),
 		NotifyOnBuildFailure:             utility.BoolPtrCopy(p.NotifyOnBuildFailure),
@@ -685,6 +945,10 @@ func (p *APIProjectRef) ToService() (*model.ProjectRef, error) {
 		GitHubPermissionGroupByRequester: p.GitHubPermissionGroupByRequester,
 		TestSelection:                    p.TestSelection.ToService(),
 		TaskOwnership:                    p.TaskOwnership.ToService(),
+		LogRetention:                     p.LogRetention.ToService(),
+		CostBudget:                       p.CostBudget.ToService(),
+		FlakyTestQuarantine:              p.FlakyTestQuarantine.ToService(),
+		RepoSource:                       p.RepoSource.ToService(),
 		RunEveryMainlineCommit:           p.RunEveryMainlineCommit,
 	}
 
@@ -701,6 +965,15 @@ func (p *APIProjectRef) ToService() (*model.ProjectRef, error) {
 		projectRef.Triggers = triggers
 	}
 
+	// Copy pipelines
+	if p.Pipelines != nil {
+		pipelines := []model.PipelineDefinition{}
+		for _, d := range p.Pipelines {
+			pipelines = append(pipelines, d.ToService())
+		}
+		projectRef.Pipelines = pipelines
+	}
+
 	// Copy periodic builds
 	if p.PeriodicBuilds != nil {
 		builds := []model.PeriodicBuildDefinition{}
@@ -780,6 +1053,7 @@ func (p *APIProjectRef) BuildPublicFields(ctx context.Context, projectRef model.
 	p.WaterfallDisabled = utility.BoolPtrCopy(projectRef.WaterfallDisabled)
 	p.StepbackDisabled = utility.BoolPtrCopy(projectRef.StepbackDisabled)
 	p.StepbackBisect = utility.BoolPtrCopy(projectRef.StepbackBisect)
+	p.CulpritFinderEnabled = utility.BoolPtrCopy(projectRef.CulpritFinderEnabled)
 	p.VersionControlEnabled = utility.BoolPtrCopy(projectRef.VersionControlEnabled)
 	p.DisabledStatsCache = utility.BoolPtrCopy(projectRef.DisabledStatsCache)
 	p.DebugSpawnHostsDisabled = utility.BoolPtrCopy(projectRef.DebugSpawnHostsDisabled)
@@ -793,6 +1067,10 @@ func (p *APIProjectRef) BuildPublicFields(ctx context.Context, projectRef model.
 	p.GitHubPermissionGroupByRequester = projectRef.GitHubPermissionGroupByRequester
 	p.TestSelection.BuildFromService(projectRef.TestSelection)
 	p.TaskOwnership.BuildFromService(projectRef.TaskOwnership)
+	p.LogRetention.BuildFromService(projectRef.LogRetention)
+	p.CostBudget.BuildFromService(projectRef.CostBudget)
+	p.FlakyTestQuarantine.BuildFromService(projectRef.FlakyTestQuarantine)
+	p.RepoSource.BuildFromService(projectRef.RepoSource)
 	p.RunEveryMainlineCommit = projectRef.RunEveryMainlineCommit
 
 	if projectRef.ProjectHealthView == "" {
@@ -841,6 +1119,17 @@ func (p *APIProjectRef) BuildPublicFields(ctx context.Context, projectRef model.
 		p.Triggers = triggers
 	}
 
+	// Copy pipelines
+	if projectRef.Pipelines != nil {
+		pipelines := []APIPipelineDefinition{}
+		for _, d := range projectRef.Pipelines {
+			apiPipeline := APIPipelineDefinition{}
+			apiPipeline.BuildFromService(d)
+			pipelines = append(pipelines, apiPipeline)
+		}
+		p.Pipelines = pipelines
+	}
+
 	// copy periodic builds
 	if projectRef.PeriodicBuilds != nil {
 		periodicBuilds := []APIPeriodicBuildDefinition{}
@@ -3,6 +3,7 @@ package model
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/utility"
@@ -223,3 +224,63 @@ func TestGitHubDynamicTokenPermissionGroupBuildFromService(t *testing.T) {
 		assert.True(utility.FromBoolPtr(pg.AllPermissions))
 	})
 }
+
+func TestPeriodicBuildDefinitionConversion(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	definition := model.PeriodicBuildDefinition{
+		ID:         "abc",
+		ConfigFile: "evergreen.yml",
+		Cron:       "0 2 * * *",
+		Timezone:   "America/New_York",
+		BlackoutWindows: []model.PeriodicBuildBlackoutWindow{
+			{Start: now, End: now.Add(24 * time.Hour), Reason: "release freeze"},
+		},
+		SkipIfUnchanged:   true,
+		LastManualRunBy:   "me",
+		LastManualRunTime: now,
+	}
+
+	apiDefinition := APIPeriodicBuildDefinition{}
+	apiDefinition.BuildFromService(definition)
+	assert.Equal(t, "America/New_York", utility.FromStringPtr(apiDefinition.Timezone))
+	require.Len(t, apiDefinition.BlackoutWindows, 1)
+	assert.Equal(t, "release freeze", utility.FromStringPtr(apiDefinition.BlackoutWindows[0].Reason))
+	assert.True(t, utility.FromBoolPtr(apiDefinition.SkipIfUnchanged))
+	assert.Equal(t, "me", utility.FromStringPtr(apiDefinition.LastManualRunBy))
+
+	roundTripped := apiDefinition.ToService()
+	assert.Equal(t, definition.Timezone, roundTripped.Timezone)
+	require.Len(t, roundTripped.BlackoutWindows, 1)
+	assert.True(t, definition.BlackoutWindows[0].Start.Equal(roundTripped.BlackoutWindows[0].Start))
+	assert.True(t, definition.BlackoutWindows[0].End.Equal(roundTripped.BlackoutWindows[0].End))
+	assert.Equal(t, definition.BlackoutWindows[0].Reason, roundTripped.BlackoutWindows[0].Reason)
+	assert.True(t, roundTripped.SkipIfUnchanged)
+	assert.Equal(t, definition.LastManualRunBy, roundTripped.LastManualRunBy)
+	assert.True(t, definition.LastManualRunTime.Equal(roundTripped.LastManualRunTime))
+}
+
+func TestPipelineDefinitionConversion(t *testing.T) {
+	definition := model.PipelineDefinition{
+		ID: "abc",
+		Upstreams: []model.PipelineUpstream{
+			{Project: "server"},
+			{Project: "tools", BuildVariantRegex: "^linux"},
+		},
+		MatchBy:                      model.PipelineMatchByManifest,
+		ManifestRepo:                 "mongodb/mongo",
+		ConfigFile:                   "release.yml",
+		Alias:                        "release",
+		UnscheduleDownstreamVersions: true,
+	}
+
+	apiDefinition := APIPipelineDefinition{}
+	apiDefinition.BuildFromService(definition)
+	assert.Equal(t, "abc", utility.FromStringPtr(apiDefinition.ID))
+	require.Len(t, apiDefinition.Upstreams, 2)
+	assert.Equal(t, "tools", utility.FromStringPtr(apiDefinition.Upstreams[1].Project))
+	assert.Equal(t, "^linux", utility.FromStringPtr(apiDefinition.Upstreams[1].BuildVariantRegex))
+	assert.Equal(t, model.PipelineMatchByManifest, utility.FromStringPtr(apiDefinition.MatchBy))
+	assert.Equal(t, "mongodb/mongo", utility.FromStringPtr(apiDefinition.ManifestRepo))
+
+	assert.Equal(t, definition, apiDefinition.ToService())
+}
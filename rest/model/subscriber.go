@@ -51,6 +51,18 @@ type APIWebhookSubscriber struct {
 	Headers    []APIWebhookHeader `json:"headers" mapstructure:"headers"`
 }
 
+type APITeamsSubscriber struct {
+	URL                *string `json:"url" mapstructure:"url"`
+	RateLimitPerMinute int     `json:"rate_limit_per_minute" mapstructure:"rate_limit_per_minute"`
+}
+
+type APIMattermostSubscriber struct {
+	URL                *string `json:"url" mapstructure:"url"`
+	Channel            *string `json:"channel" mapstructure:"channel"`
+	Username           *string `json:"username" mapstructure:"username"`
+	RateLimitPerMinute int     `json:"rate_limit_per_minute" mapstructure:"rate_limit_per_minute"`
+}
+
 type APIWebhookHeader struct {
 	Key   *string `json:"key" mapstructure:"key"`
 	Value *string `json:"value" mapstructure:"value"`
@@ -104,6 +116,20 @@ func (s *APISubscriber) BuildFromService(in event.Subscriber) error {
 		target = sub
 		s.JiraIssueSubscriber = &sub
 
+	case event.TeamsSubscriberType:
+		sub := APITeamsSubscriber{}
+		if err := sub.BuildFromService(in.Target); err != nil {
+			return err
+		}
+		target = sub
+
+	case event.MattermostSubscriberType:
+		sub := APIMattermostSubscriber{}
+		if err := sub.BuildFromService(in.Target); err != nil {
+			return err
+		}
+		target = sub
+
 	case event.JIRACommentSubscriberType, event.EmailSubscriberType,
 		event.SlackSubscriberType, event.RunChildPatchSubscriberType:
 		target = in.Target
@@ -183,6 +209,28 @@ func (s *APISubscriber) ToService() (event.Subscriber, error) {
 		}
 		target = apiModel.ToService()
 
+	case event.TeamsSubscriberType:
+		apiModel := APITeamsSubscriber{}
+		if err = mapstructure.Decode(s.Target, &apiModel); err != nil {
+			return event.Subscriber{}, gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    errors.Wrap(err, "Teams subscriber target is malformed").Error(),
+			}
+		}
+		sub := apiModel.ToService()
+		target = &sub
+
+	case event.MattermostSubscriberType:
+		apiModel := APIMattermostSubscriber{}
+		if err = mapstructure.Decode(s.Target, &apiModel); err != nil {
+			return event.Subscriber{}, gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    errors.Wrap(err, "Mattermost subscriber target is malformed").Error(),
+			}
+		}
+		sub := apiModel.ToService()
+		target = &sub
+
 	case event.JIRACommentSubscriberType, event.EmailSubscriberType,
 		event.SlackSubscriberType, event.RunChildPatchSubscriberType:
 		target = s.Target
@@ -319,6 +367,50 @@ func (s *APIWebhookHeader) ToService() event.WebhookHeader {
 	}
 }
 
+func (s *APITeamsSubscriber) BuildFromService(h any) error {
+	switch v := h.(type) {
+	case *event.TeamsSubscriber:
+		s.URL = utility.ToStringPtr(v.URL)
+		s.RateLimitPerMinute = v.RateLimitPerMinute
+
+	default:
+		return errors.Errorf("programmatic error: expected Teams subscriber but got type %T", h)
+	}
+
+	return nil
+}
+
+func (s *APITeamsSubscriber) ToService() event.TeamsSubscriber {
+	return event.TeamsSubscriber{
+		URL:                utility.FromStringPtr(s.URL),
+		RateLimitPerMinute: s.RateLimitPerMinute,
+	}
+}
+
+func (s *APIMattermostSubscriber) BuildFromService(h any) error {
+	switch v := h.(type) {
+	case *event.MattermostSubscriber:
+		s.URL = utility.ToStringPtr(v.URL)
+		s.Channel = utility.ToStringPtr(v.Channel)
+		s.Username = utility.ToStringPtr(v.Username)
+		s.RateLimitPerMinute = v.RateLimitPerMinute
+
+	default:
+		return errors.Errorf("programmatic error: expected Mattermost subscriber but got type %T", h)
+	}
+
+	return nil
+}
+
+func (s *APIMattermostSubscriber) ToService() event.MattermostSubscriber {
+	return event.MattermostSubscriber{
+		URL:                utility.FromStringPtr(s.URL),
+		Channel:            utility.FromStringPtr(s.Channel),
+		Username:           utility.FromStringPtr(s.Username),
+		RateLimitPerMinute: s.RateLimitPerMinute,
+	}
+}
+
 type APIJIRAIssueSubscriber struct {
 	Project   *string `json:"project" mapstructure:"project"`
 	IssueType *string `json:"issue_type" mapstructure:"issue_type"`
@@ -146,3 +146,57 @@ func TestSubscriberModelsSlack(t *testing.T) {
 	assert.NoError(err)
 	assert.EqualValues(slackSubscriber, origSlackSubscriber)
 }
+
+func TestSubscriberModelsTeams(t *testing.T) {
+	target := event.TeamsSubscriber{
+		URL:                "https://example.com/webhook",
+		RateLimitPerMinute: 10,
+	}
+	teamsSubscriber := event.NewTeamsSubscriber(target)
+	apiTeamsSubscriber := APISubscriber{}
+	require.NoError(t, apiTeamsSubscriber.BuildFromService(teamsSubscriber))
+
+	origTeamsSubscriber, err := apiTeamsSubscriber.ToService()
+	require.NoError(t, err)
+	assert.Equal(t, teamsSubscriber, origTeamsSubscriber)
+
+	// incoming subscribers have target serialized as a map
+	incoming := APISubscriber{
+		Type: utility.ToStringPtr(event.TeamsSubscriberType),
+		Target: map[string]any{
+			"url":                   "https://example.com/webhook",
+			"rate_limit_per_minute": 10,
+		},
+	}
+	serviceModel, err := incoming.ToService()
+	require.NoError(t, err)
+	assert.Equal(t, teamsSubscriber, serviceModel)
+}
+
+func TestSubscriberModelsMattermost(t *testing.T) {
+	target := event.MattermostSubscriber{
+		URL:      "https://example.com/hooks/abc",
+		Channel:  "builds",
+		Username: "evergreen",
+	}
+	mattermostSubscriber := event.NewMattermostSubscriber(target)
+	apiMattermostSubscriber := APISubscriber{}
+	require.NoError(t, apiMattermostSubscriber.BuildFromService(mattermostSubscriber))
+
+	origMattermostSubscriber, err := apiMattermostSubscriber.ToService()
+	require.NoError(t, err)
+	assert.Equal(t, mattermostSubscriber, origMattermostSubscriber)
+
+	// incoming subscribers have target serialized as a map
+	incoming := APISubscriber{
+		Type: utility.ToStringPtr(event.MattermostSubscriberType),
+		Target: map[string]any{
+			"url":      "https://example.com/hooks/abc",
+			"channel":  "builds",
+			"username": "evergreen",
+		},
+	}
+	serviceModel, err := incoming.ToService()
+	require.NoError(t, err)
+	assert.Equal(t, mattermostSubscriber, serviceModel)
+}
@@ -30,6 +30,29 @@ type APISubscription struct {
 	Owner *string `json:"owner"`
 	// Data for the particular condition that triggers the subscription.
 	TriggerData map[string]string `json:"trigger_data,omitempty"`
+	// Options for collapsing the subscription's notifications into digests.
+	Digest *APIDigestOptions `json:"digest,omitempty"`
+}
+
+type APIDigestOptions struct {
+	// How notifications are grouped into a digest: "window" collapses all
+	// notifications within the window and "version" collapses notifications
+	// for the same version.
+	Mode *string `json:"mode"`
+	// Number of minutes a digest collects notifications before it is sent.
+	WindowMinutes int `json:"window_minutes"`
+}
+
+func (d *APIDigestOptions) BuildFromService(opts event.DigestOptions) {
+	d.Mode = utility.ToStringPtr(string(opts.Mode))
+	d.WindowMinutes = opts.WindowMinutes
+}
+
+func (d *APIDigestOptions) ToService() event.DigestOptions {
+	return event.DigestOptions{
+		Mode:          event.DigestMode(utility.FromStringPtr(d.Mode)),
+		WindowMinutes: d.WindowMinutes,
+	}
 }
 
 func (s *APISelector) BuildFromService(selector event.Selector) {
@@ -51,6 +74,10 @@ func (s *APISubscription) BuildFromService(sub event.Subscription) error {
 	s.Owner = utility.ToStringPtr(sub.Owner)
 	s.OwnerType = utility.ToStringPtr(string(sub.OwnerType))
 	s.TriggerData = sub.TriggerData
+	if sub.Digest != nil {
+		s.Digest = &APIDigestOptions{}
+		s.Digest.BuildFromService(*sub.Digest)
+	}
 	err := s.Subscriber.BuildFromService(sub.Subscriber)
 	if err != nil {
 		return err
@@ -81,6 +108,10 @@ func (s *APISubscription) ToService() (event.Subscription, error) {
 		RegexSelectors: []event.Selector{},
 		TriggerData:    s.TriggerData,
 	}
+	if s.Digest != nil {
+		digest := s.Digest.ToService()
+		out.Digest = &digest
+	}
 	subscriber, err := s.Subscriber.ToService()
 	if err != nil {
 		return event.Subscription{}, err
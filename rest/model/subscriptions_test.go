@@ -54,3 +54,42 @@ func TestSubscriptionModels(t *testing.T) {
 	assert.NoError(err)
 	assert.EqualValues(subscription, origSubscription)
 }
+
+func TestSubscriptionModelsDigest(t *testing.T) {
+	subscription := event.Subscription{
+		ID:           mgobson.NewObjectId().Hex(),
+		ResourceType: event.ResourceTypeTask,
+		Trigger:      event.TriggerFailure,
+		Owner:        "me",
+		OwnerType:    event.OwnerTypePerson,
+		Selectors: []event.Selector{
+			{
+				Type: event.SelectorProject,
+				Data: "mci",
+			},
+		},
+		RegexSelectors: []event.Selector{},
+		Filter: event.Filter{
+			Project: "mci",
+		},
+		Subscriber: event.Subscriber{
+			Type:   event.SlackSubscriberType,
+			Target: "#evergreen",
+		},
+		Digest: &event.DigestOptions{
+			Mode:          event.DigestModeVersion,
+			WindowMinutes: 30,
+		},
+	}
+
+	apiSubscription := APISubscription{}
+	assert.NoError(t, apiSubscription.BuildFromService(subscription))
+	if assert.NotNil(t, apiSubscription.Digest) {
+		assert.Equal(t, string(event.DigestModeVersion), *apiSubscription.Digest.Mode)
+		assert.Equal(t, 30, apiSubscription.Digest.WindowMinutes)
+	}
+
+	origSubscription, err := apiSubscription.ToService()
+	assert.NoError(t, err)
+	assert.EqualValues(t, subscription, origSubscription)
+}
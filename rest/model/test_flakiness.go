@@ -0,0 +1,58 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/flakytest"
+	"github.com/evergreen-ci/utility"
+)
+
+// APITestFlakiness is the most recent flakiness score of a test and whether
+// it's automatically quarantined.
+type APITestFlakiness struct {
+	ProjectID    *string `json:"project_id"`
+	BuildVariant *string `json:"build_variant"`
+	TaskName     *string `json:"task_name"`
+	TestName     *string `json:"test_name"`
+	// Number of mainline runs of the test that were scored.
+	NumRuns     int `json:"num_runs"`
+	NumFailures int `json:"num_failures"`
+	// Fraction of runs that failed.
+	FailureRate float64 `json:"failure_rate"`
+	// Fraction of failures that passed when the same task was restarted.
+	RetryPassRate float64 `json:"retry_pass_rate"`
+	// Fraction of revisions with a failure that also had a passing run.
+	AlternationRate float64 `json:"alternation_rate"`
+	// Flakiness score between 0 and 1 combining the other signals.
+	Score float64 `json:"score"`
+	// The most recent task run of the test.
+	LastTaskID    *string `json:"last_task_id"`
+	LastExecution int     `json:"last_execution"`
+	// Whether the test is automatically quarantined.
+	Quarantined   bool       `json:"quarantined"`
+	QuarantinedAt *time.Time `json:"quarantined_at"`
+	// Number of consecutive passing runs since the test was quarantined.
+	CleanRuns  int        `json:"clean_runs"`
+	ReleasedAt *time.Time `json:"released_at"`
+	UpdatedAt  *time.Time `json:"updated_at"`
+}
+
+func (f *APITestFlakiness) BuildFromService(flakiness flakytest.TestFlakiness) {
+	f.ProjectID = utility.ToStringPtr(flakiness.ProjectID)
+	f.BuildVariant = utility.ToStringPtr(flakiness.BuildVariant)
+	f.TaskName = utility.ToStringPtr(flakiness.TaskName)
+	f.TestName = utility.ToStringPtr(flakiness.TestName)
+	f.NumRuns = flakiness.NumRuns
+	f.NumFailures = flakiness.NumFailures
+	f.FailureRate = flakiness.FailureRate
+	f.RetryPassRate = flakiness.RetryPassRate
+	f.AlternationRate = flakiness.AlternationRate
+	f.Score = flakiness.Score
+	f.LastTaskID = utility.ToStringPtr(flakiness.LastTaskID)
+	f.LastExecution = flakiness.LastExecution
+	f.Quarantined = flakiness.Quarantined
+	f.QuarantinedAt = ToTimePtr(flakiness.QuarantinedAt)
+	f.CleanRuns = flakiness.CleanRuns
+	f.ReleasedAt = ToTimePtr(flakiness.ReleasedAt)
+	f.UpdatedAt = ToTimePtr(flakiness.UpdatedAt)
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/flakytest"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPITestFlakinessBuildFromService(t *testing.T) {
+	now := time.Now().Round(time.Second)
+	f := flakytest.TestFlakiness{
+		ID:            "id",
+		ProjectID:     "project",
+		BuildVariant:  "bv",
+		TaskName:      "task",
+		TestName:      "test",
+		NumRuns:       10,
+		NumFailures:   3,
+		FailureRate:   0.3,
+		RetryPassRate: 1,
+		Score:         0.5,
+		LastTaskID:    "t1",
+		LastExecution: 1,
+		Quarantined:   true,
+		QuarantinedAt: now,
+		UpdatedAt:     now,
+	}
+
+	apiFlakiness := APITestFlakiness{}
+	apiFlakiness.BuildFromService(f)
+	assert.Equal(t, "project", utility.FromStringPtr(apiFlakiness.ProjectID))
+	assert.Equal(t, "bv", utility.FromStringPtr(apiFlakiness.BuildVariant))
+	assert.Equal(t, "task", utility.FromStringPtr(apiFlakiness.TaskName))
+	assert.Equal(t, "test", utility.FromStringPtr(apiFlakiness.TestName))
+	assert.Equal(t, 10, apiFlakiness.NumRuns)
+	assert.Equal(t, 3, apiFlakiness.NumFailures)
+	assert.Equal(t, 0.5, apiFlakiness.Score)
+	assert.Equal(t, "t1", utility.FromStringPtr(apiFlakiness.LastTaskID))
+	assert.True(t, apiFlakiness.Quarantined)
+	assert.True(t, now.Equal(utility.FromTimePtr(apiFlakiness.QuarantinedAt)))
+	assert.Nil(t, apiFlakiness.ReleasedAt, "zero times should not be set")
+}
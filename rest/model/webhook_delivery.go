@@ -0,0 +1,66 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/evergreen-ci/utility"
+)
+
+// APIWebhookDelivery is a single request made to deliver a webhook
+// notification.
+type APIWebhookDelivery struct {
+	// Identifier for the notification that was being delivered.
+	NotificationID *string `json:"notification_id"`
+	// URL the request was sent to.
+	URL *string `json:"url"`
+	// The notification's delivery attempt that made the request.
+	Attempt int `json:"attempt"`
+	// HTTP response code, or 0 if there was no response.
+	StatusCode int `json:"status_code"`
+	// Time in milliseconds until the response was received.
+	LatencyMS int64 `json:"latency_ms"`
+	// Error that caused the request to fail, if any.
+	Error *string `json:"error,omitempty"`
+	// Time the request was sent.
+	Time *time.Time `json:"time"`
+}
+
+func (d *APIWebhookDelivery) BuildFromService(delivery notification.WebhookDelivery) {
+	d.NotificationID = utility.ToStringPtr(delivery.NotificationID)
+	d.URL = utility.ToStringPtr(delivery.URL)
+	d.Attempt = delivery.Attempt
+	d.StatusCode = delivery.StatusCode
+	d.LatencyMS = delivery.LatencyMS
+	if delivery.Error != "" {
+		d.Error = utility.ToStringPtr(delivery.Error)
+	}
+	d.Time = ToTimePtr(delivery.Time)
+}
+
+// APIFailedNotification is a webhook notification that couldn't be delivered.
+type APIFailedNotification struct {
+	// Identifier for the notification.
+	ID *string `json:"id"`
+	// Identifier for the subscription that created the notification.
+	SubscriptionID *string `json:"subscription_id"`
+	// Either "retrying" if delivery will be attempted again or "dead-letter"
+	// if delivery was abandoned and the notification can be replayed.
+	Status *string `json:"status"`
+	// Number of times delivery has been attempted.
+	Attempts int `json:"attempts"`
+	// Error from the most recent attempt.
+	Error *string `json:"error"`
+	// Time of the next attempt for a notification that is retrying, or the
+	// time delivery was abandoned for a dead-lettered notification.
+	NextAttemptAt *time.Time `json:"next_attempt_at"`
+}
+
+func (n *APIFailedNotification) BuildFromService(notif notification.Notification) {
+	n.ID = utility.ToStringPtr(notif.ID)
+	n.SubscriptionID = utility.ToStringPtr(notif.SubscriptionID)
+	n.Status = utility.ToStringPtr(string(notif.DeliveryStatus))
+	n.Attempts = notif.DeliveryAttempts
+	n.Error = utility.ToStringPtr(notif.Error)
+	n.NextAttemptAt = ToTimePtr(notif.NextAttemptAt)
+}
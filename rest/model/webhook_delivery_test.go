@@ -0,0 +1,65 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDeliveryBuildFromService(t *testing.T) {
+	now := time.Now().Truncate(time.Millisecond)
+	t.Run("Succeeded", func(t *testing.T) {
+		apiDelivery := APIWebhookDelivery{}
+		apiDelivery.BuildFromService(notification.WebhookDelivery{
+			NotificationID: "n1",
+			URL:            "https://example.com",
+			Attempt:        2,
+			StatusCode:     200,
+			LatencyMS:      150,
+			Time:           now,
+		})
+		assert.Equal(t, "n1", utility.FromStringPtr(apiDelivery.NotificationID))
+		assert.Equal(t, "https://example.com", utility.FromStringPtr(apiDelivery.URL))
+		assert.Equal(t, 2, apiDelivery.Attempt)
+		assert.Equal(t, 200, apiDelivery.StatusCode)
+		assert.EqualValues(t, 150, apiDelivery.LatencyMS)
+		assert.Nil(t, apiDelivery.Error)
+		require.NotNil(t, apiDelivery.Time)
+		assert.True(t, now.Equal(*apiDelivery.Time))
+	})
+	t.Run("Failed", func(t *testing.T) {
+		apiDelivery := APIWebhookDelivery{}
+		apiDelivery.BuildFromService(notification.WebhookDelivery{
+			NotificationID: "n1",
+			StatusCode:     503,
+			Error:          "webhook response was 503",
+			Time:           now,
+		})
+		assert.Equal(t, 503, apiDelivery.StatusCode)
+		assert.Equal(t, "webhook response was 503", utility.FromStringPtr(apiDelivery.Error))
+	})
+}
+
+func TestFailedNotificationBuildFromService(t *testing.T) {
+	now := time.Now().Truncate(time.Millisecond)
+	apiNotification := APIFailedNotification{}
+	apiNotification.BuildFromService(notification.Notification{
+		ID:               "n1",
+		SubscriptionID:   "sub",
+		DeliveryStatus:   notification.DeliveryStatusDeadLetter,
+		DeliveryAttempts: notification.MaxWebhookDeliveryAttempts,
+		Error:            "webhook response was 503",
+		NextAttemptAt:    now,
+	})
+	assert.Equal(t, "n1", utility.FromStringPtr(apiNotification.ID))
+	assert.Equal(t, "sub", utility.FromStringPtr(apiNotification.SubscriptionID))
+	assert.Equal(t, "dead-letter", utility.FromStringPtr(apiNotification.Status))
+	assert.Equal(t, notification.MaxWebhookDeliveryAttempts, apiNotification.Attempts)
+	assert.Equal(t, "webhook response was 503", utility.FromStringPtr(apiNotification.Error))
+	require.NotNil(t, apiNotification.NextAttemptAt)
+	assert.True(t, now.Equal(*apiNotification.NextAttemptAt))
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/githubapp"
 	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/lease"
 	"github.com/evergreen-ci/evergreen/model/manifest"
 	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/evergreen/model/s3lifecycle"
@@ -1152,6 +1153,9 @@ func (h *heartbeatHandler) Run(ctx context.Context) gimlet.Responder {
 	if err := t.UpdateHeartbeat(ctx); err != nil {
 		grip.Warningf(ctx, "updating heartbeat for task %s: %+v", t.Id, err)
 	}
+	if err := lease.RenewForTask(ctx, t.Id, t.Execution, time.Now()); err != nil {
+		grip.Warningf(ctx, "renewing leases for task %s: %+v", t.Id, err)
+	}
 	return gimlet.NewJSONResponse(heartbeatResponse)
 }
 
@@ -1534,6 +1538,97 @@ func (h *keyvalIncHandler) Run(ctx context.Context) gimlet.Responder {
 	return gimlet.NewJSONResponse(keyVal)
 }
 
+// POST /task/{task_id}/lock/acquire
+type lockAcquireHandler struct {
+	req apimodels.LockRequest
+}
+
+func makeLockAcquire() gimlet.RouteHandler {
+	return &lockAcquireHandler{}
+}
+
+func (h *lockAcquireHandler) Factory() gimlet.RouteHandler {
+	return &lockAcquireHandler{}
+}
+
+func (h *lockAcquireHandler) Parse(ctx context.Context, r *http.Request) error {
+	if err := utility.ReadJSON(r.Body, &h.req); err != nil {
+		return errors.Wrap(err, "reading lock request from JSON request body")
+	}
+	if h.req.Name == "" {
+		return errors.New("lock name must be set")
+	}
+	if h.req.Slots == 0 {
+		h.req.Slots = 1
+	}
+	if h.req.Slots < 0 || h.req.Slots > lease.MaxSlots {
+		return errors.Errorf("number of slots must be between 1 and %d", lease.MaxSlots)
+	}
+	return nil
+}
+
+// Run tries once to acquire the lock for the task. If the lock is held by
+// other tasks, it responds with the current holders so that the agent can
+// log who it's waiting on before trying again.
+func (h *lockAcquireHandler) Run(ctx context.Context) gimlet.Responder {
+	t := MustHaveTask(ctx)
+	now := time.Now()
+
+	l, err := lease.Acquire(ctx, t.Project, h.req.Name, h.req.Slots, t.Id, t.Execution, now)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "acquiring lock '%s' for task '%s'", h.req.Name, t.Id))
+	}
+	if l != nil {
+		return gimlet.NewJSONResponse(apimodels.LockAcquireResponse{Acquired: true})
+	}
+
+	holders, err := lease.FindHolders(ctx, t.Project, h.req.Name, now)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding holders of lock '%s'", h.req.Name))
+	}
+	resp := apimodels.LockAcquireResponse{}
+	for _, holder := range holders {
+		resp.Holders = append(resp.Holders, apimodels.LockHolder{
+			TaskID:    holder.TaskID,
+			Execution: holder.Execution,
+			ExpiresAt: holder.ExpiresAt,
+		})
+	}
+	return gimlet.NewJSONResponse(resp)
+}
+
+// POST /task/{task_id}/lock/release
+type lockReleaseHandler struct {
+	req apimodels.LockRequest
+}
+
+func makeLockRelease() gimlet.RouteHandler {
+	return &lockReleaseHandler{}
+}
+
+func (h *lockReleaseHandler) Factory() gimlet.RouteHandler {
+	return &lockReleaseHandler{}
+}
+
+func (h *lockReleaseHandler) Parse(ctx context.Context, r *http.Request) error {
+	if err := utility.ReadJSON(r.Body, &h.req); err != nil {
+		return errors.Wrap(err, "reading lock request from JSON request body")
+	}
+	if h.req.Name == "" {
+		return errors.New("lock name must be set")
+	}
+	return nil
+}
+
+// Run releases the task's hold on the lock, if it has one.
+func (h *lockReleaseHandler) Run(ctx context.Context) gimlet.Responder {
+	t := MustHaveTask(ctx)
+	if err := lease.Release(ctx, t.Project, h.req.Name, t.Id, t.Execution); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+	return gimlet.NewJSONResponse(struct{}{})
+}
+
 // GET /task/{task_id}/manifest/load
 type manifestLoadHandler struct {
 	taskID   string
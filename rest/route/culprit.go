@@ -0,0 +1,151 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/culprit"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultBisectionsLimit = 20
+	maxBisectionsLimit     = 100
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/bisections
+
+type getProjectBisectionsHandler struct {
+	projectID string
+	limit     int
+}
+
+func makeGetProjectBisectionsHandler() gimlet.RouteHandler {
+	return &getProjectBisectionsHandler{}
+}
+
+// Factory creates an instance of the handler.
+//
+//	@Summary		Get culprit-finding bisections for a project
+//	@Description	Returns the project's most recent bisections searching for the commit that caused mainline task failures, newest first.
+//	@Tags			projects
+//	@Router			/projects/{project_id}/bisections [get]
+//	@Security		Api-User || Api-Key
+//	@Param			project_id	path	string	true	"the project ID"
+//	@Param			limit		query	int		false	"The number of bisections to return. Defaults to 20, and cannot exceed 100."
+//	@Success		200			{array}	model.APIBisection
+func (h *getProjectBisectionsHandler) Factory() gimlet.RouteHandler {
+	return &getProjectBisectionsHandler{}
+}
+
+func (h *getProjectBisectionsHandler) Parse(ctx context.Context, r *http.Request) error {
+	var err error
+	h.projectID, err = dbModel.GetIdForProject(ctx, gimlet.GetVars(r)["project_id"])
+	if err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    err.Error(),
+		}
+	}
+
+	h.limit = defaultBisectionsLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		h.limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			return errors.Wrap(err, "invalid limit")
+		}
+	}
+	if h.limit < 1 {
+		return errors.New("limit must be a positive integer")
+	}
+	if h.limit > maxBisectionsLimit {
+		return errors.Errorf("limit cannot exceed %d", maxBisectionsLimit)
+	}
+
+	return nil
+}
+
+func (h *getProjectBisectionsHandler) Run(ctx context.Context) gimlet.Responder {
+	bisections, err := culprit.FindByProject(ctx, h.projectID, h.limit)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding bisections for project '%s'", h.projectID))
+	}
+
+	apiBisections := make([]model.APIBisection, 0, len(bisections))
+	for _, b := range bisections {
+		apiBisection := model.APIBisection{}
+		apiBisection.BuildFromService(b)
+		apiBisections = append(apiBisections, apiBisection)
+	}
+
+	return gimlet.NewJSONResponse(apiBisections)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/bisections/{bisection_id}
+
+type getProjectBisectionHandler struct {
+	projectID   string
+	bisectionID string
+}
+
+func makeGetProjectBisectionHandler() gimlet.RouteHandler {
+	return &getProjectBisectionHandler{}
+}
+
+// Factory creates an instance of the handler.
+//
+//	@Summary		Get a culprit-finding bisection
+//	@Description	Returns a bisection's progress, including each version tested so far and the suspected commit once it's found.
+//	@Tags			projects
+//	@Router			/projects/{project_id}/bisections/{bisection_id} [get]
+//	@Security		Api-User || Api-Key
+//	@Param			project_id		path		string	true	"the project ID"
+//	@Param			bisection_id	path		string	true	"the bisection ID"
+//	@Success		200				{object}	model.APIBisection
+func (h *getProjectBisectionHandler) Factory() gimlet.RouteHandler {
+	return &getProjectBisectionHandler{}
+}
+
+func (h *getProjectBisectionHandler) Parse(ctx context.Context, r *http.Request) error {
+	vars := gimlet.GetVars(r)
+	var err error
+	h.projectID, err = dbModel.GetIdForProject(ctx, vars["project_id"])
+	if err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    err.Error(),
+		}
+	}
+	h.bisectionID = vars["bisection_id"]
+	if h.bisectionID == "" {
+		return errors.New("bisection ID must be specified")
+	}
+
+	return nil
+}
+
+func (h *getProjectBisectionHandler) Run(ctx context.Context) gimlet.Responder {
+	b, err := culprit.FindOneId(ctx, h.bisectionID)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding bisection '%s'", h.bisectionID))
+	}
+	if b == nil || b.ProjectID != h.projectID {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("bisection '%s' not found in project '%s'", h.bisectionID, h.projectID),
+		})
+	}
+
+	apiBisection := model.APIBisection{}
+	apiBisection.BuildFromService(*b)
+	return gimlet.NewJSONResponse(apiBisection)
+}
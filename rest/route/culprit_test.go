@@ -0,0 +1,91 @@
+package route
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/culprit"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBisectionRoutes(t *testing.T) {
+	require.NoError(t, db.ClearCollections(dbModel.ProjectRefCollection, culprit.Collection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(dbModel.ProjectRefCollection, culprit.Collection))
+	}()
+
+	for _, pRef := range []dbModel.ProjectRef{
+		{Id: "proj_id", Identifier: "proj"},
+		{Id: "other_id", Identifier: "other"},
+	} {
+		require.NoError(t, pRef.Insert(t.Context()))
+	}
+	now := time.Now()
+	for _, b := range []culprit.Bisection{
+		{ID: "old", ProjectID: "proj_id", Status: culprit.BisectionFound, SuspectedVersionID: "v1", CreatedAt: now.Add(-time.Hour)},
+		{ID: "new", ProjectID: "proj_id", Status: culprit.BisectionRunning, CreatedAt: now},
+		{ID: "other", ProjectID: "other_id", Status: culprit.BisectionRunning, CreatedAt: now},
+	} {
+		require.NoError(t, b.Insert(t.Context()))
+	}
+
+	t.Run("ListsProjectBisectionsNewestFirst", func(t *testing.T) {
+		r, err := http.NewRequest(http.MethodGet, "/projects/proj/bisections?limit=5", nil)
+		require.NoError(t, err)
+		r = gimlet.SetURLVars(r, map[string]string{"project_id": "proj"})
+
+		rh := makeGetProjectBisectionsHandler()
+		require.NoError(t, rh.Parse(t.Context(), r))
+		resp := rh.Run(t.Context())
+		require.Equal(t, http.StatusOK, resp.Status())
+		bisections, ok := resp.Data().([]model.APIBisection)
+		require.True(t, ok)
+		require.Len(t, bisections, 2)
+		assert.Equal(t, "new", utility.FromStringPtr(bisections[0].ID))
+		assert.Equal(t, "old", utility.FromStringPtr(bisections[1].ID))
+	})
+	t.Run("InvalidLimit", func(t *testing.T) {
+		r, err := http.NewRequest(http.MethodGet, "/projects/proj/bisections?limit=1000", nil)
+		require.NoError(t, err)
+		r = gimlet.SetURLVars(r, map[string]string{"project_id": "proj"})
+
+		assert.Error(t, makeGetProjectBisectionsHandler().Parse(t.Context(), r))
+	})
+	t.Run("GetsBisection", func(t *testing.T) {
+		r, err := http.NewRequest(http.MethodGet, "/projects/proj/bisections/old", nil)
+		require.NoError(t, err)
+		r = gimlet.SetURLVars(r, map[string]string{"project_id": "proj", "bisection_id": "old"})
+
+		rh := makeGetProjectBisectionHandler()
+		require.NoError(t, rh.Parse(t.Context(), r))
+		resp := rh.Run(t.Context())
+		require.Equal(t, http.StatusOK, resp.Status())
+		bisection, ok := resp.Data().(model.APIBisection)
+		require.True(t, ok)
+		assert.Equal(t, "found", utility.FromStringPtr(bisection.Status))
+		assert.Equal(t, "v1", utility.FromStringPtr(bisection.SuspectedVersionID))
+	})
+	t.Run("BisectionInOtherProjectIsNotFound", func(t *testing.T) {
+		r, err := http.NewRequest(http.MethodGet, "/projects/proj/bisections/other", nil)
+		require.NoError(t, err)
+		r = gimlet.SetURLVars(r, map[string]string{"project_id": "proj", "bisection_id": "other"})
+
+		rh := makeGetProjectBisectionHandler()
+		require.NoError(t, rh.Parse(t.Context(), r))
+		assert.Equal(t, http.StatusNotFound, rh.Run(t.Context()).Status())
+	})
+	t.Run("NonexistentProject", func(t *testing.T) {
+		r, err := http.NewRequest(http.MethodGet, "/projects/nope/bisections", nil)
+		require.NoError(t, err)
+		r = gimlet.SetURLVars(r, map[string]string{"project_id": "nope"})
+
+		assert.Error(t, makeGetProjectBisectionsHandler().Parse(t.Context(), r))
+	})
+}
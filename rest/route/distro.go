@@ -6,13 +6,16 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/model/ec2instancereferenceprice"
 	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/scheduler"
 	"github.com/evergreen-ci/evergreen/validator"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/evergreen-ci/utility"
@@ -696,3 +699,84 @@ func (rh *distroClientURLsGetHandler) Run(ctx context.Context) gimlet.Responder
 
 	return gimlet.NewJSONResponse(urls)
 }
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/distros/{distro_id}/scheduler_workload
+
+const (
+	defaultSchedulerWorkloadPeriod = 24 * time.Hour
+	maxSchedulerWorkloadTasks      = 50000
+)
+
+type distroSchedulerWorkloadHandler struct {
+	distroID string
+	start    time.Time
+	end      time.Time
+}
+
+func makeGetDistroSchedulerWorkload() gimlet.RouteHandler {
+	return &distroSchedulerWorkloadHandler{}
+}
+
+// Factory creates an instance of the handler.
+//
+//	@Summary		Record a scheduler simulator workload
+//	@Description	Records the finished tasks that were activated on the distro in the given time range as a workload that can be replayed with `evergreen admin simulate-scheduler`. At most 50,000 tasks are recorded.
+//	@Tags			distros
+//	@Router			/distros/{distro_id}/scheduler_workload [get]
+//	@Security		Api-User || Api-Key
+//	@Param			distro_id	path		string	true	"distro ID"
+//	@Param			start		query		string	false	"start of the time range in RFC3339 format, defaults to 24 hours before the end"
+//	@Param			end			query		string	false	"end of the time range in RFC3339 format, defaults to now"
+//	@Success		200			{object}	scheduler.SimulatorWorkload
+func (h *distroSchedulerWorkloadHandler) Factory() gimlet.RouteHandler {
+	return &distroSchedulerWorkloadHandler{}
+}
+
+func (h *distroSchedulerWorkloadHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.distroID = gimlet.GetVars(r)["distro_id"]
+
+	vals := r.URL.Query()
+	h.end = time.Now()
+	if end := vals.Get("end"); end != "" {
+		ts, err := time.Parse(time.RFC3339, end)
+		if err != nil {
+			return errors.Wrap(err, "parsing end time")
+		}
+		h.end = ts
+	}
+	h.start = h.end.Add(-defaultSchedulerWorkloadPeriod)
+	if start := vals.Get("start"); start != "" {
+		ts, err := time.Parse(time.RFC3339, start)
+		if err != nil {
+			return errors.Wrap(err, "parsing start time")
+		}
+		h.start = ts
+	}
+	if !h.start.Before(h.end) {
+		return errors.New("start time must be before end time")
+	}
+
+	return nil
+}
+
+func (h *distroSchedulerWorkloadHandler) Run(ctx context.Context) gimlet.Responder {
+	d, err := distro.FindOneId(ctx, h.distroID)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding distro '%s'", h.distroID))
+	}
+	if d == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("distro '%s' not found", h.distroID),
+		})
+	}
+
+	tasks, err := task.FindCompletedByDistroActivatedBetween(ctx, d.Id, h.start, h.end, maxSchedulerWorkloadTasks)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding tasks for distro '%s'", d.Id))
+	}
+
+	return gimlet.NewJSONResponse(scheduler.NewSimulatorWorkload(*d, tasks))
+}
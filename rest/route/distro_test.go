@@ -17,11 +17,14 @@ import (
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/model/user"
 	restModel "github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/scheduler"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/evergreen-ci/utility"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"go.mongodb.org/mongo-driver/bson"
 )
@@ -317,6 +320,44 @@ func TestDistroAMIHandler(t *testing.T) {
 	assert.NotEqual(t, http.StatusOK, resp.Status())
 }
 
+func TestDistroSchedulerWorkloadHandler(t *testing.T) {
+	ctx := t.Context()
+
+	require.NoError(t, db.ClearCollections(distro.Collection, task.Collection))
+	d := distro.Distro{Id: "d1", Provider: evergreen.ProviderNameEc2Fleet}
+	require.NoError(t, d.Insert(ctx))
+	now := time.Now().Round(time.Second)
+	for _, tsk := range []task.Task{
+		{Id: "recorded", DistroId: "d1", Status: evergreen.TaskSucceeded, ActivatedTime: now.Add(-time.Hour), TimeTaken: time.Minute},
+		{Id: "too_old", DistroId: "d1", Status: evergreen.TaskSucceeded, ActivatedTime: now.Add(-48 * time.Hour)},
+		{Id: "unfinished", DistroId: "d1", Status: evergreen.TaskStarted, ActivatedTime: now.Add(-time.Hour)},
+		{Id: "other_distro", DistroId: "d2", Status: evergreen.TaskSucceeded, ActivatedTime: now.Add(-time.Hour)},
+	} {
+		require.NoError(t, tsk.Insert(ctx))
+	}
+
+	h := makeGetDistroSchedulerWorkload().(*distroSchedulerWorkloadHandler)
+	r, err := http.NewRequest(http.MethodGet, "/distros/d1/scheduler_workload?end="+now.Format(time.RFC3339), nil)
+	require.NoError(t, err)
+	r = gimlet.SetURLVars(r, map[string]string{"distro_id": "d1"})
+	require.NoError(t, h.Parse(ctx, r))
+	assert.Equal(t, now.Add(-24*time.Hour), h.start)
+
+	resp := h.Run(ctx)
+	require.Equal(t, http.StatusOK, resp.Status())
+	workload, ok := resp.Data().(scheduler.SimulatorWorkload)
+	require.True(t, ok)
+	assert.Equal(t, "d1", workload.Distro.Id)
+	require.Len(t, workload.Tasks, 1)
+	assert.Equal(t, "recorded", workload.Tasks[0].ID)
+	assert.Equal(t, time.Minute, workload.Tasks[0].Duration)
+
+	r, err = http.NewRequest(http.MethodGet, "/distros/d1/scheduler_workload?start="+now.Format(time.RFC3339)+"&end="+now.Format(time.RFC3339), nil)
+	require.NoError(t, err)
+	r = gimlet.SetURLVars(r, map[string]string{"distro_id": "d1"})
+	assert.Error(t, h.Parse(ctx, r), "start must be before end")
+}
+
 ///////////////////////////////////////////////////////////////////////
 //
 // Tests for PUT /rest/v2/distros/{distro_id}
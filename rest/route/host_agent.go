@@ -14,6 +14,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/lease"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/units"
 	"github.com/evergreen-ci/evergreen/validator"
@@ -1423,6 +1424,15 @@ func (h *hostAgentEndTask) Run(ctx context.Context) gimlet.Responder {
 		return gimlet.MakeJSONInternalErrorResponder(err)
 	}
 
+	// Release any locks the task didn't release itself so that waiting tasks
+	// don't have to wait for the leases to expire.
+	if err = lease.ReleaseAllForTask(ctx, t.Id, t.Execution); err != nil {
+		grip.Error(ctx, message.WrapError(err, message.Fields{
+			"message": "could not release task's locks",
+			"task_id": t.Id,
+		}))
+	}
+
 	if evergreen.IsGithubMergeQueueRequester(t.Requester) {
 		if err = model.HandleEndTaskForGithubMergeQueueTask(ctx, t, h.details.Status); err != nil {
 			return gimlet.MakeJSONInternalErrorResponder(err)
@@ -613,6 +613,42 @@ func (p *countEstimatedGeneratedTasksHandler) Run(ctx context.Context) gimlet.Re
 	})
 }
 
+// GET /patches/{patch_id}/estimated_cost
+
+type estimatePatchCostHandler struct {
+	patchId string
+}
+
+func makeEstimatePatchCost() gimlet.RouteHandler {
+	return &estimatePatchCostHandler{}
+}
+
+// Factory creates an instance of the handler.
+//
+//	@Summary		Estimate the cost of a patch
+//	@Description	Predicts the cost of the tasks selected in a patch from their expected durations and distro pricing. If the project has a patch confirmation threshold, the response indicates whether the estimate exceeds it.
+//	@Tags			patches
+//	@Router			/patches/{patch_id}/estimated_cost [get]
+//	@Security		Api-User || Api-Key
+//	@Param			patch_id	path		string	true	"patch ID"
+//	@Success		200			{object}	model.APIPatchCostEstimate
+func (p *estimatePatchCostHandler) Factory() gimlet.RouteHandler {
+	return &estimatePatchCostHandler{}
+}
+
+func (p *estimatePatchCostHandler) Parse(ctx context.Context, r *http.Request) error {
+	p.patchId = gimlet.GetVars(r)["patch_id"]
+	return nil
+}
+
+func (p *estimatePatchCostHandler) Run(ctx context.Context) gimlet.Responder {
+	estimate, err := data.EstimatePatchCost(ctx, p.patchId, nil)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "estimating cost of patch '%s'", p.patchId))
+	}
+	return gimlet.NewJSONResponse(estimate)
+}
+
 type patchTasks struct {
 	// Optional, if sent will update the patch's description
 	Description string `json:"description"`
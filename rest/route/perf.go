@@ -0,0 +1,283 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/perf"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/poplar"
+	"github.com/evergreen-ci/utility"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultPerfResultsLimit = 1000
+	maxPerfResultsLimit     = 10000
+)
+
+// savePerfReport stores the report's results as the given task's, regardless
+// of the task information already in the report.
+func savePerfReport(ctx context.Context, t *task.Task, report *poplar.Report) (int, error) {
+	report.Project = t.Project
+	report.Version = t.Version
+	report.Order = t.RevisionOrderNumber
+	report.Variant = t.BuildVariant
+	report.TaskName = t.DisplayName
+	report.TaskID = t.Id
+	report.Execution = t.Execution
+	report.Mainline = t.Requester == evergreen.RepotrackerVersionRequester
+
+	points, err := perf.PointsFromReport(report, time.Now())
+	if err != nil {
+		return 0, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "invalid perf report").Error(),
+		}
+	}
+	if err = perf.UpsertPoints(ctx, points); err != nil {
+		return 0, errors.Wrapf(err, "saving perf results for task '%s'", t.Id)
+	}
+	return len(points), nil
+}
+
+// POST /task/{task_id}/perf/report
+type perfReportHandler struct {
+	report poplar.Report
+}
+
+func makePerfReport() gimlet.RouteHandler {
+	return &perfReportHandler{}
+}
+
+func (h *perfReportHandler) Factory() gimlet.RouteHandler {
+	return &perfReportHandler{}
+}
+
+func (h *perfReportHandler) Parse(ctx context.Context, r *http.Request) error {
+	return errors.Wrap(utility.ReadJSON(r.Body, &h.report), "reading perf report from JSON request body")
+}
+
+// Run stores the results of the perf report sent by the perf.send command.
+func (h *perfReportHandler) Run(ctx context.Context) gimlet.Responder {
+	t := MustHaveTask(ctx)
+	if _, err := savePerfReport(ctx, t, &h.report); err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+	return gimlet.NewJSONResponse(struct{}{})
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/projects/{project_id}/perf/report
+
+type projectPerfReportHandler struct {
+	report poplar.Report
+}
+
+func makeProjectPerfReportHandler() gimlet.RouteHandler {
+	return &projectPerfReportHandler{}
+}
+
+// Factory creates an instance of the handler.
+//
+//	@Summary		Send a performance report
+//	@Description	Stores the performance results in a poplar report for the report's task, which must be in the project. The results are stored as time series per build variant, task, test and metric, and mainline results are checked for change points. Results reported again for the same task replace its earlier ones.
+//	@Tags			projects
+//	@Router			/projects/{project_id}/perf/report [post]
+//	@Security		Api-User || Api-Key
+//	@Param			project_id	path	string			true	"the project ID or identifier"
+//	@Param			{object}	body	poplar.Report	true	"the poplar report, with the task_id set"
+//	@Success		200
+func (h *projectPerfReportHandler) Factory() gimlet.RouteHandler {
+	return &projectPerfReportHandler{}
+}
+
+func (h *projectPerfReportHandler) Parse(ctx context.Context, r *http.Request) error {
+	if err := utility.ReadJSON(r.Body, &h.report); err != nil {
+		return errors.Wrap(err, "reading perf report from JSON request body")
+	}
+	if h.report.TaskID == "" {
+		return errors.New("report must have a task ID")
+	}
+	return nil
+}
+
+func (h *projectPerfReportHandler) Run(ctx context.Context) gimlet.Responder {
+	projectID, projectIdentifier, err := projectFromContext(ctx)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	t, err := task.FindOneId(ctx, h.report.TaskID)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding task '%s'", h.report.TaskID))
+	}
+	if t == nil || t.Project != projectID {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("task '%s' not found in project '%s'", h.report.TaskID, projectIdentifier),
+		})
+	}
+
+	if _, err = savePerfReport(ctx, t, &h.report); err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+	return gimlet.NewJSONResponse(struct{}{})
+}
+
+// parsePerfFindOptions reads the filters shared by the routes that get a
+// project's perf results.
+func parsePerfFindOptions(r *http.Request) (perf.FindOptions, error) {
+	vals := r.URL.Query()
+	opts := perf.FindOptions{
+		Variant:  vals.Get("variant"),
+		TaskName: vals.Get("task_name"),
+		TestName: vals.Get("test_name"),
+		Metric:   vals.Get("metric"),
+		Limit:    defaultPerfResultsLimit,
+	}
+
+	var err error
+	if limitStr := vals.Get("limit"); limitStr != "" {
+		opts.Limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			return opts, errors.Wrap(err, "invalid limit")
+		}
+	}
+	if opts.Limit < 1 {
+		return opts, errors.New("limit must be a positive integer")
+	}
+	if opts.Limit > maxPerfResultsLimit {
+		return opts, errors.Errorf("limit cannot exceed %d", maxPerfResultsLimit)
+	}
+	return opts, nil
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/perf/points
+
+type getProjectPerfPointsHandler struct {
+	opts perf.FindOptions
+}
+
+func makeGetProjectPerfPointsHandler() gimlet.RouteHandler {
+	return &getProjectPerfPointsHandler{}
+}
+
+// Factory creates an instance of the handler.
+//
+//	@Summary		Get a project's performance results
+//	@Description	Returns the values of the project's performance metrics reported by each task, most recent revision first.
+//	@Tags			projects
+//	@Router			/projects/{project_id}/perf/points [get]
+//	@Security		Api-User || Api-Key
+//	@Param			project_id	path	string	true	"the project ID or identifier"
+//	@Param			variant		query	string	false	"Only return results from this build variant."
+//	@Param			task_name	query	string	false	"Only return results from tasks with this display name."
+//	@Param			test_name	query	string	false	"Only return results of this test."
+//	@Param			metric		query	string	false	"Only return results of this metric."
+//	@Param			mainline	query	bool	false	"Only return results from mainline commits, not patches."
+//	@Param			limit		query	int		false	"The number of results to return. Defaults to 1000, and cannot exceed 10000."
+//	@Success		200			{array}	model.APIPerfPoint
+func (h *getProjectPerfPointsHandler) Factory() gimlet.RouteHandler {
+	return &getProjectPerfPointsHandler{}
+}
+
+func (h *getProjectPerfPointsHandler) Parse(ctx context.Context, r *http.Request) error {
+	var err error
+	h.opts, err = parsePerfFindOptions(r)
+	if err != nil {
+		return err
+	}
+	if mainlineStr := r.URL.Query().Get("mainline"); mainlineStr != "" {
+		h.opts.MainlineOnly, err = strconv.ParseBool(mainlineStr)
+		if err != nil {
+			return errors.Wrap(err, "invalid mainline")
+		}
+	}
+	return nil
+}
+
+func (h *getProjectPerfPointsHandler) Run(ctx context.Context) gimlet.Responder {
+	projectID, projectIdentifier, err := projectFromContext(ctx)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	points, err := perf.FindPoints(ctx, projectID, h.opts)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding perf results for project '%s'", projectIdentifier))
+	}
+
+	apiPoints := make([]model.APIPerfPoint, 0, len(points))
+	for _, p := range points {
+		apiPoint := model.APIPerfPoint{}
+		apiPoint.BuildFromService(p)
+		apiPoints = append(apiPoints, apiPoint)
+	}
+	return gimlet.NewJSONResponse(apiPoints)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/perf/change_points
+
+type getProjectPerfChangePointsHandler struct {
+	opts perf.FindOptions
+}
+
+func makeGetProjectPerfChangePointsHandler() gimlet.RouteHandler {
+	return &getProjectPerfChangePointsHandler{}
+}
+
+// Factory creates an instance of the handler.
+//
+//	@Summary		Get a project's performance change points
+//	@Description	Returns the shifts found in the mainline history of the project's performance metrics, most recently found first.
+//	@Tags			projects
+//	@Router			/projects/{project_id}/perf/change_points [get]
+//	@Security		Api-User || Api-Key
+//	@Param			project_id	path	string	true	"the project ID or identifier"
+//	@Param			variant		query	string	false	"Only return change points in this build variant."
+//	@Param			task_name	query	string	false	"Only return change points in tasks with this display name."
+//	@Param			test_name	query	string	false	"Only return change points of this test."
+//	@Param			metric		query	string	false	"Only return change points of this metric."
+//	@Param			limit		query	int		false	"The number of change points to return. Defaults to 1000, and cannot exceed 10000."
+//	@Success		200			{array}	model.APIPerfChangePoint
+func (h *getProjectPerfChangePointsHandler) Factory() gimlet.RouteHandler {
+	return &getProjectPerfChangePointsHandler{}
+}
+
+func (h *getProjectPerfChangePointsHandler) Parse(ctx context.Context, r *http.Request) error {
+	var err error
+	h.opts, err = parsePerfFindOptions(r)
+	return err
+}
+
+func (h *getProjectPerfChangePointsHandler) Run(ctx context.Context) gimlet.Responder {
+	projectID, projectIdentifier, err := projectFromContext(ctx)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	changePoints, err := perf.FindChangePoints(ctx, projectID, h.opts)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding perf change points for project '%s'", projectIdentifier))
+	}
+
+	apiChangePoints := make([]model.APIPerfChangePoint, 0, len(changePoints))
+	for _, cp := range changePoints {
+		apiCP := model.APIPerfChangePoint{}
+		apiCP.BuildFromService(cp)
+		apiChangePoints = append(apiChangePoints, apiCP)
+	}
+	return gimlet.NewJSONResponse(apiChangePoints)
+}
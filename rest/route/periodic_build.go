@@ -0,0 +1,106 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/units"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/projects/{project_id}/periodic_builds/{definition_id}/run
+
+type periodicBuildRunHandler struct {
+	definitionID string
+}
+
+func makePeriodicBuildRunHandler() gimlet.RouteHandler {
+	return &periodicBuildRunHandler{}
+}
+
+// Factory creates an instance of the handler.
+//
+//	@Summary		Run a periodic build now
+//	@Description	Creates a version for the periodic build right away, on behalf of the user, who must have permission to edit the project's settings, and records who ran it and when on the definition. Runs started this way ignore the definition's blackout windows and skip_if_unchanged, and don't change when it next runs on its schedule.
+//	@Tags			projects
+//	@Router			/projects/{project_id}/periodic_builds/{definition_id}/run [post]
+//	@Security		Api-User || Api-Key
+//	@Param			project_id		path	string	true	"the project ID or identifier"
+//	@Param			definition_id	path	string	true	"the periodic build definition ID"
+//	@Success		200
+func (h *periodicBuildRunHandler) Factory() gimlet.RouteHandler {
+	return &periodicBuildRunHandler{}
+}
+
+func (h *periodicBuildRunHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.definitionID = gimlet.GetVars(r)["definition_id"]
+	return nil
+}
+
+func (h *periodicBuildRunHandler) Run(ctx context.Context) gimlet.Responder {
+	projectID, projectIdentifier, err := projectFromContext(ctx)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+	usr := MustHaveUser(ctx)
+
+	pRef, err := dbModel.FindMergedProjectRef(ctx, projectID, "", true)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding project '%s'", projectIdentifier))
+	}
+	if pRef == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("project '%s' not found", projectIdentifier),
+		})
+	}
+	found := false
+	for _, d := range pRef.PeriodicBuilds {
+		if d.ID == h.definitionID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("periodic build definition '%s' not found in project '%s'", h.definitionID, projectIdentifier),
+		})
+	}
+
+	now := time.Now()
+	j := units.NewPeriodicBuildManualRunJob(projectID, h.definitionID, usr.Username(), now)
+	if err = amboy.EnqueueUniqueJob(ctx, evergreen.GetEnvironment().RemoteQueue(), j); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "enqueueing periodic build job"))
+	}
+	// The run has already started, so failing to record it must not fail
+	// the request.
+	grip.Error(ctx, message.WrapError(dbModel.RecordPeriodicBuildManualRun(ctx, projectID, h.definitionID, usr.Username(), now), message.Fields{
+		"message":    "could not record manual run of periodic build",
+		"user":       usr.Username(),
+		"project":    projectID,
+		"definition": h.definitionID,
+		"job_id":     j.ID(),
+	}))
+
+	grip.Info(ctx, message.Fields{
+		"message":            "periodic build run on demand",
+		"user":               usr.Username(),
+		"project":            projectID,
+		"project_identifier": projectIdentifier,
+		"definition":         h.definitionID,
+		"job_id":             j.ID(),
+	})
+
+	return gimlet.NewJSONResponse(struct{}{})
+}
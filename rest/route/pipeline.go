@@ -0,0 +1,86 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/evergreen-ci/evergreen/model/pipeline"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultPipelineRunsLimit = 100
+	maxPipelineRunsLimit     = 1000
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/pipelines/runs
+
+type getProjectPipelineRunsHandler struct {
+	definitionID string
+	limit        int
+}
+
+func makeGetProjectPipelineRunsHandler() gimlet.RouteHandler {
+	return &getProjectPipelineRunsHandler{}
+}
+
+// Factory creates an instance of the handler.
+//
+//	@Summary		Get a project's pipeline runs
+//	@Description	Returns the runs of the project's pipelines, most recently updated first. A run tracks which upstream projects have succeeded for one revision, git tag or manifest revision, and the downstream version created once all of them have.
+//	@Tags			projects
+//	@Router			/projects/{project_id}/pipelines/runs [get]
+//	@Security		Api-User || Api-Key
+//	@Param			project_id		path	string	true	"the project ID or identifier"
+//	@Param			definition_id	query	string	false	"Only return runs of the pipeline with this definition ID."
+//	@Param			limit			query	int		false	"The number of runs to return. Defaults to 100, and cannot exceed 1000."
+//	@Success		200				{array}	model.APIPipelineRun
+func (h *getProjectPipelineRunsHandler) Factory() gimlet.RouteHandler {
+	return &getProjectPipelineRunsHandler{}
+}
+
+func (h *getProjectPipelineRunsHandler) Parse(ctx context.Context, r *http.Request) error {
+	vals := r.URL.Query()
+	h.definitionID = vals.Get("definition_id")
+	h.limit = defaultPipelineRunsLimit
+
+	if limitStr := vals.Get("limit"); limitStr != "" {
+		var err error
+		h.limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			return errors.Wrap(err, "invalid limit")
+		}
+	}
+	if h.limit < 1 {
+		return errors.New("limit must be a positive integer")
+	}
+	if h.limit > maxPipelineRunsLimit {
+		return errors.Errorf("limit cannot exceed %d", maxPipelineRunsLimit)
+	}
+	return nil
+}
+
+func (h *getProjectPipelineRunsHandler) Run(ctx context.Context) gimlet.Responder {
+	projectID, projectIdentifier, err := projectFromContext(ctx)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	runs, err := pipeline.FindByProject(ctx, projectID, h.definitionID, h.limit)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding pipeline runs for project '%s'", projectIdentifier))
+	}
+
+	apiRuns := make([]model.APIPipelineRun, 0, len(runs))
+	for _, run := range runs {
+		apiRun := model.APIPipelineRun{}
+		apiRun.BuildFromService(run)
+		apiRuns = append(apiRuns, apiRun)
+	}
+	return gimlet.NewJSONResponse(apiRuns)
+}
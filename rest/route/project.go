@@ -291,6 +291,15 @@ func (h *projectIDPatchHandler) Run(ctx context.Context) gimlet.Responder {
 	if err := h.newProjectRef.ValidateEnabledRepotracker(); err != nil {
 		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "validating project repotracker"))
 	}
+	if err := h.newProjectRef.LogRetention.Validate(); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "validating log retention settings"))
+	}
+	if err := h.newProjectRef.CostBudget.Validate(); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "validating cost budget settings"))
+	}
+	if err := h.newProjectRef.RepoSource.Validate(); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "validating repository source settings"))
+	}
 
 	before, err := dbModel.GetProjectSettings(ctx, h.newProjectRef)
 	if err != nil {
@@ -316,6 +325,9 @@ func (h *projectIDPatchHandler) Run(ctx context.Context) gimlet.Responder {
 	if err != nil {
 		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "merging project ref '%s' with repo settings", h.newProjectRef.Identifier))
 	}
+	if err = mergedProjectRef.ValidateFlakyTestQuarantine(); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "validating flaky test quarantine settings"))
+	}
 
 	if mergedProjectRef.Enabled {
 		settings, err := evergreen.GetConfig(ctx)
@@ -378,6 +390,9 @@ func (h *projectIDPatchHandler) Run(ctx context.Context) gimlet.Responder {
 	for i := range h.newProjectRef.Triggers {
 		catcher.Add(h.newProjectRef.Triggers[i].Validate(ctx, h.newProjectRef.Id))
 	}
+	for i := range h.newProjectRef.Pipelines {
+		catcher.Wrapf(h.newProjectRef.Pipelines[i].Validate(ctx, h.newProjectRef.Id), "invalid pipeline definition")
+	}
 	for i := range h.newProjectRef.PatchTriggerAliases {
 		h.newProjectRef.PatchTriggerAliases[i], err = dbModel.ValidateTriggerDefinition(ctx, h.newProjectRef.PatchTriggerAliases[i], h.newProjectRef.Id)
 		catcher.Add(err)
@@ -437,6 +452,8 @@ func (h *projectIDPatchHandler) Run(ctx context.Context) gimlet.Responder {
 		}
 	}
 
+	dbModel.PreservePeriodicBuildManualRuns(h.originalProject.PeriodicBuilds, h.newProjectRef.PeriodicBuilds)
+
 	// complete all updates
 	if err = h.newProjectRef.Replace(ctx); err != nil {
 		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "updating project '%s'", h.newProjectRef.Id))
@@ -0,0 +1,131 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/lease"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/locks
+
+type getProjectLocksHandler struct{}
+
+func makeGetProjectLocksHandler() gimlet.RouteHandler {
+	return &getProjectLocksHandler{}
+}
+
+// Factory creates an instance of the handler.
+//
+//	@Summary		Get a project's held locks
+//	@Description	Returns the leases on the project's locks that are currently held by tasks using the lock.acquire command.
+//	@Tags			projects
+//	@Router			/projects/{project_id}/locks [get]
+//	@Security		Api-User || Api-Key
+//	@Param			project_id	path	string	true	"the project ID or identifier"
+//	@Success		200			{array}	model.APILease
+func (h *getProjectLocksHandler) Factory() gimlet.RouteHandler {
+	return &getProjectLocksHandler{}
+}
+
+func (h *getProjectLocksHandler) Parse(ctx context.Context, r *http.Request) error {
+	return nil
+}
+
+func (h *getProjectLocksHandler) Run(ctx context.Context) gimlet.Responder {
+	projectID, projectIdentifier, err := projectFromContext(ctx)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	leases, err := lease.FindByProject(ctx, projectID, time.Now())
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding locks for project '%s'", projectIdentifier))
+	}
+
+	apiLeases := make([]model.APILease, 0, len(leases))
+	for _, l := range leases {
+		apiLease := model.APILease{}
+		apiLease.BuildFromService(l)
+		apiLeases = append(apiLeases, apiLease)
+	}
+
+	return gimlet.NewJSONResponse(apiLeases)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// DELETE /rest/v2/projects/{project_id}/locks/{lock_name}
+
+type deleteProjectLockHandler struct {
+	lockName string
+}
+
+func makeDeleteProjectLockHandler() gimlet.RouteHandler {
+	return &deleteProjectLockHandler{}
+}
+
+// Factory creates an instance of the handler.
+//
+//	@Summary		Force release a project lock
+//	@Description	Releases the project's lock regardless of which tasks hold it, so that waiting tasks can acquire it. Tasks that held the lock are not notified and keep running.
+//	@Tags			projects
+//	@Router			/projects/{project_id}/locks/{lock_name} [delete]
+//	@Security		Api-User || Api-Key
+//	@Param			project_id	path	string	true	"the project ID or identifier"
+//	@Param			lock_name	path	string	true	"the lock name"
+//	@Success		200
+func (h *deleteProjectLockHandler) Factory() gimlet.RouteHandler {
+	return &deleteProjectLockHandler{}
+}
+
+func (h *deleteProjectLockHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.lockName = gimlet.GetVars(r)["lock_name"]
+	return nil
+}
+
+func (h *deleteProjectLockHandler) Run(ctx context.Context) gimlet.Responder {
+	projectID, projectIdentifier, err := projectFromContext(ctx)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	holders, err := lease.FindHolders(ctx, projectID, h.lockName, time.Now())
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding holders of lock '%s' in project '%s'", h.lockName, projectIdentifier))
+	}
+	if len(holders) == 0 {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("lock '%s' is not held in project '%s'", h.lockName, projectIdentifier),
+		})
+	}
+
+	if err = lease.ForceRelease(ctx, projectID, h.lockName); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+
+	holderIDs := make([]string, 0, len(holders))
+	for _, holder := range holders {
+		holderIDs = append(holderIDs, holder.TaskID)
+	}
+	grip.Info(ctx, message.Fields{
+		"message":            "force released project lock",
+		"user":               MustHaveUser(ctx).Username(),
+		"project":            projectID,
+		"project_identifier": projectIdentifier,
+		"lock":               h.lockName,
+		"holders":            holderIDs,
+	})
+
+	return gimlet.NewJSONResponse(struct{}{})
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 
+	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/validator"
 	"github.com/evergreen-ci/gimlet"
@@ -90,3 +91,95 @@ func (v *validateProjectHandler) Run(ctx context.Context) gimlet.Responder {
 	}
 	return gimlet.NewJSONResponse(validator.ValidationErrors{})
 }
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/validate/generated
+
+type validateGeneratedProjectHandler struct {
+	settings *evergreen.Settings
+	input    validator.GeneratedValidationInput
+}
+
+func makeValidateGeneratedProject(settings *evergreen.Settings) gimlet.RouteHandler {
+	return &validateGeneratedProjectHandler{settings: settings}
+}
+
+// Factory creates an instance of the handler.
+//
+//	@Summary		Dry run generate.tasks
+//	@Description	Merge generated JSON files into a project configuration file the way generate.tasks would, without saving anything. Returns the variants, tasks and dependencies that would be added, and any errors that would cause generate.tasks to fail.
+//	@Tags			projects
+//	@Router			/validate/generated [post]
+//	@Security		Api-User || Api-Key
+//	@Param			{object}	body		validator.GeneratedValidationInput	true	"parameters"
+//	@Success		200			{object}	validator.GeneratedValidationResult
+func (v *validateGeneratedProjectHandler) Factory() gimlet.RouteHandler {
+	return &validateGeneratedProjectHandler{settings: v.settings}
+}
+
+func (v *validateGeneratedProjectHandler) Parse(ctx context.Context, r *http.Request) error {
+	if err := utility.ReadJSON(r.Body, &v.input); err != nil {
+		return errors.Wrap(err, "reading generated project validation input from JSON request body")
+	}
+	if len(v.input.ProjectYaml) == 0 {
+		return errors.New("project config must be specified")
+	}
+	if len(v.input.Generated) == 0 {
+		return errors.New("at least one generated file must be specified")
+	}
+	return nil
+}
+
+func (v *validateGeneratedProjectHandler) Run(ctx context.Context) gimlet.Responder {
+	result := validator.GeneratedValidationResult{}
+	addError := func(err error) {
+		result.Errors = append(result.Errors, validator.ValidationError{
+			Level:   validator.Error,
+			Message: err.Error(),
+		})
+	}
+
+	project := &model.Project{}
+	opts := &model.GetProjectOpts{
+		ReadFileFrom: model.ReadFromLocal,
+	}
+	pp, err := model.LoadProjectInto(ctx, v.input.ProjectYaml, opts, v.input.ProjectID, project)
+	if err != nil {
+		addError(errors.Wrap(err, "loading project config"))
+		return gimlet.NewJSONResponse(result)
+	}
+
+	generated := make([]model.GeneratedProject, 0, len(v.input.Generated))
+	for i, data := range v.input.Generated {
+		g, err := model.ParseProjectFromJSONString(data)
+		if err != nil {
+			addError(errors.Wrapf(err, "parsing generated file %d", i))
+			continue
+		}
+		generated = append(generated, g)
+	}
+	if len(result.Errors) > 0 {
+		return gimlet.NewJSONResponse(result)
+	}
+	merged, err := model.MergeGeneratedProjects(ctx, generated)
+	if err != nil {
+		addError(errors.Wrap(err, "merging generated files"))
+		return gimlet.NewJSONResponse(result)
+	}
+
+	newProject, diff, err := merged.DryRun(ctx, project, pp, v.settings.TaskLimits.MaxTasksPerVersion)
+	if diff != nil {
+		result.Diff.BuildFromService(*diff)
+	}
+	if err != nil {
+		addError(err)
+	}
+	if newProject != nil {
+		projectRef, err := model.FindMergedProjectRefSecondary(ctx, v.input.ProjectID, "", false)
+		errs := validator.CheckProject(ctx, newProject, pp.MergedProjectConfig(""), projectRef, v.input.ProjectID, err)
+		result.Errors = append(result.Errors, errs.AtLevel(validator.Error)...)
+	}
+
+	return gimlet.NewJSONResponse(result)
+}
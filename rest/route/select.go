@@ -55,6 +55,7 @@ func (t *selectTestsHandler) Parse(ctx context.Context, r *http.Request) error {
 	catcher.NewWhen(t.selectTests.BuildVariant == "", "build variant is required")
 	catcher.NewWhen(t.selectTests.TaskID == "", "task ID is required")
 	catcher.NewWhen(t.selectTests.TaskName == "", "task name is required")
+	catcher.ErrorfWhen(data.UsesHistoryStrategy(t.selectTests) && len(t.selectTests.Strategies) > 1, "strategy '%s' cannot be combined with other strategies", data.HistoryStrategy)
 	return catcher.Resolve()
 }
 
@@ -102,6 +102,30 @@ func TestSelectTestsHandler(t *testing.T) {
 	req, _ = http.NewRequest(http.MethodPost, "/select/tests", bytes.NewBuffer(j))
 	sth = makeSelectTestsHandler(env)
 	require.Error(t, sth.Parse(ctx, req), "request should fail to parse when task name is missing")
+
+	j = []byte(`{
+		"project_id": "my-project",
+		"requester": "patch",
+		"build_variant": "variant",
+		"task_id": "my-task-1234",
+		"task_name": "my-task",
+		"strategies": ["evergreen_history"]
+	}`)
+	req, _ = http.NewRequest(http.MethodPost, "/select/tests", bytes.NewBuffer(j))
+	sth = makeSelectTestsHandler(env)
+	require.NoError(t, sth.Parse(ctx, req), "request should parse successfully with the built-in history strategy")
+
+	j = []byte(`{
+		"project_id": "my-project",
+		"requester": "patch",
+		"build_variant": "variant",
+		"task_id": "my-task-1234",
+		"task_name": "my-task",
+		"strategies": ["evergreen_history", "other_strategy"]
+	}`)
+	req, _ = http.NewRequest(http.MethodPost, "/select/tests", bytes.NewBuffer(j))
+	sth = makeSelectTestsHandler(env)
+	require.Error(t, sth.Parse(ctx, req), "request should fail to parse when the built-in history strategy is combined with other strategies")
 }
 
 func TestMakeSelectTestsErrorResponse(t *testing.T) {
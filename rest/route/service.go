@@ -106,6 +106,9 @@ func AttachHandler(app *gimlet.APIApp, opts HandlerOpts) {
 	app.AddRoute("/task/{task_id}/installation_token/{owner}/{repo}").Version(2).Get().Wrap(requireUserOrTask, rateLimit).RouteHandler(makeCreateInstallationToken(env))
 	app.AddRoute("/task/{task_id}/github_dynamic_access_token/{owner}/{repo}").Version(2).Post().Wrap(requireUserOrTask, rateLimit).RouteHandler(makeCreateGitHubDynamicAccessToken(env))
 	app.AddRoute("/task/{task_id}/keyval/inc").Version(2).Post().Wrap(requireTask, rateLimit).RouteHandler(makeKeyvalPluginInc())
+	app.AddRoute("/task/{task_id}/lock/acquire").Version(2).Post().Wrap(requireTask, rateLimit).RouteHandler(makeLockAcquire())
+	app.AddRoute("/task/{task_id}/lock/release").Version(2).Post().Wrap(requireTask, rateLimit).RouteHandler(makeLockRelease())
+	app.AddRoute("/task/{task_id}/perf/report").Version(2).Post().Wrap(requireTask, rateLimit).RouteHandler(makePerfReport())
 	app.AddRoute("/task/{task_id}/manifest/load").Version(2).Get().Wrap(requireUserOrTask, rateLimit).RouteHandler(makeManifestLoad(settings))
 	app.AddRoute("/task/{task_id}/update_push_status").Version(2).Post().Wrap(requireTask, rateLimit).RouteHandler(makeUpdatePushStatus())
 	app.AddRoute("/task/{task_id}/restart").Version(2).Post().Wrap(requireTask, rateLimit).RouteHandler(makeMarkTaskForRestart())
@@ -151,6 +154,7 @@ func AttachHandler(app *gimlet.APIApp, opts HandlerOpts) {
 	app.AddRoute("/distros/{distro_id}").Version(2).Put().Wrap(requireUser, createDistro, rateLimit).RouteHandler(makePutDistro())
 	app.AddRoute("/distros/{distro_id}/setup").Version(2).Get().Wrap(requireUser, editDistroSettings, rateLimit).RouteHandler(makeGetDistroSetup())
 	app.AddRoute("/distros/{distro_id}/setup").Version(2).Patch().Wrap(requireUser, editDistroSettings, rateLimit).RouteHandler(makeChangeDistroSetup())
+	app.AddRoute("/distros/{distro_id}/scheduler_workload").Version(2).Get().Wrap(requireUser, editDistroSettings, rateLimit).RouteHandler(makeGetDistroSchedulerWorkload())
 	app.AddRoute("/distros/{distro_id}/copy/{new_distro_id}").Version(2).Put().Wrap(requireUser, editDistroSettings, rateLimit).RouteHandler(makeCopyDistro())
 
 	app.AddRoute("/hooks/github").Version(2).Post().Wrap(requireValidGithubPayload, rateLimit).RouteHandler(makeGithubHooksRoute(sc, opts.APIQueue, opts.GithubSecret, settings))
@@ -190,6 +194,7 @@ func AttachHandler(app *gimlet.APIApp, opts HandlerOpts) {
 	app.AddRoute("/patches/{patch_id}/raw").Version(2).Get().Wrap(requireUser, viewTasks, rateLimit).RouteHandler(makePatchRawHandler())
 	app.AddRoute("/patches/{patch_id}/raw_modules").Version(2).Get().Wrap(requireUser, viewTasks, rateLimit).RouteHandler(makeModuleRawHandler())
 	app.AddRoute("/patches/{patch_id}/restart").Version(2).Post().Wrap(requireUser, submitPatches, rateLimit).RouteHandler(makeRestartPatch())
+	app.AddRoute("/patches/{patch_id}/estimated_cost").Version(2).Get().Wrap(requireUser, viewTasks, rateLimit).RouteHandler(makeEstimatePatchCost())
 	app.AddRoute("/patches/{patch_id}/estimated_generated_tasks").Version(2).Get().Wrap(requireUser, rateLimit).RouteHandler(makeCountEstimatedGeneratedTasks())
 	app.AddRoute("/projects").Version(2).Get().Wrap(requireUser, rateLimit).RouteHandler(makeFetchProjectsRoute())
 	app.AddRoute("/projects/test_alias").Version(2).Get().Wrap(requireUser, rateLimit).RouteHandler(makeGetProjectAliasResultsHandler())
@@ -207,11 +212,21 @@ func AttachHandler(app *gimlet.APIApp, opts HandlerOpts) {
 	app.AddRoute("/projects/{project_id}/revisions/{commit_hash}/tasks").Version(2).Get().Wrap(requireUser, viewTasks, rateLimit).RouteHandler(makeTasksByProjectAndCommitHandler(parsleyURL))
 	app.AddRoute("/projects/{project_id}/task_reliability").Version(2).Get().Wrap(requireUser, rateLimit).RouteHandler(makeGetProjectTaskReliability())
 	app.AddRoute("/projects/{project_id}/task_stats").Version(2).Get().Wrap(requireUser, viewTasks, rateLimit).RouteHandler(makeGetProjectTaskStats())
+	app.AddRoute("/projects/{project_id}/bisections").Version(2).Get().Wrap(requireUser, viewTasks, rateLimit).RouteHandler(makeGetProjectBisectionsHandler())
+	app.AddRoute("/projects/{project_id}/bisections/{bisection_id}").Version(2).Get().Wrap(requireUser, viewTasks, rateLimit).RouteHandler(makeGetProjectBisectionHandler())
+	app.AddRoute("/projects/{project_id}/test_flakiness").Version(2).Get().Wrap(requireUser, viewTasks, rateLimit).RouteHandler(makeGetProjectTestFlakinessHandler())
 	app.AddRoute("/projects/{project_id}/versions").Version(2).Get().Wrap(requireUser, viewTasks, rateLimit).RouteHandler(makeGetProjectVersionsHandler())
 	app.AddRoute("/projects/{project_id}/versions").Version(2).Patch().Wrap(requireUser, requireProjectAdmin, rateLimit).RouteHandler(makeModifyProjectVersionsHandler())
 	app.AddRoute("/projects/{project_id}/tasks/{task_name}").Version(2).Get().Wrap(requireUser, viewTasks, rateLimit).RouteHandler(makeGetProjectTasksHandler())
 	app.AddRoute("/projects/{project_id}/task_executions").Version(2).Get().Wrap(requireUser, viewTasks, rateLimit).RouteHandler(makeGetProjectTaskExecutionsHandler())
 	app.AddRoute("/projects/{project_id}/patch_trigger_aliases").Version(2).Get().Wrap(requireUser, viewTasks, rateLimit).RouteHandler(makeFetchPatchTriggerAliases())
+	app.AddRoute("/projects/{project_id}/locks").Version(2).Get().Wrap(requireUser, addProject, viewTasks, rateLimit).RouteHandler(makeGetProjectLocksHandler())
+	app.AddRoute("/projects/{project_id}/locks/{lock_name}").Version(2).Delete().Wrap(requireUser, addProject, editTasks, rateLimit).RouteHandler(makeDeleteProjectLockHandler())
+	app.AddRoute("/projects/{project_id}/perf/report").Version(2).Post().Wrap(requireUser, addProject, editTasks, rateLimit).RouteHandler(makeProjectPerfReportHandler())
+	app.AddRoute("/projects/{project_id}/perf/points").Version(2).Get().Wrap(requireUser, addProject, viewTasks, rateLimit).RouteHandler(makeGetProjectPerfPointsHandler())
+	app.AddRoute("/projects/{project_id}/perf/change_points").Version(2).Get().Wrap(requireUser, addProject, viewTasks, rateLimit).RouteHandler(makeGetProjectPerfChangePointsHandler())
+	app.AddRoute("/projects/{project_id}/periodic_builds/{definition_id}/run").Version(2).Post().Wrap(requireUser, addProject, editProjectSettings, rateLimit).RouteHandler(makePeriodicBuildRunHandler())
+	app.AddRoute("/projects/{project_id}/pipelines/runs").Version(2).Get().Wrap(requireUser, addProject, viewTasks, rateLimit).RouteHandler(makeGetProjectPipelineRunsHandler())
 	app.AddRoute("/projects/{project_id}/parameters").Version(2).Get().Wrap(requireUser, viewTasks, rateLimit).RouteHandler(makeFetchParameters())
 	app.AddRoute("/projects/{project_id}/variants/{variant_name}/quarantine").Version(2).Post().Wrap(requireUser, addProject, editTasks, rateLimit).RouteHandler(makeVariantQuarantineHandler())
 	app.AddRoute("/projects/{project_id}/variants/{variant_name}/unquarantine").Version(2).Post().Wrap(requireUser, addProject, editTasks, rateLimit).RouteHandler(makeVariantUnquarantineHandler())
@@ -229,6 +244,9 @@ func AttachHandler(app *gimlet.APIApp, opts HandlerOpts) {
 	app.AddRoute("/subscriptions").Version(2).Delete().Wrap(requireUser, rateLimit).RouteHandler(makeDeleteSubscription())
 	app.AddRoute("/subscriptions").Version(2).Get().Wrap(requireUser, rateLimit).RouteHandler(makeFetchSubscription())
 	app.AddRoute("/subscriptions").Version(2).Post().Wrap(requireUser, rateLimit).RouteHandler(makeSetSubscription())
+	app.AddRoute("/subscriptions/{subscription_id}/failed_notifications").Version(2).Get().Wrap(requireUser, rateLimit).RouteHandler(makeFetchFailedNotifications())
+	app.AddRoute("/subscriptions/{subscription_id}/failed_notifications/replay").Version(2).Post().Wrap(requireUser, rateLimit).RouteHandler(makeReplayFailedNotifications())
+	app.AddRoute("/subscriptions/{subscription_id}/webhook_deliveries").Version(2).Get().Wrap(requireUser, rateLimit).RouteHandler(makeFetchWebhookDeliveries())
 	app.AddRoute("/tasks/{task_id}").Version(2).Get().Wrap(requireUser, viewTasks, rateLimit).RouteHandler(makeGetTaskRoute(parsleyURL))
 	app.AddRoute("/tasks/{task_id}").Version(2).Patch().Wrap(requireUser, addProject, editTasks, rateLimit).RouteHandler(makeModifyTaskRoute())
 	// No auth or rate-limit middleware: this endpoint is hit by plain curl from tasks, so it uses in-band HMAC token authentication.
@@ -265,6 +283,7 @@ func AttachHandler(app *gimlet.APIApp, opts HandlerOpts) {
 	app.AddRoute("/users/{user_id}/permission-details").Version(2).Get().Wrap(requireUser, rateLimit).RouteHandler(makeGetUserPermissionDetails(env.RoleManager()))
 	app.AddRoute("/users/{user_id}/roles").Version(2).Post().Wrap(requireUser, editRoles, rateLimit).RouteHandler(makeModifyUserRoles(env.RoleManager()))
 	app.AddRoute("/validate").Version(2).Post().Wrap(requireUser, rateLimit).RouteHandler(makeValidateProject())
+	app.AddRoute("/validate/generated").Version(2).Post().Wrap(requireUser, rateLimit).RouteHandler(makeValidateGeneratedProject(settings))
 	app.AddRoute("/versions/{version_id}").Version(2).Get().Wrap(requireUser, viewTasks, rateLimit).RouteHandler(makeGetVersionByID())
 	app.AddRoute("/versions/{version_id}").Version(2).Patch().Wrap(requireUser, editTasks, rateLimit).RouteHandler(makePatchVersion())
 	app.AddRoute("/versions/{version_id}/abort").Version(2).Post().Wrap(requireUser, editTasks, rateLimit).RouteHandler(makeAbortVersion())
@@ -13,6 +13,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/grip/level"
 	"github.com/pkg/errors"
 )
 
@@ -22,6 +23,7 @@ type getTaskOutputLogsBaseHandler struct {
 	end           *int64
 	lineLimit     int
 	tailN         int
+	search        *log.SearchOptions
 	printTime     bool
 	printPriority bool
 	paginate      bool
@@ -92,6 +94,30 @@ func (h *getTaskOutputLogsBaseHandler) parse(ctx context.Context, r *http.Reques
 		}
 	}
 
+	if pattern := vals.Get("search"); pattern != "" {
+		h.search = &log.SearchOptions{
+			Pattern:         pattern,
+			Regex:           strings.ToLower(vals.Get("search_regex")) == "true",
+			CaseInsensitive: strings.ToLower(vals.Get("search_case_insensitive")) == "true",
+		}
+		if minPriority := vals.Get("min_priority"); minPriority != "" {
+			priority, err := strconv.Atoi(minPriority)
+			if err != nil {
+				return errors.Wrap(err, "parsing min priority")
+			}
+			h.search.MinPriority = level.Priority(priority)
+		}
+		if contextLines := vals.Get("context_lines"); contextLines != "" {
+			h.search.ContextLines, err = strconv.Atoi(contextLines)
+			if err != nil {
+				return errors.Wrap(err, "parsing context lines")
+			}
+		}
+		if err = h.search.Validate(); err != nil {
+			return errors.Wrap(err, "invalid search")
+		}
+	}
+
 	h.printTime = strings.ToLower(vals.Get("print_time")) == "true"
 	h.printPriority = strings.ToLower(vals.Get("print_priority")) == "true"
 	h.paginate = strings.ToLower(vals.Get("paginate")) == "true"
@@ -111,6 +137,9 @@ func (h *getTaskOutputLogsBaseHandler) parse(ctx context.Context, r *http.Reques
 	if count > 1 {
 		return errors.New("cannot set more than of: line limit, tail, paginate")
 	}
+	if h.search != nil && h.paginate {
+		return errors.New("cannot paginate search results")
+	}
 
 	return nil
 }
@@ -189,6 +218,11 @@ func makeGetTaskLogs() *getTaskLogsHandler {
 //	@Param			print_time		query		bool	false	"If set to true, returns log lines prefixed with their timestamp."
 //	@Param			print_priority	query		bool	false	"If set to true, returns log lines prefixed with their priority."
 //	@Param			paginate		query		bool	false	"If set to true, paginates the response."
+//	@Param			search			query		string	false	"If set, only returns log lines containing this substring, along with any requested context lines. Cannot be combined with paginate."
+//	@Param			search_regex	query		bool	false	"If set to true, interprets search as an RE2 regular expression."
+//	@Param			search_case_insensitive	query	bool	false	"If set to true, matches search regardless of case."
+//	@Param			min_priority	query		int		false	"If set with search, ignores log lines below this priority."
+//	@Param			context_lines	query		int		false	"If set with search, the number of lines to return before and after each matching line. Cannot exceed 100."
 //	@Success		200				{string}	string
 func (h *getTaskLogsHandler) Factory() gimlet.RouteHandler {
 	return &getTaskLogsHandler{}
@@ -215,6 +249,7 @@ func (h *getTaskLogsHandler) Run(ctx context.Context) gimlet.Responder {
 		End:       h.end,
 		LineLimit: h.lineLimit,
 		TailN:     h.tailN,
+		Search:    h.search,
 	})
 	if err != nil {
 		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "getting task logs"))
@@ -252,6 +287,11 @@ func makeGetTestLogs() *getTestLogsHandler {
 //	@Param			print_time		query		bool	false	"If set to true, returns log lines prefixed with their timestamp."
 //	@Param			print_priority	query		bool	false	"If set to true, returns log lines prefixed with their priority."
 //	@Param			paginate		query		bool	false	"If set to true, paginates the response."
+//	@Param			search			query		string	false	"If set, only returns log lines containing this substring, along with any requested context lines. Cannot be combined with paginate."
+//	@Param			search_regex	query		bool	false	"If set to true, interprets search as an RE2 regular expression."
+//	@Param			search_case_insensitive	query	bool	false	"If set to true, matches search regardless of case."
+//	@Param			min_priority	query		int		false	"If set with search, ignores log lines below this priority."
+//	@Param			context_lines	query		int		false	"If set with search, the number of lines to return before and after each matching line. Cannot exceed 100."
 //	@Success		200				{string}	string
 func (h *getTestLogsHandler) Factory() gimlet.RouteHandler {
 	return &getTestLogsHandler{}
@@ -274,6 +314,7 @@ func (h *getTestLogsHandler) Run(ctx context.Context) gimlet.Responder {
 		End:       h.end,
 		LineLimit: h.lineLimit,
 		TailN:     h.tailN,
+		Search:    h.search,
 	})
 	if err != nil {
 		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "getting task logs"))
@@ -8,10 +8,12 @@ import (
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/log"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/grip/level"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -120,6 +122,27 @@ func TestGetTaskOutputLogsBaseHandlerParse(t *testing.T) {
 			hasErr:   true,
 			errCode:  400,
 		},
+		{
+			name:     "InvalidSearchRegex",
+			taskID:   "task",
+			urlQuery: "search=(&search_regex=true",
+			hasErr:   true,
+			errCode:  400,
+		},
+		{
+			name:     "InvalidContextLines",
+			taskID:   "task",
+			urlQuery: "search=error&context_lines=NaN",
+			hasErr:   true,
+			errCode:  400,
+		},
+		{
+			name:     "SearchAndPaginateSet",
+			taskID:   "task",
+			urlQuery: "search=error&paginate=true",
+			hasErr:   true,
+			errCode:  400,
+		},
 		{
 			name:     "DefaultParameters",
 			taskID:   "task",
@@ -164,6 +187,22 @@ func TestGetTaskOutputLogsBaseHandlerParse(t *testing.T) {
 				paginate:      true,
 			},
 		},
+		{
+			name:     "ValidParametersWithSearch",
+			taskID:   "task",
+			urlQuery: "search=fatal+error&search_regex=true&search_case_insensitive=true&min_priority=40&context_lines=5&tail_limit=10",
+			expected: &getTaskOutputLogsBaseHandler{
+				tsk:   task1,
+				tailN: 10,
+				search: &log.SearchOptions{
+					Pattern:         "fatal error",
+					Regex:           true,
+					CaseInsensitive: true,
+					MinPriority:     level.Info,
+					ContextLines:    5,
+				},
+			},
+		},
 	} {
 		t.Run(test.name, func(t *testing.T) {
 			url, err := url.Parse(fmt.Sprintf("https://evergreen.mongodb.com/rest/v2/tasks/%s/build/task_logs?%s", test.taskID, test.urlQuery))
@@ -198,6 +237,7 @@ func TestGetTaskOutputLogsBaseHandlerParse(t *testing.T) {
 				assert.Equal(t, test.expected.printTime, rh.printTime)
 				assert.Equal(t, test.expected.printPriority, rh.printPriority)
 				assert.Equal(t, test.expected.paginate, rh.paginate)
+				assert.Equal(t, test.expected.search, rh.search)
 				assert.Equal(t, 10*1024*1024, rh.softSizeLimit)
 				assert.Equal(t, time.UTC, rh.timeZone)
 			}
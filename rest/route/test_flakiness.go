@@ -0,0 +1,101 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/flakytest"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultTestFlakinessLimit = 100
+	maxTestFlakinessLimit     = 1000
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/test_flakiness
+
+type getProjectTestFlakinessHandler struct {
+	projectID string
+	opts      flakytest.FindOptions
+}
+
+func makeGetProjectTestFlakinessHandler() gimlet.RouteHandler {
+	return &getProjectTestFlakinessHandler{}
+}
+
+// Factory creates an instance of the handler.
+//
+//	@Summary		Get flakiness scores for a project's tests
+//	@Description	Returns the most recent flakiness scores of the project's tests, most flaky first, and whether each test is automatically quarantined.
+//	@Tags			projects
+//	@Router			/projects/{project_id}/test_flakiness [get]
+//	@Security		Api-User || Api-Key
+//	@Param			project_id		path	string	true	"the project ID"
+//	@Param			build_variant	query	string	false	"Only return tests in this build variant."
+//	@Param			task_name		query	string	false	"Only return tests in tasks with this display name."
+//	@Param			quarantined		query	bool	false	"Only return tests that are automatically quarantined."
+//	@Param			limit			query	int		false	"The number of tests to return. Defaults to 100, and cannot exceed 1000."
+//	@Success		200				{array}	model.APITestFlakiness
+func (h *getProjectTestFlakinessHandler) Factory() gimlet.RouteHandler {
+	return &getProjectTestFlakinessHandler{}
+}
+
+func (h *getProjectTestFlakinessHandler) Parse(ctx context.Context, r *http.Request) error {
+	var err error
+	h.projectID, err = dbModel.GetIdForProject(ctx, gimlet.GetVars(r)["project_id"])
+	if err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    err.Error(),
+		}
+	}
+
+	vals := r.URL.Query()
+	h.opts.BuildVariant = vals.Get("build_variant")
+	h.opts.TaskName = vals.Get("task_name")
+	if quarantinedStr := vals.Get("quarantined"); quarantinedStr != "" {
+		h.opts.OnlyQuarantined, err = strconv.ParseBool(quarantinedStr)
+		if err != nil {
+			return errors.Wrap(err, "invalid quarantined")
+		}
+	}
+
+	h.opts.Limit = defaultTestFlakinessLimit
+	if limitStr := vals.Get("limit"); limitStr != "" {
+		h.opts.Limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			return errors.Wrap(err, "invalid limit")
+		}
+	}
+	if h.opts.Limit < 1 {
+		return errors.New("limit must be a positive integer")
+	}
+	if h.opts.Limit > maxTestFlakinessLimit {
+		return errors.Errorf("limit cannot exceed %d", maxTestFlakinessLimit)
+	}
+
+	return nil
+}
+
+func (h *getProjectTestFlakinessHandler) Run(ctx context.Context) gimlet.Responder {
+	tests, err := flakytest.FindByProject(ctx, h.projectID, h.opts)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "finding test flakiness for project '%s'", h.projectID))
+	}
+
+	apiTests := make([]model.APITestFlakiness, 0, len(tests))
+	for _, f := range tests {
+		apiTest := model.APITestFlakiness{}
+		apiTest.BuildFromService(f)
+		apiTests = append(apiTests, apiTest)
+	}
+
+	return gimlet.NewJSONResponse(apiTests)
+}
@@ -0,0 +1,284 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/units"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultWebhookDeliveriesLimit = 100
+	maxWebhookDeliveriesLimit     = 1000
+)
+
+// checkWebhookSubscriptionAccess finds the webhook subscription and checks
+// that the user can access it at the given project settings level.
+func checkWebhookSubscriptionAccess(ctx context.Context, subscriptionID string, requiredLevel int) (*event.Subscription, error) {
+	sub, err := event.FindSubscriptionByID(ctx, subscriptionID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding subscription '%s'", subscriptionID)
+	}
+	if sub == nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("subscription '%s' not found", subscriptionID),
+		}
+	}
+	if sub.Subscriber.Type != event.EvergreenWebhookSubscriberType {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("subscription '%s' is not a webhook subscription", subscriptionID),
+		}
+	}
+
+	u := MustHaveUser(ctx)
+	switch sub.OwnerType {
+	case event.OwnerTypePerson:
+		if sub.Owner != u.Username() {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusUnauthorized,
+				Message:    "cannot access webhook deliveries for someone other than yourself",
+			}
+		}
+	case event.OwnerTypeProject:
+		if !u.HasPermission(ctx, gimlet.PermissionOpts{
+			Resource:      sub.Owner,
+			ResourceType:  evergreen.ProjectResourceType,
+			Permission:    evergreen.PermissionProjectSettings,
+			RequiredLevel: requiredLevel,
+		}) {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusUnauthorized,
+				Message:    "not authorized to access webhook deliveries for this project",
+			}
+		}
+	default:
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("subscription has unsupported owner type '%s'", sub.OwnerType),
+		}
+	}
+
+	return sub, nil
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/subscriptions/{subscription_id}/webhook_deliveries
+
+type webhookDeliveriesGetHandler struct {
+	subscriptionID string
+	limit          int
+}
+
+func makeFetchWebhookDeliveries() gimlet.RouteHandler {
+	return &webhookDeliveriesGetHandler{}
+}
+
+// Factory creates an instance of the handler.
+//
+//	@Summary		Get webhook deliveries
+//	@Description	Returns the most recent requests made to deliver a webhook subscription's notifications, newest first, including the response code and latency of each.
+//	@Tags			subscriptions
+//	@Router			/subscriptions/{subscription_id}/webhook_deliveries [get]
+//	@Security		Api-User || Api-Key
+//	@Param			subscription_id	path		string	true	"subscription ID"
+//	@Param			limit			query		int		false	"maximum number of deliveries to return (default 100)"
+//	@Success		200				{array}		model.APIWebhookDelivery
+func (h *webhookDeliveriesGetHandler) Factory() gimlet.RouteHandler {
+	return &webhookDeliveriesGetHandler{}
+}
+
+func (h *webhookDeliveriesGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.subscriptionID = gimlet.GetVars(r)["subscription_id"]
+	h.limit = defaultWebhookDeliveriesLimit
+	if limit := r.FormValue("limit"); limit != "" {
+		var err error
+		h.limit, err = strconv.Atoi(limit)
+		if err != nil {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("invalid limit '%s'", limit),
+			}
+		}
+		if h.limit <= 0 || h.limit > maxWebhookDeliveriesLimit {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("limit must be between 1 and %d", maxWebhookDeliveriesLimit),
+			}
+		}
+	}
+
+	_, err := checkWebhookSubscriptionAccess(ctx, h.subscriptionID, evergreen.ProjectSettingsView.Value)
+	return err
+}
+
+func (h *webhookDeliveriesGetHandler) Run(ctx context.Context) gimlet.Responder {
+	deliveries, err := notification.FindWebhookDeliveriesForSubscription(ctx, h.subscriptionID, h.limit)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "getting webhook deliveries for subscription '%s'", h.subscriptionID))
+	}
+
+	apiDeliveries := make([]model.APIWebhookDelivery, 0, len(deliveries))
+	for _, d := range deliveries {
+		apiDelivery := model.APIWebhookDelivery{}
+		apiDelivery.BuildFromService(d)
+		apiDeliveries = append(apiDeliveries, apiDelivery)
+	}
+
+	return gimlet.NewJSONResponse(apiDeliveries)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/subscriptions/{subscription_id}/failed_notifications
+
+type failedNotificationsGetHandler struct {
+	subscriptionID string
+}
+
+func makeFetchFailedNotifications() gimlet.RouteHandler {
+	return &failedNotificationsGetHandler{}
+}
+
+// Factory creates an instance of the handler.
+//
+//	@Summary		Get failed webhook notifications
+//	@Description	Returns a webhook subscription's notifications that failed to be delivered and are either waiting to be retried or in the dead-letter queue.
+//	@Tags			subscriptions
+//	@Router			/subscriptions/{subscription_id}/failed_notifications [get]
+//	@Security		Api-User || Api-Key
+//	@Param			subscription_id	path		string	true	"subscription ID"
+//	@Success		200				{array}		model.APIFailedNotification
+func (h *failedNotificationsGetHandler) Factory() gimlet.RouteHandler {
+	return &failedNotificationsGetHandler{}
+}
+
+func (h *failedNotificationsGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.subscriptionID = gimlet.GetVars(r)["subscription_id"]
+	_, err := checkWebhookSubscriptionAccess(ctx, h.subscriptionID, evergreen.ProjectSettingsView.Value)
+	return err
+}
+
+func (h *failedNotificationsGetHandler) Run(ctx context.Context) gimlet.Responder {
+	notifications, err := notification.FindFailedWebhookNotifications(ctx, h.subscriptionID)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "getting failed notifications for subscription '%s'", h.subscriptionID))
+	}
+
+	apiNotifications := make([]model.APIFailedNotification, 0, len(notifications))
+	for _, n := range notifications {
+		apiNotification := model.APIFailedNotification{}
+		apiNotification.BuildFromService(n)
+		apiNotifications = append(apiNotifications, apiNotification)
+	}
+
+	return gimlet.NewJSONResponse(apiNotifications)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/subscriptions/{subscription_id}/failed_notifications/replay
+
+type replayFailedNotificationsHandler struct {
+	NotificationIDs []string `json:"notification_ids"`
+
+	subscriptionID string
+}
+
+func makeReplayFailedNotifications() gimlet.RouteHandler {
+	return &replayFailedNotificationsHandler{}
+}
+
+// Factory creates an instance of the handler.
+//
+//	@Summary		Replay failed webhook notifications
+//	@Description	Moves a webhook subscription's notifications out of the dead-letter queue and delivers them again. If no notification IDs are given, all of the subscription's dead-lettered notifications are replayed.
+//	@Tags			subscriptions
+//	@Router			/subscriptions/{subscription_id}/failed_notifications/replay [post]
+//	@Security		Api-User || Api-Key
+//	@Param			subscription_id	path	string								true	"subscription ID"
+//	@Param			{object}		body	replayFailedNotificationsHandler	false	"parameters"
+//	@Success		200				{array}	string								"IDs of the replayed notifications"
+func (h *replayFailedNotificationsHandler) Factory() gimlet.RouteHandler {
+	return &replayFailedNotificationsHandler{}
+}
+
+func (h *replayFailedNotificationsHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.subscriptionID = gimlet.GetVars(r)["subscription_id"]
+	if r.Body != nil && r.ContentLength != 0 {
+		if err := utility.ReadJSON(r.Body, h); err != nil {
+			return errors.Wrap(err, "reading replay parameters from JSON request body")
+		}
+	}
+
+	_, err := checkWebhookSubscriptionAccess(ctx, h.subscriptionID, evergreen.ProjectSettingsEdit.Value)
+	return err
+}
+
+func (h *replayFailedNotificationsHandler) Run(ctx context.Context) gimlet.Responder {
+	notifications, err := notification.FindFailedWebhookNotifications(ctx, h.subscriptionID)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "getting failed notifications for subscription '%s'", h.subscriptionID))
+	}
+
+	byID := map[string]*notification.Notification{}
+	for i := range notifications {
+		if notifications[i].DeliveryStatus == notification.DeliveryStatusDeadLetter {
+			byID[notifications[i].ID] = &notifications[i]
+		}
+	}
+
+	toReplay := []*notification.Notification{}
+	if len(h.NotificationIDs) == 0 {
+		for i := range notifications {
+			if notifications[i].DeliveryStatus == notification.DeliveryStatusDeadLetter {
+				toReplay = append(toReplay, &notifications[i])
+			}
+		}
+	} else {
+		for _, id := range h.NotificationIDs {
+			n, ok := byID[id]
+			if !ok {
+				return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+					StatusCode: http.StatusBadRequest,
+					Message:    fmt.Sprintf("notification '%s' is not in the subscription's dead-letter queue", id),
+				})
+			}
+			toReplay = append(toReplay, n)
+		}
+	}
+
+	queue := evergreen.GetEnvironment().RemoteQueue()
+	ts := time.Now().Format(units.TSFormat)
+	replayed := []string{}
+	catcher := grip.NewBasicCatcher()
+	for _, n := range toReplay {
+		if err := n.Replay(ctx); err != nil {
+			catcher.Add(err)
+			continue
+		}
+		replayed = append(replayed, n.ID)
+		// The notification is picked up by the next unsent notifications
+		// cron regardless, so enqueueing it just delivers it sooner.
+		catcher.Wrapf(amboy.EnqueueUniqueJob(ctx, queue, units.NewEventSendJob(n.ID, ts)), "enqueueing job to send replayed notification '%s'", n.ID)
+	}
+	if catcher.HasErrors() {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(catcher.Resolve(), "replaying failed notifications for subscription '%s'", h.subscriptionID))
+	}
+
+	return gimlet.NewJSONResponse(replayed)
+}
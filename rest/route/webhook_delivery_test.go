@@ -0,0 +1,143 @@
+package route
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDeliveryRoutes(t *testing.T) {
+	require.NoError(t, db.ClearCollections(event.SubscriptionsCollection, notification.Collection, notification.WebhookDeliveriesCollection))
+	defer func() {
+		assert.NoError(t, db.ClearCollections(event.SubscriptionsCollection, notification.Collection, notification.WebhookDeliveriesCollection))
+	}()
+
+	sub := event.Subscription{
+		ID:           "sub",
+		ResourceType: event.ResourceTypeTask,
+		Trigger:      event.TriggerOutcome,
+		Owner:        "me",
+		OwnerType:    event.OwnerTypePerson,
+		Subscriber: event.Subscriber{
+			Type:   event.EvergreenWebhookSubscriberType,
+			Target: &event.WebhookSubscriber{URL: "https://example.com", Secret: []byte("secret")},
+		},
+	}
+	require.NoError(t, sub.Upsert(t.Context()))
+
+	for _, n := range []notification.Notification{
+		{ID: "retrying", SubscriptionID: sub.ID, Subscriber: sub.Subscriber, Payload: &util.EvergreenWebhook{Body: []byte("{}")}},
+		{ID: "dead-letter", SubscriptionID: sub.ID, Subscriber: sub.Subscriber, Payload: &util.EvergreenWebhook{Body: []byte("{}")}},
+	} {
+		require.NoError(t, notification.InsertMany(t.Context(), n))
+	}
+	retrying, err := notification.Find(t.Context(), "retrying")
+	require.NoError(t, err)
+	require.NoError(t, retrying.MarkDeliveryFailed(t.Context(), assert.AnError))
+	deadLetter, err := notification.Find(t.Context(), "dead-letter")
+	require.NoError(t, err)
+	deadLetter.DeliveryAttempts = notification.MaxWebhookDeliveryAttempts - 1
+	require.NoError(t, deadLetter.MarkDeliveryFailed(t.Context(), assert.AnError))
+
+	delivery := notification.WebhookDelivery{NotificationID: "dead-letter", SubscriptionID: sub.ID, StatusCode: http.StatusServiceUnavailable}
+	require.NoError(t, delivery.Insert(t.Context()))
+
+	owner := gimlet.AttachUser(t.Context(), &user.DBUser{Id: "me"})
+
+	t.Run("OtherUserIsUnauthorized", func(t *testing.T) {
+		ctx := gimlet.AttachUser(t.Context(), &user.DBUser{Id: "someone-else"})
+		r, err := http.NewRequest(http.MethodGet, "/subscriptions/sub/failed_notifications", nil)
+		require.NoError(t, err)
+		r = gimlet.SetURLVars(r, map[string]string{"subscription_id": sub.ID})
+
+		err = makeFetchFailedNotifications().Parse(ctx, r)
+		require.Error(t, err)
+		respErr, ok := err.(gimlet.ErrorResponse)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusUnauthorized, respErr.StatusCode)
+	})
+	t.Run("NonexistentSubscription", func(t *testing.T) {
+		r, err := http.NewRequest(http.MethodGet, "/subscriptions/nope/webhook_deliveries", nil)
+		require.NoError(t, err)
+		r = gimlet.SetURLVars(r, map[string]string{"subscription_id": "nope"})
+
+		err = makeFetchWebhookDeliveries().Parse(owner, r)
+		require.Error(t, err)
+		respErr, ok := err.(gimlet.ErrorResponse)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusNotFound, respErr.StatusCode)
+	})
+	t.Run("InvalidLimit", func(t *testing.T) {
+		r, err := http.NewRequest(http.MethodGet, "/subscriptions/sub/webhook_deliveries?limit=0", nil)
+		require.NoError(t, err)
+		r = gimlet.SetURLVars(r, map[string]string{"subscription_id": sub.ID})
+		assert.Error(t, makeFetchWebhookDeliveries().Parse(owner, r))
+	})
+	t.Run("GetWebhookDeliveries", func(t *testing.T) {
+		r, err := http.NewRequest(http.MethodGet, "/subscriptions/sub/webhook_deliveries", nil)
+		require.NoError(t, err)
+		r = gimlet.SetURLVars(r, map[string]string{"subscription_id": sub.ID})
+
+		h := makeFetchWebhookDeliveries()
+		require.NoError(t, h.Parse(owner, r))
+		resp := h.Run(owner)
+		require.Equal(t, http.StatusOK, resp.Status())
+		deliveries, ok := resp.Data().([]model.APIWebhookDelivery)
+		require.True(t, ok)
+		require.Len(t, deliveries, 1)
+		assert.Equal(t, http.StatusServiceUnavailable, deliveries[0].StatusCode)
+	})
+	t.Run("GetFailedNotifications", func(t *testing.T) {
+		r, err := http.NewRequest(http.MethodGet, "/subscriptions/sub/failed_notifications", nil)
+		require.NoError(t, err)
+		r = gimlet.SetURLVars(r, map[string]string{"subscription_id": sub.ID})
+
+		h := makeFetchFailedNotifications()
+		require.NoError(t, h.Parse(owner, r))
+		resp := h.Run(owner)
+		require.Equal(t, http.StatusOK, resp.Status())
+		failed, ok := resp.Data().([]model.APIFailedNotification)
+		require.True(t, ok)
+		assert.Len(t, failed, 2)
+	})
+	t.Run("ReplayRejectsNotificationsNotInDeadLetterQueue", func(t *testing.T) {
+		body, err := json.Marshal(map[string]any{"notification_ids": []string{"retrying"}})
+		require.NoError(t, err)
+		r, err := http.NewRequest(http.MethodPost, "/subscriptions/sub/failed_notifications/replay", bytes.NewBuffer(body))
+		require.NoError(t, err)
+		r = gimlet.SetURLVars(r, map[string]string{"subscription_id": sub.ID})
+
+		h := makeReplayFailedNotifications()
+		require.NoError(t, h.Parse(owner, r))
+		resp := h.Run(owner)
+		assert.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+	t.Run("ReplayAllDeadLetteredNotifications", func(t *testing.T) {
+		r, err := http.NewRequest(http.MethodPost, "/subscriptions/sub/failed_notifications/replay", nil)
+		require.NoError(t, err)
+		r = gimlet.SetURLVars(r, map[string]string{"subscription_id": sub.ID})
+
+		h := makeReplayFailedNotifications()
+		require.NoError(t, h.Parse(owner, r))
+		resp := h.Run(owner)
+		require.Equal(t, http.StatusOK, resp.Status())
+		assert.Equal(t, []string{"dead-letter"}, resp.Data())
+
+		n, err := notification.Find(t.Context(), "dead-letter")
+		require.NoError(t, err)
+		require.NotNil(t, n)
+		assert.Empty(t, n.DeliveryStatus)
+		assert.True(t, n.SentAt.IsZero())
+	})
+}
@@ -2,11 +2,13 @@ package scheduler
 
 import (
 	"context"
+	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/task"
 )
 
 // HostAllocator is responsible for determining how many new hosts should be
@@ -18,6 +20,12 @@ type HostAllocatorData struct {
 	Distro          distro.Distro
 	ExistingHosts   []host.Host
 	DistroQueueInfo model.DistroQueueInfo
+	// RunningTasks are the tasks running on the existing hosts. If it is
+	// nil, the running tasks are looked up in the database.
+	RunningTasks []task.Task
+	// Now is the time at which hosts are allocated. If it is zero, the
+	// current time is used.
+	Now time.Time
 }
 
 func GetHostAllocator(name string) HostAllocator {
@@ -97,6 +97,9 @@ type Unit struct {
 	cachedValue task.SortingValueBreakdown
 	id          string
 	distro      *distro.Distro
+	// now is the time at which the unit is planned. If it is zero, the
+	// current time is used.
+	now time.Time
 }
 
 // MakeUnit constructs a new unit, caching a reference to the distro
@@ -303,6 +306,10 @@ func (unit *Unit) info(ctx context.Context) unitInfo {
 	info := unitInfo{
 		Settings: unit.distro.PlannerSettings,
 	}
+	now := unit.now
+	if now.IsZero() {
+		now = time.Now()
+	}
 
 	for _, t := range unit.tasks {
 		if evergreen.IsGithubMergeQueueRequester(t.Requester) {
@@ -316,9 +323,9 @@ func (unit *Unit) info(ctx context.Context) unitInfo {
 		info.ContainsStepbackTask = info.ContainsStepbackTask || t.ActivatedBy == evergreen.StepbackTaskActivator
 
 		if !t.ActivatedTime.IsZero() {
-			info.TimeInQueue += time.Since(t.ActivatedTime)
+			info.TimeInQueue += now.Sub(t.ActivatedTime)
 		} else if !t.IngestTime.IsZero() {
-			info.TimeInQueue += time.Since(t.IngestTime)
+			info.TimeInQueue += now.Sub(t.IngestTime)
 		}
 
 		info.TotalPriority += t.Priority
@@ -429,6 +436,12 @@ func (tpl TaskPlan) Keys() []string {
 // PrepareTasksForPlanning takes a list of tasks for a distro and
 // returns a TaskPlan, grouping tasks into the appropriate units.
 func PrepareTasksForPlanning(ctx context.Context, distro *distro.Distro, tasks []task.Task) TaskPlan {
+	return prepareTasksForPlanning(ctx, distro, tasks, time.Time{})
+}
+
+// prepareTasksForPlanning is the same as PrepareTasksForPlanning, but plans
+// the tasks as of the given time instead of the current time.
+func prepareTasksForPlanning(ctx context.Context, distro *distro.Distro, tasks []task.Task, now time.Time) TaskPlan {
 	cache := UnitCache{}
 
 	for _, t := range tasks {
@@ -444,6 +457,7 @@ func PrepareTasksForPlanning(ctx context.Context, distro *distro.Distro, tasks [
 			unit = cache.Create(t.Id, t)
 		}
 		unit.SetDistro(distro)
+		unit.now = now
 	}
 
 	for _, t := range tasks {
@@ -21,6 +21,9 @@ type TaskPlannerOptions struct {
 	IncludesDependencies       bool
 	StartedAt                  time.Time
 	MaxScheduledTasksPerDistro int
+	// Now is the time at which the queue is evaluated. If it is zero, the
+	// current time is used.
+	Now time.Time
 }
 
 type TaskPlanner func(*distro.Distro, []task.Task, TaskPlannerOptions) ([]task.Task, error)
@@ -76,6 +79,10 @@ func GetDistroQueueInfo(ctx context.Context, d *distro.Distro, tasks []task.Task
 	}
 
 	maxDurationThreshold := d.GetTargetTimeForQueue(hasMergeQueueTasks)
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
 
 	for i, task := range tasks {
 		group := task.TaskGroup
@@ -138,7 +145,7 @@ func GetDistroQueueInfo(ctx context.Context, d *distro.Distro, tasks []task.Task
 				if task.DependenciesMetTime.After(startTime) {
 					startTime = task.DependenciesMetTime
 				}
-				task.WaitSinceDependenciesMet = time.Since(startTime)
+				task.WaitSinceDependenciesMet = now.Sub(startTime)
 
 				// actual wait time allows us to independently check that the threshold is working
 				if task.WaitSinceDependenciesMet > maxDurationThreshold {
@@ -0,0 +1,677 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/task"
+	restmodel "github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/utility"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultSimulatorInterval        = time.Minute
+	defaultSimulatorHostStartupTime = 8 * time.Minute
+	defaultSimulatorHostIdleTime    = 4 * time.Minute
+	defaultSimulatorDrainTime       = 7 * 24 * time.Hour
+)
+
+// simulatorEpoch is the time at which every simulated workload starts. It is
+// fixed so that simulations are reproducible.
+var simulatorEpoch = time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// SimulatorWorkload is a recorded workload for a single distro that can be
+// replayed by Simulate. In JSON, the distro has the same format as the distro
+// REST routes.
+type SimulatorWorkload struct {
+	Distro distro.Distro
+	Tasks  []SimulatorTask
+}
+
+type simulatorWorkloadJSON struct {
+	Distro restmodel.APIDistro `json:"distro"`
+	Tasks  []SimulatorTask     `json:"tasks"`
+}
+
+// MarshalJSON converts the workload's distro to its REST format.
+func (w SimulatorWorkload) MarshalJSON() ([]byte, error) {
+	out := simulatorWorkloadJSON{Tasks: w.Tasks}
+	out.Distro.BuildFromService(w.Distro)
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON reads a workload whose distro is in its REST format.
+func (w *SimulatorWorkload) UnmarshalJSON(data []byte) error {
+	in := simulatorWorkloadJSON{}
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	w.Distro = *in.Distro.ToService()
+	w.Tasks = in.Tasks
+	return nil
+}
+
+// SimulatorTask is a task in a simulator workload.
+type SimulatorTask struct {
+	ID                string `json:"id"`
+	Project           string `json:"project,omitempty"`
+	Version           string `json:"version,omitempty"`
+	BuildVariant      string `json:"build_variant,omitempty"`
+	DisplayName       string `json:"display_name,omitempty"`
+	Requester         string `json:"requester,omitempty"`
+	TaskGroup         string `json:"task_group,omitempty"`
+	TaskGroupMaxHosts int    `json:"task_group_max_hosts,omitempty"`
+	TaskGroupOrder    int    `json:"task_group_order,omitempty"`
+	Priority          int64  `json:"priority,omitempty"`
+	GenerateTask      bool   `json:"generate_task,omitempty"`
+	// DependsOn are the IDs of the tasks in the workload that must finish
+	// before this task can run.
+	DependsOn []string `json:"depends_on,omitempty"`
+	// ArrivesAt is when the task is activated, relative to the start of the
+	// workload.
+	ArrivesAt time.Duration `json:"arrives_at"`
+	// Duration is how long the task runs once it is dispatched.
+	Duration time.Duration `json:"duration"`
+	// ExpectedDuration is how long the scheduler expects the task to run. If
+	// it is zero, Duration is used.
+	ExpectedDuration time.Duration `json:"expected_duration,omitempty"`
+}
+
+// NewSimulatorWorkload records the given finished tasks as a workload for the
+// distro. Tasks arrive at the time they were activated, relative to the
+// earliest activated task, and run for as long as they ran originally.
+// Dependencies on tasks that are not in the given tasks are dropped.
+func NewSimulatorWorkload(d distro.Distro, tasks []task.Task) SimulatorWorkload {
+	arrivedAt := func(t task.Task) time.Time {
+		if !utility.IsZeroTime(t.ActivatedTime) {
+			return t.ActivatedTime
+		}
+		return t.IngestTime
+	}
+
+	var start time.Time
+	ids := make(map[string]bool, len(tasks))
+	for _, t := range tasks {
+		ids[t.Id] = true
+		if start.IsZero() || arrivedAt(t).Before(start) {
+			start = arrivedAt(t)
+		}
+	}
+
+	w := SimulatorWorkload{Distro: d}
+	for _, t := range tasks {
+		st := SimulatorTask{
+			ID:                t.Id,
+			Project:           t.Project,
+			Version:           t.Version,
+			BuildVariant:      t.BuildVariant,
+			DisplayName:       t.DisplayName,
+			Requester:         t.Requester,
+			TaskGroup:         t.TaskGroup,
+			TaskGroupMaxHosts: t.TaskGroupMaxHosts,
+			TaskGroupOrder:    t.TaskGroupOrder,
+			Priority:          t.Priority,
+			GenerateTask:      t.GenerateTask,
+			ArrivesAt:         arrivedAt(t).Sub(start),
+			Duration:          t.TimeTaken,
+			ExpectedDuration:  t.ExpectedDuration,
+		}
+		if st.Duration == 0 && !utility.IsZeroTime(t.StartTime) && t.FinishTime.After(t.StartTime) {
+			st.Duration = t.FinishTime.Sub(t.StartTime)
+		}
+		for _, dep := range t.DependsOn {
+			if ids[dep.TaskId] {
+				st.DependsOn = append(st.DependsOn, dep.TaskId)
+			}
+		}
+		w.Tasks = append(w.Tasks, st)
+	}
+	sort.SliceStable(w.Tasks, func(i, j int) bool {
+		if w.Tasks[i].ArrivesAt != w.Tasks[j].ArrivesAt {
+			return w.Tasks[i].ArrivesAt < w.Tasks[j].ArrivesAt
+		}
+		return w.Tasks[i].ID < w.Tasks[j].ID
+	})
+
+	return w
+}
+
+// SimulatorOptions configure a simulation.
+type SimulatorOptions struct {
+	// PlannerSettings and HostAllocatorSettings, if set, replace the
+	// workload distro's settings.
+	PlannerSettings       *distro.PlannerSettings
+	HostAllocatorSettings *distro.HostAllocatorSettings
+	// Interval is how often the planner and host allocator run. Defaults to
+	// a minute.
+	Interval time.Duration
+	// HostStartupTime is how long a new host takes before it can run tasks.
+	// Defaults to 8 minutes.
+	HostStartupTime time.Duration
+	// HostIdleTime is how long a host can be idle before it is terminated if
+	// the distro does not set an acceptable host idle time. Defaults to 4
+	// minutes.
+	HostIdleTime time.Duration
+	// HostCostPerHour is the cost of running a host for an hour.
+	HostCostPerHour float64
+	// MaxDuration is how long the simulation can run before it gives up on
+	// the remaining tasks. Defaults to a week after the last task arrives.
+	MaxDuration time.Duration
+}
+
+// SimulatorReport summarizes a simulation.
+type SimulatorReport struct {
+	TasksFinished   int `json:"tasks_finished" yaml:"tasks_finished"`
+	TasksUnfinished int `json:"tasks_unfinished" yaml:"tasks_unfinished"`
+	// Queue latency is the time between a task's dependencies being met
+	// (or the task arriving, if it has no dependencies) and the task
+	// starting.
+	MeanQueueLatency time.Duration `json:"mean_queue_latency" yaml:"mean_queue_latency"`
+	P50QueueLatency  time.Duration `json:"p50_queue_latency" yaml:"p50_queue_latency"`
+	P90QueueLatency  time.Duration `json:"p90_queue_latency" yaml:"p90_queue_latency"`
+	P99QueueLatency  time.Duration `json:"p99_queue_latency" yaml:"p99_queue_latency"`
+	MaxQueueLatency  time.Duration `json:"max_queue_latency" yaml:"max_queue_latency"`
+	// Makespan is the time between the start of the workload and the last
+	// task finishing.
+	Makespan     time.Duration `json:"makespan" yaml:"makespan"`
+	HostsCreated int           `json:"hosts_created" yaml:"hosts_created"`
+	PeakHosts    int           `json:"peak_hosts" yaml:"peak_hosts"`
+	HostHours    float64       `json:"host_hours" yaml:"host_hours"`
+	// Utilization is the fraction of host time spent running tasks.
+	Utilization float64 `json:"utilization" yaml:"utilization"`
+	Cost        float64 `json:"cost" yaml:"cost"`
+}
+
+type simulatedTask struct {
+	SimulatorTask
+	dependents    []*simulatedTask
+	depsRemaining int
+	numDependents int
+	readyAt       time.Time
+	startedAt     time.Time
+	finishedAt    time.Time
+}
+
+func (t *simulatedTask) arrivedAt() time.Time { return simulatorEpoch.Add(t.ArrivesAt) }
+
+func (t *simulatedTask) isReady(now time.Time) bool {
+	return !t.readyAt.IsZero() && !t.readyAt.After(now) && t.startedAt.IsZero()
+}
+
+// export converts the simulated task into a task that the planner and host
+// allocator can use without looking anything up in the database.
+func (t *simulatedTask) export(d *distro.Distro, tasksByID map[string]*simulatedTask) task.Task {
+	expected := t.ExpectedDuration
+	if expected == 0 {
+		expected = t.Duration
+	}
+	out := task.Task{
+		Id:                t.ID,
+		DistroId:          d.Id,
+		Project:           t.Project,
+		Version:           t.Version,
+		BuildVariant:      t.BuildVariant,
+		DisplayName:       t.DisplayName,
+		Requester:         t.Requester,
+		TaskGroup:         t.TaskGroup,
+		TaskGroupMaxHosts: t.TaskGroupMaxHosts,
+		TaskGroupOrder:    t.TaskGroupOrder,
+		Priority:          t.Priority,
+		GenerateTask:      t.GenerateTask,
+		NumDependents:     t.numDependents,
+		Activated:         true,
+		ActivatedTime:     t.arrivedAt(),
+		ScheduledTime:     t.arrivedAt(),
+		StartTime:         t.startedAt,
+		ExpectedDuration:  expected,
+		// The prediction is always fresh so that it is never refreshed
+		// from the database.
+		DurationPrediction: util.CachedDurationValue{
+			Value:       expected,
+			TTL:         math.MaxInt64,
+			CollectedAt: time.Now(),
+		},
+	}
+	for _, id := range t.DependsOn {
+		out.DependsOn = append(out.DependsOn, task.Dependency{
+			TaskId:     id,
+			Status:     evergreen.TaskSucceeded,
+			FinishedAt: tasksByID[id].finishedAt,
+		})
+	}
+	if len(out.DependsOn) > 0 && !t.readyAt.IsZero() {
+		out.DependenciesMetTime = t.readyAt
+	}
+	return out
+}
+
+type simulatedHost struct {
+	id           string
+	createdAt    time.Time
+	readyAt      time.Time
+	idleSince    time.Time
+	terminatedAt time.Time
+	busy         time.Duration
+	running      *simulatedTask
+}
+
+func (h *simulatedHost) export(d *distro.Distro, now time.Time) host.Host {
+	out := host.Host{
+		Id:           h.id,
+		Distro:       *d,
+		Status:       evergreen.HostRunning,
+		CreationTime: h.createdAt,
+	}
+	if h.readyAt.After(now) {
+		out.Status = evergreen.HostStarting
+	}
+	if h.running != nil {
+		out.RunningTask = h.running.ID
+		out.RunningTaskGroup = h.running.TaskGroup
+		out.RunningTaskBuildVariant = h.running.BuildVariant
+		out.RunningTaskProject = h.running.Project
+		out.RunningTaskVersion = h.running.Version
+	}
+	return out
+}
+
+type simulator struct {
+	distro    distro.Distro
+	opts      SimulatorOptions
+	idleTime  time.Duration
+	tasks     []*simulatedTask
+	tasksByID map[string]*simulatedTask
+	hosts     []*simulatedHost
+	queue     []*simulatedTask
+	peakHosts int
+}
+
+// Simulate replays the workload through the real planner and host allocator
+// and reports how long tasks waited in the queue and how many hosts they
+// needed. It uses a simulated clock and never touches the database, so the
+// same workload and options always produce the same report.
+//
+// The simulation is simplified in a few ways: tasks are only queued once their
+// dependencies have finished, hosts run any task in the queue regardless of
+// the task group they last ran, and tasks never fail or get restarted.
+func Simulate(ctx context.Context, w SimulatorWorkload, opts SimulatorOptions) (*SimulatorReport, error) {
+	s, err := newSimulator(w, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastArrival time.Duration
+	for _, t := range s.tasks {
+		lastArrival = max(lastArrival, t.ArrivesAt)
+	}
+	deadline := simulatorEpoch.Add(lastArrival + s.opts.MaxDuration)
+
+	now := simulatorEpoch
+	nextTick := simulatorEpoch
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, errors.Wrap(err, "simulating workload")
+		}
+
+		s.finishTasks(now)
+		s.markReady(now)
+		if !now.Before(nextTick) {
+			if err := s.schedule(ctx, now); err != nil {
+				return nil, errors.Wrapf(err, "scheduling at %s", now.Sub(simulatorEpoch))
+			}
+			nextTick = nextTick.Add(s.opts.Interval)
+		}
+		s.dispatch(now)
+
+		if s.isFinished(now) || now.After(deadline) {
+			break
+		}
+		now = s.nextEvent(now, nextTick)
+	}
+
+	return s.report(now), nil
+}
+
+func newSimulator(w SimulatorWorkload, opts SimulatorOptions) (*simulator, error) {
+	if w.Distro.Id == "" {
+		return nil, errors.New("workload distro must have an ID")
+	}
+	if opts.PlannerSettings != nil {
+		w.Distro.PlannerSettings = *opts.PlannerSettings
+	}
+	if opts.HostAllocatorSettings != nil {
+		w.Distro.HostAllocatorSettings = *opts.HostAllocatorSettings
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = defaultSimulatorInterval
+	}
+	if opts.HostStartupTime < 0 {
+		return nil, errors.New("host startup time cannot be negative")
+	}
+	if opts.HostStartupTime == 0 {
+		opts.HostStartupTime = defaultSimulatorHostStartupTime
+	}
+	if opts.HostIdleTime <= 0 {
+		opts.HostIdleTime = defaultSimulatorHostIdleTime
+	}
+	if opts.MaxDuration <= 0 {
+		opts.MaxDuration = defaultSimulatorDrainTime
+	}
+
+	s := &simulator{
+		distro:    w.Distro,
+		opts:      opts,
+		idleTime:  opts.HostIdleTime,
+		tasksByID: make(map[string]*simulatedTask, len(w.Tasks)),
+	}
+	if w.Distro.HostAllocatorSettings.AcceptableHostIdleTime > 0 {
+		s.idleTime = w.Distro.HostAllocatorSettings.AcceptableHostIdleTime
+	}
+
+	for _, t := range w.Tasks {
+		if t.ID == "" {
+			return nil, errors.New("workload task must have an ID")
+		}
+		if _, ok := s.tasksByID[t.ID]; ok {
+			return nil, errors.Errorf("duplicate workload task '%s'", t.ID)
+		}
+		if t.ArrivesAt < 0 || t.Duration < 0 {
+			return nil, errors.Errorf("workload task '%s' cannot have a negative arrival time or duration", t.ID)
+		}
+		st := &simulatedTask{SimulatorTask: t}
+		s.tasks = append(s.tasks, st)
+		s.tasksByID[t.ID] = st
+	}
+	for _, t := range s.tasks {
+		for _, id := range t.DependsOn {
+			dep, ok := s.tasksByID[id]
+			if !ok {
+				return nil, errors.Errorf("workload task '%s' depends on unknown task '%s'", t.ID, id)
+			}
+			dep.dependents = append(dep.dependents, t)
+			dep.numDependents++
+			t.depsRemaining++
+		}
+	}
+	sort.SliceStable(s.tasks, func(i, j int) bool {
+		if s.tasks[i].ArrivesAt != s.tasks[j].ArrivesAt {
+			return s.tasks[i].ArrivesAt < s.tasks[j].ArrivesAt
+		}
+		return s.tasks[i].ID < s.tasks[j].ID
+	})
+
+	return s, nil
+}
+
+// finishTasks frees the hosts of tasks that finish by now.
+func (s *simulator) finishTasks(now time.Time) {
+	for _, h := range s.hosts {
+		t := h.running
+		if t == nil || t.finishedAt.After(now) {
+			continue
+		}
+		h.running = nil
+		h.idleSince = t.finishedAt
+		h.busy += t.Duration
+		for _, dependent := range t.dependents {
+			dependent.depsRemaining--
+		}
+	}
+}
+
+// markReady marks the tasks that have arrived and whose dependencies have
+// finished as ready to run.
+func (s *simulator) markReady(now time.Time) {
+	for _, t := range s.tasks {
+		if !t.readyAt.IsZero() || t.depsRemaining > 0 || t.arrivedAt().After(now) {
+			continue
+		}
+		t.readyAt = t.arrivedAt()
+		for _, id := range t.DependsOn {
+			if finishedAt := s.tasksByID[id].finishedAt; finishedAt.After(t.readyAt) {
+				t.readyAt = finishedAt
+			}
+		}
+	}
+}
+
+// exportPlanDeterministically is the same as TaskPlan.Export, except that
+// units and tasks that the planner ranks equally are always ordered by ID, so
+// that replaying a workload always produces the same queue. The planner
+// itself leaves them in an arbitrary order.
+func exportPlanDeterministically(ctx context.Context, tpl TaskPlan) []task.Task {
+	sort.Slice(tpl.units, func(i, j int) bool { return tpl.units[i].ID() < tpl.units[j].ID() })
+	sort.Stable(tpl)
+
+	output := []task.Task{}
+	seen := StringSet{}
+	for _, unit := range tpl.units {
+		sortingValueBreakdown := unit.sortingValueBreakdown(ctx)
+		tasks := unit.Export(ctx)
+		sort.Slice(tasks.tasks, func(i, j int) bool { return tasks.tasks[i].Id < tasks.tasks[j].Id })
+		sort.Stable(tasks)
+		for i := range tasks.tasks {
+			if seen.Visit(tasks.tasks[i].Id) {
+				continue
+			}
+			tasks.tasks[i].SetSortingValueBreakdownAttributes(ctx, sortingValueBreakdown)
+			output = append(output, tasks.tasks[i])
+		}
+	}
+
+	return output
+}
+
+// schedule terminates idle hosts, plans the queue of ready tasks and starts
+// the hosts that the host allocator asks for.
+func (s *simulator) schedule(ctx context.Context, now time.Time) error {
+	s.terminateIdleHosts(now)
+
+	var ready []task.Task
+	for _, t := range s.tasks {
+		if t.isReady(now) {
+			ready = append(ready, t.export(&s.distro, s.tasksByID))
+		}
+	}
+	plan := exportPlanDeterministically(ctx, prepareTasksForPlanning(ctx, &s.distro, ready, now))
+	s.queue = s.queue[:0]
+	for _, t := range plan {
+		s.queue = append(s.queue, s.tasksByID[t.Id])
+	}
+
+	info := GetDistroQueueInfo(ctx, &s.distro, plan, TaskPlannerOptions{
+		ID:                   fmt.Sprintf("simulator-%s", s.distro.Id),
+		IncludesDependencies: s.distro.DispatcherSettings.Version == evergreen.DispatcherVersionRevisedWithDependencies,
+		StartedAt:            now,
+		Now:                  now,
+	})
+
+	existing := []host.Host{}
+	runningTasks := []task.Task{}
+	for _, h := range s.activeHosts() {
+		existing = append(existing, h.export(&s.distro, now))
+		if h.running != nil {
+			runningTasks = append(runningTasks, h.running.export(&s.distro, s.tasksByID))
+		}
+	}
+	allocator := GetHostAllocator(s.distro.HostAllocatorSettings.Version)
+	newHosts, _, err := allocator(ctx, &HostAllocatorData{
+		Distro:          s.distro,
+		ExistingHosts:   existing,
+		DistroQueueInfo: info,
+		RunningTasks:    runningTasks,
+		Now:             now,
+	})
+	if err != nil {
+		return errors.Wrap(err, "allocating hosts")
+	}
+
+	for i := 0; i < newHosts; i++ {
+		readyAt := now.Add(s.opts.HostStartupTime)
+		s.hosts = append(s.hosts, &simulatedHost{
+			id:        fmt.Sprintf("%s-%d", s.distro.Id, len(s.hosts)),
+			createdAt: now,
+			readyAt:   readyAt,
+			idleSince: readyAt,
+		})
+	}
+	s.peakHosts = max(s.peakHosts, len(s.activeHosts()))
+
+	return nil
+}
+
+// terminateIdleHosts terminates hosts that have been idle for too long, while
+// keeping the distro's minimum number of hosts.
+func (s *simulator) terminateIdleHosts(now time.Time) {
+	active := s.activeHosts()
+	sort.SliceStable(active, func(i, j int) bool { return active[i].idleSince.Before(active[j].idleSince) })
+	for _, h := range active {
+		if len(s.activeHosts()) <= s.distro.HostAllocatorSettings.MinimumHosts {
+			return
+		}
+		if h.running == nil && !h.readyAt.After(now) && now.Sub(h.idleSince) >= s.idleTime {
+			h.terminatedAt = now
+		}
+	}
+}
+
+// dispatch assigns queued tasks to free hosts, in queue order.
+func (s *simulator) dispatch(now time.Time) {
+	for _, h := range s.hosts {
+		if !h.terminatedAt.IsZero() || h.readyAt.After(now) || h.running != nil {
+			continue
+		}
+		for i, t := range s.queue {
+			if !t.startedAt.IsZero() || s.taskGroupFull(t) {
+				continue
+			}
+			t.startedAt = now
+			t.finishedAt = now.Add(t.Duration)
+			h.running = t
+			s.queue = append(s.queue[:i], s.queue[i+1:]...)
+			break
+		}
+	}
+}
+
+// taskGroupFull returns whether the task's group is already running on as
+// many hosts as it is allowed.
+func (s *simulator) taskGroupFull(t *simulatedTask) bool {
+	if t.TaskGroup == "" || t.TaskGroupMaxHosts <= 0 {
+		return false
+	}
+	running := 0
+	for _, h := range s.hosts {
+		if h.running != nil && h.running.TaskGroup == t.TaskGroup && h.running.BuildVariant == t.BuildVariant &&
+			h.running.Project == t.Project && h.running.Version == t.Version {
+			running++
+		}
+	}
+	return running >= t.TaskGroupMaxHosts
+}
+
+func (s *simulator) activeHosts() []*simulatedHost {
+	var active []*simulatedHost
+	for _, h := range s.hosts {
+		if h.terminatedAt.IsZero() {
+			active = append(active, h)
+		}
+	}
+	return active
+}
+
+// isFinished returns whether every task has finished, or whether nothing that
+// could happen would let any more tasks run.
+func (s *simulator) isFinished(now time.Time) bool {
+	for _, t := range s.tasks {
+		if t.finishedAt.IsZero() || t.finishedAt.After(now) {
+			if t.depsRemaining == 0 || !t.startedAt.IsZero() {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// nextEvent returns the next time at which something happens.
+func (s *simulator) nextEvent(now, nextTick time.Time) time.Time {
+	next := nextTick
+	consider := func(t time.Time) {
+		if t.After(now) && t.Before(next) {
+			next = t
+		}
+	}
+	for _, t := range s.tasks {
+		consider(t.arrivedAt())
+		if !t.startedAt.IsZero() {
+			consider(t.finishedAt)
+		}
+	}
+	for _, h := range s.hosts {
+		consider(h.readyAt)
+	}
+	return next
+}
+
+func (s *simulator) report(end time.Time) *SimulatorReport {
+	r := &SimulatorReport{
+		HostsCreated: len(s.hosts),
+		PeakHosts:    s.peakHosts,
+	}
+
+	var latencies []time.Duration
+	var total time.Duration
+	for _, t := range s.tasks {
+		if t.startedAt.IsZero() || t.finishedAt.After(end) {
+			r.TasksUnfinished++
+			continue
+		}
+		r.TasksFinished++
+		latency := t.startedAt.Sub(t.readyAt)
+		latencies = append(latencies, latency)
+		total += latency
+		r.Makespan = max(r.Makespan, t.finishedAt.Sub(simulatorEpoch))
+	}
+	if len(latencies) > 0 {
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		percentile := func(p float64) time.Duration {
+			return latencies[int(math.Ceil(p*float64(len(latencies))))-1]
+		}
+		r.MeanQueueLatency = total / time.Duration(len(latencies))
+		r.P50QueueLatency = percentile(0.5)
+		r.P90QueueLatency = percentile(0.9)
+		r.P99QueueLatency = percentile(0.99)
+		r.MaxQueueLatency = latencies[len(latencies)-1]
+	}
+
+	var hostTime, busyTime time.Duration
+	for _, h := range s.hosts {
+		terminatedAt := h.terminatedAt
+		if terminatedAt.IsZero() {
+			// Hosts left at the end of the workload are terminated once
+			// they have been idle for long enough.
+			terminatedAt = end
+			if h.running == nil && h.idleSince.Add(s.idleTime).After(end) {
+				terminatedAt = h.idleSince.Add(s.idleTime)
+			}
+		}
+		hostTime += terminatedAt.Sub(h.createdAt)
+		busyTime += h.busy
+	}
+	r.HostHours = hostTime.Hours()
+	r.Cost = r.HostHours * s.opts.HostCostPerHour
+	if hostTime > 0 {
+		r.Utilization = float64(busyTime) / float64(hostTime)
+	}
+
+	return r
+}
@@ -0,0 +1,211 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulate(t *testing.T) {
+	ctx := t.Context()
+
+	makeDistro := func(maxHosts int) distro.Distro {
+		return distro.Distro{
+			Id:       "d",
+			Provider: evergreen.ProviderNameEc2Fleet,
+			PlannerSettings: distro.PlannerSettings{
+				Version:    evergreen.PlannerVersionTunable,
+				TargetTime: 30 * time.Minute,
+			},
+			HostAllocatorSettings: distro.HostAllocatorSettings{
+				Version:      evergreen.HostAllocatorUtilization,
+				MaximumHosts: maxHosts,
+			},
+		}
+	}
+	makeTasks := func(n int, arrivesAt, duration time.Duration) []SimulatorTask {
+		tasks := make([]SimulatorTask, 0, n)
+		for i := 0; i < n; i++ {
+			tasks = append(tasks, SimulatorTask{
+				ID:           fmt.Sprintf("t%d", i),
+				Project:      "project",
+				Version:      "v",
+				BuildVariant: "bv",
+				DisplayName:  fmt.Sprintf("t%d", i),
+				Requester:    evergreen.RepotrackerVersionRequester,
+				ArrivesAt:    arrivesAt,
+				Duration:     duration,
+			})
+		}
+		return tasks
+	}
+
+	t.Run("RunsAllTasks", func(t *testing.T) {
+		w := SimulatorWorkload{Distro: makeDistro(10), Tasks: makeTasks(20, 0, 10*time.Minute)}
+		report, err := Simulate(ctx, w, SimulatorOptions{HostCostPerHour: 2})
+		require.NoError(t, err)
+
+		assert.Equal(t, 20, report.TasksFinished)
+		assert.Zero(t, report.TasksUnfinished)
+		assert.NotZero(t, report.HostsCreated)
+		assert.LessOrEqual(t, report.PeakHosts, 10)
+		assert.GreaterOrEqual(t, report.P50QueueLatency, defaultSimulatorHostStartupTime)
+		assert.GreaterOrEqual(t, report.MaxQueueLatency, report.P90QueueLatency)
+		assert.GreaterOrEqual(t, report.Makespan, defaultSimulatorHostStartupTime+10*time.Minute)
+		assert.InDelta(t, 2*report.HostHours, report.Cost, 0.0001)
+		assert.Greater(t, report.Utilization, 0.0)
+		assert.LessOrEqual(t, report.Utilization, 1.0)
+	})
+	t.Run("IsDeterministic", func(t *testing.T) {
+		w := SimulatorWorkload{Distro: makeDistro(5), Tasks: append(makeTasks(15, 0, 7*time.Minute), makeTasks(5, time.Hour, 20*time.Minute)...)}
+		for i := range w.Tasks[15:] {
+			w.Tasks[15+i].ID = fmt.Sprintf("late%d", i)
+		}
+		first, err := Simulate(ctx, w, SimulatorOptions{})
+		require.NoError(t, err)
+		for i := 0; i < 3; i++ {
+			next, err := Simulate(ctx, w, SimulatorOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, first, next)
+		}
+	})
+	t.Run("WaitsForDependencies", func(t *testing.T) {
+		tasks := makeTasks(2, 0, 10*time.Minute)
+		tasks[1].DependsOn = []string{"t0"}
+		w := SimulatorWorkload{Distro: makeDistro(2), Tasks: tasks}
+		s, err := newSimulator(w, SimulatorOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 1, s.tasksByID["t0"].numDependents)
+
+		report, err := Simulate(ctx, w, SimulatorOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 2, report.TasksFinished)
+		assert.GreaterOrEqual(t, report.Makespan, defaultSimulatorHostStartupTime+20*time.Minute)
+	})
+	t.Run("MoreHostsLowerLatency", func(t *testing.T) {
+		w := SimulatorWorkload{Distro: makeDistro(2), Tasks: makeTasks(20, 0, 15*time.Minute)}
+		few, err := Simulate(ctx, w, SimulatorOptions{})
+		require.NoError(t, err)
+		many, err := Simulate(ctx, w, SimulatorOptions{
+			HostAllocatorSettings: &distro.HostAllocatorSettings{
+				Version:      evergreen.HostAllocatorUtilization,
+				MaximumHosts: 20,
+			},
+		})
+		require.NoError(t, err)
+
+		assert.Less(t, many.MeanQueueLatency, few.MeanQueueLatency)
+		assert.Less(t, many.Makespan, few.Makespan)
+		assert.Greater(t, many.PeakHosts, few.PeakHosts)
+	})
+	t.Run("GivesUpWithoutHosts", func(t *testing.T) {
+		w := SimulatorWorkload{Distro: makeDistro(0), Tasks: makeTasks(3, 0, time.Minute)}
+		report, err := Simulate(ctx, w, SimulatorOptions{MaxDuration: time.Hour})
+		require.NoError(t, err)
+		assert.Zero(t, report.TasksFinished)
+		assert.Equal(t, 3, report.TasksUnfinished)
+		assert.Zero(t, report.HostsCreated)
+	})
+	t.Run("StopsOnUnrunnableTasks", func(t *testing.T) {
+		tasks := makeTasks(3, 0, time.Minute)
+		tasks[1].DependsOn = []string{"t2"}
+		tasks[2].DependsOn = []string{"t1"}
+		report, err := Simulate(ctx, SimulatorWorkload{Distro: makeDistro(2), Tasks: tasks}, SimulatorOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.TasksFinished)
+		assert.Equal(t, 2, report.TasksUnfinished)
+	})
+	t.Run("RejectsInvalidWorkloads", func(t *testing.T) {
+		tasks := makeTasks(2, 0, time.Minute)
+		tasks[1].ID = tasks[0].ID
+		_, err := Simulate(ctx, SimulatorWorkload{Distro: makeDistro(1), Tasks: tasks}, SimulatorOptions{})
+		assert.ErrorContains(t, err, "duplicate")
+
+		tasks = makeTasks(1, 0, time.Minute)
+		tasks[0].DependsOn = []string{"nonexistent"}
+		_, err = Simulate(ctx, SimulatorWorkload{Distro: makeDistro(1), Tasks: tasks}, SimulatorOptions{})
+		assert.ErrorContains(t, err, "unknown task")
+
+		_, err = Simulate(ctx, SimulatorWorkload{Tasks: makeTasks(1, 0, time.Minute)}, SimulatorOptions{})
+		assert.ErrorContains(t, err, "distro")
+	})
+}
+
+func TestNewSimulatorWorkload(t *testing.T) {
+	start := time.Now().Round(time.Second)
+	tasks := []task.Task{
+		{
+			Id:            "second",
+			ActivatedTime: start.Add(time.Minute),
+			StartTime:     start.Add(2 * time.Minute),
+			FinishTime:    start.Add(5 * time.Minute),
+			DependsOn:     []task.Dependency{{TaskId: "first"}, {TaskId: "elsewhere"}},
+		},
+		{
+			Id:               "first",
+			ActivatedTime:    start,
+			TimeTaken:        time.Minute,
+			ExpectedDuration: 2 * time.Minute,
+		},
+	}
+
+	w := NewSimulatorWorkload(distro.Distro{Id: "d"}, tasks)
+	assert.Equal(t, "d", w.Distro.Id)
+	require.Len(t, w.Tasks, 2)
+
+	assert.Equal(t, "first", w.Tasks[0].ID)
+	assert.Zero(t, w.Tasks[0].ArrivesAt)
+	assert.Equal(t, time.Minute, w.Tasks[0].Duration)
+	assert.Equal(t, 2*time.Minute, w.Tasks[0].ExpectedDuration)
+
+	assert.Equal(t, "second", w.Tasks[1].ID)
+	assert.Equal(t, time.Minute, w.Tasks[1].ArrivesAt)
+	assert.Equal(t, 3*time.Minute, w.Tasks[1].Duration)
+	assert.Equal(t, []string{"first"}, w.Tasks[1].DependsOn)
+}
+
+func TestSimulatorWorkloadJSON(t *testing.T) {
+	t.Run("UsesDistroRESTFormat", func(t *testing.T) {
+		data := []byte(`{
+			"distro": {
+				"name": "d",
+				"provider": "ec2-fleet",
+				"planner_settings": {"version": "tunable", "target_time": 1800000},
+				"host_allocator_settings": {"version": "utilization", "maximum_hosts": 5}
+			},
+			"tasks": [{"id": "t", "arrives_at": 0, "duration": 60000000000}]
+		}`)
+		w := SimulatorWorkload{}
+		require.NoError(t, json.Unmarshal(data, &w))
+		assert.Equal(t, "d", w.Distro.Id)
+		assert.Equal(t, 30*time.Minute, w.Distro.PlannerSettings.TargetTime)
+		assert.Equal(t, 5, w.Distro.HostAllocatorSettings.MaximumHosts)
+		require.Len(t, w.Tasks, 1)
+		assert.Equal(t, time.Minute, w.Tasks[0].Duration)
+	})
+	t.Run("RoundTrips", func(t *testing.T) {
+		w := NewSimulatorWorkload(distro.Distro{
+			Id:              "d",
+			Provider:        evergreen.ProviderNameEc2Fleet,
+			PlannerSettings: distro.PlannerSettings{Version: evergreen.PlannerVersionTunable, TargetTime: 30 * time.Minute},
+		}, []task.Task{{Id: "t", ActivatedTime: time.Now(), TimeTaken: time.Minute}})
+		data, err := json.Marshal(w)
+		require.NoError(t, err)
+
+		out := SimulatorWorkload{}
+		require.NoError(t, json.Unmarshal(data, &out))
+		assert.Equal(t, w.Distro.Id, out.Distro.Id)
+		assert.Equal(t, w.Distro.PlannerSettings.TargetTime, out.Distro.PlannerSettings.TargetTime)
+		assert.Equal(t, w.Tasks, out.Tasks)
+
+		_, err = Simulate(t.Context(), out, SimulatorOptions{})
+		assert.NoError(t, err)
+	})
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/utility"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
 	"github.com/mongodb/grip/recovery"
@@ -92,6 +93,8 @@ func UtilizationBasedHostAllocator(ctx context.Context, hostAllocatorData *HostA
 			ctx,
 			distro,
 			taskGroupData,
+			hostAllocatorData.RunningTasks,
+			hostAllocatorData.Now,
 			distro.HostAllocatorSettings.FutureHostFraction,
 			hostAllocatorData.DistroQueueInfo.MaxDurationThreshold,
 			maxHosts)
@@ -131,7 +134,7 @@ func UtilizationBasedHostAllocator(ctx context.Context, hostAllocatorData *HostA
 // evalHostUtilization calculates the number of hosts needed by taking the total task scheduled task time
 // and dividing it by the target duration. Request however many hosts are needed to achieve that minus the
 // number of free hosts
-func evalHostUtilization(ctx context.Context, d distro.Distro, taskGroupData TaskGroupData, futureHostFraction float64, maxDurationThreshold time.Duration, maxHosts int) (int, int, error) {
+func evalHostUtilization(ctx context.Context, d distro.Distro, taskGroupData TaskGroupData, runningTasks []task.Task, now time.Time, futureHostFraction float64, maxDurationThreshold time.Duration, maxHosts int) (int, int, error) {
 	existingHosts := taskGroupData.Hosts
 	taskGroupInfo := taskGroupData.Info
 	numLongRunningTasks := taskGroupInfo.CountDurationOverThreshold
@@ -153,7 +156,7 @@ func evalHostUtilization(ctx context.Context, d distro.Distro, taskGroupData Tas
 	// summing their estimated time left to completion, and dividing that number by maxDurationThreshold.
 	// That estimate is then multiplied by the futureHostFraction coefficient, which is a fraction that allows us
 	// to tune the final estimate up or down.
-	expectedNumFreeHosts, err := calcExistingFreeHosts(ctx, existingHosts, futureHostFraction, maxDurationThreshold)
+	expectedNumFreeHosts, err := calcExistingFreeHosts(ctx, existingHosts, runningTasks, now, futureHostFraction, maxDurationThreshold)
 	if err != nil {
 		return numNewHosts, expectedNumFreeHosts, err
 	}
@@ -281,8 +284,10 @@ func calcNewHostsNeeded(totalShortRunningTasksExpectedDuration, maxDurationPerHo
 }
 
 // calcExistingFreeHosts returns the number of hosts that are not running a task,
-// plus hosts that will soon be free scaled by some fraction
-func calcExistingFreeHosts(ctx context.Context, existingHosts []host.Host, futureHostFactor float64, maxDurationPerHost time.Duration) (int, error) {
+// plus hosts that will soon be free scaled by some fraction. If runningTasks is
+// nil, the tasks running on the hosts are looked up in the database. If now is
+// zero, the current time is used.
+func calcExistingFreeHosts(ctx context.Context, existingHosts []host.Host, runningTasks []task.Task, now time.Time, futureHostFactor float64, maxDurationPerHost time.Duration) (int, error) {
 	numFreeHosts := 0
 	if futureHostFactor > 1 {
 		return numFreeHosts, errors.New("future host factor cannot be greater than 1")
@@ -294,7 +299,7 @@ func calcExistingFreeHosts(ctx context.Context, existingHosts []host.Host, futur
 		}
 	}
 
-	soonToBeFree, err := getSoonToBeFreeHosts(ctx, existingHosts, futureHostFactor, maxDurationPerHost)
+	soonToBeFree, err := getSoonToBeFreeHosts(ctx, existingHosts, runningTasks, now, futureHostFactor, maxDurationPerHost)
 	if err != nil {
 		return 0, err
 	}
@@ -306,7 +311,7 @@ func calcExistingFreeHosts(ctx context.Context, existingHosts []host.Host, futur
 // to be free for some fraction of the next maxDurationPerHost interval
 // the final value is scaled by some fraction representing how confident we are that
 // the hosts will actually be free in the expected amount of time
-func getSoonToBeFreeHosts(ctx context.Context, existingHosts []host.Host, futureHostFraction float64, maxDurationPerHost time.Duration) (float64, error) {
+func getSoonToBeFreeHosts(ctx context.Context, existingHosts []host.Host, knownRunningTasks []task.Task, now time.Time, futureHostFraction float64, maxDurationPerHost time.Duration) (float64, error) {
 	runningTaskIds := []string{}
 
 	for _, existingDistroHost := range existingHosts {
@@ -319,9 +324,22 @@ func getSoonToBeFreeHosts(ctx context.Context, existingHosts []host.Host, future
 		return 0.0, nil
 	}
 
-	runningTasks, err := task.Find(ctx, task.ByIds(runningTaskIds))
-	if err != nil {
-		return 0.0, err
+	var runningTasks []task.Task
+	if knownRunningTasks != nil {
+		for _, t := range knownRunningTasks {
+			if utility.StringSliceContains(runningTaskIds, t.Id) {
+				runningTasks = append(runningTasks, t)
+			}
+		}
+	} else {
+		var err error
+		runningTasks, err = task.Find(ctx, task.ByIds(runningTaskIds))
+		if err != nil {
+			return 0.0, err
+		}
+	}
+	if now.IsZero() {
+		now = time.Now()
 	}
 
 	nums := make(chan float64, len(runningTasks))
@@ -342,7 +360,7 @@ func getSoonToBeFreeHosts(ctx context.Context, existingHosts []host.Host, future
 				durationStats := t.FetchExpectedDuration(ctx)
 				expectedDuration := durationStats.Average
 				durationStdDev := durationStats.StdDev
-				elapsedTime := time.Since(t.StartTime)
+				elapsedTime := now.Sub(t.StartTime)
 				timeLeft := expectedDuration - elapsedTime
 
 				// calculate what fraction of the host will be free within the max duration.
@@ -218,7 +218,7 @@ func (s *UtilizationAllocatorSuite) TestCalcExistingFreeHosts() {
 	}
 	s.NoError(t3.Insert(s.T().Context()))
 
-	freeHosts, err := calcExistingFreeHosts(ctx, []host.Host{h1, h2, h3, h4, h5}, 1, evergreen.MaxDurationPerDistroHost)
+	freeHosts, err := calcExistingFreeHosts(ctx, []host.Host{h1, h2, h3, h4, h5}, nil, time.Time{}, 1, evergreen.MaxDurationPerDistroHost)
 	s.NoError(err)
 	s.Equal(3, freeHosts)
 }
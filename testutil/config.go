@@ -198,6 +198,17 @@ func MockConfig() *evergreen.Settings {
 				ClientID:    "oauth_client_id",
 				ConnectorID: "oauth_connector_id",
 			},
+			OIDC: &evergreen.OIDCConfig{
+				Issuer:        "oidc_issuer",
+				ClientID:      "oidc_client_id",
+				ClientSecret:  "oidc_client_secret",
+				UsernameClaim: "preferred_username",
+				GroupsClaim:   "groups",
+				RoleMappings: []evergreen.OIDCRoleMapping{
+					{Group: "evergreen-admins", Roles: []string{"superuser"}},
+				},
+				ExpireAfterMinutes: 60,
+			},
 			BackgroundReauthMinutes: 60,
 		},
 		OktaServiceConfig: evergreen.OktaServiceConfig{
@@ -229,6 +240,15 @@ func MockConfig() *evergreen.Settings {
 				Secret: "aws_secret",
 				Bucket: "credentials_bucket",
 			},
+			CacheStores: []evergreen.CacheStoreConfig{
+				{
+					Bucket:  "cache_store_bucket",
+					Prefix:  "cache-store",
+					Region:  "us-east-1",
+					RoleARN: "arn:aws:iam::123456789012:role/cache-store",
+				},
+			},
+			CacheManifestTTLDays: 14,
 		},
 		ConfigDir: "cfg_dir",
 		Cost: evergreen.CostConfig{
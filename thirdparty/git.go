@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -298,20 +299,85 @@ func GitCloneMinimal(ctx context.Context, owner, repo, revision string) (string,
 		return "", errors.Wrap(err, "creating GitHub app installation token")
 	}
 
-	tmpDir, err := os.MkdirTemp("", fmt.Sprintf("git-clone-%s-%s-%s-", owner, repo, revision))
+	repoURL := FormGitURLForApp(owner, repo, token)
+	return gitCloneMinimal(ctx, repoURL, fmt.Sprintf("%s/%s", owner, repo), GitCloneOptions{
+		Revision: revision,
+		Depth:    1,
+	})
+}
+
+// GitCloneOptions configure a minimal git clone.
+type GitCloneOptions struct {
+	// Revision is the commit or ref to clone. Cannot be combined with Branch.
+	Revision string
+	// Branch is the branch to clone. Cannot be combined with Revision.
+	Branch string
+	// Depth is the number of commits of history to fetch. If it is zero, the
+	// full history is fetched.
+	Depth int
+}
+
+// Validate checks that the clone options are valid.
+func (o GitCloneOptions) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	catcher.NewWhen(o.Revision == "" && o.Branch == "", "must specify either a revision or a branch to clone")
+	catcher.NewWhen(o.Revision != "" && o.Branch != "", "cannot specify both a revision and a branch to clone")
+	catcher.NewWhen(o.Depth < 0, "clone depth cannot be negative")
+	return catcher.Resolve()
+}
+
+// GitCloneMinimalFromURL performs a minimal git clone of the repository at the
+// given git remote URL. Like GitCloneMinimal, the clone contains only git
+// metadata and no file content, which is fetched on demand when it is read.
+// Any credentials needed to access the remote must be part of the URL.
+// Callers are expected to clean up the returned git directory when it is no
+// longer needed.
+func GitCloneMinimalFromURL(ctx context.Context, repoURL string, opts GitCloneOptions) (string, error) {
+	ctx, span := tracer.Start(ctx, "GitCloneMinimalFromURL", trace.WithAttributes(
+		attribute.String(githubRefAttribute, opts.Revision+opts.Branch),
+	))
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, GitOperationTimeout)
+	defer cancel()
+
+	return gitCloneMinimal(ctx, repoURL, redactGitURL(repoURL), opts)
+}
+
+// redactGitURL returns the git remote URL with any password removed so that it
+// can be logged.
+func redactGitURL(repoURL string) string {
+	u, err := url.Parse(repoURL)
 	if err != nil {
-		return "", errors.Wrap(err, "creating temp dir for git clone")
+		// SCP-like remotes (e.g. git@host:owner/repo.git) do not parse as URLs
+		// and cannot contain a password.
+		return repoURL
 	}
+	return u.Redacted()
+}
 
-	repoURL := FormGitURLForApp(owner, repo, token)
+func gitCloneMinimal(ctx context.Context, repoURL, name string, opts GitCloneOptions) (string, error) {
+	if err := opts.Validate(); err != nil {
+		return "", errors.Wrap(err, "invalid git clone options")
+	}
+
+	tmpDir, err := os.MkdirTemp("", "git-clone-")
+	if err != nil {
+		return "", errors.Wrap(err, "creating temp dir for git clone")
+	}
 
-	// Clone the repository with the bare minimum metadata for just the one
-	// commit. Don't fetch any actual file blobs yet.
-	cmd := exec.CommandContext(ctx, "git", "clone",
-		fmt.Sprintf("--revision=%s", revision),
-		// Shallow clone: only fetch the one commit rather than the full commit
-		// history.
-		"--depth=1",
+	args := []string{"clone"}
+	if opts.Revision != "" {
+		args = append(args, fmt.Sprintf("--revision=%s", opts.Revision))
+	} else {
+		args = append(args, fmt.Sprintf("--branch=%s", opts.Branch), "--single-branch")
+	}
+	if opts.Depth > 0 {
+		// Shallow clone: only fetch the requested commits rather than the full
+		// commit history.
+		args = append(args, fmt.Sprintf("--depth=%d", opts.Depth))
+	}
+	args = append(args,
 		// Partial clone: don't fetch any file blobs initially (so the repo
 		// contains no actual file contents).
 		"--filter=blob:none",
@@ -319,9 +385,15 @@ func GitCloneMinimal(ctx context.Context, owner, repo, revision string) (string,
 		"--no-checkout",
 		// Don't fetch tags as they're unnecessary extra data.
 		"--no-tags",
+		// Pass "--" so git never interprets the remote URL as an option.
+		"--",
 		repoURL,
 		tmpDir,
 	)
+
+	// Clone the repository with the bare minimum metadata for just the
+	// requested commits. Don't fetch any actual file blobs yet.
+	cmd := exec.CommandContext(ctx, "git", args...)
 	cmd.WaitDelay = gitOperationWaitDelay
 	var stdout, stderr strings.Builder
 	cmd.Stdout = &stdout
@@ -330,16 +402,17 @@ func GitCloneMinimal(ctx context.Context, owner, repo, revision string) (string,
 	if err := cmd.Run(); err != nil {
 		grip.Warning(ctx, message.WrapError(err, message.Fields{
 			"message":          "minimal git clone failed",
-			"owner":            owner,
-			"repo":             repo,
-			"revision":         revision,
+			"repo":             name,
+			"revision":         opts.Revision,
+			"branch":           opts.Branch,
+			"depth":            opts.Depth,
 			"stdout":           stdout.String(),
 			"stderr":           stderr.String(),
 			"is_context_error": ctx.Err() != nil,
 		}))
 		catcher := grip.NewBasicCatcher()
 		catcher.Add(ctx.Err())
-		catcher.Wrapf(err, "git cloning repo '%s/%s'", owner, repo)
+		catcher.Wrapf(err, "git cloning repo '%s'", name)
 		catcher.Wrap(os.RemoveAll(tmpDir), "cleaning up temp dir after failed git clone")
 		return "", catcher.Resolve()
 	}
@@ -457,6 +530,183 @@ func GitRestoreFile(ctx context.Context, owner, repo, revision, gitDir string, f
 	return contents, nil
 }
 
+// GitShowFile returns the contents of a file at the given revision within the
+// given git directory. Unlike GitRestoreFile, it does not modify the git
+// directory, so it is safe to call concurrently on the same directory.
+func GitShowFile(ctx context.Context, gitDir, revision, fileName string) ([]byte, error) {
+	ctx, span := tracer.Start(ctx, "GitShowFile", trace.WithAttributes(
+		attribute.String(githubRefAttribute, revision),
+		attribute.String(githubPathAttribute, fileName),
+	))
+	defer span.End()
+
+	// The requested file could be user-provided (e.g. an include file), which
+	// is not trusted.
+	if err := validateFileIsWithinDirectory(gitDir, fileName); err != nil {
+		return nil, errors.Wrapf(err, "validating file path '%s' is within git repo directory", fileName)
+	}
+
+	object := fmt.Sprintf("%s:%s", revision, filepath.ToSlash(filepath.Clean(fileName)))
+	// Check that the object exists first so that a missing file can be
+	// distinguished from other errors.
+	if _, err := runGitCommand(ctx, gitDir, "cat-file", "-e", object); err != nil {
+		if ctx.Err() == nil {
+			// To mirror GetGithubFile's behavior, return a FileNotFoundError
+			// if the file doesn't exist in the repo at the given revision.
+			return nil, FileNotFoundError{filepath: fileName}
+		}
+		return nil, errors.Wrapf(err, "checking that file '%s' exists", fileName)
+	}
+	contents, err := runGitCommand(ctx, gitDir, "cat-file", "blob", object)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading file '%s'", fileName)
+	}
+	return []byte(contents), nil
+}
+
+// GitCommit is a commit read from a local git directory.
+type GitCommit struct {
+	Hash        string
+	AuthorName  string
+	AuthorEmail string
+	Message     string
+	CommitTime  time.Time
+}
+
+const (
+	gitLogFieldSeparator  = "\x1f"
+	gitLogRecordSeparator = "\x1e"
+)
+
+// GitLog returns up to maxCount commits reachable from revision within the
+// given git directory, ordered from most to least recent. If maxCount is not
+// positive, all reachable commits are returned.
+func GitLog(ctx context.Context, gitDir, revision string, maxCount int) ([]GitCommit, error) {
+	ctx, span := tracer.Start(ctx, "GitLog", trace.WithAttributes(
+		attribute.String(githubRefAttribute, revision),
+	))
+	defer span.End()
+
+	format := strings.Join([]string{"%H", "%an", "%ae", "%ct", "%B"}, "%x1f") + "%x1e"
+	args := []string{"log", fmt.Sprintf("--format=%s", format)}
+	if maxCount > 0 {
+		args = append(args, fmt.Sprintf("--max-count=%d", maxCount))
+	}
+	// Pass "--end-of-options" so git never interprets the revision as an
+	// option.
+	out, err := runGitCommand(ctx, gitDir, append(args, "--end-of-options", revision, "--")...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting git log for revision '%s'", revision)
+	}
+
+	var commits []GitCommit
+	for _, record := range strings.Split(out, gitLogRecordSeparator) {
+		record = strings.TrimLeft(record, "\n")
+		if record == "" {
+			continue
+		}
+		fields := strings.SplitN(record, gitLogFieldSeparator, 5)
+		if len(fields) != 5 {
+			return nil, errors.Errorf("git log returned malformed commit '%s'", record)
+		}
+		timestamp, err := strconv.ParseInt(fields[3], 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing commit time of commit '%s'", fields[0])
+		}
+		commits = append(commits, GitCommit{
+			Hash:        fields[0],
+			AuthorName:  fields[1],
+			AuthorEmail: fields[2],
+			CommitTime:  time.Unix(timestamp, 0),
+			Message:     strings.TrimRight(fields[4], "\n"),
+		})
+	}
+	return commits, nil
+}
+
+// GitChangedFiles returns the files changed by the given revision within the
+// given git directory. Merge commits are compared against their first parent.
+// The git directory must contain the revision's first parent unless the
+// revision is a root commit.
+func GitChangedFiles(ctx context.Context, gitDir, revision string) ([]string, error) {
+	ctx, span := tracer.Start(ctx, "GitChangedFiles", trace.WithAttributes(
+		attribute.String(githubRefAttribute, revision),
+	))
+	defer span.End()
+
+	args := []string{"diff-tree", "-r", "-z", "--name-only", "--no-commit-id"}
+	if _, err := runGitCommand(ctx, gitDir, "rev-parse", "--verify", "--quiet", "--end-of-options", revision+"^1"); err == nil {
+		args = append(args, "--end-of-options", revision+"^1", revision)
+	} else {
+		args = append(args, "--root", "--end-of-options", revision)
+	}
+	out, err := runGitCommand(ctx, gitDir, args...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting files changed by revision '%s'", revision)
+	}
+
+	files := []string{}
+	for _, file := range strings.Split(out, "\x00") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// GitFetchRevision fetches a revision, and up to depth commits of its history,
+// from the origin remote of the given git directory, which is useful for a
+// revision that isn't on the cloned branch. Like a minimal clone, it doesn't
+// fetch any file blobs. If depth is zero, the full history is fetched.
+func GitFetchRevision(ctx context.Context, gitDir, revision string, depth int) error {
+	ctx, span := tracer.Start(ctx, "GitFetchRevision", trace.WithAttributes(
+		attribute.String(githubRefAttribute, revision),
+	))
+	defer span.End()
+
+	args := []string{"fetch", "--filter=blob:none", "--no-tags"}
+	if depth > 0 {
+		args = append(args, fmt.Sprintf("--depth=%d", depth))
+	}
+	_, err := runGitCommand(ctx, gitDir, append(args, "--end-of-options", "origin", revision)...)
+	return errors.Wrapf(err, "fetching revision '%s'", revision)
+}
+
+// GitMergeBase returns the best common ancestor of the given revisions within
+// the given git directory.
+func GitMergeBase(ctx context.Context, gitDir string, revisions ...string) (string, error) {
+	ctx, span := tracer.Start(ctx, "GitMergeBase")
+	defer span.End()
+
+	out, err := runGitCommand(ctx, gitDir, append([]string{"merge-base", "--end-of-options"}, revisions...)...)
+	if err != nil {
+		return "", errors.Wrap(err, "getting git merge base")
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// runGitCommand runs a git command that is expected to return quickly within
+// the given git directory and returns its standard output.
+func runGitCommand(ctx context.Context, gitDir string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, GitOperationTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = gitDir
+	cmd.WaitDelay = gitOperationWaitDelay
+	var stdout, stderr strings.Builder
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		catcher := grip.NewBasicCatcher()
+		catcher.Add(ctx.Err())
+		catcher.Wrapf(err, "running 'git %s': %s", args[0], strings.TrimSpace(stderr.String()))
+		return "", catcher.Resolve()
+	}
+	return stdout.String(), nil
+}
+
 // validateFileIsWithinDirectory ensures that the given file path is
 // contained within the specified directory.
 func validateFileIsWithinDirectory(dir, file string) error {
@@ -0,0 +1,238 @@
+package thirdparty
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/utility"
+	"github.com/pkg/errors"
+)
+
+const (
+	// gitLabCommitsPerPage is the number of commits requested per page when
+	// listing commits. This is the maximum GitLab allows.
+	gitLabCommitsPerPage = 100
+	// gitLabNextPageHeader is the header in which GitLab returns the number
+	// of the next page of a paginated response.
+	gitLabNextPageHeader = "X-Next-Page"
+	// gitLabTokenHeader is the header used to authenticate to GitLab with a
+	// personal, project or group access token.
+	gitLabTokenHeader = "PRIVATE-TOKEN"
+)
+
+// GitLabClient is a client for the GitLab REST API.
+type GitLabClient struct {
+	httpClient *http.Client
+	baseURL    string
+	token      string
+}
+
+// NewGitLabClient creates a new client for the GitLab instance at baseURL
+// (e.g. https://gitlab.com). If token is not empty, it is used to authenticate
+// requests. Callers must call Close when they are done with the client.
+func NewGitLabClient(baseURL, token string) (*GitLabClient, error) {
+	if baseURL == "" {
+		return nil, errors.New("GitLab base URL is not configured")
+	}
+	return &GitLabClient{
+		httpClient: utility.WithOTelTracing(utility.GetHTTPClient()),
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		token:      token,
+	}, nil
+}
+
+// Close releases resources used by the client.
+func (c *GitLabClient) Close() {
+	utility.PutHTTPClient(c.httpClient)
+}
+
+// GitLabCommit is a commit returned by the GitLab API.
+type GitLabCommit struct {
+	ID            string    `json:"id"`
+	Title         string    `json:"title"`
+	Message       string    `json:"message"`
+	AuthorName    string    `json:"author_name"`
+	AuthorEmail   string    `json:"author_email"`
+	AuthoredDate  time.Time `json:"authored_date"`
+	CommittedDate time.Time `json:"committed_date"`
+	ParentIDs     []string  `json:"parent_ids"`
+}
+
+// GitLabDiff is a single file's diff in a commit returned by the GitLab API.
+type GitLabDiff struct {
+	OldPath     string `json:"old_path"`
+	NewPath     string `json:"new_path"`
+	NewFile     bool   `json:"new_file"`
+	RenamedFile bool   `json:"renamed_file"`
+	DeletedFile bool   `json:"deleted_file"`
+}
+
+// GetCommits returns a page of the commits on the given ref of the project,
+// ordered from most to least recent. Pages start at 1. It also returns the
+// next page of commits, which is 0 if this is the last page.
+func (c *GitLabClient) GetCommits(ctx context.Context, projectPath, ref string, page int) ([]GitLabCommit, int, error) {
+	query := url.Values{}
+	query.Set("ref_name", ref)
+	query.Set("per_page", strconv.Itoa(gitLabCommitsPerPage))
+	if page > 0 {
+		query.Set("page", strconv.Itoa(page))
+	}
+
+	var commits []GitLabCommit
+	header, err := c.getJSON(ctx, c.projectURL(projectPath, "repository/commits", query), &commits)
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "getting commits for ref '%s' of GitLab project '%s'", ref, projectPath)
+	}
+
+	nextPage, err := parseGitLabNextPage(header)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "parsing next page of commits")
+	}
+	return commits, nextPage, nil
+}
+
+// GetCommit returns a single commit of the project.
+func (c *GitLabClient) GetCommit(ctx context.Context, projectPath, sha string) (*GitLabCommit, error) {
+	commit := &GitLabCommit{}
+	if _, err := c.getJSON(ctx, c.projectURL(projectPath, "repository/commits/"+url.PathEscape(sha), nil), commit); err != nil {
+		return nil, errors.Wrapf(err, "getting commit '%s' of GitLab project '%s'", sha, projectPath)
+	}
+	return commit, nil
+}
+
+// GetCommitDiff returns the file diffs of a single commit of the project.
+func (c *GitLabClient) GetCommitDiff(ctx context.Context, projectPath, sha string) ([]GitLabDiff, error) {
+	var diffs []GitLabDiff
+	page := 1
+	for page > 0 {
+		query := url.Values{}
+		query.Set("per_page", strconv.Itoa(gitLabCommitsPerPage))
+		query.Set("page", strconv.Itoa(page))
+
+		var pageDiffs []GitLabDiff
+		header, err := c.getJSON(ctx, c.projectURL(projectPath, fmt.Sprintf("repository/commits/%s/diff", url.PathEscape(sha)), query), &pageDiffs)
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting diff of commit '%s' of GitLab project '%s'", sha, projectPath)
+		}
+		diffs = append(diffs, pageDiffs...)
+
+		if page, err = parseGitLabNextPage(header); err != nil {
+			return nil, errors.Wrap(err, "parsing next page of diffs")
+		}
+	}
+	return diffs, nil
+}
+
+// GetMergeBase returns the SHA of the common ancestor of the given revisions
+// of the project.
+func (c *GitLabClient) GetMergeBase(ctx context.Context, projectPath string, revisions ...string) (string, error) {
+	query := url.Values{}
+	for _, rev := range revisions {
+		query.Add("refs[]", rev)
+	}
+
+	commit := &GitLabCommit{}
+	if _, err := c.getJSON(ctx, c.projectURL(projectPath, "repository/merge_base", query), commit); err != nil {
+		return "", errors.Wrapf(err, "getting merge base of GitLab project '%s'", projectPath)
+	}
+	if commit.ID == "" {
+		return "", errors.Errorf("GitLab returned no merge base for revisions %s", strings.Join(revisions, ", "))
+	}
+	return commit.ID, nil
+}
+
+// GetFileContent returns the raw contents of the file at the given path in
+// the project at the given ref. It returns a FileNotFoundError if the file
+// does not exist.
+func (c *GitLabClient) GetFileContent(ctx context.Context, projectPath, ref, path string) ([]byte, error) {
+	query := url.Values{}
+	query.Set("ref", ref)
+
+	resp, err := c.do(ctx, c.projectURL(projectPath, fmt.Sprintf("repository/files/%s/raw", url.PathEscape(path)), query))
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting file '%s' of GitLab project '%s'", path, projectPath)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, FileNotFoundError{filepath: path}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Wrapf(gitLabResponseError(resp), "getting file '%s' of GitLab project '%s'", path, projectPath)
+	}
+
+	contents, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, ResponseReadError{msg: err.Error()}
+	}
+	return contents, nil
+}
+
+// projectURL returns the API URL for the given resource of the project. The
+// project path (e.g. group/subgroup/project) is escaped as GitLab requires.
+func (c *GitLabClient) projectURL(projectPath, resource string, query url.Values) string {
+	u := fmt.Sprintf("%s/api/v4/projects/%s/%s", c.baseURL, url.PathEscape(projectPath), resource)
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return u
+}
+
+func (c *GitLabClient) do(ctx context.Context, u string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, APIRequestError{Message: err.Error()}
+	}
+	if c.token != "" {
+		req.Header.Set(gitLabTokenHeader, c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "making GitLab API request")
+	}
+	return resp, nil
+}
+
+func (c *GitLabClient) getJSON(ctx context.Context, u string, out any) (http.Header, error) {
+	resp, err := c.do(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, gitLabResponseError(resp)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, ResponseReadError{msg: err.Error()}
+	}
+	if err = json.Unmarshal(body, out); err != nil {
+		return nil, APIUnmarshalError{body: string(body), msg: err.Error()}
+	}
+	return resp.Header, nil
+}
+
+func gitLabResponseError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return APIResponseError{msg: fmt.Sprintf("GitLab returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))}
+}
+
+// parseGitLabNextPage returns the next page from the response headers, which
+// is 0 if there are no more pages.
+func parseGitLabNextPage(header http.Header) (int, error) {
+	next := header.Get(gitLabNextPageHeader)
+	if next == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(next)
+}
@@ -0,0 +1,117 @@
+package thirdparty
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitLabClient(t *testing.T) {
+	const projectPath = "group/subgroup/project"
+	const token = "token"
+	commits := []GitLabCommit{
+		{ID: "c3", Message: "third\n", AuthorName: "a", AuthorEmail: "a@example.com", CommittedDate: time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)},
+		{ID: "c2", Message: "second\n", AuthorName: "b", AuthorEmail: "b@example.com", CommittedDate: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{ID: "c1", Message: "first\n", AuthorName: "a", AuthorEmail: "a@example.com", CommittedDate: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	const projectPrefix = "/api/v4/projects/group%2Fsubgroup%2Fproject/"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(gitLabTokenHeader) != token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.EscapedPath() {
+		case projectPrefix + "repository/commits":
+			assert.Equal(t, "main", r.URL.Query().Get("ref_name"))
+			// Return two commits per page to exercise pagination.
+			switch page := r.URL.Query().Get("page"); page {
+			case "", "1":
+				w.Header().Set(gitLabNextPageHeader, "2")
+				assert.NoError(t, json.NewEncoder(w).Encode(commits[:2]))
+			case "2":
+				assert.NoError(t, json.NewEncoder(w).Encode(commits[2:]))
+			default:
+				t.Errorf("unexpected page '%s'", page)
+			}
+		case projectPrefix + "repository/commits/c2":
+			assert.NoError(t, json.NewEncoder(w).Encode(commits[1]))
+		case projectPrefix + "repository/commits/c2/diff":
+			assert.NoError(t, json.NewEncoder(w).Encode([]GitLabDiff{
+				{OldPath: "a.txt", NewPath: "a.txt"},
+				{OldPath: "old.txt", NewPath: "new.txt", RenamedFile: true},
+			}))
+		case projectPrefix + "repository/merge_base":
+			assert.Equal(t, []string{"c3", "c1"}, r.URL.Query()["refs[]"])
+			assert.NoError(t, json.NewEncoder(w).Encode(commits[2]))
+		case projectPrefix + "repository/files/dir%2Fevergreen.yml/raw":
+			assert.Equal(t, "c3", r.URL.Query().Get("ref"))
+			_, err := w.Write([]byte("tasks: []"))
+			assert.NoError(t, err)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := NewGitLabClient(srv.URL+"/", token)
+	require.NoError(t, err)
+	defer client.Close()
+
+	t.Run("GetCommitsPaginates", func(t *testing.T) {
+		page, nextPage, err := client.GetCommits(t.Context(), projectPath, "main", 1)
+		require.NoError(t, err)
+		assert.Equal(t, 2, nextPage)
+		require.Len(t, page, 2)
+		assert.Equal(t, "c3", page[0].ID)
+		assert.Equal(t, commits[0].CommittedDate, page[0].CommittedDate.UTC())
+
+		page, nextPage, err = client.GetCommits(t.Context(), projectPath, "main", nextPage)
+		require.NoError(t, err)
+		assert.Zero(t, nextPage)
+		require.Len(t, page, 1)
+		assert.Equal(t, "c1", page[0].ID)
+	})
+	t.Run("GetCommit", func(t *testing.T) {
+		commit, err := client.GetCommit(t.Context(), projectPath, "c2")
+		require.NoError(t, err)
+		assert.Equal(t, "second\n", commit.Message)
+		assert.Equal(t, "b@example.com", commit.AuthorEmail)
+	})
+	t.Run("GetCommitDiff", func(t *testing.T) {
+		diffs, err := client.GetCommitDiff(t.Context(), projectPath, "c2")
+		require.NoError(t, err)
+		require.Len(t, diffs, 2)
+		assert.Equal(t, "new.txt", diffs[1].NewPath)
+		assert.True(t, diffs[1].RenamedFile)
+	})
+	t.Run("GetMergeBase", func(t *testing.T) {
+		base, err := client.GetMergeBase(t.Context(), projectPath, "c3", "c1")
+		require.NoError(t, err)
+		assert.Equal(t, "c1", base)
+	})
+	t.Run("GetFileContent", func(t *testing.T) {
+		contents, err := client.GetFileContent(t.Context(), projectPath, "c3", "dir/evergreen.yml")
+		require.NoError(t, err)
+		assert.Equal(t, "tasks: []", string(contents))
+	})
+	t.Run("GetFileContentReturnsFileNotFound", func(t *testing.T) {
+		_, err := client.GetFileContent(t.Context(), projectPath, "c3", "missing.yml")
+		require.Error(t, err)
+		assert.True(t, IsFileNotFound(err))
+	})
+	t.Run("ErrorStatusFails", func(t *testing.T) {
+		unauthorized, err := NewGitLabClient(srv.URL, "wrong")
+		require.NoError(t, err)
+		defer unauthorized.Close()
+
+		_, _, err = unauthorized.GetCommits(t.Context(), projectPath, "main", 1)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "401")
+	})
+}
@@ -223,6 +223,7 @@ func (t *buildTriggers) makeData(ctx context.Context, sub *event.Subscription, p
 		URL:             t.build.GetURL(t.uiConfig.Url),
 		PastTenseStatus: t.data.Status,
 		apiModel:        &api,
+		Build:           t.build,
 	}
 
 	if t.data.GithubCheckStatus != "" {
@@ -0,0 +1,239 @@
+package trigger
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	// digestFailedTestsLimit is the maximum number of failed test names
+	// recorded for each item in a digest.
+	digestFailedTestsLimit = 10
+
+	digestObject = "digest"
+)
+
+const emailDigestContentTemplateString = `{{ define "content"}}
+<p>Hi,</p>
+
+<p>{{ .Headline }}</p>
+<ul>
+{{ range .Items }}
+<li><a href="{{ .URL }}">{{ .DisplayName }}</a> ({{ .Object }} in '{{ .Project }}'{{ if .BuildVariant }} on '{{ .BuildVariant }}'{{ end }}) has {{ .Status }}.{{ if .FailedTests }} Failed tests: {{ range $i, $test := .FailedTests }}{{ if $i }}, {{ end }}{{ $test }}{{ end }}{{ end }}</li>
+{{ end }}
+</ul>
+{{ if .Omitted }}<p>...and {{ .Omitted }} more.</p>{{ end }}
+
+<p>Digest: {{ .DigestID }}</p>
+{{ end }}`
+
+var emailDigestContentTemplate = template.Must(template.New("content").Parse(emailDigestContentTemplateString))
+
+type digestTemplateData struct {
+	DigestID string
+	Headline string
+	Items    []notification.DigestItem
+	Omitted  int
+	Headers  http.Header
+}
+
+// digestWebhookBody is the JSON body of a digest sent to a webhook.
+type digestWebhookBody struct {
+	Mode      event.DigestMode          `json:"mode"`
+	VersionID string                    `json:"version_id,omitempty"`
+	Total     int                       `json:"total"`
+	Failed    int                       `json:"failed"`
+	Items     []notification.DigestItem `json:"items"`
+	CreatedAt time.Time                 `json:"created_at"`
+}
+
+// supportsDigest returns whether notifications for the subscription should be
+// collected into a digest rather than sent individually.
+func supportsDigest(sub *event.Subscription) bool {
+	return sub.Digest.IsEnabled() && utility.StringSliceContains(event.DigestSubscriberTypes, sub.Subscriber.Type)
+}
+
+// makeDigestItem summarizes the notification data as a single item of a
+// digest.
+func makeDigestItem(sub *event.Subscription, data *commonTemplateData) *notification.DigestItem {
+	item := &notification.DigestItem{
+		EventID:        data.EventID,
+		SubscriptionID: sub.ID,
+		Trigger:        sub.Trigger,
+		Object:         data.Object,
+		ID:             data.ID,
+		DisplayName:    data.DisplayName,
+		Project:        data.Project,
+		Status:         data.PastTenseStatus,
+		URL:            data.URL,
+		Time:           time.Now(),
+	}
+
+	switch {
+	case data.Task != nil:
+		item.VersionID = data.Task.Version
+		item.BuildVariant = data.Task.BuildVariant
+		item.Failed = evergreen.IsFailedTaskStatus(data.Task.Status)
+	case data.Build != nil:
+		item.VersionID = data.Build.Version
+		item.BuildVariant = data.Build.BuildVariant
+		item.Failed = data.Build.Status == evergreen.BuildFailed
+	default:
+		// Versions and patches share their ID with their version.
+		item.VersionID = data.ID
+		item.Failed = data.PastTenseStatus == evergreen.VersionFailed
+	}
+
+	for _, test := range data.FailedTests {
+		if len(item.FailedTests) >= digestFailedTestsLimit {
+			break
+		}
+		item.FailedTests = append(item.FailedTests, test.GetDisplayTestName())
+	}
+
+	return item
+}
+
+// DigestPayload builds the summary payload for the digest's subscriber.
+func DigestPayload(d *notification.Digest) (any, error) {
+	switch d.Subscriber.Type {
+	case event.SlackSubscriberType:
+		return slackDigest(d), nil
+	case event.EmailSubscriberType:
+		return emailDigest(d)
+	case event.EvergreenWebhookSubscriberType:
+		return webhookDigest(d)
+	}
+
+	return nil, errors.Errorf("subscriber type '%s' does not support digests", d.Subscriber.Type)
+}
+
+// digestListedItems returns the items a digest summary lists, which are its
+// failures if there are any and otherwise all of its items.
+func digestListedItems(d *notification.Digest) []notification.DigestItem {
+	if failed := d.FailedItems(); len(failed) > 0 {
+		return failed
+	}
+	return d.UniqueItems()
+}
+
+func digestHeadline(d *notification.Digest) string {
+	total := len(d.UniqueItems()) + d.Total - len(d.Items)
+	failed := len(d.FailedItems())
+	headline := fmt.Sprintf("Evergreen digest: %d of %d notifications since %s were failures.", failed, total, d.CreatedAt.UTC().Format(time.RFC822))
+	if d.Mode == event.DigestModeVersion {
+		headline = fmt.Sprintf("Evergreen digest for version '%s': %d of %d notifications were failures.", d.VersionID, failed, total)
+	}
+	return headline
+}
+
+func digestHeaders(d *notification.Digest) http.Header {
+	headerMap := map[string][]string{
+		event.SelectorObject: {digestObject},
+		"digest-mode":        {string(d.Mode)},
+		"digest-items":       {strconv.Itoa(len(d.UniqueItems()))},
+	}
+	if d.VersionID != "" {
+		headerMap[event.SelectorInVersion] = []string{d.VersionID}
+	}
+	return makeHeaders(headerMap)
+}
+
+func slackDigest(d *notification.Digest) *notification.SlackPayload {
+	items := digestListedItems(d)
+	attachments := []message.SlackAttachment{}
+	for i, item := range items {
+		if i == slackAttachmentsLimit-1 && len(items) > slackAttachmentsLimit {
+			attachments = append(attachments, message.SlackAttachment{
+				Text: fmt.Sprintf("...and %d more", len(items)-i),
+			})
+			break
+		}
+		color := evergreenSuccessColor
+		if item.Failed {
+			color = evergreenFailColor
+		}
+		fields := []*message.SlackAttachmentField{
+			{Title: "Project", Value: item.Project, Short: true},
+			{Title: "Status", Value: item.Status, Short: true},
+		}
+		if item.BuildVariant != "" {
+			fields = append(fields, &message.SlackAttachmentField{Title: "Build Variant", Value: item.BuildVariant, Short: true})
+		}
+		if len(item.FailedTests) > 0 {
+			fields = append(fields, &message.SlackAttachmentField{Title: "Failed Tests", Value: strconv.Itoa(len(item.FailedTests)), Short: true})
+		}
+		attachments = append(attachments, message.SlackAttachment{
+			Title:     item.DisplayName,
+			TitleLink: item.URL,
+			Color:     color,
+			Fields:    fields,
+		})
+	}
+	if len(attachments) > 0 {
+		attachments[len(attachments)-1].Footer = fmt.Sprintf("Digest: %s", d.ID)
+	}
+
+	return &notification.SlackPayload{
+		Body:        digestHeadline(d),
+		Attachments: attachments,
+	}
+}
+
+func emailDigest(d *notification.Digest) (*message.Email, error) {
+	bodyTmpl, err := emailBodyTemplate.Clone()
+	if err != nil {
+		return nil, errors.Wrap(err, "cloning email body template")
+	}
+	if _, err = bodyTmpl.AddParseTree("content", emailDigestContentTemplate.Tree); err != nil {
+		return nil, errors.Wrap(err, "adding email digest content")
+	}
+
+	items := digestListedItems(d)
+	data := digestTemplateData{
+		DigestID: d.ID,
+		Headline: digestHeadline(d),
+		Items:    items,
+		Omitted:  d.Total - len(d.Items),
+		Headers:  digestHeaders(d),
+	}
+	buf := &bytes.Buffer{}
+	if err = bodyTmpl.ExecuteTemplate(buf, "emailbody", data); err != nil {
+		return nil, errors.Wrap(err, "executing email digest template")
+	}
+
+	m := message.Email{
+		Subject:           data.Headline,
+		Body:              buf.String(),
+		PlainTextContents: false,
+		Headers:           data.Headers,
+	}
+	// prevent Gmail from threading digests with similar subjects
+	m.Headers["X-Entity-Ref-Id"] = []string{fmt.Sprintf("%s-%s-%d", digestObject, d.ID, d.CreatedAt.UnixNano())}
+
+	return &m, nil
+}
+
+func webhookDigest(d *notification.Digest) (*util.EvergreenWebhook, error) {
+	items := d.UniqueItems()
+	return webhookPayload(digestWebhookBody{
+		Mode:      d.Mode,
+		VersionID: d.VersionID,
+		Total:     len(items) + d.Total - len(d.Items),
+		Failed:    len(d.FailedItems()),
+		Items:     items,
+		CreatedAt: d.CreatedAt,
+	}, digestHeaders(d))
+}
@@ -0,0 +1,198 @@
+package trigger
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/testresult"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/grip/message"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMakeCommonPayloadWithDigest(t *testing.T) {
+	sub := &event.Subscription{
+		ID:      "sub",
+		Trigger: event.TriggerFailure,
+		Subscriber: event.Subscriber{
+			Type:   event.SlackSubscriberType,
+			Target: "#evergreen",
+		},
+		Digest: &event.DigestOptions{Mode: event.DigestModeVersion},
+	}
+
+	t.Run("Task", func(t *testing.T) {
+		data := &commonTemplateData{
+			ID:              "t1",
+			EventID:         "e1",
+			DisplayName:     "compile",
+			Object:          event.ObjectTask,
+			Project:         "mci",
+			URL:             "https://example.com/task/t1",
+			PastTenseStatus: evergreen.TaskFailed,
+			Task:            &task.Task{Id: "t1", Version: "v1", BuildVariant: "ubuntu", Status: evergreen.TaskFailed},
+			FailedTests: []testresult.TestResult{
+				{TestName: "test1", Status: evergreen.TestFailedStatus},
+				{TestName: "test2", Status: evergreen.TestFailedStatus},
+			},
+		}
+		item := makeDigestItem(sub, data)
+		assert.Equal(t, "e1", item.EventID)
+		assert.Equal(t, "sub", item.SubscriptionID)
+		assert.Equal(t, "v1", item.VersionID)
+		assert.Equal(t, "ubuntu", item.BuildVariant)
+		assert.True(t, item.Failed)
+		assert.Equal(t, []string{"test1", "test2"}, item.FailedTests)
+	})
+	t.Run("Build", func(t *testing.T) {
+		data := &commonTemplateData{
+			ID:              "b1",
+			Object:          event.ObjectBuild,
+			PastTenseStatus: evergreen.BuildSucceeded,
+			Build:           &build.Build{Id: "b1", Version: "v1", BuildVariant: "ubuntu", Status: evergreen.BuildSucceeded},
+		}
+		payload, err := makeCommonPayload(sub, event.Attributes{}, data)
+		require.NoError(t, err)
+		item, ok := payload.(*notification.DigestItem)
+		require.True(t, ok)
+		assert.Equal(t, "v1", item.VersionID)
+		assert.False(t, item.Failed)
+	})
+	t.Run("Version", func(t *testing.T) {
+		data := &commonTemplateData{
+			ID:              "v1",
+			Object:          event.ObjectVersion,
+			PastTenseStatus: evergreen.VersionFailed,
+		}
+		payload, err := makeCommonPayload(sub, event.Attributes{}, data)
+		require.NoError(t, err)
+		item, ok := payload.(*notification.DigestItem)
+		require.True(t, ok)
+		assert.Equal(t, "v1", item.VersionID)
+		assert.True(t, item.Failed)
+	})
+	t.Run("UnsupportedSubscriber", func(t *testing.T) {
+		jiraSub := *sub
+		jiraSub.Subscriber = event.Subscriber{
+			Type:   event.JIRACommentSubscriberType,
+			Target: "EVG-1234",
+		}
+		data := &commonTemplateData{
+			ID:              "v1",
+			Object:          event.ObjectVersion,
+			PastTenseStatus: evergreen.VersionFailed,
+		}
+		payload, err := makeCommonPayload(&jiraSub, event.Attributes{}, data)
+		require.NoError(t, err)
+		_, ok := payload.(*notification.DigestItem)
+		assert.False(t, ok)
+	})
+}
+
+func makeTestDigest(subscriber event.Subscriber, numFailed, numPassed int) *notification.Digest {
+	d := &notification.Digest{
+		ID:         "digest",
+		Subscriber: subscriber,
+		Mode:       event.DigestModeVersion,
+		VersionID:  "v1",
+		CreatedAt:  time.Now(),
+	}
+	for i := 0; i < numFailed+numPassed; i++ {
+		status := evergreen.TaskSucceeded
+		if i < numFailed {
+			status = evergreen.TaskFailed
+		}
+		d.Items = append(d.Items, notification.DigestItem{
+			EventID:     fmt.Sprintf("e%d", i),
+			Object:      event.ObjectTask,
+			ID:          fmt.Sprintf("t%d", i),
+			DisplayName: fmt.Sprintf("task-%d", i),
+			Project:     "mci",
+			VersionID:   "v1",
+			Status:      status,
+			Failed:      i < numFailed,
+			URL:         fmt.Sprintf("https://example.com/task/t%d", i),
+		})
+	}
+	d.Total = len(d.Items)
+	return d
+}
+
+func TestDigestPayload(t *testing.T) {
+	t.Run("Slack", func(t *testing.T) {
+		d := makeTestDigest(event.Subscriber{Type: event.SlackSubscriberType, Target: "#evergreen"}, 2, 3)
+		payload, err := DigestPayload(d)
+		require.NoError(t, err)
+		slackPayload, ok := payload.(*notification.SlackPayload)
+		require.True(t, ok)
+		assert.Equal(t, "Evergreen digest for version 'v1': 2 of 5 notifications were failures.", slackPayload.Body)
+		require.Len(t, slackPayload.Attachments, 2)
+		assert.Equal(t, "task-0", slackPayload.Attachments[0].Title)
+		assert.Equal(t, evergreenFailColor, slackPayload.Attachments[0].Color)
+		assert.Equal(t, "Digest: digest", slackPayload.Attachments[1].Footer)
+	})
+	t.Run("SlackTruncatesAttachments", func(t *testing.T) {
+		d := makeTestDigest(event.Subscriber{Type: event.SlackSubscriberType, Target: "#evergreen"}, 25, 0)
+		payload, err := DigestPayload(d)
+		require.NoError(t, err)
+		slackPayload, ok := payload.(*notification.SlackPayload)
+		require.True(t, ok)
+		require.Len(t, slackPayload.Attachments, slackAttachmentsLimit)
+		assert.Equal(t, "...and 16 more", slackPayload.Attachments[slackAttachmentsLimit-1].Text)
+	})
+	t.Run("SlackListsAllItemsWithoutFailures", func(t *testing.T) {
+		d := makeTestDigest(event.Subscriber{Type: event.SlackSubscriberType, Target: "#evergreen"}, 0, 3)
+		payload, err := DigestPayload(d)
+		require.NoError(t, err)
+		slackPayload, ok := payload.(*notification.SlackPayload)
+		require.True(t, ok)
+		assert.Len(t, slackPayload.Attachments, 3)
+	})
+	t.Run("Email", func(t *testing.T) {
+		email := "a@example.com"
+		d := makeTestDigest(event.Subscriber{Type: event.EmailSubscriberType, Target: &email}, 2, 1)
+		d.Items[0].FailedTests = []string{"test1", "test2"}
+		payload, err := DigestPayload(d)
+		require.NoError(t, err)
+		emailPayload, ok := payload.(*message.Email)
+		require.True(t, ok)
+		assert.Equal(t, "Evergreen digest for version 'v1': 2 of 3 notifications were failures.", emailPayload.Subject)
+		assert.Contains(t, emailPayload.Body, `<a href="https://example.com/task/t0">task-0</a>`)
+		assert.Contains(t, emailPayload.Body, "Failed tests: test1, test2")
+		assert.Contains(t, emailPayload.Body, `<a href="https://example.com/task/t1">task-1</a>`)
+		assert.NotContains(t, emailPayload.Body, "task-2")
+		assert.Equal(t, []string{"digest"}, emailPayload.Headers["X-Evergreen-object"])
+		assert.NotEmpty(t, emailPayload.Headers["X-Entity-Ref-Id"])
+	})
+	t.Run("Webhook", func(t *testing.T) {
+		d := makeTestDigest(event.Subscriber{Type: event.EvergreenWebhookSubscriberType, Target: &event.WebhookSubscriber{URL: "https://example.com"}}, 1, 1)
+		// A repeat of the same event from another subscription is omitted.
+		d.Items = append(d.Items, d.Items[0])
+		d.Total++
+		payload, err := DigestPayload(d)
+		require.NoError(t, err)
+		webhookPayload, ok := payload.(*util.EvergreenWebhook)
+		require.True(t, ok)
+		body := digestWebhookBody{}
+		require.NoError(t, json.Unmarshal(webhookPayload.Body, &body))
+		assert.Equal(t, event.DigestModeVersion, body.Mode)
+		assert.Equal(t, "v1", body.VersionID)
+		assert.Equal(t, 2, body.Total)
+		assert.Equal(t, 1, body.Failed)
+		assert.Len(t, body.Items, 2)
+		assert.Equal(t, []string{"v1"}, webhookPayload.Headers["X-Evergreen-in-version"])
+	})
+	t.Run("UnsupportedSubscriber", func(t *testing.T) {
+		d := makeTestDigest(event.Subscriber{Type: event.JIRACommentSubscriberType, Target: "EVG-1234"}, 1, 0)
+		_, err := DigestPayload(d)
+		assert.Error(t, err)
+	})
+}
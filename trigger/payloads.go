@@ -7,6 +7,7 @@ import (
 	"html/template"
 	"net/http"
 	"net/url"
+	"regexp"
 	ttemplate "text/template"
 
 	"github.com/evergreen-ci/evergreen"
@@ -237,6 +238,13 @@ const jiraIssueTitle string = "Evergreen {{ .Object }} '{{ .DisplayName }}' in '
 
 const slackTemplate string = `The {{ .Object }} <{{ .URL }}|{{ .DisplayName }}> in '{{ .Project }}' has {{ .PastTenseStatus }}!`
 
+// chatMarkdownTemplate is the message text for chat services that format
+// messages with Markdown, like Microsoft Teams and Mattermost.
+const chatMarkdownTemplate string = `The {{ .Object }} [{{ .DisplayName }}]({{ .URL }}) in '{{ .Project }}' has {{ .PastTenseStatus }}!`
+
+// slackLinkRegexp matches links in Slack's <url|text> format.
+var slackLinkRegexp = regexp.MustCompile(`<([^<>|]+)\|([^<>]+)>`)
+
 func makeHeaders(headerMap map[string][]string) http.Header {
 	headers := http.Header{}
 	for headerField, headerData := range headerMap {
@@ -368,6 +376,157 @@ func slack(t *commonTemplateData) (*notification.SlackPayload, error) {
 	}, nil
 }
 
+// slackToMarkdown converts Slack-formatted links in s to Markdown links.
+func slackToMarkdown(s string) string {
+	return slackLinkRegexp.ReplaceAllString(s, "[$2]($1)")
+}
+
+// adaptiveCardColor returns the Adaptive Card text color closest to the given
+// Slack attachment color.
+func adaptiveCardColor(slackColor string) string {
+	switch slackColor {
+	case evergreenSuccessColor:
+		return notification.AdaptiveCardGood
+	case evergreenFailColor:
+		return notification.AdaptiveCardAttention
+	case evergreenRunningColor:
+		return notification.AdaptiveCardWarning
+	default:
+		return ""
+	}
+}
+
+func chatMarkdownText(t *commonTemplateData) (string, error) {
+	textTmpl, err := ttemplate.New("chat-markdown").Parse(chatMarkdownTemplate)
+	if err != nil {
+		return "", errors.Wrap(err, "parsing chat message template")
+	}
+
+	buf := &bytes.Buffer{}
+	if err = textTmpl.Execute(buf, t); err != nil {
+		return "", errors.Wrap(err, "generating chat message text from template")
+	}
+	return buf.String(), nil
+}
+
+// teams returns a Microsoft Teams message with an Adaptive Card built from the
+// same attachments as the Slack message.
+func teams(t *commonTemplateData) (*notification.TeamsPayload, error) {
+	msg, err := chatMarkdownText(t)
+	if err != nil {
+		return nil, errors.Wrap(err, "making Teams message")
+	}
+
+	headline := notification.AdaptiveCardElement{
+		Type:   notification.AdaptiveCardTextBlock,
+		Text:   msg,
+		Size:   notification.AdaptiveCardMedium,
+		Weight: notification.AdaptiveCardBolder,
+		Wrap:   true,
+	}
+	if len(t.slack) > 0 {
+		headline.Color = adaptiveCardColor(t.slack[0].Color)
+	}
+	body := []notification.AdaptiveCardElement{headline}
+
+	for _, attachment := range t.slack {
+		title := attachment.Title
+		if attachment.TitleLink != "" {
+			title = fmt.Sprintf("[%s](%s)", attachment.Title, attachment.TitleLink)
+		}
+		if title != "" {
+			body = append(body, notification.AdaptiveCardElement{
+				Type:      notification.AdaptiveCardTextBlock,
+				Text:      title,
+				Weight:    notification.AdaptiveCardBolder,
+				Wrap:      true,
+				Separator: true,
+			})
+		}
+		if attachment.Text != "" {
+			body = append(body, notification.AdaptiveCardElement{
+				Type: notification.AdaptiveCardTextBlock,
+				Text: slackToMarkdown(attachment.Text),
+				Wrap: true,
+			})
+		}
+		if len(attachment.Fields) > 0 {
+			facts := notification.AdaptiveCardElement{Type: notification.AdaptiveCardFactSet}
+			for _, field := range attachment.Fields {
+				if field == nil {
+					continue
+				}
+				facts.Facts = append(facts.Facts, notification.AdaptiveCardFact{
+					Title: field.Title,
+					Value: slackToMarkdown(field.Value),
+				})
+			}
+			body = append(body, facts)
+		}
+	}
+
+	body = append(body, notification.AdaptiveCardElement{
+		Type:     notification.AdaptiveCardTextBlock,
+		Text:     fmt.Sprintf("Subscription: %s; Event: %s", t.SubscriptionID, t.EventID),
+		Size:     notification.AdaptiveCardSmall,
+		IsSubtle: true,
+		Wrap:     true,
+	})
+
+	actions := []notification.AdaptiveCardAction{
+		{
+			Type:  notification.AdaptiveCardOpenURL,
+			Title: fmt.Sprintf("View %s", t.Object),
+			URL:   t.URL,
+		},
+	}
+
+	return notification.NewTeamsPayload(body, actions), nil
+}
+
+// mattermost returns a message for a Mattermost-compatible incoming webhook
+// with the same attachments as the Slack message.
+func mattermost(t *commonTemplateData) (*notification.MattermostPayload, error) {
+	msg, err := chatMarkdownText(t)
+	if err != nil {
+		return nil, errors.Wrap(err, "making Mattermost message")
+	}
+
+	attachments := make([]notification.MattermostAttachment, 0, len(t.slack))
+	for _, attachment := range t.slack {
+		converted := notification.MattermostAttachment{
+			Fallback:  attachment.Fallback,
+			Color:     attachment.Color,
+			Title:     attachment.Title,
+			TitleLink: attachment.TitleLink,
+			Text:      slackToMarkdown(attachment.Text),
+			Footer:    attachment.Footer,
+		}
+		if converted.Fallback == "" {
+			converted.Fallback = attachment.Title
+		}
+		for _, field := range attachment.Fields {
+			if field == nil {
+				continue
+			}
+			converted.Fields = append(converted.Fields, notification.MattermostField{
+				Title: field.Title,
+				Value: slackToMarkdown(field.Value),
+				Short: field.Short,
+			})
+		}
+		attachments = append(attachments, converted)
+	}
+	if len(attachments) > 0 {
+		attachments[len(attachments)-1].Footer = fmt.Sprintf("Subscription: %s; Event: %s", t.SubscriptionID, t.EventID)
+	}
+
+	return &notification.MattermostPayload{
+		Text:        msg,
+		Attachments: attachments,
+	}, nil
+}
+
 // truncateString splits a string into two parts, with the following behavior:
 // If the entire string is <= capacity, it's returned unchanged.
 // Otherwise, the string is split at the (capacity-3)'th byte. The first string
@@ -402,6 +561,10 @@ func makeCommonPayload(sub *event.Subscription, eventAttributes event.Attributes
 		}
 	}
 
+	if supportsDigest(sub) {
+		return makeDigestItem(sub, data), nil
+	}
+
 	switch sub.Subscriber.Type {
 	case event.GithubPullRequestSubscriberType, event.GithubCheckSubscriberType, event.GithubMergeSubscriberType:
 		if len(data.githubDescription) == 0 {
@@ -432,6 +595,13 @@ func makeCommonPayload(sub *event.Subscription, eventAttributes event.Attributes
 
 	case event.SlackSubscriberType:
 		return slack(data)
+
+	case event.TeamsSubscriberType:
+		return teams(data)
+
+	case event.MattermostSubscriberType:
+		return mattermost(data)
+
 	case event.RunChildPatchSubscriberType:
 		return nil, nil
 	}
@@ -10,11 +10,13 @@ import (
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/notification"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/model/testresult"
 	restModel "github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/evergreen/testutil"
 	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/grip/message"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
@@ -173,6 +175,72 @@ func (s *payloadSuite) TestSlack() {
 	s.Empty(m.Attachments)
 }
 
+func (s *payloadSuite) TestTeams() {
+	s.t.slack = []message.SlackAttachment{
+		{
+			Title:     "display-1234",
+			TitleLink: s.url,
+			Color:     evergreenFailColor,
+			Fields: []*message.SlackAttachmentField{
+				{Title: "Build", Value: "<https://example.com/build/1|ubuntu>"},
+			},
+		},
+	}
+
+	m, err := teams(&s.t)
+	s.NoError(err)
+	s.Require().NotNil(m)
+
+	s.Equal("message", m.Type)
+	s.Require().Len(m.Attachments, 1)
+	s.Equal("application/vnd.microsoft.card.adaptive", m.Attachments[0].ContentType)
+	card := m.Attachments[0].Content
+	s.Equal("AdaptiveCard", card.Type)
+	s.Require().Len(card.Body, 4)
+	s.Equal("The patch [display-1234](https://example.com/patch/1234) in 'test' has failed!", card.Body[0].Text)
+	s.Equal(notification.AdaptiveCardAttention, card.Body[0].Color)
+	s.Equal("[display-1234](https://example.com/patch/1234)", card.Body[1].Text)
+	s.Equal(notification.AdaptiveCardFactSet, card.Body[2].Type)
+	s.Equal([]notification.AdaptiveCardFact{{Title: "Build", Value: "[ubuntu](https://example.com/build/1)"}}, card.Body[2].Facts)
+	s.Equal("Subscription: subscriptionid; Event: eventid", card.Body[3].Text)
+	s.Require().Len(card.Actions, 1)
+	s.Equal(s.url, card.Actions[0].URL)
+}
+
+func (s *payloadSuite) TestMattermost() {
+	m, err := mattermost(&s.t)
+	s.NoError(err)
+	s.Require().NotNil(m)
+
+	s.Equal("The patch [display-1234](https://example.com/patch/1234) in 'test' has failed!", m.Text)
+	s.Empty(m.Attachments)
+
+	s.t.slack = []message.SlackAttachment{
+		{
+			Title:     "Evergreen Patch",
+			TitleLink: s.url,
+			Color:     evergreenSuccessColor,
+			Text:      "see <https://example.com/host/1|the host>",
+			Fields: []*message.SlackAttachmentField{
+				{Title: "Time Taken", Value: "1m", Short: true},
+			},
+		},
+	}
+	m, err = mattermost(&s.t)
+	s.NoError(err)
+	s.Require().NotNil(m)
+	s.Require().Len(m.Attachments, 1)
+	s.Equal(notification.MattermostAttachment{
+		Fallback:  "Evergreen Patch",
+		Color:     evergreenSuccessColor,
+		Title:     "Evergreen Patch",
+		TitleLink: s.url,
+		Text:      "see [the host](https://example.com/host/1)",
+		Fields:    []notification.MattermostField{{Title: "Time Taken", Value: "1m", Short: true}},
+		Footer:    "Subscription: subscriptionid; Event: eventid",
+	}, m.Attachments[0])
+}
+
 func (s *payloadSuite) TestGetFailedTestsFromTemplate() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
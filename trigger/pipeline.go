@@ -0,0 +1,236 @@
+package trigger
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/manifest"
+	"github.com/evergreen-ci/evergreen/model/pipeline"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// triggerDownstreamPipelinesForBuild records the state of the build's version
+// in the runs of the pipelines that wait on its project, and creates the
+// downstream version of each run whose upstream projects have all succeeded.
+func triggerDownstreamPipelinesForBuild(ctx context.Context, b *build.Build, e *event.EventLogEntry, processor projectProcessor) ([]model.Version, error) {
+	if b.Requester != evergreen.RepotrackerVersionRequester && b.Requester != evergreen.GitTagRequester {
+		return nil, nil
+	}
+	downstreamProjects, err := model.FindPipelineDownstreamProjects(ctx, b.Project)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding pipeline downstream projects of project '%s'", b.Project)
+	}
+	if len(downstreamProjects) == 0 {
+		return nil, nil
+	}
+
+	sourceVersion, err := model.VersionFindOneId(ctx, b.Version)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding source version '%s'", b.Version)
+	}
+	if sourceVersion == nil {
+		return nil, errors.Errorf("source version '%s' not found", b.Version)
+	}
+	builds, err := build.Find(ctx, build.ByVersion(b.Version))
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding builds for version '%s'", b.Version)
+	}
+
+	catcher := grip.NewBasicCatcher()
+	versions := []model.Version{}
+	for _, ref := range downstreamProjects {
+		// A pipeline inherited from the repo can list one of the repo's own
+		// projects as an upstream, but a project cannot trigger itself, so
+		// that project's runs never trigger.
+		if ref.Id == b.Project {
+			continue
+		}
+		for _, def := range ref.Pipelines {
+			upstream := def.GetUpstream(b.Project)
+			if upstream == nil {
+				continue
+			}
+			status, err := pipelineUpstreamStatus(builds, upstream.BuildVariantRegex)
+			if err != nil {
+				catcher.Wrapf(err, "getting status of upstream project '%s' in pipeline '%s'", b.Project, def.ID)
+				continue
+			}
+			matchKeys, err := getPipelineMatchKeys(ctx, def, sourceVersion)
+			if err != nil {
+				catcher.Wrapf(err, "getting match keys for version '%s' in pipeline '%s'", sourceVersion.Id, def.ID)
+				continue
+			}
+
+			for _, matchKey := range matchKeys {
+				v, err := evalPipelineRun(ctx, ref, def, matchKey, sourceVersion, status, e, processor)
+				if err != nil {
+					catcher.Wrapf(err, "evaluating run '%s' of pipeline '%s' in project '%s'", matchKey, def.ID, ref.Id)
+					continue
+				}
+				if v != nil {
+					versions = append(versions, *v)
+				}
+			}
+		}
+	}
+
+	return versions, catcher.Resolve()
+}
+
+// evalPipelineRun records the upstream version's status in the pipeline's run
+// for the match key and, if that completes the run, creates its downstream
+// version.
+func evalPipelineRun(ctx context.Context, ref model.ProjectRef, def model.PipelineDefinition, matchKey string, sourceVersion *model.Version, status string, e *event.EventLogEntry, processor projectProcessor) (*model.Version, error) {
+	now := time.Now()
+	upstreamProjects := def.UpstreamProjects()
+	run, err := pipeline.SetUpstream(ctx, ref.Id, def.ID, matchKey, upstreamProjects, pipeline.Upstream{
+		ProjectID:  sourceVersion.Identifier,
+		Status:     status,
+		VersionID:  sourceVersion.Id,
+		Revision:   sourceVersion.Revision,
+		UpdateTime: now,
+	}, now)
+	if err != nil {
+		return nil, err
+	}
+	if !run.Ready(upstreamProjects) {
+		return nil, nil
+	}
+	claimed, err := pipeline.Claim(ctx, run.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, nil
+	}
+
+	args := ProcessorArgs{
+		SourceVersion:                sourceVersion,
+		DownstreamProject:            ref,
+		ConfigFile:                   def.ConfigFile,
+		TriggerType:                  model.ProjectTriggerLevelPipeline,
+		TriggerID:                    run.ID,
+		EventID:                      e.ID,
+		DefinitionID:                 def.ID,
+		Alias:                        def.Alias,
+		UnscheduleDownstreamVersions: def.UnscheduleDownstreamVersions,
+	}
+	v, err := processor(ctx, args)
+	if err != nil {
+		grip.Error(ctx, message.WrapError(pipeline.SetFailed(ctx, run.ID, err, time.Now()), message.Fields{
+			"message":    "could not mark pipeline run failed",
+			"run_id":     run.ID,
+			"project":    ref.Id,
+			"definition": def.ID,
+		}))
+		return nil, errors.Wrap(err, "creating downstream version")
+	}
+	if v == nil {
+		return nil, nil
+	}
+	if err = pipeline.SetDownstreamVersion(ctx, run.ID, v.Id, time.Now()); err != nil {
+		return v, err
+	}
+
+	grip.Info(ctx, message.Fields{
+		"message":            "pipeline run triggered downstream version",
+		"run_id":             run.ID,
+		"project":            ref.Id,
+		"definition":         def.ID,
+		"match_key":          matchKey,
+		"downstream_version": v.Id,
+	})
+	return v, nil
+}
+
+// pipelineUpstreamStatus returns whether the version with the given builds has
+// succeeded, failed or is still running as an upstream of a pipeline. Only the
+// activated builds whose variant matches the regex count.
+func pipelineUpstreamStatus(builds []build.Build, variantRegex string) (string, error) {
+	regex, err := regexp.Compile(variantRegex)
+	if err != nil {
+		return "", errors.Wrapf(err, "compiling build variant regexp '%s'", variantRegex)
+	}
+
+	numMatching := 0
+	allSucceeded := true
+	for _, b := range builds {
+		if !b.Activated || !regex.MatchString(b.BuildVariant) {
+			continue
+		}
+		numMatching++
+		if b.Status == evergreen.BuildFailed {
+			return pipeline.UpstreamStatusFailed, nil
+		}
+		if b.Status != evergreen.BuildSucceeded {
+			allSucceeded = false
+		}
+	}
+	if numMatching == 0 || !allSucceeded {
+		return pipeline.UpstreamStatusPending, nil
+	}
+	return pipeline.UpstreamStatusSucceeded, nil
+}
+
+// getPipelineMatchKeys returns the keys of the pipeline runs that the upstream
+// version is part of.
+func getPipelineMatchKeys(ctx context.Context, def model.PipelineDefinition, v *model.Version) ([]string, error) {
+	if def.MatchBy != model.PipelineMatchByManifest {
+		return pipelineMatchKeys(def, v, nil, nil), nil
+	}
+
+	upstreamRef, err := model.FindMergedProjectRef(ctx, v.Identifier, v.Id, false)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding project '%s'", v.Identifier)
+	}
+	if upstreamRef == nil {
+		return nil, errors.Errorf("project '%s' not found", v.Identifier)
+	}
+	m, err := manifest.FindOne(ctx, manifest.ById(v.Id))
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding manifest for version '%s'", v.Id)
+	}
+	return pipelineMatchKeys(def, v, upstreamRef, m), nil
+}
+
+// pipelineMatchKeys returns the revision, git tags or revision of the manifest
+// repo that the version shares with the other upstream versions in a run.
+func pipelineMatchKeys(def model.PipelineDefinition, v *model.Version, upstreamRef *model.ProjectRef, m *manifest.Manifest) []string {
+	switch def.MatchBy {
+	case model.PipelineMatchByGitTag:
+		tags := []string{}
+		seen := map[string]bool{}
+		for _, tag := range append([]model.GitTag{v.TriggeredByGitTag}, v.GitTags...) {
+			if tag.Tag == "" || seen[tag.Tag] {
+				continue
+			}
+			seen[tag.Tag] = true
+			tags = append(tags, tag.Tag)
+		}
+		return tags
+	case model.PipelineMatchByManifest:
+		owner, repo, _ := strings.Cut(def.ManifestRepo, "/")
+		if upstreamRef != nil && upstreamRef.Owner == owner && upstreamRef.Repo == repo {
+			return []string{v.Revision}
+		}
+		if m == nil {
+			return nil
+		}
+		for _, module := range m.Modules {
+			if module != nil && module.Owner == owner && module.Repo == repo && module.Revision != "" {
+				return []string{module.Revision}
+			}
+		}
+		return nil
+	default:
+		return []string{v.Revision}
+	}
+}
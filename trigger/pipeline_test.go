@@ -0,0 +1,187 @@
+package trigger
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/manifest"
+	"github.com/evergreen-ci/evergreen/model/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestPipelineUpstreamStatus(t *testing.T) {
+	for tName, tCase := range map[string]struct {
+		builds   []build.Build
+		regex    string
+		expected string
+	}{
+		"AllSucceeded": {
+			builds: []build.Build{
+				{BuildVariant: "linux", Activated: true, Status: evergreen.BuildSucceeded},
+				{BuildVariant: "windows", Activated: true, Status: evergreen.BuildSucceeded},
+			},
+			expected: pipeline.UpstreamStatusSucceeded,
+		},
+		"AnyFailed": {
+			builds: []build.Build{
+				{BuildVariant: "linux", Activated: true, Status: evergreen.BuildFailed},
+				{BuildVariant: "windows", Activated: true, Status: evergreen.BuildStarted},
+			},
+			expected: pipeline.UpstreamStatusFailed,
+		},
+		"SomeRunning": {
+			builds: []build.Build{
+				{BuildVariant: "linux", Activated: true, Status: evergreen.BuildSucceeded},
+				{BuildVariant: "windows", Activated: true, Status: evergreen.BuildStarted},
+			},
+			expected: pipeline.UpstreamStatusPending,
+		},
+		"IgnoresInactiveBuilds": {
+			builds: []build.Build{
+				{BuildVariant: "linux", Activated: true, Status: evergreen.BuildSucceeded},
+				{BuildVariant: "windows", Activated: false, Status: evergreen.BuildCreated},
+			},
+			expected: pipeline.UpstreamStatusSucceeded,
+		},
+		"IgnoresNonMatchingVariants": {
+			builds: []build.Build{
+				{BuildVariant: "linux", Activated: true, Status: evergreen.BuildSucceeded},
+				{BuildVariant: "windows", Activated: true, Status: evergreen.BuildFailed},
+			},
+			regex:    "^linux",
+			expected: pipeline.UpstreamStatusSucceeded,
+		},
+		"NoMatchingBuilds": {
+			builds: []build.Build{
+				{BuildVariant: "windows", Activated: true, Status: evergreen.BuildSucceeded},
+			},
+			regex:    "^linux",
+			expected: pipeline.UpstreamStatusPending,
+		},
+	} {
+		t.Run(tName, func(t *testing.T) {
+			status, err := pipelineUpstreamStatus(tCase.builds, tCase.regex)
+			require.NoError(t, err)
+			assert.Equal(t, tCase.expected, status)
+		})
+	}
+
+	t.Run("InvalidRegex", func(t *testing.T) {
+		_, err := pipelineUpstreamStatus(nil, "[")
+		assert.Error(t, err)
+	})
+}
+
+func TestPipelineMatchKeys(t *testing.T) {
+	v := &model.Version{
+		Id:                "v",
+		Revision:          "abc",
+		TriggeredByGitTag: model.GitTag{Tag: "v1.0"},
+		GitTags:           []model.GitTag{{Tag: "v1.0"}, {Tag: "latest"}},
+	}
+
+	t.Run("Revision", func(t *testing.T) {
+		def := model.PipelineDefinition{MatchBy: model.PipelineMatchByRevision}
+		assert.Equal(t, []string{"abc"}, pipelineMatchKeys(def, v, nil, nil))
+	})
+	t.Run("GitTag", func(t *testing.T) {
+		def := model.PipelineDefinition{MatchBy: model.PipelineMatchByGitTag}
+		assert.Equal(t, []string{"v1.0", "latest"}, pipelineMatchKeys(def, v, nil, nil))
+	})
+	t.Run("GitTagWithoutTags", func(t *testing.T) {
+		def := model.PipelineDefinition{MatchBy: model.PipelineMatchByGitTag}
+		assert.Empty(t, pipelineMatchKeys(def, &model.Version{Revision: "abc"}, nil, nil))
+	})
+
+	def := model.PipelineDefinition{MatchBy: model.PipelineMatchByManifest, ManifestRepo: "mongodb/mongo"}
+	t.Run("ManifestRepoIsUpstream", func(t *testing.T) {
+		upstreamRef := &model.ProjectRef{Owner: "mongodb", Repo: "mongo"}
+		assert.Equal(t, []string{"abc"}, pipelineMatchKeys(def, v, upstreamRef, nil))
+	})
+	t.Run("ManifestModule", func(t *testing.T) {
+		upstreamRef := &model.ProjectRef{Owner: "mongodb", Repo: "mongo-tools"}
+		m := &manifest.Manifest{Modules: map[string]*manifest.Module{
+			"enterprise": {Owner: "10gen", Repo: "enterprise", Revision: "def"},
+			"mongo":      {Owner: "mongodb", Repo: "mongo", Revision: "123"},
+		}}
+		assert.Equal(t, []string{"123"}, pipelineMatchKeys(def, v, upstreamRef, m))
+	})
+	t.Run("ManifestWithoutModule", func(t *testing.T) {
+		upstreamRef := &model.ProjectRef{Owner: "mongodb", Repo: "mongo-tools"}
+		assert.Empty(t, pipelineMatchKeys(def, v, upstreamRef, nil))
+		assert.Empty(t, pipelineMatchKeys(def, v, upstreamRef, &manifest.Manifest{}))
+	})
+}
+
+func TestTriggerDownstreamPipelines(t *testing.T) {
+	ctx := t.Context()
+	require.NoError(t, db.ClearCollections(build.Collection, model.VersionCollection, model.ProjectRefCollection, pipeline.Collection))
+
+	downstream := model.ProjectRef{
+		Id:      "downstream",
+		Enabled: true,
+		Pipelines: []model.PipelineDefinition{
+			{
+				ID: "release",
+				Upstreams: []model.PipelineUpstream{
+					{Project: "server"},
+					{Project: "tools", BuildVariantRegex: "^linux"},
+				},
+				MatchBy:    model.PipelineMatchByRevision,
+				ConfigFile: "release.yml",
+				Alias:      "release",
+			},
+		},
+	}
+	require.NoError(t, downstream.Insert(ctx))
+	for _, v := range []model.Version{
+		{Id: "server_v", Identifier: "server", Revision: "abc", Requester: evergreen.RepotrackerVersionRequester},
+		{Id: "tools_v", Identifier: "tools", Revision: "abc", Requester: evergreen.RepotrackerVersionRequester},
+	} {
+		require.NoError(t, v.Insert(ctx))
+	}
+	serverBuild := build.Build{Id: "server_b", Project: "server", Version: "server_v", BuildVariant: "linux", Activated: true, Status: evergreen.BuildSucceeded, Requester: evergreen.RepotrackerVersionRequester}
+	toolsBuild := build.Build{Id: "tools_b", Project: "tools", Version: "tools_v", BuildVariant: "linux", Activated: true, Status: evergreen.BuildStarted, Requester: evergreen.RepotrackerVersionRequester}
+	toolsWindowsBuild := build.Build{Id: "tools_windows_b", Project: "tools", Version: "tools_v", BuildVariant: "windows", Activated: true, Status: evergreen.BuildFailed, Requester: evergreen.RepotrackerVersionRequester}
+	for _, b := range []build.Build{serverBuild, toolsBuild, toolsWindowsBuild} {
+		require.NoError(t, b.Insert(ctx))
+	}
+	runID := pipeline.MakeRunID("downstream", "release", "abc")
+
+	versions, err := triggerDownstreamPipelinesForBuild(ctx, &serverBuild, &event.EventLogEntry{}, mockTriggerVersion)
+	require.NoError(t, err)
+	assert.Empty(t, versions, "tools has not succeeded yet")
+	run, err := pipeline.FindOneId(ctx, runID)
+	require.NoError(t, err)
+	require.NotNil(t, run)
+	assert.Equal(t, pipeline.RunStatusWaiting, run.Status)
+	require.NotNil(t, run.GetUpstream("server"))
+	assert.Equal(t, pipeline.UpstreamStatusSucceeded, run.GetUpstream("server").Status)
+	assert.Equal(t, "server_v", run.GetUpstream("server").VersionID)
+	require.NotNil(t, run.GetUpstream("tools"))
+	assert.Equal(t, pipeline.UpstreamStatusPending, run.GetUpstream("tools").Status)
+
+	toolsBuild.Status = evergreen.BuildSucceeded
+	require.NoError(t, build.UpdateOne(ctx, bson.M{build.IdKey: toolsBuild.Id}, bson.M{"$set": bson.M{build.StatusKey: evergreen.BuildSucceeded}}))
+	versions, err = triggerDownstreamPipelinesForBuild(ctx, &toolsBuild, &event.EventLogEntry{}, mockTriggerVersion)
+	require.NoError(t, err)
+	require.Len(t, versions, 1, "the failed windows build should not count")
+	assert.Equal(t, "downstream", versions[0].Branch)
+	assert.Equal(t, "release", versions[0].Message)
+	assert.Equal(t, model.ProjectTriggerLevelPipeline, versions[0].TriggerType)
+	assert.Equal(t, runID, versions[0].TriggerID)
+	run, err = pipeline.FindOneId(ctx, runID)
+	require.NoError(t, err)
+	require.NotNil(t, run)
+	assert.Equal(t, pipeline.RunStatusTriggered, run.Status)
+
+	versions, err = triggerDownstreamPipelinesForBuild(ctx, &serverBuild, &event.EventLogEntry{}, mockTriggerVersion)
+	require.NoError(t, err)
+	assert.Empty(t, versions, "a run should only be triggered once")
+}
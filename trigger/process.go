@@ -22,6 +22,9 @@ import (
 // a slice of notifications, and an error object representing all errors
 // that occurred while processing triggers
 
+// Notifications for subscriptions with digests enabled are added to the
+// subscriber's pending digest instead of being returned.
+//
 // It is possible for this function to return notifications and errors at the
 // same time. If the notifications array is not nil, they are valid and should
 // be processed as normal.
@@ -82,6 +85,17 @@ func NotificationsFromEvent(ctx context.Context, e *event.EventLogEntry) ([]noti
 		if n == nil {
 			continue
 		}
+		n.SubscriptionID = subscriptions[i].ID
+		if item, ok := n.Payload.(*notification.DigestItem); ok {
+			if err = notification.AddToDigest(ctx, &subscriptions[i], *item); err != nil {
+				catcher.Add(err)
+				grip.Error(ctx, message.WrapError(err, msg))
+				continue
+			}
+			msg["message"] = "processed subscription and added notification to digest"
+			grip.Info(ctx, msg)
+			continue
+		}
 		grip.Info(ctx, msg)
 
 		notifications = append(notifications, *n)
@@ -137,7 +151,12 @@ func EvalProjectTriggers(ctx context.Context, e *event.EventLogEntry, processor
 		if b == nil {
 			return nil, errors.Errorf("build '%s' not found", e.ResourceId)
 		}
-		return triggerDownstreamProjectsForBuild(ctx, b, e, processor)
+		versions, err := triggerDownstreamProjectsForBuild(ctx, b, e, processor)
+		pipelineVersions, pipelineErr := triggerDownstreamPipelinesForBuild(ctx, b, e, processor)
+		catcher := grip.NewBasicCatcher()
+		catcher.Add(err)
+		catcher.Add(pipelineErr)
+		return append(versions, pipelineVersions...), catcher.Resolve()
 	default:
 		return nil, nil
 	}
@@ -32,6 +32,9 @@ func init() {
 	registry.registerEventHandler(event.ResourceTypeTask, event.TaskStarted, makeTaskTriggers)
 	registry.registerEventHandler(event.ResourceTypeTask, event.TaskFinished, makeTaskTriggers)
 	registry.registerEventHandler(event.ResourceTypeTask, event.TaskBlocked, makeTaskTriggers)
+	registry.registerEventHandler(event.ResourceTypeTask, event.TaskTestQuarantined, makeTestQuarantineTriggers)
+	registry.registerEventHandler(event.ResourceTypeTask, event.TaskTestReleased, makeTestQuarantineTriggers)
+	registry.registerEventHandler(event.ResourceTypeTask, event.TaskPerfRegression, makePerfRegressionTriggers)
 }
 
 const (
@@ -67,6 +70,34 @@ func makeTaskTriggers() eventHandler {
 	return t
 }
 
+// makeTestQuarantineTriggers handles the events for a task's test being
+// automatically quarantined or released. They're kept separate from the other
+// task triggers, which would otherwise notify about the task's status again.
+func makeTestQuarantineTriggers() eventHandler {
+	t := &taskTriggers{
+		oldTestResults: map[string]*testresult.TestResult{},
+	}
+	t.base.triggers = map[string]trigger{
+		event.TriggerTestQuarantined: t.testQuarantined,
+		event.TriggerTestReleased:    t.testReleased,
+	}
+
+	return t
+}
+
+// makePerfRegressionTriggers handles the events for a change point being found
+// in a task's performance results.
+func makePerfRegressionTriggers() eventHandler {
+	t := &taskTriggers{
+		oldTestResults: map[string]*testresult.TestResult{},
+	}
+	t.base.triggers = map[string]trigger{
+		event.TriggerPerfRegression: t.perfRegression,
+	}
+
+	return t
+}
+
 // newAlertRecord creates an instance of an alert record for the given alert type, populating it
 // with as much data from the triggerContext as possible
 func newAlertRecord(subID string, t *task.Task, alertType string) *alertrecord.AlertRecord {
@@ -485,6 +516,27 @@ func (t *taskTriggers) taskStarted(ctx context.Context, sub *event.Subscription)
 	return t.generate(ctx, sub, "", "")
 }
 
+func (t *taskTriggers) testQuarantined(ctx context.Context, sub *event.Subscription) (*notification.Notification, error) {
+	if t.event.EventType != event.TaskTestQuarantined || t.data.TestName == "" {
+		return nil, nil
+	}
+	return t.generate(ctx, sub, fmt.Sprintf("had a flaky test quarantined (flakiness score %.2f)", t.data.FlakinessScore), t.data.TestName)
+}
+
+func (t *taskTriggers) testReleased(ctx context.Context, sub *event.Subscription) (*notification.Notification, error) {
+	if t.event.EventType != event.TaskTestReleased || t.data.TestName == "" {
+		return nil, nil
+	}
+	return t.generate(ctx, sub, "had a test released from quarantine", t.data.TestName)
+}
+
+func (t *taskTriggers) perfRegression(ctx context.Context, sub *event.Subscription) (*notification.Notification, error) {
+	if t.event.EventType != event.TaskPerfRegression || t.data.TestName == "" {
+		return nil, nil
+	}
+	return t.generate(ctx, sub, fmt.Sprintf("had a performance regression of %+.1f%% in metric '%s'", t.data.PerfPercentChange, t.data.PerfMetric), t.data.TestName)
+}
+
 func (t *taskTriggers) taskFailedOrBlocked(ctx context.Context, sub *event.Subscription) (*notification.Notification, error) {
 	if t.task.IsPartOfDisplay(ctx) {
 		return nil, nil
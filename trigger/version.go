@@ -9,6 +9,7 @@ import (
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/culprit"
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/notification"
 	"github.com/evergreen-ci/evergreen/model/patch"
@@ -25,6 +26,7 @@ func init() {
 	registry.registerEventHandler(event.ResourceTypeVersion, event.VersionStateChange, makeVersionTriggers)
 	registry.registerEventHandler(event.ResourceTypeVersion, event.VersionGithubCheckFinished, makeVersionTriggers)
 	registry.registerEventHandler(event.ResourceTypeVersion, event.VersionChildrenCompletion, makeVersionTriggers)
+	registry.registerEventHandler(event.ResourceTypeVersion, event.VersionCulpritFound, makeVersionTriggers)
 }
 
 type versionTriggers struct {
@@ -50,6 +52,9 @@ func makeVersionTriggers() eventHandler {
 		event.TriggerRegression:             t.versionRegression,
 		event.TriggerExceedsDuration:        t.versionExceedsDuration,
 		event.TriggerRuntimeChangeByPercent: t.versionRuntimeChange,
+		event.TriggerVersionCostExceeds:     t.versionCostExceeds,
+		event.TriggerProjectBudgetThreshold: t.projectBudgetThreshold,
+		event.TriggerCulpritFound:           t.versionCulpritFound,
 	}
 	return t
 }
@@ -374,3 +379,84 @@ func (t *versionTriggers) versionRegression(ctx context.Context, sub *event.Subs
 	}
 	return nil, nil
 }
+
+func (t *versionTriggers) versionCostExceeds(ctx context.Context, sub *event.Subscription) (*notification.Notification, error) {
+	if !evergreen.IsFinishedVersionStatus(t.data.Status) || t.event.EventType != event.VersionStateChange {
+		return nil, nil
+	}
+	thresholdString, ok := sub.TriggerData[event.VersionCostThresholdKey]
+	if !ok {
+		return nil, errors.Errorf("subscription '%s' has no cost threshold", sub.ID)
+	}
+	threshold, err := strconv.ParseFloat(thresholdString, 64)
+	if err != nil {
+		return nil, errors.Errorf("subscription '%s' has an invalid cost threshold", sub.ID)
+	}
+
+	versionCost := t.version.Cost.AdjustedTotal()
+	if versionCost <= threshold {
+		return nil, nil
+	}
+	return t.generate(ctx, sub, fmt.Sprintf("cost $%.2f (over threshold of $%s)", versionCost, thresholdString))
+}
+
+func (t *versionTriggers) projectBudgetThreshold(ctx context.Context, sub *event.Subscription) (*notification.Notification, error) {
+	if !evergreen.IsFinishedVersionStatus(t.data.Status) || t.event.EventType != event.VersionStateChange {
+		return nil, nil
+	}
+	percentString, ok := sub.TriggerData[event.ProjectBudgetPercentKey]
+	if !ok {
+		percentString = "100"
+	}
+	percent, err := strconv.ParseFloat(percentString, 64)
+	if err != nil {
+		return nil, errors.Errorf("subscription '%s' has an invalid budget percentage", sub.ID)
+	}
+
+	projectRef, err := model.FindMergedProjectRef(ctx, t.version.Identifier, t.version.Id, false)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding project ref '%s'", t.version.Identifier)
+	}
+	if projectRef == nil || !projectRef.CostBudget.HasBudget() {
+		return nil, nil
+	}
+	usages, err := projectRef.GetCostBudgetUsage(ctx, t.version.Requester)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting cost budget usage for project '%s'", projectRef.Id)
+	}
+
+	versionCost := t.version.Cost.AdjustedTotal()
+	for _, usage := range usages {
+		if !reachedBudgetThreshold(usage, t.version.CreateTime, versionCost, percent) {
+			continue
+		}
+		claimed, err := model.ClaimCostBudgetNotification(ctx, sub.ID, projectRef.Id, usage, percent)
+		if err != nil {
+			return nil, errors.Wrapf(err, "claiming budget threshold notification for subscription '%s'", sub.ID)
+		}
+		if claimed {
+			return t.generate(ctx, sub, fmt.Sprintf("brought project spend to %.1f%% of its %s (over threshold of %s%%)", usage.PercentSpent(), usage.String(), percentString))
+		}
+	}
+	return nil, nil
+}
+
+func (t *versionTriggers) versionCulpritFound(ctx context.Context, sub *event.Subscription) (*notification.Notification, error) {
+	if t.event.EventType != event.VersionCulpritFound || t.data.SuspectedRevision == "" {
+		return nil, nil
+	}
+	return t.generate(ctx, sub, fmt.Sprintf("a suspected culprit commit %s for %d failed tasks", culprit.ShortRevision(t.data.SuspectedRevision), t.data.NumFailedTasks))
+}
+
+// reachedBudgetThreshold returns whether a version counts against a budget
+// whose spend has reached the given percentage of it. Versions created before
+// the budget's current period do not count against it. Since versions can
+// finish concurrently or out of order, this doesn't determine which version
+// crossed the threshold; each threshold is only notified about once per
+// period.
+func reachedBudgetThreshold(usage model.CostBudgetUsage, versionCreateTime time.Time, versionCost, percent float64) bool {
+	if versionCost <= 0 || versionCreateTime.Before(usage.Start) {
+		return false
+	}
+	return usage.Spent >= usage.Budget*percent/100
+}
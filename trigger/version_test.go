@@ -10,6 +10,7 @@ import (
 	mgobson "github.com/evergreen-ci/evergreen/db/mgo/bson"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/alertrecord"
+	"github.com/evergreen-ci/evergreen/model/cost"
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/evergreen/model/task"
@@ -349,6 +350,93 @@ func (s *VersionSuite) TestVersionRuntimeChange() {
 	s.NotNil(n)
 }
 
+func (s *VersionSuite) TestVersionCostExceeds() {
+	sub := event.NewSubscriptionByID(event.ResourceTypeVersion, event.TriggerVersionCostExceeds, s.event.ResourceId, s.subs[0].Subscriber)
+	sub.TriggerData = map[string]string{event.VersionCostThresholdKey: "10"}
+	s.t.version.Cost = cost.Cost{AdjustedEC2Cost: 12}
+
+	// unfinished version should not generate
+	n, err := s.t.versionCostExceeds(s.ctx, &sub)
+	s.NoError(err)
+	s.Nil(n)
+
+	// version that exceeds cost should generate
+	s.t.data.Status = evergreen.VersionSucceeded
+	n, err = s.t.versionCostExceeds(s.ctx, &sub)
+	s.NoError(err)
+	s.NotNil(n)
+
+	// version that does not exceed cost should not generate
+	s.t.version.Cost = cost.Cost{AdjustedEC2Cost: 8}
+	n, err = s.t.versionCostExceeds(s.ctx, &sub)
+	s.NoError(err)
+	s.Nil(n)
+}
+
+func (s *VersionSuite) TestProjectBudgetThreshold() {
+	s.NoError(db.ClearCollections(model.ProjectRefCollection, model.CostBudgetNotificationsCollection))
+	pRef := model.ProjectRef{
+		Id:         s.version.Identifier,
+		CostBudget: model.CostBudgetSettings{DailyBudget: 10},
+	}
+	s.NoError(pRef.Insert(s.ctx))
+	other := model.Version{Id: "other", Identifier: pRef.Id, Requester: evergreen.RepotrackerVersionRequester, CreateTime: time.Now(), Cost: cost.Cost{AdjustedEC2Cost: 5}}
+	s.NoError(other.Insert(s.ctx))
+
+	s.version.CreateTime = time.Now()
+	s.version.Cost = cost.Cost{AdjustedEC2Cost: 4}
+	_, err := db.Replace(s.ctx, model.VersionCollection, bson.M{"_id": s.version.Id}, &s.version)
+	s.NoError(err)
+
+	sub := event.NewSubscriptionByID(event.ResourceTypeVersion, event.TriggerProjectBudgetThreshold, s.event.ResourceId, s.subs[0].Subscriber)
+	sub.ID = "budget_sub"
+	sub.TriggerData = map[string]string{event.ProjectBudgetPercentKey: "80"}
+	s.t.data.Status = evergreen.VersionSucceeded
+
+	// version that finishes with spend at 90% of the budget should generate
+	n, err := s.t.projectBudgetThreshold(s.ctx, &sub)
+	s.NoError(err)
+	s.NotNil(n)
+
+	// the threshold was already notified about in this period, even though
+	// another version finishing out of order would also be past it
+	n, err = s.t.projectBudgetThreshold(s.ctx, &sub)
+	s.NoError(err)
+	s.Nil(n)
+
+	// a lower threshold than one already notified about
+	sub.TriggerData[event.ProjectBudgetPercentKey] = "40"
+	n, err = s.t.projectBudgetThreshold(s.ctx, &sub)
+	s.NoError(err)
+	s.Nil(n)
+
+	// spend has not reached the threshold
+	delete(sub.TriggerData, event.ProjectBudgetPercentKey)
+	n, err = s.t.projectBudgetThreshold(s.ctx, &sub)
+	s.NoError(err)
+	s.Nil(n)
+
+	// a different subscription is notified separately, even if the version
+	// that finishes last didn't bring the spend past the threshold
+	otherSub := event.NewSubscriptionByID(event.ResourceTypeVersion, event.TriggerProjectBudgetThreshold, s.event.ResourceId, s.subs[0].Subscriber)
+	otherSub.ID = "other_budget_sub"
+	otherSub.TriggerData = map[string]string{event.ProjectBudgetPercentKey: "40"}
+	n, err = s.t.projectBudgetThreshold(s.ctx, &otherSub)
+	s.NoError(err)
+	s.NotNil(n)
+}
+
+func TestReachedBudgetThreshold(t *testing.T) {
+	start := time.Now().Add(-time.Hour)
+	usage := model.CostBudgetUsage{Start: start, Budget: 100, Spent: 85}
+
+	assert.True(t, reachedBudgetThreshold(usage, time.Now(), 10, 80))
+	assert.True(t, reachedBudgetThreshold(usage, time.Now(), 1, 80), "spend was already past the threshold")
+	assert.False(t, reachedBudgetThreshold(usage, time.Now(), 10, 90), "spend has not reached the threshold")
+	assert.False(t, reachedBudgetThreshold(usage, start.Add(-time.Minute), 10, 80), "version was created before the period")
+	assert.False(t, reachedBudgetThreshold(usage, time.Now(), 0, 80), "version has no cost")
+}
+
 func (s *VersionSuite) TestMakeDataForRepotrackerVersion() {
 	sub := s.subs[0]
 	data, err := s.t.makeData(s.ctx, &sub, "")
@@ -0,0 +1,146 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/cachestore"
+	"github.com/evergreen-ci/pail"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const cacheStoreGCJobName = "cache-store-gc"
+
+func init() {
+	registry.AddJobType(cacheStoreGCJobName, func() amboy.Job { return makeCacheStoreGCJob() })
+}
+
+type cacheStoreGCJob struct {
+	job.Base `bson:"job_base" json:"job_base" yaml:"job_base"`
+
+	env evergreen.Environment
+	// makeBucket connects to a store's bucket. It's only overridden in
+	// tests.
+	makeBucket func(context.Context, evergreen.CacheStoreConfig) (pail.Bucket, error)
+}
+
+func makeCacheStoreGCJob() *cacheStoreGCJob {
+	j := &cacheStoreGCJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    cacheStoreGCJobName,
+				Version: 0,
+			},
+		},
+	}
+	return j
+}
+
+// NewCacheStoreGCJob creates a job that removes expired manifests and
+// unreferenced blobs from the content-addressed stores used by chunked caches.
+func NewCacheStoreGCJob(id string) amboy.Job {
+	j := makeCacheStoreGCJob()
+	j.SetID(fmt.Sprintf("%s.%s", cacheStoreGCJobName, id))
+	return j
+}
+
+func (j *cacheStoreGCJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	if j.env == nil {
+		j.env = evergreen.GetEnvironment()
+	}
+	if j.makeBucket == nil {
+		j.makeBucket = newCacheStoreBucket
+	}
+
+	bucketsConfig := j.env.Settings().Buckets
+	manifestTTL := time.Duration(bucketsConfig.CacheManifestTTLDays) * 24 * time.Hour
+
+	for _, store := range bucketsConfig.CacheStores {
+		prefix := store.Prefix
+		if prefix == "" {
+			prefix = cachestore.DefaultPrefix
+		}
+
+		bucket, err := j.makeBucket(ctx, store)
+		if err != nil {
+			j.AddError(errors.Wrapf(err, "connecting to cache store bucket '%s'", store.Bucket))
+			continue
+		}
+
+		res, err := cachestore.CollectGarbage(ctx, bucket, cachestore.GCOptions{
+			Prefix:      prefix,
+			ManifestTTL: manifestTTL,
+		})
+		if err != nil {
+			grip.Error(ctx, message.WrapError(err, message.Fields{
+				"message": "failed to garbage collect cache store",
+				"bucket":  store.Bucket,
+				"prefix":  prefix,
+				"job_id":  j.ID(),
+			}))
+			j.AddError(errors.Wrapf(err, "garbage collecting cache store '%s/%s'", store.Bucket, prefix))
+			continue
+		}
+
+		grip.Info(ctx, message.Fields{
+			"message":           "garbage collected cache store",
+			"bucket":            store.Bucket,
+			"prefix":            prefix,
+			"manifests_removed": res.ManifestsRemoved,
+			"pending_removed":   res.PendingRemoved,
+			"blobs_retained":    res.BlobsRetained,
+			"blobs_removed":     res.BlobsRemoved,
+			"bytes_removed":     res.BytesRemoved,
+			"job_id":            j.ID(),
+		})
+	}
+}
+
+func newCacheStoreBucket(ctx context.Context, store evergreen.CacheStoreConfig) (pail.Bucket, error) {
+	opts := pail.S3Options{
+		Name:       store.Bucket,
+		Region:     store.Region,
+		MaxRetries: utility.ToIntPtr(evergreen.DefaultS3MaxRetries),
+	}
+	if opts.Region == "" {
+		opts.Region = evergreen.DefaultS3Region
+	}
+	if store.RoleARN != "" {
+		opts.AssumeRoleARN = store.RoleARN
+		if store.ExternalID != "" {
+			externalID := store.ExternalID
+			opts.AssumeRoleOptions = []func(*stscreds.AssumeRoleOptions){
+				func(aro *stscreds.AssumeRoleOptions) {
+					aro.ExternalID = &externalID
+				},
+			}
+		}
+	}
+
+	bucket, err := pail.NewS3Bucket(ctx, opts)
+	return bucket, errors.Wrap(err, "creating S3 bucket")
+}
+
+// PopulateCacheStoreGCJob is a queue operation to populate the daily cache
+// store garbage collection job.
+func PopulateCacheStoreGCJob(env evergreen.Environment) amboy.QueueOperation {
+	return func(ctx context.Context, queue amboy.Queue) error {
+		if len(env.Settings().Buckets.CacheStores) == 0 {
+			return nil
+		}
+
+		ts := utility.RoundPartOfDay(0).Format(TSFormat)
+		return queue.Put(ctx, NewCacheStoreGCJob(ts))
+	}
+}
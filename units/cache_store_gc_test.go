@@ -0,0 +1,99 @@
+package units
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/mock"
+	"github.com/evergreen-ci/evergreen/model/cachestore"
+	"github.com/evergreen-ci/pail"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheStoreGCJob(t *testing.T) {
+	putBlob := func(t *testing.T, bucket pail.Bucket, prefix, contents string) string {
+		sum := sha256.Sum256([]byte(contents))
+		hash := hex.EncodeToString(sum[:])
+		require.NoError(t, bucket.Put(t.Context(), cachestore.BlobKey(prefix, hash), bytes.NewBufferString(contents)))
+		return hash
+	}
+	putManifest := func(t *testing.T, bucket pail.Bucket, key string, hash string) {
+		b, err := cachestore.MarshalManifest(&cachestore.Manifest{
+			Version:   cachestore.ManifestVersion,
+			CreatedAt: time.Now(),
+			Entries:   []cachestore.Entry{{Path: "file", Type: cachestore.EntryTypeFile, Size: 1}},
+			Chunks:    []cachestore.Chunk{{Hash: hash, Size: 1}},
+		})
+		require.NoError(t, err)
+		require.NoError(t, bucket.Put(t.Context(), key, bytes.NewReader(b)))
+	}
+	exists := func(t *testing.T, bucket pail.Bucket, key string) bool {
+		ok, err := bucket.Exists(t.Context(), key)
+		require.NoError(t, err)
+		return ok
+	}
+
+	t.Run("RemovesUnreferencedBlobsFromEachStore", func(t *testing.T) {
+		env := &mock.Environment{}
+		require.NoError(t, env.Configure(t.Context()))
+
+		buckets := map[string]pail.Bucket{}
+		for _, name := range []string{"bucket1", "bucket2"} {
+			bucket, err := pail.NewLocalBucket(pail.LocalOptions{Path: t.TempDir()})
+			require.NoError(t, err)
+			buckets[name] = bucket
+		}
+		env.EvergreenSettings.Buckets.CacheStores = []evergreen.CacheStoreConfig{
+			{Bucket: "bucket1"},
+			{Bucket: "bucket2", Prefix: "custom"},
+		}
+
+		referenced := putBlob(t, buckets["bucket1"], cachestore.DefaultPrefix, "a")
+		putManifest(t, buckets["bucket1"], cachestore.ManifestKey(cachestore.DefaultPrefix, "project", "key", "deps"), referenced)
+		orphaned1 := putBlob(t, buckets["bucket1"], cachestore.DefaultPrefix, "b")
+		orphaned2 := putBlob(t, buckets["bucket2"], "custom", "c")
+
+		j := makeCacheStoreGCJob()
+		j.env = env
+		j.makeBucket = func(_ context.Context, store evergreen.CacheStoreConfig) (pail.Bucket, error) {
+			return buckets[store.Bucket], nil
+		}
+		j.Run(t.Context())
+		require.NoError(t, j.Error())
+
+		assert.True(t, exists(t, buckets["bucket1"], cachestore.BlobKey(cachestore.DefaultPrefix, referenced)))
+		assert.False(t, exists(t, buckets["bucket1"], cachestore.BlobKey(cachestore.DefaultPrefix, orphaned1)))
+		assert.False(t, exists(t, buckets["bucket2"], cachestore.BlobKey("custom", orphaned2)))
+	})
+	t.Run("ContinuesPastStoresThatFail", func(t *testing.T) {
+		env := &mock.Environment{}
+		require.NoError(t, env.Configure(t.Context()))
+
+		bucket, err := pail.NewLocalBucket(pail.LocalOptions{Path: t.TempDir()})
+		require.NoError(t, err)
+		env.EvergreenSettings.Buckets.CacheStores = []evergreen.CacheStoreConfig{
+			{Bucket: "unreachable"},
+			{Bucket: "reachable"},
+		}
+		orphaned := putBlob(t, bucket, cachestore.DefaultPrefix, "a")
+
+		j := makeCacheStoreGCJob()
+		j.env = env
+		j.makeBucket = func(_ context.Context, store evergreen.CacheStoreConfig) (pail.Bucket, error) {
+			if store.Bucket == "unreachable" {
+				return nil, errors.New("connection refused")
+			}
+			return bucket, nil
+		}
+		j.Run(t.Context())
+		assert.Error(t, j.Error())
+		assert.False(t, exists(t, bucket, cachestore.BlobKey(cachestore.DefaultPrefix, orphaned)))
+	})
+}
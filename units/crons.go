@@ -13,6 +13,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/evergreen-ci/evergreen/model/perf"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/utility"
@@ -116,6 +117,14 @@ func sendNotificationJobs(ctx context.Context, _ evergreen.Environment, ts time.
 	return notificationJobs(ctx, unprocessedNotifications, flags, ts)
 }
 
+func notificationDigestFlushJobs(ctx context.Context, _ evergreen.Environment, ts time.Time) ([]amboy.Job, error) {
+	return []amboy.Job{NewNotificationDigestFlushJob(ts.Format(TSFormat))}, nil
+}
+
+func culpritFinderJobs(ctx context.Context, _ evergreen.Environment, ts time.Time) ([]amboy.Job, error) {
+	return []amboy.Job{NewCulpritFinderJob(ts.Format(TSFormat))}, nil
+}
+
 func eventNotifierJobs(ctx context.Context, env evergreen.Environment, ts time.Time) ([]amboy.Job, error) {
 	flags, err := evergreen.GetServiceFlags(ctx)
 	if err != nil {
@@ -1125,6 +1134,173 @@ func populateRetryFailedLogMoveJobs(env evergreen.Environment, runInOldTaskColle
 	}
 }
 
+// logCompactionDelay is how long after a task finishes that its logs are
+// compacted. This leaves time for the logs of failed tasks to be moved to the
+// failed bucket first.
+const logCompactionDelay = 24 * time.Hour
+
+// logCompactionLookback bounds how long ago tasks whose logs are compacted
+// could have finished, so that the tasks of the entire history are not
+// scanned.
+const logCompactionLookback = 7 * 24 * time.Hour
+
+// logRetentionMinJobsPerRun is the number of jobs enqueued for each retention
+// stage by a single run when there is no backlog. This avoids S3 rate limiting
+// when many tasks become eligible at once.
+const logRetentionMinJobsPerRun = 1000
+
+// logRetentionBacklogRuns is the number of runs over which the backlog of a
+// retention stage is drained. When a stage has more candidate tasks than can
+// be processed in this many runs at the minimum rate, each run enqueues a
+// proportionally larger share of the backlog so that it cannot grow without
+// bound and leave logs past their retention.
+const logRetentionBacklogRuns = 6
+
+// PopulateLogRetentionJobs enqueues the jobs that move the logs of finished
+// tasks to the next retention tier: compaction for every task, then reduction
+// and deletion for the tasks of projects with log retention settings. The
+// tasks that finished the longest ago are enqueued first.
+func PopulateLogRetentionJobs(env evergreen.Environment) amboy.QueueOperation {
+	return func(ctx context.Context, queue amboy.Queue) error {
+		flags, err := evergreen.GetServiceFlags(ctx)
+		if err != nil {
+			return errors.Wrap(err, "getting service flags")
+		}
+		if !flags.LogRetentionEnabled {
+			return nil
+		}
+
+		now := time.Now()
+		ts := utility.RoundPartOfHour(0).Format(TSFormat)
+		catcher := grip.NewBasicCatcher()
+		findCandidates := func(project string, finishedAfter, finishedBefore time.Time, stage task.LogRetentionStage) ([]task.Task, error) {
+			backlog, err := task.CountLogRetentionCandidates(ctx, project, finishedAfter, finishedBefore, stage)
+			if err != nil {
+				return nil, errors.Wrap(err, "counting candidate tasks")
+			}
+			if backlog == 0 {
+				return nil, nil
+			}
+			limit := max(logRetentionMinJobsPerRun, (backlog+logRetentionBacklogRuns-1)/logRetentionBacklogRuns)
+			if backlog > logRetentionMinJobsPerRun {
+				grip.Info(ctx, message.Fields{
+					"message": "log retention stage has a backlog of candidate tasks",
+					"stage":   stage,
+					"project": project,
+					"backlog": backlog,
+					"limit":   limit,
+				})
+			}
+			return task.FindLogRetentionCandidates(ctx, project, finishedAfter, finishedBefore, stage, limit)
+		}
+		enqueue := func(tasks []task.Task, stage task.LogRetentionStage) {
+			for _, t := range tasks {
+				catcher.Wrapf(amboy.EnqueueUniqueJob(ctx, queue, NewLogRetentionJob(t.Id, stage, ts)), "enqueueing log retention job for task '%s'", t.Id)
+			}
+		}
+
+		projectRefs, err := model.FindAllMergedProjectRefs(ctx)
+		if err != nil {
+			return errors.Wrap(err, "finding project refs")
+		}
+		for _, pRef := range projectRefs {
+			settings := pRef.LogRetention
+			if settings.IsZero() {
+				continue
+			}
+
+			var deleteCutoff time.Time
+			if settings.DeleteAfterDays > 0 {
+				deleteCutoff = now.AddDate(0, 0, -settings.DeleteAfterDays)
+				tasks, err := findCandidates(pRef.Id, time.Time{}, deleteCutoff, task.LogRetentionStageDeleted)
+				if err != nil {
+					catcher.Wrapf(err, "finding tasks in project '%s' whose logs should be deleted", pRef.Id)
+					continue
+				}
+				enqueue(tasks, task.LogRetentionStageDeleted)
+			}
+			if settings.ReduceAfterDays > 0 {
+				reduceCutoff := now.AddDate(0, 0, -settings.ReduceAfterDays)
+				tasks, err := findCandidates(pRef.Id, deleteCutoff, reduceCutoff, task.LogRetentionStageReduced)
+				if err != nil {
+					catcher.Wrapf(err, "finding tasks in project '%s' whose logs should be reduced", pRef.Id)
+					continue
+				}
+				enqueue(tasks, task.LogRetentionStageReduced)
+			}
+		}
+
+		compactAfter, compactBefore := now.Add(-logCompactionLookback), now.Add(-logCompactionDelay)
+		tasks, err := findCandidates("", compactAfter, compactBefore, task.LogRetentionStageCompacted)
+		if err != nil {
+			catcher.Wrap(err, "finding tasks whose logs should be compacted")
+		} else {
+			enqueue(filterLogsNotPendingMove(env.Settings(), tasks), task.LogRetentionStageCompacted)
+		}
+
+		return catcher.Resolve()
+	}
+}
+
+// filterLogsNotPendingMove filters out the failed tasks whose logs have not
+// been moved to the failed bucket yet.
+func filterLogsNotPendingMove(settings *evergreen.Settings, tasks []task.Task) []task.Task {
+	failedBucket := settings.Buckets.LogBucketFailedTasks.Name
+	if failedBucket == "" {
+		return tasks
+	}
+
+	var filtered []task.Task
+	for _, t := range tasks {
+		if t.Status == evergreen.TaskFailed && t.LogBucketName() != failedBucket && !utility.StringSliceContains(settings.Buckets.LongRetentionProjects, t.Project) {
+			continue
+		}
+		filtered = append(filtered, t)
+	}
+
+	return filtered
+}
+
+// PopulateFlakyTestScoringJobs enqueues a job to score the flakiness of the
+// tests of each project that quarantines flaky tests automatically.
+func PopulateFlakyTestScoringJobs() amboy.QueueOperation {
+	return func(ctx context.Context, queue amboy.Queue) error {
+		projectRefs, err := model.FindAllMergedEnabledTrackedProjectRefs(ctx)
+		if err != nil {
+			return errors.Wrap(err, "finding project refs")
+		}
+
+		ts := utility.RoundPartOfHour(0).Format(TSFormat)
+		catcher := grip.NewBasicCatcher()
+		for _, pRef := range projectRefs {
+			if !pRef.FlakyTestQuarantine.Enabled || !pRef.IsTestSelectionAllowed() {
+				continue
+			}
+			catcher.Wrapf(amboy.EnqueueUniqueJob(ctx, queue, NewFlakyTestScoringJob(pRef.Id, ts)), "enqueueing flaky test scoring job for project '%s'", pRef.Id)
+		}
+		return catcher.Resolve()
+	}
+}
+
+// PopulatePerfChangePointDetectionJobs enqueues a job to detect change points
+// in the performance results of each project that recently reported mainline
+// results.
+func PopulatePerfChangePointDetectionJobs() amboy.QueueOperation {
+	return func(ctx context.Context, queue amboy.Queue) error {
+		projectIDs, err := perf.FindProjectsWithNewPoints(ctx, time.Now().Add(-perfChangePointLookback))
+		if err != nil {
+			return errors.Wrap(err, "finding projects with new perf results")
+		}
+
+		ts := utility.RoundPartOfHour(0).Format(TSFormat)
+		catcher := grip.NewBasicCatcher()
+		for _, projectID := range projectIDs {
+			catcher.Wrapf(amboy.EnqueueUniqueJob(ctx, queue, NewPerfChangePointDetectionJob(projectID, ts)), "enqueueing perf change point detection job for project '%s'", projectID)
+		}
+		return catcher.Resolve()
+	}
+}
+
 func PopulateLocalQueueJobs(env evergreen.Environment) amboy.QueueOperation {
 	return func(ctx context.Context, queue amboy.Queue) error {
 		catcher := grip.NewBasicCatcher()
@@ -199,6 +199,28 @@ func (s *cronsEventSuite) TestNotificationIsEnabled() {
 	}
 }
 
+func (s *cronsEventSuite) TestCulpritFinderStartJobForEvent() {
+	failed := &event.EventLogEntry{
+		ID:           "event",
+		ResourceId:   "version",
+		ResourceType: event.ResourceTypeVersion,
+		EventType:    event.VersionStateChange,
+		Data:         &event.VersionEventData{Status: evergreen.VersionFailed},
+	}
+	j := culpritFinderStartJobForEvent(failed)
+	s.Require().NotNil(j)
+	s.Equal("culprit-finder-start.version.event", j.ID())
+	s.Equal([]string{"culprit-finder-start.version"}, j.Scopes())
+
+	succeeded := *failed
+	succeeded.Data = &event.VersionEventData{Status: evergreen.VersionSucceeded}
+	s.Nil(culpritFinderStartJobForEvent(&succeeded))
+
+	otherEvent := *failed
+	otherEvent.EventType = event.VersionGithubCheckFinished
+	s.Nil(culpritFinderStartJobForEvent(&otherEvent))
+}
+
 func (s *cronsEventSuite) TestEndToEnd() {
 	defer evergreen.SetEnvironment(evergreen.GetEnvironment())
 
@@ -48,6 +48,9 @@ func (j *cronsRemoteHourJob) Run(ctx context.Context) {
 	ops := []amboy.QueueOperation{
 		PopulateRetryFailedLogMoveJobsForOldTasks(j.env),
 		PopulateRetryFailedLogMoveJobs(j.env),
+		PopulateLogRetentionJobs(j.env),
+		PopulateFlakyTestScoringJobs(),
+		PopulatePerfChangePointDetectionJobs(),
 		PopulateCacheHistoricalTaskDataJob(2),
 		PopulateTaskHostExpirationExtendJob(),
 		PopulateSpawnhostExpirationCheckJob(),
@@ -58,6 +61,7 @@ func (j *cronsRemoteHourJob) Run(ctx context.Context) {
 		PopulateDuplicateTaskCheckJobs(),
 		PopulateUnexpirableSpawnHostStatsJob(),
 		PopulateDistroAutoTuneJobs(),
+		PopulateCacheStoreGCJob(j.env),
 	}
 
 	queue := j.env.RemoteQueue()
@@ -0,0 +1,127 @@
+package units
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/culprit"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	culpritFinderJobName      = "culprit-finder"
+	culpritFinderStartJobName = "culprit-finder-start"
+)
+
+func init() {
+	registry.AddJobType(culpritFinderJobName, func() amboy.Job { return makeCulpritFinderJob() })
+	registry.AddJobType(culpritFinderStartJobName, func() amboy.Job { return makeCulpritFinderStartJob() })
+}
+
+type culpritFinderJob struct {
+	job.Base `bson:"job_base" json:"job_base" yaml:"job_base"`
+	env      evergreen.Environment
+}
+
+func makeCulpritFinderJob() *culpritFinderJob {
+	j := &culpritFinderJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    culpritFinderJobName,
+				Version: 0,
+			},
+		},
+	}
+	return j
+}
+
+// NewCulpritFinderJob returns a job that advances every running
+// culprit-finding bisection whose current tasks have finished.
+func NewCulpritFinderJob(ts string) amboy.Job {
+	j := makeCulpritFinderJob()
+	j.SetID(fmt.Sprintf("%s.%s", culpritFinderJobName, ts))
+	j.SetScopes([]string{culpritFinderJobName})
+	j.SetEnqueueAllScopes(true)
+	return j
+}
+
+func (j *culpritFinderJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+	if j.env == nil {
+		j.env = evergreen.GetEnvironment()
+	}
+
+	bisections, err := culprit.FindRunning(ctx)
+	if err != nil {
+		j.AddError(errors.Wrap(err, "finding running bisections"))
+		return
+	}
+
+	for i := range bisections {
+		if ctx.Err() != nil {
+			j.AddError(ctx.Err())
+			return
+		}
+		j.AddError(errors.Wrapf(model.AdvanceBisection(ctx, &bisections[i]), "advancing bisection '%s'", bisections[i].ID))
+	}
+
+	grip.InfoWhen(ctx, len(bisections) > 0, message.Fields{
+		"job_id":         j.ID(),
+		"job_type":       j.Type().Name,
+		"message":        "advanced culprit-finding bisections",
+		"num_bisections": len(bisections),
+	})
+}
+
+type culpritFinderStartJob struct {
+	job.Base  `bson:"job_base" json:"job_base" yaml:"job_base"`
+	VersionID string `bson:"version_id" json:"version_id" yaml:"version_id"`
+}
+
+func makeCulpritFinderStartJob() *culpritFinderStartJob {
+	j := &culpritFinderStartJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    culpritFinderStartJobName,
+				Version: 0,
+			},
+		},
+	}
+	return j
+}
+
+// NewCulpritFinderStartJob returns a job that starts culprit-finding
+// bisections for the failures in a mainline version that just failed. The ID
+// is unique to the event for the version failing, since a restarted version
+// can fail again.
+func NewCulpritFinderStartJob(versionID, eventID string) amboy.Job {
+	j := makeCulpritFinderStartJob()
+	j.VersionID = versionID
+	j.SetID(fmt.Sprintf("%s.%s.%s", culpritFinderStartJobName, versionID, eventID))
+	j.SetScopes([]string{fmt.Sprintf("%s.%s", culpritFinderStartJobName, versionID)})
+	j.SetEnqueueAllScopes(true)
+	return j
+}
+
+func (j *culpritFinderStartJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	v, err := model.VersionFindOneId(ctx, j.VersionID)
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "finding version '%s'", j.VersionID))
+		return
+	}
+	if v == nil {
+		j.AddError(errors.Errorf("version '%s' not found", j.VersionID))
+		return
+	}
+
+	j.AddError(errors.Wrapf(model.StartCulpritFinding(ctx, v), "starting culprit finding for version '%s'", j.VersionID))
+}
@@ -124,6 +124,10 @@ func (j *eventNotifierJob) processEvent(ctx context.Context, e *event.EventLogEn
 	catcher.Add(errors.Wrap(err, "getting notification jobs"))
 	catcher.Add(errors.Wrap(j.q.PutMany(ctx, jobs), "enqueueing notification jobs"))
 
+	if culpritJob := culpritFinderStartJobForEvent(e); culpritJob != nil {
+		catcher.Wrap(amboy.EnqueueUniqueJob(ctx, j.q, culpritJob), "enqueueing culprit finder start job")
+	}
+
 	endTime := time.Now()
 	totalDuration := endTime.Sub(startTime)
 
@@ -224,6 +228,19 @@ func (j *eventNotifierJob) processEventTriggers(ctx context.Context, e *event.Ev
 	return n, err
 }
 
+// culpritFinderStartJobForEvent returns the job to start culprit finding for
+// a version if the event is for it failing, and nil otherwise.
+func culpritFinderStartJobForEvent(e *event.EventLogEntry) amboy.Job {
+	if e.ResourceType != event.ResourceTypeVersion || e.EventType != event.VersionStateChange {
+		return nil
+	}
+	data, ok := e.Data.(*event.VersionEventData)
+	if !ok || data.Status != evergreen.VersionFailed {
+		return nil
+	}
+	return NewCulpritFinderStartJob(e.ResourceId, e.ID)
+}
+
 func notificationJobs(ctx context.Context, notifications []notification.Notification, flags *evergreen.ServiceFlags, ts time.Time) ([]amboy.Job, error) {
 	catcher := grip.NewBasicCatcher()
 	var jobs []amboy.Job
@@ -245,7 +262,7 @@ func notificationIsEnabled(ctx context.Context, flags *evergreen.ServiceFlags, n
 	case event.JIRAIssueSubscriberType, event.JIRACommentSubscriberType:
 		return !flags.JIRANotificationsDisabled
 
-	case event.EvergreenWebhookSubscriberType:
+	case event.EvergreenWebhookSubscriberType, event.TeamsSubscriberType, event.MattermostSubscriberType:
 		return !flags.WebhookNotificationsDisabled
 
 	case event.EmailSubscriberType:
@@ -3,11 +3,13 @@ package units
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/githubapp"
 	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/utility"
 	"github.com/mongodb/amboy"
 	"github.com/mongodb/amboy/job"
@@ -22,6 +24,10 @@ import (
 
 const (
 	eventSendJobName = "event-send"
+
+	// chatWebhookRateLimitWindow is the period over which the number of
+	// notifications sent to a chat webhook is limited.
+	chatWebhookRateLimitWindow = time.Minute
 )
 
 func init() {
@@ -101,6 +107,28 @@ func (j *eventSendJob) Run(ctx context.Context) {
 		j.AddError(errors.Errorf("notification '%s' has already been processed", n.ID))
 		return
 	}
+	if n.NextAttemptAt.After(time.Now()) {
+		// Leave the failed delivery to be retried by a later job once its
+		// backoff has elapsed.
+		return
+	}
+
+	rateLimited, err := j.isRateLimited(ctx, n)
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "checking rate limit for notification '%s'", n.ID))
+		return
+	}
+	if rateLimited {
+		// Leave the notification unsent so that it's sent by a later job once
+		// the target is no longer rate limited.
+		grip.Info(ctx, message.Fields{
+			"job_id":            j.ID(),
+			"notification_id":   n.ID,
+			"notification_type": n.Subscriber.Type,
+			"message":           "notification target is rate limited, deferring send",
+		})
+		return
+	}
 
 	err = j.send(ctx, n)
 	grip.Error(ctx, message.WrapError(err, message.Fields{
@@ -110,6 +138,15 @@ func (j *eventSendJob) Run(ctx context.Context) {
 		"message":           "send failed",
 	}))
 	j.AddError(err)
+	if deliveryErr, ok := errors.Cause(err).(*webhookDeliveryError); ok {
+		j.AddError(errors.Wrapf(n.MarkDeliveryFailed(ctx, deliveryErr), "recording failed delivery for notification '%s'", n.ID))
+		return
+	}
+	if err == nil && n.DeliveryStatus != "" {
+		// Clear the failure left by the earlier attempts to deliver it.
+		j.AddError(errors.Wrapf(n.MarkDelivered(ctx), "marking notification '%s' as delivered", n.ID))
+		return
+	}
 	j.AddError(errors.Wrapf(n.MarkSent(ctx), "marking notification '%s' as sent", n.ID))
 	j.AddError(errors.Wrapf(n.MarkError(ctx, err), "setting error for notification '%s'", n.ID))
 }
@@ -147,10 +184,79 @@ func (j *eventSendJob) send(ctx context.Context, n *notification.Notification) e
 			return errors.Wrap(err, "getting global notification sender")
 		}
 	}
+	if webhook, ok := c.Raw().(*util.EvergreenWebhook); ok {
+		return j.sendWebhook(ctx, n, sender, c, webhook)
+	}
+	sender.Send(ctx, c)
+	return nil
+}
+
+// webhookDeliveryError is returned when a webhook notification could not be
+// delivered to its URL, in which case the delivery is retried.
+type webhookDeliveryError struct {
+	cause error
+}
+
+func (e *webhookDeliveryError) Error() string {
+	return e.cause.Error()
+}
+
+// sendWebhook sends the webhook notification and logs each attempt to deliver
+// it.
+func (j *eventSendJob) sendWebhook(ctx context.Context, n *notification.Notification, sender send.Sender, c message.Composer, webhook *util.EvergreenWebhook) error {
+	var lastAttempt *util.WebhookAttempt
+	webhook.OnAttempt = func(attempt util.WebhookAttempt) {
+		lastAttempt = &attempt
+		delivery := notification.WebhookDelivery{
+			NotificationID: n.ID,
+			SubscriptionID: n.SubscriptionID,
+			URL:            webhook.URL,
+			Attempt:        n.DeliveryAttempts + 1,
+			StatusCode:     attempt.StatusCode,
+			LatencyMS:      attempt.Latency.Milliseconds(),
+			Time:           attempt.Time,
+		}
+		if attempt.Err != nil {
+			delivery.Error = attempt.Err.Error()
+		}
+		grip.Error(ctx, message.WrapError(delivery.Insert(ctx), message.Fields{
+			"job_id":          j.ID(),
+			"notification_id": n.ID,
+			"message":         "could not log webhook delivery",
+		}))
+	}
+
 	sender.Send(ctx, c)
+
+	// A sender that doesn't report its attempts leaves nothing to retry.
+	if lastAttempt != nil && lastAttempt.Err != nil {
+		return &webhookDeliveryError{cause: lastAttempt.Err}
+	}
 	return nil
 }
 
+// isRateLimited returns whether the notification's target has already received
+// as many notifications as it allows within the rate limit window. Only chat
+// webhook targets are rate limited.
+func (j *eventSendJob) isRateLimited(ctx context.Context, n *notification.Notification) (bool, error) {
+	var url string
+	var limit int
+	switch target := n.Subscriber.Target.(type) {
+	case *event.TeamsSubscriber:
+		url, limit = target.URL, target.GetRateLimitPerMinute()
+	case *event.MattermostSubscriber:
+		url, limit = target.URL, target.GetRateLimitPerMinute()
+	default:
+		return false, nil
+	}
+
+	sent, err := notification.CountSentToURLSince(ctx, n.Subscriber.Type, url, time.Now().Add(-chatWebhookRateLimitWindow))
+	if err != nil {
+		return false, err
+	}
+	return sent >= limit, nil
+}
+
 func (j *eventSendJob) checkDegradedMode(ctx context.Context, n *notification.Notification) error {
 	switch n.Subscriber.Type {
 	case event.GithubPullRequestSubscriberType, event.GithubCheckSubscriberType, event.GithubMergeSubscriberType:
@@ -165,7 +271,7 @@ func (j *eventSendJob) checkDegradedMode(ctx context.Context, n *notification.No
 	case event.JIRACommentSubscriberType:
 		return checkFlag(ctx, j.flags.JIRANotificationsDisabled)
 
-	case event.EvergreenWebhookSubscriberType:
+	case event.EvergreenWebhookSubscriberType, event.TeamsSubscriberType, event.MattermostSubscriberType:
 		return checkFlag(ctx, j.flags.WebhookNotificationsDisabled)
 
 	case event.EmailSubscriberType:
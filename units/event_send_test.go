@@ -2,6 +2,7 @@ package units
 
 import (
 	"context"
+	"net/http"
 	"regexp"
 	"testing"
 	"time"
@@ -14,7 +15,10 @@ import (
 	"github.com/evergreen-ci/evergreen/testutil"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/grip/message"
+	"github.com/mongodb/grip/send"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 type eventNotificationSuite struct {
@@ -200,6 +204,42 @@ func (s *eventNotificationSuite) TestEvergreenWebhook() {
 	})
 }
 
+type failingWebhookSender struct {
+	*send.Base
+}
+
+func (f *failingWebhookSender) Send(_ context.Context, m message.Composer) {
+	webhook := m.Raw().(*util.EvergreenWebhook)
+	webhook.OnAttempt(util.WebhookAttempt{
+		StatusCode: http.StatusServiceUnavailable,
+		Latency:    time.Second,
+		Err:        errors.New("webhook response was 503 (Service Unavailable)"),
+		Time:       time.Now(),
+	})
+}
+
+func (f *failingWebhookSender) Flush(context.Context) error { return nil }
+
+func (s *eventNotificationSuite) TestEvergreenWebhookDeliveryFailure() {
+	s.NoError(db.ClearCollections(notification.WebhookDeliveriesCollection))
+	job := NewEventSendJob(s.webhook.ID, "").(*eventSendJob)
+	job.env = s.env
+	s.webhook.SubscriptionID = "sub"
+
+	payload := &util.EvergreenWebhook{NotificationID: s.webhook.ID, URL: "https://example.com", Body: []byte("o hai")}
+	err := job.sendWebhook(s.ctx, s.webhook, &failingWebhookSender{Base: send.NewBase("test")}, util.NewWebhookMessage(*payload), payload)
+	s.Require().Error(err)
+	_, ok := err.(*webhookDeliveryError)
+	s.True(ok)
+
+	deliveries, err := notification.FindWebhookDeliveriesForSubscription(s.ctx, s.webhook.SubscriptionID, 10)
+	s.Require().NoError(err)
+	s.Require().Len(deliveries, 1)
+	s.Equal(http.StatusServiceUnavailable, deliveries[0].StatusCode)
+	s.EqualValues(1000, deliveries[0].LatencyMS)
+	s.Equal(1, deliveries[0].Attempt)
+}
+
 func (s *eventNotificationSuite) TestSlack() {
 	job := NewEventSendJob(s.slack.ID, "").(*eventSendJob)
 	job.env = s.env
@@ -218,6 +258,74 @@ func (s *eventNotificationSuite) TestSlack() {
 	})
 }
 
+func (s *eventNotificationSuite) TestTeams() {
+	n := notification.Notification{
+		ID:         "teams",
+		Subscriber: event.NewTeamsSubscriber(event.TeamsSubscriber{URL: "https://example.com/webhook"}),
+		Payload: notification.NewTeamsPayload([]notification.AdaptiveCardElement{
+			{Type: notification.AdaptiveCardTextBlock, Text: "Hi"},
+		}, nil),
+	}
+	s.Require().NoError(notification.InsertMany(s.ctx, n))
+
+	job := NewEventSendJob(n.ID, "").(*eventSendJob)
+	job.env = s.env
+	job.Run(s.ctx)
+
+	s.NoError(job.Error())
+	s.NotZero(s.notificationHasError(s.ctx, n.ID, ""))
+
+	msg, recv := s.env.InternalSender.GetMessageSafe()
+	s.True(recv)
+	s.NotPanics(func() {
+		webhook := msg.Message.Raw().(*util.ChatWebhook)
+		s.Equal("https://example.com/webhook", webhook.URL)
+		s.Contains(string(webhook.Body), `"text":"Hi"`)
+	})
+}
+
+func (s *eventNotificationSuite) TestChatWebhookRateLimit() {
+	subscriber := event.NewMattermostSubscriber(event.MattermostSubscriber{
+		URL:                "https://example.com/hooks/abc",
+		RateLimitPerMinute: 1,
+	})
+	sent := notification.Notification{
+		ID:         "mattermost-sent",
+		Subscriber: subscriber,
+		Payload:    &notification.MattermostPayload{Text: "first"},
+		SentAt:     time.Now(),
+	}
+	pending := notification.Notification{
+		ID:         "mattermost-pending",
+		Subscriber: subscriber,
+		Payload:    &notification.MattermostPayload{Text: "second"},
+	}
+	s.Require().NoError(notification.InsertMany(s.ctx, sent, pending))
+
+	job := NewEventSendJob(pending.ID, "").(*eventSendJob)
+	job.env = s.env
+	job.Run(s.ctx)
+	s.NoError(job.Error())
+
+	// The notification should be left unsent until the target is no longer
+	// rate limited.
+	s.Zero(s.notificationHasError(s.ctx, pending.ID, ""))
+	_, recv := s.env.InternalSender.GetMessageSafe()
+	s.False(recv)
+
+	s.Require().NoError(db.UpdateId(s.ctx, notification.Collection, sent.ID, bson.M{
+		"$set": bson.M{"sent_at": time.Now().Add(-2 * chatWebhookRateLimitWindow)},
+	}))
+	job = NewEventSendJob(pending.ID, "1").(*eventSendJob)
+	job.env = s.env
+	job.Run(s.ctx)
+	s.NoError(job.Error())
+
+	s.NotZero(s.notificationHasError(s.ctx, pending.ID, ""))
+	_, recv = s.env.InternalSender.GetMessageSafe()
+	s.True(recv)
+}
+
 func (s *eventNotificationSuite) TestJIRAComment() {
 	job := NewEventSendJob(s.jiraComment.ID, "").(*eventSendJob)
 	job.env = s.env
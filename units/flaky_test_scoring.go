@@ -0,0 +1,303 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/flakytest"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	flakyTestScoringJobName = "flaky-test-scoring"
+	flakyTestScoringTimeout = 30 * time.Minute
+	// flakyTestScoringLookback is how far back the test history used to
+	// score tests goes.
+	flakyTestScoringLookback = 14 * 24 * time.Hour
+	// flakyTestScoringMaxTasks caps the number of task runs whose test
+	// results are read each time a project is scored.
+	flakyTestScoringMaxTasks = 2000
+	// flakyTestScoringConcurrency is the number of task runs whose test
+	// results are read at once.
+	flakyTestScoringConcurrency = 8
+)
+
+func init() {
+	registry.AddJobType(flakyTestScoringJobName, func() amboy.Job {
+		return makeFlakyTestScoringJob()
+	})
+}
+
+type flakyTestScoringJob struct {
+	job.Base  `bson:"metadata" json:"metadata" yaml:"metadata"`
+	ProjectID string `bson:"project_id" json:"project_id" yaml:"project_id"`
+
+	env evergreen.Environment
+}
+
+func makeFlakyTestScoringJob() *flakyTestScoringJob {
+	return &flakyTestScoringJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    flakyTestScoringJobName,
+				Version: 0,
+			},
+		},
+	}
+}
+
+// NewFlakyTestScoringJob creates a job that scores the flakiness of a
+// project's tests from their recent mainline history, then quarantines the
+// tests that are too flaky and releases the quarantined tests that have
+// passed enough times in a row.
+func NewFlakyTestScoringJob(projectID, ts string) amboy.Job {
+	j := makeFlakyTestScoringJob()
+	j.ProjectID = projectID
+	j.SetID(fmt.Sprintf("%s.%s.%s", flakyTestScoringJobName, projectID, ts))
+	j.SetScopes([]string{fmt.Sprintf("%s.%s", flakyTestScoringJobName, projectID)})
+	j.SetEnqueueAllScopes(true)
+	j.UpdateTimeInfo(amboy.JobTimeInfo{MaxTime: flakyTestScoringTimeout})
+	return j
+}
+
+// flakyTestKey identifies a test across runs of the same task.
+type flakyTestKey struct {
+	buildVariant string
+	taskName     string
+	testName     string
+}
+
+func (j *flakyTestScoringJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+	if j.env == nil {
+		j.env = evergreen.GetEnvironment()
+	}
+
+	pRef, err := model.FindMergedProjectRef(ctx, j.ProjectID, "", false)
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "finding project ref '%s'", j.ProjectID))
+		return
+	}
+	if pRef == nil {
+		j.AddError(errors.Errorf("project ref '%s' not found", j.ProjectID))
+		return
+	}
+	settings := pRef.FlakyTestQuarantine
+	if !settings.Enabled {
+		return
+	}
+	if !pRef.IsTestSelectionAllowed() {
+		// Quarantined tests are only skipped by test selection, so they'd
+		// keep running and failing tasks in this project.
+		grip.Info(ctx, message.Fields{
+			"message": "not scoring flaky tests because test selection is not allowed in the project",
+			"project": j.ProjectID,
+			"job_id":  j.ID(),
+		})
+		return
+	}
+
+	now := time.Now()
+	runs, err := j.findTestRuns(ctx, now.Add(-flakyTestScoringLookback))
+	if err != nil {
+		j.AddError(errors.Wrap(err, "finding test runs"))
+		return
+	}
+
+	existing, err := flakytest.FindByProject(ctx, j.ProjectID, flakytest.FindOptions{})
+	if err != nil {
+		j.AddError(errors.Wrap(err, "finding existing test flakiness"))
+		return
+	}
+	byID := make(map[string]flakytest.TestFlakiness, len(existing))
+	for _, f := range existing {
+		byID[f.ID] = f
+	}
+
+	var numQuarantined, numReleased int
+	for key, testRuns := range runs {
+		id := flakytest.MakeID(j.ProjectID, key.buildVariant, key.taskName, key.testName)
+		f, ok := byID[id]
+		if !ok {
+			f = flakytest.TestFlakiness{
+				ID:           id,
+				ProjectID:    j.ProjectID,
+				BuildVariant: key.buildVariant,
+				TaskName:     key.taskName,
+				TestName:     key.testName,
+			}
+		}
+		quarantined, released := updateTestFlakiness(&f, testRuns, settings, now)
+		if err = f.Replace(ctx); err != nil {
+			j.AddError(err)
+			continue
+		}
+
+		if quarantined {
+			numQuarantined++
+			event.LogTestQuarantined(ctx, f.LastTaskID, f.LastExecution, f.TestName, f.Score)
+		}
+		if released {
+			numReleased++
+			event.LogTestReleased(ctx, f.LastTaskID, f.LastExecution, f.TestName, f.Score)
+		}
+	}
+
+	j.AddError(flakytest.RemoveStale(ctx, j.ProjectID, now.Add(-flakyTestScoringLookback)))
+
+	grip.Info(ctx, message.Fields{
+		"message":         "scored flaky tests",
+		"job_id":          j.ID(),
+		"project_id":      j.ProjectID,
+		"num_tests":       len(runs),
+		"num_quarantined": numQuarantined,
+		"num_released":    numReleased,
+	})
+}
+
+// updateTestFlakiness rescores the test and quarantines or releases it based
+// on the project's settings. It returns whether the test was quarantined or
+// released.
+func updateTestFlakiness(f *flakytest.TestFlakiness, runs []flakytest.TestRun, settings model.FlakyTestQuarantineSettings, now time.Time) (quarantined, released bool) {
+	// A released test is only scored on the runs since it was released, so
+	// that the same failures don't quarantine it again.
+	scoredRuns := runs
+	if !f.Quarantined && !f.ReleasedAt.IsZero() {
+		scoredRuns = nil
+		for _, r := range runs {
+			if r.FinishTime.After(f.ReleasedAt) {
+				scoredRuns = append(scoredRuns, r)
+			}
+		}
+	}
+	f.SetScores(flakytest.Compute(scoredRuns))
+	f.UpdatedAt = now
+	if len(runs) > 0 {
+		latest := runs[0]
+		for _, r := range runs[1:] {
+			if r.FinishTime.After(latest.FinishTime) {
+				latest = r
+			}
+		}
+		f.LastTaskID = latest.TaskID
+		f.LastExecution = latest.Execution
+	}
+
+	if f.Quarantined {
+		f.CleanRuns = flakytest.CleanRunsSince(runs, f.QuarantinedAt)
+		if f.CleanRuns >= settings.GetReleaseAfterCleanRuns() {
+			f.Release(now)
+			return false, true
+		}
+		return false, false
+	}
+
+	if f.NumRuns >= settings.GetMinRuns() && f.Score >= settings.GetThreshold() {
+		f.Quarantine(now)
+		return true, false
+	}
+	return false, false
+}
+
+// findTestRuns returns the outcome of every test that passed or failed in the
+// project's mainline tasks that finished since the given time, including
+// earlier executions of restarted tasks.
+func (j *flakyTestScoringJob) findTestRuns(ctx context.Context, since time.Time) (map[flakyTestKey][]flakytest.TestRun, error) {
+	tasks, err := task.FindAll(ctx, db.Query(bson.M{
+		task.ProjectKey:     j.ProjectID,
+		task.RequesterKey:   bson.M{"$in": evergreen.SystemVersionRequesterTypes},
+		task.StatusKey:      bson.M{"$in": evergreen.TaskCompletedStatuses},
+		task.FinishTimeKey:  bson.M{"$gte": since},
+		task.DisplayOnlyKey: bson.M{"$ne": true},
+	}).Sort([]string{"-" + task.FinishTimeKey}).Limit(flakyTestScoringMaxTasks))
+	if err != nil {
+		return nil, errors.Wrap(err, "finding recent mainline tasks")
+	}
+
+	var restarted []string
+	for _, t := range tasks {
+		if t.Execution > 0 {
+			restarted = append(restarted, t.Id)
+		}
+	}
+	if len(restarted) > 0 {
+		oldTasks, err := task.FindAllOld(ctx, db.Query(bson.M{task.OldTaskIdKey: bson.M{"$in": restarted}}))
+		if err != nil {
+			return nil, errors.Wrap(err, "finding earlier executions of restarted tasks")
+		}
+		tasks = append(tasks, oldTasks...)
+	}
+
+	results := make([][]flakytest.TestRun, len(tasks))
+	keys := make([][]flakyTestKey, len(tasks))
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(flakyTestScoringConcurrency)
+	for i := range tasks {
+		t := tasks[i]
+		eg.Go(func() error {
+			testResults, err := t.GetTestResults(egCtx, j.env, nil)
+			if err != nil {
+				if egCtx.Err() != nil {
+					return egCtx.Err()
+				}
+				// Score the tests without this task's results rather than
+				// not scoring any of the project's tests.
+				grip.Warning(egCtx, message.WrapError(err, message.Fields{
+					"message":   "could not get test results for flaky test scoring, skipping task",
+					"project":   j.ProjectID,
+					"task_id":   t.Id,
+					"execution": t.Execution,
+					"job_id":    j.ID(),
+				}))
+				return nil
+			}
+			taskID := t.Id
+			if t.Archived {
+				taskID = t.OldTaskId
+			}
+			for _, r := range testResults.Results {
+				var failed bool
+				switch r.Status {
+				case evergreen.TestSucceededStatus:
+				case evergreen.TestFailedStatus, evergreen.TestSilentlyFailedStatus:
+					failed = true
+				default:
+					continue
+				}
+				keys[i] = append(keys[i], flakyTestKey{buildVariant: t.BuildVariant, taskName: t.DisplayName, testName: r.GetDisplayTestName()})
+				results[i] = append(results[i], flakytest.TestRun{
+					TaskID:     taskID,
+					Execution:  t.Execution,
+					Revision:   t.Revision,
+					FinishTime: t.FinishTime,
+					Failed:     failed,
+				})
+			}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	runs := map[flakyTestKey][]flakytest.TestRun{}
+	for i := range results {
+		for k, key := range keys[i] {
+			runs[key] = append(runs[key], results[i][k])
+		}
+	}
+	return runs, nil
+}
@@ -0,0 +1,87 @@
+package units
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/flakytest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateTestFlakiness(t *testing.T) {
+	now := time.Now()
+	settings := model.FlakyTestQuarantineSettings{
+		Enabled:               true,
+		Threshold:             0.5,
+		MinRuns:               4,
+		ReleaseAfterCleanRuns: 2,
+	}
+	flakyRuns := []flakytest.TestRun{
+		{TaskID: "t1", Revision: "r1", FinishTime: now.Add(-5 * time.Hour), Failed: true},
+		{TaskID: "t1", Execution: 1, Revision: "r1", FinishTime: now.Add(-4 * time.Hour)},
+		{TaskID: "t2", Revision: "r2", FinishTime: now.Add(-3 * time.Hour), Failed: true},
+		{TaskID: "t2", Execution: 1, Revision: "r2", FinishTime: now.Add(-2 * time.Hour)},
+	}
+
+	t.Run("QuarantinesFlakyTest", func(t *testing.T) {
+		f := flakytest.TestFlakiness{}
+		quarantined, released := updateTestFlakiness(&f, flakyRuns, settings, now)
+		assert.True(t, quarantined)
+		assert.False(t, released)
+		assert.True(t, f.Quarantined)
+		assert.Equal(t, now, f.QuarantinedAt)
+		assert.Equal(t, "t2", f.LastTaskID)
+		assert.Equal(t, 1, f.LastExecution)
+		assert.Equal(t, now, f.UpdatedAt)
+	})
+	t.Run("DoesNotQuarantineWithTooFewRuns", func(t *testing.T) {
+		f := flakytest.TestFlakiness{}
+		quarantined, _ := updateTestFlakiness(&f, flakyRuns[:2], settings, now)
+		assert.False(t, quarantined)
+		assert.False(t, f.Quarantined)
+		assert.Equal(t, 1.0, f.RetryPassRate, "should still be scored")
+	})
+	t.Run("DoesNotQuarantineBelowThreshold", func(t *testing.T) {
+		f := flakytest.TestFlakiness{}
+		runs := []flakytest.TestRun{
+			{TaskID: "t1", Revision: "r1", FinishTime: now.Add(-5 * time.Hour), Failed: true},
+			{TaskID: "t2", Revision: "r2", FinishTime: now.Add(-4 * time.Hour)},
+			{TaskID: "t3", Revision: "r3", FinishTime: now.Add(-3 * time.Hour)},
+			{TaskID: "t4", Revision: "r4", FinishTime: now.Add(-2 * time.Hour)},
+			{TaskID: "t5", Revision: "r5", FinishTime: now.Add(-time.Hour)},
+		}
+		quarantined, _ := updateTestFlakiness(&f, runs, settings, now)
+		assert.False(t, quarantined)
+		assert.False(t, f.Quarantined)
+		assert.Less(t, f.Score, settings.Threshold)
+	})
+	t.Run("CountsCleanRunsWhileQuarantined", func(t *testing.T) {
+		f := flakytest.TestFlakiness{Quarantined: true, QuarantinedAt: now.Add(-90 * time.Minute)}
+		runs := append([]flakytest.TestRun{}, flakyRuns...)
+		runs = append(runs, flakytest.TestRun{TaskID: "t3", Revision: "r3", FinishTime: now.Add(-time.Hour)})
+		quarantined, released := updateTestFlakiness(&f, runs, settings, now)
+		assert.False(t, quarantined)
+		assert.False(t, released)
+		assert.True(t, f.Quarantined)
+		assert.Equal(t, 1, f.CleanRuns)
+	})
+	t.Run("ReleasesAfterEnoughCleanRuns", func(t *testing.T) {
+		f := flakytest.TestFlakiness{Quarantined: true, QuarantinedAt: now.Add(-3 * time.Hour)}
+		runs := append([]flakytest.TestRun{}, flakyRuns...)
+		runs = append(runs, flakytest.TestRun{TaskID: "t3", Revision: "r3", FinishTime: now.Add(-time.Hour)})
+		quarantined, released := updateTestFlakiness(&f, runs, settings, now)
+		assert.False(t, quarantined)
+		assert.True(t, released)
+		assert.False(t, f.Quarantined)
+		assert.Equal(t, now, f.ReleasedAt)
+	})
+	t.Run("ReleasedTestIsOnlyScoredOnNewRuns", func(t *testing.T) {
+		f := flakytest.TestFlakiness{ReleasedAt: now.Add(-150 * time.Minute)}
+		quarantined, released := updateTestFlakiness(&f, flakyRuns, settings, now)
+		assert.False(t, quarantined, "failures before the release should not quarantine the test again")
+		assert.False(t, released)
+		assert.Equal(t, 1, f.NumRuns)
+		assert.Equal(t, "t2", f.LastTaskID)
+	})
+}
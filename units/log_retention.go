@@ -0,0 +1,130 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/log"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	logRetentionJobName     = "log-retention"
+	logRetentionTimeout     = 30 * time.Minute
+	logRetentionMaxAttempts = 3
+)
+
+func init() {
+	registry.AddJobType(logRetentionJobName, func() amboy.Job {
+		return makeLogRetentionJob()
+	})
+}
+
+type logRetentionJob struct {
+	job.Base `bson:"metadata" json:"metadata" yaml:"metadata"`
+	TaskID   string `bson:"task_id" json:"task_id" yaml:"task_id"`
+	// Stage is the retention tier the task's logs are moved to.
+	Stage task.LogRetentionStage `bson:"stage" json:"stage" yaml:"stage"`
+}
+
+func makeLogRetentionJob() *logRetentionJob {
+	return &logRetentionJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    logRetentionJobName,
+				Version: 0,
+			},
+		},
+	}
+}
+
+// NewLogRetentionJob creates a job that compacts, reduces, or deletes the logs
+// of a finished task depending on the given retention stage.
+func NewLogRetentionJob(taskID string, stage task.LogRetentionStage, ts string) amboy.Job {
+	j := makeLogRetentionJob()
+	j.TaskID = taskID
+	j.Stage = stage
+	jobID := fmt.Sprintf("%s.%s.%s.%s", logRetentionJobName, taskID, stage, ts)
+	j.SetID(jobID)
+	j.SetScopes([]string{fmt.Sprintf("%s.%s", logRetentionJobName, taskID)})
+	j.SetEnqueueAllScopes(true)
+	j.UpdateTimeInfo(amboy.JobTimeInfo{MaxTime: logRetentionTimeout})
+	j.UpdateRetryInfo(amboy.JobRetryOptions{
+		Retryable:   utility.TruePtr(),
+		MaxAttempts: utility.ToIntPtr(logRetentionMaxAttempts),
+	})
+
+	return j
+}
+
+func (j *logRetentionJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	t, err := task.FindOneId(ctx, j.TaskID)
+	if err != nil {
+		j.AddRetryableError(errors.Wrapf(err, "finding task '%s'", j.TaskID))
+		return
+	}
+	if t == nil {
+		j.AddError(errors.Errorf("task '%s' not found", j.TaskID))
+		return
+	}
+	if !t.IsFinished() {
+		// The task was restarted since the job was enqueued.
+		return
+	}
+
+	var res log.CompactResult
+	var removed int
+	switch j.Stage {
+	case task.LogRetentionStageCompacted:
+		res, err = t.CompactLogs(ctx)
+	case task.LogRetentionStageReduced:
+		var pRef *model.ProjectRef
+		pRef, err = model.FindMergedProjectRef(ctx, t.Project, t.Version, false)
+		if err != nil {
+			j.AddRetryableError(errors.Wrapf(err, "finding project ref '%s'", t.Project))
+			return
+		}
+		if pRef == nil {
+			j.AddError(errors.Errorf("project ref '%s' not found", t.Project))
+			return
+		}
+		res, err = t.ReduceLogs(ctx, pRef.LogRetention.ReductionOptions())
+	case task.LogRetentionStageDeleted:
+		removed, err = t.DeleteLogs(ctx)
+	default:
+		j.AddError(errors.Errorf("unrecognized log retention stage '%s'", j.Stage))
+		return
+	}
+	if err != nil {
+		j.AddRetryableError(errors.Wrapf(err, "moving logs of task '%s' to retention stage '%s'", t.Id, j.Stage))
+		return
+	}
+
+	grip.Info(ctx, message.Fields{
+		"message":        "applied log retention stage",
+		"job":            j.ID(),
+		"task_id":        t.Id,
+		"project":        t.Project,
+		"stage":          j.Stage,
+		"logs_compacted": res.LogsCompacted,
+		"chunks_read":    res.ChunksRead,
+		"chunks_written": res.ChunksWritten,
+		"chunks_removed": removed,
+		"lines_written":  res.LinesWritten,
+		"bytes_uploaded": res.BytesUploaded,
+		"put_requests":   res.PutRequests,
+		"finish_time":    t.FinishTime,
+		"bucket":         t.LogBucketName(),
+	})
+}
@@ -0,0 +1,92 @@
+package units
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/log"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/grip/level"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestLogRetentionJob(t *testing.T) {
+	setup := func(t *testing.T) *task.Task {
+		require.NoError(t, db.ClearCollections(task.Collection, model.ProjectRefCollection))
+		pRef := model.ProjectRef{
+			Id:           "project",
+			LogRetention: model.LogRetentionSettings{ReduceAfterDays: 7, TailLines: 2},
+		}
+		require.NoError(t, pRef.Insert(t.Context()))
+		tsk := &task.Task{
+			Id:         "task",
+			Project:    pRef.Id,
+			Status:     evergreen.TaskSucceeded,
+			FinishTime: time.Now().Add(-30 * 24 * time.Hour),
+			TaskOutputInfo: &task.TaskOutput{
+				TaskLogs: task.TaskLogOutput{
+					Version:      1,
+					BucketConfig: evergreen.BucketConfig{Type: evergreen.BucketTypeLocal, Name: t.TempDir()},
+				},
+			},
+		}
+		require.NoError(t, tsk.Insert(t.Context()))
+
+		for i := range 5 {
+			require.NoError(t, task.AppendTaskLogs(t.Context(), tsk, task.TaskLogTypeTask, []log.LogLine{
+				{Priority: level.Info, Timestamp: time.Now().UnixNano(), Data: "line"},
+			}), "appending chunk %d", i)
+		}
+
+		return tsk
+	}
+	countLines := func(t *testing.T, tsk *task.Task) int {
+		it, err := tsk.GetTaskLogs(t.Context(), task.TaskLogGetOptions{LogType: task.TaskLogTypeTask})
+		require.NoError(t, err)
+		var n int
+		for it.Next() {
+			n++
+		}
+		require.NoError(t, it.Err())
+		return n
+	}
+	runJob := func(t *testing.T, tsk *task.Task, stage task.LogRetentionStage) *task.Task {
+		j := NewLogRetentionJob(tsk.Id, stage, utility.RoundPartOfHour(0).Format(TSFormat))
+		j.Run(t.Context())
+		require.NoError(t, j.Error())
+
+		dbTask, err := task.FindOneId(t.Context(), tsk.Id)
+		require.NoError(t, err)
+		require.NotNil(t, dbTask)
+		assert.Equal(t, stage, dbTask.LogRetentionStage)
+		return dbTask
+	}
+
+	t.Run("Compacts", func(t *testing.T) {
+		tsk := runJob(t, setup(t), task.LogRetentionStageCompacted)
+		assert.Equal(t, 5, countLines(t, tsk))
+	})
+	t.Run("ReducesWithProjectSettings", func(t *testing.T) {
+		tsk := runJob(t, setup(t), task.LogRetentionStageReduced)
+		assert.Equal(t, 2, countLines(t, tsk))
+	})
+	t.Run("Deletes", func(t *testing.T) {
+		tsk := runJob(t, setup(t), task.LogRetentionStageDeleted)
+		assert.Zero(t, countLines(t, tsk))
+	})
+	t.Run("SkipsUnfinishedTasks", func(t *testing.T) {
+		tsk := setup(t)
+		require.NoError(t, task.UpdateOne(t.Context(), task.ById(tsk.Id), bson.M{"$set": bson.M{task.StatusKey: evergreen.TaskStarted}}))
+
+		j := NewLogRetentionJob(tsk.Id, task.LogRetentionStageDeleted, utility.RoundPartOfHour(0).Format(TSFormat))
+		j.Run(t.Context())
+		require.NoError(t, j.Error())
+		assert.Equal(t, 5, countLines(t, tsk))
+	})
+}
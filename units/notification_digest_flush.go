@@ -0,0 +1,152 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/evergreen-ci/evergreen/trigger"
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/mongodb/grip/sometimes"
+	"github.com/pkg/errors"
+)
+
+const (
+	notificationDigestFlushJobName = "notification-digest-flush"
+)
+
+func init() {
+	registry.AddJobType(notificationDigestFlushJobName, func() amboy.Job { return makeNotificationDigestFlushJob() })
+}
+
+type notificationDigestFlushJob struct {
+	job.Base `bson:"job_base" json:"job_base" yaml:"job_base"`
+	env      evergreen.Environment
+	q        amboy.Queue
+}
+
+func makeNotificationDigestFlushJob() *notificationDigestFlushJob {
+	j := &notificationDigestFlushJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    notificationDigestFlushJobName,
+				Version: 0,
+			},
+		},
+	}
+	return j
+}
+
+// NewNotificationDigestFlushJob returns a job that sends every pending
+// notification digest whose window has closed as a single summary
+// notification.
+func NewNotificationDigestFlushJob(ts string) amboy.Job {
+	j := makeNotificationDigestFlushJob()
+	j.SetID(fmt.Sprintf("%s.%s", notificationDigestFlushJobName, ts))
+	j.SetScopes([]string{notificationDigestFlushJobName})
+	j.SetEnqueueAllScopes(true)
+	return j
+}
+
+func (j *notificationDigestFlushJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+	if j.env == nil {
+		j.env = evergreen.GetEnvironment()
+	}
+	if j.q == nil {
+		j.q = j.env.RemoteQueue()
+	}
+
+	flags, err := evergreen.GetServiceFlags(ctx)
+	if err != nil {
+		j.AddError(errors.Wrap(err, "getting service flags"))
+		return
+	}
+	if flags.EventProcessingDisabled {
+		grip.InfoWhen(ctx, sometimes.Percent(evergreen.DegradedLoggingPercent), message.Fields{
+			"job_type": j.Type().Name,
+			"message":  "events processing is disabled",
+		})
+		return
+	}
+
+	ids, err := notification.FindDigestIDsToFlush(ctx, time.Now())
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+
+	notifications := make([]notification.Notification, 0, len(ids))
+	for _, id := range ids {
+		n, err := j.flushDigest(ctx, id)
+		if err != nil {
+			j.AddError(err)
+			continue
+		}
+		if n != nil {
+			notifications = append(notifications, *n)
+		}
+	}
+	if len(notifications) == 0 {
+		return
+	}
+
+	jobs, err := notificationJobs(ctx, notifications, flags, utility.RoundPartOfMinute(0))
+	j.AddError(errors.Wrap(err, "getting notification jobs"))
+	j.AddError(errors.Wrap(j.q.PutMany(ctx, jobs), "enqueueing notification jobs"))
+
+	grip.Info(ctx, message.Fields{
+		"job_id":        j.ID(),
+		"job_type":      j.Type().Name,
+		"source":        "events-processing",
+		"message":       "flushed notification digests",
+		"num_digests":   len(ids),
+		"notifications": len(notifications),
+	})
+}
+
+// flushDigest claims the digest, inserts the summary notification for it and
+// returns the notification. If the notification can't be built or inserted,
+// the digest is restored so that a later job can flush it. It returns nil if
+// another job already claimed the digest.
+func (j *notificationDigestFlushJob) flushDigest(ctx context.Context, id string) (*notification.Notification, error) {
+	d, err := notification.ClaimDigest(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if d == nil || len(d.Items) == 0 {
+		return nil, nil
+	}
+
+	n, err := insertDigestNotification(ctx, d)
+	if err != nil {
+		catcher := grip.NewBasicCatcher()
+		catcher.Wrapf(err, "digest '%s'", id)
+		catcher.Wrapf(notification.RestoreDigest(ctx, d), "restoring digest '%s'", id)
+		return nil, catcher.Resolve()
+	}
+	return n, nil
+}
+
+func insertDigestNotification(ctx context.Context, d *notification.Digest) (*notification.Notification, error) {
+	payload, err := trigger.DigestPayload(d)
+	if err != nil {
+		return nil, errors.Wrap(err, "building payload")
+	}
+	n, err := notification.NewDigestNotification(d, payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating notification")
+	}
+	if err = notification.InsertMany(ctx, *n); err != nil && !db.IsDuplicateKey(err) {
+		return nil, errors.Wrap(err, "inserting notification")
+	}
+	return n, nil
+}
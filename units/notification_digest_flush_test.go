@@ -0,0 +1,35 @@
+package units
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlushDigestRestoresDigestOnFailure(t *testing.T) {
+	require.NoError(t, db.ClearCollections(notification.DigestsCollection, notification.Collection))
+	d := notification.Digest{
+		ID:         "digest",
+		Subscriber: event.Subscriber{Type: event.JIRAIssueSubscriberType, Target: "EVG"},
+		Mode:       event.DigestModeWindow,
+		Items:      []notification.DigestItem{{EventID: "e1", ID: "t1", Failed: true}},
+		Total:      1,
+		CreatedAt:  time.Now(),
+		FlushAt:    time.Now(),
+	}
+	require.NoError(t, db.Insert(t.Context(), notification.DigestsCollection, d))
+
+	j := makeNotificationDigestFlushJob()
+	n, err := j.flushDigest(t.Context(), d.ID)
+	assert.Error(t, err, "subscriber type does not support digests")
+	assert.Nil(t, n)
+
+	ids, err := notification.FindDigestIDsToFlush(t.Context(), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, []string{d.ID}, ids, "digest should be restored so that it's not lost")
+}
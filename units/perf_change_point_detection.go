@@ -0,0 +1,170 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/perf"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	perfChangePointDetectionJobName = "perf-change-point-detection"
+	perfChangePointDetectionTimeout = 30 * time.Minute
+	// perfChangePointLookback is how recently a series must have gotten a new
+	// mainline point to be checked for change points. It's longer than the
+	// interval between jobs so that points reported late aren't missed.
+	perfChangePointLookback = 2 * time.Hour
+	// perfChangePointHistoryLength caps the number of a series' most recent
+	// mainline points that are checked for change points.
+	perfChangePointHistoryLength = 500
+)
+
+func init() {
+	registry.AddJobType(perfChangePointDetectionJobName, func() amboy.Job {
+		return makePerfChangePointDetectionJob()
+	})
+}
+
+type perfChangePointDetectionJob struct {
+	job.Base  `bson:"metadata" json:"metadata" yaml:"metadata"`
+	ProjectID string `bson:"project_id" json:"project_id" yaml:"project_id"`
+}
+
+func makePerfChangePointDetectionJob() *perfChangePointDetectionJob {
+	return &perfChangePointDetectionJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    perfChangePointDetectionJobName,
+				Version: 0,
+			},
+		},
+	}
+}
+
+// NewPerfChangePointDetectionJob creates a job that checks the mainline
+// history of each of the project's performance series that got new results
+// recently for change points, and logs a perf regression event for each new
+// one that made performance worse.
+func NewPerfChangePointDetectionJob(projectID, ts string) amboy.Job {
+	j := makePerfChangePointDetectionJob()
+	j.ProjectID = projectID
+	j.SetID(fmt.Sprintf("%s.%s.%s", perfChangePointDetectionJobName, projectID, ts))
+	j.SetScopes([]string{fmt.Sprintf("%s.%s", perfChangePointDetectionJobName, projectID)})
+	j.SetEnqueueAllScopes(true)
+	j.UpdateTimeInfo(amboy.JobTimeInfo{MaxTime: perfChangePointDetectionTimeout})
+	return j
+}
+
+func (j *perfChangePointDetectionJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	now := time.Now()
+	seriesIDs, err := perf.FindSeriesWithNewPoints(ctx, j.ProjectID, now.Add(-perfChangePointLookback))
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+
+	var numChangePoints, numRegressions int
+	for _, seriesID := range seriesIDs {
+		if ctx.Err() != nil {
+			j.AddError(ctx.Err())
+			return
+		}
+		changePoints, err := detectSeriesChangePoints(ctx, seriesID, now)
+		if err != nil {
+			j.AddError(errors.Wrapf(err, "detecting change points in series '%s'", seriesID))
+			continue
+		}
+		for _, cp := range changePoints {
+			if !cp.Regression {
+				continue
+			}
+			event.LogTaskPerfRegression(ctx, cp.TaskID, cp.Execution, cp.TestName, cp.Metric, cp.PercentChange)
+			numRegressions++
+		}
+		numChangePoints += len(changePoints)
+	}
+
+	grip.Info(ctx, message.Fields{
+		"message":           "detected perf change points",
+		"job_id":            j.ID(),
+		"project_id":        j.ProjectID,
+		"num_series":        len(seriesIDs),
+		"num_change_points": numChangePoints,
+		"num_regressions":   numRegressions,
+	})
+}
+
+// detectSeriesChangePoints finds the change points in the series' recent
+// mainline history and saves the ones that haven't already been found. It
+// returns the new change points.
+func detectSeriesChangePoints(ctx context.Context, seriesID string, now time.Time) ([]perf.ChangePoint, error) {
+	history, err := perf.FindMainlineHistory(ctx, seriesID, perfChangePointHistoryLength)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := perf.FindChangePointsForSeries(ctx, seriesID)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]float64, 0, len(history))
+	for _, p := range history {
+		values = append(values, p.Value)
+	}
+	changes := filterKnownChanges(history, perf.DetectChanges(values, perf.DetectorOptions{}), existing, perf.DefaultMinSegmentLength)
+
+	newChangePoints := []perf.ChangePoint{}
+	for _, change := range changes {
+		cp := perf.NewChangePoint(history[change.Index], change, now)
+		inserted, err := cp.Insert(ctx)
+		if err != nil {
+			return newChangePoints, err
+		}
+		if inserted {
+			newChangePoints = append(newChangePoints, cp)
+		}
+	}
+	return newChangePoints, nil
+}
+
+// filterKnownChanges removes the changes that are within the given number of
+// points of a change point that was already found. As more history comes in,
+// the detector can move a change point slightly, which shouldn't be reported
+// as another regression.
+func filterKnownChanges(history []perf.Point, changes []perf.Change, existing []perf.ChangePoint, window int) []perf.Change {
+	indexByOrder := make(map[int]int, len(history))
+	for i, p := range history {
+		indexByOrder[p.Order] = i
+	}
+	knownIndexes := []int{}
+	for _, cp := range existing {
+		if i, ok := indexByOrder[cp.Order]; ok {
+			knownIndexes = append(knownIndexes, i)
+		}
+	}
+
+	filtered := []perf.Change{}
+	for _, change := range changes {
+		known := false
+		for _, i := range knownIndexes {
+			if d := change.Index - i; d >= -window && d <= window {
+				known = true
+				break
+			}
+		}
+		if !known {
+			filtered = append(filtered, change)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,28 @@
+package units
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model/perf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterKnownChanges(t *testing.T) {
+	history := make([]perf.Point, 40)
+	for i := range history {
+		history[i] = perf.Point{Order: 100 + 2*i}
+	}
+	changes := []perf.Change{{Index: 12}, {Index: 30}}
+
+	t.Run("KeepsNewChanges", func(t *testing.T) {
+		assert.Equal(t, changes, filterKnownChanges(history, changes, nil, 5))
+	})
+	t.Run("RemovesChangesNearKnownChangePoints", func(t *testing.T) {
+		existing := []perf.ChangePoint{{Order: history[10].Order}}
+		assert.Equal(t, []perf.Change{{Index: 30}}, filterKnownChanges(history, changes, existing, 5))
+	})
+	t.Run("IgnoresChangePointsOutsideHistory", func(t *testing.T) {
+		existing := []perf.ChangePoint{{Order: 1}}
+		assert.Equal(t, changes, filterKnownChanges(history, changes, existing, 5))
+	})
+}
@@ -3,6 +3,7 @@ package units
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/thirdparty"
@@ -31,6 +32,10 @@ func init() {
 type periodicBuildJob struct {
 	ProjectID    string `bson:"project_id"`
 	DefinitionID string `bson:"def_id"`
+	// ManualRunBy is the user who ran the periodic build on demand. Manual
+	// runs ignore the definition's schedule, blackout windows and
+	// skip_if_unchanged, and don't change its next run time.
+	ManualRunBy string `bson:"manual_run_by,omitempty"`
 
 	project *model.ProjectRef
 	env     evergreen.Environment
@@ -62,6 +67,18 @@ func NewPeriodicBuildJob(projectID, definitionID string) amboy.Job {
 	return j
 }
 
+// NewPeriodicBuildManualRunJob returns a job that runs the periodic build
+// right away on behalf of the user, outside of its schedule.
+func NewPeriodicBuildManualRunJob(projectID, definitionID, user string, ts time.Time) amboy.Job {
+	j := makePeriodicBuildsJob()
+	j.ProjectID = projectID
+	j.DefinitionID = definitionID
+	j.ManualRunBy = user
+	j.SetID(fmt.Sprintf("%s-%s-%s-manual-%s", periodicBuildJobName, projectID, definitionID, ts.Format(TSFormat)))
+
+	return j
+}
+
 func (j *periodicBuildJob) Run(ctx context.Context) {
 	if j.env == nil {
 		j.env = evergreen.GetEnvironment()
@@ -84,20 +101,63 @@ func (j *periodicBuildJob) Run(ctx context.Context) {
 		j.AddError(errors.Errorf("periodic build definition '%s' not found", j.DefinitionID))
 		return
 	}
-	defer func() {
-		err = model.UpdateNextPeriodicBuild(ctx, j.ProjectID, definition)
-		grip.Error(ctx, message.WrapError(err, message.Fields{
-			"message":    "unable to set next periodic build job time",
-			"project":    j.ProjectID,
-			"definition": j.DefinitionID,
-		}))
-	}()
+	isManualRun := j.ManualRunBy != ""
+	if !isManualRun {
+		defer func() {
+			err = model.UpdateNextPeriodicBuild(ctx, j.ProjectID, definition)
+			grip.Error(ctx, message.WrapError(err, message.Fields{
+				"message":    "unable to set next periodic build job time",
+				"project":    j.ProjectID,
+				"definition": j.DefinitionID,
+			}))
+		}()
+
+		// The job is enqueued ahead of the run it's for, so the blackout
+		// windows apply to the scheduled time rather than the current one.
+		runTime := definition.NextRunTime
+		if utility.IsZeroTime(runTime) {
+			runTime = time.Now()
+		}
+		if window := definition.InBlackout(runTime); window != nil {
+			grip.Info(ctx, message.Fields{
+				"message":      "skipping periodic build during blackout window",
+				"project":      j.ProjectID,
+				"definition":   j.DefinitionID,
+				"run_time":     runTime,
+				"window_start": window.Start,
+				"window_end":   window.End,
+				"reason":       window.Reason,
+			})
+			return
+		}
+	}
 
 	mostRecentRevision, authorID, err := model.FindLatestRevisionAndAuthorForProject(ctx, j.ProjectID)
 	if err != nil {
 		j.AddError(err)
 		return
 	}
+	if !isManualRun && definition.SkipIfUnchanged {
+		lastVersion, err := model.FindLastPeriodicBuild(ctx, j.ProjectID, definition.ID)
+		if err != nil {
+			j.AddError(errors.Wrapf(err, "finding last periodic build for definition '%s'", definition.ID))
+			return
+		}
+		if lastVersion != nil && lastVersion.Revision == mostRecentRevision {
+			grip.Info(ctx, message.Fields{
+				"message":      "skipping periodic build because the tracked branch hasn't changed",
+				"project":      j.ProjectID,
+				"definition":   j.DefinitionID,
+				"revision":     mostRecentRevision,
+				"last_version": lastVersion.Id,
+			})
+			return
+		}
+	}
+
+	if isManualRun {
+		authorID = j.ManualRunBy
+	}
 	usr, err := user.GetPeriodicBuildUser(ctx, authorID)
 	if err != nil {
 		grip.Error(ctx, message.WrapError(err, message.Fields{
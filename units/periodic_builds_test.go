@@ -71,3 +71,120 @@ func TestPeriodicBuildsJob(t *testing.T) {
 	assert.True(sampleProject.PeriodicBuilds[0].NextRunTime.Add(time.Hour).Equal(dbProject.PeriodicBuilds[0].NextRunTime))
 	assert.Equal(usr.Id, createdVersion.AuthorID)
 }
+
+func TestPeriodicBuildsJobSkips(t *testing.T) {
+	for tName, tCase := range map[string]func(t *testing.T, j *periodicBuildJob, pRef model.ProjectRef){
+		"SkipsDuringBlackoutWindow": func(t *testing.T, j *periodicBuildJob, pRef model.ProjectRef) {
+			now := time.Now()
+			pRef.PeriodicBuilds[0].BlackoutWindows = []model.PeriodicBuildBlackoutWindow{
+				{Start: now.Add(-time.Hour), End: now.Add(2 * time.Hour), Reason: "release freeze"},
+			}
+			require.NoError(t, pRef.Insert(t.Context()))
+
+			j.Run(t.Context())
+			require.NoError(t, j.Error())
+
+			v, err := model.FindLastPeriodicBuild(t.Context(), pRef.Id, "abc")
+			require.NoError(t, err)
+			assert.Nil(t, v)
+			dbProject, err := model.FindBranchProjectRef(t.Context(), pRef.Id)
+			require.NoError(t, err)
+			require.NotNil(t, dbProject)
+			assert.True(t, pRef.PeriodicBuilds[0].NextRunTime.Add(time.Hour).Equal(dbProject.PeriodicBuilds[0].NextRunTime), "skipped run should still be rescheduled")
+		},
+		"SkipsScheduledRunInBlackoutWindowThatHasNotStartedYet": func(t *testing.T, j *periodicBuildJob, pRef model.ProjectRef) {
+			runTime := pRef.PeriodicBuilds[0].NextRunTime
+			pRef.PeriodicBuilds[0].BlackoutWindows = []model.PeriodicBuildBlackoutWindow{
+				{Start: runTime.Add(-time.Minute), End: runTime.Add(time.Hour), Reason: "release freeze"},
+			}
+			require.NoError(t, pRef.Insert(t.Context()))
+
+			j.Run(t.Context())
+			require.NoError(t, j.Error())
+
+			v, err := model.FindLastPeriodicBuild(t.Context(), pRef.Id, "abc")
+			require.NoError(t, err)
+			assert.Nil(t, v, "run scheduled in the window should be skipped even though the job ran before it opened")
+		},
+		"DoesNotSkipScheduledRunAfterBlackoutWindow": func(t *testing.T, j *periodicBuildJob, pRef model.ProjectRef) {
+			runTime := pRef.PeriodicBuilds[0].NextRunTime
+			pRef.PeriodicBuilds[0].BlackoutWindows = []model.PeriodicBuildBlackoutWindow{
+				{Start: time.Now().Add(-time.Hour), End: runTime, Reason: "release freeze"},
+			}
+			require.NoError(t, pRef.Insert(t.Context()))
+			require.NoError(t, (&user.DBUser{Id: evergreen.PeriodicBuildUser}).Insert(t.Context()))
+			testutil.ConfigureIntegrationTest(t, j.env.Settings())
+
+			j.Run(t.Context())
+			require.NoError(t, j.Error())
+
+			v, err := model.FindLastPeriodicBuild(t.Context(), pRef.Id, "abc")
+			require.NoError(t, err)
+			assert.NotNil(t, v, "run scheduled when the window ends should not be skipped even though the job ran during it")
+		},
+		"SkipsIfUnchanged": func(t *testing.T, j *periodicBuildJob, pRef model.ProjectRef) {
+			pRef.PeriodicBuilds[0].SkipIfUnchanged = true
+			require.NoError(t, pRef.Insert(t.Context()))
+			lastPeriodicBuild := model.Version{
+				Id:              "last_periodic_build",
+				Identifier:      pRef.Id,
+				Requester:       evergreen.AdHocRequester,
+				Revision:        "88dcc12106a40cb4917f552deab7574ececd9a3e",
+				PeriodicBuildID: "abc",
+				CreateTime:      time.Now().Add(-time.Hour),
+			}
+			require.NoError(t, lastPeriodicBuild.Insert(t.Context()))
+
+			j.Run(t.Context())
+			require.NoError(t, j.Error())
+
+			v, err := model.FindLastPeriodicBuild(t.Context(), pRef.Id, "abc")
+			require.NoError(t, err)
+			require.NotNil(t, v)
+			assert.Equal(t, lastPeriodicBuild.Id, v.Id, "no new version should be created")
+			dbProject, err := model.FindBranchProjectRef(t.Context(), pRef.Id)
+			require.NoError(t, err)
+			require.NotNil(t, dbProject)
+			assert.True(t, pRef.PeriodicBuilds[0].NextRunTime.Add(time.Hour).Equal(dbProject.PeriodicBuilds[0].NextRunTime), "skipped run should still be rescheduled")
+		},
+	} {
+		t.Run(tName, func(t *testing.T) {
+			require.NoError(t, db.ClearCollections(model.VersionCollection, model.ProjectRefCollection, build.Collection, task.Collection, user.Collection))
+
+			pRef := model.ProjectRef{
+				Id:         "myProject",
+				Owner:      "evergreen-ci",
+				Repo:       "sample",
+				RemotePath: "evergreen.yml",
+				Branch:     "main",
+				PeriodicBuilds: []model.PeriodicBuildDefinition{
+					{IntervalHours: 1, ID: "abc", ConfigFile: "evergreen.yml", NextRunTime: time.Now().Truncate(time.Second).Add(time.Hour)},
+				},
+			}
+			mainlineVersion := model.Version{
+				Id:                  "mainline_version",
+				Identifier:          pRef.Id,
+				Requester:           evergreen.RepotrackerVersionRequester,
+				Revision:            "88dcc12106a40cb4917f552deab7574ececd9a3e",
+				RevisionOrderNumber: 1,
+			}
+			require.NoError(t, mainlineVersion.Insert(t.Context()))
+
+			j := makePeriodicBuildsJob()
+			j.env = evergreen.GetEnvironment()
+			j.ProjectID = pRef.Id
+			j.DefinitionID = "abc"
+
+			tCase(t, j, pRef)
+		})
+	}
+}
+
+func TestNewPeriodicBuildManualRunJob(t *testing.T) {
+	ts := time.Now()
+	j, ok := NewPeriodicBuildManualRunJob("myProject", "abc", "me", ts).(*periodicBuildJob)
+	require.True(t, ok)
+	assert.Equal(t, "me", j.ManualRunBy)
+	assert.Empty(t, j.Scopes(), "manual runs should not be blocked by a scheduled run")
+	assert.NotEqual(t, NewPeriodicBuildJob("myProject", "abc").ID(), j.ID())
+}
@@ -119,10 +119,10 @@ func (j *reauthorizeUserJob) Run(ctx context.Context) {
 
 	err = um.ReauthorizeUser(ctx, j.user)
 
-	// This handles a special Okta case in which the user's refresh token from
-	// Okta has expired, in which case they should be logged out, so that they
-	// are forced to log in to get a new refresh token.
-	if err != nil && strings.Contains(err.Error(), "invalid_grant") && strings.Contains(err.Error(), "The refresh token is invalid or expired.") {
+	// This handles the special case in which the user's refresh token from
+	// the identity provider has expired, in which case they should be logged
+	// out, so that they are forced to log in to get a new refresh token.
+	if isInvalidRefreshTokenError(err) {
 		grip.Info(ctx, message.WrapError(err, message.Fields{
 			"message": "user's refresh token is invalid, logging them out",
 			"user":    j.UserID,
@@ -147,3 +147,15 @@ func (j *reauthorizeUserJob) Run(ctx context.Context) {
 		}
 	}
 }
+
+// isInvalidRefreshTokenError returns whether the error indicates that the
+// identity provider rejected the user's refresh token. Okta and the OIDC user
+// manager both report this as an invalid_grant error whose message says the
+// refresh token is invalid or expired.
+func isInvalidRefreshTokenError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "invalid_grant") && strings.Contains(strings.ToLower(msg), "refresh token is invalid or expired")
+}
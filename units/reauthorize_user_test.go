@@ -198,3 +198,11 @@ func TestReauthorizeUserJob(t *testing.T) {
 		})
 	}
 }
+
+func TestIsInvalidRefreshTokenError(t *testing.T) {
+	assert.False(t, isInvalidRefreshTokenError(nil))
+	assert.False(t, isInvalidRefreshTokenError(errors.New("fail reauth")))
+	assert.False(t, isInvalidRefreshTokenError(errors.New(`oauth2: "invalid_grant" "Code not valid"`)))
+	assert.True(t, isInvalidRefreshTokenError(errors.New(`invalid_grant: The refresh token is invalid or expired.`)), "should match Okta errors")
+	assert.True(t, isInvalidRefreshTokenError(errors.Wrap(errors.New(`oauth2: "invalid_grant" "Token is not active"`), "refresh token is invalid or expired for user 'alice'")), "should match OIDC errors")
+}
@@ -0,0 +1,141 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/evergreen-ci/utility"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/mongodb/grip/send"
+	"github.com/pkg/errors"
+)
+
+const (
+	chatWebhookAttempts = 3
+	chatWebhookMinDelay = time.Second
+)
+
+// ChatWebhook is a JSON message posted to a chat service's incoming webhook,
+// such as Microsoft Teams or Mattermost. Unlike EvergreenWebhook, the request
+// is not signed, since the URL itself authorizes the request.
+type ChatWebhook struct {
+	NotificationID string `bson:"notification_id"`
+	URL            string `bson:"url"`
+	Body           []byte `bson:"body"`
+}
+
+type chatWebhookMessage struct {
+	raw ChatWebhook
+
+	message.Base
+}
+
+// NewChatWebhookMessage returns a composer for a chat webhook message.
+func NewChatWebhookMessage(raw ChatWebhook) message.Composer {
+	return &chatWebhookMessage{
+		raw: raw,
+	}
+}
+
+func (w *chatWebhookMessage) Loggable() bool {
+	if len(w.raw.NotificationID) == 0 || len(w.raw.Body) == 0 || len(w.raw.URL) == 0 {
+		return false
+	}
+
+	err := ValidateWebhookURL(w.raw.URL)
+	if err != nil {
+		grip.Error(context.Background(), message.WrapError(err, message.Fields{
+			"message":         "chat webhook invalid url",
+			"notification_id": w.raw.NotificationID,
+		}))
+	}
+
+	return err == nil
+}
+
+func (w *chatWebhookMessage) Raw() any {
+	return &w.raw
+}
+
+func (w *chatWebhookMessage) String() string {
+	return string(w.raw.Body)
+}
+
+type chatWebhookLogger struct {
+	client *http.Client
+	*send.Base
+}
+
+// NewChatWebhookLogger returns a sender that posts chat webhook messages. It
+// uses the same destination restrictions as the Evergreen webhook sender.
+func NewChatWebhookLogger() (send.Sender, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = webhookDialContext(net.DefaultResolver)
+
+	return &chatWebhookLogger{
+		client: utility.WithOTelTracing(&http.Client{
+			Transport:     transport,
+			CheckRedirect: validateWebhookRedirect,
+		}),
+		Base: send.NewBase("evergreen"),
+	}, nil
+}
+
+func (w *chatWebhookLogger) Send(ctx context.Context, m message.Composer) {
+	if w.Level().ShouldLog(m) {
+		if err := w.send(m); err != nil {
+			w.ErrorHandler()(ctx, err, m)
+		}
+	}
+}
+
+func (w *chatWebhookLogger) send(m message.Composer) error {
+	raw, ok := m.Raw().(*ChatWebhook)
+	if !ok {
+		return errors.Errorf("received unexpected composer %T", m.Raw())
+	}
+
+	return utility.Retry(context.Background(), func() (bool, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), defaultWebhookTimeout)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, raw.URL, bytes.NewReader(raw.Body))
+		if err != nil {
+			return false, errors.Wrap(err, "creating chat webhook HTTP request")
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		msgFields := message.Fields{
+			"message":         "error sending chat webhook notification",
+			"notification_id": raw.NotificationID,
+			"is_ctx_err":      utility.IsContextError(ctx.Err()),
+		}
+		resp, err := w.client.Do(req)
+		if err != nil {
+			return true, message.WrapError(errors.Wrap(err, "sending chat webhook data"), msgFields)
+		}
+		defer resp.Body.Close()
+
+		msgFields["status_code"] = resp.StatusCode
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxWebhookResponseDrainSize))
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			// Client errors other than being throttled will not succeed on retry.
+			retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+			return retry, message.WrapError(errors.Errorf("chat webhook response was %d (%s)", resp.StatusCode, http.StatusText(resp.StatusCode)), msgFields)
+		}
+
+		return false, nil
+	}, utility.RetryOptions{
+		MaxAttempts: chatWebhookAttempts,
+		MinDelay:    chatWebhookMinDelay,
+	})
+}
+
+func (w *chatWebhookLogger) Flush(_ context.Context) error { return nil }
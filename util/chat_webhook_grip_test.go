@@ -0,0 +1,106 @@
+package util
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mongodb/grip/message"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type chatWebhookTransport struct {
+	statuses []int
+	requests []*http.Request
+	bodies   []string
+}
+
+func (t *chatWebhookTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	t.requests = append(t.requests, req)
+	t.bodies = append(t.bodies, string(body))
+
+	status := http.StatusOK
+	if len(t.statuses) > 0 {
+		status, t.statuses = t.statuses[0], t.statuses[1:]
+	}
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}, nil
+}
+
+func TestChatWebhookComposer(t *testing.T) {
+	assert.False(t, NewChatWebhookMessage(ChatWebhook{}).Loggable())
+	assert.False(t, NewChatWebhookMessage(ChatWebhook{
+		NotificationID: "evergreen",
+		URL:            "https://127.0.0.1/hooks/abc",
+		Body:           []byte(`{"text":"hi"}`),
+	}).Loggable())
+
+	m := NewChatWebhookMessage(ChatWebhook{
+		NotificationID: "evergreen",
+		URL:            "https://example.com/hooks/abc",
+		Body:           []byte(`{"text":"hi"}`),
+	})
+	assert.True(t, m.Loggable())
+	assert.Equal(t, `{"text":"hi"}`, m.String())
+}
+
+func TestChatWebhookSender(t *testing.T) {
+	sender, err := NewChatWebhookLogger()
+	require.NoError(t, err)
+	s, ok := sender.(*chatWebhookLogger)
+	require.True(t, ok)
+
+	m := NewChatWebhookMessage(ChatWebhook{
+		NotificationID: "evergreen",
+		URL:            "https://example.com/hooks/abc",
+		Body:           []byte(`{"text":"hi"}`),
+	})
+
+	for name, test := range map[string]func(*testing.T, *chatWebhookTransport){
+		"Succeeds": func(t *testing.T, transport *chatWebhookTransport) {
+			require.NoError(t, s.SetErrorHandler(func(_ context.Context, err error, _ message.Composer) {
+				t.Fatal("error handler was called, but shouldn't have been")
+			}))
+			s.Send(t.Context(), m)
+			require.Len(t, transport.requests, 1)
+			assert.Equal(t, "https://example.com/hooks/abc", transport.requests[0].URL.String())
+			assert.Equal(t, "application/json", transport.requests[0].Header.Get("Content-Type"))
+			assert.Equal(t, `{"text":"hi"}`, transport.bodies[0])
+		},
+		"RetriesWhenThrottled": func(t *testing.T, transport *chatWebhookTransport) {
+			transport.statuses = []int{http.StatusTooManyRequests}
+			require.NoError(t, s.SetErrorHandler(func(_ context.Context, err error, _ message.Composer) {
+				t.Fatal("error handler was called, but shouldn't have been")
+			}))
+			s.Send(t.Context(), m)
+			assert.Len(t, transport.requests, 2)
+		},
+		"DoesNotRetryClientError": func(t *testing.T, transport *chatWebhookTransport) {
+			transport.statuses = []int{http.StatusBadRequest}
+			var capturedErr error
+			require.NoError(t, s.SetErrorHandler(func(_ context.Context, err error, _ message.Composer) {
+				capturedErr = err
+			}))
+			s.Send(t.Context(), m)
+			assert.Len(t, transport.requests, 1)
+			require.Error(t, capturedErr)
+			assert.ErrorContains(t, capturedErr, "response was 400 (Bad Request)")
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			transport := &chatWebhookTransport{}
+			s.client = &http.Client{Transport: transport}
+			test(t, transport)
+		})
+	}
+}
@@ -26,3 +26,15 @@ func CalculateHMACHash(secret []byte, body []byte) (string, error) {
 
 	return "sha256=" + hex.EncodeToString(mac.Sum(nil)), nil
 }
+
+// CalculateTimestampedHMACHash calculates a sha256 HMAC hash of the timestamp
+// and body, joined by a ".", with the given secret. Covering the timestamp lets
+// receivers reject old payloads that are sent again. The string result has the
+// same format as CalculateHMACHash.
+func CalculateTimestampedHMACHash(secret []byte, timestamp string, body []byte) (string, error) {
+	signed := make([]byte, 0, len(timestamp)+1+len(body))
+	signed = append(signed, timestamp...)
+	signed = append(signed, '.')
+	signed = append(signed, body...)
+	return CalculateHMACHash(secret, signed)
+}
@@ -15,3 +15,18 @@ func TestCalculateHMACHash(t *testing.T) {
 	assert.NoError(err)
 	assert.Equal("sha256=d9d154a6958468d66ee12eec0fe7f9bc1c3dd6e1aa65851c66dd8be33f6ab1ae", text)
 }
+
+func TestCalculateTimestampedHMACHash(t *testing.T) {
+	body := []byte("Four score and seven bits ago")
+	secret := []byte("i have the best beard")
+
+	text, err := CalculateTimestampedHMACHash(secret, "1700000000", body)
+	assert.NoError(t, err)
+	expected, err := CalculateHMACHash(secret, []byte("1700000000.Four score and seven bits ago"))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, text)
+
+	otherTime, err := CalculateTimestampedHMACHash(secret, "1700000001", body)
+	assert.NoError(t, err)
+	assert.NotEqual(t, text, otherTime)
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/netip"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/evergreen-ci/utility"
@@ -23,6 +24,12 @@ const (
 	maxWebhookResponseDrainSize   = 64 * 1024
 	evergreenNotificationIDHeader = "X-Evergreen-Notification-ID"
 	evergreenHMACHeader           = "X-Evergreen-Signature"
+	// evergreenTimestampHeader is the Unix time in seconds at which the
+	// webhook request was signed.
+	evergreenTimestampHeader = "X-Evergreen-Timestamp"
+	// evergreenTimestampHMACHeader is the signature of the timestamp and the
+	// body, which receivers can use to reject replayed requests.
+	evergreenTimestampHMACHeader = "X-Evergreen-Timestamp-Signature"
 )
 
 type EvergreenWebhook struct {
@@ -34,6 +41,21 @@ type EvergreenWebhook struct {
 	Retries        int         `bson:"retries"`
 	MinDelayMS     int         `bson:"min_delay_ms"`
 	TimeoutMS      int         `bson:"timeout_ms"`
+
+	// OnAttempt, if set, is called after every attempt to deliver the
+	// webhook.
+	OnAttempt func(WebhookAttempt) `bson:"-"`
+}
+
+// WebhookAttempt describes the outcome of a single attempt to deliver a
+// webhook.
+type WebhookAttempt struct {
+	// StatusCode is the HTTP response code, or 0 if there was no response.
+	StatusCode int
+	Latency    time.Duration
+	// Err is the error if the attempt failed.
+	Err  error
+	Time time.Time
 }
 
 type evergreenWebhookMessage struct {
@@ -86,7 +108,7 @@ func (w *evergreenWebhookMessage) String() string {
 	return string(w.raw.Body)
 }
 
-func (w *EvergreenWebhook) request() (*http.Request, error) {
+func (w *EvergreenWebhook) request(now time.Time) (*http.Request, error) {
 	req, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(w.Body))
 	if err != nil {
 		return nil, errors.Wrap(err, "creating webhook HTTP request")
@@ -96,6 +118,11 @@ func (w *EvergreenWebhook) request() (*http.Request, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "calculating HMAC hash")
 	}
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	timestampHash, err := CalculateTimestampedHMACHash(w.Secret, timestamp, w.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "calculating timestamped HMAC hash")
+	}
 
 	for k := range w.Headers {
 		for i := range w.Headers[k] {
@@ -106,9 +133,13 @@ func (w *EvergreenWebhook) request() (*http.Request, error) {
 	// Deduplicate the evergreen headers.
 	req.Header.Del(evergreenHMACHeader)
 	req.Header.Del(evergreenNotificationIDHeader)
+	req.Header.Del(evergreenTimestampHeader)
+	req.Header.Del(evergreenTimestampHMACHeader)
 
 	req.Header.Add(evergreenHMACHeader, hash)
 	req.Header.Add(evergreenNotificationIDHeader, w.NotificationID)
+	req.Header.Add(evergreenTimestampHeader, timestamp)
+	req.Header.Add(evergreenTimestampHMACHeader, timestampHash)
 
 	return req, nil
 }
@@ -160,7 +191,8 @@ func (w *evergreenWebhookLogger) send(m message.Composer) error {
 
 	client := w.client
 	return utility.Retry(context.Background(), func() (bool, error) {
-		req, err := raw.request()
+		startAt := time.Now()
+		req, err := raw.request(startAt)
 		if err != nil {
 			return false, errors.Wrap(err, "making webhook request")
 		}
@@ -170,6 +202,15 @@ func (w *evergreenWebhookLogger) send(m message.Composer) error {
 		req = req.WithContext(ctx)
 
 		resp, err := client.Do(req)
+		attempt := WebhookAttempt{
+			Latency: time.Since(startAt),
+			Time:    startAt,
+		}
+		defer func() {
+			if raw.OnAttempt != nil {
+				raw.OnAttempt(attempt)
+			}
+		}()
 		msgFields := message.Fields{
 			"message":         "error sending webhook notification",
 			"notification_id": raw.NotificationID,
@@ -177,18 +218,21 @@ func (w *evergreenWebhookLogger) send(m message.Composer) error {
 			"is_ctx_err":      utility.IsContextError(ctx.Err()),
 		}
 		if err != nil {
-			return true, message.WrapError(errors.Wrap(err, "sending webhook data"), msgFields)
+			attempt.Err = errors.Wrap(err, "sending webhook data")
+			return true, message.WrapError(attempt.Err, msgFields)
 		}
 
 		defer resp.Body.Close()
 
+		attempt.StatusCode = resp.StatusCode
 		msgFields["status_code"] = resp.StatusCode
 
 		// Endpoint response bodies may contain sensitive data, so do not retain them in operator logs.
 		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxWebhookResponseDrainSize))
 
 		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-			return true, message.WrapError(errors.Errorf("webhook response was %d (%s)", resp.StatusCode, http.StatusText(resp.StatusCode)), msgFields)
+			attempt.Err = errors.Errorf("webhook response was %d (%s)", resp.StatusCode, http.StatusText(resp.StatusCode))
+			return true, message.WrapError(attempt.Err, msgFields)
 		}
 
 		msgFields["message"] = "successfully sent webhook notification"
@@ -102,7 +102,9 @@ func TestEvergreenWebhookSender(t *testing.T) {
 			s.Send(t.Context(), m)
 			assert.Equal(t, "https://example.com", transport.lastUrl)
 
-			assert.Len(t, transport.header, 3)
+			assert.Len(t, transport.header, 5)
+			assert.NotEmpty(t, transport.header.Get(evergreenTimestampHeader))
+			assert.NotEmpty(t, transport.header.Get(evergreenTimestampHMACHeader))
 			assert.Len(t, transport.header["Test"], 2)
 			assert.Contains(t, transport.header["Test"], "test1")
 			assert.Contains(t, transport.header["Test"], "test2")
@@ -178,6 +180,34 @@ func TestEvergreenWebhookSender(t *testing.T) {
 			assert.Equal(t, attempts, transport.attemptCount)
 			assert.Equal(t, body, transport.lastBody)
 		},
+		"ReportsEachAttempt": func(t *testing.T) {
+			transport.minAttempts = 2
+			secret := []byte("hi")
+			transport.secret = secret
+			var attempts []WebhookAttempt
+			m := NewWebhookMessage(EvergreenWebhook{
+				NotificationID: "evergreen",
+				URL:            "https://example.com",
+				Secret:         secret,
+				Body:           []byte("something important"),
+				Retries:        2,
+				MinDelayMS:     1,
+				OnAttempt: func(attempt WebhookAttempt) {
+					attempts = append(attempts, attempt)
+				},
+			})
+			assert.NoError(t, s.SetErrorHandler(func(_ context.Context, err error, _ message.Composer) {
+				t.Fatal("error handler was called, but shouldn't have been")
+			}))
+
+			s.Send(t.Context(), m)
+			require.Len(t, attempts, 2)
+			assert.Equal(t, http.StatusBadRequest, attempts[0].StatusCode)
+			assert.Error(t, attempts[0].Err)
+			assert.Equal(t, http.StatusNoContent, attempts[1].StatusCode)
+			assert.NoError(t, attempts[1].Err)
+			assert.False(t, attempts[1].Time.IsZero())
+		},
 	} {
 		transport = mockWebhookTransport{}
 		s.client = &http.Client{
@@ -361,6 +391,18 @@ func (t *mockWebhookTransport) RoundTrip(req *http.Request) (*http.Response, err
 		resp.Body = io.NopCloser(bytes.NewBufferString(fmt.Sprintf("expected signature: %s, got %s", sig, hash)))
 		return resp, nil
 	}
+
+	timestampHash, err := CalculateTimestampedHMACHash(t.secret, req.Header.Get(evergreenTimestampHeader), body)
+	if err != nil {
+		resp.StatusCode = http.StatusInternalServerError
+		resp.Body = io.NopCloser(bytes.NewBufferString(err.Error()))
+		return resp, nil
+	}
+	if !hmac.Equal([]byte(timestampHash), []byte(req.Header.Get(evergreenTimestampHMACHeader))) {
+		resp.StatusCode = http.StatusBadRequest
+		resp.Body = io.NopCloser(bytes.NewBufferString("timestamp signature does not match"))
+		return resp, nil
+	}
 	resp.StatusCode = http.StatusNoContent
 	grip.Info(context.Background(), message.Fields{
 		"message":   fmt.Sprintf("received %s", mid),
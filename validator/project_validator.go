@@ -18,6 +18,7 @@ import (
 	"github.com/evergreen-ci/evergreen/agent/globals"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/distro"
+	restModel "github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/evergreen/thirdparty"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/utility"
@@ -118,6 +119,23 @@ type ValidationInput struct {
 	ProjectID   string `json:"project_id" yaml:"project_id"`
 }
 
+// GeneratedValidationInput is the input to a dry run of generate.tasks, which
+// merges generated JSON files into a project config without saving them.
+type GeneratedValidationInput struct {
+	ProjectYaml []byte `json:"project_yaml" yaml:"project_yaml"`
+	ProjectID   string `json:"project_id" yaml:"project_id"`
+	// Generated is the contents of each generated JSON file.
+	Generated []string `json:"generated" yaml:"generated"`
+}
+
+// GeneratedValidationResult is the result of a dry run of generate.tasks.
+type GeneratedValidationResult struct {
+	// Errors are the problems with the generated files or the project config
+	// they produce. generate.tasks would fail if there are any errors.
+	Errors ValidationErrors                  `json:"errors" yaml:"errors,omitempty"`
+	Diff   restModel.APIGeneratedProjectDiff `json:"diff" yaml:"diff"`
+}
+
 // Functions used to validate the project configuration file for errors.
 // These are expected to only return ValidationError's with
 // a level of Error ValidationLevel. They must also explicitly return